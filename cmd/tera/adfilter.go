@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// handleAdFilter is the entry point for `tera adfilter ...`.
+func handleAdFilter(args []string) {
+	if len(args) == 0 {
+		printAdFilterHelp()
+		return
+	}
+
+	ac, err := storage.LoadAdFilterConfigFromUnified()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "status", "list":
+		printAdFilterStatus(ac)
+
+	case "enable":
+		ac.Enabled = true
+		saveAdFilterConfig(ac)
+		fmt.Println("✓ Ad filter enabled (takes effect next time TERA starts)")
+
+	case "disable":
+		ac.Enabled = false
+		saveAdFilterConfig(ac)
+		fmt.Println("✓ Ad filter disabled")
+
+	case "add":
+		pattern, action, station, err := parseAdFilterRuleArgs(args[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			printAdFilterHelp()
+			os.Exit(1)
+		}
+		rule := config.AdFilterRule{Pattern: pattern, Action: action, Station: station}
+		if _, err := rule.Compile(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid pattern: %v\n", err)
+			os.Exit(1)
+		}
+		ac.Rules = append(ac.Rules, rule)
+		saveAdFilterConfig(ac)
+		fmt.Printf("✓ Added rule %d\n", len(ac.Rules))
+		printAdFilterStatus(ac)
+
+	case "remove", "rm":
+		n, err := ruleIndexArg(args[1:], len(ac.Rules), "tera adfilter status")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		ac.Rules = append(ac.Rules[:n], ac.Rules[n+1:]...)
+		saveAdFilterConfig(ac)
+		fmt.Printf("✓ Removed rule %d\n", n+1)
+
+	case "mute-on-connect":
+		ac.MuteOnConnectSeconds = adFilterIntArg(args[1:], "seconds")
+		validateAndSaveAdFilter(ac)
+		fmt.Printf("✓ Mute on connect set to %ds\n", ac.MuteOnConnectSeconds)
+
+	case "lower-volume":
+		ac.LowerVolume = adFilterIntArg(args[1:], "percent")
+		validateAndSaveAdFilter(ac)
+		fmt.Printf("✓ Lower volume set to %d%%\n", ac.LowerVolume)
+
+	case "test":
+		title, _, station, err := parseAdFilterRuleArgs(args[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if n, ok := matchAdFilterRule(ac, title, station); ok {
+			r := ac.Rules[n]
+			fmt.Printf("Rule %d matches: %s %q\n", n+1, r.Action, r.Pattern)
+		} else {
+			fmt.Println("No rule matches")
+		}
+
+	case "--help", "-h":
+		printAdFilterHelp()
+
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown adfilter command %q\n\n", args[0])
+		printAdFilterHelp()
+		os.Exit(1)
+	}
+}
+
+// parseAdFilterRuleArgs reads "<text> [--action A] [--station UUID]".
+func parseAdFilterRuleArgs(args []string) (text, action, station string, err error) {
+	action = config.AdActionMute
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--action", "--station":
+			if i+1 >= len(args) {
+				return "", "", "", fmt.Errorf("%s needs a value", args[i])
+			}
+			if args[i] == "--action" {
+				action = args[i+1]
+			} else {
+				station = args[i+1]
+			}
+			i++
+		default:
+			if text != "" {
+				return "", "", "", fmt.Errorf("unexpected argument %q (quote patterns that contain spaces)", args[i])
+			}
+			text = args[i]
+		}
+	}
+	if text == "" {
+		return "", "", "", fmt.Errorf("missing pattern")
+	}
+	switch action {
+	case config.AdActionMute, config.AdActionLower, config.AdActionSkip:
+	default:
+		return "", "", "", fmt.Errorf("action must be %s, %s or %s", config.AdActionMute, config.AdActionLower, config.AdActionSkip)
+	}
+	return text, action, station, nil
+}
+
+// matchAdFilterRule returns the index of the rule the player would apply to
+// title, checking station rules before global ones like the player does.
+func matchAdFilterRule(ac config.AdFilterConfig, title, station string) (int, bool) {
+	for _, stationPass := range []bool{true, false} {
+		for i, r := range ac.Rules {
+			if (r.Station != "") != stationPass || (stationPass && r.Station != station) {
+				continue
+			}
+			if re, err := r.Compile(); err == nil && re.MatchString(title) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// ruleIndexArg parses a 1-based rule number from args into a slice index.
+// listCmd is the command that shows the numbers.
+func ruleIndexArg(args []string, count int, listCmd string) (int, error) {
+	if len(args) == 0 {
+		return 0, fmt.Errorf("missing number (see '%s')", listCmd)
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 || n > count {
+		return 0, fmt.Errorf("number must be 1-%d (see '%s')", count, listCmd)
+	}
+	return n - 1, nil
+}
+
+func adFilterIntArg(args []string, name string) int {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: missing %s\n", name)
+		os.Exit(1)
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s must be a number\n", name)
+		os.Exit(1)
+	}
+	return n
+}
+
+func validateAndSaveAdFilter(ac config.AdFilterConfig) {
+	if err := ac.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Note: %v\n", err)
+	}
+	saveAdFilterConfig(ac)
+}
+
+func saveAdFilterConfig(ac config.AdFilterConfig) {
+	if err := storage.SaveAdFilterConfigToUnified(ac); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printAdFilterStatus(ac config.AdFilterConfig) {
+	state := "disabled"
+	if ac.Enabled {
+		state = "enabled"
+	}
+	fmt.Printf("  Status:           %s\n", state)
+	fmt.Printf("  Mute on connect:  %ds\n", ac.MuteOnConnectSeconds)
+	fmt.Printf("  Lower volume:     %d%%\n", ac.LowerVolume)
+	if len(ac.Rules) == 0 {
+		fmt.Println("  Rules:            none")
+		return
+	}
+	fmt.Println("  Rules:")
+	for i, r := range ac.Rules {
+		scope := "all stations"
+		if r.Station != "" {
+			scope = "station " + r.Station
+		}
+		fmt.Printf("    %d. %-5s %q (%s)\n", i+1, r.Action, r.Pattern, scope)
+	}
+}
+
+func printAdFilterHelp() {
+	fmt.Print(`TERA Ad Filter Commands
+
+Usage: tera adfilter <command>
+
+Commands:
+  status                     Show settings and rules
+  enable                     Apply the rules while playing
+  disable                    Turn the ad filter off
+  add <pattern> [--action mute|lower|skip] [--station UUID]
+                             Add a rule (default action: mute)
+  remove <n>                 Remove rule n
+  mute-on-connect <seconds>  Silence the first seconds of every stream (0-120)
+  lower-volume <percent>     Volume used by "lower" rules (0-100)
+  test <title> [--station UUID]
+                             Show which rule matches a track title
+
+Rules match the stream's track title with a case-insensitive regular
+expression. "mute" and "lower" last until the title changes. "skip" moves
+on to the next station when playing from a favorites list or a shuffle
+session, and mutes everywhere else. Station rules are checked before rules
+that apply to all stations.
+
+Examples:
+  tera adfilter add '^(advert|commercial)'
+  tera adfilter add 'traffic|weather' --action lower
+  tera adfilter add 'jingle' --action skip --station 9617a958-0601-11e8-ae97-52543be04c81
+`)
+}
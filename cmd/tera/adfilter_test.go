@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/shinokada/tera/v3/internal/config"
+)
+
+func TestParseAdFilterRuleArgs(t *testing.T) {
+	pattern, action, station, err := parseAdFilterRuleArgs([]string{"--action", "skip", "jingle", "--station", "uuid-1"})
+	if err != nil || pattern != "jingle" || action != config.AdActionSkip || station != "uuid-1" {
+		t.Errorf("got %q %q %q %v", pattern, action, station, err)
+	}
+
+	if _, action, _, err := parseAdFilterRuleArgs([]string{"advert"}); err != nil || action != config.AdActionMute {
+		t.Errorf("expected default action mute, got %q %v", action, err)
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"--action", "fade", "advert"},
+		{"advert", "--station"},
+		{"two", "words"},
+	} {
+		if _, _, _, err := parseAdFilterRuleArgs(args); err == nil {
+			t.Errorf("expected error for %q", args)
+		}
+	}
+}
+
+func TestMatchAdFilterRule_StationRulesFirst(t *testing.T) {
+	ac := config.AdFilterConfig{Rules: []config.AdFilterRule{
+		{Pattern: "news", Action: config.AdActionLower},
+		{Pattern: "news", Action: config.AdActionSkip, Station: "uuid-1"},
+	}}
+
+	if n, ok := matchAdFilterRule(ac, "NEWS", "uuid-1"); !ok || n != 1 {
+		t.Errorf("expected station rule 1, got %d %v", n, ok)
+	}
+	if n, ok := matchAdFilterRule(ac, "news", "uuid-2"); !ok || n != 0 {
+		t.Errorf("expected global rule 0, got %d %v", n, ok)
+	}
+	if _, ok := matchAdFilterRule(ac, "Artist - Song", ""); ok {
+		t.Error("expected no match")
+	}
+}
+
+func TestRuleIndexArg(t *testing.T) {
+	if n, err := ruleIndexArg([]string{"2"}, 3, "tera adfilter status"); err != nil || n != 1 {
+		t.Errorf("expected index 1, got %d %v", n, err)
+	}
+	for _, args := range [][]string{{}, {"0"}, {"4"}, {"x"}} {
+		if _, err := ruleIndexArg(args, 3, "tera adfilter status"); err == nil {
+			t.Errorf("expected error for %q", args)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// handleData is the entry point for `tera data <subcommand>`.
+func handleData(args []string) {
+	if len(args) == 0 {
+		printDataHelp()
+		return
+	}
+	switch args[0] {
+	case "migrate":
+		handleDataMigrate(args[1:])
+	case "--help", "-h", "help":
+		printDataHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown data command %q\n\n", args[0])
+		printDataHelp()
+		os.Exit(1)
+	}
+}
+
+// handleDataMigrate brings every data file up to the current version, or with
+// --dry-run tries the pending migrations in memory and says which would run
+// and which would fail. The schemas of the
+// blocklist and subscriptions are registered by their packages, which the
+// TUI imports.
+func handleDataMigrate(args []string) {
+	fs := flag.NewFlagSet("data migrate", flag.ExitOnError)
+	fs.Usage = printDataHelp
+	dryRun := fs.Bool("dry-run", false, "report what would change without writing")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n\n", fs.Arg(0))
+		printDataHelp()
+		os.Exit(1)
+	}
+
+	dir, err := dataDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	reports, err := storage.MigrateDataDir(dir, *dryRun)
+	printMigrationReports(os.Stdout, reports, *dryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Files that failed were left unchanged.")
+		os.Exit(1)
+	}
+}
+
+// printMigrationReports writes one line per data file, followed by the
+// migrations run or pending.
+func printMigrationReports(w io.Writer, reports []storage.MigrationReport, dryRun bool) {
+	pending, failed := 0, 0
+	for _, r := range reports {
+		name := filepath.Base(r.Path)
+		switch {
+		case r.Missing:
+			_, _ = fmt.Fprintf(w, "  %-24s not created yet\n", name)
+		case r.Newer():
+			_, _ = fmt.Fprintf(w, "  %-24s v%d is newer than this TERA knows (v%d); left alone\n", name, r.From, r.To)
+		case len(r.Pending) == 0:
+			_, _ = fmt.Fprintf(w, "  %-24s up to date (v%d)\n", name, r.From)
+		case r.Failed:
+			failed++
+			verb := "failed to migrate"
+			if dryRun {
+				verb = "would fail to migrate"
+			}
+			_, _ = fmt.Fprintf(w, "  %-24s %s v%d → v%d; left unchanged\n", name, verb, r.From, r.To)
+		default:
+			pending++
+			verb := "migrated"
+			if dryRun {
+				verb = "would migrate"
+			}
+			_, _ = fmt.Fprintf(w, "  %-24s %s v%d → v%d\n", name, verb, r.From, r.To)
+			for _, m := range r.Pending {
+				_, _ = fmt.Fprintf(w, "      %d. %s\n", m.Version, m.Description)
+			}
+			if r.Backup != "" {
+				_, _ = fmt.Fprintf(w, "      original kept as %s\n", filepath.Base(r.Backup))
+			}
+		}
+	}
+	if pending == 0 && failed == 0 {
+		_, _ = fmt.Fprintln(w, "\nAll data files are up to date.")
+	} else if pending > 0 && dryRun {
+		_, _ = fmt.Fprintf(w, "\n%d file(s) would be migrated. Run 'tera data migrate' to apply.\n", pending)
+	}
+}
+
+func printDataHelp() {
+	fmt.Print(`TERA Data Files
+
+Usage:
+  tera data migrate [--dry-run]
+
+The ratings, tags, play statistics, notes, liked songs, journal, blocklist
+and subscriptions files record the version of their layout. TERA upgrades
+older files when it loads them; 'tera data migrate' does it for every file
+at once, and --dry-run lists the pending steps, and any that would fail,
+without writing anything. Favorite lists, the search history and the sync
+preferences have no version and are not migrated.
+
+Before a file is upgraded, the original is copied next to it as
+<name>.v<version>.bak. A file is only replaced when every step succeeded,
+so one that fails is left as it was and can be fixed and migrated again.
+`)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+func TestPrintMigrationReports(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "station_ratings.json"), []byte(`{"ratings":{}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "station_tags.json"), []byte(`{"station_tags":{},"version":1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := storage.MigrateDataDir(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	printMigrationReports(&out, reports, true)
+	got := out.String()
+	for _, want := range []string{
+		"station_ratings.json     would migrate v0 → v1",
+		"station_tags.json        up to date (v1)",
+		"station_metadata.json    not created yet",
+		"1 file(s) would be migrated",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output misses %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "original kept") {
+		t.Error("dry run reports a backup")
+	}
+}
+
+func TestPrintMigrationReports_Failed(t *testing.T) {
+	reports := []storage.MigrationReport{{
+		Path:    "/data/station_ratings.json",
+		To:      1,
+		Pending: []storage.Migration{{Version: 1, Description: "clamp ratings"}},
+		Failed:  true,
+	}}
+	var out bytes.Buffer
+	printMigrationReports(&out, reports, true)
+	got := out.String()
+	if !strings.Contains(got, "station_ratings.json     would fail to migrate v0 → v1; left unchanged") {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+	if strings.Contains(got, "up to date") {
+		t.Errorf("a failed file is reported as up to date:\n%s", got)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/playlist"
+)
+
+// handleFav is the entry point for `tera fav <subcommand>`.
+func handleFav(args []string) {
+	if len(args) == 0 {
+		printFavHelp()
+		return
+	}
+	switch args[0] {
+	case "export":
+		handleFavExport(args[1:])
+	case "import":
+		handleFavImport(args[1:])
+	case "--help", "-h", "help":
+		printFavHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown fav command %q\n\n", args[0])
+		printFavHelp()
+		os.Exit(1)
+	}
+}
+
+// parseWithArg parses flags that may come before or after one positional
+// argument, as in `tera fav export Jazz --format pls`.
+func parseWithArg(fs *flag.FlagSet, args []string) (string, error) {
+	var arg string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		arg, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	rest := fs.Args()
+	if arg == "" && len(rest) > 0 {
+		arg, rest = rest[0], rest[1:]
+	}
+	if len(rest) > 0 {
+		return "", fmt.Errorf("unexpected argument %q", rest[0])
+	}
+	return arg, nil
+}
+
+func handleFavExport(args []string) {
+	fs := flag.NewFlagSet("fav export", flag.ExitOnError)
+	fs.Usage = printFavHelp
+	format := fs.String("format", "", "m3u, pls, xspf or opml")
+	output := fs.String("output", "", "file to write, or - for standard output")
+	fs.StringVar(output, "o", "", "file to write, or - for standard output")
+	name, err := parseWithArg(fs, args)
+	if err != nil || name == "" {
+		if err == nil {
+			err = fmt.Errorf("missing list name")
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		printFavHelp()
+		os.Exit(1)
+	}
+	if *format == "" {
+		*format = playlist.FormatFromPath(*output)
+	}
+	if *format == "" {
+		*format = playlist.FormatM3U
+	}
+	if !playlist.IsFormat(*format) {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (use %s)\n", *format, strings.Join(playlist.Formats, ", "))
+		os.Exit(1)
+	}
+
+	store, done, err := openFavorites()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer done()
+	ctx := context.Background()
+
+	if *output == "-" {
+		list, err := store.LoadList(ctx, name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: could not load list %q: %v\n", name, err)
+			os.Exit(1)
+		}
+		p := playlist.Playlist{Title: name, Entries: playlist.FromStations(list.Stations)}
+		if err := playlist.Write(os.Stdout, p, *format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	path := *output
+	if path == "" {
+		if path, err = playlist.DefaultExportPath(name, *format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	n, err := playlist.ExportList(ctx, store, name, path, *format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Exported %d station(s) from '%s' to %s\n", n, name, path)
+}
+
+func handleFavImport(args []string) {
+	fs := flag.NewFlagSet("fav import", flag.ExitOnError)
+	fs.Usage = printFavHelp
+	format := fs.String("format", "", "m3u, pls, xspf or opml (default: from the file)")
+	listName := fs.String("list", "", "list to import into (default: from the playlist)")
+	offline := fs.Bool("offline", false, "keep every stream as a custom station without Radio Browser lookups")
+	path, err := parseWithArg(fs, args)
+	if err != nil || path == "" {
+		if err == nil {
+			err = fmt.Errorf("missing playlist file")
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		printFavHelp()
+		os.Exit(1)
+	}
+	if *format != "" && !playlist.IsFormat(*format) {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (use %s)\n", *format, strings.Join(playlist.Formats, ", "))
+		os.Exit(1)
+	}
+
+	store, done, err := openFavorites()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer done()
+	var finder playlist.StationFinder
+	if !*offline {
+		finder = api.NewClient()
+	}
+	r, err := playlist.ImportFile(context.Background(), store, finder, path, *format, *listName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if r.LookupErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Radio Browser lookup failed, remaining streams kept as custom stations: %v\n", r.LookupErr)
+	}
+	fmt.Printf("✓ Imported %d station(s) into '%s'\n", r.Added, r.List)
+	fmt.Printf("  %d matched by URL, %d by name, %d custom", r.ByURL, r.ByName, r.Custom)
+	if r.Duplicates > 0 {
+		fmt.Printf(", %d already in the list", r.Duplicates)
+	}
+	fmt.Println()
+}
+
+func printFavHelp() {
+	fmt.Print(`TERA Favorites Import/Export
+
+Usage:
+  tera fav export <list> [--format m3u|pls|xspf|opml] [-o FILE]
+  tera fav import <file> [--format m3u|pls|xspf|opml] [--list NAME] [--offline]
+
+Export writes a favorites list as a playlist for VLC, foobar2000,
+Rhythmbox and other players (default ~/tera-<list>.<format>; -o - prints
+it). The format defaults to the output file's extension, then m3u.
+
+Import adds the streams in a playlist to a favorites list, named after the
+playlist unless --list is given (Folder/List for a nested list), creating
+it if needed. Each stream is matched to a Radio Browser station by URL,
+then by exact name; streams that match nothing are kept as custom
+stations. Stations already in the list are skipped.
+
+Examples:
+  tera fav export My-favorites --format pls
+  tera fav export Jazz -o jazz.xspf
+  tera fav import ~/Downloads/radio.opml --list News
+  tera fav import smooth.pls --list Jazz/Smooth
+  tera fav import stations.m3u --offline
+`)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestParseWithArg(t *testing.T) {
+	for _, args := range [][]string{
+		{"Jazz", "--format", "pls"},
+		{"--format", "pls", "Jazz"},
+	} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		format := fs.String("format", "", "")
+		arg, err := parseWithArg(fs, args)
+		if err != nil || arg != "Jazz" || *format != "pls" {
+			t.Errorf("parseWithArg(%q) = %q, %q, %v", args, arg, *format, err)
+		}
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	if _, err := parseWithArg(fs, []string{"a", "b"}); err == nil {
+		t.Error("expected an error for two arguments")
+	}
+}
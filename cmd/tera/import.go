@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/importer"
+)
+
+// handleImport is the entry point for `tera import --from <app> [path]`.
+func handleImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	fs.Usage = printImportHelp
+	from := fs.String("from", "", strings.Join(importer.Names(), ", "))
+	listName := fs.String("list", "My-favorites", "favorites list to import into")
+	offline := fs.Bool("offline", false, "keep stations as they are without Radio Browser lookups")
+	path, err := parseWithArg(fs, args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		printImportHelp()
+		os.Exit(1)
+	}
+	if *from == "" {
+		fmt.Fprintf(os.Stderr, "Error: --from is required (%s)\n\n", strings.Join(importer.Names(), ", "))
+		printImportHelp()
+		os.Exit(1)
+	}
+	src, ok := importer.Lookup(*from)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown app %q (use %s)\n", *from, strings.Join(importer.Names(), ", "))
+		os.Exit(1)
+	}
+	name := strings.Join(strings.Fields(*listName), "-")
+	if name == "" {
+		fmt.Fprintln(os.Stderr, "Error: --list cannot be empty")
+		os.Exit(1)
+	}
+
+	store, done, err := openFavorites()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer done()
+	var finder importer.StationFinder
+	if !*offline {
+		finder = api.NewClient()
+	}
+	r, err := importer.Import(context.Background(), store, finder, src, path, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if r.LookupErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Radio Browser lookup failed, remaining stations kept as custom stations: %v\n", r.LookupErr)
+	}
+	fmt.Printf("✓ Imported %d station(s) from %s into '%s'\n", r.Added, src.Title, r.List)
+	fmt.Printf("  %d matched by UUID, %d by URL, %d by name, %d custom", r.ByUUID, r.ByURL, r.ByName, r.Custom)
+	if r.Duplicates > 0 {
+		fmt.Printf(", %d already in the list", r.Duplicates)
+	}
+	if r.Skipped > 0 {
+		fmt.Printf(", %d skipped (not on Radio Browser and no stream URL)", r.Skipped)
+	}
+	fmt.Println()
+}
+
+func printImportHelp() {
+	fmt.Print(`TERA Import
+
+Usage:
+  tera import --from shortwave|goodvibes|radiotray|radiodroid [PATH] [--list NAME] [--offline]
+
+Adds the favorites of another radio app to a TERA favorites list
+(default My-favorites), creating the list if needed. Stations already in
+the list are skipped.
+
+Without PATH the app's own data is read from its usual location:
+  shortwave   ~/.local/share/Shortwave/Shortwave.db (or the Flatpak copy)
+  goodvibes   ~/.local/share/goodvibes/stations.xml (or the Flatpak copy)
+  radiotray   ~/.config/radiotray-ng/bookmarks.json
+  radiodroid  no default: export the favorites in RadioDroid's settings
+              and pass the exported file
+
+Stations are looked up in Radio Browser by their UUID when the app keeps
+one, then by stream URL and exact name; anything else is kept as a custom
+station. --offline skips the lookups.
+
+Examples:
+  tera import --from shortwave
+  tera import --from radiotray --list Radiotray
+  tera import --from radiodroid ~/Downloads/radiodroid.m3u
+`)
+}
@@ -65,6 +65,36 @@ func main() {
 		case "config":
 			handleConfigCommand()
 			return
+		case "remote":
+			handleRemote(os.Args[2:])
+			return
+		case "relay":
+			handleRelay(os.Args[2:])
+			return
+		case "adfilter":
+			handleAdFilter(os.Args[2:])
+			return
+		case "metadata":
+			handleMetadata(os.Args[2:])
+			return
+		case "stats":
+			handleStats(os.Args[2:])
+			return
+		case "report":
+			handleReport(os.Args[2:])
+			return
+		case "fav":
+			handleFav(os.Args[2:])
+			return
+		case "import":
+			handleImport(os.Args[2:])
+			return
+		case "sub":
+			handleSub(os.Args[2:])
+			return
+		case "data":
+			handleData(os.Args[2:])
+			return
 		case "--help", "-h":
 			printHelp()
 			return
@@ -102,6 +132,18 @@ func main() {
 	p := tea.NewProgram(app, tea.WithAltScreen())
 	app.SetProgram(p)
 
+	// Start the HTTP remote if the user opted in
+	if rc, err := storage.LoadRemoteConfigFromUnified(); err == nil && rc.Enabled {
+		if err := app.StartRemote(rc); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not start remote: %v\n", err)
+		}
+	}
+	if rc, err := storage.LoadRelayConfigFromUnified(); err == nil && rc.Enabled {
+		if err := app.StartRelay(rc); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not start relay: %v\n", err)
+		}
+	}
+
 	// Set up graceful shutdown handler for SIGINT (Ctrl+C) and SIGTERM
 	// This ensures proper cleanup even when signals bypass Bubble Tea's key handling
 	sigChan := make(chan os.Signal, 1)
@@ -330,6 +372,16 @@ Commands:
   play     Play a station from the command line (no TUI)
   theme    Manage theme settings (reset, path, edit, export)
   config   Manage configuration (path, reset, validate, migrate)
+  remote   Manage the local HTTP API and web remote
+  relay    Re-stream the playing station to other devices
+  adfilter Mute, lower or skip ads by track title
+  metadata Configure how track titles are split into artist and title
+  stats    Show listening stats as terminal charts (--json for scripts)
+  report   Export a year-in-review report as Markdown or HTML
+  fav      Import or export favorites lists as M3U, PLS, XSPF or OPML
+  import   Import favorites from Shortwave, Goodvibes, Radiotray-NG or RadioDroid
+  sub      Subscribe to shared lists in a public gist or at an HTTPS URL
+  data     Upgrade data files to the current layout (migrate [--dry-run])
 
 Options:
   -h, --help     Show this help message
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/player"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// handleMetadata is the entry point for `tera metadata ...`.
+func handleMetadata(args []string) {
+	if len(args) == 0 {
+		printMetadataHelp()
+		return
+	}
+
+	mc, err := storage.LoadMetadataConfigFromUnified()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "list", "status":
+		printMetadataPatterns(mc)
+
+	case "add":
+		pattern, station, err := parseStationArgs(args[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			printMetadataHelp()
+			os.Exit(1)
+		}
+		mp := config.MetadataPattern{Pattern: pattern, Station: station}
+		if _, err := mp.Compile(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid pattern: %v\n", err)
+			os.Exit(1)
+		}
+		mc.Patterns = append(mc.Patterns, mp)
+		saveMetadataConfig(mc)
+		fmt.Printf("✓ Added pattern %d (takes effect next time TERA starts)\n", len(mc.Patterns))
+
+	case "remove", "rm":
+		n, err := ruleIndexArg(args[1:], len(mc.Patterns), "tera metadata list")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		mc.Patterns = append(mc.Patterns[:n], mc.Patterns[n+1:]...)
+		saveMetadataConfig(mc)
+		fmt.Printf("✓ Removed pattern %d\n", n+1)
+
+	case "test":
+		title, station, err := parseStationArgs(args[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		installMetadataPatterns(mc)
+		info := player.ParseTrack(title, station)
+		fmt.Printf("  Artist: %s\n", info.Artist)
+		fmt.Printf("  Title:  %s\n", info.Title)
+		fmt.Printf("  Album:  %s\n", info.Album)
+
+	case "--help", "-h":
+		printMetadataHelp()
+
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown metadata command %q\n\n", args[0])
+		printMetadataHelp()
+		os.Exit(1)
+	}
+}
+
+// parseStationArgs reads "<text> [--station UUID]".
+func parseStationArgs(args []string) (text, station string, err error) {
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--station":
+			if i+1 >= len(args) {
+				return "", "", fmt.Errorf("--station needs a value")
+			}
+			station = args[i+1]
+			i++
+		case text != "":
+			return "", "", fmt.Errorf("unexpected argument %q (quote text that contains spaces)", args[i])
+		default:
+			text = args[i]
+		}
+	}
+	if text == "" {
+		return "", "", fmt.Errorf("missing text")
+	}
+	return text, station, nil
+}
+
+// installMetadataPatterns makes player.ParseTrack use the configured patterns.
+func installMetadataPatterns(mc config.MetadataConfig) {
+	var patterns []player.MetadataPattern
+	for _, p := range mc.Patterns {
+		if re, err := p.Compile(); err == nil {
+			patterns = append(patterns, player.MetadataPattern{Regexp: re, StationUUID: p.Station})
+		}
+	}
+	player.SetMetadataPatterns(patterns)
+}
+
+func saveMetadataConfig(mc config.MetadataConfig) {
+	if err := storage.SaveMetadataConfigToUnified(mc); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printMetadataPatterns(mc config.MetadataConfig) {
+	if len(mc.Patterns) == 0 {
+		fmt.Println("  No custom patterns; only the built-in ones are used.")
+		return
+	}
+	for i, p := range mc.Patterns {
+		scope := "all stations"
+		if p.Station != "" {
+			scope = "station " + p.Station
+		}
+		fmt.Printf("  %d. %q (%s)\n", i+1, p.Pattern, scope)
+	}
+}
+
+func printMetadataHelp() {
+	fmt.Print(`TERA Metadata Commands
+
+Usage: tera metadata <command>
+
+Commands:
+  list                           Show custom title patterns
+  add <pattern> [--station UUID] Add a pattern (tried before the built-in ones)
+  remove <n>                     Remove pattern n
+  test <title> [--station UUID]  Show how a track title is split
+
+TERA splits stream titles into artist, title and album. Built-in patterns
+handle "Artist - Title", "Title by Artist [from Album]" and "Artist / Title",
+and drop prefixes such as "NOW PLAYING:". Custom patterns are
+case-insensitive regular expressions that name the parts with the groups
+(?P<artist>...), (?P<title>...) and (?P<album>...); a title group is required.
+Station patterns are tried before patterns for all stations.
+
+Examples:
+  tera metadata add '^(?P<title>.+?) - (?P<artist>.+)$' --station 9617a958-0601-11e8-ae97-52543be04c81
+  tera metadata test 'NOW PLAYING: Radiohead - Creep'
+`)
+}
@@ -0,0 +1,15 @@
+package main
+
+import "testing"
+
+func TestParseStationArgs(t *testing.T) {
+	text, station, err := parseStationArgs([]string{"--station", "uuid-1", "Artist - Title"})
+	if err != nil || text != "Artist - Title" || station != "uuid-1" {
+		t.Errorf("got %q %q %v", text, station, err)
+	}
+	for _, args := range [][]string{{}, {"--station"}, {"a", "b"}} {
+		if _, _, err := parseStationArgs(args); err == nil {
+			t.Errorf("expected error for %q", args)
+		}
+	}
+}
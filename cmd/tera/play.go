@@ -203,13 +203,65 @@ func dataDir() (string, error) {
 	return filepath.Join(cfgDir, "tera", "data"), nil
 }
 
-// newMetadataManager creates a MetadataManager using the standard data path.
+// useConfiguredDatabase opens the SQLite store when the config selects it, so
+// managers created afterwards use it. On failure the JSON files are used.
+func useConfiguredDatabase(dir string) {
+	if _, err := storage.OpenConfiguredDatabase(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not open database, using JSON files: %v\n", err)
+	}
+}
+
+// openFavorites returns the favorites store. When the config selects the
+// SQLite backend it is kept in the database, so commands see and change the
+// same lists as the TUI; the returned function then closes the database,
+// writing the JSON export.
+func openFavorites() (*storage.Storage, func(), error) {
+	favDir, err := favoritesDir()
+	if err != nil {
+		return nil, nil, err
+	}
+	done := func() {}
+	if dir, err := dataDir(); err == nil {
+		db, err := storage.OpenConfiguredDatabase(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not open database, using JSON files: %v\n", err)
+		}
+		if db != nil {
+			done = func() {
+				if err := db.Close(); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+			}
+		}
+	}
+	return storage.NewStorage(favDir), done, nil
+}
+
+// useStationNotes loads the user's station notes so their display names and
+// preferred stream URLs apply to the stations a command shows or plays.
+func useStationNotes(dir string) {
+	notes, err := storage.NewNotesManager(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not load station notes: %v\n", err)
+	}
+	api.SetStationOverlay(notes)
+}
+
+// newMetadataManager creates a MetadataManager using the standard data path
+// that logs listening sessions as played from the command line.
 func newMetadataManager() (*storage.MetadataManager, error) {
 	dir, err := dataDir()
 	if err != nil {
 		return nil, err
 	}
-	return storage.NewMetadataManager(dir)
+	useConfiguredDatabase(dir)
+	meta, err := storage.NewMetadataManager(dir)
+	if err != nil {
+		return nil, err
+	}
+	meta.SetSessionLog(storage.OpenConfiguredSessionLog(dir))
+	meta.SetPlaySource("cli")
+	return meta, nil
 }
 
 // newRatingsManager creates a RatingsManager using the standard data path.
@@ -218,6 +270,7 @@ func newRatingsManager() (*storage.RatingsManager, error) {
 	if err != nil {
 		return nil, err
 	}
+	useConfiguredDatabase(dir)
 	return storage.NewRatingsManager(dir)
 }
 
@@ -259,6 +312,10 @@ func runPlayback(station *api.Station, contextLabel string, dur time.Duration, m
 		}()
 	}
 
+	if dir, err := dataDir(); err == nil {
+		useStationNotes(dir)
+	}
+
 	p := player.NewMPVPlayer()
 	if meta != nil {
 		p.SetMetadataManager(meta)
@@ -275,7 +332,7 @@ func runPlayback(station *api.Station, contextLabel string, dur time.Duration, m
 	}
 
 	// Print status line
-	name := truncate(station.Name, 40)
+	name := truncate(station.TrimName(), 40)
 	if dur > 0 {
 		fmt.Printf("▶ Playing: %s  [%s]  (stops in %s · Ctrl+C to stop early)\n",
 			name, contextLabel, dur)
@@ -322,13 +379,19 @@ func runPlayback(station *api.Station, contextLabel string, dur time.Duration, m
 // handlePlayFavorites: tera play fav [list-name] [n]
 // -----------------------------------------------------------------
 func handlePlayFavorites(listName string, n int, dur time.Duration) {
-	favDir, err := favoritesDir()
+	// The database stays open for the play statistics
+	store, _, err := openFavorites()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	store := storage.NewStorage(favDir)
+	// "smooth" finds "Jazz/Smooth" when no other list has that name
+	listName, err = store.ResolveListName(context.Background(), listName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 	list, err := store.LoadList(context.Background(), listName)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -544,9 +607,13 @@ Defaults:
   list-name  My-favorites
   n          1 (first item)
 
+A list in a folder is named by its path (Jazz/Smooth), or by its last part
+alone when no other list has that name.
+
 Examples:
   tera play fav
   tera play fav jazz 3
+  tera play fav Jazz/Smooth 2
   tera play recent 2 / tera play rec 2
   tera play top
   tera play most-played 3
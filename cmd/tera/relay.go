@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/relay"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// handleRelay is the entry point for `tera relay ...`.
+func handleRelay(args []string) {
+	if len(args) == 0 {
+		printRelayHelp()
+		return
+	}
+
+	rc, err := storage.LoadRelayConfigFromUnified()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "status":
+		printRelayStatus(rc)
+
+	case "enable":
+		rc.Enabled = true
+		if len(args) > 1 {
+			rc.Listen = args[1]
+			if err := rc.Validate(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		saveRelayConfig(rc)
+		fmt.Println("✓ Relay enabled (takes effect next time TERA starts)")
+		printRelayStatus(rc)
+
+	case "disable":
+		rc.Enabled = false
+		saveRelayConfig(rc)
+		fmt.Println("✓ Relay disabled")
+
+	case "--help", "-h":
+		printRelayHelp()
+
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown relay command %q\n\n", args[0])
+		printRelayHelp()
+		os.Exit(1)
+	}
+}
+
+func saveRelayConfig(rc config.RelayConfig) {
+	if err := storage.SaveRelayConfigToUnified(rc); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printRelayStatus(rc config.RelayConfig) {
+	state := "disabled"
+	if rc.Enabled {
+		state = "enabled"
+	}
+	fmt.Printf("  Status:  %s\n", state)
+	fmt.Printf("  Listen:  %s\n", rc.Listen)
+	host, port, err := net.SplitHostPort(rc.Listen)
+	if err != nil {
+		return
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "<this-computer>"
+	}
+	fmt.Printf("  Stream:  http://%s%s\n", net.JoinHostPort(host, port), relay.MountPath)
+}
+
+func printRelayHelp() {
+	fmt.Print(`TERA Relay Commands
+
+Usage: tera relay <command>
+
+Commands:
+  status               Show relay settings and the stream URL
+  enable [host:port]   Enable re-streaming of the playing station
+  disable              Disable the relay
+
+While enabled, whatever TERA plays is pulled once and served as an
+Icecast-compatible stream (with ICY track titles) that any number of local
+players can open. The stream follows station changes made in TERA.
+
+The relay listens on 127.0.0.1:8788 by default. To reach it from smart
+speakers or other computers, bind to all interfaces:
+
+  tera relay enable 0.0.0.0:8788
+
+The stream is not password protected; only enable it on trusted networks.
+`)
+}
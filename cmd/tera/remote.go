@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/remote"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// handleRemote is the entry point for `tera remote ...`.
+func handleRemote(args []string) {
+	if len(args) == 0 {
+		printRemoteHelp()
+		return
+	}
+
+	rc, err := storage.LoadRemoteConfigFromUnified()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "status":
+		printRemoteStatus(rc)
+
+	case "enable":
+		rc.Enabled = true
+		if len(args) > 1 {
+			rc.Listen = args[1]
+			if err := rc.Validate(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if rc.Token == "" {
+			if rc.Token, err = remote.GenerateToken(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		saveRemoteConfig(rc)
+		fmt.Println("✓ Remote enabled (takes effect next time TERA starts)")
+		printRemoteStatus(rc)
+
+	case "disable":
+		rc.Enabled = false
+		saveRemoteConfig(rc)
+		fmt.Println("✓ Remote disabled")
+
+	case "rotate-token":
+		if rc.Token, err = remote.GenerateToken(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		saveRemoteConfig(rc)
+		fmt.Println("✓ New token generated; previously paired devices must reconnect")
+		printRemoteStatus(rc)
+
+	case "--help", "-h":
+		printRemoteHelp()
+
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown remote command %q\n\n", args[0])
+		printRemoteHelp()
+		os.Exit(1)
+	}
+}
+
+func saveRemoteConfig(rc config.RemoteConfig) {
+	if err := storage.SaveRemoteConfigToUnified(rc); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving config: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printRemoteStatus(rc config.RemoteConfig) {
+	state := "disabled"
+	if rc.Enabled {
+		state = "enabled"
+	}
+	fmt.Printf("  Status:  %s\n", state)
+	fmt.Printf("  Listen:  %s\n", rc.Listen)
+	if rc.Token == "" {
+		fmt.Println("  Token:   (generated on first start)")
+		return
+	}
+	fmt.Printf("  Token:   %s\n", rc.Token)
+	fmt.Printf("  Open:    %s\n", remoteURL(rc))
+	if host, _, err := net.SplitHostPort(rc.Listen); err == nil {
+		if ip := net.ParseIP(host); host == "" || (ip != nil && !ip.IsLoopback()) {
+			fmt.Println("\n  Note: the remote is reachable from other devices on your network.")
+		}
+	}
+}
+
+// remoteURL returns the URL of the web remote with the token in the fragment,
+// which browsers never send to the server or write to access logs.
+func remoteURL(rc config.RemoteConfig) string {
+	host, port, err := net.SplitHostPort(rc.Listen)
+	if err != nil {
+		return ""
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("http://%s/#token=%s", net.JoinHostPort(host, port), rc.Token)
+}
+
+func printRemoteHelp() {
+	fmt.Print(`TERA Remote Commands
+
+Usage: tera remote <command>
+
+Commands:
+  status                Show remote settings and the pairing URL
+  enable [host:port]    Enable the HTTP API and web remote
+  disable               Disable the HTTP API and web remote
+  rotate-token          Generate a new access token
+
+The remote starts together with the TUI. It listens on 127.0.0.1:8787 by
+default, so only this computer can reach it. To control TERA from a phone on
+your LAN, bind to all interfaces:
+
+  tera remote enable 0.0.0.0:8787
+
+Every API request must carry the token as "Authorization: Bearer <token>".
+Settings live in the 'remote' section of config.yaml.
+`)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/stats"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// handleReport is the entry point for `tera report [flags]`.
+func handleReport(args []string) {
+	now := time.Now()
+	reportCmd := flag.NewFlagSet("report", flag.ExitOnError)
+	reportCmd.Usage = printReportHelp
+	year := reportCmd.Int("year", now.Year(), "year to report on")
+	format := reportCmd.String("format", stats.YearFormatMarkdown, "md or html")
+	output := reportCmd.String("output", "", "file to write, or - for standard output")
+	reportCmd.StringVar(output, "o", "", "file to write, or - for standard output")
+	top := reportCmd.Int("top", stats.DefaultYearTop, "entries in each list")
+	if err := reportCmd.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if reportCmd.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n\n", reportCmd.Arg(0))
+		printReportHelp()
+		os.Exit(1)
+	}
+	if *format != stats.YearFormatMarkdown && *format != stats.YearFormatHTML {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (use md or html)\n", *format)
+		os.Exit(1)
+	}
+	if *year < 2000 || *year > now.Year() {
+		fmt.Fprintf(os.Stderr, "Error: --year must be between 2000 and %d\n", now.Year())
+		os.Exit(1)
+	}
+	if *top < 1 {
+		fmt.Fprintln(os.Stderr, "Error: --top must be at least 1")
+		os.Exit(1)
+	}
+
+	report, err := collectYearReport(*year, now, *top)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *output == "-" {
+		if err := stats.RenderYear(os.Stdout, report, *format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	path := *output
+	if path == "" {
+		if path, err = defaultReportPath(*year, *format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if err := writeReport(path, report, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ %d report saved to %s\n", *year, path)
+}
+
+// collectYearReport opens the data stores and builds the report for year.
+func collectYearReport(year int, now time.Time, top int) (stats.YearReport, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return stats.YearReport{}, err
+	}
+	useStationNotes(dir)
+	meta, err := newMetadataManager()
+	if err != nil {
+		return stats.YearReport{}, err
+	}
+	defer func() { _ = meta.Close() }()
+	ratings, err := newRatingsManager()
+	if err != nil {
+		return stats.YearReport{}, err
+	}
+	defer func() { _ = ratings.Close() }()
+	tags, err := storage.NewTagsManager(dir)
+	if err != nil {
+		return stats.YearReport{}, err
+	}
+	defer func() { _ = tags.Close() }()
+	liked, err := storage.NewLikedSongsManager(dir)
+	if err != nil {
+		return stats.YearReport{}, err
+	}
+
+	return stats.CollectYear(dir, stats.YearStores{
+		Metadata:   meta,
+		Ratings:    ratings,
+		Tags:       tags,
+		LikedSongs: liked,
+	}, year, now, top)
+}
+
+// writeReport renders report into a new file at path.
+func writeReport(path string, report stats.YearReport, format string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := stats.RenderYear(f, report, format); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// defaultReportPath returns ~/tera-<year>-in-review.<format>.
+func defaultReportPath(year int, format string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, fmt.Sprintf("tera-%d-in-review.%s", year, format)), nil
+}
+
+func printReportHelp() {
+	fmt.Print(`TERA Year in Review
+
+Usage: tera report [--year YYYY] [--format md|html] [--output FILE] [--top N]
+
+Builds a shareable summary of a year of listening: hours listened, a monthly
+breakdown, top stations and genres, new discoveries and your most-liked
+songs. The report is a single file with inline charts and needs no network
+to view.
+
+Options:
+  --year YYYY       Year to report on (default this year)
+  --format md|html  Markdown or a self-contained HTML page (default md)
+  -o, --output FILE File to write, or - for standard output
+                    (default ~/tera-YYYY-in-review.md or .html)
+  --top N           Entries in each list (default 10)
+
+Listening time comes from the session log (see 'session_log' in
+config.yaml). If the log has nothing from the year, stations played that
+year are ranked by their all-time play statistics instead.
+
+Examples:
+  tera report
+  tera report --year 2026 --format html
+  tera report --format md -o - | less
+`)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/stats"
+)
+
+// defaultStatsWidth is the chart width when $COLUMNS is not set.
+const defaultStatsWidth = 80
+
+// handleStats is the entry point for `tera stats [flags]`.
+func handleStats(args []string) {
+	opts := stats.DefaultOptions()
+	statsCmd := flag.NewFlagSet("stats", flag.ExitOnError)
+	statsCmd.Usage = printStatsHelp
+	asJSON := statsCmd.Bool("json", false, "print the report as JSON")
+	statsCmd.IntVar(&opts.Weeks, "weeks", opts.Weeks, "weeks of weekly totals")
+	statsCmd.IntVar(&opts.Months, "months", opts.Months, "months of monthly totals")
+	statsCmd.IntVar(&opts.Top, "top", opts.Top, "entries in each top list")
+	if err := statsCmd.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if statsCmd.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n\n", statsCmd.Arg(0))
+		printStatsHelp()
+		os.Exit(1)
+	}
+	if opts.Weeks < 0 || opts.Months < 0 || opts.Top < 1 {
+		fmt.Fprintln(os.Stderr, "Error: --weeks and --months cannot be negative and --top must be at least 1")
+		os.Exit(1)
+	}
+
+	dir, err := dataDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	useStationNotes(dir)
+	meta, err := newMetadataManager()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	report, err := stats.Collect(dir, meta, time.Now(), opts)
+	_ = meta.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Println(stats.Render(report, statsWidth()))
+}
+
+// statsWidth returns the terminal width from $COLUMNS, or a default.
+func statsWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultStatsWidth
+}
+
+func printStatsHelp() {
+	fmt.Print(`TERA Listening Stats
+
+Usage: tera stats [--json] [--weeks N] [--months N] [--top N]
+
+Shows total listening time per week and month, top stations, tags,
+countries and languages by listening time, listening by hour of day and
+weekday, and listening streaks.
+
+Options:
+  --json       Print the report as JSON for scripts
+  --weeks N    Weeks of weekly totals (default 8)
+  --months N   Months of monthly totals (default 6)
+  --top N      Entries in each top list (default 5)
+
+Weekly and monthly totals, the heatmap and streaks come from the listening
+session log (see 'session_log' in config.yaml); top lists use all-time play
+statistics.
+
+Examples:
+  tera stats
+  tera stats --weeks 12 --top 10
+  tera stats --json | jq '.top_stations'
+`)
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/subscription"
+)
+
+// handleSub is the entry point for `tera sub <subcommand>`.
+func handleSub(args []string) {
+	if len(args) == 0 {
+		printSubHelp()
+		return
+	}
+	switch args[0] {
+	case "add":
+		handleSubAdd(args[1:])
+	case "list", "ls":
+		handleSubList()
+	case "remove", "rm":
+		handleSubRemove(args[1:])
+	case "refresh":
+		handleSubRefresh(args[1:])
+	case "copy":
+		handleSubCopy(args[1:])
+	case "--help", "-h", "help":
+		printSubHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown sub command %q\n\n", args[0])
+		printSubHelp()
+		os.Exit(1)
+	}
+}
+
+// openSubscriptions loads the subscriptions or exits.
+func openSubscriptions() *subscription.Manager {
+	dir, err := dataDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	mgr, err := subscription.NewManager(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return mgr
+}
+
+// subArg parses flags around the one positional argument of a subcommand.
+func subArg(fs *flag.FlagSet, args []string, what string) string {
+	arg, err := parseWithArg(fs, args)
+	if err != nil || arg == "" {
+		if err == nil {
+			err = fmt.Errorf("missing %s", what)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		printSubHelp()
+		os.Exit(1)
+	}
+	return arg
+}
+
+func handleSubAdd(args []string) {
+	fs := flag.NewFlagSet("sub add", flag.ExitOnError)
+	fs.Usage = printSubHelp
+	name := fs.String("name", "", "name shown in favorites (default: from the gist or file)")
+	file := fs.String("file", "", "read only this file or list of the gist")
+	every := fs.Duration("every", subscription.DefaultInterval, "how often to refresh")
+	source := subArg(fs, args, "gist or URL")
+
+	mgr := openSubscriptions()
+	sub, err := mgr.Add(context.Background(), *name, source, *file, *every)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Subscribed to '%s' (%d station(s), refreshed every %v)\n", sub.Name, len(sub.Stations), sub.Interval())
+}
+
+func handleSubList() {
+	subs := openSubscriptions().List()
+	if len(subs) == 0 {
+		fmt.Println("No subscriptions. Add one with: tera sub add <gist-or-url>")
+		return
+	}
+	for _, sub := range subs {
+		fmt.Printf("%s  (%d station(s), every %v)\n", sub.Name, len(sub.Stations), sub.Interval())
+		fmt.Printf("  %s\n", sub.Source)
+		fmt.Printf("  updated %s", sub.UpdatedAt.Local().Format(time.DateTime))
+		if sub.LastError != "" {
+			fmt.Printf(" • last refresh failed: %s", sub.LastError)
+		}
+		fmt.Println()
+	}
+}
+
+func handleSubRemove(args []string) {
+	fs := flag.NewFlagSet("sub remove", flag.ExitOnError)
+	fs.Usage = printSubHelp
+	name := subArg(fs, args, "subscription name")
+	if err := openSubscriptions().Remove(name); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Unsubscribed from '%s'\n", name)
+}
+
+func handleSubRefresh(args []string) {
+	fs := flag.NewFlagSet("sub refresh", flag.ExitOnError)
+	fs.Usage = printSubHelp
+	name, err := parseWithArg(fs, args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		printSubHelp()
+		os.Exit(1)
+	}
+
+	mgr := openSubscriptions()
+	names := []string{name}
+	if name == "" {
+		names = mgr.Names()
+	}
+	failed := false
+	for _, n := range names {
+		if err := mgr.Refresh(context.Background(), n); err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %s: %v\n", n, err)
+			failed = true
+			continue
+		}
+		sub, _ := mgr.Get(n)
+		fmt.Printf("✓ Refreshed '%s' (%d station(s))\n", sub.Name, len(sub.Stations))
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func handleSubCopy(args []string) {
+	fs := flag.NewFlagSet("sub copy", flag.ExitOnError)
+	fs.Usage = printSubHelp
+	list := fs.String("list", "", "local list to copy into (default: the subscription name)")
+	name := subArg(fs, args, "subscription name")
+	if *list == "" {
+		*list = name
+	}
+
+	store, done, err := openFavorites()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer done()
+	n, err := openSubscriptions().CopyTo(context.Background(), store, name, *list)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Copied %d station(s) from '%s' into '%s'\n", n, name, *list)
+}
+
+func printSubHelp() {
+	fmt.Print(`TERA Subscriptions
+
+Usage:
+  tera sub add <gist-or-url> [--name NAME] [--file FILE] [--every 24h]
+  tera sub list
+  tera sub refresh [name]
+  tera sub copy <name> [--list LIST]
+  tera sub remove <name>
+
+A subscription follows a favorites list someone else publishes, as a
+read-only list shown under your own lists in Play from Favorites. The
+source is a public gist (URL or ID), such as one made with Sync to Gist,
+or an https URL of a TERA list (.json) or an M3U, PLS, XSPF or OPML
+playlist. Every list in a gist is combined unless --file picks one, by
+file name or list name.
+
+Subscriptions are refreshed in the background while TERA runs, every 24
+hours unless --every says otherwise (at least 15m). If a refresh fails,
+the stations from the last one are kept. Copy a subscription into a local
+list to edit it; stations already in the list are skipped.
+
+Examples:
+  tera sub add https://gist.github.com/someone/aa5a315d61ae9438b18d
+  tera sub add aa5a315d61ae9438b18d --file Jazz --name Team-Jazz --every 6h
+  tera sub add https://example.com/stations.m3u --name Community
+  tera sub copy Team-Jazz --list Jazz
+`)
+}
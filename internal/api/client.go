@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,6 +15,10 @@ import (
 
 var baseURL = "https://de1.api.radio-browser.info/json/stations"
 
+// ErrStationNotFound is returned by GetByUUID when Radio Browser has no
+// station with the UUID.
+var ErrStationNotFound = errors.New("station not found")
+
 type Client struct {
 	httpClient *http.Client
 }
@@ -33,11 +38,24 @@ func (c *Client) SearchByTag(ctx context.Context, tag string) ([]Station, error)
 	return c.doSearch(ctx, form)
 }
 
+// SearchByURL returns the stations whose stream URL is exactly streamURL.
+func (c *Client) SearchByURL(ctx context.Context, streamURL string) ([]Station, error) {
+	form := url.Values{}
+	form.Add("url", strings.TrimSpace(streamURL))
+
+	return c.postStations(ctx, "/byurl", form)
+}
+
 func (c *Client) doSearch(ctx context.Context, form url.Values) ([]Station, error) {
+	return c.postStations(ctx, "/search", form)
+}
+
+// postStations posts form to a stations endpoint and decodes the result.
+func (c *Client) postStations(ctx context.Context, endpoint string, form url.Values) ([]Station, error) {
 	req, err := http.NewRequestWithContext(
 		ctx,
 		"POST",
-		baseURL+"/search",
+		baseURL+endpoint,
 		bytes.NewBufferString(form.Encode()),
 	)
 	if err != nil {
@@ -94,7 +112,7 @@ func (c *Client) GetByUUID(ctx context.Context, stationUUID string) (*Station, e
 		return nil, err
 	}
 	if len(stations) == 0 {
-		return nil, fmt.Errorf("station %s not found", stationUUID)
+		return nil, fmt.Errorf("%w: %s", ErrStationNotFound, stationUUID)
 	}
 	return &stations[0], nil
 }
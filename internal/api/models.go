@@ -1,6 +1,15 @@
 package api
 
-import "strings"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync/atomic"
+)
+
+// customStationPrefix marks the UUID of a station that is not in Radio
+// Browser, such as a stream imported from another app's playlist.
+const customStationPrefix = "custom-"
 
 // Station represents a radio station from Radio Browser API
 type Station struct {
@@ -16,13 +25,122 @@ type Station struct {
 	Codec       string `json:"codec"`
 	Bitrate     int    `json:"bitrate"`
 	Volume      *int   `json:"volume,omitempty"` // Per-station volume (0-100), nil means use default
+	// Per-station playback overrides, nil means use the connection defaults
+	Options *PlaybackOptions `json:"playback_options,omitempty"`
+}
+
+// PlaybackOptions are per-station overrides applied when the station is
+// played. They are stored with the favorite, never sent to Radio Browser.
+type PlaybackOptions struct {
+	UserAgent   string            `json:"user_agent,omitempty"`   // Replaces mpv's default User-Agent
+	Referer     string            `json:"referer,omitempty"`      // Sent as the HTTP Referer
+	Headers     map[string]string `json:"headers,omitempty"`      // Extra HTTP request headers
+	CacheMB     int               `json:"cache_mb,omitempty"`     // Demuxer cache size; 0 uses the connection setting
+	StartOffset int               `json:"start_offset,omitempty"` // Seconds to skip at start (on-demand streams)
+	MPVArgs     []string          `json:"mpv_args,omitempty"`     // Extra mpv flags, checked against a safe list
+}
+
+// IsZero reports whether o carries no overrides.
+func (o *PlaybackOptions) IsZero() bool {
+	return o == nil || (o.UserAgent == "" && o.Referer == "" && len(o.Headers) == 0 &&
+		o.CacheMB == 0 && o.StartOffset == 0 && len(o.MPVArgs) == 0)
+}
+
+// CustomStationUUID returns a stable UUID for a custom station playing
+// streamURL.
+func CustomStationUUID(streamURL string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(streamURL)))
+	return customStationPrefix + hex.EncodeToString(sum[:8])
+}
+
+// IsCustom reports whether the station is a custom stream rather than a
+// Radio Browser station, so it cannot be voted for or looked up.
+func (s *Station) IsCustom() bool {
+	return strings.HasPrefix(s.StationUUID, customStationPrefix)
+}
+
+// StationOverlay supplies the user's own details for stations, keyed by
+// station UUID. Empty strings mean the station has none.
+type StationOverlay interface {
+	DisplayName(uuid string) string
+	PreferredURL(uuid string) string
+	Notes(uuid string) string
+}
+
+type overlayHolder struct{ StationOverlay }
+
+var activeOverlay atomic.Pointer[overlayHolder]
+
+// SetStationOverlay makes TrimName, StreamURL and SearchText use o. Pass nil
+// to show stations as Radio Browser has them.
+func SetStationOverlay(o StationOverlay) {
+	if o == nil {
+		activeOverlay.Store(nil)
+		return
+	}
+	activeOverlay.Store(&overlayHolder{o})
+}
+
+func stationOverlay() StationOverlay {
+	if h := activeOverlay.Load(); h != nil {
+		return h.StationOverlay
+	}
+	return nil
 }
 
-// TrimName returns station name with whitespace trimmed
+// TrimName returns the name shown for the station: the user's display name
+// when one is set, otherwise the station name with whitespace trimmed.
 func (s *Station) TrimName() string {
+	if o := stationOverlay(); o != nil {
+		if name := o.DisplayName(s.StationUUID); name != "" {
+			return name
+		}
+	}
 	return strings.TrimSpace(s.Name)
 }
 
+// OriginalName returns the station name with whitespace trimmed, ignoring
+// any display name the user set.
+func (s *Station) OriginalName() string {
+	return strings.TrimSpace(s.Name)
+}
+
+// StreamURL returns the URL to play: the user's preferred stream URL when
+// one is set, otherwise URLResolved.
+func (s *Station) StreamURL() string {
+	if o := stationOverlay(); o != nil {
+		if u := o.PreferredURL(s.StationUUID); u != "" {
+			return u
+		}
+	}
+	return s.URLResolved
+}
+
+// UserNotes returns the user's notes on the station, or "".
+func (s *Station) UserNotes() string {
+	if o := stationOverlay(); o != nil {
+		return o.Notes(s.StationUUID)
+	}
+	return ""
+}
+
+// SearchText returns the text a local filter matches against: the station
+// name, plus the user's display name and notes when set.
+func (s *Station) SearchText() string {
+	o := stationOverlay()
+	if o == nil {
+		return s.Name
+	}
+	parts := []string{s.Name}
+	if name := o.DisplayName(s.StationUUID); name != "" {
+		parts = append(parts, name)
+	}
+	if notes := s.UserNotes(); notes != "" {
+		parts = append(parts, notes)
+	}
+	return strings.Join(parts, " ")
+}
+
 // SetVolume sets the station's volume (0-100)
 func (s *Station) SetVolume(vol int) {
 	s.Volume = &vol
@@ -5,13 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
 )
 
 // Manager handles blocklist operations with thread-safe access
@@ -21,6 +21,7 @@ type Manager struct {
 	blockRules    []BlockRule               // Active block rules
 	mu            sync.RWMutex              // Protects concurrent access
 	lastBlock     *BlockedStation           // Last blocked station for undo feature
+	file          *storage.SharedFile       // blocklist.json, shared with other TERA processes
 }
 
 // NewManager creates a new blocklist manager
@@ -29,6 +30,7 @@ func NewManager(blocklistPath string) *Manager {
 	return &Manager{
 		blocklistPath: blocklistPath,
 		blockedMap:    make(map[string]BlockedStation),
+		file:          storage.NewSharedFile(blocklistPath),
 	}
 }
 
@@ -36,16 +38,31 @@ func NewManager(blocklistPath string) *Manager {
 func (m *Manager) Load(ctx context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	storage.MigrateOnLoad(m.blocklistPath)
+	return m.loadLocked()
+}
+
+// Reload rereads the blocklist if another process changed it since it was
+// last read or saved. It reports whether the blocklist was reread.
+func (m *Manager) Reload() (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.file.Changed() {
+		return false, nil
+	}
+	return true, m.reloadLocked()
+}
 
-	// If file doesn't exist, start with empty blocklist
-	if _, err := os.Stat(m.blocklistPath); os.IsNotExist(err) {
+// loadLocked reads the blocklist (caller must hold m.mu)
+func (m *Manager) loadLocked() error {
+	data, err := m.file.Read()
+	if os.IsNotExist(err) {
+		// If file doesn't exist, start with empty blocklist
 		m.blockedMap = make(map[string]BlockedStation)
 		m.blockRules = []BlockRule{}
 		m.lastBlock = nil
 		return nil
 	}
-
-	data, err := os.ReadFile(m.blocklistPath)
 	if err != nil {
 		return fmt.Errorf("failed to read blocklist: %w", err)
 	}
@@ -74,10 +91,43 @@ func (m *Manager) Load(ctx context.Context) error {
 	return nil
 }
 
+// reloadLocked rereads the blocklist another process saved. Unlike Load it
+// keeps the undo target (caller must hold m.mu)
+func (m *Manager) reloadLocked() error {
+	lastBlock := m.lastBlock
+	if err := m.loadLocked(); err != nil {
+		return err
+	}
+	m.lastBlock = lastBlock
+	return nil
+}
+
+// lockFile takes the cross-process lock on the blocklist and rereads it if
+// another process saved it since, so a change is made to the latest copy
+// (caller must hold m.mu)
+func (m *Manager) lockFile() (*storage.FileLock, error) {
+	lock, err := m.file.Lock()
+	if err != nil {
+		return nil, err
+	}
+	if m.file.Changed() {
+		if err := m.reloadLocked(); err != nil {
+			lock.Unlock()
+			return nil, err
+		}
+	}
+	return lock, nil
+}
+
 // Save writes the blocklist to disk
 func (m *Manager) Save(ctx context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	lock, err := m.lockFile()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
 	return m.save()
 }
 
@@ -90,6 +140,11 @@ func (m *Manager) Block(ctx context.Context, station *api.Station) (string, erro
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	lock, err := m.lockFile()
+	if err != nil {
+		return "", err
+	}
+	defer lock.Unlock()
 
 	// Check if already blocked
 	if _, exists := m.blockedMap[station.StationUUID]; exists {
@@ -124,6 +179,7 @@ func (m *Manager) Block(ctx context.Context, station *api.Station) (string, erro
 		m.lastBlock = prevLastBlock
 		return "", err
 	}
+	storage.RecordChange("Blocked "+station.TrimName(), stationChange(station.StationUUID, nil, &blocked))
 
 	// Generate message with optional warning
 	count := len(m.blockedMap)
@@ -143,6 +199,11 @@ func (m *Manager) Block(ctx context.Context, station *api.Station) (string, erro
 func (m *Manager) Unblock(ctx context.Context, stationUUID string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	lock, err := m.lockFile()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
 
 	// Check if blocked and save for rollback
 	station, exists := m.blockedMap[stationUUID]
@@ -159,6 +220,7 @@ func (m *Manager) Unblock(ctx context.Context, stationUUID string) error {
 		m.blockedMap[stationUUID] = station
 		return err
 	}
+	storage.RecordChange("Unblocked "+station.Name, stationChange(stationUUID, &station, nil))
 	return nil
 }
 
@@ -202,6 +264,11 @@ func (m *Manager) Count() int {
 func (m *Manager) Clear(ctx context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	lock, err := m.lockFile()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
 
 	// Save for rollback
 	oldMap := m.blockedMap
@@ -216,6 +283,11 @@ func (m *Manager) Clear(ctx context.Context) error {
 		m.lastBlock = oldLastBlock
 		return err
 	}
+	changes := make([]storage.JournalChange, 0, len(oldMap))
+	for uuid, station := range oldMap {
+		changes = append(changes, stationChange(uuid, &station, nil))
+	}
+	storage.RecordChange("Cleared blocklist", changes...)
 	return nil
 }
 
@@ -232,6 +304,11 @@ func (m *Manager) GetLastBlocked() *BlockedStation {
 func (m *Manager) UndoLastBlock(ctx context.Context) (bool, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	lock, err := m.lockFile()
+	if err != nil {
+		return false, err
+	}
+	defer lock.Unlock()
 
 	if m.lastBlock == nil {
 		return false, nil
@@ -251,18 +328,13 @@ func (m *Manager) UndoLastBlock(ctx context.Context) (bool, error) {
 		m.lastBlock = &undone
 		return false, err
 	}
+	storage.RecordChange("Unblocked "+undone.Name, stationChange(undone.StationUUID, &undone, nil))
 
 	return true, nil
 }
 
 // save is an internal helper that saves without locking (caller must hold lock)
 func (m *Manager) save() error {
-	// Ensure directory exists
-	dir := filepath.Dir(m.blocklistPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create blocklist directory: %w", err)
-	}
-
 	// Convert map to slice
 	stations := make([]BlockedStation, 0, len(m.blockedMap))
 	for _, station := range m.blockedMap {
@@ -280,33 +352,8 @@ func (m *Manager) save() error {
 		return fmt.Errorf("failed to marshal blocklist: %w", err)
 	}
 
-	// Atomic write using temp file and rename
-	tmpFile, err := os.CreateTemp(dir, "blocklist-*.tmp")
-	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
-	}
-	tmpName := tmpFile.Name()
-	defer func() {
-		_ = tmpFile.Close()
-		if err != nil {
-			_ = os.Remove(tmpName)
-		}
-	}()
-
-	if _, err := tmpFile.Write(data); err != nil {
-		return fmt.Errorf("failed to write to temp file: %w", err)
-	}
-
-	if err := tmpFile.Sync(); err != nil {
-		return fmt.Errorf("failed to sync temp file: %w", err)
-	}
-
-	if err := tmpFile.Close(); err != nil {
-		return fmt.Errorf("failed to close temp file: %w", err)
-	}
-
-	if err := os.Rename(tmpName, m.blocklistPath); err != nil {
-		return fmt.Errorf("failed to rename temp file: %w", err)
+	if err := m.file.Write(data, 0644); err != nil {
+		return fmt.Errorf("failed to write blocklist: %w", err)
 	}
 
 	return nil
@@ -316,6 +363,11 @@ func (m *Manager) save() error {
 func (m *Manager) AddBlockRule(ctx context.Context, ruleType BlockRuleType, value string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	lock, err := m.lockFile()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
 
 	// Check if rule already exists
 	for _, rule := range m.blockRules {
@@ -337,6 +389,7 @@ func (m *Manager) AddBlockRule(ctx context.Context, ruleType BlockRuleType, valu
 		m.blockRules = m.blockRules[:len(m.blockRules)-1]
 		return err
 	}
+	storage.RecordChange("Added block rule "+newRule.String(), ruleChange(newRule, nil, &newRule))
 	return nil
 }
 
@@ -344,6 +397,11 @@ func (m *Manager) AddBlockRule(ctx context.Context, ruleType BlockRuleType, valu
 func (m *Manager) RemoveBlockRule(ctx context.Context, ruleType BlockRuleType, value string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	lock, err := m.lockFile()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
 
 	// Find and remove rule
 	for i, rule := range m.blockRules {
@@ -359,6 +417,7 @@ func (m *Manager) RemoveBlockRule(ctx context.Context, ruleType BlockRuleType, v
 				m.blockRules = append(m.blockRules[:idx], append([]BlockRule{removed}, m.blockRules[idx:]...)...)
 				return err
 			}
+			storage.RecordChange("Removed block rule "+removed.String(), ruleChange(removed, &removed, nil))
 			return nil
 		}
 	}
@@ -413,3 +472,69 @@ func (m *Manager) IsBlockedByAny(station *api.Station) bool {
 
 	return false
 }
+
+// Journal keys of blocked stations and block rules.
+const (
+	journalStationPrefix = "station:"
+	journalRulePrefix    = "rule:"
+)
+
+func stationChange(uuid string, before, after *BlockedStation) storage.JournalChange {
+	return storage.NewJournalChange(storage.JournalBlocklist, journalStationPrefix+uuid, before, after)
+}
+
+func ruleChange(rule BlockRule, before, after *BlockRule) storage.JournalChange {
+	key := journalRulePrefix + string(rule.Type) + ":" + strings.ToLower(rule.Value)
+	return storage.NewJournalChange(storage.JournalBlocklist, key, before, after)
+}
+
+// ApplyJournal puts a blocked station or a block rule back for undo and
+// redo. value holds the BlockedStation or BlockRule, or null to remove it.
+func (m *Manager) ApplyJournal(key string, value json.RawMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lock, err := m.lockFile()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	remove := len(value) == 0 || string(value) == "null"
+	switch {
+	case strings.HasPrefix(key, journalStationPrefix):
+		uuid := strings.TrimPrefix(key, journalStationPrefix)
+		if remove {
+			delete(m.blockedMap, uuid)
+			if m.lastBlock != nil && m.lastBlock.StationUUID == uuid {
+				m.lastBlock = nil
+			}
+			break
+		}
+		var station BlockedStation
+		if err := json.Unmarshal(value, &station); err != nil {
+			return err
+		}
+		m.blockedMap[uuid] = station
+
+	case strings.HasPrefix(key, journalRulePrefix):
+		typ, val, _ := strings.Cut(strings.TrimPrefix(key, journalRulePrefix), ":")
+		rules := m.blockRules[:0:0]
+		for _, rule := range m.blockRules {
+			if rule.Type != BlockRuleType(typ) || !strings.EqualFold(rule.Value, val) {
+				rules = append(rules, rule)
+			}
+		}
+		if !remove {
+			var rule BlockRule
+			if err := json.Unmarshal(value, &rule); err != nil {
+				return err
+			}
+			rules = append(rules, rule)
+		}
+		m.blockRules = rules
+
+	default:
+		return fmt.Errorf("unknown blocklist journal key %q", key)
+	}
+	return m.save()
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
 )
 
 func TestNewManager(t *testing.T) {
@@ -396,3 +397,123 @@ func TestConcurrentAccess(t *testing.T) {
 		t.Error("Expected some blocked stations after concurrent access")
 	}
 }
+
+func TestJournalUndoRedo(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := NewManager(filepath.Join(tmpDir, "blocklist.json"))
+	ctx := context.Background()
+
+	journal, err := storage.OpenJournal(tmpDir)
+	if err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	journal.Handle(storage.JournalBlocklist, manager.ApplyJournal)
+	storage.SetJournal(journal)
+	defer storage.SetJournal(nil)
+
+	station := &api.Station{StationUUID: "test-uuid-1", Name: "Test Station"}
+	if _, err := manager.Block(ctx, station); err != nil {
+		t.Fatalf("Block failed: %v", err)
+	}
+	if err := manager.AddBlockRule(ctx, BlockRuleCountry, "US"); err != nil {
+		t.Fatalf("AddBlockRule failed: %v", err)
+	}
+	if err := manager.Clear(ctx); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+
+	// Undo the clear, then the rule
+	if _, err := journal.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if !manager.IsBlocked("test-uuid-1") {
+		t.Error("Expected station to be blocked again after undoing Clear")
+	}
+	if _, err := journal.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if len(manager.GetBlockRules()) != 0 {
+		t.Errorf("Expected no rules after undo, got %v", manager.GetBlockRules())
+	}
+	if _, err := journal.Redo(); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	if rules := manager.GetBlockRules(); len(rules) != 1 || rules[0].Value != "US" {
+		t.Errorf("Expected the US rule after redo, got %v", rules)
+	}
+
+	// The restored state is saved
+	reloaded := NewManager(filepath.Join(tmpDir, "blocklist.json"))
+	if err := reloaded.Load(ctx); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reloaded.IsBlocked("test-uuid-1") || len(reloaded.GetBlockRules()) != 1 {
+		t.Error("Expected the undone changes to be saved")
+	}
+}
+
+func TestTwoManagersShareBlocklist(t *testing.T) {
+	blocklistPath := filepath.Join(t.TempDir(), "blocklist.json")
+	ctx := context.Background()
+
+	first := NewManager(blocklistPath)
+	second := NewManager(blocklistPath)
+	if err := first.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := first.Block(ctx, &api.Station{StationUUID: "a", Name: "Jazz FM"}); err != nil {
+		t.Fatal(err)
+	}
+	// second has not seen the first block, but must not drop it
+	if _, err := second.Block(ctx, &api.Station{StationUUID: "b", Name: "Rock FM"}); err != nil {
+		t.Fatal(err)
+	}
+	if !second.IsBlocked("a") {
+		t.Error("second manager did not pick up the first block")
+	}
+
+	reloaded, err := first.Reload()
+	if err != nil || !reloaded {
+		t.Fatalf("Reload = %v, %v; want true", reloaded, err)
+	}
+	if !first.IsBlocked("b") || first.Count() != 2 {
+		t.Errorf("first manager has %d blocks after reload", first.Count())
+	}
+	if reloaded, _ := first.Reload(); reloaded {
+		t.Error("second Reload reported a change")
+	}
+	// The undo target survives the reread
+	if undone, err := first.UndoLastBlock(ctx); err != nil || !undone {
+		t.Errorf("UndoLastBlock = %v, %v", undone, err)
+	}
+}
+
+func TestLoadMigratesUnversionedBlocklist(t *testing.T) {
+	blocklistPath := filepath.Join(t.TempDir(), "blocklist.json")
+	original := `{"blocked_stations":[{"stationuuid":"a","name":"Jazz FM"}]}`
+	if err := os.WriteFile(blocklistPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewManager(blocklistPath)
+	if err := manager.Load(context.Background()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !manager.IsBlocked("a") {
+		t.Error("blocked station lost in migration")
+	}
+	data, err := os.ReadFile(blocklistPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"version": "1.0"`) || !strings.Contains(string(data), `"block_rules": []`) {
+		t.Errorf("blocklist not migrated:\n%s", data)
+	}
+	if backup, err := os.ReadFile(blocklistPath + ".v0.bak"); err != nil || string(backup) != original {
+		t.Errorf("backup = %q, %v", backup, err)
+	}
+}
@@ -2,7 +2,10 @@ package blocklist
 
 import (
 	"errors"
+	"fmt"
 	"time"
+
+	"github.com/shinokada/tera/v3/internal/storage"
 )
 
 // Errors
@@ -32,6 +35,29 @@ type Blocklist struct {
 	BlockRules      []BlockRule      `json:"block_rules,omitempty"`
 }
 
+// Schema lists the migrations of blocklist.json. The file spells its
+// version "1.0".
+var Schema = &storage.DataSchema{
+	File: "blocklist.json",
+	Migrations: []storage.Migration{{
+		Version:     1,
+		Description: "Add missing station and rule lists",
+		Up: func(doc map[string]any) error {
+			for _, key := range []string{"blocked_stations", "block_rules"} {
+				if doc[key] == nil {
+					doc[key] = []any{}
+				}
+			}
+			return nil
+		},
+	}},
+	FormatVersion: func(version int) any { return fmt.Sprintf("%d.0", version) },
+}
+
+func init() {
+	storage.RegisterDataSchema(Schema)
+}
+
 // BlockWarningThresholds defines when to show warnings
 const (
 	BlockWarningThreshold = 100
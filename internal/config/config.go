@@ -3,7 +3,11 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -21,6 +25,17 @@ type Config struct {
 	Blocklist   BlocklistConfig   `yaml:"blocklist"`
 	PlayHistory PlayHistoryConfig `yaml:"play_history"`
 	PlayOptions PlayOptionsConfig `yaml:"play_options"`
+	Remote      RemoteConfig      `yaml:"remote"`
+	Relay       RelayConfig       `yaml:"relay"`
+	Visualizer  VisualizerConfig  `yaml:"visualizer"`
+	TimeShift   TimeShiftConfig   `yaml:"time_shift"`
+	AdFilter    AdFilterConfig    `yaml:"ad_filter"`
+	Metadata    MetadataConfig    `yaml:"metadata"`
+	Resume      ResumeConfig      `yaml:"resume"`
+	DataSaver   DataSaverConfig   `yaml:"data_saver"`
+	Storage     StorageConfig     `yaml:"storage"`
+	SessionLog  SessionLogConfig  `yaml:"session_log"`
+	AutoBackup  AutoBackupConfig  `yaml:"auto_backup"`
 }
 
 // PlayerConfig represents player settings
@@ -107,6 +122,302 @@ func DefaultPlayOptionsConfig() PlayOptionsConfig {
 	}
 }
 
+// RemoteConfig holds settings for the local HTTP API and web remote.
+type RemoteConfig struct {
+	Enabled bool   `yaml:"enabled"` // Start the HTTP server with the TUI (default: false)
+	Listen  string `yaml:"listen"`  // host:port to bind (default: "127.0.0.1:8787")
+	Token   string `yaml:"token"`   // Shared secret required on every API request; generated when empty
+}
+
+// DefaultRemoteListen is the default bind address for the HTTP remote. It is
+// loopback-only so the API is never exposed to the network unless the user
+// opts in by changing the host.
+const DefaultRemoteListen = "127.0.0.1:8787"
+
+// DefaultRemoteConfig returns a RemoteConfig with sensible defaults.
+func DefaultRemoteConfig() RemoteConfig {
+	return RemoteConfig{
+		Enabled: false,
+		Listen:  DefaultRemoteListen,
+		Token:   "",
+	}
+}
+
+// RelayConfig holds settings for re-streaming the current station over HTTP.
+type RelayConfig struct {
+	Enabled bool   `yaml:"enabled"` // Start the relay with the TUI (default: false)
+	Listen  string `yaml:"listen"`  // host:port to bind (default: "127.0.0.1:8788"; use 0.0.0.0 for the LAN)
+}
+
+// DefaultRelayListen is the default bind address for the stream relay.
+const DefaultRelayListen = "127.0.0.1:8788"
+
+// DefaultRelayConfig returns a RelayConfig with sensible defaults.
+func DefaultRelayConfig() RelayConfig {
+	return RelayConfig{
+		Enabled: false,
+		Listen:  DefaultRelayListen,
+	}
+}
+
+// VisualizerConfig holds settings for the audio level display in the play view
+// and the now-playing bar.
+type VisualizerConfig struct {
+	Enabled bool   `yaml:"enabled"` // Measure and display audio levels (default: false)
+	Style   string `yaml:"style"`   // "vu" or "spectrum" (default: "vu")
+	FPS     int    `yaml:"fps"`     // Redraws per second, range [1, 30] (default: 12)
+	Bands   int    `yaml:"bands"`   // Spectrum bars, range [4, 24] (default: 12)
+	LowCPU  bool   `yaml:"low_cpu"` // Low-CPU mode: never run the analysis filter (default: false)
+}
+
+// Visualizer styles.
+const (
+	VisualizerStyleVU       = "vu"
+	VisualizerStyleSpectrum = "spectrum"
+)
+
+// DefaultVisualizerConfig returns a VisualizerConfig with sensible defaults.
+func DefaultVisualizerConfig() VisualizerConfig {
+	return VisualizerConfig{
+		Enabled: false,
+		Style:   VisualizerStyleVU,
+		FPS:     12,
+		Bands:   12,
+		LowCPU:  false,
+	}
+}
+
+// Active reports whether the visualizer should run at all.
+func (v VisualizerConfig) Active() bool {
+	return v.Enabled && !v.LowCPU
+}
+
+// TimeShiftConfig holds settings for pausing and rewinding live radio.
+type TimeShiftConfig struct {
+	Enabled      bool `yaml:"enabled"`       // Keep played audio for rewinding (default: false)
+	DepthMinutes int  `yaml:"depth_minutes"` // How far back you can rewind, range [1, 120] (default: 10)
+}
+
+// DefaultTimeShiftConfig returns a TimeShiftConfig with sensible defaults.
+func DefaultTimeShiftConfig() TimeShiftConfig {
+	return TimeShiftConfig{
+		Enabled:      false,
+		DepthMinutes: 10,
+	}
+}
+
+// Depth returns the rewind depth, or zero when time-shift is disabled.
+func (t TimeShiftConfig) Depth() time.Duration {
+	if !t.Enabled {
+		return 0
+	}
+	return time.Duration(t.DepthMinutes) * time.Minute
+}
+
+// ResumeConfig holds settings for picking a station back up after the
+// network drops or the computer wakes from sleep.
+type ResumeConfig struct {
+	Enabled        bool `yaml:"enabled"`         // Wait for the network and resume the same station (default: true)
+	TimeoutMinutes int  `yaml:"timeout_minutes"` // Give up after this long, range [1, 120] (default: 10)
+}
+
+// DefaultResumeConfig returns a ResumeConfig with sensible defaults.
+func DefaultResumeConfig() ResumeConfig {
+	return ResumeConfig{
+		Enabled:        true,
+		TimeoutMinutes: 10,
+	}
+}
+
+// Timeout returns how long to wait for the network, or zero when resuming
+// is disabled.
+func (r ResumeConfig) Timeout() time.Duration {
+	if !r.Enabled {
+		return 0
+	}
+	return time.Duration(r.TimeoutMinutes) * time.Minute
+}
+
+// DataSaverConfig holds settings for metered connections. Data usage is
+// always counted; these settings decide what TERA does about it.
+type DataSaverConfig struct {
+	Enabled      bool `yaml:"enabled"`        // Play low-bitrate variants and enforce the cap (default: false)
+	MaxBitrate   int  `yaml:"max_bitrate"`    // Preferred highest bitrate in kbps, range [16, 320] (default: 64)
+	MonthlyCapMB int  `yaml:"monthly_cap_mb"` // Monthly data cap in MB; 0 means no cap (default: 0)
+	WarnPercent  int  `yaml:"warn_percent"`   // Warn at this share of the cap, range [50, 100] (default: 90)
+	StopAtCap    bool `yaml:"stop_at_cap"`    // Stop playback once the cap is reached (default: false)
+}
+
+// DefaultDataSaverConfig returns a DataSaverConfig with sensible defaults.
+func DefaultDataSaverConfig() DataSaverConfig {
+	return DataSaverConfig{
+		Enabled:      false,
+		MaxBitrate:   64,
+		MonthlyCapMB: 0,
+		WarnPercent:  90,
+		StopAtCap:    false,
+	}
+}
+
+// CapBytes returns the monthly cap in bytes, or zero when there is none.
+func (d DataSaverConfig) CapBytes() int64 {
+	if !d.Enabled {
+		return 0
+	}
+	return int64(d.MonthlyCapMB) << 20
+}
+
+// StorageConfig selects where play statistics, ratings, tags and favorites
+// are kept.
+type StorageConfig struct {
+	Backend string `yaml:"backend"` // "json" or "sqlite" (default: "json")
+}
+
+// Storage backends.
+const (
+	StorageBackendJSON   = "json"
+	StorageBackendSQLite = "sqlite"
+)
+
+// DefaultStorageConfig returns a StorageConfig with sensible defaults.
+func DefaultStorageConfig() StorageConfig {
+	return StorageConfig{
+		Backend: StorageBackendJSON,
+	}
+}
+
+// SessionLogConfig holds settings for the listening session log shown in
+// the timeline.
+type SessionLogConfig struct {
+	Enabled       bool `yaml:"enabled"`        // Record a session for every play (default: true)
+	RetentionDays int  `yaml:"retention_days"` // Days of sessions kept; 0 keeps them forever, range [0, 3650] (default: 90)
+}
+
+// DefaultSessionLogConfig returns a SessionLogConfig with sensible defaults.
+func DefaultSessionLogConfig() SessionLogConfig {
+	return SessionLogConfig{
+		Enabled:       true,
+		RetentionDays: 90,
+	}
+}
+
+// AutoBackupConfig schedules zip backups of the data files, taken with the
+// categories last chosen in Sync & Backup.
+type AutoBackupConfig struct {
+	Enabled    bool   `yaml:"enabled"`     // Take automatic backups (default: false)
+	OnExit     bool   `yaml:"on_exit"`     // Back up when TERA quits (default: true)
+	Interval   string `yaml:"interval"`    // "off", "daily" or "weekly": back up while TERA runs once the last backup is this old (default: "daily")
+	Dir        string `yaml:"dir"`         // Directory of the backups; empty means "backups" in the config directory
+	Encrypt    bool   `yaml:"encrypt"`     // Encrypt with the passphrase remembered in the keychain (default: false)
+	KeepLast   int    `yaml:"keep_last"`   // Newest backups always kept, range [1, 100] (default: 5)
+	KeepDaily  int    `yaml:"keep_daily"`  // Days whose last backup is kept, range [0, 365] (default: 7)
+	KeepWeekly int    `yaml:"keep_weekly"` // Weeks whose last backup is kept, range [0, 104] (default: 4)
+}
+
+// Automatic backup intervals.
+const (
+	AutoBackupOff    = "off"
+	AutoBackupDaily  = "daily"
+	AutoBackupWeekly = "weekly"
+)
+
+// DefaultAutoBackupConfig returns an AutoBackupConfig with sensible defaults.
+func DefaultAutoBackupConfig() AutoBackupConfig {
+	return AutoBackupConfig{
+		Enabled:    false,
+		OnExit:     true,
+		Interval:   AutoBackupDaily,
+		KeepLast:   5,
+		KeepDaily:  7,
+		KeepWeekly: 4,
+	}
+}
+
+// IntervalDuration returns how old the last backup may get before TERA takes
+// a new one while running, or zero when it only backs up on exit.
+func (a AutoBackupConfig) IntervalDuration() time.Duration {
+	switch a.Interval {
+	case AutoBackupDaily:
+		return 24 * time.Hour
+	case AutoBackupWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// AdFilterConfig holds rules that silence ads and station announcements by
+// matching the stream's track title.
+type AdFilterConfig struct {
+	Enabled              bool           `yaml:"enabled"`                 // Apply the rules below (default: false)
+	MuteOnConnectSeconds int            `yaml:"mute_on_connect_seconds"` // Silence the start of every stream, range [0, 120] (default: 0)
+	LowerVolume          int            `yaml:"lower_volume"`            // Percent of normal volume for "lower" rules, range [0, 100] (default: 20)
+	Rules                []AdFilterRule `yaml:"rules"`
+}
+
+// AdFilterRule acts on every track title its pattern matches.
+type AdFilterRule struct {
+	Pattern string `yaml:"pattern"`           // Regular expression, matched case-insensitively
+	Action  string `yaml:"action"`            // "mute", "lower" or "skip" (default: "mute")
+	Station string `yaml:"station,omitempty"` // Station UUID; empty applies to every station
+}
+
+// Ad filter actions. Mute and lower last until the title changes; skip moves
+// on to the next station when the current screen has one (a favorites list
+// or a shuffle session) and mutes otherwise.
+const (
+	AdActionMute  = "mute"
+	AdActionLower = "lower"
+	AdActionSkip  = "skip"
+)
+
+// DefaultAdFilterConfig returns an AdFilterConfig with sensible defaults.
+func DefaultAdFilterConfig() AdFilterConfig {
+	return AdFilterConfig{
+		Enabled:              false,
+		MuteOnConnectSeconds: 0,
+		LowerVolume:          20,
+		Rules: []AdFilterRule{
+			{Pattern: `^(ads?|ad break|adverts?|advertisements?|commercials?|commercial break)$`, Action: AdActionMute},
+		},
+	}
+}
+
+// Compile returns the rule's case-insensitive regular expression.
+func (r AdFilterRule) Compile() (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + r.Pattern)
+}
+
+// MetadataConfig holds custom patterns for splitting stream titles into
+// artist, title and album. They are tried before the built-in patterns.
+type MetadataConfig struct {
+	Patterns []MetadataPattern `yaml:"patterns"`
+}
+
+// MetadataPattern names the parts of a title with the regexp groups
+// (?P<artist>...), (?P<title>...) and (?P<album>...).
+type MetadataPattern struct {
+	Pattern string `yaml:"pattern"`           // Regular expression, matched case-insensitively; needs a title group
+	Station string `yaml:"station,omitempty"` // Station UUID; empty applies to every station
+}
+
+// DefaultMetadataConfig returns a MetadataConfig with sensible defaults.
+func DefaultMetadataConfig() MetadataConfig {
+	return MetadataConfig{Patterns: []MetadataPattern{}}
+}
+
+// Compile returns the pattern's case-insensitive regular expression. It
+// fails when the expression has no title group.
+func (m MetadataPattern) Compile() (*regexp.Regexp, error) {
+	re, err := regexp.Compile("(?i)" + m.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	if re.SubexpIndex("title") < 0 {
+		return nil, errors.New("pattern needs a (?P<title>...) group")
+	}
+	return re, nil
+}
+
 // PlayHistoryConfig holds Recently Played display settings for the main menu
 type PlayHistoryConfig struct {
 	Enabled        bool `yaml:"enabled"`          // Show Recently Played section in main menu (default: true)
@@ -182,6 +493,17 @@ func DefaultConfig() Config {
 		},
 		PlayHistory: DefaultPlayHistoryConfig(),
 		PlayOptions: DefaultPlayOptionsConfig(),
+		Remote:      DefaultRemoteConfig(),
+		Relay:       DefaultRelayConfig(),
+		Visualizer:  DefaultVisualizerConfig(),
+		TimeShift:   DefaultTimeShiftConfig(),
+		AdFilter:    DefaultAdFilterConfig(),
+		Metadata:    DefaultMetadataConfig(),
+		Resume:      DefaultResumeConfig(),
+		DataSaver:   DefaultDataSaverConfig(),
+		Storage:     DefaultStorageConfig(),
+		SessionLog:  DefaultSessionLogConfig(),
+		AutoBackup:  DefaultAutoBackupConfig(),
 	}
 }
 
@@ -224,6 +546,61 @@ func (c *Config) Validate() error {
 		errs = append(errs, fmt.Sprintf("play_options: %v", err))
 	}
 
+	// Validate Remote config
+	if err := c.Remote.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("remote: %v", err))
+	}
+
+	// Validate Relay config
+	if err := c.Relay.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("relay: %v", err))
+	}
+
+	// Validate Visualizer config
+	if err := c.Visualizer.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("visualizer: %v", err))
+	}
+
+	// Validate TimeShift config
+	if err := c.TimeShift.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("time_shift: %v", err))
+	}
+
+	// Validate AdFilter config
+	if err := c.AdFilter.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("ad_filter: %v", err))
+	}
+
+	// Validate Metadata config
+	if err := c.Metadata.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("metadata: %v", err))
+	}
+
+	// Validate Resume config
+	if err := c.Resume.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("resume: %v", err))
+	}
+
+	// Validate DataSaver config
+	if err := c.DataSaver.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("data_saver: %v", err))
+	}
+
+	// Validate Storage config
+	if err := c.Storage.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("storage: %v", err))
+	}
+
+	// Validate SessionLog config
+	if err := c.SessionLog.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("session_log: %v", err))
+	}
+
+	// Validate AutoBackup config
+	if err := c.AutoBackup.Validate(); err != nil {
+		errs = append(errs, fmt.Sprintf("auto_backup: %v", err))
+	}
+
 	if len(errs) > 0 {
 		return errors.New(strings.Join(errs, "; "))
 	}
@@ -557,3 +934,295 @@ func (p *PlayOptionsConfig) Validate() error {
 	}
 	return nil
 }
+
+// validateListenAddr resets *addr to def when it is not a usable host:port
+// pair. An empty value is silently replaced.
+func validateListenAddr(addr *string, def string, errs *[]string) {
+	if *addr == "" {
+		*addr = def
+	} else if _, port, err := net.SplitHostPort(*addr); err != nil {
+		*addr = def
+		*errs = append(*errs, fmt.Sprintf("listen must be host:port, set to %q", def))
+	} else if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		*addr = def
+		*errs = append(*errs, fmt.Sprintf("listen port must be 1-65535, set to %q", def))
+	}
+}
+
+// Validate validates RemoteConfig, resetting Listen to the default when it is
+// not a valid host:port pair.
+func (r *RemoteConfig) Validate() error {
+	var errs []string
+
+	validateListenAddr(&r.Listen, DefaultRemoteListen, &errs)
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Validate validates RelayConfig, resetting Listen to the default when it is
+// not a valid host:port pair.
+func (r *RelayConfig) Validate() error {
+	var errs []string
+
+	validateListenAddr(&r.Listen, DefaultRelayListen, &errs)
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Validate validates VisualizerConfig, clamping FPS and Bands to their
+// ranges and normalising Style to a known value.
+func (v *VisualizerConfig) Validate() error {
+	var errs []string
+
+	if v.Style != VisualizerStyleVU && v.Style != VisualizerStyleSpectrum {
+		v.Style = VisualizerStyleVU
+		errs = append(errs, fmt.Sprintf("style must be %q or %q, set to %q", VisualizerStyleVU, VisualizerStyleSpectrum, VisualizerStyleVU))
+	}
+
+	if v.FPS < 1 {
+		v.FPS = 1
+		errs = append(errs, "fps must be >= 1, set to 1")
+	}
+	if v.FPS > 30 {
+		v.FPS = 30
+		errs = append(errs, "fps must be <= 30, set to 30")
+	}
+
+	if v.Bands < 4 {
+		v.Bands = 4
+		errs = append(errs, "bands must be >= 4, set to 4")
+	}
+	if v.Bands > 24 {
+		v.Bands = 24
+		errs = append(errs, "bands must be <= 24, set to 24")
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Validate validates TimeShiftConfig, clamping DepthMinutes to [1, 120].
+func (t *TimeShiftConfig) Validate() error {
+	var errs []string
+
+	if t.DepthMinutes < 1 {
+		t.DepthMinutes = 1
+		errs = append(errs, "depth_minutes must be >= 1, set to 1")
+	}
+	if t.DepthMinutes > 120 {
+		t.DepthMinutes = 120
+		errs = append(errs, "depth_minutes must be <= 120, set to 120")
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Validate validates ResumeConfig, clamping TimeoutMinutes to [1, 120].
+func (r *ResumeConfig) Validate() error {
+	var errs []string
+
+	if r.TimeoutMinutes < 1 {
+		r.TimeoutMinutes = 1
+		errs = append(errs, "timeout_minutes must be >= 1, set to 1")
+	}
+	if r.TimeoutMinutes > 120 {
+		r.TimeoutMinutes = 120
+		errs = append(errs, "timeout_minutes must be <= 120, set to 120")
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Validate validates DataSaverConfig, clamping its ranges.
+func (d *DataSaverConfig) Validate() error {
+	var errs []string
+
+	if d.MaxBitrate < 16 {
+		d.MaxBitrate = 16
+		errs = append(errs, "max_bitrate must be >= 16, set to 16")
+	}
+	if d.MaxBitrate > 320 {
+		d.MaxBitrate = 320
+		errs = append(errs, "max_bitrate must be <= 320, set to 320")
+	}
+	if d.MonthlyCapMB < 0 {
+		d.MonthlyCapMB = 0
+		errs = append(errs, "monthly_cap_mb must be >= 0, set to 0")
+	}
+	if d.WarnPercent < 50 {
+		d.WarnPercent = 50
+		errs = append(errs, "warn_percent must be >= 50, set to 50")
+	}
+	if d.WarnPercent > 100 {
+		d.WarnPercent = 100
+		errs = append(errs, "warn_percent must be <= 100, set to 100")
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Validate validates StorageConfig. An empty backend means JSON.
+func (s *StorageConfig) Validate() error {
+	switch s.Backend {
+	case StorageBackendJSON, StorageBackendSQLite:
+		return nil
+	case "":
+		s.Backend = StorageBackendJSON
+		return nil
+	}
+	err := fmt.Errorf("backend must be %q or %q, set to %q", StorageBackendJSON, StorageBackendSQLite, StorageBackendJSON)
+	s.Backend = StorageBackendJSON
+	return err
+}
+
+// Validate validates SessionLogConfig, clamping its ranges.
+func (s *SessionLogConfig) Validate() error {
+	var errs []string
+
+	if s.RetentionDays < 0 {
+		s.RetentionDays = 0
+		errs = append(errs, "retention_days must be >= 0, set to 0")
+	}
+	if s.RetentionDays > 3650 {
+		s.RetentionDays = 3650
+		errs = append(errs, "retention_days must be <= 3650, set to 3650")
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Validate validates AutoBackupConfig, clamping its ranges. An unknown
+// interval becomes "daily".
+func (a *AutoBackupConfig) Validate() error {
+	var errs []string
+
+	switch a.Interval {
+	case AutoBackupOff, AutoBackupDaily, AutoBackupWeekly:
+	case "":
+		a.Interval = AutoBackupOff
+	default:
+		errs = append(errs, fmt.Sprintf("interval must be %q, %q or %q, set to %q", AutoBackupOff, AutoBackupDaily, AutoBackupWeekly, AutoBackupDaily))
+		a.Interval = AutoBackupDaily
+	}
+	if a.KeepLast < 1 {
+		a.KeepLast = 1
+		errs = append(errs, "keep_last must be >= 1, set to 1")
+	}
+	if a.KeepLast > 100 {
+		a.KeepLast = 100
+		errs = append(errs, "keep_last must be <= 100, set to 100")
+	}
+	if a.KeepDaily < 0 {
+		a.KeepDaily = 0
+		errs = append(errs, "keep_daily must be >= 0, set to 0")
+	}
+	if a.KeepDaily > 365 {
+		a.KeepDaily = 365
+		errs = append(errs, "keep_daily must be <= 365, set to 365")
+	}
+	if a.KeepWeekly < 0 {
+		a.KeepWeekly = 0
+		errs = append(errs, "keep_weekly must be >= 0, set to 0")
+	}
+	if a.KeepWeekly > 104 {
+		a.KeepWeekly = 104
+		errs = append(errs, "keep_weekly must be <= 104, set to 104")
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Validate validates AdFilterConfig: ranges are clamped, rules with an empty
+// or invalid pattern are dropped and unknown actions become "mute".
+func (a *AdFilterConfig) Validate() error {
+	var errs []string
+
+	if a.MuteOnConnectSeconds < 0 {
+		a.MuteOnConnectSeconds = 0
+		errs = append(errs, "mute_on_connect_seconds must be >= 0, set to 0")
+	}
+	if a.MuteOnConnectSeconds > 120 {
+		a.MuteOnConnectSeconds = 120
+		errs = append(errs, "mute_on_connect_seconds must be <= 120, set to 120")
+	}
+
+	if a.LowerVolume < 0 {
+		a.LowerVolume = 0
+		errs = append(errs, "lower_volume must be >= 0, set to 0")
+	}
+	if a.LowerVolume > 100 {
+		a.LowerVolume = 100
+		errs = append(errs, "lower_volume must be <= 100, set to 100")
+	}
+
+	rules := a.Rules[:0]
+	for i, r := range a.Rules {
+		if strings.TrimSpace(r.Pattern) == "" {
+			errs = append(errs, fmt.Sprintf("rules[%d]: empty pattern, rule removed", i))
+			continue
+		}
+		if _, err := r.Compile(); err != nil {
+			errs = append(errs, fmt.Sprintf("rules[%d]: invalid pattern %q, rule removed", i, r.Pattern))
+			continue
+		}
+		switch r.Action {
+		case AdActionMute, AdActionLower, AdActionSkip:
+		case "":
+			r.Action = AdActionMute
+		default:
+			errs = append(errs, fmt.Sprintf("rules[%d]: unknown action %q, set to %q", i, r.Action, AdActionMute))
+			r.Action = AdActionMute
+		}
+		rules = append(rules, r)
+	}
+	a.Rules = rules
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Validate validates MetadataConfig, dropping patterns that do not compile
+// or have no title group.
+func (m *MetadataConfig) Validate() error {
+	var errs []string
+
+	patterns := m.Patterns[:0]
+	for i, p := range m.Patterns {
+		if _, err := p.Compile(); err != nil {
+			errs = append(errs, fmt.Sprintf("patterns[%d]: %v, pattern removed", i, err))
+			continue
+		}
+		patterns = append(patterns, p)
+	}
+	m.Patterns = patterns
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
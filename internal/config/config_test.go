@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -868,3 +869,267 @@ func TestPlayOptionsConfig_RoundTrip(t *testing.T) {
 		t.Errorf("LastUsedVolume: got %d, want %d", po.LastUsedVolume, want.LastUsedVolume)
 	}
 }
+
+func TestRemoteConfigDefaults(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if cfg.Remote.Enabled {
+		t.Error("expected Remote.Enabled to be false")
+	}
+	if cfg.Remote.Listen != DefaultRemoteListen {
+		t.Errorf("expected Listen %q, got %q", DefaultRemoteListen, cfg.Remote.Listen)
+	}
+	if cfg.Remote.Token != "" {
+		t.Errorf("expected empty token, got %q", cfg.Remote.Token)
+	}
+}
+
+func TestRemoteConfigValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		listen   string
+		want     string
+		hasError bool
+	}{
+		{"default", DefaultRemoteListen, DefaultRemoteListen, false},
+		{"lan bind", "0.0.0.0:9000", "0.0.0.0:9000", false},
+		{"empty reset silently", "", DefaultRemoteListen, false},
+		{"missing port", "localhost", DefaultRemoteListen, true},
+		{"port out of range", "127.0.0.1:70000", DefaultRemoteListen, true},
+		{"non-numeric port", "127.0.0.1:http", DefaultRemoteListen, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := RemoteConfig{Listen: tt.listen}
+			err := r.Validate()
+			if tt.hasError && err == nil {
+				t.Error("expected validation error, got nil")
+			}
+			if !tt.hasError && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if r.Listen != tt.want {
+				t.Errorf("Listen: got %q, want %q", r.Listen, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelayConfigDefaultsAndValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Relay.Enabled {
+		t.Error("expected Relay.Enabled to be false")
+	}
+	if cfg.Relay.Listen != DefaultRelayListen {
+		t.Errorf("expected Listen %q, got %q", DefaultRelayListen, cfg.Relay.Listen)
+	}
+
+	r := RelayConfig{Listen: "0.0.0.0:8000"}
+	if err := r.Validate(); err != nil || r.Listen != "0.0.0.0:8000" {
+		t.Errorf("valid LAN bind rejected: %v (%q)", err, r.Listen)
+	}
+	r = RelayConfig{Listen: "nonsense"}
+	if err := r.Validate(); err == nil || r.Listen != DefaultRelayListen {
+		t.Errorf("expected reset to default with error, got %v (%q)", err, r.Listen)
+	}
+}
+
+func TestVisualizerConfigDefaultsAndValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Visualizer.Enabled || cfg.Visualizer.Active() {
+		t.Error("expected visualizer to be off by default")
+	}
+	if cfg.Visualizer.Style != VisualizerStyleVU {
+		t.Errorf("expected style %q, got %q", VisualizerStyleVU, cfg.Visualizer.Style)
+	}
+
+	v := VisualizerConfig{Enabled: true, Style: "wave", FPS: 120, Bands: 1}
+	if err := v.Validate(); err == nil {
+		t.Error("expected validation errors")
+	}
+	if v.Style != VisualizerStyleVU || v.FPS != 30 || v.Bands != 4 {
+		t.Errorf("expected clamped values, got %+v", v)
+	}
+
+	v.LowCPU = true
+	if v.Active() {
+		t.Error("low-CPU mode must turn the visualizer off")
+	}
+}
+
+func TestTimeShiftConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.TimeShift.Enabled || cfg.TimeShift.Depth() != 0 {
+		t.Error("expected time-shift to be off by default")
+	}
+
+	ts := TimeShiftConfig{Enabled: true, DepthMinutes: 500}
+	if err := ts.Validate(); err == nil || ts.DepthMinutes != 120 {
+		t.Errorf("expected clamp to 120 with error, got %d (%v)", ts.DepthMinutes, err)
+	}
+	if ts.Depth() != 120*time.Minute {
+		t.Errorf("expected 2h depth, got %v", ts.Depth())
+	}
+}
+
+func TestResumeConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Resume.Timeout() != 10*time.Minute {
+		t.Errorf("expected resume on with a 10 minute timeout by default, got %v", cfg.Resume.Timeout())
+	}
+
+	rc := ResumeConfig{Enabled: true, TimeoutMinutes: 0}
+	if err := rc.Validate(); err == nil || rc.TimeoutMinutes != 1 {
+		t.Errorf("expected clamp to 1 with error, got %d (%v)", rc.TimeoutMinutes, err)
+	}
+	rc.Enabled = false
+	if rc.Timeout() != 0 {
+		t.Errorf("expected no timeout when disabled, got %v", rc.Timeout())
+	}
+}
+
+func TestDataSaverConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.DataSaver.Enabled || cfg.DataSaver.CapBytes() != 0 {
+		t.Error("expected data saver to be off by default")
+	}
+
+	ds := DataSaverConfig{Enabled: true, MaxBitrate: 8, MonthlyCapMB: 2048, WarnPercent: 120}
+	if err := ds.Validate(); err == nil {
+		t.Error("expected clamping errors")
+	}
+	if ds.MaxBitrate != 16 || ds.WarnPercent != 100 {
+		t.Errorf("expected clamped values, got %+v", ds)
+	}
+	if ds.CapBytes() != 2<<30 {
+		t.Errorf("expected a 2 GB cap, got %d", ds.CapBytes())
+	}
+}
+
+func TestStorageConfig(t *testing.T) {
+	if got := DefaultConfig().Storage.Backend; got != StorageBackendJSON {
+		t.Errorf("expected the JSON backend by default, got %q", got)
+	}
+
+	sc := StorageConfig{Backend: "postgres"}
+	if err := sc.Validate(); err == nil || sc.Backend != StorageBackendJSON {
+		t.Errorf("expected an unknown backend to fall back to JSON, got %q (%v)", sc.Backend, err)
+	}
+	sc = StorageConfig{}
+	if err := sc.Validate(); err != nil || sc.Backend != StorageBackendJSON {
+		t.Errorf("expected an empty backend to mean JSON, got %q (%v)", sc.Backend, err)
+	}
+}
+
+func TestSessionLogConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	if !cfg.SessionLog.Enabled || cfg.SessionLog.RetentionDays != 90 {
+		t.Errorf("unexpected session log defaults: %+v", cfg.SessionLog)
+	}
+
+	sc := SessionLogConfig{RetentionDays: -1}
+	if err := sc.Validate(); err == nil || sc.RetentionDays != 0 {
+		t.Errorf("expected retention_days to be clamped to 0, got %d (%v)", sc.RetentionDays, err)
+	}
+	sc = SessionLogConfig{RetentionDays: 5000}
+	if err := sc.Validate(); err == nil || sc.RetentionDays != 3650 {
+		t.Errorf("expected retention_days to be clamped to 3650, got %d (%v)", sc.RetentionDays, err)
+	}
+}
+
+func TestAdFilterConfigDefaultsAndValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.AdFilter.Enabled {
+		t.Error("expected ad filter to be off by default")
+	}
+	if len(cfg.AdFilter.Rules) == 0 {
+		t.Fatal("expected a default rule")
+	}
+	re, err := cfg.AdFilter.Rules[0].Compile()
+	if err != nil {
+		t.Fatalf("default rule does not compile: %v", err)
+	}
+	for _, title := range []string{"Advertisement", "COMMERCIAL BREAK", "ads"} {
+		if !re.MatchString(title) {
+			t.Errorf("default rule should match %q", title)
+		}
+	}
+	if re.MatchString("Adele - Hello") {
+		t.Error("default rule must not match ordinary titles")
+	}
+
+	a := AdFilterConfig{
+		MuteOnConnectSeconds: 500,
+		LowerVolume:          -5,
+		Rules: []AdFilterRule{
+			{Pattern: "jingle", Action: ""},
+			{Pattern: "  "},
+			{Pattern: "([", Action: AdActionSkip},
+			{Pattern: "promo", Action: "fade", Station: "uuid-1"},
+			{Pattern: "news", Action: AdActionLower},
+		},
+	}
+	if err := a.Validate(); err == nil {
+		t.Error("expected validation errors")
+	}
+	if a.MuteOnConnectSeconds != 120 || a.LowerVolume != 0 {
+		t.Errorf("expected clamped values, got %+v", a)
+	}
+	want := []AdFilterRule{
+		{Pattern: "jingle", Action: AdActionMute},
+		{Pattern: "promo", Action: AdActionMute, Station: "uuid-1"},
+		{Pattern: "news", Action: AdActionLower},
+	}
+	if len(a.Rules) != len(want) {
+		t.Fatalf("expected %d rules, got %+v", len(want), a.Rules)
+	}
+	for i := range want {
+		if a.Rules[i] != want[i] {
+			t.Errorf("rules[%d] = %+v, want %+v", i, a.Rules[i], want[i])
+		}
+	}
+}
+
+func TestMetadataConfigValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	if len(cfg.Metadata.Patterns) != 0 {
+		t.Error("expected no custom patterns by default")
+	}
+
+	m := MetadataConfig{Patterns: []MetadataPattern{
+		{Pattern: `^(?P<title>.+) - (?P<artist>.+)$`, Station: "uuid-1"},
+		{Pattern: `^(?P<artist>.+) - (?P<song>.+)$`},
+		{Pattern: `([`},
+	}}
+	if err := m.Validate(); err == nil {
+		t.Error("expected validation errors")
+	}
+	if len(m.Patterns) != 1 || m.Patterns[0].Station != "uuid-1" {
+		t.Errorf("expected only the valid pattern to remain, got %+v", m.Patterns)
+	}
+
+	re, err := m.Patterns[0].Compile()
+	if err != nil || !re.MatchString("SONG - ARTIST") {
+		t.Errorf("expected a case-insensitive match, got %v", err)
+	}
+}
+
+func TestAutoBackupConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.AutoBackup.Enabled || !cfg.AutoBackup.OnExit || cfg.AutoBackup.IntervalDuration() != 24*time.Hour {
+		t.Errorf("unexpected auto backup defaults: %+v", cfg.AutoBackup)
+	}
+
+	ac := AutoBackupConfig{Interval: "hourly", KeepLast: 0, KeepDaily: -1, KeepWeekly: 500}
+	if err := ac.Validate(); err == nil {
+		t.Error("expected out-of-range settings to be reported")
+	}
+	if ac.Interval != AutoBackupDaily || ac.KeepLast != 1 || ac.KeepDaily != 0 || ac.KeepWeekly != 104 {
+		t.Errorf("unexpected clamped settings: %+v", ac)
+	}
+	ac = AutoBackupConfig{Interval: AutoBackupOff, KeepLast: 5}
+	if err := ac.Validate(); err != nil || ac.IntervalDuration() != 0 {
+		t.Errorf("expected off to back up on exit only, got %v (%v)", ac.IntervalDuration(), err)
+	}
+}
@@ -0,0 +1,42 @@
+package gist
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+const backupPassphraseUser = "backup-passphrase"
+
+// SaveBackupPassphrase remembers the passphrase of encrypted backups in the
+// OS keychain. Unlike the token there is no file fallback: a passphrase kept
+// in a plain file next to the backups would defeat the encryption.
+func SaveBackupPassphrase(passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("cannot save empty passphrase")
+	}
+	if err := keyring.Set(keychainService, backupPassphraseUser, passphrase); err != nil {
+		return fmt.Errorf("failed to save passphrase to keychain: %w", err)
+	}
+	return nil
+}
+
+// LoadBackupPassphrase returns the remembered backup passphrase, or "" if
+// there is none or the keychain is unavailable.
+func LoadBackupPassphrase() string {
+	passphrase, err := keyring.Get(keychainService, backupPassphraseUser)
+	if err != nil {
+		return ""
+	}
+	return passphrase
+}
+
+// DeleteBackupPassphrase forgets the remembered backup passphrase.
+func DeleteBackupPassphrase() error {
+	err := keyring.Delete(keychainService, backupPassphraseUser)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("failed to delete passphrase from keychain: %w", err)
+	}
+	return nil
+}
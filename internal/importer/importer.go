@@ -0,0 +1,185 @@
+// Package importer reads the favorites of other radio apps — Shortwave,
+// Goodvibes, Radiotray-NG and RadioDroid — and adds them to a TERA
+// favorites list.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/playlist"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// lookupTimeout bounds a Radio Browser UUID lookup.
+const lookupTimeout = 10 * time.Second
+
+// Record is one station read from another app.
+type Record struct {
+	UUID string // Radio Browser station UUID, when the app keeps it
+	Name string
+	URL  string
+}
+
+// Source describes an app whose favorites can be imported.
+type Source struct {
+	Name  string // Value of --from
+	Title string // Display name
+	// DefaultPaths returns where the app keeps its data, most likely first.
+	// It is nil when the data has to be exported from the app first.
+	DefaultPaths func() []string
+	// Read returns the stations stored in the file at path.
+	Read func(path string) ([]Record, error)
+}
+
+// Sources lists the supported apps.
+var Sources = []Source{shortwave, goodvibes, radiotray, radiodroid}
+
+// Lookup returns the source called name.
+func Lookup(name string) (Source, bool) {
+	for _, s := range Sources {
+		if strings.EqualFold(s.Name, name) {
+			return s, true
+		}
+	}
+	return Source{}, false
+}
+
+// Names returns the names of the supported apps.
+func Names() []string {
+	names := make([]string, len(Sources))
+	for i, s := range Sources {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// FindPath returns the first of the source's default paths that exists.
+func (s Source) FindPath() (string, error) {
+	if s.DefaultPaths == nil {
+		return "", fmt.Errorf("export your %s favorites and pass the file's path", s.Title)
+	}
+	paths := s.DefaultPaths()
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	if len(paths) == 0 {
+		return "", fmt.Errorf("no %s data found; pass its path", s.Title)
+	}
+	return "", fmt.Errorf("no %s data found at %s; pass its path", s.Title, paths[0])
+}
+
+// StationFinder looks stations up in Radio Browser. *api.Client implements
+// it.
+type StationFinder interface {
+	playlist.StationFinder
+	GetByUUID(ctx context.Context, stationUUID string) (*api.Station, error)
+}
+
+// Result is the outcome of an import.
+type Result struct {
+	playlist.ResolveResult
+	ByUUID     int // Matched a Radio Browser station by UUID
+	Skipped    int // Not found by UUID and has no stream URL to keep
+	List       string
+	Added      int
+	Duplicates int // Already in the list
+}
+
+// Resolve turns records into stations. Records with a Radio Browser UUID
+// are fetched by it; the others, and those whose station was removed from
+// Radio Browser, are matched by stream URL and then name as for playlists.
+// A nil finder keeps every record with a URL as a custom station; records
+// with only a UUID are then skipped.
+func Resolve(ctx context.Context, finder StationFinder, records []Record) Result {
+	var r Result
+	for _, rec := range records {
+		if finder != nil && r.LookupErr == nil && rec.UUID != "" {
+			station, err := getByUUID(ctx, finder, rec.UUID)
+			if err == nil {
+				r.ByUUID++
+				r.Stations = append(r.Stations, *station)
+				continue
+			}
+			if !errors.Is(err, api.ErrStationNotFound) {
+				r.LookupErr = err
+			}
+		}
+		if rec.URL == "" {
+			r.Skipped++
+			continue
+		}
+
+		var pf playlist.StationFinder
+		if finder != nil && r.LookupErr == nil {
+			pf = finder
+		}
+		one := playlist.Resolve(ctx, pf, []playlist.Entry{{Name: rec.Name, URL: rec.URL}})
+		r.Stations = append(r.Stations, one.Stations...)
+		r.ByURL += one.ByURL
+		r.ByName += one.ByName
+		r.Custom += one.Custom
+		if one.LookupErr != nil {
+			r.LookupErr = one.LookupErr
+		}
+	}
+	return r
+}
+
+func getByUUID(ctx context.Context, finder StationFinder, uuid string) (*api.Station, error) {
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+	return finder.GetByUUID(ctx, uuid)
+}
+
+// Import reads src's favorites from path, or from the app's default
+// location when path is empty, and adds them to the favorites list name,
+// creating it if needed. Stations already in the list are skipped.
+func Import(ctx context.Context, store *storage.Storage, finder StationFinder, src Source, path, name string) (Result, error) {
+	if path == "" {
+		var err error
+		if path, err = src.FindPath(); err != nil {
+			return Result{}, err
+		}
+	}
+	records, err := src.Read(path)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read %s favorites from %s: %w", src.Title, path, err)
+	}
+	if len(records) == 0 {
+		return Result{}, fmt.Errorf("no stations found in %s", path)
+	}
+
+	r := Resolve(ctx, finder, records)
+	r.List = name
+	r.Added, err = store.AddStations(ctx, name, r.Stations)
+	if err != nil {
+		return r, err
+	}
+	r.Duplicates = len(r.Stations) - r.Added
+	return r, nil
+}
+
+// dataHome returns $XDG_DATA_HOME, defaulting to ~/.local/share.
+func dataHome(home string) string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	return filepath.Join(home, ".local", "share")
+}
+
+// configHome returns $XDG_CONFIG_HOME, defaulting to ~/.config.
+func configHome(home string) string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	return filepath.Join(home, ".config")
+}
@@ -0,0 +1,203 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+func writeFile(t *testing.T, dir, name, data string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadGoodvibes(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "stations.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<Stations>
+  <Station>
+    <uri>http://jazz.example/stream</uri>
+    <name>Jazz FM</name>
+  </Station>
+  <Station>
+    <uri>http://nameless.example</uri>
+  </Station>
+</Stations>
+`)
+	records, err := readGoodvibes(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Record{{Name: "Jazz FM", URL: "http://jazz.example/stream"}, {URL: "http://nameless.example"}}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("got %+v, want %+v", records, want)
+	}
+}
+
+func TestReadRadiotray(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "bookmarks.json", `[
+  {"group": "root", "image": "", "stations": [{"name": "Jazz FM", "url": "http://jazz.example", "image": ""}]},
+  {"group": "News", "stations": [{"name": "BBC", "url": "http://bbc.example", "notifications": true}, {"name": "Broken", "url": ""}]}
+]`)
+	records, err := readRadiotray(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Record{{Name: "Jazz FM", URL: "http://jazz.example"}, {Name: "BBC", URL: "http://bbc.example"}}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("got %+v, want %+v", records, want)
+	}
+}
+
+func TestReadRadiodroid(t *testing.T) {
+	dir := t.TempDir()
+	want := []Record{{UUID: "uuid-jazz", Name: "Jazz FM", URL: "http://jazz.example"}, {Name: "Pirate", URL: "http://pirate.example"}}
+
+	m3u := writeFile(t, dir, "radiodroid.m3u", "#EXTM3U\n#RADIOBROWSERUUID:uuid-jazz\n#EXTINF:-1,Jazz FM\nhttp://jazz.example\n#EXTINF:-1,Pirate\nhttp://pirate.example\n")
+	records, err := readRadiodroid(m3u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("m3u: got %+v, want %+v", records, want)
+	}
+
+	js := writeFile(t, dir, "favourites.json", `[{"stationuuid":"uuid-jazz","name":"Jazz FM","url":"http://jazz.example"},{"name":"Pirate","url":"http://old.example","url_resolved":"http://pirate.example"}]`)
+	if records, err = readRadiodroid(js); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("json: got %+v, want %+v", records, want)
+	}
+}
+
+func TestReadShortwave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Shortwave.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE library (id INTEGER PRIMARY KEY NOT NULL, uuid TEXT NOT NULL, is_local BOOLEAN NOT NULL DEFAULT 0, data TEXT)`,
+		`INSERT INTO library (uuid, is_local, data) VALUES ('uuid-jazz', 0, NULL)`,
+		`INSERT INTO library (uuid, is_local, data) VALUES ('local-1', 1, '{"name":"Pirate","url":"http://pirate.example"}')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = db.Close()
+
+	records, err := readShortwave(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Record{{UUID: "uuid-jazz"}, {UUID: "local-1", Name: "Pirate", URL: "http://pirate.example"}}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("got %+v, want %+v", records, want)
+	}
+
+	if _, err := readShortwave(writeFile(t, t.TempDir(), "other.db", "")); err == nil {
+		t.Error("expected an error for a database without a library table")
+	}
+}
+
+// fakeFinder serves lookups from maps.
+type fakeFinder struct {
+	byUUID map[string]api.Station
+	byURL  map[string]api.Station
+	err    error
+}
+
+func (f *fakeFinder) GetByUUID(_ context.Context, uuid string) (*api.Station, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if s, ok := f.byUUID[uuid]; ok {
+		return &s, nil
+	}
+	return nil, fmt.Errorf("%w: %s", api.ErrStationNotFound, uuid)
+}
+
+func (f *fakeFinder) SearchByURL(_ context.Context, u string) ([]api.Station, error) {
+	if s, ok := f.byURL[u]; ok {
+		return []api.Station{s}, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeFinder) SearchByName(context.Context, string) ([]api.Station, error) {
+	return nil, nil
+}
+
+func TestResolve(t *testing.T) {
+	finder := &fakeFinder{
+		byUUID: map[string]api.Station{"uuid-jazz": {StationUUID: "uuid-jazz", Name: "Jazz FM"}},
+		byURL:  map[string]api.Station{"http://moved.example": {StationUUID: "uuid-moved", Name: "Moved"}},
+	}
+	records := []Record{
+		{UUID: "uuid-jazz"},
+		{UUID: "uuid-gone", Name: "Moved", URL: "http://moved.example"},
+		{UUID: "uuid-gone"},
+		{Name: "Pirate", URL: "http://pirate.example"},
+	}
+	r := Resolve(context.Background(), finder, records)
+	if r.ByUUID != 1 || r.ByURL != 1 || r.Custom != 1 || r.Skipped != 1 || r.LookupErr != nil {
+		t.Fatalf("unexpected counts: %+v", r)
+	}
+	got := []string{r.Stations[0].StationUUID, r.Stations[1].StationUUID, r.Stations[2].StationUUID}
+	want := []string{"uuid-jazz", "uuid-moved", api.CustomStationUUID("http://pirate.example")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestResolve_LookupError(t *testing.T) {
+	finder := &fakeFinder{err: errors.New("offline")}
+	r := Resolve(context.Background(), finder, []Record{{UUID: "a", URL: "http://a.example"}, {UUID: "b"}})
+	if r.LookupErr == nil || r.Custom != 1 || r.Skipped != 1 {
+		t.Errorf("unexpected result: %+v", r)
+	}
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "radiotray-ng"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "radiotray-ng"), "bookmarks.json", `[{"group":"root","stations":[{"name":"Jazz FM","url":"http://jazz.example"}]}]`)
+
+	store := storage.NewStorage(filepath.Join(dir, "favorites"))
+	ctx := context.Background()
+	src, ok := Lookup("RadioTray")
+	if !ok {
+		t.Fatal("radiotray source not found")
+	}
+	r, err := Import(ctx, store, nil, src, "", "My-favorites")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Added != 1 || r.Custom != 1 {
+		t.Errorf("unexpected import: %+v", r)
+	}
+	if r, err = Import(ctx, store, nil, src, "", "My-favorites"); err != nil || r.Added != 0 || r.Duplicates != 1 {
+		t.Errorf("unexpected second import: %+v, %v", r, err)
+	}
+
+	radiodroid, _ := Lookup("radiodroid")
+	if _, err := Import(ctx, store, nil, radiodroid, "", "My-favorites"); err == nil {
+		t.Error("expected RadioDroid to need a path")
+	}
+}
@@ -0,0 +1,272 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "modernc.org/sqlite" // pure-Go driver registered as "sqlite"
+)
+
+// maxFileSize caps how much of an app's data file is read.
+const maxFileSize = 10 << 20
+
+// homePaths returns the paths built by fn from the home directory, or none
+// when it is unknown.
+func homePaths(fn func(home string) []string) func() []string {
+	return func() []string {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		return fn(home)
+	}
+}
+
+func readFile(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxFileSize {
+		return nil, fmt.Errorf("file is larger than %d MB", maxFileSize>>20)
+	}
+	return os.ReadFile(path)
+}
+
+// shortwave reads the library of GNOME Shortwave, a SQLite database that
+// keeps the Radio Browser UUID of each station and, for stations added by
+// hand, their name and stream URL as JSON.
+var shortwave = Source{
+	Name:  "shortwave",
+	Title: "Shortwave",
+	DefaultPaths: homePaths(func(home string) []string {
+		return []string{
+			filepath.Join(dataHome(home), "Shortwave", "Shortwave.db"),
+			filepath.Join(home, ".var", "app", "de.haeckerfelix.Shortwave", "data", "Shortwave", "Shortwave.db"),
+		}
+	}),
+	Read: readShortwave,
+}
+
+func readShortwave(path string) ([]Record, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	// Read-only so an open Shortwave is not disturbed
+	db, err := sql.Open("sqlite", "file:"+filepath.ToSlash(path)+"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	columns := make(map[string]bool)
+	rows, err := db.Query(`SELECT name FROM pragma_table_info('library')`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		columns[name] = true
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	uuidColumn := "uuid"
+	if !columns[uuidColumn] {
+		uuidColumn = "stationuuid"
+	}
+	if !columns[uuidColumn] {
+		return nil, fmt.Errorf("not a Shortwave database (no library table)")
+	}
+	dataColumn := "''"
+	if columns["data"] {
+		dataColumn = "COALESCE(data, '')"
+	}
+
+	rows, err = db.Query(fmt.Sprintf(`SELECT COALESCE(%s, ''), %s FROM library ORDER BY rowid`, uuidColumn, dataColumn))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var records []Record
+	for rows.Next() {
+		var uuid, data string
+		if err := rows.Scan(&uuid, &data); err != nil {
+			return nil, err
+		}
+		rec := Record{UUID: strings.TrimSpace(uuid)}
+		if data != "" {
+			// The metadata uses Radio Browser's field names
+			var meta struct {
+				Name        string `json:"name"`
+				URL         string `json:"url"`
+				URLResolved string `json:"url_resolved"`
+			}
+			if json.Unmarshal([]byte(data), &meta) == nil {
+				rec.Name = meta.Name
+				rec.URL = firstNonEmpty(meta.URLResolved, meta.URL)
+			}
+		}
+		if rec.UUID != "" || rec.URL != "" {
+			records = append(records, rec)
+		}
+	}
+	return records, rows.Err()
+}
+
+// goodvibes reads Goodvibes' stations.xml.
+var goodvibes = Source{
+	Name:  "goodvibes",
+	Title: "Goodvibes",
+	DefaultPaths: homePaths(func(home string) []string {
+		return []string{
+			filepath.Join(dataHome(home), "goodvibes", "stations.xml"),
+			filepath.Join(home, ".var", "app", "io.gitlab.Goodvibes", "data", "goodvibes", "stations.xml"),
+		}
+	}),
+	Read: readGoodvibes,
+}
+
+func readGoodvibes(path string) ([]Record, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Stations []struct {
+			Name string `xml:"name"`
+			URI  string `xml:"uri"`
+		} `xml:"Station"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid stations file: %w", err)
+	}
+	var records []Record
+	for _, s := range doc.Stations {
+		if u := strings.TrimSpace(s.URI); u != "" {
+			records = append(records, Record{Name: strings.TrimSpace(s.Name), URL: u})
+		}
+	}
+	return records, nil
+}
+
+// radiotray reads Radiotray-NG's bookmarks.json, a list of groups of
+// stations. The groups are flattened into one list.
+var radiotray = Source{
+	Name:  "radiotray",
+	Title: "Radiotray-NG",
+	DefaultPaths: homePaths(func(home string) []string {
+		return []string{filepath.Join(configHome(home), "radiotray-ng", "bookmarks.json")}
+	}),
+	Read: readRadiotray,
+}
+
+func readRadiotray(path string) ([]Record, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var groups []struct {
+		Stations []struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"stations"`
+	}
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return nil, fmt.Errorf("invalid bookmarks file: %w", err)
+	}
+	var records []Record
+	for _, g := range groups {
+		for _, s := range g.Stations {
+			if u := strings.TrimSpace(s.URL); u != "" {
+				records = append(records, Record{Name: strings.TrimSpace(s.Name), URL: u})
+			}
+		}
+	}
+	return records, nil
+}
+
+// radiodroid reads a RadioDroid favorites export. RadioDroid runs on
+// Android, so there is no default path: export the favorites from its
+// settings, which writes an M3U playlist with a #RADIOBROWSERUUID line per
+// station, and copy the file over. A JSON list of Radio Browser stations,
+// as kept in the app's own data, is read too.
+var radiodroid = Source{
+	Name:  "radiodroid",
+	Title: "RadioDroid",
+	Read:  readRadiodroid,
+}
+
+func readRadiodroid(path string) ([]Record, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("\ufeff"))
+	if bytes.HasPrefix(data, []byte("[")) {
+		var stations []struct {
+			UUID        string `json:"stationuuid"`
+			Name        string `json:"name"`
+			URL         string `json:"url"`
+			URLResolved string `json:"url_resolved"`
+		}
+		if err := json.Unmarshal(data, &stations); err != nil {
+			return nil, fmt.Errorf("invalid favorites file: %w", err)
+		}
+		var records []Record
+		for _, s := range stations {
+			rec := Record{UUID: strings.TrimSpace(s.UUID), Name: strings.TrimSpace(s.Name), URL: strings.TrimSpace(firstNonEmpty(s.URLResolved, s.URL))}
+			if rec.UUID != "" || rec.URL != "" {
+				records = append(records, rec)
+			}
+		}
+		return records, nil
+	}
+
+	var records []Record
+	var pending Record
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#RADIOBROWSERUUID:"):
+			pending.UUID = strings.TrimSpace(strings.TrimPrefix(line, "#RADIOBROWSERUUID:"))
+		case strings.HasPrefix(line, "#EXTINF:"):
+			if _, name, ok := strings.Cut(line, ","); ok {
+				pending.Name = strings.TrimSpace(name)
+			}
+		case strings.HasPrefix(line, "#"):
+		default:
+			pending.URL = line
+			records = append(records, pending)
+			pending = Record{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
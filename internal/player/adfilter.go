@@ -0,0 +1,167 @@
+package player
+
+import (
+	"regexp"
+	"sync/atomic"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+// adPollInterval replaces the usual metadata poll interval while an ad filter
+// is installed, so ads are silenced within a couple of seconds.
+const adPollInterval = 2 * time.Second
+
+// AdAction is what an AdRule does while its pattern matches the track title.
+type AdAction int
+
+const (
+	AdActionMute  AdAction = iota // Silence until the title changes
+	AdActionLower                 // Drop to AdFilter.LowerPercent until the title changes
+	AdActionSkip                  // Silence and ask the AdSkipHandler to move on
+)
+
+// AdRule matches stream track titles on one station or on every station.
+type AdRule struct {
+	Pattern     *regexp.Regexp
+	Action      AdAction
+	StationUUID string // empty applies to every station
+}
+
+// AdFilter is the set of rules players apply to track titles.
+type AdFilter struct {
+	Rules         []AdRule
+	LowerPercent  int           // Volume for AdActionLower, as a percent of the normal volume
+	MuteOnConnect time.Duration // Silence the start of every stream (pre-roll ads)
+}
+
+// Match returns the rule for title on the given station. Station rules are
+// checked before global ones so a station can override them.
+func (f *AdFilter) Match(title, stationUUID string) (AdRule, bool) {
+	if title == "" {
+		return AdRule{}, false
+	}
+	for _, r := range f.Rules {
+		if r.StationUUID != "" && r.StationUUID == stationUUID && r.Pattern.MatchString(title) {
+			return r, true
+		}
+	}
+	for _, r := range f.Rules {
+		if r.StationUUID == "" && r.Pattern.MatchString(title) {
+			return r, true
+		}
+	}
+	return AdRule{}, false
+}
+
+var adFilter atomic.Pointer[AdFilter]
+
+// SetAdFilter installs f for players started from now on. Pass nil to turn
+// the ad filter off.
+func SetAdFilter(f *AdFilter) {
+	adFilter.Store(f)
+}
+
+// AdSkipHandler is called when a skip rule matches. Like PlaybackObserver it
+// runs with the player's lock held, so it must return quickly and must not
+// call back into the player. The player stays silent until something plays
+// another station or the title changes.
+type AdSkipHandler func(p *MPVPlayer, station *api.Station)
+
+var adSkipHandler atomic.Pointer[AdSkipHandler]
+
+// SetAdSkipHandler installs fn as the process-wide skip handler. Pass nil to
+// remove it.
+func SetAdSkipHandler(fn AdSkipHandler) {
+	if fn == nil {
+		adSkipHandler.Store(nil)
+		return
+	}
+	adSkipHandler.Store(&fn)
+}
+
+// Reasons reported by AdStatus.
+const (
+	AdReasonConnect = "connect" // Mute-on-connect window
+	AdReasonRule    = "rule"    // A mute or skip rule matched
+	AdReasonLower   = "lower"   // A lower rule matched
+)
+
+// AdStatus reports whether the ad filter is currently overriding the volume
+// and why.
+func (p *MPVPlayer) AdStatus() (reason string, active bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.adReason, p.playing && p.adVolume >= 0
+}
+
+// resetAdFilterLocked picks up the current ad filter for a new stream and
+// returns the volume mpv should start at. Caller must hold p.mu.
+func (p *MPVPlayer) resetAdFilterLocked(volume int) int {
+	p.adFilter = adFilter.Load()
+	p.adVolume, p.adReason, p.adTitle = -1, "", ""
+	p.adConnectUntil = time.Time{}
+	if p.adFilter == nil || p.adFilter.MuteOnConnect <= 0 || volume == 0 {
+		return volume
+	}
+	p.adConnectUntil = time.Now().Add(p.adFilter.MuteOnConnect)
+	p.adVolume, p.adReason = 0, AdReasonConnect
+	return 0
+}
+
+// clearAdOverrideLocked drops the ad filter's volume override after the user
+// changed the volume; the rules apply again from the next title change.
+// Caller must hold p.mu.
+func (p *MPVPlayer) clearAdOverrideLocked() {
+	p.adVolume, p.adReason = -1, ""
+	p.adConnectUntil = time.Time{}
+}
+
+// applyAdFilter re-evaluates the rules when the title changes or the
+// mute-on-connect window ends.
+func (p *MPVPlayer) applyAdFilter(title string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	f := p.adFilter
+	if f == nil || !p.playing || p.station == nil || p.conn == nil {
+		return
+	}
+	windowEnded := !p.adConnectUntil.IsZero() && !time.Now().Before(p.adConnectUntil)
+	if title == p.adTitle && !windowEnded {
+		return
+	}
+	if windowEnded {
+		p.adConnectUntil = time.Time{}
+	}
+	p.adTitle = title
+
+	volume, reason, skip := -1, "", false
+	if rule, ok := f.Match(title, p.station.StationUUID); ok {
+		switch rule.Action {
+		case AdActionLower:
+			volume, reason = p.volume*f.LowerPercent/100, AdReasonLower
+		case AdActionSkip:
+			volume, reason, skip = 0, AdReasonRule, true
+		default:
+			volume, reason = 0, AdReasonRule
+		}
+	} else if !p.adConnectUntil.IsZero() {
+		volume, reason = 0, AdReasonConnect
+	}
+
+	if volume != p.adVolume {
+		effective := volume
+		if effective < 0 {
+			effective = p.volume
+		}
+		_ = p.sendCommand([]interface{}{"set_property", "volume", float64(effective)})
+	}
+	p.adVolume, p.adReason = volume, reason
+
+	if skip {
+		if fn := adSkipHandler.Load(); fn != nil {
+			(*fn)(p, p.station)
+		}
+	}
+}
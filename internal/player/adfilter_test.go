@@ -0,0 +1,205 @@
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+// fakeMPV answers IPC commands on conn and records every volume it is sent.
+// get_property requests are answered from props.
+type fakeMPV struct {
+	mu      sync.Mutex
+	volumes []float64
+	props   map[string]interface{}
+}
+
+func (f *fakeMPV) serve(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		var req struct {
+			Command   []interface{} `json:"command"`
+			RequestID uint64        `json:"request_id"`
+		}
+		if json.Unmarshal(line, &req) != nil {
+			continue
+		}
+		if len(req.Command) == 3 && req.Command[0] == "set_property" && req.Command[1] == "volume" {
+			f.mu.Lock()
+			f.volumes = append(f.volumes, req.Command[2].(float64))
+			f.mu.Unlock()
+		}
+		resp := map[string]interface{}{"request_id": req.RequestID, "error": "success"}
+		if len(req.Command) == 2 && req.Command[0] == "get_property" {
+			f.mu.Lock()
+			if v, ok := f.props[req.Command[1].(string)]; ok {
+				resp["data"] = v
+			} else {
+				resp["error"] = "property unavailable"
+			}
+			f.mu.Unlock()
+		}
+		reply, _ := json.Marshal(resp)
+		_, _ = conn.Write(append(reply, '\n'))
+	}
+}
+
+func (f *fakeMPV) last() (float64, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.volumes) == 0 {
+		return -1, 0
+	}
+	return f.volumes[len(f.volumes)-1], len(f.volumes)
+}
+
+// newAdTestPlayer returns a player that looks like it is playing station
+// with filter installed, connected to a fake mpv.
+func newAdTestPlayer(t *testing.T, filter *AdFilter, station *api.Station) (*MPVPlayer, *fakeMPV) {
+	t.Helper()
+	client, server := net.Pipe()
+	t.Cleanup(func() { _ = client.Close(); _ = server.Close() })
+	fake := &fakeMPV{}
+	go fake.serve(server)
+
+	SetAdFilter(filter)
+	t.Cleanup(func() { SetAdFilter(nil) })
+
+	p := NewMPVPlayer()
+	p.mu.Lock()
+	p.playing = true
+	p.station = station
+	p.volume = 80
+	p.conn = client
+	p.connReader = bufio.NewReader(client)
+	p.resetAdFilterLocked(p.volume)
+	p.mu.Unlock()
+	return p, fake
+}
+
+func TestAdFilterMatch_StationRulesFirst(t *testing.T) {
+	f := &AdFilter{Rules: []AdRule{
+		{Pattern: regexp.MustCompile(`(?i)news`), Action: AdActionLower},
+		{Pattern: regexp.MustCompile(`(?i)news`), Action: AdActionSkip, StationUUID: "st-1"},
+	}}
+
+	if r, ok := f.Match("News at ten", "st-1"); !ok || r.Action != AdActionSkip {
+		t.Errorf("station rule should win on its station, got %+v %v", r, ok)
+	}
+	if r, ok := f.Match("News at ten", "st-2"); !ok || r.Action != AdActionLower {
+		t.Errorf("global rule should apply elsewhere, got %+v %v", r, ok)
+	}
+	if _, ok := f.Match("", "st-1"); ok {
+		t.Error("an empty title must never match")
+	}
+}
+
+func TestApplyAdFilter_MuteLowerAndRestore(t *testing.T) {
+	filter := &AdFilter{
+		LowerPercent: 25,
+		Rules: []AdRule{
+			{Pattern: regexp.MustCompile(`(?i)^advert`), Action: AdActionMute},
+			{Pattern: regexp.MustCompile(`(?i)traffic`), Action: AdActionLower},
+		},
+	}
+	p, fake := newAdTestPlayer(t, filter, &api.Station{StationUUID: "st-1"})
+
+	p.applyAdFilter("Advertisement")
+	if v, _ := fake.last(); v != 0 {
+		t.Errorf("expected mute, mpv volume %v", v)
+	}
+	if reason, active := p.AdStatus(); !active || reason != AdReasonRule {
+		t.Errorf("expected rule status, got %q %v", reason, active)
+	}
+	if p.GetVolume() != 80 {
+		t.Error("the ad filter must not change the user's volume")
+	}
+
+	// Same title again: nothing is re-sent.
+	_, n := fake.last()
+	p.applyAdFilter("Advertisement")
+	if _, n2 := fake.last(); n2 != n {
+		t.Error("unchanged title must not resend the volume")
+	}
+
+	p.applyAdFilter("Traffic update")
+	if v, _ := fake.last(); v != 20 {
+		t.Errorf("expected lowered volume 20, got %v", v)
+	}
+
+	p.applyAdFilter("Artist - Song")
+	if v, _ := fake.last(); v != 80 {
+		t.Errorf("expected volume restored to 80, got %v", v)
+	}
+	if _, active := p.AdStatus(); active {
+		t.Error("status should be clear after the ad")
+	}
+}
+
+func TestApplyAdFilter_UserVolumeWinsUntilTitleChanges(t *testing.T) {
+	filter := &AdFilter{Rules: []AdRule{{Pattern: regexp.MustCompile(`(?i)advert`)}}}
+	p, fake := newAdTestPlayer(t, filter, &api.Station{StationUUID: "st-1"})
+
+	p.applyAdFilter("Advert")
+	p.SetVolume(60)
+	p.applyAdFilter("Advert")
+	if v, _ := fake.last(); v != 60 {
+		t.Errorf("user volume should stick for the same title, got %v", v)
+	}
+	p.applyAdFilter("Another advert")
+	if v, _ := fake.last(); v != 0 {
+		t.Errorf("a new matching title should mute again, got %v", v)
+	}
+}
+
+func TestApplyAdFilter_SkipCallsHandler(t *testing.T) {
+	var skipped []string
+	SetAdSkipHandler(func(_ *MPVPlayer, st *api.Station) { skipped = append(skipped, st.StationUUID) })
+	defer SetAdSkipHandler(nil)
+
+	filter := &AdFilter{Rules: []AdRule{{Pattern: regexp.MustCompile(`jingle`), Action: AdActionSkip}}}
+	p, fake := newAdTestPlayer(t, filter, &api.Station{StationUUID: "st-1"})
+
+	p.applyAdFilter("station jingle")
+	p.applyAdFilter("station jingle")
+	if len(skipped) != 1 || skipped[0] != "st-1" {
+		t.Errorf("expected one skip request, got %v", skipped)
+	}
+	if v, _ := fake.last(); v != 0 {
+		t.Errorf("skip should mute while waiting, got %v", v)
+	}
+}
+
+func TestMuteOnConnect(t *testing.T) {
+	filter := &AdFilter{MuteOnConnect: time.Hour}
+	p, fake := newAdTestPlayer(t, filter, &api.Station{StationUUID: "st-1"})
+
+	if reason, active := p.AdStatus(); !active || reason != AdReasonConnect {
+		t.Fatalf("expected connect mute, got %q %v", reason, active)
+	}
+	if got := p.resetAdFilterLocked(0); got != 0 || !p.adConnectUntil.IsZero() {
+		t.Error("an already silent start needs no connect mute")
+	}
+
+	p.mu.Lock()
+	p.resetAdFilterLocked(p.volume)
+	p.adConnectUntil = time.Now().Add(-time.Second) // window over
+	p.mu.Unlock()
+	p.applyAdFilter("")
+	if v, _ := fake.last(); v != 80 {
+		t.Errorf("expected volume restored after the window, got %v", v)
+	}
+	if _, active := p.AdStatus(); active {
+		t.Error("status should be clear after the window")
+	}
+}
@@ -0,0 +1,157 @@
+package player
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// levelFilterLabel names the mpv audio filter that measures levels, so its
+// frame metadata can be read back through the af-metadata/<label> property.
+const levelFilterLabel = "teravis"
+
+// Spectrum band limits. Radio streams rarely carry anything useful outside
+// this range, and keeping the top band well below Nyquist avoids empty bars
+// on low-bitrate streams that are lowpassed by the encoder.
+const (
+	minBandHz = 60.0
+	maxBandHz = 12000.0
+)
+
+// AudioLevels is one snapshot of the signal measured by mpv. Levels are in
+// dBFS: 0 is full scale and silence is -Inf.
+type AudioLevels struct {
+	Left, Right         float64   // RMS level per channel
+	PeakLeft, PeakRight float64   // Peak level per channel
+	Bands               []float64 // RMS level per frequency band, low to high
+}
+
+// levelAnalysis holds the process-wide analysis settings; nil means off.
+var levelAnalysis atomic.Pointer[int]
+
+// SetLevelAnalysis controls whether players started from now on ask mpv to
+// measure audio levels. bands is the number of spectrum bands to measure in
+// addition to the stereo VU levels (0 for VU only); a negative value turns
+// measuring off entirely so low-CPU setups pay nothing for it. Players that
+// are already running keep the setting they started with.
+func SetLevelAnalysis(bands int) {
+	if bands < 0 {
+		levelAnalysis.Store(nil)
+		return
+	}
+	levelAnalysis.Store(&bands)
+}
+
+// levelAnalysisBands reports the current analysis setting; ok is false when
+// level analysis is off.
+func levelAnalysisBands() (bands int, ok bool) {
+	if n := levelAnalysis.Load(); n != nil {
+		return *n, true
+	}
+	return 0, false
+}
+
+// levelFilterArg returns the --af argument that installs the measuring filter.
+func levelFilterArg(bands int) string {
+	graph := levelFilterGraph(bands)
+	// mpv's %len% quoting passes the graph through verbatim, so the commas,
+	// semicolons and brackets inside it are not parsed as option syntax.
+	return fmt.Sprintf("--af=@%s:lavfi=graph=%%%d%%%s", levelFilterLabel, len(graph), graph)
+}
+
+// levelFilterGraph builds the lavfi graph used for measuring. The audible
+// signal is never altered: astats runs on a merged stream whose first two
+// channels are the original stereo pair, and the trailing pan drops the
+// extra band channels again. Frame metadata survives pan, so mpv reports the
+// statistics for every channel (1-2 = stereo, 3.. = bands).
+func levelFilterGraph(bands int) string {
+	const stats = "astats=metadata=1:reset=1"
+	if bands <= 0 {
+		return "aformat=channel_layouts=stereo," + stats
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "aformat=channel_layouts=stereo,asplit=2[tv_out][tv_mono];")
+	fmt.Fprintf(&b, "[tv_mono]pan=mono|c0=0.5*c0+0.5*c1,asplit=%d", bands)
+	for i := 0; i < bands; i++ {
+		fmt.Fprintf(&b, "[tv_b%d]", i)
+	}
+	b.WriteString(";")
+
+	centers, width := bandCenters(bands)
+	for i, hz := range centers {
+		fmt.Fprintf(&b, "[tv_b%d]bandpass=f=%.0f:width_type=o:width=%.2f[tv_f%d];", i, hz, width, i)
+	}
+
+	b.WriteString("[tv_out]")
+	for i := 0; i < bands; i++ {
+		fmt.Fprintf(&b, "[tv_f%d]", i)
+	}
+	fmt.Fprintf(&b, "amerge=inputs=%d,%s,pan=stereo|c0=c0|c1=c1", bands+1, stats)
+	return b.String()
+}
+
+// bandCenters returns log-spaced centre frequencies between minBandHz and
+// maxBandHz and the bandwidth, in octaves, that makes neighbouring bands meet.
+func bandCenters(bands int) ([]float64, float64) {
+	if bands == 1 {
+		return []float64{math.Sqrt(minBandHz * maxBandHz)}, math.Log2(maxBandHz / minBandHz)
+	}
+	ratio := math.Pow(maxBandHz/minBandHz, 1/float64(bands-1))
+	centers := make([]float64, bands)
+	for i := range centers {
+		centers[i] = minBandHz * math.Pow(ratio, float64(i))
+	}
+	return centers, math.Log2(ratio)
+}
+
+// parseAudioLevels converts the af-metadata map reported by mpv into
+// AudioLevels. Missing or unparsable values read as silence.
+func parseAudioLevels(meta map[string]interface{}, bands int) AudioLevels {
+	get := func(channel int, stat string) float64 {
+		s, _ := meta[fmt.Sprintf("lavfi.astats.%d.%s", channel, stat)].(string)
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return math.Inf(-1)
+		}
+		return v
+	}
+
+	lv := AudioLevels{
+		Left:      get(1, "RMS_level"),
+		Right:     get(2, "RMS_level"),
+		PeakLeft:  get(1, "Peak_level"),
+		PeakRight: get(2, "Peak_level"),
+	}
+	if bands > 0 {
+		lv.Bands = make([]float64, bands)
+		for i := range lv.Bands {
+			lv.Bands[i] = get(i+3, "RMS_level")
+		}
+	}
+	return lv
+}
+
+// GetAudioLevels returns the most recent levels measured by mpv. It fails
+// when the player was started without level analysis or mpv has not
+// produced any measurements yet.
+func (p *MPVPlayer) GetAudioLevels() (AudioLevels, error) {
+	p.mu.Lock()
+	bands := p.levelBands
+	p.mu.Unlock()
+	if bands < 0 {
+		return AudioLevels{}, fmt.Errorf("level analysis is off")
+	}
+
+	val, err := p.getProperty("af-metadata/" + levelFilterLabel)
+	if err != nil {
+		return AudioLevels{}, err
+	}
+	meta, ok := val.(map[string]interface{})
+	if !ok || len(meta) == 0 {
+		return AudioLevels{}, fmt.Errorf("no level data yet")
+	}
+	return parseAudioLevels(meta, bands), nil
+}
@@ -0,0 +1,80 @@
+package player
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLevelFilterGraph_KeepsStereoOutput(t *testing.T) {
+	if g := levelFilterGraph(0); !strings.HasSuffix(g, "astats=metadata=1:reset=1") {
+		t.Errorf("VU graph should end with astats, got %q", g)
+	}
+
+	g := levelFilterGraph(4)
+	for _, want := range []string{"asplit=4[tv_b0][tv_b1][tv_b2][tv_b3]", "amerge=inputs=5", "pan=stereo|c0=c0|c1=c1"} {
+		if !strings.Contains(g, want) {
+			t.Errorf("graph missing %q: %s", want, g)
+		}
+	}
+	if strings.Count(g, "bandpass=") != 4 {
+		t.Errorf("expected 4 bandpass filters: %s", g)
+	}
+}
+
+func TestLevelFilterArg_QuotesGraph(t *testing.T) {
+	arg := levelFilterArg(6)
+	graph := levelFilterGraph(6)
+	want := "--af=@teravis:lavfi=graph=%" + strconv.Itoa(len(graph)) + "%" + graph
+	if arg != want {
+		t.Errorf("got %q\nwant %q", arg, want)
+	}
+}
+
+func TestBandCenters_LogSpaced(t *testing.T) {
+	centers, width := bandCenters(8)
+	if math.Abs(centers[0]-minBandHz) > 0.01 || math.Abs(centers[7]-maxBandHz) > 0.5 {
+		t.Errorf("unexpected band range: %v", centers)
+	}
+	if width <= 0 {
+		t.Errorf("expected a positive bandwidth, got %v", width)
+	}
+}
+
+func TestParseAudioLevels(t *testing.T) {
+	meta := map[string]interface{}{
+		"lavfi.astats.1.RMS_level":  "-12.5",
+		"lavfi.astats.2.RMS_level":  "-14.0",
+		"lavfi.astats.1.Peak_level": "-3.0",
+		"lavfi.astats.2.Peak_level": "-inf",
+		"lavfi.astats.3.RMS_level":  "-30",
+	}
+	lv := parseAudioLevels(meta, 2)
+	if lv.Left != -12.5 || lv.Right != -14 || lv.PeakLeft != -3 {
+		t.Errorf("unexpected channel levels: %+v", lv)
+	}
+	if !math.IsInf(lv.PeakRight, -1) {
+		t.Errorf("expected -Inf for silent peak, got %v", lv.PeakRight)
+	}
+	if len(lv.Bands) != 2 || lv.Bands[0] != -30 || !math.IsInf(lv.Bands[1], -1) {
+		t.Errorf("unexpected bands: %v", lv.Bands)
+	}
+}
+
+func TestSetLevelAnalysis(t *testing.T) {
+	t.Cleanup(func() { SetLevelAnalysis(-1) })
+
+	SetLevelAnalysis(-1)
+	if _, ok := levelAnalysisBands(); ok {
+		t.Error("expected analysis off")
+	}
+	SetLevelAnalysis(10)
+	if n, ok := levelAnalysisBands(); !ok || n != 10 {
+		t.Errorf("expected 10 bands, got %d (%v)", n, ok)
+	}
+
+	if _, err := NewMPVPlayer().GetAudioLevels(); err == nil {
+		t.Error("a player that never started should report no levels")
+	}
+}
@@ -0,0 +1,149 @@
+package player
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// TrackInfo is a stream title split into its parts. Raw is the title exactly
+// as the station sent it; Title is always set when Raw is not blank.
+type TrackInfo struct {
+	Raw    string
+	Artist string
+	Title  string
+	Album  string
+}
+
+// String renders the track as "Artist - Title (Album)", leaving out the
+// parts that are unknown.
+func (t TrackInfo) String() string {
+	s := t.Title
+	if t.Artist != "" {
+		s = t.Artist + " - " + s
+	}
+	if t.Album != "" {
+		s += " (" + t.Album + ")"
+	}
+	if s == "" {
+		return strings.TrimSpace(t.Raw)
+	}
+	return s
+}
+
+// Query renders the track as a plain "Artist Title" search query.
+func (t TrackInfo) Query() string {
+	return strings.TrimSpace(t.Artist + " " + t.Title)
+}
+
+// nowPlayingPrefix matches the announcements some stations put in front of
+// the song, such as "NOW PLAYING: ".
+var nowPlayingPrefix = regexp.MustCompile(`(?i)^(?:now\s+playing|now\s+on\s+air|on\s+air|currently\s+playing|playing|np)\s*[:\-–—|]\s*`)
+
+// builtinTrackPatterns are tried in order after any user patterns.
+var builtinTrackPatterns = []*regexp.Regexp{
+	// Artist - Title (also en/em dash and pipe separators)
+	regexp.MustCompile(`^(?P<artist>.+?)\s+[-–—|]\s+(?P<title>.+)$`),
+	// "Title" by Artist [from Album]
+	regexp.MustCompile(`(?i)^["“](?P<title>[^"”]+)["”]\s+by\s+(?P<artist>.+?)(?:\s+from\s+(?P<album>.+))?$`),
+	// Title by Artist from Album. A bare "Title by Artist" is not split:
+	// songs such as "Stand by Me" have "by" in the title.
+	regexp.MustCompile(`(?i)^(?P<title>.+)\s+by\s+(?P<artist>.+?)\s+from\s+(?P<album>.+)$`),
+	// Artist / Title (spaces required so names like AC/DC stay intact)
+	regexp.MustCompile(`^(?P<artist>.+?)\s+/\s+(?P<title>.+)$`),
+}
+
+// MetadataPattern is a user pattern for splitting stream titles. It names
+// its parts with the groups "artist", "title" and "album".
+type MetadataPattern struct {
+	Regexp      *regexp.Regexp
+	StationUUID string // empty applies to every station
+}
+
+var metadataPatterns atomic.Pointer[[]MetadataPattern]
+
+// SetMetadataPatterns installs user patterns that are tried before the
+// built-in ones. Station patterns are tried before global ones.
+func SetMetadataPatterns(patterns []MetadataPattern) {
+	if len(patterns) == 0 {
+		metadataPatterns.Store(nil)
+		return
+	}
+	metadataPatterns.Store(&patterns)
+}
+
+// ParseTrack splits a stream title heard on the given station using the
+// installed user patterns and the built-in ones.
+func ParseTrack(raw, stationUUID string) TrackInfo {
+	var user []*regexp.Regexp
+	if p := metadataPatterns.Load(); p != nil {
+		for _, mp := range *p {
+			if mp.StationUUID != "" && mp.StationUUID == stationUUID {
+				user = append(user, mp.Regexp)
+			}
+		}
+		for _, mp := range *p {
+			if mp.StationUUID == "" {
+				user = append(user, mp.Regexp)
+			}
+		}
+	}
+	return parseTrack(raw, user)
+}
+
+// parseTrack tries the user patterns on the trimmed title, then the built-in
+// patterns once any "now playing" prefix is removed. A title nothing matches
+// is returned whole as the Title.
+func parseTrack(raw string, user []*regexp.Regexp) TrackInfo {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return TrackInfo{Raw: raw}
+	}
+	for _, re := range user {
+		if info, ok := matchTrack(re, s); ok {
+			info.Raw = raw
+			return info
+		}
+	}
+	s = strings.TrimSpace(nowPlayingPrefix.ReplaceAllString(s, ""))
+	for _, re := range builtinTrackPatterns {
+		if info, ok := matchTrack(re, s); ok {
+			info.Raw = raw
+			return info
+		}
+	}
+	return TrackInfo{Raw: raw, Title: s}
+}
+
+// matchTrack fills a TrackInfo from re's named groups. It fails unless the
+// title group matched something.
+func matchTrack(re *regexp.Regexp, s string) (TrackInfo, bool) {
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return TrackInfo{}, false
+	}
+	var info TrackInfo
+	for i, name := range re.SubexpNames() {
+		v := strings.TrimSpace(m[i])
+		switch name {
+		case "artist":
+			info.Artist = v
+		case "title":
+			info.Title = v
+		case "album":
+			info.Album = v
+		}
+	}
+	return info, info.Title != ""
+}
+
+// GetCachedTrackInfo returns the current track split into its parts, using
+// the patterns for the playing station.
+func (p *MPVPlayer) GetCachedTrackInfo() TrackInfo {
+	raw := p.GetCachedTrack()
+	var uuid string
+	if st := p.GetCurrentStation(); st != nil {
+		uuid = st.StationUUID
+	}
+	return ParseTrack(raw, uuid)
+}
@@ -0,0 +1,97 @@
+package player
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestParseTrack_BuiltinPatterns(t *testing.T) {
+	tests := []struct {
+		raw                  string
+		artist, title, album string
+	}{
+		{"Daft Punk - Get Lucky", "Daft Punk", "Get Lucky", ""},
+		{"  Daft Punk  -  Get Lucky  ", "Daft Punk", "Get Lucky", ""},
+		{"Sigur Rós – Hoppípolla", "Sigur Rós", "Hoppípolla", ""},
+		{"Björk — Army of Me", "Björk", "Army of Me", ""},
+		{"Artist | Title", "Artist", "Title", ""},
+		{"Massive Attack - Teardrop - 2006 Remaster", "Massive Attack", "Teardrop - 2006 Remaster", ""},
+		{"Jay-Z - 99 Problems", "Jay-Z", "99 Problems", ""},
+		{"NOW PLAYING: Radiohead - Creep", "Radiohead", "Creep", ""},
+		{"Now playing - Radiohead - Creep", "Radiohead", "Creep", ""},
+		{"On Air: Nina Simone - Feeling Good", "Nina Simone", "Feeling Good", ""},
+		{`"Get Lucky" by Daft Punk`, "Daft Punk", "Get Lucky", ""},
+		{"“Stand by Me” by Ben E. King", "Ben E. King", "Stand by Me", ""},
+		{"Teardrop by Massive Attack from Mezzanine", "Massive Attack", "Teardrop", "Mezzanine"},
+		{"Stand by Me by Ben E. King from Don't Play That Song!", "Ben E. King", "Stand by Me", "Don't Play That Song!"},
+		// Bare titles with "by" in them stay whole
+		{"Stand by Me", "", "Stand by Me", ""},
+		{"Get Lucky by Daft Punk", "", "Get Lucky by Daft Punk", ""},
+		{"Killing Me Softly by the Fugees", "", "Killing Me Softly by the Fugees", ""},
+		{"AC/DC / Thunderstruck", "AC/DC", "Thunderstruck", ""},
+		{"AC/DC", "", "AC/DC", ""},
+		{"Station ID", "", "Station ID", ""},
+		{"NOW PLAYING: Jingle", "", "Jingle", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got := parseTrack(tt.raw, nil)
+			if got.Artist != tt.artist || got.Title != tt.title || got.Album != tt.album {
+				t.Errorf("parseTrack(%q) = %+v, want artist=%q title=%q album=%q", tt.raw, got, tt.artist, tt.title, tt.album)
+			}
+			if got.Raw != tt.raw {
+				t.Errorf("Raw = %q, want the original title", got.Raw)
+			}
+		})
+	}
+}
+
+func TestParseTrack_Empty(t *testing.T) {
+	if got := parseTrack("   ", nil); got.Title != "" || got.Artist != "" || got.String() != "" {
+		t.Errorf("expected an empty TrackInfo, got %+v", got)
+	}
+}
+
+func TestParseTrack_UserPatternsFirst(t *testing.T) {
+	titleFirst := regexp.MustCompile(`^(?P<title>.+?) - (?P<artist>.+?)(?: \[(?P<album>.+)\])?$`)
+	got := parseTrack("Get Lucky - Daft Punk [Random Access Memories]", []*regexp.Regexp{titleFirst})
+	if got.Artist != "Daft Punk" || got.Title != "Get Lucky" || got.Album != "Random Access Memories" {
+		t.Errorf("user pattern not applied: %+v", got)
+	}
+
+	// A user pattern that does not match falls through to the built-ins.
+	noMatch := regexp.MustCompile(`^\*\*\* (?P<title>.+) \*\*\*$`)
+	got = parseTrack("Daft Punk - Get Lucky", []*regexp.Regexp{noMatch})
+	if got.Artist != "Daft Punk" || got.Title != "Get Lucky" {
+		t.Errorf("expected built-in fallback, got %+v", got)
+	}
+}
+
+func TestParseTrack_StationOverrides(t *testing.T) {
+	SetMetadataPatterns([]MetadataPattern{
+		{Regexp: regexp.MustCompile(`^(?P<artist>.+?) - (?P<title>.+)$`)},
+		{Regexp: regexp.MustCompile(`^(?P<title>.+?) - (?P<artist>.+)$`), StationUUID: "classic-fm"},
+	})
+	defer SetMetadataPatterns(nil)
+
+	if got := ParseTrack("Clair de Lune - Debussy", "classic-fm"); got.Artist != "Debussy" || got.Title != "Clair de Lune" {
+		t.Errorf("station pattern should win on its station, got %+v", got)
+	}
+	if got := ParseTrack("Debussy - Clair de Lune", "other"); got.Artist != "Debussy" {
+		t.Errorf("global pattern should apply elsewhere, got %+v", got)
+	}
+}
+
+func TestTrackInfo_StringAndQuery(t *testing.T) {
+	full := TrackInfo{Raw: "x", Artist: "Massive Attack", Title: "Teardrop", Album: "Mezzanine"}
+	if got := full.String(); got != "Massive Attack - Teardrop (Mezzanine)" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := full.Query(); got != "Massive Attack Teardrop" {
+		t.Errorf("Query() = %q", got)
+	}
+	if got := (TrackInfo{Title: "Station ID"}).String(); got != "Station ID" {
+		t.Errorf("String() without artist = %q", got)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -30,6 +31,50 @@ func (p *MPVPlayer) PlayWithVolume(station *api.Station, volume int) error {
 	return p.Play(&cloned)
 }
 
+// PlaybackObserver is notified whenever any MPVPlayer starts a station, and
+// with station == nil when the player that started the latest one stops.
+// Other players stopping, such as a screen's player while a handed-off one
+// keeps playing, are not reported. It is called with the player's lock held,
+// so it must return quickly and must not call back into the player.
+type PlaybackObserver func(station *api.Station)
+
+var (
+	playbackObserver atomic.Pointer[PlaybackObserver]
+	// observedPlayer is the player that started the latest station.
+	observedPlayer atomic.Pointer[MPVPlayer]
+)
+
+// SetPlaybackObserver installs fn as the process-wide playback observer,
+// replacing any previous one. Pass nil to remove it.
+func SetPlaybackObserver(fn PlaybackObserver) {
+	if fn == nil {
+		playbackObserver.Store(nil)
+		return
+	}
+	playbackObserver.Store(&fn)
+}
+
+func notifyPlaybackObserver(station *api.Station) {
+	if fn := playbackObserver.Load(); fn != nil {
+		(*fn)(station)
+	}
+}
+
+// notifyPlaybackStarted reports that p started station and makes p the
+// player whose stop is reported.
+func notifyPlaybackStarted(p *MPVPlayer, station *api.Station) {
+	observedPlayer.Store(p)
+	notifyPlaybackObserver(station)
+}
+
+// notifyPlaybackStopped reports that p stopped, if it started the latest
+// station.
+func notifyPlaybackStopped(p *MPVPlayer) {
+	if observedPlayer.CompareAndSwap(p, nil) {
+		notifyPlaybackObserver(nil)
+	}
+}
+
 // playerInstanceCounter provides a process-wide unique ID for each MPVPlayer
 // so that concurrent instances never collide on the same IPC socket path.
 var playerInstanceCounter atomic.Uint64
@@ -55,6 +100,17 @@ type MPVPlayer struct {
 	currentTrack    string                   // Current playing track
 	trackMu         sync.Mutex               // Protect track history
 	metadataManager *storage.MetadataManager // Track play statistics
+	levelBands      int                      // Spectrum bands measured by mpv; -1 when level analysis is off
+	timeShift       bool                     // Started with a time-shift buffer
+	adFilter        *AdFilter                // Ad rules for this stream; nil when off
+	adVolume        int                      // Volume forced by the ad filter; -1 when not overriding
+	adReason        string                   // Why adVolume is set (AdReason*)
+	adTitle         string                   // Last title the ad filter evaluated
+	adConnectUntil  time.Time                // End of the mute-on-connect window
+	resumeDeadline  time.Time                // When to stop waiting for the network; zero when not waiting
+	wokeFromSleep   bool                     // mpv was killed after a suspend and should be resumed
+	sessionBytes    atomic.Int64             // Data downloaded by the current stream
+	capWarned       bool                     // The data cap warning was sent for this stream
 }
 
 // NewMPVPlayer creates a new MPV player instance
@@ -65,11 +121,16 @@ func NewMPVPlayer() *MPVPlayer {
 		lastVolume: 100,
 		stopCh:     make(chan struct{}),
 		instanceID: playerInstanceCounter.Add(1),
+		levelBands: -1,
+		adVolume:   -1,
 	}
 }
 
 // Play starts playing a radio station
 func (p *MPVPlayer) Play(station *api.Station) error {
+	// Data saver may swap in a lower-bitrate stream; this can hit the network
+	station = pickVariant(station)
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -79,9 +140,19 @@ func (p *MPVPlayer) Play(station *api.Station) error {
 		return nil
 	}
 
+	if err := p.checkDataCapLocked(); err != nil {
+		return err
+	}
+
+	return p.playLocked(station)
+}
+
+// playLocked starts station, replacing whatever is playing. Caller must hold
+// p.mu.
+func (p *MPVPlayer) playLocked(station *api.Station) error {
 	// Stop any existing playback
 	if p.playing {
-		_ = p.stopInternal()
+		_ = p.stopInternal(storage.StopReasonSwitched)
 	}
 
 	// Check if mpv is available
@@ -124,12 +195,15 @@ func (p *MPVPlayer) Play(station *api.Station) error {
 		connConfig = storage.DefaultConnectionConfig()
 	}
 
+	// Pre-roll ads: start silent and let the ad filter restore the volume
+	startVolume := p.resetAdFilterLocked(volumeToUse)
+
 	// Build mpv arguments
 	args := []string{
 		"--no-video",
 		"--no-terminal",
 		"--really-quiet",
-		fmt.Sprintf("--volume=%d", volumeToUse),
+		fmt.Sprintf("--volume=%d", startVolume),
 		fmt.Sprintf("--input-ipc-server=%s", p.socketPath),
 	}
 
@@ -155,9 +229,29 @@ func (p *MPVPlayer) Play(station *api.Station) error {
 		args = append(args, "--no-cache")
 	}
 
+	// Keep already-played audio around for rewinding
+	p.timeShift = false
+	if depth := time.Duration(timeShiftDepth.Load()); depth > 0 {
+		p.timeShift = true
+		args = append(args, timeShiftArgs(depth, station.Bitrate, connConfig.StreamBufferMB)...)
+	}
+
+	// Per-station overrides go last so they win over the connection flags
+	if err := ValidatePlaybackOptions(station.Options); err != nil {
+		return fmt.Errorf("station options: %w", err)
+	}
+	args = append(args, playbackOptionArgs(station.Options)...)
+
+	// Measure levels for the visualizer when it is enabled
+	p.levelBands = -1
+	if bands, ok := levelAnalysisBands(); ok {
+		p.levelBands = bands
+		args = append(args, levelFilterArg(bands))
+	}
+
 	// Validate URL scheme before passing to mpv to prevent local file access
 	// via file:// or other unexpected schemes from a malicious API response.
-	safeURL, err := validateStreamURL(station.URLResolved)
+	safeURL, err := validateStreamURL(station.StreamURL())
 	if err != nil {
 		return err
 	}
@@ -183,12 +277,16 @@ func (p *MPVPlayer) Play(station *api.Station) error {
 	p.playing = true
 	p.paused = false
 	p.station = station
+	p.wokeFromSleep = false
+	p.capWarned = false
+	p.sessionBytes.Store(0)
 	p.stopCh = make(chan struct{})
 
 	// Record play start for statistics (errors are non-fatal)
 	if p.metadataManager != nil {
 		_ = p.metadataManager.StartPlay(station)
 	}
+	notifyPlaybackStarted(p, station)
 
 	// Connect to IPC socket (with retry for socket creation delay)
 	go p.connectToSocket()
@@ -199,6 +297,14 @@ func (p *MPVPlayer) Play(station *api.Station) error {
 	// Monitor metadata for track changes
 	go p.monitorMetadata()
 
+	// Count downloaded data for usage statistics and the data cap
+	go p.monitorUsage(p.stopCh, station)
+
+	// Notice when the computer wakes from sleep so the stream can resume
+	if resumeTimeout.Load() > 0 {
+		go p.watchSuspend(p.stopCh)
+	}
+
 	return nil
 }
 
@@ -242,6 +348,10 @@ func (p *MPVPlayer) connectToSocket() {
 			if muted {
 				currentVol = 0
 			}
+			// Keep the ad filter's override (e.g. mute-on-connect)
+			if p.adVolume >= 0 {
+				currentVol = p.adVolume
+			}
 			_ = p.sendCommand([]interface{}{"set_property", "volume", float64(currentVol)})
 			p.mu.Unlock()
 			return
@@ -364,6 +474,17 @@ type ipcReply struct {
 	Error     string      `json:"error"`
 }
 
+// StreamSchemes are the URL schemes the player opens a station with.
+// Anything else, such as file:// or fd://, could make mpv open local
+// resources.
+var StreamSchemes = []string{"http", "https", "rtsp", "rtmp", "rtsps", "rtmps"}
+
+// IsStreamScheme reports whether scheme, in any case, is one of
+// StreamSchemes.
+func IsStreamScheme(scheme string) bool {
+	return slices.Contains(StreamSchemes, strings.ToLower(scheme))
+}
+
 // validateStreamURL checks that the URL uses a safe streaming scheme and
 // returns the trimmed, validated URL. This prevents a malicious or compromised
 // API response from supplying a file:// or fd:// URL that would cause mpv to
@@ -380,15 +501,13 @@ func validateStreamURL(rawURL string) (string, error) {
 		return "", fmt.Errorf("station URL is invalid: %q", rawURL)
 	}
 
-	switch strings.ToLower(u.Scheme) {
-	case "http", "https", "rtsp", "rtmp", "rtsps", "rtmps":
-		if u.Host == "" {
-			return "", fmt.Errorf("station URL must include a host: %q", rawURL)
-		}
-		return cleaned, nil
-	default:
-		return "", fmt.Errorf("station URL has disallowed scheme (must be http/https/rtsp/rtmp/rtsps/rtmps): %q", rawURL)
+	if !IsStreamScheme(u.Scheme) {
+		return "", fmt.Errorf("station URL has disallowed scheme (must be %s): %q", strings.Join(StreamSchemes, "/"), rawURL)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("station URL must include a host: %q", rawURL)
 	}
+	return cleaned, nil
 }
 
 // GetAudioBitrate returns the current audio bitrate (useful for checking signal)
@@ -472,7 +591,14 @@ func (p *MPVPlayer) monitorMetadata() {
 	stopCh := p.stopCh
 	p.mu.Unlock()
 
-	ticker := time.NewTicker(5 * time.Second) // Check every 5 seconds
+	interval := 5 * time.Second // Check every 5 seconds
+	p.mu.Lock()
+	if p.adFilter != nil {
+		interval = adPollInterval
+	}
+	p.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
@@ -491,6 +617,9 @@ func (p *MPVPlayer) monitorMetadata() {
 			if err == nil && track != "" {
 				p.addToTrackHistory(track)
 			}
+			if err == nil {
+				p.applyAdFilter(track)
+			}
 
 		case <-stopCh:
 			return
@@ -510,7 +639,7 @@ func (p *MPVPlayer) Stop() error {
 		return nil
 	}
 
-	return p.stopInternal()
+	return p.stopInternal(storage.StopReasonStopped)
 }
 
 // cleanupResourcesLocked releases IPC connection, socket file, and all player
@@ -532,11 +661,20 @@ func (p *MPVPlayer) cleanupResourcesLocked() {
 	// Signal monitorMetadata goroutine to stop
 	close(p.stopCh)
 
+	if p.playing {
+		notifyPlaybackStopped(p)
+	}
+
 	p.playing = false
 	p.killed = false // reset so the player instance may be reused after a full stop
 	p.paused = false
 	p.station = nil
 	p.cmd = nil
+	p.wokeFromSleep = false
+	if !p.resumeDeadline.IsZero() {
+		p.resumeDeadline = time.Time{}
+		notifyNetworkStatus(p, false)
+	}
 
 	// Clear track history
 	p.trackMu.Lock()
@@ -545,31 +683,17 @@ func (p *MPVPlayer) cleanupResourcesLocked() {
 	p.trackMu.Unlock()
 }
 
-// stopInternal stops playback without locking (internal use)
-func (p *MPVPlayer) stopInternal() error {
+// stopInternal stops playback without locking (internal use). reason is
+// recorded with the listening session.
+func (p *MPVPlayer) stopInternal(reason string) error {
 	// Record play stop for statistics (errors are non-fatal)
 	if p.metadataManager != nil && p.station != nil {
-		_ = p.metadataManager.StopPlay(p.station.StationUUID)
+		_ = p.metadataManager.StopPlayWithReason(p.station.StationUUID, reason)
 	}
 
 	if p.cmd != nil && p.cmd.Process != nil {
-		if runtime.GOOS == "windows" {
-			// On Windows, mpv installed via package managers (e.g. scoop) uses a
-			// shim executable that spawns the real mpv as a child process.
-			// Process.Kill() only terminates the shim, leaving the real mpv running
-			// as an orphan. taskkill with /T kills the entire process tree so all
-			// descendant processes (including the real mpv) are terminated.
-			_ = exec.Command("taskkill", "/F", "/T", "/PID", fmt.Sprintf("%d", p.cmd.Process.Pid)).Run()
-			// Also call Kill to ensure the Go process handle is cleaned up.
-			_ = p.cmd.Process.Kill()
-		} else {
-			// Send termination signal
-			if err := p.cmd.Process.Kill(); err != nil {
-				// Process may have already exited
-				if !errors.Is(err, os.ErrProcessDone) {
-					return fmt.Errorf("failed to stop mpv: %w", err)
-				}
-			}
+		if err := killMPV(p.cmd); err != nil {
+			return err
 		}
 
 		// Wait for process to finish (with timeout to prevent hanging)
@@ -590,6 +714,29 @@ func (p *MPVPlayer) stopInternal() error {
 	return nil
 }
 
+// killMPV terminates the mpv process started by cmd without waiting for it.
+func killMPV(cmd *exec.Cmd) error {
+	if runtime.GOOS == "windows" {
+		// On Windows, mpv installed via package managers (e.g. scoop) uses a
+		// shim executable that spawns the real mpv as a child process.
+		// Process.Kill() only terminates the shim, leaving the real mpv running
+		// as an orphan. taskkill with /T kills the entire process tree so all
+		// descendant processes (including the real mpv) are terminated.
+		_ = exec.Command("taskkill", "/F", "/T", "/PID", fmt.Sprintf("%d", cmd.Process.Pid)).Run()
+		// Also call Kill to ensure the Go process handle is cleaned up.
+		_ = cmd.Process.Kill()
+		return nil
+	}
+	// Send termination signal
+	if err := cmd.Process.Kill(); err != nil {
+		// Process may have already exited
+		if !errors.Is(err, os.ErrProcessDone) {
+			return fmt.Errorf("failed to stop mpv: %w", err)
+		}
+	}
+	return nil
+}
+
 // IsPlaying returns whether the player is currently playing
 func (p *MPVPlayer) IsPlaying() bool {
 	p.mu.Lock()
@@ -634,6 +781,7 @@ func (p *MPVPlayer) SetVolume(volume int) {
 	}
 	p.volume = volume
 	p.muted = (volume == 0)
+	p.clearAdOverrideLocked()
 
 	// Send volume command to mpv via IPC
 	if p.conn != nil {
@@ -652,6 +800,7 @@ func (p *MPVPlayer) IncreaseVolume(amount int) int {
 	}
 	p.muted = false
 	p.lastVolume = p.volume
+	p.clearAdOverrideLocked()
 
 	// Send volume command to mpv via IPC
 	if p.conn != nil {
@@ -674,6 +823,7 @@ func (p *MPVPlayer) DecreaseVolume(amount int) int {
 	if p.volume > 0 {
 		p.lastVolume = p.volume
 	}
+	p.clearAdOverrideLocked()
 
 	// Send volume command to mpv via IPC
 	if p.conn != nil {
@@ -703,6 +853,7 @@ func (p *MPVPlayer) ToggleMute() (muted bool, volume int) {
 		p.volume = 0
 		p.muted = true
 	}
+	p.clearAdOverrideLocked()
 
 	// Send volume command to mpv via IPC
 	if p.conn != nil {
@@ -745,7 +896,8 @@ func (p *MPVPlayer) IsPaused() bool {
 // Done returns a channel that is closed when playback ends for any reason:
 // a natural stream drop, an external process kill, or an explicit Stop call.
 // Callers must not rely on this channel to distinguish between these cases;
-// it only signals that the player is no longer active.
+// it only signals that the player is no longer active. Resuming a stream
+// after a network drop starts a new one, so it closes the channel as well.
 func (p *MPVPlayer) Done() <-chan struct{} {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -763,6 +915,7 @@ func (p *MPVPlayer) SetMetadataManager(mgr *storage.MetadataManager) {
 func (p *MPVPlayer) monitor() {
 	p.mu.Lock()
 	cmd := p.cmd
+	stopCh := p.stopCh
 	p.mu.Unlock()
 
 	if cmd == nil || cmd.Process == nil {
@@ -782,18 +935,36 @@ func (p *MPVPlayer) monitor() {
 	select {
 	case <-done:
 		// Process ended naturally (stream drop, network loss, error).
-		// Guard with p.playing: stopInternal may have raced us here (it kills the
-		// process, then calls cleanupResourcesLocked which sets playing=false and
-		// closes stopCh). If it won the lock first, skip to avoid a double-close.
+		// Guard with p.playing and the captured cmd: stopInternal may have
+		// raced us here (it kills the process, then calls
+		// cleanupResourcesLocked which sets playing=false and closes stopCh),
+		// and Play may already have started another stream. If either won the
+		// lock first, skip to avoid a double-close.
 		p.mu.Lock()
-		if p.playing {
-			if p.metadataManager != nil && p.station != nil {
-				_ = p.metadataManager.StopPlay(p.station.StationUUID)
+		if !p.playing || p.cmd != cmd {
+			p.mu.Unlock()
+			return
+		}
+		station := p.station
+		woke := p.wokeFromSleep
+		p.mu.Unlock()
+
+		// Checking the network can take a few seconds, so do it unlocked
+		resume := shouldResume(station, woke)
+
+		p.mu.Lock()
+		if p.playing && p.cmd == cmd {
+			if resume {
+				p.waitForNetworkLocked()
+			} else {
+				if p.metadataManager != nil && p.station != nil {
+					_ = p.metadataManager.StopPlayWithReason(p.station.StationUUID, storage.StopReasonEnded)
+				}
+				p.cleanupResourcesLocked()
 			}
-			p.cleanupResourcesLocked()
 		}
 		p.mu.Unlock()
-	case <-p.stopCh:
+	case <-stopCh:
 		// Stop was called, process already killed and resources cleaned up
 		return
 	}
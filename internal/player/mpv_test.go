@@ -175,3 +175,57 @@ func TestMPVPlayer_ThreadSafety(t *testing.T) {
 
 	// If we got here without data races, test passes
 }
+
+// TestPlaybackObserver_NotifiedOnCleanup verifies that tearing down a playing
+// player reports a nil station to the observer, and that an idle Stop() does not.
+func TestPlaybackObserver_NotifiedOnCleanup(t *testing.T) {
+	var calls []*api.Station
+	SetPlaybackObserver(func(st *api.Station) { calls = append(calls, st) })
+	defer SetPlaybackObserver(nil)
+
+	p := NewMPVPlayer()
+	_ = p.Stop()
+	if len(calls) != 0 {
+		t.Fatalf("idle Stop() must not notify, got %d calls", len(calls))
+	}
+
+	p = NewMPVPlayer()
+	p.mu.Lock()
+	p.playing = true
+	p.station = &api.Station{StationUUID: "obs-1"}
+	observedPlayer.Store(p)
+	p.cleanupResourcesLocked()
+	p.mu.Unlock()
+
+	if len(calls) != 1 || calls[0] != nil {
+		t.Errorf("expected a single nil notification, got %v", calls)
+	}
+}
+
+// TestPlaybackObserver_IgnoresOtherPlayers verifies that a player stopping
+// while another one started the current station is not reported.
+func TestPlaybackObserver_IgnoresOtherPlayers(t *testing.T) {
+	var calls []*api.Station
+	SetPlaybackObserver(func(st *api.Station) { calls = append(calls, st) })
+	defer SetPlaybackObserver(nil)
+
+	current, other := NewMPVPlayer(), NewMPVPlayer()
+	notifyPlaybackStarted(other, &api.Station{StationUUID: "old"})
+	notifyPlaybackStarted(current, &api.Station{StationUUID: "new"})
+
+	other.mu.Lock()
+	other.playing = true
+	other.cleanupResourcesLocked()
+	other.mu.Unlock()
+	if len(calls) != 2 {
+		t.Fatalf("stopping another player must not notify, got %d calls", len(calls))
+	}
+
+	current.mu.Lock()
+	current.playing = true
+	current.cleanupResourcesLocked()
+	current.mu.Unlock()
+	if len(calls) != 3 || calls[2] != nil {
+		t.Errorf("expected the current player's stop to be reported, got %v", calls)
+	}
+}
@@ -0,0 +1,161 @@
+package player
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+// Per-station cache limits, matching the connection settings range.
+const (
+	minStationCacheMB = 10
+	maxStationCacheMB = 200
+	maxStartOffset    = 24 * 60 * 60
+)
+
+// safeMPVFlags lists the mpv options a station may set through MPVArgs.
+// Everything here only tunes networking, demuxing or audio output; options
+// that can open local files, run scripts or spawn processes (script, af,
+// ytdl, include, input-*, stream-lavf-o, ...), weaken security (tls-verify)
+// or force a demuxer onto whatever the stream is (demuxer,
+// demuxer-lavf-format) are deliberately absent since favorites can be
+// imported from other people.
+var safeMPVFlags = map[string]bool{
+	"ad":                           true,
+	"ad-lavc-downmix":              true,
+	"audio-buffer":                 true,
+	"audio-channels":               true,
+	"audio-format":                 true,
+	"audio-pitch-correction":       true,
+	"audio-samplerate":             true,
+	"audio-stream-silence":         true,
+	"cache-pause":                  true,
+	"cache-pause-initial":          true,
+	"cache-pause-wait":             true,
+	"cache-secs":                   true,
+	"demuxer-lavf-analyzeduration": true,
+	"demuxer-lavf-probesize":       true,
+	"demuxer-max-back-bytes":       true,
+	"demuxer-max-bytes":            true,
+	"demuxer-readahead-secs":       true,
+	"gapless-audio":                true,
+	"hls-bitrate":                  true,
+	"network-timeout":              true,
+	"replaygain":                   true,
+	"replaygain-preamp":            true,
+	"stream-buffer-size":           true,
+	"volume-max":                   true,
+}
+
+// SafeMPVFlags returns the option names allowed in PlaybackOptions.MPVArgs,
+// sorted for display.
+func SafeMPVFlags() []string {
+	names := make([]string, 0, len(safeMPVFlags))
+	for name := range safeMPVFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidatePlaybackOptions checks per-station overrides before they are saved
+// or handed to mpv. A nil value is valid.
+func ValidatePlaybackOptions(o *api.PlaybackOptions) error {
+	if o == nil {
+		return nil
+	}
+	if hasControlChars(o.UserAgent) {
+		return fmt.Errorf("user agent must be a single line")
+	}
+	if hasControlChars(o.Referer) {
+		return fmt.Errorf("referer must be a single line")
+	}
+	for name, value := range o.Headers {
+		if !validHeaderName(name) {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+		if hasControlChars(value) {
+			return fmt.Errorf("header %s must be a single line", name)
+		}
+	}
+	if o.CacheMB != 0 && (o.CacheMB < minStationCacheMB || o.CacheMB > maxStationCacheMB) {
+		return fmt.Errorf("cache must be 0 or %d-%d MB", minStationCacheMB, maxStationCacheMB)
+	}
+	if o.StartOffset < 0 || o.StartOffset > maxStartOffset {
+		return fmt.Errorf("start offset must be 0-%d seconds", maxStartOffset)
+	}
+	for _, arg := range o.MPVArgs {
+		if err := validateMPVArg(arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateMPVArg accepts --name, --no-name and --name=value for safe-listed
+// option names.
+func validateMPVArg(arg string) error {
+	if !strings.HasPrefix(arg, "--") {
+		return fmt.Errorf("mpv option %q must start with --", arg)
+	}
+	name, value, _ := strings.Cut(arg[2:], "=")
+	if hasControlChars(value) {
+		return fmt.Errorf("mpv option %q must be a single line", arg)
+	}
+	if !safeMPVFlags[name] && !safeMPVFlags[strings.TrimPrefix(name, "no-")] {
+		return fmt.Errorf("mpv option --%s is not allowed", name)
+	}
+	return nil
+}
+
+// playbackOptionArgs turns validated overrides into mpv arguments. They are
+// appended after the connection flags; mpv lets later options win, so a
+// station's cache size replaces the global one.
+func playbackOptionArgs(o *api.PlaybackOptions) []string {
+	if o.IsZero() {
+		return nil
+	}
+	var args []string
+	if o.UserAgent != "" {
+		args = append(args, "--user-agent="+o.UserAgent)
+	}
+	if o.Referer != "" {
+		args = append(args, "--referrer="+o.Referer)
+	}
+	// The -append form adds one entry verbatim, so commas in header values
+	// are not treated as list separators.
+	names := make([]string, 0, len(o.Headers))
+	for name := range o.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, fmt.Sprintf("--http-header-fields-append=%s: %s", name, o.Headers[name]))
+	}
+	if o.CacheMB > 0 {
+		args = append(args, "--cache=yes", fmt.Sprintf("--demuxer-max-bytes=%dM", o.CacheMB))
+	}
+	if o.StartOffset > 0 {
+		args = append(args, fmt.Sprintf("--start=%d", o.StartOffset))
+	}
+	return append(args, o.MPVArgs...)
+}
+
+func hasControlChars(s string) bool {
+	return strings.ContainsAny(s, "\r\n\x00")
+}
+
+// validHeaderName reports whether name is an RFC 7230 token.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r > 0x7e || r <= 0x20 || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return false
+		}
+	}
+	return true
+}
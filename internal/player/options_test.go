@@ -0,0 +1,71 @@
+package player
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+func TestValidatePlaybackOptions(t *testing.T) {
+	valid := &api.PlaybackOptions{
+		UserAgent:   "VLC/3.0",
+		Referer:     "https://example.com/player",
+		Headers:     map[string]string{"X-Token": "abc, def"},
+		CacheMB:     100,
+		StartOffset: 30,
+		MPVArgs:     []string{"--demuxer-lavf-probesize=32", "--no-cache-pause", "--network-timeout=30"},
+	}
+	if err := ValidatePlaybackOptions(valid); err != nil {
+		t.Fatalf("expected valid options, got %v", err)
+	}
+	if err := ValidatePlaybackOptions(nil); err != nil {
+		t.Errorf("nil options should be valid, got %v", err)
+	}
+
+	cases := map[string]*api.PlaybackOptions{
+		"header injection": {UserAgent: "x\r\nEvil: 1"},
+		"bad header name":  {Headers: map[string]string{"Bad Name": "v"}},
+		"cache too small":  {CacheMB: 5},
+		"negative start":   {StartOffset: -1},
+		"unsafe flag":      {MPVArgs: []string{"--script=/tmp/x.lua"}},
+		"unsafe negated":   {MPVArgs: []string{"--no-ytdl"}},
+		"tls off":          {MPVArgs: []string{"--tls-verify=no"}},
+		"not a flag":       {MPVArgs: []string{"file:///etc/passwd"}},
+		"lavf options":     {MPVArgs: []string{"--stream-lavf-o=protocol_whitelist=file"}},
+		"forced demuxer":   {MPVArgs: []string{"--demuxer=rawaudio"}},
+		"forced format":    {MPVArgs: []string{"--demuxer-lavf-format=aac"}},
+	}
+	for name, o := range cases {
+		if err := ValidatePlaybackOptions(o); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPlaybackOptionArgs(t *testing.T) {
+	if args := playbackOptionArgs(nil); args != nil {
+		t.Errorf("nil options should add no args, got %v", args)
+	}
+
+	args := playbackOptionArgs(&api.PlaybackOptions{
+		UserAgent: "UA",
+		Referer:   "https://r",
+		Headers:   map[string]string{"B": "2", "A": "1, 1"},
+		CacheMB:   150,
+		MPVArgs:   []string{"--network-timeout=30"},
+	})
+	want := []string{
+		"--user-agent=UA",
+		"--referrer=https://r",
+		"--http-header-fields-append=A: 1, 1",
+		"--http-header-fields-append=B: 2",
+		"--cache=yes",
+		"--demuxer-max-bytes=150M",
+		"--network-timeout=30",
+	}
+	if !slices.Equal(args, want) {
+		t.Errorf("got  %s\nwant %s", strings.Join(args, " "), strings.Join(want, " "))
+	}
+}
@@ -0,0 +1,215 @@
+package player
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+const (
+	// resumeProbeInterval is how often a player waiting for the network
+	// checks whether the stream host can be reached again.
+	resumeProbeInterval = 3 * time.Second
+	// resumeDialTimeout bounds a single reachability check, DNS included.
+	resumeDialTimeout = 3 * time.Second
+	// suspendCheckInterval is how often players look at the wall clock.
+	suspendCheckInterval = 5 * time.Second
+	// suspendGap is how far the wall clock may run ahead of the ticker
+	// before the computer is considered to have been asleep.
+	suspendGap = 30 * time.Second
+)
+
+// resumeTimeout holds the process-wide network resume timeout; zero means
+// streams that drop simply end.
+var resumeTimeout atomic.Int64
+
+// SetNetworkResume sets how long players started from now on wait for the
+// network to come back after their stream drops. Zero or a negative timeout
+// turns resuming off.
+func SetNetworkResume(timeout time.Duration) {
+	resumeTimeout.Store(int64(max(timeout, 0)))
+}
+
+// NetworkStatusHandler is called when a player starts or stops waiting for
+// the network. Like PlaybackObserver it runs with the player's lock held, so
+// it must return quickly and must not call back into the player.
+type NetworkStatusHandler func(p *MPVPlayer, waiting bool)
+
+var networkStatusHandler atomic.Pointer[NetworkStatusHandler]
+
+// SetNetworkStatusHandler installs fn as the process-wide network status
+// handler. Pass nil to remove it.
+func SetNetworkStatusHandler(fn NetworkStatusHandler) {
+	if fn == nil {
+		networkStatusHandler.Store(nil)
+		return
+	}
+	networkStatusHandler.Store(&fn)
+}
+
+func notifyNetworkStatus(p *MPVPlayer, waiting bool) {
+	if fn := networkStatusHandler.Load(); fn != nil {
+		(*fn)(p, waiting)
+	}
+}
+
+// streamHostAddr returns the host:port a stream URL connects to.
+func streamHostAddr(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	host := u.Hostname()
+	if host == "" {
+		return "", fmt.Errorf("stream URL has no host")
+	}
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// hostReachable resolves and connects to addr. It is a variable so tests can
+// take the network down.
+var hostReachable = func(addr string) bool {
+	conn, err := net.DialTimeout("tcp", addr, resumeDialTimeout)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// WaitingForNetwork reports whether the player lost its stream to a network
+// drop and is waiting to resume it, and how long it will keep waiting.
+func (p *MPVPlayer) WaitingForNetwork() (remaining time.Duration, waiting bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.resumeDeadline.IsZero() {
+		return 0, false
+	}
+	return max(time.Until(p.resumeDeadline), 0), true
+}
+
+// shouldResume decides whether a stream that just ended went down with the
+// network. After a sleep it always does; otherwise the stream host must be
+// unreachable, so a station that is up but failing still ends normally.
+func shouldResume(station *api.Station, wokeFromSleep bool) bool {
+	if resumeTimeout.Load() <= 0 || station == nil {
+		return false
+	}
+	addr, err := streamHostAddr(station.StreamURL())
+	if err != nil {
+		return false
+	}
+	return wokeFromSleep || !hostReachable(addr)
+}
+
+// waitForNetworkLocked keeps the player on its station after mpv exited and
+// starts polling for the network. Caller must hold p.mu.
+func (p *MPVPlayer) waitForNetworkLocked() {
+	// Time spent offline is not listening time
+	if p.metadataManager != nil && p.station != nil {
+		_ = p.metadataManager.StopPlayWithReason(p.station.StationUUID, storage.StopReasonNetwork)
+	}
+	if p.conn != nil {
+		_ = p.conn.Close()
+		p.conn = nil
+		p.connReader = nil
+	}
+	p.cmd = nil
+	p.paused = false
+	// Round(0) drops the monotonic reading so the deadline follows the wall
+	// clock and still passes while the computer sleeps.
+	p.resumeDeadline = time.Now().Add(time.Duration(resumeTimeout.Load())).Round(0)
+	notifyNetworkStatus(p, true)
+
+	go p.awaitNetwork(p.stopCh, p.station, p.resumeDeadline)
+}
+
+// awaitNetwork plays station again once its host can be reached, or stops the
+// player when the deadline passes first. It gives up quietly if anything else
+// stops or replaces the stream in the meantime.
+func (p *MPVPlayer) awaitNetwork(stopCh chan struct{}, station *api.Station, deadline time.Time) {
+	addr, _ := streamHostAddr(station.StreamURL())
+	ticker := time.NewTicker(resumeProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		reachable := hostReachable(addr)
+
+		p.mu.Lock()
+		if p.stopCh != stopCh || p.resumeDeadline.IsZero() {
+			p.mu.Unlock()
+			return
+		}
+		if reachable {
+			p.resumeLocked()
+			p.mu.Unlock()
+			return
+		}
+		if time.Now().After(deadline) {
+			p.cleanupResourcesLocked()
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// resumeLocked restarts the current station at the current volume. Caller
+// must hold p.mu.
+func (p *MPVPlayer) resumeLocked() {
+	volume := p.volume
+	if p.muted {
+		volume = 0
+	}
+	resumed := *p.station
+	resumed.Volume = &volume
+	// A failed start leaves the player stopped, which is all we can do.
+	_ = p.playLocked(&resumed)
+}
+
+// watchSuspend restarts the stream when the wall clock jumps ahead of the
+// ticker, which means the computer was asleep. The monotonic clock stops
+// during sleep on some systems, so only wall-clock readings are compared.
+// mpv often keeps a dead connection open after a long sleep; killing it lets
+// monitor treat the stream like any other network drop.
+func (p *MPVPlayer) watchSuspend(stopCh chan struct{}) {
+	ticker := time.NewTicker(suspendCheckInterval)
+	defer ticker.Stop()
+
+	last := time.Now().Round(0)
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+		now := time.Now().Round(0)
+		if now.Sub(last) > suspendCheckInterval+suspendGap {
+			p.mu.Lock()
+			if p.stopCh == stopCh && p.cmd != nil && p.cmd.Process != nil {
+				p.wokeFromSleep = true
+				_ = killMPV(p.cmd)
+			}
+			p.mu.Unlock()
+		}
+		last = now
+	}
+}
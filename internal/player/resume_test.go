@@ -0,0 +1,194 @@
+package player
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+// fakeNetwork replaces hostReachable for the duration of a test and counts
+// the checks made.
+func fakeNetwork(t *testing.T, up bool) *atomic.Int32 {
+	t.Helper()
+	var checks atomic.Int32
+	orig := hostReachable
+	hostReachable = func(string) bool {
+		checks.Add(1)
+		return up
+	}
+	t.Cleanup(func() { hostReachable = orig })
+	return &checks
+}
+
+// networkEvents records NetworkStatusHandler calls.
+type networkEvents struct {
+	mu     sync.Mutex
+	events []bool
+}
+
+func (n *networkEvents) install(t *testing.T) {
+	t.Helper()
+	SetNetworkStatusHandler(func(_ *MPVPlayer, waiting bool) {
+		n.mu.Lock()
+		n.events = append(n.events, waiting)
+		n.mu.Unlock()
+	})
+	t.Cleanup(func() { SetNetworkStatusHandler(nil) })
+}
+
+func (n *networkEvents) get() []bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]bool(nil), n.events...)
+}
+
+// startExitingProcess starts a child that exits straight away, standing in
+// for an mpv whose stream dropped.
+func startExitingProcess(t *testing.T) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start child: %v", err)
+	}
+	return cmd
+}
+
+func TestStreamHostAddr(t *testing.T) {
+	tests := []struct {
+		url, want string
+		wantErr   bool
+	}{
+		{"http://stream.example.com/live", "stream.example.com:80", false},
+		{"https://stream.example.com/live", "stream.example.com:443", false},
+		{"http://10.0.0.5:8000/radio.mp3", "10.0.0.5:8000", false},
+		{"http://[::1]:8000/", "[::1]:8000", false},
+		{"not a url", "", true},
+	}
+	for _, tt := range tests {
+		got, err := streamHostAddr(tt.url)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("streamHostAddr(%q) = %q, %v; want %q (error %v)", tt.url, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestShouldResume(t *testing.T) {
+	station := &api.Station{URLResolved: "http://stream.example.com/live"}
+
+	SetNetworkResume(0)
+	fakeNetwork(t, false)
+	if shouldResume(station, true) {
+		t.Error("resume is off, nothing should be resumed")
+	}
+
+	SetNetworkResume(time.Minute)
+	defer SetNetworkResume(0)
+	if !shouldResume(station, false) {
+		t.Error("an unreachable host should be waited for")
+	}
+
+	fakeNetwork(t, true)
+	if shouldResume(station, false) {
+		t.Error("a stream that ended with the host up should just end")
+	}
+	if !shouldResume(station, true) {
+		t.Error("a stream killed after sleep should always resume")
+	}
+	if shouldResume(&api.Station{URLResolved: "::"}, true) {
+		t.Error("a stream without a host cannot be resumed")
+	}
+}
+
+func TestMonitor_WaitsForNetworkWhenStreamDrops(t *testing.T) {
+	SetNetworkResume(time.Minute)
+	defer SetNetworkResume(0)
+	checks := fakeNetwork(t, false)
+	var events networkEvents
+	events.install(t)
+
+	p := NewMPVPlayer()
+	p.mu.Lock()
+	p.playing = true
+	p.station = &api.Station{StationUUID: "net-1", URLResolved: "http://stream.example.com/live"}
+	p.cmd = startExitingProcess(t)
+	done := p.stopCh
+	p.mu.Unlock()
+
+	p.monitor()
+
+	remaining, waiting := p.WaitingForNetwork()
+	if !waiting || remaining <= 0 || remaining > time.Minute {
+		t.Fatalf("expected to wait up to a minute, got %v %v", remaining, waiting)
+	}
+	if !p.IsPlaying() || p.GetCurrentStation() == nil {
+		t.Error("the player should stay on its station while waiting")
+	}
+	select {
+	case <-done:
+		t.Fatal("Done must stay open while waiting for the network")
+	default:
+	}
+
+	// Let the first poll happen so the test does not race it.
+	for deadline := time.Now().Add(2 * time.Second); checks.Load() < 2 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := p.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if _, waiting := p.WaitingForNetwork(); waiting || p.IsPlaying() {
+		t.Error("Stop should end the wait")
+	}
+	if got := events.get(); len(got) != 2 || !got[0] || got[1] {
+		t.Errorf("expected waiting then not waiting, got %v", got)
+	}
+}
+
+func TestMonitor_StreamEndsWhenNetworkIsUp(t *testing.T) {
+	SetNetworkResume(time.Minute)
+	defer SetNetworkResume(0)
+	fakeNetwork(t, true)
+
+	p := NewMPVPlayer()
+	p.mu.Lock()
+	p.playing = true
+	p.station = &api.Station{URLResolved: "http://stream.example.com/live"}
+	p.cmd = startExitingProcess(t)
+	p.mu.Unlock()
+
+	p.monitor()
+
+	if _, waiting := p.WaitingForNetwork(); waiting || p.IsPlaying() {
+		t.Error("a stream that fails while online should simply stop")
+	}
+}
+
+func TestAwaitNetwork_GivesUpAtDeadline(t *testing.T) {
+	SetNetworkResume(time.Minute)
+	defer SetNetworkResume(0)
+	fakeNetwork(t, false)
+
+	p := NewMPVPlayer()
+	p.mu.Lock()
+	p.playing = true
+	p.station = &api.Station{URLResolved: "http://stream.example.com/live"}
+	p.resumeDeadline = time.Now().Add(-time.Second)
+	stopCh, station, deadline := p.stopCh, p.station, p.resumeDeadline
+	p.mu.Unlock()
+
+	p.awaitNetwork(stopCh, station, deadline)
+
+	if p.IsPlaying() {
+		t.Error("the player should stop once the deadline passes")
+	}
+	select {
+	case <-p.Done():
+	default:
+		t.Error("Done should be closed after giving up")
+	}
+}
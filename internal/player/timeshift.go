@@ -0,0 +1,151 @@
+package player
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// timeShiftAssumedKbps sizes the back buffer for stations that do not report
+// a bitrate. It is on the high side so the requested depth is never cut
+// short; the buffer only grows as audio actually arrives.
+const timeShiftAssumedKbps = 320
+
+// liveEdgeMargin keeps "go live" seeks slightly inside the cached range;
+// seeking exactly to the end makes mpv try a network seek, which live
+// streams do not support.
+const liveEdgeMargin = 0.5
+
+// timeShiftDepth holds the process-wide time-shift depth; zero means off.
+var timeShiftDepth atomic.Int64
+
+// SetTimeShift sets how much already-played audio players started from now
+// on keep for rewinding. Zero or a negative depth turns time-shift off.
+func SetTimeShift(depth time.Duration) {
+	timeShiftDepth.Store(int64(max(depth, 0)))
+}
+
+// timeShiftArgs returns the mpv flags that keep depth worth of audio at
+// kbps behind the playback position, plus room to keep filling ahead of it
+// while paused. The forward cache is never made smaller than the
+// configured bufferMB.
+func timeShiftArgs(depth time.Duration, kbps, bufferMB int) []string {
+	if kbps <= 0 {
+		kbps = timeShiftAssumedKbps
+	}
+	mb := int(math.Ceil(depth.Seconds() * float64(kbps) * 1000 / 8 / (1 << 20)))
+	mb = max(mb, 1)
+	return []string{
+		"--cache=yes",
+		"--force-seekable=yes",
+		fmt.Sprintf("--demuxer-max-back-bytes=%dM", mb),
+		fmt.Sprintf("--demuxer-max-bytes=%dM", max(mb, bufferMB)),
+	}
+}
+
+// TimeShiftStatus describes where playback is within the time-shift buffer.
+type TimeShiftStatus struct {
+	Behind   time.Duration // Distance from the live edge
+	Buffered time.Duration // Total audio available for rewinding and catching up
+}
+
+// seekableRange extracts the cached range from mpv's demuxer-cache-state.
+func seekableRange(state interface{}) (start, end float64, ok bool) {
+	m, _ := state.(map[string]interface{})
+	ranges, _ := m["seekable-ranges"].([]interface{})
+	if len(ranges) == 0 {
+		return 0, 0, false
+	}
+	// mpv lists ranges oldest first; the last one ends at the live edge.
+	r, _ := ranges[len(ranges)-1].(map[string]interface{})
+	start, okStart := r["start"].(float64)
+	end, okEnd := r["end"].(float64)
+	return start, end, okStart && okEnd && end >= start
+}
+
+// clampSeek returns the absolute position for a relative seek of delta
+// seconds from pos, kept within the cached range [start, end].
+func clampSeek(pos, start, end, delta float64) float64 {
+	target := pos + delta
+	if target < start {
+		target = start
+	}
+	if limit := end - liveEdgeMargin; target > limit {
+		target = max(limit, start)
+	}
+	return target
+}
+
+// TimeShiftEnabled reports whether the current stream was started with a
+// time-shift buffer.
+func (p *MPVPlayer) TimeShiftEnabled() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.playing && p.timeShift
+}
+
+// cachePosition reads the playback position and cached range from mpv.
+func (p *MPVPlayer) cachePosition() (pos, start, end float64, err error) {
+	if !p.TimeShiftEnabled() {
+		return 0, 0, 0, fmt.Errorf("time-shift is off")
+	}
+	state, err := p.getProperty("demuxer-cache-state")
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	start, end, ok := seekableRange(state)
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("nothing buffered yet")
+	}
+	v, err := p.getProperty("time-pos")
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	pos, ok = v.(float64)
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("no playback position yet")
+	}
+	return pos, start, end, nil
+}
+
+// TimeShiftStatus reports how far behind live playback is.
+func (p *MPVPlayer) TimeShiftStatus() (TimeShiftStatus, error) {
+	pos, start, end, err := p.cachePosition()
+	if err != nil {
+		return TimeShiftStatus{}, err
+	}
+	behind := max(end-pos, 0)
+	return TimeShiftStatus{
+		Behind:   time.Duration(behind * float64(time.Second)),
+		Buffered: time.Duration((end - start) * float64(time.Second)),
+	}, nil
+}
+
+// Seek moves playback by delta within the time-shift buffer. Seeking past
+// either end stops at the oldest buffered audio or at the live edge.
+func (p *MPVPlayer) Seek(delta time.Duration) error {
+	pos, start, end, err := p.cachePosition()
+	if err != nil {
+		return err
+	}
+	return p.seekAbsolute(clampSeek(pos, start, end, delta.Seconds()))
+}
+
+// JumpToLive seeks to the live edge of the time-shift buffer.
+func (p *MPVPlayer) JumpToLive() error {
+	pos, start, end, err := p.cachePosition()
+	if err != nil {
+		return err
+	}
+	return p.seekAbsolute(clampSeek(pos, start, end, end-pos))
+}
+
+func (p *MPVPlayer) seekAbsolute(target float64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.playing {
+		return fmt.Errorf("not playing")
+	}
+	return p.sendCommand([]interface{}{"seek", target, "absolute"})
+}
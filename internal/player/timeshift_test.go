@@ -0,0 +1,91 @@
+package player
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeShiftArgs_SizesBuffer(t *testing.T) {
+	// 10 minutes at 128 kbps is 9.6 MB, rounded up.
+	args := timeShiftArgs(10*time.Minute, 128, 0)
+	want := []string{"--cache=yes", "--force-seekable=yes", "--demuxer-max-back-bytes=10M", "--demuxer-max-bytes=10M"}
+	if len(args) != len(want) {
+		t.Fatalf("got %v, want %v", args, want)
+	}
+	for i := range want {
+		if args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, args[i], want[i])
+		}
+	}
+
+	// Unknown bitrate assumes a high one so the depth is not cut short.
+	if got := timeShiftArgs(10*time.Minute, 0, 0)[2]; got != "--demuxer-max-back-bytes=23M" {
+		t.Errorf("unknown bitrate: got %q", got)
+	}
+	if got := timeShiftArgs(time.Second, 64, 0)[2]; got != "--demuxer-max-back-bytes=1M" {
+		t.Errorf("tiny depth: got %q", got)
+	}
+
+	// A larger configured forward cache is kept, a smaller one grows.
+	if got := timeShiftArgs(10*time.Minute, 128, 50)[3]; got != "--demuxer-max-bytes=50M" {
+		t.Errorf("configured cache shrunk: got %q", got)
+	}
+	if got := timeShiftArgs(10*time.Minute, 128, 5)[3]; got != "--demuxer-max-bytes=10M" {
+		t.Errorf("small configured cache: got %q", got)
+	}
+}
+
+func TestSetTimeShift_NegativeIsOff(t *testing.T) {
+	t.Cleanup(func() { SetTimeShift(0) })
+	SetTimeShift(5 * time.Minute)
+	if got := time.Duration(timeShiftDepth.Load()); got != 5*time.Minute {
+		t.Errorf("got %v", got)
+	}
+	SetTimeShift(-time.Minute)
+	if got := timeShiftDepth.Load(); got != 0 {
+		t.Errorf("negative depth should turn time-shift off, got %d", got)
+	}
+}
+
+func TestSeekableRange_UsesLastRange(t *testing.T) {
+	state := map[string]interface{}{
+		"seekable-ranges": []interface{}{
+			map[string]interface{}{"start": 0.0, "end": 12.0},
+			map[string]interface{}{"start": 40.0, "end": 95.5},
+		},
+	}
+	start, end, ok := seekableRange(state)
+	if !ok || start != 40 || end != 95.5 {
+		t.Errorf("got %v-%v ok=%v", start, end, ok)
+	}
+
+	for _, bad := range []interface{}{nil, map[string]interface{}{}, map[string]interface{}{"seekable-ranges": []interface{}{}}} {
+		if _, _, ok := seekableRange(bad); ok {
+			t.Errorf("expected no range for %v", bad)
+		}
+	}
+}
+
+func TestClampSeek(t *testing.T) {
+	tests := []struct {
+		name             string
+		pos, delta, want float64
+	}{
+		{"rewind within buffer", 100, -10, 90},
+		{"rewind past oldest audio", 30, -60, 20},
+		{"forward within buffer", 50, 10, 60},
+		{"forward past live edge", 110, 60, 119.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clampSeek(tt.pos, 20, 120, tt.delta); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A range shorter than the margin never seeks before its start.
+	if got := clampSeek(10, 10, 10.2, 5); got != 10 {
+		t.Errorf("short range: got %v", got)
+	}
+}
@@ -0,0 +1,198 @@
+package player
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// usagePollInterval is how often players measure how much they downloaded.
+const usagePollInterval = 5 * time.Second
+
+// ErrDataCapReached is returned by Play when the monthly data cap has been
+// used up and the cap stops playback.
+var ErrDataCapReached = errors.New("monthly data cap reached")
+
+// DataCap limits how much stream data all players may download in a
+// calendar month. Usage is read from the player's metadata manager, so
+// players without one are not limited.
+type DataCap struct {
+	Limit  int64 // Bytes allowed per month
+	WarnAt int64 // Bytes after which players report a warning
+	Stop   bool  // Stop playback, and refuse to start it, at the limit
+}
+
+var dataCap atomic.Pointer[DataCap]
+
+// SetDataCap installs c for every player. Pass nil to remove the cap.
+func SetDataCap(c *DataCap) {
+	dataCap.Store(c)
+}
+
+// DataCapHandler is called when a player passes the warning level or stops
+// at the cap. Like PlaybackObserver it runs with the player's lock held, so
+// it must return quickly and must not call back into the player.
+type DataCapHandler func(p *MPVPlayer, stopped bool)
+
+var dataCapHandler atomic.Pointer[DataCapHandler]
+
+// SetDataCapHandler installs fn as the process-wide data cap handler. Pass
+// nil to remove it.
+func SetDataCapHandler(fn DataCapHandler) {
+	if fn == nil {
+		dataCapHandler.Store(nil)
+		return
+	}
+	dataCapHandler.Store(&fn)
+}
+
+func notifyDataCap(p *MPVPlayer, stopped bool) {
+	if fn := dataCapHandler.Load(); fn != nil {
+		(*fn)(p, stopped)
+	}
+}
+
+// VariantPicker returns the stream to play in place of station, such as a
+// lower-bitrate variant, or nil to play station itself. Play calls it before
+// taking the player's lock, so it may block briefly on the network.
+type VariantPicker func(station *api.Station) *api.Station
+
+var variantPicker atomic.Pointer[VariantPicker]
+
+// SetVariantPicker installs fn for every player. Pass nil to remove it.
+func SetVariantPicker(fn VariantPicker) {
+	if fn == nil {
+		variantPicker.Store(nil)
+		return
+	}
+	variantPicker.Store(&fn)
+}
+
+// pickVariant applies the installed VariantPicker to station.
+func pickVariant(station *api.Station) *api.Station {
+	if fn := variantPicker.Load(); fn != nil {
+		if variant := (*fn)(station); variant != nil {
+			return variant
+		}
+	}
+	return station
+}
+
+// DataUsage is this month's download total measured against the cap.
+type DataUsage struct {
+	Used    int64
+	Limit   int64
+	Warning bool // Used has passed the warning level
+	Reached bool // Used has reached the limit
+	Stops   bool // Playback stops at the limit
+}
+
+// DataUsage reports this month's usage against the installed cap. ok is
+// false when there is no cap or the player does not record statistics.
+func (p *MPVPlayer) DataUsage() (usage DataUsage, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.dataUsageLocked()
+}
+
+// dataUsageLocked implements DataUsage. Caller must hold p.mu.
+func (p *MPVPlayer) dataUsageLocked() (DataUsage, bool) {
+	c := dataCap.Load()
+	if c == nil || c.Limit <= 0 || p.metadataManager == nil {
+		return DataUsage{}, false
+	}
+	used := p.metadataManager.MonthBytes(storage.UsageMonth(time.Now()))
+	return DataUsage{
+		Used:    used,
+		Limit:   c.Limit,
+		Warning: used >= c.WarnAt,
+		Reached: used >= c.Limit,
+		Stops:   c.Stop,
+	}, true
+}
+
+// checkDataCapLocked refuses to start a stream once a stopping cap has been
+// reached. Caller must hold p.mu.
+func (p *MPVPlayer) checkDataCapLocked() error {
+	if usage, ok := p.dataUsageLocked(); ok && usage.Reached && usage.Stops {
+		return fmt.Errorf("%w (%s of %s used)", ErrDataCapReached,
+			storage.FormatBytes(usage.Used), storage.FormatBytes(usage.Limit))
+	}
+	return nil
+}
+
+// SessionBytes returns how much the current stream has downloaded.
+func (p *MPVPlayer) SessionBytes() int64 {
+	return p.sessionBytes.Load()
+}
+
+// monitorUsage adds up what the stream downloads until stopCh closes.
+func (p *MPVPlayer) monitorUsage(stopCh chan struct{}, station *api.Station) {
+	ticker := time.NewTicker(usagePollInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case now := <-ticker.C:
+			elapsed := now.Sub(last)
+			last = now
+			if n := int64(p.inputRate() * elapsed.Seconds()); n > 0 {
+				p.recordUsage(stopCh, station, n)
+			}
+		}
+	}
+}
+
+// inputRate estimates how fast mpv is downloading, in bytes per second. It
+// prefers the rate the demuxer measures on the network and falls back to the
+// audio bitrate while playing; it returns 0 when mpv cannot be asked.
+func (p *MPVPlayer) inputRate() float64 {
+	if state, err := p.getProperty("demuxer-cache-state"); err == nil {
+		if m, ok := state.(map[string]interface{}); ok {
+			if rate, ok := m["raw-input-rate"].(float64); ok {
+				return rate
+			}
+		}
+	}
+	if p.IsPaused() {
+		return 0
+	}
+	if bitrate, err := p.GetAudioBitrate(); err == nil && bitrate > 0 {
+		return float64(bitrate) / 8
+	}
+	return 0
+}
+
+// recordUsage counts n downloaded bytes and applies the data cap.
+func (p *MPVPlayer) recordUsage(stopCh chan struct{}, station *api.Station, n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Ignore a sample that raced with Stop or a newer stream
+	if p.stopCh != stopCh || !p.playing {
+		return
+	}
+	p.sessionBytes.Add(n)
+	if p.metadataManager == nil {
+		return
+	}
+	p.metadataManager.AddBytes(station.StationUUID, n)
+
+	usage, ok := p.dataUsageLocked()
+	switch {
+	case !ok:
+	case usage.Reached && usage.Stops:
+		_ = p.stopInternal(storage.StopReasonDataCap)
+		notifyDataCap(p, true)
+	case usage.Warning && !p.capWarned:
+		p.capWarned = true
+		notifyDataCap(p, false)
+	}
+}
@@ -0,0 +1,119 @@
+package player
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+func TestInputRate(t *testing.T) {
+	p, fake := newAdTestPlayer(t, nil, &api.Station{StationUUID: "st-1"})
+
+	fake.mu.Lock()
+	fake.props = map[string]interface{}{
+		"demuxer-cache-state": map[string]interface{}{"raw-input-rate": 16000.0},
+		"audio-bitrate":       128000.0,
+	}
+	fake.mu.Unlock()
+	if got := p.inputRate(); got != 16000 {
+		t.Errorf("expected the demuxer's network rate, got %v", got)
+	}
+
+	fake.mu.Lock()
+	delete(fake.props, "demuxer-cache-state")
+	fake.mu.Unlock()
+	if got := p.inputRate(); got != 16000 {
+		t.Errorf("expected 128 kbps from the audio bitrate, got %v", got)
+	}
+
+	p.mu.Lock()
+	p.paused = true
+	p.mu.Unlock()
+	if got := p.inputRate(); got != 0 {
+		t.Errorf("a paused stream without cache statistics should count nothing, got %v", got)
+	}
+}
+
+func TestRecordUsage_WarnsThenStopsAtCap(t *testing.T) {
+	mgr, err := storage.NewMetadataManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewMetadataManager: %v", err)
+	}
+	defer func() { _ = mgr.Close() }()
+
+	SetDataCap(&DataCap{Limit: 1000, WarnAt: 500, Stop: true})
+	defer SetDataCap(nil)
+	var mu sync.Mutex
+	var events []bool
+	SetDataCapHandler(func(_ *MPVPlayer, stopped bool) {
+		mu.Lock()
+		events = append(events, stopped)
+		mu.Unlock()
+	})
+	defer SetDataCapHandler(nil)
+
+	station := &api.Station{StationUUID: "st-1"}
+	p := NewMPVPlayer()
+	p.SetMetadataManager(mgr)
+	p.mu.Lock()
+	p.playing = true
+	p.station = station
+	stopCh := p.stopCh
+	p.mu.Unlock()
+
+	p.recordUsage(stopCh, station, 600)
+	p.recordUsage(stopCh, station, 100) // warns only once
+	if usage, ok := p.DataUsage(); !ok || !usage.Warning || usage.Reached || usage.Used != 700 {
+		t.Errorf("expected a warning at 700 bytes, got %+v %v", usage, ok)
+	}
+	if p.SessionBytes() != 700 {
+		t.Errorf("SessionBytes = %d, want 700", p.SessionBytes())
+	}
+
+	p.recordUsage(stopCh, station, 400)
+	if p.IsPlaying() {
+		t.Error("playback should stop at the cap")
+	}
+	mu.Lock()
+	if len(events) != 2 || events[0] || !events[1] {
+		t.Errorf("expected a warning then a stop, got %v", events)
+	}
+	mu.Unlock()
+
+	if err := p.Play(station); !errors.Is(err, ErrDataCapReached) {
+		t.Errorf("Play at the cap should fail with ErrDataCapReached, got %v", err)
+	}
+
+	// A sample that races with the stop is ignored
+	p.recordUsage(stopCh, station, 1)
+	if got := mgr.MonthBytes(storage.UsageMonth(time.Now())); got != 1100 {
+		t.Errorf("MonthBytes = %d, want 1100", got)
+	}
+}
+
+func TestPickVariant(t *testing.T) {
+	station := &api.Station{StationUUID: "hq", Bitrate: 320}
+	if got := pickVariant(station); got != station {
+		t.Error("without a picker the station itself should play")
+	}
+
+	variant := &api.Station{StationUUID: "lq", Bitrate: 64}
+	SetVariantPicker(func(st *api.Station) *api.Station {
+		if st.Bitrate > 64 {
+			return variant
+		}
+		return nil
+	})
+	defer SetVariantPicker(nil)
+
+	if got := pickVariant(station); got != variant {
+		t.Errorf("expected the low-bitrate variant, got %+v", got)
+	}
+	if got := pickVariant(variant); got != variant {
+		t.Errorf("a nil pick should keep the station, got %+v", got)
+	}
+}
@@ -0,0 +1,224 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// lookupTimeout bounds the Radio Browser lookups for one entry.
+const lookupTimeout = 10 * time.Second
+
+// StationFinder looks streams up in Radio Browser. *api.Client implements
+// it.
+type StationFinder interface {
+	SearchByURL(ctx context.Context, streamURL string) ([]api.Station, error)
+	SearchByName(ctx context.Context, name string) ([]api.Station, error)
+}
+
+// ResolveResult is the outcome of matching playlist entries to stations.
+type ResolveResult struct {
+	Stations  []api.Station
+	ByURL     int   // Matched a Radio Browser station by stream URL
+	ByName    int   // Matched a Radio Browser station by exact name
+	Custom    int   // Kept as custom stations
+	LookupErr error // First lookup failure; later entries were not looked up
+}
+
+// Resolve turns entries into stations. Each entry is looked up in Radio
+// Browser by stream URL, then by exact name; entries that match nothing
+// are kept as custom stations. A nil finder keeps every entry as a custom
+// station, as does a lookup failure for it and the entries after it.
+func Resolve(ctx context.Context, finder StationFinder, entries []Entry) ResolveResult {
+	var r ResolveResult
+	for _, e := range entries {
+		if finder != nil && r.LookupErr == nil {
+			station, byURL, err := lookup(ctx, finder, e)
+			if err != nil {
+				r.LookupErr = err
+			} else if station != nil {
+				if byURL {
+					r.ByURL++
+				} else {
+					r.ByName++
+				}
+				r.Stations = append(r.Stations, *station)
+				continue
+			}
+		}
+		r.Custom++
+		r.Stations = append(r.Stations, CustomStation(e))
+	}
+	return r
+}
+
+// lookup finds the Radio Browser station for e, reporting whether it
+// matched by URL. It returns a nil station when nothing matches.
+func lookup(ctx context.Context, finder StationFinder, e Entry) (*api.Station, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	stations, err := finder.SearchByURL(ctx, e.URL)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(stations) > 0 {
+		return &stations[0], true, nil
+	}
+
+	name := strings.TrimSpace(e.Name)
+	if name == "" {
+		return nil, false, nil
+	}
+	stations, err = finder.SearchByName(ctx, name)
+	if err != nil {
+		return nil, false, err
+	}
+	// Results come most-voted first; only an exact name counts as a match
+	for i := range stations {
+		if strings.EqualFold(stations[i].OriginalName(), name) {
+			return &stations[i], false, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// CustomStation returns the station kept for an entry that is not in Radio
+// Browser.
+func CustomStation(e Entry) api.Station {
+	name := strings.TrimSpace(e.Name)
+	if name == "" {
+		name = e.URL
+	}
+	return api.Station{
+		StationUUID: api.CustomStationUUID(e.URL),
+		Name:        name,
+		URLResolved: e.URL,
+	}
+}
+
+// FromStations returns the playlist entries for stations.
+func FromStations(stations []api.Station) []Entry {
+	entries := make([]Entry, 0, len(stations))
+	for _, s := range stations {
+		if s.URLResolved == "" {
+			continue
+		}
+		entries = append(entries, Entry{Name: s.TrimName(), URL: s.URLResolved})
+	}
+	return entries
+}
+
+// ExportList writes the favorites list name to path in format and returns
+// how many stations were written.
+func ExportList(ctx context.Context, store *storage.Storage, name, path, format string) (int, error) {
+	list, err := store.LoadList(ctx, name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, fmt.Errorf("list %q not found", name)
+		}
+		return 0, fmt.Errorf("failed to load list %q: %w", name, err)
+	}
+	p := Playlist{Title: name, Entries: FromStations(list.Stations)}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := Write(f, p, format); err != nil {
+		_ = f.Close()
+		return 0, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+	return len(p.Entries), nil
+}
+
+// ImportResult is the outcome of importing a playlist into a list.
+type ImportResult struct {
+	ResolveResult
+	List       string
+	Added      int
+	Duplicates int // Already in the list
+}
+
+// ImportFile reads the playlist at path and adds its streams to the
+// favorites list name, creating it if needed. An empty format is guessed
+// from the file; an empty name is derived from the playlist, while a given
+// one must be a valid, possibly nested, list name.
+func ImportFile(ctx context.Context, store *storage.Storage, finder StationFinder, path, format, name string) (ImportResult, error) {
+	if name != "" {
+		if err := storage.ValidateListName(name); err != nil {
+			return ImportResult{}, err
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if format == "" {
+		format = FormatFromPath(path)
+	}
+	p, err := Read(f, format)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	if len(p.Entries) == 0 {
+		return ImportResult{}, fmt.Errorf("no streams found in %s", filepath.Base(path))
+	}
+	if name == "" {
+		name = ListName(p.Title, path)
+	}
+
+	r := ImportResult{ResolveResult: Resolve(ctx, finder, p.Entries), List: name}
+	r.Added, err = store.AddStations(ctx, name, r.Stations)
+	if err != nil {
+		return r, err
+	}
+	r.Duplicates = len(r.Stations) - r.Added
+	return r, nil
+}
+
+// ListName returns a favorites list name for an imported playlist: its
+// title, or else the file name, with spaces replaced by hyphens as when a
+// list is renamed. A name that isn't a valid list name, such as a hidden or
+// reserved one, becomes "Imported".
+func ListName(title, path string) string {
+	name := strings.TrimSpace(title)
+	if name == "" {
+		base := filepath.Base(path)
+		name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.Join(strings.Fields(name), "-")
+	if storage.ValidateListName(name) != nil {
+		return "Imported"
+	}
+	return name
+}
+
+// DefaultExportPath returns ~/tera-<list>.<format>, with the folders of a
+// nested list joined by hyphens ("Jazz/Smooth" becomes tera-Jazz-Smooth).
+func DefaultExportPath(name, format string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	name = strings.ReplaceAll(name, "/", "-")
+	return filepath.Join(home, fmt.Sprintf("tera-%s.%s", name, format)), nil
+}
@@ -0,0 +1,152 @@
+package playlist
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// fakeFinder serves lookups from maps and counts the calls.
+type fakeFinder struct {
+	byURL  map[string]api.Station
+	byName map[string][]api.Station
+	err    error
+	calls  int
+}
+
+func (f *fakeFinder) SearchByURL(_ context.Context, u string) ([]api.Station, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	if s, ok := f.byURL[u]; ok {
+		return []api.Station{s}, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeFinder) SearchByName(_ context.Context, name string) ([]api.Station, error) {
+	f.calls++
+	return f.byName[name], nil
+}
+
+func TestResolve(t *testing.T) {
+	finder := &fakeFinder{
+		byURL: map[string]api.Station{"http://jazz.example": {StationUUID: "rb-jazz", Name: "Jazz FM"}},
+		byName: map[string][]api.Station{
+			// A partial match comes first but only the exact name counts
+			"Swiss Jazz": {{StationUUID: "rb-other", Name: "Swiss Jazz Classics"}, {StationUUID: "rb-swiss", Name: "swiss jazz"}},
+		},
+	}
+	entries := []Entry{
+		{Name: "Jazz", URL: "http://jazz.example"},
+		{Name: "Swiss Jazz", URL: "http://old-swiss.example"},
+		{Name: "Pirate", URL: "http://pirate.example"},
+		{URL: "http://nameless.example"},
+	}
+	r := Resolve(context.Background(), finder, entries)
+	if r.ByURL != 1 || r.ByName != 1 || r.Custom != 2 || r.LookupErr != nil {
+		t.Fatalf("unexpected counts: %+v", r)
+	}
+	if r.Stations[0].StationUUID != "rb-jazz" || r.Stations[1].StationUUID != "rb-swiss" {
+		t.Errorf("unexpected matches: %+v", r.Stations[:2])
+	}
+	pirate := r.Stations[2]
+	if !pirate.IsCustom() || pirate.Name != "Pirate" || pirate.URLResolved != "http://pirate.example" {
+		t.Errorf("unexpected custom station: %+v", pirate)
+	}
+	if pirate.StationUUID != api.CustomStationUUID("http://pirate.example") {
+		t.Error("custom station UUIDs should be stable")
+	}
+	if r.Stations[3].Name != "http://nameless.example" {
+		t.Errorf("a nameless stream should be named after its URL, got %q", r.Stations[3].Name)
+	}
+}
+
+func TestResolve_StopsLookingUpAfterAnError(t *testing.T) {
+	finder := &fakeFinder{err: errors.New("offline")}
+	r := Resolve(context.Background(), finder, []Entry{{URL: "http://a.example"}, {URL: "http://b.example"}})
+	if r.LookupErr == nil || r.Custom != 2 || finder.calls != 1 {
+		t.Errorf("expected one failed lookup and two custom stations, got %+v after %d call(s)", r, finder.calls)
+	}
+}
+
+func TestImportAndExport(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewStorage(filepath.Join(dir, "favorites"))
+	ctx := context.Background()
+
+	path := filepath.Join(dir, "Late Night Radio.pls")
+	data := "[playlist]\nFile1=http://jazz.example\nTitle1=Jazz\nFile2=http://pirate.example\nTitle2=Pirate\nNumberOfEntries=2\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	finder := &fakeFinder{byURL: map[string]api.Station{"http://jazz.example": {StationUUID: "rb-jazz", Name: "Jazz FM", URLResolved: "http://jazz.example"}}}
+
+	r, err := ImportFile(ctx, store, finder, path, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.List != "Late-Night-Radio" || r.Added != 2 || r.ByURL != 1 || r.Custom != 1 {
+		t.Errorf("unexpected import: %+v", r)
+	}
+	// Importing again adds nothing
+	if r, err = ImportFile(ctx, store, nil, path, "", r.List); err != nil || r.Added != 0 || r.Duplicates != 2 {
+		t.Errorf("unexpected second import: %+v, %v", r, err)
+	}
+
+	out := filepath.Join(dir, "out.m3u")
+	n, err := ExportList(ctx, store, "Late-Night-Radio", out, FormatM3U)
+	if err != nil || n != 2 {
+		t.Fatalf("ExportList = %d, %v", n, err)
+	}
+	exported, _ := os.ReadFile(out)
+	if !strings.Contains(string(exported), "#EXTINF:-1,Jazz FM\nhttp://jazz.example\n#EXTINF:-1,Pirate\nhttp://pirate.example\n") {
+		t.Errorf("unexpected export:\n%s", exported)
+	}
+	if _, err := ExportList(ctx, store, "Missing", out, FormatM3U); err == nil {
+		t.Error("expected an error for a missing list")
+	}
+
+	// An explicit list name may be nested, but is not cleaned up
+	if r, err = ImportFile(ctx, store, nil, path, "", "Jazz/Late Night"); err != nil || r.List != "Jazz/Late Night" || r.Added != 2 {
+		t.Errorf("unexpected nested import: %+v, %v", r, err)
+	}
+	for _, name := range []string{"../outside", "Jazz/", `a\b`} {
+		if _, err := ImportFile(ctx, store, nil, path, "", name); !errors.Is(err, storage.ErrInvalidListName) {
+			t.Errorf("ImportFile into %q: expected ErrInvalidListName, got %v", name, err)
+		}
+	}
+}
+
+func TestDefaultExportPath(t *testing.T) {
+	path, err := DefaultExportPath("Jazz/Smooth", FormatM3U)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := filepath.Base(path); got != "tera-Jazz-Smooth.m3u" {
+		t.Errorf("DefaultExportPath = %q, want tera-Jazz-Smooth.m3u", got)
+	}
+}
+
+func TestListName(t *testing.T) {
+	for _, tt := range []struct{ title, path, want string }{
+		{"My Jazz", "/x/y.m3u", "My-Jazz"},
+		{"", "/x/Road trip.xspf", "Road-trip"},
+		{"a/b: c", "", "ab-c"},
+		{"", "/x/..m3u", "Imported"},
+		{"search history", "/x/y.m3u", "Imported"},
+		{"", "/x/search-history.pls", "Imported"},
+		{".hidden", "/x/y.m3u", "Imported"},
+	} {
+		if got := ListName(tt.title, tt.path); got != tt.want {
+			t.Errorf("ListName(%q, %q) = %q, want %q", tt.title, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+// Package playlist reads and writes favorites lists as M3U, PLS, XSPF and
+// OPML playlists so they can be shared with other players and radio apps.
+package playlist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shinokada/tera/v3/internal/player"
+)
+
+// Playlist formats.
+const (
+	FormatM3U  = "m3u"
+	FormatPLS  = "pls"
+	FormatXSPF = "xspf"
+	FormatOPML = "opml"
+)
+
+// Formats lists the supported formats in menu order.
+var Formats = []string{FormatM3U, FormatPLS, FormatXSPF, FormatOPML}
+
+// maxPlaylistSize caps how much of a playlist file is read.
+const maxPlaylistSize = 10 << 20
+
+// Entry is one stream in a playlist.
+type Entry struct {
+	Name string
+	URL  string
+}
+
+// Playlist is a titled list of streams.
+type Playlist struct {
+	Title   string
+	Entries []Entry
+}
+
+// IsFormat reports whether format is a supported playlist format.
+func IsFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatFromPath guesses the format from a file extension. It returns ""
+// when the extension is not a playlist one.
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".m3u", ".m3u8":
+		return FormatM3U
+	case ".pls":
+		return FormatPLS
+	case ".xspf":
+		return FormatXSPF
+	case ".opml":
+		return FormatOPML
+	}
+	return ""
+}
+
+// sniff guesses the format from the start of a playlist.
+func sniff(data []byte) string {
+	head := strings.ToLower(string(bytes.TrimSpace(data[:min(len(data), 512)])))
+	head = strings.TrimPrefix(head, "\ufeff")
+	switch {
+	case strings.HasPrefix(head, "[playlist]"):
+		return FormatPLS
+	case strings.Contains(head, "<opml"):
+		return FormatOPML
+	case strings.Contains(head, "<playlist"):
+		return FormatXSPF
+	}
+	// M3U files often have no header at all, just one URL per line
+	return FormatM3U
+}
+
+// Read parses a playlist. An empty format is detected from the content.
+// Entries without a stream URL, such as local files, are left out.
+func Read(r io.Reader, format string) (*Playlist, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxPlaylistSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read playlist: %w", err)
+	}
+	if format == "" {
+		format = sniff(data)
+	}
+
+	var p *Playlist
+	switch format {
+	case FormatM3U:
+		p, err = readM3U(data)
+	case FormatPLS:
+		p, err = readPLS(data)
+	case FormatXSPF:
+		p, err = readXSPF(data)
+	case FormatOPML:
+		p, err = readOPML(data)
+	default:
+		return nil, fmt.Errorf("unknown playlist format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := p.Entries[:0]
+	for _, e := range p.Entries {
+		e.Name = oneLine(e.Name)
+		e.URL = strings.TrimSpace(e.URL)
+		if isStreamURL(e.URL) {
+			entries = append(entries, e)
+		}
+	}
+	p.Entries = entries
+	p.Title = oneLine(p.Title)
+	return p, nil
+}
+
+// isStreamURL reports whether u is a network stream the player opens rather
+// than a file.
+func isStreamURL(u string) bool {
+	scheme, _, ok := strings.Cut(u, "://")
+	return ok && player.IsStreamScheme(scheme)
+}
+
+// Write renders p in format.
+func Write(w io.Writer, p Playlist, format string) error {
+	switch format {
+	case FormatM3U:
+		return writeM3U(w, p)
+	case FormatPLS:
+		return writePLS(w, p)
+	case FormatXSPF:
+		return writeXSPF(w, p)
+	case FormatOPML:
+		return writeOPML(w, p)
+	}
+	return fmt.Errorf("unknown playlist format %q", format)
+}
+
+// oneLine keeps a name from breaking a line-based format.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func readM3U(data []byte) (*Playlist, error) {
+	p := &Playlist{}
+	var name string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#EXTINF:"):
+			// #EXTINF:-1 tvg-logo="...",Station Name — the name follows the
+			// first comma outside quotes
+			name = extinfName(strings.TrimPrefix(line, "#EXTINF:"))
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			p.Title = strings.TrimPrefix(line, "#PLAYLIST:")
+		case strings.HasPrefix(line, "#"):
+		default:
+			p.Entries = append(p.Entries, Entry{Name: name, URL: line})
+			name = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read playlist: %w", err)
+	}
+	return p, nil
+}
+
+func extinfName(info string) string {
+	inQuotes := false
+	for i, r := range info {
+		switch r {
+		case '"':
+			inQuotes = !inQuotes
+		case ',':
+			if !inQuotes {
+				return info[i+1:]
+			}
+		}
+	}
+	return ""
+}
+
+func writeM3U(w io.Writer, p Playlist) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "#EXTM3U")
+	if p.Title != "" {
+		fmt.Fprintf(bw, "#PLAYLIST:%s\n", oneLine(p.Title))
+	}
+	for _, e := range p.Entries {
+		fmt.Fprintf(bw, "#EXTINF:-1,%s\n%s\n", oneLine(e.Name), e.URL)
+	}
+	return bw.Flush()
+}
+
+func readPLS(data []byte) (*Playlist, error) {
+	files := make(map[int]string)
+	titles := make(map[int]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if n, ok := plsIndex(key, "file"); ok {
+			files[n] = value
+		} else if n, ok := plsIndex(key, "title"); ok {
+			titles[n] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read playlist: %w", err)
+	}
+	numbers := make([]int, 0, len(files))
+	for n := range files {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	p := &Playlist{}
+	for _, n := range numbers {
+		p.Entries = append(p.Entries, Entry{Name: titles[n], URL: files[n]})
+	}
+	return p, nil
+}
+
+// plsIndex returns N for a key such as "file3" when it starts with prefix.
+func plsIndex(key, prefix string) (int, bool) {
+	if !strings.HasPrefix(key, prefix) {
+		return 0, false
+	}
+	n, err := strconv.Atoi(key[len(prefix):])
+	return n, err == nil
+}
+
+func writePLS(w io.Writer, p Playlist) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "[playlist]")
+	for i, e := range p.Entries {
+		fmt.Fprintf(bw, "File%d=%s\nTitle%d=%s\nLength%d=-1\n", i+1, e.URL, i+1, oneLine(e.Name), i+1)
+	}
+	fmt.Fprintf(bw, "NumberOfEntries=%d\nVersion=2\n", len(p.Entries))
+	return bw.Flush()
+}
+
+// xspfPlaylist is the subset of XSPF that TERA reads and writes.
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"playlist"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Version string      `xml:"version,attr"`
+	Title   string      `xml:"title,omitempty"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Locations []string `xml:"location"`
+	Title     string   `xml:"title,omitempty"`
+}
+
+func readXSPF(data []byte) (*Playlist, error) {
+	var doc xspfPlaylist
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid XSPF playlist: %w", err)
+	}
+	p := &Playlist{Title: doc.Title}
+	for _, t := range doc.Tracks {
+		for _, loc := range t.Locations {
+			// The first location that is a stream wins
+			if isStreamURL(strings.TrimSpace(loc)) {
+				p.Entries = append(p.Entries, Entry{Name: t.Title, URL: loc})
+				break
+			}
+		}
+	}
+	return p, nil
+}
+
+func writeXSPF(w io.Writer, p Playlist) error {
+	doc := xspfPlaylist{Xmlns: "http://xspf.org/ns/0/", Version: "1", Title: p.Title}
+	for _, e := range p.Entries {
+		doc.Tracks = append(doc.Tracks, xspfTrack{Locations: []string{e.URL}, Title: oneLine(e.Name)})
+	}
+	return writeXML(w, doc)
+}
+
+// opmlDoc is an OPML 2.0 outline of stations, as used by radio directories
+// and podcast apps.
+type opmlDoc struct {
+	XMLName xml.Name      `xml:"opml"`
+	Version string        `xml:"version,attr"`
+	Title   string        `xml:"head>title,omitempty"`
+	Body    []opmlOutline `xml:"body>outline"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	URL      string        `xml:"URL,attr,omitempty"`
+	LowerURL string        `xml:"url,attr,omitempty"`
+	Children []opmlOutline `xml:"outline"`
+}
+
+func readOPML(data []byte) (*Playlist, error) {
+	var doc opmlDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid OPML file: %w", err)
+	}
+	p := &Playlist{Title: doc.Title}
+	var walk func([]opmlOutline)
+	walk = func(outlines []opmlOutline) {
+		for _, o := range outlines {
+			u := o.URL
+			if u == "" {
+				u = o.LowerURL
+			}
+			// "link" outlines point at more OPML, not at a stream
+			if u != "" && !strings.EqualFold(o.Type, "link") {
+				name := o.Text
+				if name == "" {
+					name = o.Title
+				}
+				p.Entries = append(p.Entries, Entry{Name: name, URL: u})
+			}
+			walk(o.Children)
+		}
+	}
+	walk(doc.Body)
+	return p, nil
+}
+
+func writeOPML(w io.Writer, p Playlist) error {
+	doc := opmlDoc{Version: "2.0", Title: p.Title}
+	for _, e := range p.Entries {
+		doc.Body = append(doc.Body, opmlOutline{Text: oneLine(e.Name), Type: "audio", URL: e.URL})
+	}
+	return writeXML(w, doc)
+}
+
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,132 @@
+package playlist
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name, format, data string
+		title              string
+		want               []Entry
+	}{
+		{
+			name:   "m3u",
+			format: FormatM3U,
+			data: "\ufeff#EXTM3U\n#PLAYLIST:Jazz\n#EXTINF:-1 tvg-name=\"a, b\",Jazz FM\nhttp://jazz.example/live\n" +
+				"# a comment\n/home/me/music/song.mp3\nhttps://bare.example/stream\n",
+			title: "Jazz",
+			want:  []Entry{{Name: "Jazz FM", URL: "http://jazz.example/live"}, {URL: "https://bare.example/stream"}},
+		},
+		{
+			name:   "pls",
+			format: FormatPLS,
+			data:   "[playlist]\nFile2=http://two.example\nTitle2=Two\nfile1=http://one.example\ntitle1=One\nLength1=-1\nNumberOfEntries=2\n",
+			want:   []Entry{{Name: "One", URL: "http://one.example"}, {Name: "Two", URL: "http://two.example"}},
+		},
+		{
+			name:   "xspf",
+			format: FormatXSPF,
+			data: `<?xml version="1.0"?><playlist version="1" xmlns="http://xspf.org/ns/0/"><title>Mix</title><trackList>` +
+				`<track><location>file:///song.ogg</location><location>http://x.example/a</location><title>A &amp; B</title></track>` +
+				`<track><location>file:///only-local.ogg</location></track></trackList></playlist>`,
+			title: "Mix",
+			want:  []Entry{{Name: "A & B", URL: "http://x.example/a"}},
+		},
+		{
+			name:   "opml",
+			format: FormatOPML,
+			data: `<opml version="1.0"><head><title>Radio</title></head><body><outline text="News">` +
+				`<outline type="audio" text="BBC" URL="http://bbc.example/ws"/>` +
+				`<outline type="link" text="More" URL="http://dir.example/more.opml"/>` +
+				`<outline text="Lower" url="http://lower.example"/></outline></body></opml>`,
+			title: "Radio",
+			want:  []Entry{{Name: "BBC", URL: "http://bbc.example/ws"}, {Name: "Lower", URL: "http://lower.example"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, format := range []string{tt.format, ""} {
+				p, err := Read(strings.NewReader(tt.data), format)
+				if err != nil {
+					t.Fatalf("Read(%q): %v", format, err)
+				}
+				if p.Title != tt.title {
+					t.Errorf("Read(%q) title = %q, want %q", format, p.Title, tt.title)
+				}
+				if len(p.Entries) != len(tt.want) {
+					t.Fatalf("Read(%q) = %+v, want %+v", format, p.Entries, tt.want)
+				}
+				for i := range tt.want {
+					if p.Entries[i] != tt.want[i] {
+						t.Errorf("Read(%q) entry %d = %+v, want %+v", format, i, p.Entries[i], tt.want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	in := Playlist{Title: "My <Jazz> & more", Entries: []Entry{
+		{Name: "Jazz\nFM", URL: "http://jazz.example/live?a=1&b=2"},
+		{Name: "Swiss Jazz", URL: "https://swiss.example/jazz"},
+	}}
+	for _, format := range Formats {
+		var buf bytes.Buffer
+		if err := Write(&buf, in, format); err != nil {
+			t.Fatalf("Write(%s): %v", format, err)
+		}
+		out, err := Read(&buf, "")
+		if err != nil {
+			t.Fatalf("Read(%s): %v", format, err)
+		}
+		if len(out.Entries) != 2 || out.Entries[0].Name != "Jazz FM" || out.Entries[0].URL != in.Entries[0].URL {
+			t.Errorf("%s round trip lost entries: %+v", format, out.Entries)
+		}
+		if format != FormatPLS && out.Title != in.Title {
+			t.Errorf("%s round trip title = %q", format, out.Title)
+		}
+	}
+	if err := Write(&bytes.Buffer{}, in, "wpl"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	for path, want := range map[string]string{
+		"a.M3U8": FormatM3U, "b.pls": FormatPLS, "c.xspf": FormatXSPF, "d.opml": FormatOPML, "e.json": "",
+	} {
+		if got := FormatFromPath(path); got != want {
+			t.Errorf("FormatFromPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestIsStreamURL(t *testing.T) {
+	// The schemes the player opens, and no others
+	for u, want := range map[string]bool{
+		"http://a/s": true, "HTTPS://a/s": true, "rtsp://a/s": true, "rtmps://a/s": true, "rtsps://a/s": true,
+		"mms://a/s": false, "mmsh://a/s": false, "icyx://a/s": false, "file:///tmp/s.mp3": false, "s.mp3": false,
+	} {
+		if got := isStreamURL(u); got != want {
+			t.Errorf("isStreamURL(%q) = %v, want %v", u, got, want)
+		}
+	}
+}
+
+func TestRead_LineTooLong(t *testing.T) {
+	long := strings.Repeat("x", bufio.MaxScanTokenSize+1)
+	for format, data := range map[string]string{
+		FormatM3U: "#EXTM3U\n#EXTINF:-1,A\nhttp://a/s\n#EXTINF:-1," + long + "\nhttp://b/s\n",
+		FormatPLS: "[playlist]\nFile1=http://a/s\nTitle2=" + long + "\nFile2=http://b/s\n",
+	} {
+		if _, err := Read(strings.NewReader(data), format); !errors.Is(err, bufio.ErrTooLong) {
+			t.Errorf("%s: expected bufio.ErrTooLong instead of a partial import, got %v", format, err)
+		}
+	}
+}
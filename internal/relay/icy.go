@@ -0,0 +1,155 @@
+package relay
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMetaInt is the interval, in audio bytes, at which ICY metadata blocks
+// are injected for listeners that request them. 16000 matches Icecast and
+// SHOUTcast defaults, which keeps older clients happy.
+const DefaultMetaInt = 16000
+
+// maxMetaBlocks is the largest metadata length byte: 255 × 16 bytes.
+const maxMetaBlocks = 255
+
+// icyReader strips in-band ICY metadata from an upstream stream, returning
+// only audio bytes and reporting each StreamTitle through onTitle.
+type icyReader struct {
+	r         io.Reader
+	metaInt   int
+	remaining int
+	onTitle   func(string)
+}
+
+func newICYReader(r io.Reader, metaInt int, onTitle func(string)) *icyReader {
+	return &icyReader{r: r, metaInt: metaInt, remaining: metaInt, onTitle: onTitle}
+}
+
+func (ir *icyReader) Read(p []byte) (int, error) {
+	if ir.remaining == 0 {
+		if err := ir.readMetadata(); err != nil {
+			return 0, err
+		}
+		ir.remaining = ir.metaInt
+	}
+	if len(p) > ir.remaining {
+		p = p[:ir.remaining]
+	}
+	n, err := ir.r.Read(p)
+	ir.remaining -= n
+	return n, err
+}
+
+func (ir *icyReader) readMetadata() error {
+	var lenByte [1]byte
+	if _, err := io.ReadFull(ir.r, lenByte[:]); err != nil {
+		return err
+	}
+	size := int(lenByte[0]) * 16
+	if size == 0 {
+		return nil
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(ir.r, buf); err != nil {
+		return err
+	}
+	if title, ok := parseStreamTitle(string(buf)); ok && ir.onTitle != nil {
+		ir.onTitle(title)
+	}
+	return nil
+}
+
+// parseStreamTitle extracts the StreamTitle value from an ICY metadata block
+// such as "StreamTitle='Artist - Song';StreamUrl=”;". The second return value
+// is false when the block carries no StreamTitle.
+func parseStreamTitle(meta string) (string, bool) {
+	meta = strings.TrimRight(meta, "\x00")
+	const key = "StreamTitle='"
+	start := strings.Index(meta, key)
+	if start < 0 {
+		return "", false
+	}
+	rest := meta[start+len(key):]
+	// Titles may legitimately contain apostrophes, so look for the "';"
+	// terminator first and only fall back to the last quote.
+	if end := strings.Index(rest, "';"); end >= 0 {
+		return rest[:end], true
+	}
+	if end := strings.LastIndex(rest, "'"); end >= 0 {
+		return rest[:end], true
+	}
+	return rest, true
+}
+
+// encodeMetadata builds an ICY metadata block (length byte followed by the
+// zero-padded payload) announcing title.
+func encodeMetadata(title string) []byte {
+	// A literal "';" would end the title early on the client side.
+	title = strings.ReplaceAll(title, "';", "’;")
+	if maxTitle := maxMetaBlocks*16 - len("StreamTitle='';"); len(title) > maxTitle {
+		title = title[:maxTitle]
+		for !utf8.ValidString(title) {
+			title = title[:len(title)-1]
+		}
+	}
+	payload := "StreamTitle='" + title + "';"
+	blocks := (len(payload) + 15) / 16
+	out := make([]byte, 1+blocks*16)
+	out[0] = byte(blocks)
+	copy(out[1:], payload)
+	return out
+}
+
+// icyWriter injects a metadata block every metaInt audio bytes. When the title
+// has not changed since the last block it sends the one-byte empty block, as
+// Icecast does, to keep per-interval overhead minimal.
+type icyWriter struct {
+	w         io.Writer
+	metaInt   int
+	remaining int
+	title     func() string
+	lastSent  string
+	sentOnce  bool
+}
+
+func newICYWriter(w io.Writer, metaInt int, title func() string) *icyWriter {
+	return &icyWriter{w: w, metaInt: metaInt, remaining: metaInt, title: title}
+}
+
+func (iw *icyWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if iw.remaining == 0 {
+			if err := iw.writeMetadata(); err != nil {
+				return written, err
+			}
+			iw.remaining = iw.metaInt
+		}
+		chunk := p
+		if len(chunk) > iw.remaining {
+			chunk = chunk[:iw.remaining]
+		}
+		n, err := iw.w.Write(chunk)
+		written += n
+		iw.remaining -= n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+func (iw *icyWriter) writeMetadata() error {
+	title := iw.title()
+	if iw.sentOnce && title == iw.lastSent {
+		_, err := iw.w.Write([]byte{0})
+		return err
+	}
+	iw.lastSent = title
+	iw.sentOnce = true
+	_, err := iw.w.Write(encodeMetadata(title))
+	return err
+}
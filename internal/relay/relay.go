@@ -0,0 +1,450 @@
+// Package relay re-streams the station TERA is playing over a local,
+// Icecast-compatible HTTP endpoint so other devices can listen along.
+//
+// The upstream stream is pulled once per station regardless of how many
+// listeners are connected. In-band ICY metadata from the station is stripped
+// and re-injected per listener at DefaultMetaInt, so clients that asked for
+// metadata see track titles and clients that did not get plain audio.
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+const (
+	// MountPath is the listener endpoint, mirroring an Icecast mount point.
+	MountPath = "/stream"
+
+	// chunkSize is the read size used for the upstream stream.
+	chunkSize = 8 << 10
+	// listenerQueue is the number of chunks buffered per listener before it
+	// is considered too slow and disconnected (~512 KB).
+	listenerQueue = 64
+	// burstSize is how much recent audio a new listener receives immediately
+	// so playback starts without waiting for the buffer to fill.
+	burstSize = 64 << 10
+	// stopGrace delays tearing down listeners after playback stops so that
+	// switching stations (stop old → start new) does not disconnect them.
+	stopGrace = 5 * time.Second
+	// retryDelay is the pause between upstream reconnect attempts.
+	retryDelay = 2 * time.Second
+)
+
+// ErrNoSource is returned to listeners when nothing is playing.
+var ErrNoSource = errors.New("relay: nothing is playing")
+
+// listener is one connected client.
+type listener struct {
+	ch     chan []byte
+	kicked chan struct{}
+	once   sync.Once
+}
+
+func (l *listener) kick() {
+	l.once.Do(func() { close(l.kicked) })
+}
+
+// Relay pulls the current station once and fans it out to local listeners.
+type Relay struct {
+	addr      string
+	userAgent string
+	client    *http.Client
+
+	mu          sync.Mutex
+	station     *api.Station
+	contentType string
+	title       string
+	burst       []byte
+	listeners   map[*listener]struct{}
+	cancel      context.CancelFunc // cancels the current upstream pump
+	generation  uint64             // bumped on every source change
+	stopTimer   *time.Timer
+
+	srvMu   sync.Mutex
+	httpSrv *http.Server
+	ln      net.Listener
+}
+
+// New creates a Relay that will listen on addr once Start is called.
+func New(addr, userAgent string) *Relay {
+	return &Relay{
+		addr:      addr,
+		userAgent: userAgent,
+		// No overall timeout: the upstream response body is a live stream.
+		client:    &http.Client{},
+		listeners: make(map[*listener]struct{}),
+	}
+}
+
+// Start binds the listening socket and serves in a background goroutine.
+func (r *Relay) Start() error {
+	r.srvMu.Lock()
+	defer r.srvMu.Unlock()
+	if r.httpSrv != nil {
+		return errors.New("relay: already running")
+	}
+	ln, err := net.Listen("tcp", r.addr)
+	if err != nil {
+		return fmt.Errorf("relay: failed to listen on %s: %w", r.addr, err)
+	}
+	r.ln = ln
+	r.httpSrv = &http.Server{
+		Handler:           r.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	srv := r.httpSrv
+	go func() { _ = srv.Serve(ln) }()
+	return nil
+}
+
+// Addr returns the bound address once started, or the configured one.
+func (r *Relay) Addr() string {
+	r.srvMu.Lock()
+	defer r.srvMu.Unlock()
+	if r.ln != nil {
+		return r.ln.Addr().String()
+	}
+	return r.addr
+}
+
+// Shutdown stops the upstream pull, disconnects every listener and closes the
+// HTTP server.
+func (r *Relay) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	r.stopSourceLocked()
+	r.mu.Unlock()
+
+	r.srvMu.Lock()
+	srv := r.httpSrv
+	r.httpSrv = nil
+	r.ln = nil
+	r.srvMu.Unlock()
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(ctx)
+}
+
+// SetStation switches the relay to station. A nil station means playback
+// stopped; listeners are kept for a short grace period in case another
+// station starts right away. Safe to use as a player.PlaybackObserver: it
+// never blocks.
+func (r *Relay) SetStation(station *api.Station) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if station == nil {
+		if r.station == nil || r.stopTimer != nil {
+			return
+		}
+		gen := r.generation
+		r.stopTimer = time.AfterFunc(stopGrace, func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if r.generation == gen {
+				r.stopSourceLocked()
+			}
+		})
+		return
+	}
+
+	if r.stopTimer != nil {
+		r.stopTimer.Stop()
+		r.stopTimer = nil
+	}
+	if r.station != nil && r.station.StationUUID == station.StationUUID && r.station.StreamURL() == station.StreamURL() {
+		return
+	}
+	if r.cancel != nil {
+		r.cancel()
+	}
+	st := *station
+	r.station = &st
+	r.title = ""
+	r.burst = nil
+	r.generation++
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	go r.pump(ctx, r.generation, st)
+}
+
+// stopSourceLocked cancels the upstream pull and disconnects all listeners.
+// Must be called with r.mu held.
+func (r *Relay) stopSourceLocked() {
+	if r.stopTimer != nil {
+		r.stopTimer.Stop()
+		r.stopTimer = nil
+	}
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+	r.station = nil
+	r.contentType = ""
+	r.title = ""
+	r.burst = nil
+	r.generation++
+	for l := range r.listeners {
+		l.kick()
+		delete(r.listeners, l)
+	}
+}
+
+// Listeners returns the number of connected clients.
+func (r *Relay) Listeners() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.listeners)
+}
+
+// CurrentTitle returns the last StreamTitle seen on the upstream stream.
+func (r *Relay) CurrentTitle() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.title
+}
+
+// pump pulls the upstream stream for station until ctx is cancelled,
+// reconnecting after transient failures.
+func (r *Relay) pump(ctx context.Context, gen uint64, station api.Station) {
+	for {
+		// Upstream failures are usually transient (station restarts, network
+		// blips), so keep retrying until the source changes.
+		_ = r.pumpOnce(ctx, gen, station)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+func (r *Relay) pumpOnce(ctx context.Context, gen uint64, station api.Station) error {
+	u, err := url.Parse(station.StreamURL())
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("relay: unsupported stream URL %q", station.StreamURL())
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Icy-MetaData", "1")
+	if r.userAgent != "" {
+		req.Header.Set("User-Agent", r.userAgent)
+	}
+	// Streams that need special headers in mpv need them here too.
+	if o := station.Options; o != nil {
+		for name, value := range o.Headers {
+			req.Header.Set(name, value)
+		}
+		if o.Referer != "" {
+			req.Header.Set("Referer", o.Referer)
+		}
+		if o.UserAgent != "" {
+			req.Header.Set("User-Agent", o.UserAgent)
+		}
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("relay: upstream returned %s", resp.Status)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "audio/mpeg"
+	}
+	r.mu.Lock()
+	if r.generation != gen {
+		r.mu.Unlock()
+		return nil
+	}
+	// A codec change (e.g. MP3 → AAC) cannot be spliced into a running
+	// connection, so ask listeners to reconnect and pick up the new format.
+	if r.contentType != "" && r.contentType != contentType {
+		for l := range r.listeners {
+			l.kick()
+			delete(r.listeners, l)
+		}
+	}
+	r.contentType = contentType
+	r.mu.Unlock()
+
+	var body io.Reader = resp.Body
+	if mi, err := strconv.Atoi(resp.Header.Get("icy-metaint")); err == nil && mi > 0 {
+		body = newICYReader(resp.Body, mi, func(title string) {
+			r.mu.Lock()
+			if r.generation == gen {
+				r.title = title
+			}
+			r.mu.Unlock()
+		})
+	}
+
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			chunk := make([]byte, n)
+			copy(chunk, buf[:n])
+			if !r.broadcast(gen, chunk) {
+				return nil
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// broadcast hands chunk to every listener. Listeners whose queue is full are
+// disconnected rather than stalling everyone else. Returns false if the
+// source has changed and the caller should stop.
+func (r *Relay) broadcast(gen uint64, chunk []byte) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.generation != gen {
+		return false
+	}
+	r.burst = append(r.burst, chunk...)
+	if over := len(r.burst) - burstSize; over > 0 {
+		r.burst = append([]byte(nil), r.burst[over:]...)
+	}
+	for l := range r.listeners {
+		select {
+		case l.ch <- chunk:
+		default:
+			l.kick()
+			delete(r.listeners, l)
+		}
+	}
+	return true
+}
+
+// Handler returns the relay's HTTP handler.
+func (r *Relay) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+MountPath, r.handleStream)
+	mux.HandleFunc("GET /status-json.xsl", r.handleStatus)
+	return mux
+}
+
+func (r *Relay) handleStream(w http.ResponseWriter, req *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	r.mu.Lock()
+	if r.station == nil || r.contentType == "" {
+		r.mu.Unlock()
+		http.Error(w, ErrNoSource.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	station := *r.station
+	contentType := r.contentType
+	burst := append([]byte(nil), r.burst...)
+	l := &listener{ch: make(chan []byte, listenerQueue), kicked: make(chan struct{})}
+	r.listeners[l] = struct{}{}
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.listeners, l)
+		r.mu.Unlock()
+	}()
+
+	h := w.Header()
+	h.Set("Content-Type", contentType)
+	h.Set("Cache-Control", "no-cache, no-store")
+	h.Set("icy-name", strings.TrimSpace(station.Name))
+	h.Set("icy-pub", "0")
+	if station.Tags != "" {
+		h.Set("icy-genre", station.Tags)
+	}
+	if station.Bitrate > 0 {
+		h.Set("icy-br", strconv.Itoa(station.Bitrate))
+	}
+
+	var out io.Writer = w
+	if req.Header.Get("Icy-MetaData") == "1" {
+		h.Set("icy-metaint", strconv.Itoa(DefaultMetaInt))
+		out = newICYWriter(w, DefaultMetaInt, r.CurrentTitle)
+	}
+	w.WriteHeader(http.StatusOK)
+
+	if len(burst) > 0 {
+		if _, err := out.Write(burst); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case chunk := <-l.ch:
+			if _, err := out.Write(chunk); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-l.kicked:
+			return
+		case <-req.Context().Done():
+			return
+		}
+	}
+}
+
+// handleStatus serves a subset of Icecast's status-json.xsl so tools that
+// probe Icecast servers can discover the stream.
+func (r *Relay) handleStatus(w http.ResponseWriter, req *http.Request) {
+	type source struct {
+		ListenURL   string `json:"listenurl"`
+		ServerName  string `json:"server_name,omitempty"`
+		ServerType  string `json:"server_type,omitempty"`
+		Genre       string `json:"genre,omitempty"`
+		Bitrate     int    `json:"bitrate,omitempty"`
+		Title       string `json:"title,omitempty"`
+		Listeners   int    `json:"listeners"`
+		StationUUID string `json:"stationuuid,omitempty"`
+	}
+	r.mu.Lock()
+	var src *source
+	if r.station != nil {
+		src = &source{
+			ListenURL:   "http://" + req.Host + MountPath,
+			ServerName:  strings.TrimSpace(r.station.Name),
+			ServerType:  r.contentType,
+			Genre:       r.station.Tags,
+			Bitrate:     r.station.Bitrate,
+			Title:       r.title,
+			Listeners:   len(r.listeners),
+			StationUUID: r.station.StationUUID,
+		}
+	}
+	r.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	stats := map[string]any{"server_id": "TERA relay"}
+	if src != nil {
+		stats["source"] = src
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"icestats": stats})
+}
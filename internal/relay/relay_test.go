@@ -0,0 +1,230 @@
+package relay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+func TestParseStreamTitle(t *testing.T) {
+	cases := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"StreamTitle='Artist - Song';StreamUrl='';\x00\x00", "Artist - Song", true},
+		{"StreamTitle='Don't Stop';", "Don't Stop", true},
+		{"StreamTitle='';", "", true},
+		{"StreamUrl='http://x';", "", false},
+	}
+	for _, tc := range cases {
+		got, ok := parseStreamTitle(tc.in)
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("parseStreamTitle(%q) = %q, %v; want %q, %v", tc.in, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
+
+func TestEncodeMetadata(t *testing.T) {
+	block := encodeMetadata("Hello")
+	if int(block[0])*16 != len(block)-1 {
+		t.Fatalf("length byte %d does not match payload %d", block[0], len(block)-1)
+	}
+	if title, _ := parseStreamTitle(string(block[1:])); title != "Hello" {
+		t.Errorf("round trip: got %q", title)
+	}
+
+	long := encodeMetadata(strings.Repeat("é", 5000))
+	if long[0] != maxMetaBlocks {
+		t.Errorf("expected %d blocks for an oversized title, got %d", maxMetaBlocks, long[0])
+	}
+}
+
+// TestICYRoundTrip writes audio through icyWriter and reads it back through
+// icyReader, checking that the audio is unchanged and titles survive.
+func TestICYRoundTrip(t *testing.T) {
+	audio := bytes.Repeat([]byte("0123456789"), 1000) // 10 KB
+	title := "First"
+
+	var wire bytes.Buffer
+	w := newICYWriter(&wire, 1024, func() string { return title })
+	if _, err := w.Write(audio[:5000]); err != nil {
+		t.Fatal(err)
+	}
+	title = "Second"
+	if _, err := w.Write(audio[5000:]); err != nil {
+		t.Fatal(err)
+	}
+
+	var titles []string
+	r := newICYReader(&wire, 1024, func(s string) { titles = append(titles, s) })
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, audio) {
+		t.Fatalf("audio corrupted: got %d bytes, want %d", len(got), len(audio))
+	}
+	if len(titles) != 2 || titles[0] != "First" || titles[1] != "Second" {
+		t.Errorf("expected titles [First Second], got %v", titles)
+	}
+}
+
+// fakeUpstream serves an endless ICY stream with the given title.
+func fakeUpstream(t *testing.T, contentType, title string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Icy-MetaData") != "1" {
+			t.Error("relay should request ICY metadata upstream")
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("icy-metaint", "512")
+		w.WriteHeader(http.StatusOK)
+		iw := newICYWriter(w, 512, func() string { return title })
+		chunk := bytes.Repeat([]byte{0xAB}, 256)
+		for {
+			if _, err := iw.Write(chunk); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestRelay_NoSource(t *testing.T) {
+	r := New("127.0.0.1:0", "")
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", MountPath, nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a source, got %d", rec.Code)
+	}
+}
+
+func TestRelay_FansOutWithMetadata(t *testing.T) {
+	upstream := fakeUpstream(t, "audio/mpeg", "Live Song")
+	r := New("127.0.0.1:0", "TERA-test")
+	front := httptest.NewServer(r.Handler())
+	defer front.Close()
+	defer func() { _ = r.Shutdown(context.Background()) }()
+
+	r.SetStation(&api.Station{StationUUID: "s1", Name: "Test FM", URLResolved: upstream.URL, Bitrate: 128})
+	waitFor(t, "upstream title", func() bool { return r.CurrentTitle() == "Live Song" })
+
+	// Listener with metadata.
+	req, _ := http.NewRequest("GET", front.URL+MountPath, nil)
+	req.Header.Set("Icy-MetaData", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.Header.Get("icy-name") != "Test FM" || resp.Header.Get("icy-br") != "128" {
+		t.Errorf("unexpected ICY headers: %v", resp.Header)
+	}
+	metaInt, _ := strconv.Atoi(resp.Header.Get("icy-metaint"))
+	if metaInt != DefaultMetaInt {
+		t.Fatalf("expected icy-metaint %d, got %d", DefaultMetaInt, metaInt)
+	}
+	var titles []string
+	ir := newICYReader(resp.Body, metaInt, func(s string) { titles = append(titles, s) })
+	audio := make([]byte, metaInt+1)
+	if _, err := io.ReadFull(ir, audio); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	for _, b := range audio {
+		if b != 0xAB {
+			t.Fatal("listener received upstream metadata bytes as audio")
+		}
+	}
+	if len(titles) == 0 || titles[0] != "Live Song" {
+		t.Errorf("expected injected title 'Live Song', got %v", titles)
+	}
+
+	// Plain listener: no icy-metaint and raw audio.
+	resp2, err := http.Get(front.URL + MountPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp2.Body.Close() }()
+	if resp2.Header.Get("icy-metaint") != "" {
+		t.Error("plain listener should not get icy-metaint")
+	}
+	buf := make([]byte, 1024)
+	if _, err := io.ReadFull(resp2.Body, buf); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "two listeners", func() bool { return r.Listeners() == 2 })
+
+	// Status endpoint reports the source.
+	sresp, err := http.Get(front.URL + "/status-json.xsl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sresp.Body.Close() }()
+	var status struct {
+		Icestats struct {
+			Source struct {
+				ServerName string `json:"server_name"`
+				Title      string `json:"title"`
+				Listeners  int    `json:"listeners"`
+			} `json:"source"`
+		} `json:"icestats"`
+	}
+	if err := json.NewDecoder(sresp.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Icestats.Source.ServerName != "Test FM" || status.Icestats.Source.Title != "Live Song" {
+		t.Errorf("unexpected status: %+v", status.Icestats.Source)
+	}
+}
+
+func TestRelay_FollowsStationChanges(t *testing.T) {
+	first := fakeUpstream(t, "audio/mpeg", "One")
+	second := fakeUpstream(t, "audio/mpeg", "Two")
+	r := New("127.0.0.1:0", "")
+	defer func() { _ = r.Shutdown(context.Background()) }()
+
+	r.SetStation(&api.Station{StationUUID: "a", URLResolved: first.URL})
+	waitFor(t, "first title", func() bool { return r.CurrentTitle() == "One" })
+
+	// Stop followed immediately by a new station must not tear down the source.
+	r.SetStation(nil)
+	r.SetStation(&api.Station{StationUUID: "b", URLResolved: second.URL})
+	waitFor(t, "second title", func() bool { return r.CurrentTitle() == "Two" })
+
+	r.mu.Lock()
+	uuid := r.station.StationUUID
+	timerPending := r.stopTimer != nil
+	r.mu.Unlock()
+	if uuid != "b" || timerPending {
+		t.Errorf("expected station b with no pending stop, got %s (timer=%v)", uuid, timerPending)
+	}
+}
@@ -0,0 +1,522 @@
+// Package remote implements TERA's opt-in local HTTP API and the small web
+// remote page served alongside it.
+//
+// The server never touches data files directly: favorites, ratings and search
+// go through the same storage managers and API client the TUI uses, and
+// playback is delegated to a Controller implemented by the UI so that every
+// state change still happens on the Bubble Tea update loop.
+package remote
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// maxRequestBody caps JSON request bodies; every accepted payload is tiny.
+const maxRequestBody = 64 << 10
+
+// ErrNothingPlaying is returned by a Controller when an action needs an active
+// station (e.g. changing the volume) but nothing is playing.
+var ErrNothingPlaying = errors.New("nothing is playing")
+
+// NowPlaying describes the current playback state reported by the Controller.
+type NowPlaying struct {
+	Playing bool         `json:"playing"`
+	Paused  bool         `json:"paused"`
+	Station *api.Station `json:"station,omitempty"`
+	Track   string       `json:"track,omitempty"`  // Raw stream title
+	Artist  string       `json:"artist,omitempty"` // Parts parsed from Track, when known
+	Title   string       `json:"title,omitempty"`
+	Album   string       `json:"album,omitempty"`
+	Volume  int          `json:"volume"`
+	Muted   bool         `json:"muted"`
+	Context string       `json:"context,omitempty"`
+}
+
+// Controller is the bridge between the HTTP server and the running player.
+// Implementations must be safe to call from any goroutine.
+type Controller interface {
+	NowPlaying() (NowPlaying, error)
+	Play(station api.Station) error
+	Stop() error
+	SetVolume(volume int) (int, error)
+}
+
+// Options configures a Server. Any manager may be nil, in which case the
+// endpoints backed by it answer 503 Service Unavailable.
+type Options struct {
+	Addr       string
+	Token      string
+	Favorites  *storage.Storage
+	Ratings    *storage.RatingsManager
+	Client     *api.Client
+	Controller Controller
+}
+
+// Server is the HTTP API server.
+type Server struct {
+	opts    Options
+	handler http.Handler
+
+	mu       sync.Mutex
+	httpSrv  *http.Server
+	listener net.Listener
+}
+
+// NewServer creates a Server. It does not start listening; call Start.
+func NewServer(opts Options) *Server {
+	s := &Server{opts: opts}
+	s.handler = s.routes()
+	return s
+}
+
+// GenerateToken returns a random 32-character hex token suitable for
+// RemoteConfig.Token.
+func GenerateToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Handler returns the server's HTTP handler. It is exposed for tests and for
+// embedding the API into another server.
+func (s *Server) Handler() http.Handler {
+	return s.handler
+}
+
+// Start binds the listener and serves in a background goroutine. It returns an
+// error if the address cannot be bound or no token is configured.
+func (s *Server) Start() error {
+	if s.opts.Token == "" {
+		return errors.New("remote: refusing to start without a token")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.httpSrv != nil {
+		return errors.New("remote: server already running")
+	}
+
+	ln, err := net.Listen("tcp", s.opts.Addr)
+	if err != nil {
+		return fmt.Errorf("remote: failed to listen on %s: %w", s.opts.Addr, err)
+	}
+	s.listener = ln
+	s.httpSrv = &http.Server{
+		Handler:           s.handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	srv := s.httpSrv
+	go func() { _ = srv.Serve(ln) }()
+	return nil
+}
+
+// Addr returns the bound address once Start has succeeded, or the configured
+// address otherwise.
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener != nil {
+		return s.listener.Addr().String()
+	}
+	return s.opts.Addr
+}
+
+// Shutdown gracefully stops the server. It is safe to call when the server
+// was never started.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	srv := s.httpSrv
+	s.httpSrv = nil
+	s.listener = nil
+	s.mu.Unlock()
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(ctx)
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	// The remote page itself carries no data, so it is served without a
+	// token; the page asks for one and sends it with every API call.
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+
+	mux.Handle("GET /api/now-playing", s.auth(s.handleNowPlaying))
+	mux.Handle("POST /api/play", s.auth(s.handlePlay))
+	mux.Handle("POST /api/stop", s.auth(s.handleStop))
+	mux.Handle("POST /api/volume", s.auth(s.handleVolume))
+	mux.Handle("GET /api/favorites", s.auth(s.handleFavoriteLists))
+	mux.Handle("GET /api/favorites/{list...}", s.auth(s.handleFavoriteList))
+	mux.Handle("GET /api/search", s.auth(s.handleSearch))
+	mux.Handle("GET /api/ratings", s.auth(s.handleRatings))
+	mux.Handle("PUT /api/ratings/{uuid}", s.auth(s.handleSetRating))
+	mux.Handle("DELETE /api/ratings/{uuid}", s.auth(s.handleDeleteRating))
+
+	return mux
+}
+
+// auth wraps h so that it only runs when the request carries the configured
+// token, either as "Authorization: Bearer <token>" or "X-Tera-Token".
+func (s *Server) auth(h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Tera-Token")
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			token = bearer
+		}
+		if s.opts.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "invalid or missing token")
+			return
+		}
+		h(w, r)
+	})
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(indexHTML)
+}
+
+func (s *Server) handleNowPlaying(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Controller == nil {
+		writeError(w, http.StatusServiceUnavailable, "playback control unavailable")
+		return
+	}
+	np, err := s.opts.Controller.NowPlaying()
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if np.Station != nil && s.opts.Ratings != nil {
+		if rating := s.opts.Ratings.GetRating(np.Station.StationUUID); rating != nil {
+			writeJSON(w, http.StatusOK, struct {
+				NowPlaying
+				Rating int `json:"rating"`
+			}{np, rating.Rating})
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, np)
+}
+
+// playRequest selects a station either by list position or by UUID. A UUID
+// is looked up in the favorites first and then through the Radio Browser API.
+type playRequest struct {
+	List  string `json:"list,omitempty"`
+	Index *int   `json:"index,omitempty"`
+	UUID  string `json:"uuid,omitempty"`
+}
+
+func (s *Server) handlePlay(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Controller == nil {
+		writeError(w, http.StatusServiceUnavailable, "playback control unavailable")
+		return
+	}
+	var req playRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	station, status, err := s.resolveStation(r.Context(), req)
+	if err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+	if err := s.opts.Controller.Play(*station); err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, station)
+}
+
+func (s *Server) resolveStation(ctx context.Context, req playRequest) (*api.Station, int, error) {
+	if req.List != "" {
+		list, code, err := s.loadList(ctx, req.List)
+		if err != nil {
+			return nil, code, err
+		}
+		if req.Index != nil {
+			if *req.Index < 0 || *req.Index >= len(list.Stations) {
+				return nil, http.StatusBadRequest, fmt.Errorf("index %d out of range", *req.Index)
+			}
+			st := list.Stations[*req.Index]
+			return &st, 0, nil
+		}
+		for _, st := range list.Stations {
+			if st.StationUUID == req.UUID {
+				return &st, 0, nil
+			}
+		}
+		return nil, http.StatusNotFound, errors.New("station not found in list")
+	}
+
+	if req.UUID == "" {
+		return nil, http.StatusBadRequest, errors.New("uuid or list is required")
+	}
+	if s.opts.Favorites != nil {
+		if names, err := s.opts.Favorites.GetAllLists(ctx); err == nil {
+			for _, name := range names {
+				list, err := s.opts.Favorites.LoadList(ctx, name)
+				if err != nil {
+					continue
+				}
+				for _, st := range list.Stations {
+					if st.StationUUID == req.UUID {
+						return &st, 0, nil
+					}
+				}
+			}
+		}
+	}
+	if s.opts.Client == nil {
+		return nil, http.StatusNotFound, storage.ErrStationNotFound
+	}
+	st, err := s.opts.Client.GetByUUID(ctx, req.UUID)
+	if err != nil {
+		return nil, http.StatusNotFound, err
+	}
+	return st, 0, nil
+}
+
+func (s *Server) handleStop(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Controller == nil {
+		writeError(w, http.StatusServiceUnavailable, "playback control unavailable")
+		return
+	}
+	if err := s.opts.Controller.Stop(); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"stopped": true})
+}
+
+func (s *Server) handleVolume(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Controller == nil {
+		writeError(w, http.StatusServiceUnavailable, "playback control unavailable")
+		return
+	}
+	var req struct {
+		Volume *int `json:"volume"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Volume == nil || *req.Volume < 0 || *req.Volume > 100 {
+		writeError(w, http.StatusBadRequest, "volume must be between 0 and 100")
+		return
+	}
+	vol, err := s.opts.Controller.SetVolume(*req.Volume)
+	if errors.Is(err, ErrNothingPlaying) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"volume": vol})
+}
+
+func (s *Server) handleFavoriteLists(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Favorites == nil {
+		writeError(w, http.StatusServiceUnavailable, "favorites unavailable")
+		return
+	}
+	names, err := s.opts.Favorites.GetAllLists(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if names == nil {
+		names = []string{}
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"lists": names})
+}
+
+func (s *Server) handleFavoriteList(w http.ResponseWriter, r *http.Request) {
+	list, code, err := s.loadList(r.Context(), r.PathValue("list"))
+	if err != nil {
+		writeError(w, code, err.Error())
+		return
+	}
+	if list.Stations == nil {
+		list.Stations = []api.Station{}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Client == nil {
+		writeError(w, http.StatusServiceUnavailable, "search unavailable")
+		return
+	}
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+
+	var (
+		results []api.Station
+		err     error
+	)
+	switch by := r.URL.Query().Get("by"); by {
+	case "", "name":
+		results, err = s.opts.Client.SearchByName(ctx, q)
+	case "tag":
+		results, err = s.opts.Client.SearchByTag(ctx, q)
+	case "language":
+		results, err = s.opts.Client.SearchByLanguage(ctx, q)
+	case "country":
+		results, err = s.opts.Client.SearchByCountry(ctx, q)
+	case "state":
+		results, err = s.opts.Client.SearchByState(ctx, q)
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown search type %q", by))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	if results == nil {
+		results = []api.Station{}
+	}
+	writeJSON(w, http.StatusOK, map[string][]api.Station{"stations": results})
+}
+
+// ratedStation is the JSON form of storage.StationWithRating.
+type ratedStation struct {
+	Station api.Station `json:"station"`
+	Rating  int         `json:"rating"`
+	RatedAt time.Time   `json:"rated_at"`
+}
+
+func (s *Server) handleRatings(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Ratings == nil {
+		writeError(w, http.StatusServiceUnavailable, "ratings unavailable")
+		return
+	}
+	all := s.opts.Ratings.GetAllRated()
+	out := make([]ratedStation, 0, len(all))
+	for _, sr := range all {
+		if sr.Rating == nil {
+			continue
+		}
+		out = append(out, ratedStation{Station: sr.Station, Rating: sr.Rating.Rating, RatedAt: sr.Rating.UpdatedAt})
+	}
+	writeJSON(w, http.StatusOK, map[string][]ratedStation{"ratings": out})
+}
+
+func (s *Server) handleSetRating(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Ratings == nil {
+		writeError(w, http.StatusServiceUnavailable, "ratings unavailable")
+		return
+	}
+	var req struct {
+		Rating int `json:"rating"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	station, status, err := s.stationByUUID(r.Context(), r.PathValue("uuid"))
+	if err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+	if err := s.opts.Ratings.SetRating(station, req.Rating); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, ratedStation{Station: *station, Rating: req.Rating, RatedAt: time.Now()})
+}
+
+// stationByUUID looks up the station uuid: the one playing, or else as a
+// play request finds it. The client only names the station, so it can't
+// store a made-up name or stream URL under a real station's UUID.
+func (s *Server) stationByUUID(ctx context.Context, uuid string) (*api.Station, int, error) {
+	if s.opts.Controller != nil {
+		if np, err := s.opts.Controller.NowPlaying(); err == nil && np.Station != nil && np.Station.StationUUID == uuid {
+			st := *np.Station
+			return &st, 0, nil
+		}
+	}
+	return s.resolveStation(ctx, playRequest{UUID: uuid})
+}
+
+func (s *Server) handleDeleteRating(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Ratings == nil {
+		writeError(w, http.StatusServiceUnavailable, "ratings unavailable")
+		return
+	}
+	if err := s.opts.Ratings.RemoveRating(r.PathValue("uuid")); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadList loads the favorites list name, which may be nested ("Jazz/Smooth")
+// or, as with `tera play`, just a unique last segment. It returns the HTTP
+// status to report on error.
+func (s *Server) loadList(ctx context.Context, name string) (*storage.FavoritesList, int, error) {
+	if storage.ValidateListName(name) != nil {
+		return nil, http.StatusBadRequest, errors.New("invalid list name")
+	}
+	if s.opts.Favorites == nil {
+		return nil, http.StatusServiceUnavailable, errors.New("favorites unavailable")
+	}
+	resolved, err := s.opts.Favorites.ResolveListName(ctx, name)
+	if err != nil {
+		return nil, http.StatusNotFound, err
+	}
+	list, err := s.opts.Favorites.LoadList(ctx, resolved)
+	if err != nil {
+		return nil, http.StatusNotFound, fmt.Errorf("list %q not found", name)
+	}
+	return list, 0, nil
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
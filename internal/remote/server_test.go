@@ -0,0 +1,364 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+const testToken = "secret-token"
+
+type fakeController struct {
+	mu      sync.Mutex
+	playing *api.Station
+	volume  int
+}
+
+func (f *fakeController) NowPlaying() (NowPlaying, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return NowPlaying{Playing: f.playing != nil, Station: f.playing, Volume: f.volume}, nil
+}
+
+func (f *fakeController) Play(station api.Station) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.playing = &station
+	return nil
+}
+
+func (f *fakeController) Stop() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.playing = nil
+	return nil
+}
+
+func (f *fakeController) SetVolume(volume int) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.playing == nil {
+		return 0, ErrNothingPlaying
+	}
+	f.volume = volume
+	return volume, nil
+}
+
+func newTestServer(t *testing.T) (*Server, *fakeController, *storage.RatingsManager) {
+	t.Helper()
+	dir := t.TempDir()
+	favs := storage.NewStorage(dir)
+	err := favs.SaveList(context.Background(), &storage.FavoritesList{
+		Name: "jazz",
+		Stations: []api.Station{
+			{StationUUID: "uuid-1", Name: "Jazz One", URLResolved: "http://example.com/1"},
+			{StationUUID: "uuid-2", Name: "Jazz Two", URLResolved: "http://example.com/2"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SaveList: %v", err)
+	}
+	ratings, _ := storage.NewRatingsManager(t.TempDir())
+	t.Cleanup(func() { _ = ratings.Close() })
+
+	ctrl := &fakeController{volume: 80}
+	srv := NewServer(Options{
+		Token:      testToken,
+		Favorites:  favs,
+		Ratings:    ratings,
+		Controller: ctrl,
+	})
+	return srv, ctrl, ratings
+}
+
+func do(t *testing.T, srv *Server, method, path, body string, authed bool) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if authed {
+		req.Header.Set("Authorization", "Bearer "+testToken)
+	}
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIndexServedWithoutToken(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+	rec := do(t, srv, "GET", "/", "", false)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "TERA Remote") {
+		t.Error("expected embedded remote page")
+	}
+}
+
+func TestAPIRequiresToken(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+
+	if rec := do(t, srv, "GET", "/api/now-playing", "", false); rec.Code != http.StatusUnauthorized {
+		t.Errorf("missing token: expected 401, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest("GET", "/api/now-playing", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong token: expected 401, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/api/now-playing", nil)
+	req.Header.Set("X-Tera-Token", testToken)
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("X-Tera-Token: expected 200, got %d", rec.Code)
+	}
+}
+
+func TestFavoritesEndpoints(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+
+	rec := do(t, srv, "GET", "/api/favorites", "", true)
+	var lists struct{ Lists []string }
+	if err := json.Unmarshal(rec.Body.Bytes(), &lists); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(lists.Lists) != 1 || lists.Lists[0] != "jazz" {
+		t.Errorf("expected [jazz], got %v", lists.Lists)
+	}
+
+	rec = do(t, srv, "GET", "/api/favorites/jazz", "", true)
+	var list storage.FavoritesList
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(list.Stations) != 2 {
+		t.Errorf("expected 2 stations, got %d", len(list.Stations))
+	}
+
+	if rec := do(t, srv, "GET", "/api/favorites/missing", "", true); rec.Code != http.StatusNotFound {
+		t.Errorf("missing list: expected 404, got %d", rec.Code)
+	}
+	if rec := do(t, srv, "GET", "/api/favorites/..", "", true); rec.Code == http.StatusOK {
+		t.Error("path traversal should be rejected")
+	}
+}
+
+func TestNestedFavoriteList(t *testing.T) {
+	favs := storage.NewStorage(t.TempDir())
+	err := favs.SaveList(context.Background(), &storage.FavoritesList{
+		Name:     "Jazz/Smooth",
+		Stations: []api.Station{{StationUUID: "uuid-s", Name: "Smooth", URLResolved: "http://example.com/s"}},
+	})
+	if err != nil {
+		t.Fatalf("SaveList: %v", err)
+	}
+	ctrl := &fakeController{}
+	srv := NewServer(Options{Token: testToken, Favorites: favs, Controller: ctrl})
+
+	for _, path := range []string{"/api/favorites/Jazz/Smooth", "/api/favorites/Jazz%2FSmooth", "/api/favorites/smooth"} {
+		rec := do(t, srv, "GET", path, "", true)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: expected 200, got %d: %s", path, rec.Code, rec.Body)
+		}
+		var list storage.FavoritesList
+		if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(list.Stations) != 1 {
+			t.Errorf("GET %s: expected 1 station, got %d", path, len(list.Stations))
+		}
+	}
+	if rec := do(t, srv, "GET", "/api/favorites/Jazz/../Smooth", "", true); rec.Code == http.StatusOK {
+		t.Error("path traversal should be rejected")
+	}
+
+	if rec := do(t, srv, "POST", "/api/play", `{"list":"Jazz/Smooth","index":0}`, true); rec.Code != http.StatusOK {
+		t.Fatalf("play nested list: expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if ctrl.playing == nil || ctrl.playing.StationUUID != "uuid-s" {
+		t.Errorf("expected uuid-s playing, got %+v", ctrl.playing)
+	}
+}
+
+func TestPlayStopVolume(t *testing.T) {
+	srv, ctrl, _ := newTestServer(t)
+
+	if rec := do(t, srv, "POST", "/api/volume", `{"volume":50}`, true); rec.Code != http.StatusConflict {
+		t.Errorf("volume while stopped: expected 409, got %d", rec.Code)
+	}
+
+	if rec := do(t, srv, "POST", "/api/play", `{"list":"jazz","index":1}`, true); rec.Code != http.StatusOK {
+		t.Fatalf("play by index: expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if ctrl.playing == nil || ctrl.playing.StationUUID != "uuid-2" {
+		t.Fatalf("expected uuid-2 playing, got %+v", ctrl.playing)
+	}
+
+	// UUID lookups search every favorites list before hitting the network.
+	if rec := do(t, srv, "POST", "/api/play", `{"uuid":"uuid-1"}`, true); rec.Code != http.StatusOK {
+		t.Fatalf("play by uuid: expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if ctrl.playing.StationUUID != "uuid-1" {
+		t.Errorf("expected uuid-1 playing, got %s", ctrl.playing.StationUUID)
+	}
+
+	if rec := do(t, srv, "POST", "/api/play", `{"list":"jazz","index":5}`, true); rec.Code != http.StatusBadRequest {
+		t.Errorf("out of range index: expected 400, got %d", rec.Code)
+	}
+
+	if rec := do(t, srv, "POST", "/api/volume", `{"volume":150}`, true); rec.Code != http.StatusBadRequest {
+		t.Errorf("volume 150: expected 400, got %d", rec.Code)
+	}
+	if rec := do(t, srv, "POST", "/api/volume", `{"volume":40}`, true); rec.Code != http.StatusOK {
+		t.Errorf("volume 40: expected 200, got %d", rec.Code)
+	}
+	if ctrl.volume != 40 {
+		t.Errorf("expected volume 40, got %d", ctrl.volume)
+	}
+
+	if rec := do(t, srv, "POST", "/api/stop", "", true); rec.Code != http.StatusOK {
+		t.Errorf("stop: expected 200, got %d", rec.Code)
+	}
+	if ctrl.playing != nil {
+		t.Error("expected playback stopped")
+	}
+}
+
+func TestRatingsEndpoints(t *testing.T) {
+	srv, ctrl, ratings := newTestServer(t)
+
+	if rec := do(t, srv, "PUT", "/api/ratings/uuid-1", `{"rating":4}`, true); rec.Code != http.StatusOK {
+		t.Fatalf("set rating: expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if r := ratings.GetRating("uuid-1"); r == nil || r.Rating != 4 {
+		t.Fatalf("expected rating 4 stored, got %+v", r)
+	}
+	if rec := do(t, srv, "PUT", "/api/ratings/uuid-1", `{"rating":9}`, true); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid rating: expected 400, got %d", rec.Code)
+	}
+
+	// Now-playing includes the rating of the current station.
+	_ = ctrl.Play(api.Station{StationUUID: "uuid-1", Name: "Jazz One"})
+	rec := do(t, srv, "GET", "/api/now-playing", "", true)
+	var np struct {
+		Playing bool
+		Rating  int
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &np); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !np.Playing || np.Rating != 4 {
+		t.Errorf("expected playing with rating 4, got %+v", np)
+	}
+
+	rec = do(t, srv, "GET", "/api/ratings", "", true)
+	var all struct {
+		Ratings []ratedStation
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &all); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(all.Ratings) != 1 || all.Ratings[0].Station.Name != "Jazz One" {
+		t.Errorf("unexpected ratings: %+v", all.Ratings)
+	}
+
+	if rec := do(t, srv, "DELETE", "/api/ratings/uuid-1", "", true); rec.Code != http.StatusNoContent {
+		t.Errorf("delete rating: expected 204, got %d", rec.Code)
+	}
+	if ratings.GetRating("uuid-1") != nil {
+		t.Error("expected rating removed")
+	}
+}
+
+// TestSetRating_StoresServerCopy verifies that a rating stores the station
+// as the server knows it, never details sent by the client.
+func TestSetRating_StoresServerCopy(t *testing.T) {
+	srv, ctrl, ratings := newTestServer(t)
+
+	forged := `{"rating":5,"station":{"stationuuid":"uuid-1","name":"Fake","url_resolved":"http://evil.example/x"}}`
+	if rec := do(t, srv, "PUT", "/api/ratings/uuid-1", forged, true); rec.Code != http.StatusBadRequest {
+		t.Errorf("station in body: expected 400, got %d", rec.Code)
+	}
+	if ratings.GetRating("uuid-1") != nil {
+		t.Fatal("a rejected request must not store a rating")
+	}
+
+	// The playing station is rated as it plays, even outside the favorites
+	_ = ctrl.Play(api.Station{StationUUID: "live-1", Name: "Live One", URLResolved: "http://example.com/live"})
+	if rec := do(t, srv, "PUT", "/api/ratings/live-1", `{"rating":4}`, true); rec.Code != http.StatusOK {
+		t.Fatalf("rate current: expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do(t, srv, "PUT", "/api/ratings/uuid-2", `{"rating":3}`, true); rec.Code != http.StatusOK {
+		t.Fatalf("rate favorite: expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	names := make(map[string]string)
+	for _, sr := range ratings.GetAllRated() {
+		names[sr.Station.StationUUID] = sr.Station.Name
+	}
+	if names["live-1"] != "Live One" || names["uuid-2"] != "Jazz Two" {
+		t.Errorf("expected the server's copies, got %v", names)
+	}
+}
+
+func TestSearchValidation(t *testing.T) {
+	srv, _, _ := newTestServer(t)
+	// No API client configured.
+	if rec := do(t, srv, "GET", "/api/search?q=jazz", "", true); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without client, got %d", rec.Code)
+	}
+
+	srv.opts.Client = api.NewClient()
+	if rec := do(t, srv, "GET", "/api/search", "", true); rec.Code != http.StatusBadRequest {
+		t.Errorf("missing q: expected 400, got %d", rec.Code)
+	}
+	if rec := do(t, srv, "GET", "/api/search?q=x&by=bogus", "", true); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad type: expected 400, got %d", rec.Code)
+	}
+}
+
+func TestStartRequiresToken(t *testing.T) {
+	srv := NewServer(Options{Addr: "127.0.0.1:0"})
+	if err := srv.Start(); err == nil {
+		_ = srv.Shutdown(context.Background())
+		t.Fatal("expected Start to fail without a token")
+	}
+}
+
+func TestStartAndShutdown(t *testing.T) {
+	srv := NewServer(Options{Addr: "127.0.0.1:0", Token: testToken})
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	resp, err := http.Get("http://" + srv.Addr() + "/")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown: %v", err)
+	}
+}
+
+func TestGenerateToken(t *testing.T) {
+	a, err := GenerateToken()
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	b, _ := GenerateToken()
+	if len(a) != 32 || a == b {
+		t.Errorf("expected distinct 32-char tokens, got %q and %q", a, b)
+	}
+}
@@ -0,0 +1,155 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// barEighths draws the fraction of a bar cell, in eighths.
+var barEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// heatShades draws a heatmap cell from no listening to the busiest hour.
+var heatShades = []string{"·", "░", "▒", "▓", "█"}
+
+var weekdayNames = [7]string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// Render draws r as plain-text charts that fit in width columns.
+func Render(r Report, width int) string {
+	if width < 40 {
+		width = 40
+	}
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Total listening: %s", storage.FormatDuration(r.TotalSeconds))
+	if r.Sessions > 0 {
+		fmt.Fprintf(&b, " · %d session(s) logged (%s)", r.Sessions, storage.FormatDuration(r.LoggedSeconds))
+	}
+	b.WriteString("\n")
+	st := r.Streaks
+	fmt.Fprintf(&b, "Streak: %s now · longest %s", days(st.CurrentDays), days(st.LongestDays))
+	if st.LongestDays > 0 {
+		fmt.Fprintf(&b, " (%s – %s)", st.LongestStart.Format("Jan 2"), st.LongestEnd.Format("Jan 2 2006"))
+	}
+	fmt.Fprintf(&b, " · %s with listening\n", days(st.DaysListened))
+
+	writePeriods(&b, "Hours per week", r.Weeks, width)
+	writePeriods(&b, "Hours per month", r.Months, width)
+	writeEntries(&b, "Top stations", r.TopStations, width)
+	writeEntries(&b, "Top tags", r.TopTags, width)
+	writeEntries(&b, "Top countries", r.TopCountries, width)
+	writeEntries(&b, "Top languages", r.TopLanguages, width)
+	writeHeatmap(&b, r.Heatmap)
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// days formats a number of days.
+func days(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+func writePeriods(b *strings.Builder, title string, periods []Period, width int) {
+	if len(periods) == 0 {
+		return
+	}
+	rows := make([]chartRow, len(periods))
+	for i, p := range periods {
+		rows[i] = chartRow{label: p.Label, value: p.Seconds}
+	}
+	writeChart(b, title, rows, width)
+}
+
+func writeEntries(b *strings.Builder, title string, entries []Entry, width int) {
+	rows := make([]chartRow, len(entries))
+	for i, e := range entries {
+		rows[i] = chartRow{label: fmt.Sprintf("%d. %s", i+1, e.Name), value: e.Seconds}
+	}
+	if len(rows) == 0 {
+		fmt.Fprintf(b, "\n%s\n  No listening time yet\n", title)
+		return
+	}
+	writeChart(b, title, rows, width)
+}
+
+type chartRow struct {
+	label string
+	value int64
+}
+
+// writeChart draws a horizontal bar chart of rows under title. Labels are
+// cut to a third of the width; the bars share what is left.
+func writeChart(b *strings.Builder, title string, rows []chartRow, width int) {
+	fmt.Fprintf(b, "\n%s\n", title)
+	labelWidth := 0
+	var maxValue int64
+	for _, row := range rows {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(row.label))
+		maxValue = max(maxValue, row.value)
+	}
+	labelWidth = min(labelWidth, width/3)
+	const valueWidth = 8 // "123h 45m"
+	barWidth := max(width-labelWidth-valueWidth-6, 10)
+	for _, row := range rows {
+		// fmt pads by runes, which matches the block characters in bars
+		fmt.Fprintf(b, "  %-*s  %-*s  %s\n", labelWidth, truncate(row.label, labelWidth),
+			barWidth, bar(row.value, maxValue, barWidth), storage.FormatDuration(row.value))
+	}
+}
+
+// truncate shortens s to at most n runes, appending "…" if trimmed.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// bar draws value as a bar of up to width cells, full at maxValue.
+func bar(value, maxValue int64, width int) string {
+	if value <= 0 || maxValue <= 0 || width <= 0 {
+		return ""
+	}
+	eighths := int(value * int64(width) * 8 / maxValue)
+	if eighths == 0 {
+		eighths = 1 // Show that there was some listening
+	}
+	return strings.Repeat("█", eighths/8) + barEighths[eighths%8]
+}
+
+// writeHeatmap draws hour of day across and weekday down, two columns per
+// hour.
+func writeHeatmap(b *strings.Builder, heatmap [7][24]int64) {
+	b.WriteString("\nListening by hour and weekday\n")
+	var maxValue int64
+	for _, row := range heatmap {
+		for _, v := range row {
+			maxValue = max(maxValue, v)
+		}
+	}
+	// Hour labels every three hours line up over the "  Mon " row prefix
+	var hours strings.Builder
+	for h := 0; h < 24; h += 3 {
+		fmt.Fprintf(&hours, "%-6s", fmt.Sprintf("%02d", h))
+	}
+	fmt.Fprintf(b, "      %s\n", strings.TrimRight(hours.String(), " "))
+	for d, row := range heatmap {
+		fmt.Fprintf(b, "  %s ", weekdayNames[d])
+		for _, v := range row {
+			cell := heatShades[0]
+			if v > 0 && maxValue > 0 {
+				// Any listening shows at least the lightest shade
+				cell = heatShades[1+int(v*int64(len(heatShades)-2)/maxValue)]
+			}
+			b.WriteString(cell + cell)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(b, "      %s none  %s  %s  %s  %s most\n", heatShades[0], heatShades[1], heatShades[2], heatShades[3], heatShades[4])
+}
@@ -0,0 +1,269 @@
+// Package stats builds listening reports from the session log and the play
+// statistics kept by the metadata store.
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// Options controls how much history a report covers.
+type Options struct {
+	Weeks  int // Weekly totals shown, ending with the current week
+	Months int // Monthly totals shown, ending with the current month
+	Top    int // Entries in each top list
+}
+
+// DefaultOptions returns Options with sensible defaults.
+func DefaultOptions() Options {
+	return Options{
+		Weeks:  8,
+		Months: 6,
+		Top:    5,
+	}
+}
+
+// Period is the listening time in one week or month.
+type Period struct {
+	Start   time.Time `json:"start"`
+	Label   string    `json:"label"`
+	Seconds int64     `json:"seconds"`
+}
+
+// Entry is one row of a top list.
+type Entry struct {
+	Name    string `json:"name"`
+	Seconds int64  `json:"seconds"`
+	Plays   int    `json:"plays"`
+}
+
+// Streaks counts consecutive days with listening.
+type Streaks struct {
+	CurrentDays  int       `json:"current_days"` // Ends today, or yesterday if nothing was played yet today
+	LongestDays  int       `json:"longest_days"`
+	LongestStart time.Time `json:"longest_start"` // Zero when there is no streak
+	LongestEnd   time.Time `json:"longest_end"`
+	DaysListened int       `json:"days_listened"`
+}
+
+// Report is a listening report. Weekly and monthly totals, the heatmap and
+// streaks come from the session log; the top lists and TotalSeconds come
+// from the play statistics, which go back further.
+type Report struct {
+	Generated     time.Time `json:"generated"`
+	TotalSeconds  int64     `json:"total_seconds"`  // All-time listening time
+	LoggedSeconds int64     `json:"logged_seconds"` // Listening time in the session log
+	Sessions      int       `json:"sessions"`
+	Weeks         []Period  `json:"weeks"`
+	Months        []Period  `json:"months"`
+	TopStations   []Entry   `json:"top_stations"`
+	TopTags       []Entry   `json:"top_tags"`
+	TopCountries  []Entry   `json:"top_countries"`
+	TopLanguages  []Entry   `json:"top_languages"`
+	// Heatmap holds seconds listened by weekday (Monday first) and hour of
+	// day.
+	Heatmap [7][24]int64 `json:"heatmap"`
+	Streaks Streaks      `json:"streaks"`
+}
+
+// Build computes a report as of now. Times are bucketed in now's location.
+func Build(sessions []storage.ListeningSession, stations []storage.StationWithMetadata, now time.Time, opts Options) Report {
+	loc := now.Location()
+	r := Report{Generated: now, Sessions: len(sessions)}
+
+	// Split every session at hour boundaries so time spent across midnight
+	// or the end of a week lands in the right bucket.
+	days := make(map[time.Time]int64)
+	weeks := make(map[time.Time]int64)
+	months := make(map[time.Time]int64)
+	for _, s := range sessions {
+		eachHour(s, loc, func(start time.Time, secs int64) {
+			r.LoggedSeconds += secs
+			r.Heatmap[weekdayIndex(start)][start.Hour()] += secs
+			day := startOfDay(start)
+			days[day] += secs
+			weeks[startOfWeek(day)] += secs
+			months[time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, loc)] += secs
+		})
+	}
+
+	thisWeek := startOfWeek(startOfDay(now))
+	for i := opts.Weeks - 1; i >= 0; i-- {
+		w := thisWeek.AddDate(0, 0, -7*i)
+		r.Weeks = append(r.Weeks, Period{Start: w, Label: w.Format("Jan 2"), Seconds: weeks[w]})
+	}
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	for i := opts.Months - 1; i >= 0; i-- {
+		m := thisMonth.AddDate(0, -i, 0)
+		r.Months = append(r.Months, Period{Start: m, Label: m.Format("Jan 2006"), Seconds: months[m]})
+	}
+
+	r.Streaks = streaks(days, startOfDay(now))
+
+	stationTotals := make(map[string]*Entry)
+	tagTotals := make(map[string]*Entry)
+	countryTotals := make(map[string]*Entry)
+	languageTotals := make(map[string]*Entry)
+	for _, s := range stations {
+		if s.Metadata == nil {
+			continue
+		}
+		secs, plays := s.Metadata.TotalDurationSeconds, s.Metadata.PlayCount
+		r.TotalSeconds += secs
+		name := s.Station.TrimName()
+		if name == "" {
+			name = s.Station.StationUUID
+		}
+		// Stations are keyed by UUID; two stations may share a name
+		add(stationTotals, s.Station.StationUUID, name, secs, plays)
+		for _, tag := range splitList(s.Station.Tags) {
+			add(tagTotals, tag, tag, secs, plays)
+		}
+		if country := strings.TrimSpace(s.Station.Country); country != "" {
+			add(countryTotals, strings.ToLower(country), country, secs, plays)
+		}
+		for _, lang := range splitList(s.Station.Language) {
+			add(languageTotals, lang, lang, secs, plays)
+		}
+	}
+	r.TopStations = top(stationTotals, opts.Top)
+	r.TopTags = top(tagTotals, opts.Top)
+	r.TopCountries = top(countryTotals, opts.Top)
+	r.TopLanguages = top(languageTotals, opts.Top)
+	return r
+}
+
+// eachHour calls fn with the start and length in seconds of each part of s
+// that falls within one clock hour in loc.
+func eachHour(s storage.ListeningSession, loc *time.Location, fn func(start time.Time, secs int64)) {
+	start, end := s.Start.In(loc), s.End.In(loc)
+	for start.Before(end) {
+		next := time.Date(start.Year(), start.Month(), start.Day(), start.Hour()+1, 0, 0, 0, loc)
+		if next.After(end) {
+			next = end
+		}
+		fn(start, int64(next.Sub(start).Seconds()))
+		start = next
+	}
+}
+
+// add adds listening time to the entry for key, creating it as name.
+func add(totals map[string]*Entry, key, name string, secs int64, plays int) {
+	e, ok := totals[key]
+	if !ok {
+		e = &Entry{Name: name}
+		totals[key] = e
+	}
+	e.Seconds += secs
+	e.Plays += plays
+}
+
+// top returns up to n entries with the most listening time, then the most
+// plays. Entries with no listening time are left out.
+func top(totals map[string]*Entry, n int) []Entry {
+	entries := make([]Entry, 0, len(totals))
+	for _, e := range totals {
+		if e.Seconds > 0 {
+			entries = append(entries, *e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Seconds != b.Seconds {
+			return a.Seconds > b.Seconds
+		}
+		if a.Plays != b.Plays {
+			return a.Plays > b.Plays
+		}
+		return a.Name < b.Name
+	})
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+// splitList splits a Radio Browser comma-separated field such as tags or
+// languages into lowercase items.
+func splitList(s string) []string {
+	var items []string
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item != "" && !seen[item] {
+			seen[item] = true
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// streaks counts runs of consecutive days in days, as of today.
+func streaks(days map[time.Time]int64, today time.Time) Streaks {
+	var st Streaks
+	sorted := make([]time.Time, 0, len(days))
+	for day, secs := range days {
+		if secs > 0 {
+			sorted = append(sorted, day)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+	st.DaysListened = len(sorted)
+
+	run := 0
+	var runStart time.Time
+	for i, day := range sorted {
+		if i > 0 && sorted[i-1].AddDate(0, 0, 1).Equal(day) {
+			run++
+		} else {
+			run, runStart = 1, day
+		}
+		if run > st.LongestDays {
+			st.LongestDays, st.LongestStart, st.LongestEnd = run, runStart, day
+		}
+	}
+
+	// The current streak survives until a whole day passes without listening
+	day := today
+	if days[day] <= 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	for days[day] > 0 {
+		st.CurrentDays++
+		day = day.AddDate(0, 0, -1)
+	}
+	return st
+}
+
+// startOfDay returns midnight on t's date in t's location.
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek returns the Monday on or before day.
+func startOfWeek(day time.Time) time.Time {
+	return day.AddDate(0, 0, -weekdayIndex(day))
+}
+
+// weekdayIndex numbers weekdays from Monday (0) to Sunday (6).
+func weekdayIndex(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+// Collect builds a report from the session log in dataPath and the play
+// statistics in meta.
+func Collect(dataPath string, meta *storage.MetadataManager, now time.Time, opts Options) (Report, error) {
+	cfg, _ := storage.LoadSessionLogConfigFromUnified()
+	sessions, err := storage.NewSessionLog(dataPath, cfg.RetentionDays).All()
+	if err != nil {
+		return Report{}, err
+	}
+	var stations []storage.StationWithMetadata
+	if meta != nil {
+		stations = meta.GetTopPlayed(0)
+	}
+	return Build(sessions, stations, now, opts), nil
+}
@@ -0,0 +1,153 @@
+package stats
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// Wednesday, October 14 2026 at noon
+var testNow = time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+func session(uuid string, start time.Time, d time.Duration) storage.ListeningSession {
+	return storage.ListeningSession{StationUUID: uuid, Start: start, End: start.Add(d)}
+}
+
+func TestBuild_BucketsSessions(t *testing.T) {
+	sunday := time.Date(2026, 10, 11, 23, 30, 0, 0, time.UTC)
+	sessions := []storage.ListeningSession{
+		// Crosses midnight into Monday, the start of a new week
+		session("a", sunday, time.Hour),
+		session("a", time.Date(2026, 10, 13, 8, 0, 0, 0, time.UTC), 2*time.Hour),
+		session("b", time.Date(2026, 9, 30, 20, 0, 0, 0, time.UTC), 90*time.Minute),
+	}
+	r := Build(sessions, nil, testNow, DefaultOptions())
+
+	if r.LoggedSeconds != int64((4*time.Hour + 30*time.Minute).Seconds()) {
+		t.Errorf("LoggedSeconds = %d", r.LoggedSeconds)
+	}
+	if got := r.Heatmap[6][23]; got != 1800 {
+		t.Errorf("Sunday 23:00 = %d, want 1800", got)
+	}
+	if got := r.Heatmap[0][0]; got != 1800 {
+		t.Errorf("Monday 00:00 = %d, want 1800", got)
+	}
+	if got := r.Heatmap[1][8] + r.Heatmap[1][9]; got != 7200 {
+		t.Errorf("Tuesday morning = %d, want 7200", got)
+	}
+
+	if len(r.Weeks) != 8 || len(r.Months) != 6 {
+		t.Fatalf("expected 8 weeks and 6 months, got %d and %d", len(r.Weeks), len(r.Months))
+	}
+	thisWeek, lastWeek := r.Weeks[7], r.Weeks[6]
+	if thisWeek.Label != "Oct 12" || thisWeek.Seconds != 1800+7200 {
+		t.Errorf("unexpected current week: %+v", thisWeek)
+	}
+	if lastWeek.Seconds != 1800 {
+		t.Errorf("expected the Sunday half hour in the previous week, got %+v", lastWeek)
+	}
+	if r.Months[5].Label != "Oct 2026" || r.Months[5].Seconds != 3600+7200 || r.Months[4].Seconds != 5400 {
+		t.Errorf("unexpected months: %+v", r.Months[4:])
+	}
+}
+
+func TestBuild_Streaks(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 9, 0, 0, 0, time.UTC) }
+	var sessions []storage.ListeningSession
+	for _, d := range []int{1, 2, 3, 4, 8, 12, 13} {
+		sessions = append(sessions, session("a", day(d), time.Minute))
+	}
+
+	// Nothing yet today: the streak that ended yesterday still counts
+	st := Build(sessions, nil, testNow, DefaultOptions()).Streaks
+	if st.CurrentDays != 2 || st.LongestDays != 4 || st.DaysListened != 7 {
+		t.Errorf("unexpected streaks: %+v", st)
+	}
+	if !st.LongestStart.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) || st.LongestEnd.Day() != 4 {
+		t.Errorf("unexpected longest streak: %v – %v", st.LongestStart, st.LongestEnd)
+	}
+
+	st = Build(sessions, nil, testNow.AddDate(0, 0, 2), DefaultOptions()).Streaks
+	if st.CurrentDays != 0 {
+		t.Errorf("expected the streak to end after a day off, got %d", st.CurrentDays)
+	}
+}
+
+func TestBuild_TopLists(t *testing.T) {
+	stations := []storage.StationWithMetadata{
+		{
+			Station:  api.Station{StationUUID: "a", Name: "Jazz FM", Tags: "jazz,Smooth Jazz", Country: "UK", Language: "english"},
+			Metadata: &storage.StationMetadata{PlayCount: 3, TotalDurationSeconds: 600},
+		},
+		{
+			Station:  api.Station{StationUUID: "b", Name: "Radio Swiss Jazz", Tags: "jazz", Country: "Switzerland", Language: "german,french"},
+			Metadata: &storage.StationMetadata{PlayCount: 1, TotalDurationSeconds: 900},
+		},
+		{
+			Station:  api.Station{StationUUID: "c", Name: "Never Finished"},
+			Metadata: &storage.StationMetadata{PlayCount: 1},
+		},
+	}
+	r := Build(nil, stations, testNow, DefaultOptions())
+
+	if r.TotalSeconds != 1500 {
+		t.Errorf("TotalSeconds = %d, want 1500", r.TotalSeconds)
+	}
+	if len(r.TopStations) != 2 || r.TopStations[0].Name != "Radio Swiss Jazz" {
+		t.Errorf("unexpected top stations: %+v", r.TopStations)
+	}
+	if len(r.TopTags) != 2 || r.TopTags[0].Name != "jazz" || r.TopTags[0].Seconds != 1500 || r.TopTags[0].Plays != 4 {
+		t.Errorf("unexpected top tags: %+v", r.TopTags)
+	}
+	if len(r.TopLanguages) != 3 || r.TopCountries[0].Name != "Switzerland" {
+		t.Errorf("unexpected languages or countries: %+v %+v", r.TopLanguages, r.TopCountries)
+	}
+}
+
+func TestRender(t *testing.T) {
+	stations := []storage.StationWithMetadata{{
+		Station:  api.Station{StationUUID: "a", Name: "Jazz FM", Tags: "jazz"},
+		Metadata: &storage.StationMetadata{PlayCount: 2, TotalDurationSeconds: 5400},
+	}}
+	sessions := []storage.ListeningSession{session("a", time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC), 90*time.Minute)}
+	r := Build(sessions, stations, testNow, DefaultOptions())
+
+	out := Render(r, 80)
+	for _, want := range []string{"Total listening: 1h 30m", "Hours per week", "1. Jazz FM", "Top countries\n  No listening time yet", "Wed ··", "Streak: 1 day now"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if n := len([]rune(line)); n > 80 {
+			t.Errorf("line is %d runes wide: %q", n, line)
+		}
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"top_stations":[{"name":"Jazz FM","seconds":5400,"plays":2}]`) {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
+
+func TestBar(t *testing.T) {
+	if got := bar(0, 10, 8); got != "" {
+		t.Errorf("bar(0) = %q", got)
+	}
+	if got := bar(10, 10, 4); got != "████" {
+		t.Errorf("bar(max) = %q", got)
+	}
+	if got := bar(5, 10, 3); got != "█▌" {
+		t.Errorf("bar(half) = %q", got)
+	}
+	if got := bar(1, 1000, 4); got != "▏" {
+		t.Errorf("a tiny value should still show, got %q", got)
+	}
+}
@@ -0,0 +1,298 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// DefaultYearTop is the number of entries in each list of a year report.
+const DefaultYearTop = 10
+
+// StationEntry is a station in a year report.
+type StationEntry struct {
+	UUID        string    `json:"uuid"`
+	Name        string    `json:"name"`
+	Seconds     int64     `json:"seconds"`
+	Plays       int       `json:"plays"`
+	Rating      int       `json:"rating,omitempty"` // 1-5 stars, 0 when unrated
+	FirstPlayed time.Time `json:"first_played,omitempty"`
+}
+
+// SongEntry is a liked song in a year report.
+type SongEntry struct {
+	Song    string    `json:"song"`
+	Station string    `json:"station"` // Where it was liked most recently
+	Likes   int       `json:"likes"`
+	LastAt  time.Time `json:"last_liked_at"`
+}
+
+// YearData is everything a year report is built from.
+type YearData struct {
+	Sessions   []storage.ListeningSession
+	Stations   []storage.StationWithMetadata
+	Ratings    []storage.StationWithRating
+	CustomTags map[string][]string // Custom tags by station UUID
+	LikedSongs []storage.LikedSong
+}
+
+// YearReport is a year-in-review summary.
+//
+// Listening time comes from the session log. When the log has nothing from
+// the year, for instance because it was kept for less time, the stations
+// played that year are ranked by their all-time play statistics instead and
+// FromPlayStats is set.
+type YearReport struct {
+	Year          int            `json:"year"`
+	Generated     time.Time      `json:"generated"`
+	FromPlayStats bool           `json:"from_play_stats"`
+	LogStart      time.Time      `json:"log_start,omitempty"` // Start of the session log, when it began during the year
+	TotalSeconds  int64          `json:"total_seconds"`
+	Sessions      int            `json:"sessions"`
+	DaysListened  int            `json:"days_listened"`
+	StationsHeard int            `json:"stations_heard"`
+	Months        []Period       `json:"months"`
+	TopStations   []StationEntry `json:"top_stations"`
+	TopGenres     []Entry        `json:"top_genres"`
+	NewStations   int            `json:"new_stations"`
+	Discoveries   []StationEntry `json:"discoveries"`
+	LikedSongs    int            `json:"liked_songs"`
+	TopSongs      []SongEntry    `json:"top_songs"`
+}
+
+// BuildYear computes the report for year, with dates in now's location.
+// Each list holds at most n entries.
+func BuildYear(year int, data YearData, now time.Time, n int) YearReport {
+	loc := now.Location()
+	yearStart := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
+	yearEnd := yearStart.AddDate(1, 0, 0)
+	inYear := func(t time.Time) bool {
+		return !t.IsZero() && !t.Before(yearStart) && t.Before(yearEnd)
+	}
+	r := YearReport{Year: year, Generated: now}
+
+	stations := make(map[string]storage.StationWithMetadata, len(data.Stations))
+	for _, s := range data.Stations {
+		stations[s.Station.StationUUID] = s
+	}
+	ratings := make(map[string]int, len(data.Ratings))
+	for _, s := range data.Ratings {
+		if s.Rating != nil {
+			ratings[s.Station.StationUUID] = s.Rating.Rating
+		}
+	}
+
+	months := make(map[time.Month]int64)
+	days := make(map[time.Time]bool)
+	heard := make(map[string]*StationEntry)
+	entry := func(uuid, name string) *StationEntry {
+		e, ok := heard[uuid]
+		if !ok {
+			e = &StationEntry{UUID: uuid, Name: name, Rating: ratings[uuid]}
+			if s, ok := stations[uuid]; ok {
+				if n := s.Station.TrimName(); n != "" {
+					e.Name = n
+				}
+				if s.Metadata != nil {
+					e.FirstPlayed = s.Metadata.FirstPlayed
+				}
+			}
+			if e.Name == "" {
+				e.Name = uuid
+			}
+			heard[uuid] = e
+		}
+		return e
+	}
+
+	for _, s := range data.Sessions {
+		if r.LogStart.IsZero() || s.Start.Before(r.LogStart) {
+			r.LogStart = s.Start
+		}
+		counted := false
+		eachHour(s, loc, func(start time.Time, secs int64) {
+			if !inYear(start) {
+				return
+			}
+			e := entry(s.StationUUID, s.StationName)
+			if !counted {
+				counted = true
+				r.Sessions++
+				e.Plays++
+			}
+			e.Seconds += secs
+			r.TotalSeconds += secs
+			months[start.Month()] += secs
+			days[startOfDay(start)] = true
+		})
+	}
+
+	if r.LogStart = r.LogStart.In(loc); !inYear(r.LogStart) {
+		r.LogStart = time.Time{}
+	}
+
+	if r.Sessions == 0 {
+		// Nothing logged this year: fall back to the all-time statistics of
+		// the stations that were played in it.
+		for _, s := range data.Stations {
+			m := s.Metadata
+			if m == nil || !(inYear(m.LastPlayed) || inYear(m.FirstPlayed)) {
+				continue
+			}
+			r.FromPlayStats = true
+			e := entry(s.Station.StationUUID, "")
+			e.Seconds += m.TotalDurationSeconds
+			e.Plays += m.PlayCount
+			r.TotalSeconds += m.TotalDurationSeconds
+		}
+	} else {
+		for m := time.January; m <= time.December; m++ {
+			start := time.Date(year, m, 1, 0, 0, 0, 0, loc)
+			r.Months = append(r.Months, Period{Start: start, Label: start.Format("Jan"), Seconds: months[m]})
+		}
+	}
+	r.DaysListened = len(days)
+	r.StationsHeard = len(heard)
+
+	genres := make(map[string]*Entry)
+	var all, discoveries []StationEntry
+	for uuid, e := range heard {
+		all = append(all, *e)
+		if inYear(e.FirstPlayed) {
+			discoveries = append(discoveries, *e)
+		}
+		tags := splitList(stations[uuid].Station.Tags)
+		for _, tag := range data.CustomTags[uuid] {
+			if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" && !contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		for _, tag := range tags {
+			add(genres, tag, tag, e.Seconds, e.Plays)
+		}
+	}
+	r.TopStations = topStations(all, n)
+	r.TopGenres = top(genres, n)
+	r.NewStations = len(discoveries)
+	r.Discoveries = topStations(discoveries, n)
+
+	var liked []storage.LikedSong
+	for _, s := range data.LikedSongs {
+		if inYear(s.LikedAt.In(loc)) {
+			liked = append(liked, s)
+		}
+	}
+	r.LikedSongs = len(liked)
+	r.TopSongs = topSongs(liked, n)
+	return r
+}
+
+// topStations sorts entries by listening time, then plays, then name, and
+// keeps at most n.
+func topStations(entries []StationEntry, n int) []StationEntry {
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Seconds != b.Seconds {
+			return a.Seconds > b.Seconds
+		}
+		if a.Plays != b.Plays {
+			return a.Plays > b.Plays
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.UUID < b.UUID
+	})
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+// topSongs groups liked songs by how they display, ignoring case, and
+// returns the n liked most often; ties go to the most recently liked.
+func topSongs(songs []storage.LikedSong, n int) []SongEntry {
+	groups := make(map[string]*SongEntry)
+	for _, s := range songs {
+		name := strings.TrimSpace(s.Display())
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		e, ok := groups[key]
+		if !ok {
+			e = &SongEntry{Song: name}
+			groups[key] = e
+		}
+		e.Likes++
+		if s.LikedAt.After(e.LastAt) {
+			e.LastAt, e.Station = s.LikedAt, s.StationName
+		}
+	}
+	entries := make([]SongEntry, 0, len(groups))
+	for _, e := range groups {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Likes != b.Likes {
+			return a.Likes > b.Likes
+		}
+		if !a.LastAt.Equal(b.LastAt) {
+			return a.LastAt.After(b.LastAt)
+		}
+		return a.Song < b.Song
+	})
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+// YearStores are the stores a year report reads. Nil stores are skipped.
+type YearStores struct {
+	Metadata   *storage.MetadataManager
+	Ratings    *storage.RatingsManager
+	Tags       *storage.TagsManager
+	LikedSongs *storage.LikedSongsManager
+}
+
+// CollectYear builds the report for year from the session log in dataPath
+// and stores, with at most n entries in each list.
+func CollectYear(dataPath string, stores YearStores, year int, now time.Time, n int) (YearReport, error) {
+	var data YearData
+	// Every logged session is read, whatever the retention, so a report on
+	// an earlier year uses what is left of it.
+	sessions, err := storage.NewSessionLog(dataPath, 0).All()
+	if err != nil {
+		return YearReport{}, err
+	}
+	data.Sessions = sessions
+	if stores.Metadata != nil {
+		data.Stations = stores.Metadata.GetTopPlayed(0)
+	}
+	if stores.Ratings != nil {
+		data.Ratings = stores.Ratings.GetAllRated()
+	}
+	if stores.Tags != nil {
+		data.CustomTags = make(map[string][]string)
+		for _, uuid := range stores.Tags.GetTaggedStations() {
+			data.CustomTags[uuid] = stores.Tags.GetTags(uuid)
+		}
+	}
+	if stores.LikedSongs != nil {
+		data.LikedSongs = stores.LikedSongs.All()
+	}
+	return BuildYear(year, data, now, n), nil
+}
@@ -0,0 +1,351 @@
+package stats
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// Year report formats.
+const (
+	YearFormatMarkdown = "md"
+	YearFormatHTML     = "html"
+)
+
+// markdownBarWidth is the widest bar in a Markdown chart, in cells.
+const markdownBarWidth = 20
+
+// RenderYear writes r to w as a self-contained Markdown or HTML document.
+func RenderYear(w io.Writer, r YearReport, format string) error {
+	switch format {
+	case YearFormatMarkdown:
+		_, err := io.WriteString(w, yearMarkdown(r))
+		return err
+	case YearFormatHTML:
+		return yearHTML.Execute(w, newYearPage(r))
+	}
+	return fmt.Errorf("unknown report format %q", format)
+}
+
+// highlights returns the summary sentences at the top of a report.
+func highlights(r YearReport) []string {
+	var lines []string
+	listened := storage.FormatDuration(r.TotalSeconds) + " of radio"
+	switch {
+	case r.StationsHeard == 0:
+		lines = append(lines, fmt.Sprintf("No listening recorded in %d", r.Year))
+	case r.FromPlayStats:
+		lines = append(lines, fmt.Sprintf("%s across %s", listened, count(r.StationsHeard, "station")))
+	default:
+		lines = append(lines, fmt.Sprintf("%s over %s in %s", listened, count(r.DaysListened, "day"), count(r.Sessions, "session")))
+		lines = append(lines, fmt.Sprintf("%s heard", count(r.StationsHeard, "station")))
+	}
+	lines = append(lines, fmt.Sprintf("%s discovered", count(r.NewStations, "new station")))
+	lines = append(lines, fmt.Sprintf("%s liked", count(r.LikedSongs, "song")))
+	return lines
+}
+
+// sourceNote explains where listening time came from, or returns "" when
+// the session log covers the whole year.
+func sourceNote(r YearReport) string {
+	switch {
+	case r.FromPlayStats:
+		return fmt.Sprintf("The session log has no plays from %d, so listening time is each station's all-time total.", r.Year)
+	case !r.LogStart.IsZero():
+		return fmt.Sprintf("Listening time is counted from the session log, which starts on %s.", r.LogStart.Format("January 2"))
+	}
+	return ""
+}
+
+// count formats n with a noun, adding "s" for the plural.
+func count(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func yearMarkdown(r YearReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %d in Radio\n\n", r.Year)
+	fmt.Fprintf(&b, "_Generated by TERA on %s._\n\n", r.Generated.Format("January 2, 2006"))
+
+	b.WriteString("## Highlights\n\n")
+	for _, line := range highlights(r) {
+		fmt.Fprintf(&b, "- %s\n", line)
+	}
+	if note := sourceNote(r); note != "" {
+		fmt.Fprintf(&b, "\n> %s\n", note)
+	}
+
+	if len(r.Months) > 0 {
+		b.WriteString("\n## Month by Month\n\n| Month | Listening | |\n|---|---:|---|\n")
+		var maxValue int64
+		for _, m := range r.Months {
+			maxValue = max(maxValue, m.Seconds)
+		}
+		for _, m := range r.Months {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", m.Label, storage.FormatDuration(m.Seconds), markdownBar(m.Seconds, maxValue))
+		}
+	}
+
+	b.WriteString("\n## Top Stations\n\n")
+	if len(r.TopStations) == 0 {
+		b.WriteString("No stations played.\n")
+	} else {
+		writeMarkdownStations(&b, r.TopStations)
+	}
+
+	b.WriteString("\n## Top Genres\n\n")
+	if len(r.TopGenres) == 0 {
+		b.WriteString("No genres yet.\n")
+	} else {
+		b.WriteString("| # | Genre | Listening | |\n|---:|---|---:|---|\n")
+		for i, g := range r.TopGenres {
+			fmt.Fprintf(&b, "| %d | %s | %s | %s |\n", i+1, markdownEscape(g.Name), storage.FormatDuration(g.Seconds),
+				markdownBar(g.Seconds, r.TopGenres[0].Seconds))
+		}
+	}
+
+	b.WriteString("\n## New Discoveries\n\n")
+	if len(r.Discoveries) == 0 {
+		fmt.Fprintf(&b, "No new stations in %d.\n", r.Year)
+	} else {
+		writeMarkdownStations(&b, r.Discoveries)
+	}
+
+	b.WriteString("\n## Most-Liked Songs\n\n")
+	if len(r.TopSongs) == 0 {
+		fmt.Fprintf(&b, "No songs liked in %d.\n", r.Year)
+	} else {
+		b.WriteString("| # | Song | Station | Likes |\n|---:|---|---|---:|\n")
+		for i, s := range r.TopSongs {
+			fmt.Fprintf(&b, "| %d | %s | %s | %d |\n", i+1, markdownEscape(s.Song), markdownEscape(s.Station), s.Likes)
+		}
+	}
+	return b.String()
+}
+
+func writeMarkdownStations(b *strings.Builder, stations []StationEntry) {
+	b.WriteString("| # | Station | Listening | Plays | Rating | |\n|---:|---|---:|---:|---|---|\n")
+	for i, s := range stations {
+		fmt.Fprintf(b, "| %d | %s | %s | %d | %s | %s |\n", i+1, markdownEscape(s.Name), storage.FormatDuration(s.Seconds),
+			s.Plays, storage.RenderStarsCompact(s.Rating, true), markdownBar(s.Seconds, stations[0].Seconds))
+	}
+}
+
+// markdownBar draws a bar chart cell as inline code so it keeps its width.
+func markdownBar(value, maxValue int64) string {
+	if b := bar(value, maxValue, markdownBarWidth); b != "" {
+		return "`" + b + "`"
+	}
+	return ""
+}
+
+// markdownEscape keeps text from breaking a table or turning into markup.
+func markdownEscape(s string) string {
+	r := strings.NewReplacer("|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`", "<", "&lt;", "\n", " ")
+	return r.Replace(s)
+}
+
+// yearPage is the data behind the HTML report.
+type yearPage struct {
+	Year       int
+	Generated  string
+	Highlights []string
+	Note       string
+	Months     []column
+	Stations   []row
+	Genres     []row
+	New        []row
+	Songs      []SongEntry
+}
+
+// column is one bar of the monthly SVG chart.
+type column struct {
+	Label, Value string
+	X, Y, Height int
+}
+
+// row is one bar of a horizontal HTML chart.
+type row struct {
+	Name, Value, Detail string
+	Percent             int
+}
+
+// Monthly chart geometry, in SVG units.
+const (
+	chartColumnWidth = 40
+	chartHeight      = 160
+)
+
+func newYearPage(r YearReport) yearPage {
+	p := yearPage{
+		Year:       r.Year,
+		Generated:  r.Generated.Format("January 2, 2006"),
+		Highlights: highlights(r),
+		Note:       sourceNote(r),
+		Songs:      r.TopSongs,
+	}
+	var maxValue int64
+	for _, m := range r.Months {
+		maxValue = max(maxValue, m.Seconds)
+	}
+	for i, m := range r.Months {
+		h := 0
+		if maxValue > 0 {
+			h = int(m.Seconds * chartHeight / maxValue)
+		}
+		if m.Seconds > 0 && h == 0 {
+			h = 1 // Show that there was some listening
+		}
+		p.Months = append(p.Months, column{
+			Label:  m.Label,
+			Value:  storage.FormatDuration(m.Seconds),
+			X:      i * chartColumnWidth,
+			Y:      chartHeight - h,
+			Height: h,
+		})
+	}
+	p.Stations = stationRows(r.TopStations)
+	p.New = stationRows(r.Discoveries)
+	for _, g := range r.TopGenres {
+		p.Genres = append(p.Genres, row{
+			Name:    g.Name,
+			Value:   storage.FormatDuration(g.Seconds),
+			Detail:  count(g.Plays, "play"),
+			Percent: percent(g.Seconds, r.TopGenres[0].Seconds),
+		})
+	}
+	return p
+}
+
+func stationRows(stations []StationEntry) []row {
+	rows := make([]row, 0, len(stations))
+	for _, s := range stations {
+		detail := count(s.Plays, "play")
+		if stars := storage.RenderStarsCompact(s.Rating, true); stars != "" {
+			detail += " · " + stars
+		}
+		rows = append(rows, row{
+			Name:    s.Name,
+			Value:   storage.FormatDuration(s.Seconds),
+			Detail:  detail,
+			Percent: percent(s.Seconds, stations[0].Seconds),
+		})
+	}
+	return rows
+}
+
+// percent returns value as a whole percentage of maxValue, at least 1 for
+// any listening.
+func percent(value, maxValue int64) int {
+	if value <= 0 || maxValue <= 0 {
+		return 0
+	}
+	return max(int(value*100/maxValue), 1)
+}
+
+// yearHTML is a single page with inline styles and charts, so the report
+// opens anywhere without a network connection.
+var yearHTML = template.Must(template.New("year").Funcs(template.FuncMap{
+	"add": func(a, b int) int { return a + b },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Year}} in Radio</title>
+<style>
+:root { --bg: #fdfcff; --fg: #1f1d2b; --dim: #6b6880; --accent: #7c5cff; --track: #ebe7ff; }
+@media (prefers-color-scheme: dark) {
+  :root { --bg: #16141f; --fg: #ece9f7; --dim: #a09cb5; --accent: #a18bff; --track: #2a2640; }
+}
+body { margin: 0; background: var(--bg); color: var(--fg); font: 16px/1.5 system-ui, sans-serif; }
+main { max-width: 760px; margin: 0 auto; padding: 2rem 1.25rem 3rem; }
+h1 { font-size: 2.5rem; margin: 0; }
+h2 { margin-top: 2.5rem; border-bottom: 2px solid var(--track); padding-bottom: .25rem; }
+.dim, .note { color: var(--dim); }
+.note { border-left: 3px solid var(--accent); padding-left: .75rem; }
+.highlights { list-style: none; padding: 0; font-size: 1.2rem; }
+.highlights li::before { content: "♪ "; color: var(--accent); }
+.row { display: grid; grid-template-columns: 2rem 1fr 6rem; gap: .5rem; align-items: center; margin: .5rem 0; }
+.row .rank { color: var(--dim); text-align: right; }
+.row .value { text-align: right; font-variant-numeric: tabular-nums; }
+.row .detail { grid-column: 2 / 4; color: var(--dim); font-size: .85rem; margin-top: -.35rem; }
+.track { grid-column: 2 / 4; height: .5rem; background: var(--track); border-radius: .25rem; }
+.bar { height: 100%; background: var(--accent); border-radius: .25rem; }
+svg { width: 100%; height: auto; }
+svg rect { fill: var(--accent); }
+svg text { fill: var(--dim); font-size: 11px; text-anchor: middle; }
+table { border-collapse: collapse; width: 100%; }
+td, th { text-align: left; padding: .4rem .5rem; border-bottom: 1px solid var(--track); }
+td.num { text-align: right; }
+</style>
+</head>
+<body>
+<main>
+<h1>{{.Year}} in Radio</h1>
+<p class="dim">Generated by TERA on {{.Generated}}</p>
+
+<ul class="highlights">
+{{- range .Highlights}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- with .Note}}
+<p class="note">{{.}}</p>
+{{- end}}
+{{- if .Months}}
+
+<h2>Month by Month</h2>
+<svg viewBox="0 -20 480 200" role="img" aria-label="Listening time per month">
+{{- range .Months}}
+<g><title>{{.Label}}: {{.Value}}</title><rect x="{{.X}}" y="{{.Y}}" width="32" height="{{.Height}}" rx="3"></rect><text x="{{.X}}" dx="16" y="176">{{.Label}}</text></g>
+{{- end}}
+</svg>
+{{- end}}
+
+<h2>Top Stations</h2>
+{{- template "rows" .Stations}}
+{{- if not .Stations}}
+<p class="dim">No stations played.</p>
+{{- end}}
+
+<h2>Top Genres</h2>
+{{- template "rows" .Genres}}
+{{- if not .Genres}}
+<p class="dim">No genres yet.</p>
+{{- end}}
+
+<h2>New Discoveries</h2>
+{{- template "rows" .New}}
+{{- if not .New}}
+<p class="dim">No new stations in {{.Year}}.</p>
+{{- end}}
+
+<h2>Most-Liked Songs</h2>
+{{- if .Songs}}
+<table>
+<tr><th>#</th><th>Song</th><th>Station</th><th>Likes</th></tr>
+{{- range $i, $s := .Songs}}
+<tr><td>{{add $i 1}}</td><td>{{$s.Song}}</td><td>{{$s.Station}}</td><td class="num">{{$s.Likes}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p class="dim">No songs liked in {{.Year}}.</p>
+{{- end}}
+</main>
+</body>
+</html>
+{{define "rows"}}
+{{- range $i, $r := .}}
+<div class="row"><span class="rank">{{add $i 1}}</span><span>{{$r.Name}}</span><span class="value">{{$r.Value}}</span>
+<div class="track"><div class="bar" style="width: {{$r.Percent}}%"></div></div>
+<span class="detail">{{$r.Detail}}</span></div>
+{{- end}}
+{{- end}}
+`))
@@ -0,0 +1,139 @@
+package stats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+func yearTestData() YearData {
+	return YearData{
+		Sessions: []storage.ListeningSession{
+			// Started on New Year's Eve: only the half hour after midnight counts
+			session("a", time.Date(2025, 12, 31, 23, 30, 0, 0, time.UTC), time.Hour),
+			session("a", time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC), 2*time.Hour),
+			session("b", time.Date(2026, 3, 3, 20, 0, 0, 0, time.UTC), time.Hour),
+		},
+		Stations: []storage.StationWithMetadata{
+			{
+				Station:  api.Station{StationUUID: "a", Name: "Jazz <FM>", Tags: "jazz,smooth jazz"},
+				Metadata: &storage.StationMetadata{PlayCount: 9, FirstPlayed: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+			},
+			{
+				Station:  api.Station{StationUUID: "b", Name: "Radio | Swiss", Tags: "jazz"},
+				Metadata: &storage.StationMetadata{PlayCount: 1, FirstPlayed: time.Date(2026, 3, 3, 20, 0, 0, 0, time.UTC)},
+			},
+		},
+		Ratings: []storage.StationWithRating{
+			{Station: api.Station{StationUUID: "a"}, Rating: &storage.StationRating{Rating: 5}},
+		},
+		CustomTags: map[string][]string{"b": {"Late Night", "jazz"}},
+		LikedSongs: []storage.LikedSong{
+			{Title: "Miles Davis - So What", Artist: "Miles Davis", Track: "So What", StationName: "Jazz FM", LikedAt: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)},
+			{Title: "MILES DAVIS - SO WHAT", Artist: "MILES DAVIS", Track: "SO WHAT", StationName: "Radio Swiss", LikedAt: time.Date(2026, 3, 3, 21, 0, 0, 0, time.UTC)},
+			{Title: "Nina Simone - Feeling Good", StationName: "Jazz FM", LikedAt: time.Date(2026, 3, 2, 8, 30, 0, 0, time.UTC)},
+			{Title: "Last Year's Song", StationName: "Jazz FM", LikedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+}
+
+func TestBuildYear(t *testing.T) {
+	r := BuildYear(2026, yearTestData(), testNow, DefaultYearTop)
+
+	if r.FromPlayStats || r.Sessions != 3 || r.TotalSeconds != int64((3*time.Hour+30*time.Minute).Seconds()) {
+		t.Errorf("unexpected totals: %+v", r)
+	}
+	if r.DaysListened != 3 || r.StationsHeard != 2 {
+		t.Errorf("DaysListened = %d, StationsHeard = %d", r.DaysListened, r.StationsHeard)
+	}
+	if len(r.Months) != 12 || r.Months[0].Seconds != 1800 || r.Months[2].Seconds != 3*3600 {
+		t.Errorf("unexpected months: %+v", r.Months)
+	}
+	if !r.LogStart.IsZero() {
+		t.Errorf("the log started the year before, got LogStart %v", r.LogStart)
+	}
+
+	if len(r.TopStations) != 2 || r.TopStations[0].Name != "Jazz <FM>" || r.TopStations[0].Rating != 5 || r.TopStations[0].Plays != 2 {
+		t.Errorf("unexpected top stations: %+v", r.TopStations)
+	}
+	if r.TopGenres[0].Name != "jazz" || r.TopGenres[0].Seconds != r.TotalSeconds {
+		t.Errorf("unexpected top genres: %+v", r.TopGenres)
+	}
+	if len(r.TopGenres) != 3 {
+		t.Errorf("custom tags should count as genres once each: %+v", r.TopGenres)
+	}
+	if r.NewStations != 1 || r.Discoveries[0].UUID != "b" {
+		t.Errorf("unexpected discoveries: %+v", r.Discoveries)
+	}
+
+	if r.LikedSongs != 3 || len(r.TopSongs) != 2 {
+		t.Fatalf("unexpected liked songs: %d %+v", r.LikedSongs, r.TopSongs)
+	}
+	if s := r.TopSongs[0]; s.Likes != 2 || s.Station != "Radio Swiss" {
+		t.Errorf("likes of the same song should be grouped: %+v", s)
+	}
+}
+
+func TestBuildYear_FallsBackToPlayStats(t *testing.T) {
+	data := yearTestData()
+	data.Sessions = nil
+	data.Stations[0].Metadata.LastPlayed = time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	data.Stations[0].Metadata.TotalDurationSeconds = 7200
+
+	r := BuildYear(2026, data, testNow, DefaultYearTop)
+	if !r.FromPlayStats || r.TotalSeconds != 7200 || r.StationsHeard != 2 || len(r.Months) != 0 {
+		t.Errorf("unexpected fallback report: %+v", r)
+	}
+	if r.TopStations[0].Plays != 9 {
+		t.Errorf("expected all-time plays, got %+v", r.TopStations[0])
+	}
+}
+
+func TestRenderYear(t *testing.T) {
+	data := yearTestData()
+	// The log starts in March, after the year began
+	data.Sessions = data.Sessions[1:]
+	r := BuildYear(2026, data, testNow, DefaultYearTop)
+
+	var md bytes.Buffer
+	if err := RenderYear(&md, r, YearFormatMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# 2026 in Radio",
+		"- 3h of radio over 2 days in 2 sessions",
+		"which starts on March 2",
+		"| Mar | 3h | `████████████████████` |",
+		"| 1 | Jazz &lt;FM> | 2h | 1 | ★ ★ ★ ★ ★ |",
+		`Radio \| Swiss`,
+		"| 1 | Miles Davis - So What | Radio Swiss | 2 |",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("expected %q in Markdown:\n%s", want, md.String())
+		}
+	}
+
+	var html bytes.Buffer
+	if err := RenderYear(&html, r, YearFormatHTML); err != nil {
+		t.Fatal(err)
+	}
+	out := html.String()
+	for _, want := range []string{"<title>2026 in Radio</title>", "Jazz &lt;FM&gt;", `style="width: 100%"`, "<title>Mar: 3h</title>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in HTML", want)
+		}
+	}
+	for _, external := range []string{"http://", "https://", "<script", "<link"} {
+		if strings.Contains(out, external) {
+			t.Errorf("the HTML report should be self-contained, found %q", external)
+		}
+	}
+
+	if err := RenderYear(&html, r, "pdf"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Automatic backups are zip exports named after the time they were taken,
+// kept together in one directory. Each one is a generation; rotation keeps
+// the newest few plus the last one of recent days and weeks.
+
+const (
+	autoBackupPrefix     = "tera-auto-"
+	autoBackupTimeLayout = "20060102-150405"
+	autoBackupDirName    = "backups"
+)
+
+// BackupGeneration is one automatic backup.
+type BackupGeneration struct {
+	Path string
+	Time time.Time
+	Size int64
+}
+
+// BackupRetention says which generations rotation keeps. A generation is
+// kept if any rule keeps it.
+type BackupRetention struct {
+	KeepLast   int // newest generations
+	KeepDaily  int // days, newest first, whose last generation is kept
+	KeepWeekly int // ISO weeks, newest first, whose last generation is kept
+}
+
+// AutoBackupDir returns the directory of automatic backups: dir with ~
+// expanded, or "backups" in the config directory when dir is empty.
+func (b *BackupManager) AutoBackupDir(dir string) (string, error) {
+	if dir == "" {
+		return filepath.Join(b.configDir, autoBackupDirName), nil
+	}
+	if dir == "~" || strings.HasPrefix(dir, "~/") || strings.HasPrefix(dir, "~\\") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to expand ~: %w", err)
+		}
+		return filepath.Join(home, dir[1:]), nil
+	}
+	return dir, nil
+}
+
+// AutoBackup exports prefs to a new generation in dir, encrypted unless
+// passphrase is empty, and returns its path.
+func (b *BackupManager) AutoBackup(dir string, prefs SyncPrefs, passphrase string, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	path := filepath.Join(dir, autoBackupPrefix+now.Format(autoBackupTimeLayout)+".zip")
+	if err := b.ExportWithPassphrase(path, prefs, passphrase); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ListBackupGenerations returns the automatic backups in dir, newest first.
+// A missing directory has none.
+func ListBackupGenerations(dir string) ([]BackupGeneration, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var gens []BackupGeneration
+	for _, e := range entries {
+		name := e.Name()
+		stamp, ok := strings.CutPrefix(name, autoBackupPrefix)
+		if !ok || e.IsDir() {
+			continue
+		}
+		stamp, ok = strings.CutSuffix(stamp, ".zip")
+		if !ok {
+			continue
+		}
+		t, err := time.ParseInLocation(autoBackupTimeLayout, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		gens = append(gens, BackupGeneration{Path: filepath.Join(dir, name), Time: t, Size: info.Size()})
+	}
+	sort.Slice(gens, func(i, j int) bool { return gens[i].Time.After(gens[j].Time) })
+	return gens, nil
+}
+
+// AutoBackupDue reports whether the newest generation in dir is at least
+// every old, or there is none.
+func AutoBackupDue(dir string, every time.Duration, now time.Time) (bool, error) {
+	gens, err := ListBackupGenerations(dir)
+	if err != nil {
+		return false, err
+	}
+	return len(gens) == 0 || now.Sub(gens[0].Time) >= every, nil
+}
+
+// RotateBackups deletes the generations in dir that keep does not keep and
+// returns their paths.
+func RotateBackups(dir string, keep BackupRetention) ([]string, error) {
+	gens, err := ListBackupGenerations(dir)
+	if err != nil {
+		return nil, err
+	}
+	kept := keptGenerations(gens, keep)
+	var removed []string
+	var errs []error
+	for _, g := range gens {
+		if kept[g.Path] {
+			continue
+		}
+		if err := os.Remove(g.Path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, g.Path)
+	}
+	return removed, errors.Join(errs...)
+}
+
+// keptGenerations applies keep to gens, which are sorted newest first. The
+// daily and weekly rules count periods that have a backup, so a break from
+// TERA does not use them up.
+func keptGenerations(gens []BackupGeneration, keep BackupRetention) map[string]bool {
+	kept := make(map[string]bool)
+	for i := 0; i < len(gens) && i < keep.KeepLast; i++ {
+		kept[gens[i].Path] = true
+	}
+	keepPerPeriod := func(limit int, period func(time.Time) string) {
+		seen := make(map[string]bool)
+		for _, g := range gens {
+			if len(seen) == limit {
+				return
+			}
+			p := period(g.Time)
+			if !seen[p] {
+				seen[p] = true
+				kept[g.Path] = true
+			}
+		}
+	}
+	keepPerPeriod(keep.KeepDaily, func(t time.Time) string { return t.Format("2006-01-02") })
+	keepPerPeriod(keep.KeepWeekly, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	})
+	return kept
+}
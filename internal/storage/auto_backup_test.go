@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAutoBackupAndListGenerations(t *testing.T) {
+	_, bm := setupBackupDir(t)
+	dir := filepath.Join(t.TempDir(), "backups")
+
+	if due, err := AutoBackupDue(dir, time.Hour, time.Now()); err != nil || !due {
+		t.Fatalf("empty dir due = %v, %v", due, err)
+	}
+	first := time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+	second := first.Add(2 * time.Hour)
+	for _, at := range []time.Time{first, second} {
+		if _, err := bm.AutoBackup(dir, DefaultSyncPrefs(), "", at); err != nil {
+			t.Fatalf("AutoBackup: %v", err)
+		}
+	}
+	// Other files in the directory are not generations
+	writeTestFile(t, filepath.Join(dir, "notes.txt"), "x")
+	writeTestFile(t, filepath.Join(dir, autoBackupPrefix+"garbage.zip"), "x")
+
+	gens, err := ListBackupGenerations(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gens) != 2 || !gens[0].Time.Equal(second) || !gens[1].Time.Equal(first) {
+		t.Fatalf("generations = %+v", gens)
+	}
+	if available, err := bm.ListArchiveCategories(gens[0].Path); err != nil || !available.Favorites {
+		t.Errorf("categories = %+v, %v", available, err)
+	}
+
+	if due, _ := AutoBackupDue(dir, 24*time.Hour, second.Add(time.Hour)); due {
+		t.Error("backup due an hour after the last one")
+	}
+	if due, _ := AutoBackupDue(dir, 24*time.Hour, second.Add(25*time.Hour)); !due {
+		t.Error("backup not due a day after the last one")
+	}
+	if gens, err := ListBackupGenerations(filepath.Join(dir, "missing")); err != nil || gens != nil {
+		t.Errorf("missing dir = %v, %v", gens, err)
+	}
+}
+
+func TestRotateBackups(t *testing.T) {
+	dir := t.TempDir()
+	// Four a day for ten days, at 06:00, 12:00, 18:00 and 23:00
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	for day := 0; day < 10; day++ {
+		for _, hour := range []int{6, 12, 18, 23} {
+			at := start.AddDate(0, 0, day).Add(time.Duration(hour) * time.Hour)
+			writeTestFile(t, filepath.Join(dir, autoBackupPrefix+at.Format(autoBackupTimeLayout)+".zip"), "zip")
+		}
+	}
+
+	removed, err := RotateBackups(dir, BackupRetention{KeepLast: 3, KeepDaily: 3, KeepWeekly: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gens, err := ListBackupGenerations(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kept []string
+	for _, g := range gens {
+		kept = append(kept, g.Time.Format("01-02 15"))
+	}
+	want := []string{
+		"10-10 23", "10-10 18", "10-10 12", // last 3
+		"10-09 23", "10-08 23", // daily, with 10-10
+		"10-04 23", // weekly: the week of 10-05 to 10-10 is kept already
+	}
+	if !reflect.DeepEqual(kept, want) {
+		t.Errorf("kept %v, want %v", kept, want)
+	}
+	if len(removed)+len(kept) != 40 {
+		t.Errorf("removed %d of 40", len(removed))
+	}
+
+	// Rotating again changes nothing
+	if removed, err := RotateBackups(dir, BackupRetention{KeepLast: 3, KeepDaily: 3, KeepWeekly: 2}); err != nil || len(removed) != 0 {
+		t.Errorf("second rotation removed %v, %v", removed, err)
+	}
+}
+
+func TestAutoBackupDir(t *testing.T) {
+	bm := &BackupManager{configDir: "/cfg"}
+	if dir, _ := bm.AutoBackupDir(""); dir != filepath.Join("/cfg", autoBackupDirName) {
+		t.Errorf("default dir = %s", dir)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	if dir, _ := bm.AutoBackupDir("~/tera-backups"); dir != filepath.Join(home, "tera-backups") {
+		t.Errorf("~ dir = %s", dir)
+	}
+}
@@ -2,8 +2,10 @@ package storage
 
 import (
 	"archive/zip"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
@@ -11,6 +13,10 @@ import (
 	"time"
 )
 
+// backupEncryptionEntry is the zip entry holding the encryption header of an
+// encrypted backup. Its presence marks every other entry as encrypted.
+const backupEncryptionEntry = "tera-encryption.json"
+
 // BackupManager handles zip-based export and restore of user data.
 type BackupManager struct {
 	configDir string
@@ -71,34 +77,54 @@ func ResolveBackupPath(path string) (string, error) {
 func (b *BackupManager) categoryFiles(prefs SyncPrefs) ([]string, error) {
 	var files []string
 
+	// With the SQLite backend the stats, ratings, tags and favorites files
+	// are exports; bring them up to date before they are copied.
+	if prefs.Favorites || prefs.RatingsVotes || prefs.MetadataTags {
+		if err := exportDatabaseJSON(filepath.Join(b.configDir, "data")); err != nil {
+			return nil, err
+		}
+	}
+
 	if prefs.Settings {
 		files = append(files, "config.yaml")
 	}
 	if prefs.Favorites || prefs.SearchHistory {
-		// Add *.json files from data/favorites/, routing search-history.json
-		// to SearchHistory and all others to Favorites.
+		// Add *.json files from data/favorites/ and its list folders,
+		// routing search-history.json to SearchHistory and all others,
+		// including the folders' list order files, to Favorites.
 		favDir := filepath.Join(b.configDir, "data", "favorites")
-		entries, err := os.ReadDir(favDir)
-		if err != nil {
-			if !os.IsNotExist(err) {
-				return nil, fmt.Errorf("failed to read favorites directory: %w", err)
+		err := filepath.WalkDir(favDir, func(p string, e fs.DirEntry, err error) error {
+			if err != nil {
+				return err
 			}
-		} else {
-			for _, e := range entries {
-				if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
-					continue
+			if e.IsDir() {
+				// Skip hidden folders such as the Gist restore .backup
+				if p != favDir && strings.HasPrefix(e.Name(), ".") {
+					return filepath.SkipDir
 				}
-				relPath := filepath.Join("data", "favorites", e.Name())
-				if e.Name() == SystemFileSearchHistory {
-					if prefs.SearchHistory {
-						files = append(files, relPath)
-					}
-					continue
-				}
-				if prefs.Favorites {
+				return nil
+			}
+			if !strings.HasSuffix(e.Name(), ".json") {
+				return nil
+			}
+			rel, err := filepath.Rel(favDir, p)
+			if err != nil {
+				return err
+			}
+			relPath := filepath.Join("data", "favorites", rel)
+			if rel == SystemFileSearchHistory {
+				if prefs.SearchHistory {
 					files = append(files, relPath)
 				}
+				return nil
+			}
+			if prefs.Favorites {
+				files = append(files, relPath)
 			}
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read favorites directory: %w", err)
 		}
 	}
 	if prefs.RatingsVotes {
@@ -114,7 +140,24 @@ func (b *BackupManager) categoryFiles(prefs SyncPrefs) ([]string, error) {
 		files = append(files,
 			filepath.Join("data", "station_metadata.json"),
 			filepath.Join("data", "station_tags.json"),
+			filepath.Join("data", StationNotesFileName),
 		)
+		// Monthly listening session logs from data/sessions/
+		entries, err := os.ReadDir(filepath.Join(b.configDir, "data", SessionsDirName))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to read sessions directory: %w", err)
+			}
+		} else {
+			for _, e := range entries {
+				if !e.IsDir() && IsSessionLogFile(e.Name()) {
+					files = append(files, filepath.Join("data", SessionsDirName, e.Name()))
+				}
+			}
+		}
+	}
+	if prefs.LikedSongs {
+		files = append(files, filepath.Join("data", LikedSongsFileName))
 	}
 
 	return files, nil
@@ -124,7 +167,14 @@ func (b *BackupManager) categoryFiles(prefs SyncPrefs) ([]string, error) {
 // Only files that actually exist on disk are included; missing files are silently skipped.
 // Uses a temp-file-and-rename strategy so a failed export never corrupts an
 // existing backup at the destination path.
-func (b *BackupManager) Export(destPath string, prefs SyncPrefs) (err error) {
+func (b *BackupManager) Export(destPath string, prefs SyncPrefs) error {
+	return b.ExportWithPassphrase(destPath, prefs, "")
+}
+
+// ExportWithPassphrase is Export, encrypting every file with passphrase
+// unless it is empty. Entry names stay readable, so the categories of an
+// encrypted backup can be listed without the passphrase.
+func (b *BackupManager) ExportWithPassphrase(destPath string, prefs SyncPrefs, passphrase string) (err error) {
 	resolved, err := ResolveBackupPath(destPath)
 	if err != nil {
 		return err
@@ -155,12 +205,22 @@ func (b *BackupManager) Export(destPath string, prefs SyncPrefs) (err error) {
 		if err != nil {
 			return err
 		}
+		var key []byte
+		if passphrase != "" {
+			var header *encryptionHeader
+			if header, key, err = newEncryptionHeader(passphrase); err != nil {
+				return fmt.Errorf("failed to set up encryption: %w", err)
+			}
+			if err := addEncryptionHeader(w, header); err != nil {
+				return err
+			}
+		}
 		for _, relPath := range categoryFiles {
 			absPath := filepath.Join(b.configDir, relPath)
 			if _, statErr := os.Stat(absPath); os.IsNotExist(statErr) {
 				continue // skip missing files silently
 			}
-			if addErr := addFileToZip(w, absPath, relPath); addErr != nil {
+			if addErr := addFileToZip(w, absPath, relPath, key); addErr != nil {
 				return fmt.Errorf("failed to add %s to archive: %w", relPath, addErr)
 			}
 		}
@@ -254,6 +314,17 @@ func (b *BackupManager) ConflictingFiles(srcPath string, prefs SyncPrefs) ([]str
 // When force is false and files already exist, it returns RestoreConflictError
 // listing the conflicting paths. Pass force=true to overwrite without checking.
 func (b *BackupManager) Restore(srcPath string, prefs SyncPrefs, force bool) error {
+	return b.RestoreWithPassphrase(srcPath, prefs, force, "")
+}
+
+// RestoreWithPassphrase is Restore for a backup that may be encrypted. An
+// encrypted one needs its passphrase: without it ErrPassphraseRequired is
+// returned, and ErrWrongPassphrase for a wrong one, before anything is
+// written.
+func (b *BackupManager) RestoreWithPassphrase(srcPath string, prefs SyncPrefs, force bool, passphrase string) error {
+	if err := b.CheckArchivePassphrase(srcPath, passphrase); err != nil {
+		return err
+	}
 	if !force {
 		conflicts, err := b.ConflictingFiles(srcPath, prefs)
 		if err != nil {
@@ -269,6 +340,10 @@ func (b *BackupManager) Restore(srcPath string, prefs SyncPrefs, force bool) err
 		return fmt.Errorf("failed to open zip archive: %w", err)
 	}
 	defer func() { _ = r.Close() }()
+	key, err := archiveKey(&r.Reader, passphrase)
+	if err != nil {
+		return err
+	}
 
 	// Walk every entry in the zip and restore those whose category is selected.
 	// We derive the category from the entry name directly rather than calling
@@ -290,12 +365,14 @@ func (b *BackupManager) Restore(srcPath string, prefs SyncPrefs, force bool) err
 		if err != nil {
 			return err
 		}
-		if err := extractFileFromZip(f, destPath); err != nil {
+		if err := extractFileFromZip(f, destPath, key); err != nil {
 			return fmt.Errorf("failed to restore %s: %w", slashName, err)
 		}
 	}
 
-	return nil
+	// With the SQLite backend the restored stats, ratings, tags and
+	// favorites only count once they are in the database.
+	return importDatabaseJSON(filepath.Join(b.configDir, "data"))
 }
 
 // zipEntryWanted reports whether a zip entry (named with forward slashes)
@@ -313,12 +390,23 @@ func zipEntryWanted(slashName string, prefs SyncPrefs) bool {
 		return prefs.RatingsVotes
 	case slashName == "data/blocklist.json":
 		return prefs.Blocklist
-	case slashName == "data/station_metadata.json" || slashName == "data/station_tags.json":
+	case slashName == "data/station_metadata.json" || slashName == "data/station_tags.json" ||
+		slashName == "data/"+StationNotesFileName ||
+		isSessionLogPath(slashName):
 		return prefs.MetadataTags
+	case slashName == "data/"+LikedSongsFileName:
+		return prefs.LikedSongs
 	}
 	return false
 }
 
+// isSessionLogPath reports whether slashName is a monthly session log in
+// data/sessions/.
+func isSessionLogPath(slashName string) bool {
+	dir, name := path.Split(slashName)
+	return dir == "data/"+SessionsDirName+"/" && IsSessionLogFile(name)
+}
+
 // ListArchiveCategories inspects a zip archive and returns which categories are present.
 func (b *BackupManager) ListArchiveCategories(srcPath string) (SyncPrefs, error) {
 	r, err := zip.OpenReader(srcPath)
@@ -344,38 +432,115 @@ func (b *BackupManager) ListArchiveCategories(srcPath string) (SyncPrefs, error)
 			prefs.RatingsVotes = true
 		case name == "data/blocklist.json":
 			prefs.Blocklist = true
-		case name == "data/station_metadata.json" || name == "data/station_tags.json":
+		case name == "data/station_metadata.json" || name == "data/station_tags.json" ||
+			name == "data/"+StationNotesFileName ||
+			isSessionLogPath(name):
 			prefs.MetadataTags = true
+		case name == "data/"+LikedSongsFileName:
+			prefs.LikedSongs = true
 		}
 	}
 	return prefs, nil
 }
 
+// IsArchiveEncrypted reports whether the backup at srcPath is encrypted.
+func (b *BackupManager) IsArchiveEncrypted(srcPath string) (bool, error) {
+	r, err := zip.OpenReader(srcPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to open zip archive: %w", err)
+	}
+	defer func() { _ = r.Close() }()
+	return findZipEntry(&r.Reader, backupEncryptionEntry) != nil, nil
+}
+
+// CheckArchivePassphrase returns nil if the backup at srcPath can be restored
+// with passphrase: it is not encrypted, or passphrase opens it.
+func (b *BackupManager) CheckArchivePassphrase(srcPath, passphrase string) error {
+	r, err := zip.OpenReader(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to open zip archive: %w", err)
+	}
+	defer func() { _ = r.Close() }()
+	_, err = archiveKey(&r.Reader, passphrase)
+	return err
+}
+
+// archiveKey returns the key of an encrypted archive, or nil for a plain one.
+func archiveKey(r *zip.Reader, passphrase string) ([]byte, error) {
+	f := findZipEntry(r, backupEncryptionEntry)
+	if f == nil {
+		return nil, nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", backupEncryptionEntry, err)
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(io.LimitReader(rc, 64<<10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", backupEncryptionEntry, err)
+	}
+	header, err := parseEncryptionHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	return header.unlock(passphrase)
+}
+
+func findZipEntry(r *zip.Reader, name string) *zip.File {
+	for _, f := range r.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// addEncryptionHeader writes the entry that marks the archive as encrypted.
+func addEncryptionHeader(w *zip.Writer, header *encryptionHeader) error {
+	data, err := json.MarshalIndent(header, "", "  ")
+	if err != nil {
+		return err
+	}
+	entry, err := w.Create(backupEncryptionEntry)
+	if err != nil {
+		return fmt.Errorf("failed to create zip entry: %w", err)
+	}
+	_, err = entry.Write(data)
+	return err
+}
+
 // addFileToZip adds the file at absPath to the zip writer using relPath as the
-// entry name inside the archive.
-func addFileToZip(w *zip.Writer, absPath, relPath string) error {
-	f, err := os.Open(absPath)
+// entry name inside the archive, encrypted with key unless it is nil.
+func addFileToZip(w *zip.Writer, absPath, relPath string, key []byte) error {
+	data, err := os.ReadFile(absPath)
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
 	}
-	defer func() { _ = f.Close() }()
 
 	// Use forward slashes in the zip entry name regardless of OS
-	entry, err := w.Create(filepath.ToSlash(relPath))
+	name := filepath.ToSlash(relPath)
+	if key != nil {
+		if data, err = seal(key, name, data); err != nil {
+			return fmt.Errorf("failed to encrypt: %w", err)
+		}
+	}
+	entry, err := w.Create(name)
 	if err != nil {
 		return fmt.Errorf("failed to create zip entry: %w", err)
 	}
 
-	if _, err := io.Copy(entry, f); err != nil {
+	if _, err := entry.Write(data); err != nil {
 		return fmt.Errorf("failed to write zip entry: %w", err)
 	}
 	return nil
 }
 
 // extractFileFromZip extracts a single zip.File entry to destPath,
-// creating parent directories as needed. Uses atomicWriteFile so that a
-// crash mid-extract never leaves a truncated or partially-written file.
-func extractFileFromZip(f *zip.File, destPath string) error {
+// creating parent directories as needed, decrypting it with key unless that
+// is nil. Uses atomicWriteFile so that a crash mid-extract never leaves a
+// truncated or partially-written file.
+func extractFileFromZip(f *zip.File, destPath string, key []byte) error {
 	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
@@ -390,6 +555,11 @@ func extractFileFromZip(f *zip.File, destPath string) error {
 	if err != nil {
 		return fmt.Errorf("failed to read zip entry: %w", err)
 	}
+	if key != nil {
+		if data, err = openSealed(key, f.Name, data); err != nil {
+			return err
+		}
+	}
 
 	if err := atomicWriteFile(destPath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
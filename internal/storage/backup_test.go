@@ -15,6 +15,7 @@ func setupBackupDir(t *testing.T) (configDir string, bm *BackupManager) {
 
 	dirs := []string{
 		filepath.Join(configDir, "data", "favorites"),
+		filepath.Join(configDir, "data", "sessions"),
 	}
 	for _, d := range dirs {
 		if err := os.MkdirAll(d, 0755); err != nil {
@@ -28,7 +29,10 @@ func setupBackupDir(t *testing.T) (configDir string, bm *BackupManager) {
 		"data/voted_stations.json":                    `{"stations":[]}`,
 		"data/station_ratings.json":                   `{}`,
 		"data/station_tags.json":                      `{}`,
+		"data/station_notes.json":                     `{"notes":{}}`,
 		"data/station_metadata.json":                  `{}`,
+		"data/liked_songs.json":                       `{"songs":[]}`,
+		"data/sessions/sessions-2026-10.jsonl":        "{}\n",
 		"data/favorites/search-history.json":          `{"search_items":[]}`,
 		"data/favorites/Jazz.json":                    `[]`,
 		"data/favorites/Pops.json":                    `[]`,
@@ -74,7 +78,10 @@ func TestExport_AllCategories(t *testing.T) {
 		"data/voted_stations.json",
 		"data/station_ratings.json",
 		"data/station_tags.json",
+		"data/station_notes.json",
 		"data/station_metadata.json",
+		"data/liked_songs.json",
+		"data/sessions/sessions-2026-10.jsonl",
 		"data/favorites/Jazz.json",
 		"data/favorites/Pops.json",
 	}
@@ -168,6 +175,9 @@ func TestListArchiveCategories(t *testing.T) {
 	if !prefs.SearchHistory {
 		t.Error("expected SearchHistory=true")
 	}
+	if !prefs.LikedSongs {
+		t.Error("expected LikedSongs=true")
+	}
 }
 
 func TestListArchiveCategories_Selective(t *testing.T) {
@@ -190,7 +200,7 @@ func TestListArchiveCategories_Selective(t *testing.T) {
 	if !got.Blocklist {
 		t.Error("expected Blocklist=true")
 	}
-	if got.Settings || got.RatingsVotes || got.MetadataTags || got.SearchHistory {
+	if got.Settings || got.RatingsVotes || got.MetadataTags || got.SearchHistory || got.LikedSongs {
 		t.Errorf("expected only Favorites and Blocklist, got %+v", got)
 	}
 }
@@ -351,3 +361,41 @@ func isRestoreConflictError(err error, target **RestoreConflictError) bool {
 	}
 	return false
 }
+
+func TestExport_NestedFavorites(t *testing.T) {
+	configDir, bm := setupBackupDir(t)
+	favDir := filepath.Join(configDir, "data", "favorites")
+	for _, dir := range []string{filepath.Join(favDir, "Jazz"), filepath.Join(favDir, ".backup")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, rel := range []string{"Jazz/Smooth.json", "Jazz/" + SystemFileListOrder, ".backup/Jazz.json"} {
+		if err := os.WriteFile(filepath.Join(favDir, filepath.FromSlash(rel)), []byte(`[]`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dest := filepath.Join(t.TempDir(), "backup.zip")
+	if err := bm.Export(dest, SyncPrefs{Favorites: true}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	got := zipContains(t, dest)
+	for _, name := range []string{"data/favorites/Jazz/Smooth.json", "data/favorites/Jazz/.order.json"} {
+		if _, ok := got[name]; !ok {
+			t.Errorf("expected %s in archive", name)
+		}
+	}
+	if _, ok := got["data/favorites/.backup/Jazz.json"]; ok {
+		t.Error("the .backup folder should not be archived")
+	}
+
+	// Restoring recreates the folder
+	restored := t.TempDir()
+	if err := (&BackupManager{configDir: restored}).Restore(dest, SyncPrefs{Favorites: true}, true); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(restored, "data", "favorites", "Jazz", "Smooth.json")); err != nil {
+		t.Errorf("expected the nested list to be restored: %v", err)
+	}
+}
@@ -0,0 +1,648 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+// SystemDirCleanup holds the undo snapshot of the last library cleanup.
+// Hidden like the other system entries, so it is never shown as a folder.
+const SystemDirCleanup = ".cleanup"
+
+var (
+	// ErrCleanupStale is returned by ApplyCleanup when a list changed after
+	// the plan was made, so the preview no longer matches.
+	ErrCleanupStale = errors.New("lists changed since the preview; review the changes again")
+	// ErrNothingToUndo is returned by UndoCleanup when there is no cleanup
+	// to undo.
+	ErrNothingToUndo = errors.New("nothing to undo")
+)
+
+// DuplicateKind says why stations were grouped as duplicates.
+type DuplicateKind int
+
+const (
+	DuplicateUUID DuplicateKind = iota // the same Radio Browser station
+	DuplicateURL                       // different stations playing the same stream
+	DuplicateName                      // names that differ only in case, punctuation or quality tags
+)
+
+func (k DuplicateKind) String() string {
+	switch k {
+	case DuplicateUUID:
+		return "same station"
+	case DuplicateURL:
+		return "same stream"
+	case DuplicateName:
+		return "similar name"
+	}
+	return "unknown"
+}
+
+// StationRef is one station entry of a favorites list.
+type StationRef struct {
+	List    string
+	Index   int
+	Station api.Station
+}
+
+// DuplicateGroup is a set of entries, in one list or several, that look like
+// the same station.
+type DuplicateGroup struct {
+	Kind DuplicateKind
+	Key  string // the shared UUID, stream URL or normalized name
+	Refs []StationRef
+}
+
+// Lists returns the names of the lists the group's entries are in.
+func (g DuplicateGroup) Lists() []string {
+	var lists []string
+	for _, ref := range g.Refs {
+		if !contains(lists, ref.List) {
+			lists = append(lists, ref.List)
+		}
+	}
+	return lists
+}
+
+// Words dropped when comparing names: codecs, bitrates and quality labels
+// that stations append to their names.
+var (
+	nameNoiseWords = map[string]bool{
+		"mp3": true, "aac": true, "aacplus": true, "ogg": true, "opus": true, "flac": true,
+		"hd": true, "hq": true, "lq": true, "kbps": true, "kbit": true, "stream": true,
+	}
+	bitrateWord = regexp.MustCompile(`^\d+(k|kb|kbps|kbit)$`)
+)
+
+// NormalizeStationName reduces a station name to the words that identify
+// it, so "  ★ RADIO XYZ 128k MP3 ★" and "Radio XYZ" compare equal.
+func NormalizeStationName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kept := words[:0]
+	for _, w := range words {
+		if !nameNoiseWords[w] && !bitrateWord.MatchString(w) {
+			kept = append(kept, w)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// normalizeStreamURL ignores the scheme, the case of the host and a
+// trailing slash, which do not change the stream.
+func normalizeStreamURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	key := strings.ToLower(u.Host) + strings.TrimSuffix(u.Path, "/")
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key
+}
+
+// loadAllLists loads every list, in display order.
+func (s *Storage) loadAllLists(ctx context.Context) ([]*FavoritesList, error) {
+	names, err := s.GetAllLists(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	lists := make([]*FavoritesList, 0, len(names))
+	for _, name := range names {
+		list, err := s.LoadList(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load list %q: %w", name, err)
+		}
+		lists = append(lists, list)
+	}
+	return lists, nil
+}
+
+// FindDuplicates looks through every list for the same station saved more
+// than once, different stations playing the same stream, and stations whose
+// names differ only in case, punctuation or codec and bitrate labels. Each
+// entry appears in at most one group of each kind.
+func (s *Storage) FindDuplicates(ctx context.Context) ([]DuplicateGroup, error) {
+	lists, err := s.loadAllLists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type index struct {
+		keys []string
+		refs map[string][]StationRef
+	}
+	newIndex := func() *index { return &index{refs: make(map[string][]StationRef)} }
+	byUUID, byURL, byName := newIndex(), newIndex(), newIndex()
+	add := func(ix *index, key string, ref StationRef) {
+		if key == "" {
+			return
+		}
+		if _, ok := ix.refs[key]; !ok {
+			ix.keys = append(ix.keys, key)
+		}
+		ix.refs[key] = append(ix.refs[key], ref)
+	}
+	for _, list := range lists {
+		for i, st := range list.Stations {
+			ref := StationRef{List: list.Name, Index: i, Station: st}
+			add(byUUID, st.StationUUID, ref)
+			add(byURL, normalizeStreamURL(st.URLResolved), ref)
+			add(byName, NormalizeStationName(st.OriginalName()), ref)
+		}
+	}
+
+	// distinct counts the different values of key among refs.
+	distinct := func(refs []StationRef, key func(StationRef) string) int {
+		seen := make(map[string]bool)
+		for _, ref := range refs {
+			seen[key(ref)] = true
+		}
+		return len(seen)
+	}
+	uuidOf := func(ref StationRef) string { return ref.Station.StationUUID }
+	urlOf := func(ref StationRef) string { return normalizeStreamURL(ref.Station.URLResolved) }
+
+	var groups []DuplicateGroup
+	for _, key := range byUUID.keys {
+		if refs := byUUID.refs[key]; len(refs) > 1 {
+			groups = append(groups, DuplicateGroup{Kind: DuplicateUUID, Key: key, Refs: refs})
+		}
+	}
+	// Copies of one station are already reported above
+	for _, key := range byURL.keys {
+		if refs := byURL.refs[key]; distinct(refs, uuidOf) > 1 {
+			groups = append(groups, DuplicateGroup{Kind: DuplicateURL, Key: key, Refs: refs})
+		}
+	}
+	// So are stations sharing a stream
+	for _, key := range byName.keys {
+		if refs := byName.refs[key]; distinct(refs, uuidOf) > 1 && distinct(refs, urlOf) > 1 {
+			groups = append(groups, DuplicateGroup{Kind: DuplicateName, Key: key, Refs: refs})
+		}
+	}
+	return groups, nil
+}
+
+// listChange is the new content of one list in a CleanupPlan.
+type listChange struct {
+	name    string
+	existed bool
+	before  []api.Station
+	after   []api.Station // nil deletes the list
+}
+
+// CleanupPlan is a set of list changes worked out ahead of time so they can
+// be previewed before ApplyCleanup writes them.
+type CleanupPlan struct {
+	Summary string
+	changes []listChange
+}
+
+// ListDiff is the preview of one list's change.
+type ListDiff struct {
+	List    string
+	Created bool
+	Deleted bool
+	Added   []api.Station
+	Removed []api.Station
+}
+
+// Empty reports whether the plan changes nothing.
+func (p *CleanupPlan) Empty() bool {
+	return p == nil || len(p.changes) == 0
+}
+
+// Preview lists the stations each list gains and loses.
+func (p *CleanupPlan) Preview() []ListDiff {
+	if p == nil {
+		return nil
+	}
+	diffs := make([]ListDiff, 0, len(p.changes))
+	for _, c := range p.changes {
+		d := ListDiff{List: c.name, Created: !c.existed, Deleted: c.after == nil}
+		d.Removed = subtractStations(c.before, c.after)
+		d.Added = subtractStations(c.after, c.before)
+		diffs = append(diffs, d)
+	}
+	return diffs
+}
+
+// subtractStations returns the entries of a that are not in b, counting
+// copies of a station separately.
+func subtractStations(a, b []api.Station) []api.Station {
+	left := make(map[string]int)
+	for _, st := range b {
+		left[st.StationUUID]++
+	}
+	var out []api.Station
+	for _, st := range a {
+		if left[st.StationUUID] > 0 {
+			left[st.StationUUID]--
+			continue
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+// planner collects the changes of a plan, loading each list once.
+type planner struct {
+	s       *Storage
+	ctx     context.Context
+	changes []*listChange
+}
+
+// list returns the change of name, loading the list on first use.
+func (p *planner) list(name string) (*listChange, error) {
+	for _, c := range p.changes {
+		if c.name == name {
+			return c, nil
+		}
+	}
+	c := &listChange{name: name}
+	list, err := p.s.LoadList(p.ctx, name)
+	switch {
+	case err == nil:
+		c.existed = true
+		c.before = list.Stations
+		c.after = append([]api.Station{}, list.Stations...)
+	case os.IsNotExist(err):
+		c.after = []api.Station{}
+	default:
+		return nil, err
+	}
+	p.changes = append(p.changes, c)
+	return c, nil
+}
+
+// plan drops lists that end up unchanged.
+func (p *planner) plan(summary string) *CleanupPlan {
+	plan := &CleanupPlan{Summary: summary}
+	for _, c := range p.changes {
+		if c.existed && c.after != nil && sameStations(c.before, c.after) {
+			continue
+		}
+		plan.changes = append(plan.changes, *c)
+	}
+	return plan
+}
+
+// sameStations compares lists by their station UUIDs.
+func sameStations(a, b []api.Station) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].StationUUID != b[i].StationUUID {
+			return false
+		}
+	}
+	return true
+}
+
+// PlanKeepOne plans removing every entry of group except group.Refs[keep].
+func (s *Storage) PlanKeepOne(ctx context.Context, group DuplicateGroup, keep int) (*CleanupPlan, error) {
+	if keep < 0 || keep >= len(group.Refs) {
+		return nil, fmt.Errorf("no entry %d in the group", keep)
+	}
+	p := &planner{s: s, ctx: ctx}
+	drop := make(map[string][]int)
+	for i, ref := range group.Refs {
+		if i != keep {
+			drop[ref.List] = append(drop[ref.List], ref.Index)
+		}
+	}
+	for _, name := range group.Lists() {
+		indexes := drop[name]
+		if len(indexes) == 0 {
+			continue
+		}
+		c, err := p.list(name)
+		if err != nil {
+			return nil, err
+		}
+		// Remove from the end so the earlier indexes stay valid
+		sort.Sort(sort.Reverse(sort.IntSlice(indexes)))
+		for _, i := range indexes {
+			if i >= len(c.after) {
+				return nil, ErrCleanupStale
+			}
+			c.after = append(c.after[:i], c.after[i+1:]...)
+		}
+	}
+	kept := group.Refs[keep]
+	return p.plan(fmt.Sprintf("Keep %s in %s, remove %d other(s)", kept.Station.TrimName(), kept.List, len(group.Refs)-1)), nil
+}
+
+// PlanDedupeLists plans removing, within each list, the later copies of a
+// station and the later stations playing a stream the list already has.
+// Stations saved in several lists are left alone.
+func (s *Storage) PlanDedupeLists(ctx context.Context) (*CleanupPlan, error) {
+	names, err := s.GetAllLists(ctx)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	p := &planner{s: s, ctx: ctx}
+	removed := 0
+	for _, name := range names {
+		c, err := p.list(name)
+		if err != nil {
+			return nil, err
+		}
+		c.after, removed = dedupeStations(c.after, removed)
+	}
+	return p.plan(fmt.Sprintf("Remove %d duplicate(s) within lists", removed)), nil
+}
+
+// dedupeStations keeps the first of stations sharing a UUID or stream URL
+// and adds the number dropped to removed.
+func dedupeStations(stations []api.Station, removed int) ([]api.Station, int) {
+	uuids := make(map[string]bool)
+	urls := make(map[string]bool)
+	out := stations[:0]
+	for _, st := range stations {
+		u := normalizeStreamURL(st.URLResolved)
+		if uuids[st.StationUUID] || (u != "" && urls[u]) {
+			removed++
+			continue
+		}
+		uuids[st.StationUUID] = true
+		if u != "" {
+			urls[u] = true
+		}
+		out = append(out, st)
+	}
+	return out, removed
+}
+
+// PlanMergeLists plans adding the stations of sources to target, which is
+// created if needed, skipping stations target already has. With
+// deleteSources the source lists are deleted afterwards.
+func (s *Storage) PlanMergeLists(ctx context.Context, sources []string, target string, deleteSources bool) (*CleanupPlan, error) {
+	if err := ValidateListName(target); err != nil {
+		return nil, err
+	}
+	p := &planner{s: s, ctx: ctx}
+	t, err := p.list(target)
+	if err != nil {
+		return nil, err
+	}
+	merged := 0
+	for _, name := range sources {
+		if name == target {
+			continue
+		}
+		c, err := p.list(name)
+		if err != nil {
+			return nil, err
+		}
+		if !c.existed {
+			return nil, fmt.Errorf("list %q not found", name)
+		}
+		var n int
+		t.after, n = appendNew(t.after, c.before)
+		merged += n
+		if deleteSources {
+			c.after = nil
+		}
+	}
+	summary := fmt.Sprintf("Merge %d station(s) from %s into %s", merged, strings.Join(sources, ", "), target)
+	if deleteSources {
+		summary += " and delete the merged lists"
+	}
+	return p.plan(summary), nil
+}
+
+// appendNew appends the stations of add that list does not have, by UUID or
+// stream URL, and returns how many it appended.
+func appendNew(list, add []api.Station) ([]api.Station, int) {
+	uuids := make(map[string]bool)
+	urls := make(map[string]bool)
+	for _, st := range list {
+		uuids[st.StationUUID] = true
+		if u := normalizeStreamURL(st.URLResolved); u != "" {
+			urls[u] = true
+		}
+	}
+	n := 0
+	for _, st := range add {
+		u := normalizeStreamURL(st.URLResolved)
+		if uuids[st.StationUUID] || (u != "" && urls[u]) {
+			continue
+		}
+		uuids[st.StationUUID] = true
+		if u != "" {
+			urls[u] = true
+		}
+		list = append(list, st)
+		n++
+	}
+	return list, n
+}
+
+// PlanMoveStations plans moving the stations with the given UUIDs from one
+// list to another, which is created if needed. Stations the target already
+// has are only removed from the source.
+func (s *Storage) PlanMoveStations(ctx context.Context, from string, uuids []string, to string) (*CleanupPlan, error) {
+	if from == to {
+		return nil, fmt.Errorf("the stations are already in %q", to)
+	}
+	if err := ValidateListName(to); err != nil {
+		return nil, err
+	}
+	p := &planner{s: s, ctx: ctx}
+	src, err := p.list(from)
+	if err != nil {
+		return nil, err
+	}
+	if !src.existed {
+		return nil, fmt.Errorf("list %q not found", from)
+	}
+	var moving []api.Station
+	kept := src.after[:0]
+	for _, st := range src.after {
+		if contains(uuids, st.StationUUID) {
+			moving = append(moving, st)
+		} else {
+			kept = append(kept, st)
+		}
+	}
+	if len(moving) == 0 {
+		return nil, ErrStationNotFound
+	}
+	src.after = kept
+	dst, err := p.list(to)
+	if err != nil {
+		return nil, err
+	}
+	dst.after, _ = appendNew(dst.after, moving)
+	return p.plan(fmt.Sprintf("Move %d station(s) from %s to %s", len(moving), from, to)), nil
+}
+
+// cleanupUndo is the snapshot written before a cleanup is applied.
+type cleanupUndo struct {
+	Summary   string            `json:"summary"`
+	AppliedAt time.Time         `json:"applied_at"`
+	Lists     []cleanupUndoList `json:"lists"`
+}
+
+type cleanupUndoList struct {
+	Name     string        `json:"name"`
+	Existed  bool          `json:"existed"`
+	Stations []api.Station `json:"stations,omitempty"`
+}
+
+func (s *Storage) undoPath() string {
+	return filepath.Join(s.favoritePath, SystemDirCleanup, "undo.json")
+}
+
+// ApplyCleanup writes the changes of plan. It first saves the lists as they
+// were, replacing the previous snapshot, so UndoCleanup can restore them.
+func (s *Storage) ApplyCleanup(ctx context.Context, plan *CleanupPlan) error {
+	if plan.Empty() {
+		return nil
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	undo := cleanupUndo{Summary: plan.Summary, AppliedAt: time.Now()}
+	for _, c := range plan.changes {
+		list, err := s.LoadList(ctx, c.name)
+		switch {
+		case err == nil:
+			if !c.existed || !sameStations(list.Stations, c.before) {
+				return ErrCleanupStale
+			}
+		case os.IsNotExist(err):
+			if c.existed {
+				return ErrCleanupStale
+			}
+		default:
+			return err
+		}
+		undo.Lists = append(undo.Lists, cleanupUndoList{Name: c.name, Existed: c.existed, Stations: c.before})
+	}
+	data, err := json.MarshalIndent(undo, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.undoPath()), 0755); err != nil {
+		return err
+	}
+	if err := atomicWriteFile(s.undoPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to save undo snapshot: %w", err)
+	}
+
+	for _, c := range plan.changes {
+		if c.after == nil {
+			if err := s.removeList(c.name); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.SaveList(ctx, &FavoritesList{Name: c.name, Stations: c.after}); err != nil {
+			return err
+		}
+	}
+
+	changes := make([]JournalChange, 0, len(plan.changes))
+	for _, c := range plan.changes {
+		var before []api.Station
+		if c.existed {
+			before = c.before
+		}
+		changes = append(changes, NewJournalChange(JournalFavorites, c.name, before, c.after))
+	}
+	RecordChange("Library cleanup: "+plan.Summary, changes...)
+	return nil
+}
+
+// removeList deletes a list and tidies its folder. Caller must hold s.mu.
+func (s *Storage) removeList(name string) error {
+	if err := s.deleteListFile(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	folder, base := splitListName(name)
+	s.forget(folder, base)
+	s.pruneFolder(folder)
+	return nil
+}
+
+// LastCleanup returns the summary of the cleanup UndoCleanup would revert,
+// or "" when there is none.
+func (s *Storage) LastCleanup() string {
+	data, err := os.ReadFile(s.undoPath())
+	if err != nil {
+		return ""
+	}
+	var undo cleanupUndo
+	if json.Unmarshal(data, &undo) != nil {
+		return ""
+	}
+	return undo.Summary
+}
+
+// UndoCleanup puts the lists changed by the last ApplyCleanup back as they
+// were and returns that cleanup's summary. Lists it created are deleted and
+// lists it deleted come back. Changes made to those lists since are lost.
+func (s *Storage) UndoCleanup(ctx context.Context) (string, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(s.undoPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNothingToUndo
+		}
+		return "", err
+	}
+	var undo cleanupUndo
+	if err := json.Unmarshal(data, &undo); err != nil {
+		return "", fmt.Errorf("failed to read undo snapshot: %w", err)
+	}
+	for _, l := range undo.Lists {
+		if !l.Existed {
+			if err := s.removeList(l.Name); err != nil {
+				return "", err
+			}
+			continue
+		}
+		stations := l.Stations
+		if stations == nil {
+			stations = []api.Station{}
+		}
+		if err := s.SaveList(ctx, &FavoritesList{Name: l.Name, Stations: stations}); err != nil {
+			return "", err
+		}
+	}
+	if err := os.Remove(s.undoPath()); err != nil {
+		return "", err
+	}
+	return undo.Summary, nil
+}
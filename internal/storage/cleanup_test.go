@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+func TestNormalizeStationName(t *testing.T) {
+	cases := map[string]string{
+		"Radio XYZ":                "radio xyz",
+		"  ★ RADIO XYZ 128k MP3 ★": "radio xyz",
+		"Radio-XYZ (HQ AAC)":       "radio xyz",
+		"BBC Radio 1":              "bbc radio 1",
+		"320kbps":                  "",
+	}
+	for in, want := range cases {
+		if got := NormalizeStationName(in); got != want {
+			t.Errorf("NormalizeStationName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func newCleanupStore(t *testing.T, lists map[string][]api.Station) *Storage {
+	t.Helper()
+	store := NewStorage(t.TempDir())
+	for name, stations := range lists {
+		if err := store.SaveList(context.Background(), &FavoritesList{Name: name, Stations: stations}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func stationUUIDs(t *testing.T, store *Storage, name string) []string {
+	t.Helper()
+	list, err := store.LoadList(context.Background(), name)
+	if err != nil {
+		t.Fatalf("LoadList(%q): %v", name, err)
+	}
+	uuids := []string{}
+	for _, st := range list.Stations {
+		uuids = append(uuids, st.StationUUID)
+	}
+	return uuids
+}
+
+func TestStorage_FindDuplicates(t *testing.T) {
+	a := api.Station{StationUUID: "a", Name: "Jazz FM", URLResolved: "http://jazz.example/live"}
+	b := api.Station{StationUUID: "b", Name: "Jazz FM mirror", URLResolved: "https://JAZZ.example/live/"}
+	c := api.Station{StationUUID: "c", Name: "Rock One 128k", URLResolved: "http://rock.example/128"}
+	d := api.Station{StationUUID: "d", Name: "ROCK ONE (HQ)", URLResolved: "http://rock.example/320"}
+	e := api.Station{StationUUID: "e", Name: "Other", URLResolved: "http://other.example"}
+	store := newCleanupStore(t, map[string][]api.Station{
+		"Jazz": {a, c},
+		"Mix":  {a, b, d, e},
+	})
+
+	groups, err := store.FindDuplicates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	type summary struct {
+		kind DuplicateKind
+		key  string
+		refs int
+	}
+	var got []summary
+	for _, g := range groups {
+		got = append(got, summary{g.Kind, g.Key, len(g.Refs)})
+	}
+	want := []summary{
+		{DuplicateUUID, "a", 2},
+		{DuplicateURL, "jazz.example/live", 3},
+		{DuplicateName, "rock one", 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDuplicates = %+v, want %+v", got, want)
+	}
+	if lists := groups[0].Lists(); !reflect.DeepEqual(lists, []string{"Jazz", "Mix"}) {
+		t.Errorf("Lists = %v", lists)
+	}
+}
+
+func TestStorage_CleanupKeepOneAndUndo(t *testing.T) {
+	ctx := context.Background()
+	a := api.Station{StationUUID: "a", URLResolved: "http://a"}
+	b := api.Station{StationUUID: "b", URLResolved: "http://b"}
+	store := newCleanupStore(t, map[string][]api.Station{
+		"Jazz": {a, b},
+		"Mix":  {b, a},
+	})
+
+	groups, err := store.FindDuplicates(ctx)
+	if err != nil || len(groups) != 2 {
+		t.Fatalf("FindDuplicates = %v, %v", groups, err)
+	}
+	plan, err := store.PlanKeepOne(ctx, groups[0], 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Made before the first plan is applied, so it expects Jazz as it was
+	stale, err := store.PlanKeepOne(ctx, groups[1], 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preview := plan.Preview()
+	if len(preview) != 1 || preview[0].List != "Jazz" || len(preview[0].Removed) != 1 || preview[0].Removed[0].StationUUID != "a" {
+		t.Fatalf("Preview = %+v", preview)
+	}
+	if err := store.ApplyCleanup(ctx, plan); err != nil {
+		t.Fatal(err)
+	}
+	if got := stationUUIDs(t, store, "Jazz"); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("Jazz = %v", got)
+	}
+	if store.LastCleanup() != plan.Summary {
+		t.Errorf("LastCleanup = %q, want %q", store.LastCleanup(), plan.Summary)
+	}
+
+	if err := store.ApplyCleanup(ctx, stale); !errors.Is(err, ErrCleanupStale) {
+		t.Errorf("stale ApplyCleanup = %v, want ErrCleanupStale", err)
+	}
+
+	if _, err := store.UndoCleanup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := stationUUIDs(t, store, "Jazz"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("after undo Jazz = %v", got)
+	}
+	if _, err := store.UndoCleanup(ctx); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("second UndoCleanup = %v, want ErrNothingToUndo", err)
+	}
+}
+
+func TestStorage_CleanupMergeAndMove(t *testing.T) {
+	ctx := context.Background()
+	a := api.Station{StationUUID: "a", URLResolved: "http://a"}
+	b := api.Station{StationUUID: "b", URLResolved: "http://b"}
+	b2 := api.Station{StationUUID: "b2", URLResolved: "http://b/"}
+	c := api.Station{StationUUID: "c", URLResolved: "http://c"}
+	store := newCleanupStore(t, map[string][]api.Station{
+		"Jazz":        {a, b, a},
+		"Jazz/Smooth": {b2, c},
+	})
+
+	dedupe, err := store.PlanDedupeLists(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ApplyCleanup(ctx, dedupe); err != nil {
+		t.Fatal(err)
+	}
+	if got := stationUUIDs(t, store, "Jazz"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("after dedupe Jazz = %v", got)
+	}
+
+	// b2 plays the same stream as b, so only c is merged
+	merge, err := store.PlanMergeLists(ctx, []string{"Jazz/Smooth"}, "Jazz", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ApplyCleanup(ctx, merge); err != nil {
+		t.Fatal(err)
+	}
+	if got := stationUUIDs(t, store, "Jazz"); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("after merge Jazz = %v", got)
+	}
+	if _, err := store.LoadList(ctx, "Jazz/Smooth"); !os.IsNotExist(err) {
+		t.Errorf("merged list still exists: %v", err)
+	}
+
+	move, err := store.PlanMoveStations(ctx, "Jazz", []string{"a", "c"}, "Picks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := move.Preview(); len(diff) != 2 || !diff[1].Created || len(diff[1].Added) != 2 {
+		t.Fatalf("move Preview = %+v", diff)
+	}
+	if err := store.ApplyCleanup(ctx, move); err != nil {
+		t.Fatal(err)
+	}
+	if got := stationUUIDs(t, store, "Picks"); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("Picks = %v", got)
+	}
+
+	// Undo removes the list the move created
+	if _, err := store.UndoCleanup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.LoadList(ctx, "Picks"); !os.IsNotExist(err) {
+		t.Errorf("created list survived undo: %v", err)
+	}
+	lists, _ := store.GetAllLists(ctx)
+	if !reflect.DeepEqual(lists, []string{"Jazz"}) {
+		t.Errorf("GetAllLists = %v", lists)
+	}
+}
@@ -169,6 +169,168 @@ func SavePlayOptionsConfigToUnified(po config.PlayOptionsConfig) error {
 	})
 }
 
+// LoadRemoteConfigFromUnified loads HTTP remote settings from unified config.
+func LoadRemoteConfigFromUnified() (config.RemoteConfig, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.DefaultRemoteConfig(), err
+	}
+	return cfg.Remote, nil
+}
+
+// SaveRemoteConfigToUnified saves HTTP remote settings to unified config.
+func SaveRemoteConfigToUnified(rc config.RemoteConfig) error {
+	return updateUnifiedConfig(func(cfg *config.Config) {
+		cfg.Remote = rc
+	})
+}
+
+// LoadRelayConfigFromUnified loads stream relay settings from unified config.
+func LoadRelayConfigFromUnified() (config.RelayConfig, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.DefaultRelayConfig(), err
+	}
+	return cfg.Relay, nil
+}
+
+// SaveRelayConfigToUnified saves stream relay settings to unified config.
+func SaveRelayConfigToUnified(rc config.RelayConfig) error {
+	return updateUnifiedConfig(func(cfg *config.Config) {
+		cfg.Relay = rc
+	})
+}
+
+// LoadVisualizerConfigFromUnified loads visualizer settings from unified config.
+func LoadVisualizerConfigFromUnified() (config.VisualizerConfig, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.DefaultVisualizerConfig(), err
+	}
+	return cfg.Visualizer, nil
+}
+
+// SaveVisualizerConfigToUnified saves visualizer settings to unified config.
+func SaveVisualizerConfigToUnified(vc config.VisualizerConfig) error {
+	return updateUnifiedConfig(func(cfg *config.Config) {
+		cfg.Visualizer = vc
+	})
+}
+
+// LoadTimeShiftConfigFromUnified loads time-shift settings from unified config.
+func LoadTimeShiftConfigFromUnified() (config.TimeShiftConfig, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.DefaultTimeShiftConfig(), err
+	}
+	return cfg.TimeShift, nil
+}
+
+// SaveTimeShiftConfigToUnified saves time-shift settings to unified config.
+func SaveTimeShiftConfigToUnified(tc config.TimeShiftConfig) error {
+	return updateUnifiedConfig(func(cfg *config.Config) {
+		cfg.TimeShift = tc
+	})
+}
+
+// LoadResumeConfigFromUnified loads network resume settings from unified config.
+func LoadResumeConfigFromUnified() (config.ResumeConfig, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.DefaultResumeConfig(), err
+	}
+	return cfg.Resume, nil
+}
+
+// SaveResumeConfigToUnified saves network resume settings to unified config.
+func SaveResumeConfigToUnified(rc config.ResumeConfig) error {
+	return updateUnifiedConfig(func(cfg *config.Config) {
+		cfg.Resume = rc
+	})
+}
+
+// LoadDataSaverConfigFromUnified loads data saver settings from unified config.
+func LoadDataSaverConfigFromUnified() (config.DataSaverConfig, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.DefaultDataSaverConfig(), err
+	}
+	return cfg.DataSaver, nil
+}
+
+// SaveDataSaverConfigToUnified saves data saver settings to unified config.
+func SaveDataSaverConfigToUnified(dc config.DataSaverConfig) error {
+	return updateUnifiedConfig(func(cfg *config.Config) {
+		cfg.DataSaver = dc
+	})
+}
+
+// LoadStorageConfigFromUnified loads storage backend settings from unified config.
+func LoadStorageConfigFromUnified() (config.StorageConfig, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.DefaultStorageConfig(), err
+	}
+	return cfg.Storage, nil
+}
+
+// LoadSessionLogConfigFromUnified loads session log settings from unified config.
+func LoadSessionLogConfigFromUnified() (config.SessionLogConfig, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.DefaultSessionLogConfig(), err
+	}
+	return cfg.SessionLog, nil
+}
+
+// SaveSessionLogConfigToUnified saves session log settings to unified config.
+func SaveSessionLogConfigToUnified(sc config.SessionLogConfig) error {
+	return updateUnifiedConfig(func(cfg *config.Config) {
+		cfg.SessionLog = sc
+	})
+}
+
+// LoadAutoBackupConfigFromUnified loads automatic backup settings from unified config.
+func LoadAutoBackupConfigFromUnified() (config.AutoBackupConfig, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.DefaultAutoBackupConfig(), err
+	}
+	return cfg.AutoBackup, nil
+}
+
+// LoadAdFilterConfigFromUnified loads ad filter settings from unified config.
+func LoadAdFilterConfigFromUnified() (config.AdFilterConfig, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.DefaultAdFilterConfig(), err
+	}
+	return cfg.AdFilter, nil
+}
+
+// SaveAdFilterConfigToUnified saves ad filter settings to unified config.
+func SaveAdFilterConfigToUnified(ac config.AdFilterConfig) error {
+	return updateUnifiedConfig(func(cfg *config.Config) {
+		cfg.AdFilter = ac
+	})
+}
+
+// LoadMetadataConfigFromUnified loads track title patterns from unified config.
+func LoadMetadataConfigFromUnified() (config.MetadataConfig, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return config.DefaultMetadataConfig(), err
+	}
+	return cfg.Metadata, nil
+}
+
+// SaveMetadataConfigToUnified saves track title patterns to unified config.
+func SaveMetadataConfigToUnified(mc config.MetadataConfig) error {
+	return updateUnifiedConfig(func(cfg *config.Config) {
+		cfg.Metadata = mc
+	})
+}
+
 // CheckAndMigrateV2Config checks for v2 config and migrates if found
 // Returns true if migration was performed, false otherwise
 // If force is true, migration runs even if v3 config already exists
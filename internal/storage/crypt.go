@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Backups and the backup Gist can be encrypted with a passphrase. Each file
+// is sealed with AES-256-GCM under a key derived from the passphrase with
+// PBKDF2-SHA256, and bound to its name so files can't be swapped. The salt
+// and a check value to tell a wrong passphrase from a damaged file are kept
+// in an encryptionHeader: the tera-encryption.json entry of a zip, or the
+// manifest of the backup Gist.
+
+var (
+	// ErrPassphraseRequired is returned when restoring an encrypted backup
+	// without a passphrase.
+	ErrPassphraseRequired = errors.New("backup is encrypted; enter its passphrase")
+	// ErrWrongPassphrase is returned when the passphrase does not open an
+	// encrypted backup.
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+)
+
+const (
+	encryptionCipher = "aes-256-gcm"
+	encryptionKDF    = "pbkdf2-sha256"
+	// encryptedGistPrefix starts the content of an encrypted Gist file; the
+	// rest is the sealed content in base64.
+	encryptedGistPrefix = "tera-encrypted-v1:"
+	encryptionCheckName = "tera-check"
+)
+
+// pbkdf2Iterations is the work factor for new headers; tests lower it.
+var pbkdf2Iterations = 600_000
+
+type encryptionHeader struct {
+	Cipher     string `json:"cipher"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Check      []byte `json:"check"`
+}
+
+// newEncryptionHeader starts a new encrypted backup and returns its header
+// and key.
+func newEncryptionHeader(passphrase string) (*encryptionHeader, []byte, error) {
+	h := &encryptionHeader{
+		Cipher:     encryptionCipher,
+		KDF:        encryptionKDF,
+		Iterations: pbkdf2Iterations,
+		Salt:       make([]byte, 16),
+	}
+	if _, err := rand.Read(h.Salt); err != nil {
+		return nil, nil, err
+	}
+	key, err := h.deriveKey(passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	if h.Check, err = seal(key, encryptionCheckName, []byte("tera")); err != nil {
+		return nil, nil, err
+	}
+	return h, key, nil
+}
+
+// deriveKey derives the key from passphrase. The header comes from the
+// backup, so a work factor far above what TERA writes is refused rather than
+// letting a crafted backup hang the restore.
+func (h *encryptionHeader) deriveKey(passphrase string) ([]byte, error) {
+	if h.Cipher != encryptionCipher || h.KDF != encryptionKDF {
+		return nil, fmt.Errorf("unsupported backup encryption %s/%s", h.Cipher, h.KDF)
+	}
+	if h.Iterations < 1 || h.Iterations > 10*pbkdf2Iterations {
+		return nil, fmt.Errorf("unsupported backup encryption: %d iterations", h.Iterations)
+	}
+	return pbkdf2.Key(sha256.New, passphrase, h.Salt, h.Iterations, 32)
+}
+
+// unlock returns the key of the backup, or ErrPassphraseRequired or
+// ErrWrongPassphrase.
+func (h *encryptionHeader) unlock(passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrPassphraseRequired
+	}
+	key, err := h.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := openSealed(key, encryptionCheckName, h.Check); err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return key, nil
+}
+
+func parseEncryptionHeader(data []byte) (*encryptionHeader, error) {
+	var h encryptionHeader
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("invalid encryption header: %w", err)
+	}
+	return &h, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts data stored under name. The result is the nonce followed by
+// the ciphertext.
+func seal(key []byte, name string, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(data)+gcm.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, []byte(name)), nil
+}
+
+// openSealed decrypts what seal returned for the same name.
+func openSealed(key []byte, name string, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("%s: encrypted data is truncated", name)
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	data, err := gcm.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return nil, fmt.Errorf("%s: encrypted data is damaged", name)
+	}
+	return data, nil
+}
+
+// sealGistContent encrypts a Gist file as text.
+func sealGistContent(key []byte, name, content string) (string, error) {
+	sealed, err := seal(key, name, []byte(content))
+	if err != nil {
+		return "", err
+	}
+	return encryptedGistPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// isSealedGistContent reports whether a Gist file was encrypted.
+func isSealedGistContent(content string) bool {
+	return strings.HasPrefix(content, encryptedGistPrefix)
+}
+
+// openGistContent decrypts a Gist file sealGistContent encrypted.
+func openGistContent(key []byte, name, content string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(strings.TrimPrefix(content, encryptedGistPrefix)))
+	if err != nil {
+		return "", fmt.Errorf("%s: encrypted data is damaged", name)
+	}
+	data, err := openSealed(key, name, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
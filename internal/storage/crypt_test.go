@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shinokada/tera/v3/internal/gist"
+)
+
+func init() {
+	pbkdf2Iterations = 1000 // keep the tests fast
+}
+
+func TestEncryptedBackupRoundTrip(t *testing.T) {
+	configDir, bm := setupBackupDir(t)
+	dest := filepath.Join(t.TempDir(), "backup.zip")
+	if err := bm.ExportWithPassphrase(dest, DefaultSyncPrefs(), "correct horse"); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	// Names stay readable, contents do not
+	names := zipContains(t, dest)
+	if _, ok := names[backupEncryptionEntry]; !ok {
+		t.Fatalf("no %s in %v", backupEncryptionEntry, names)
+	}
+	if _, ok := names["data/favorites/Jazz.json"]; !ok {
+		t.Fatalf("entry names changed: %v", names)
+	}
+	r, err := zip.OpenReader(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+	if f := findZipEntry(&r.Reader, "data/station_notes.json"); f == nil {
+		t.Error("notes are missing")
+	} else if rc, err := f.Open(); err == nil {
+		entry, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		if strings.Contains(string(entry), "notes") {
+			t.Error("notes are stored as plain text")
+		}
+	}
+	if encrypted, err := bm.IsArchiveEncrypted(dest); err != nil || !encrypted {
+		t.Errorf("IsArchiveEncrypted = %v, %v", encrypted, err)
+	}
+	if available, err := bm.ListArchiveCategories(dest); err != nil || !available.Favorites {
+		t.Errorf("ListArchiveCategories = %+v, %v", available, err)
+	}
+
+	restoreBM := &BackupManager{configDir: t.TempDir()}
+	if err := restoreBM.Restore(dest, DefaultSyncPrefs(), false); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("restore without passphrase: %v", err)
+	}
+	if err := restoreBM.RestoreWithPassphrase(dest, DefaultSyncPrefs(), false, "wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("restore with wrong passphrase: %v", err)
+	}
+	if entries, _ := os.ReadDir(restoreBM.configDir); len(entries) != 0 {
+		t.Errorf("failed restores wrote %d entries", len(entries))
+	}
+
+	if err := restoreBM.RestoreWithPassphrase(dest, DefaultSyncPrefs(), false, "correct horse"); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	for _, rel := range []string{"config.yaml", "data/favorites/Jazz.json", "data/station_notes.json"} {
+		want, _ := os.ReadFile(filepath.Join(configDir, filepath.FromSlash(rel)))
+		got, err := os.ReadFile(filepath.Join(restoreBM.configDir, filepath.FromSlash(rel)))
+		if err != nil || string(got) != string(want) {
+			t.Errorf("%s = %q, %v; want %q", rel, got, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(restoreBM.configDir, backupEncryptionEntry)); !os.IsNotExist(err) {
+		t.Error("encryption header was restored as a file")
+	}
+}
+
+func TestPlainBackupIgnoresPassphrase(t *testing.T) {
+	_, bm := setupBackupDir(t)
+	dest := filepath.Join(t.TempDir(), "backup.zip")
+	if err := bm.Export(dest, DefaultSyncPrefs()); err != nil {
+		t.Fatal(err)
+	}
+	if encrypted, err := bm.IsArchiveEncrypted(dest); err != nil || encrypted {
+		t.Errorf("IsArchiveEncrypted = %v, %v", encrypted, err)
+	}
+	if err := bm.CheckArchivePassphrase(dest, ""); err != nil {
+		t.Errorf("CheckArchivePassphrase: %v", err)
+	}
+	restoreBM := &BackupManager{configDir: t.TempDir()}
+	if err := restoreBM.RestoreWithPassphrase(dest, DefaultSyncPrefs(), false, "unused"); err != nil {
+		t.Errorf("Restore: %v", err)
+	}
+}
+
+func TestSealedEntriesAreBoundToTheirNames(t *testing.T) {
+	header, key, err := newEncryptionHeader("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := seal(key, "data/a.json", []byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openSealed(key, "data/b.json", sealed); err == nil {
+		t.Error("entry opened under another name")
+	}
+
+	data, _ := json.Marshal(header)
+	parsed, err := parseEncryptionHeader(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := parsed.unlock("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain, err := openSealed(reopened, "data/a.json", sealed); err != nil || string(plain) != "a" {
+		t.Errorf("openSealed = %q, %v", plain, err)
+	}
+	if _, err := parsed.unlock(""); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("empty passphrase: %v", err)
+	}
+
+	// A crafted work factor is refused instead of hanging the restore
+	parsed.Iterations = 1 << 30
+	if _, err := parsed.unlock("secret"); err == nil || errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("huge iteration count: %v", err)
+	}
+}
+
+func TestEncryptedGistFilesRestore(t *testing.T) {
+	header, key, err := newEncryptionHeader("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, _ := json.Marshal(gistManifest{App: "tera", Encryption: header})
+	name := gistFilename("data/station_notes.json")
+	sealed, err := sealGistContent(key, name, `{"notes":{}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &gist.Gist{Files: map[string]gist.GistFile{
+		backupGistMarkerFile: {Content: string(manifest)},
+		name:                 {Content: sealed},
+	}}
+	if !GistEncrypted(g) {
+		t.Fatal("GistEncrypted = false")
+	}
+	if err := CheckGistPassphrase(g, "wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("wrong passphrase: %v", err)
+	}
+
+	baseDir := t.TempDir()
+	client := &http.Client{}
+	if err := stageAndWriteGistFiles(client, g.Files, DefaultSyncPrefs(), baseDir, nil); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("restore without key: %v", err)
+	}
+	key, err = gistKey(client, g.Files, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stageAndWriteGistFiles(client, g.Files, DefaultSyncPrefs(), baseDir, key); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(baseDir, "data", "station_notes.json"))
+	if err != nil || string(got) != `{"notes":{}}` {
+		t.Errorf("restored notes = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The schemas of the data files kept by this package. Version 1 is the
+// layout the files had when versions were introduced; its migrations tidy
+// files written before that, once. A file already at version 1 is not
+// checked again.
+
+func init() {
+	RegisterDataSchema(&DataSchema{
+		File: metadataFileName,
+		Migrations: []Migration{{
+			Version:     1,
+			Description: "Add missing maps and drop empty station entries",
+			Up: func(doc map[string]any) error {
+				if err := ensureObject(doc, "stations"); err != nil {
+					return err
+				}
+				dropNullEntries(doc["stations"].(map[string]any))
+				return nil
+			},
+		}},
+	})
+
+	RegisterDataSchema(&DataSchema{
+		File: ratingsFileName,
+		Migrations: []Migration{{
+			Version:     1,
+			Description: "Add missing maps, bring ratings into 1-5 stars and drop ones without stars",
+			Up: func(doc map[string]any) error {
+				if err := ensureObject(doc, "ratings"); err != nil {
+					return err
+				}
+				ratings := doc["ratings"].(map[string]any)
+				dropNullEntries(ratings)
+				for uuid, v := range ratings {
+					entry, ok := v.(map[string]any)
+					if !ok {
+						return fmt.Errorf("rating of %s is not an object", uuid)
+					}
+					stars, ok := numberValue(entry["rating"])
+					if !ok {
+						delete(ratings, uuid)
+						continue
+					}
+					entry["rating"] = max(1, min(5, stars))
+				}
+				return nil
+			},
+		}},
+	})
+
+	RegisterDataSchema(&DataSchema{
+		File: tagsFileName,
+		Migrations: []Migration{{
+			Version:     1,
+			Description: "Lowercase and deduplicate tags and rebuild the tag index",
+			Up:          migrateTagsV1,
+		}},
+	})
+
+	RegisterDataSchema(&DataSchema{
+		File: StationNotesFileName,
+		Migrations: []Migration{{
+			Version:     1,
+			Description: "Add missing notes map and drop empty notes",
+			Up: func(doc map[string]any) error {
+				if err := ensureObject(doc, "notes"); err != nil {
+					return err
+				}
+				dropNullEntries(doc["notes"].(map[string]any))
+				return nil
+			},
+		}},
+	})
+
+	RegisterDataSchema(&DataSchema{
+		File: LikedSongsFileName,
+		Migrations: []Migration{{
+			Version:     1,
+			Description: "Add missing song list",
+			Up: func(doc map[string]any) error {
+				return ensureArray(doc, "songs")
+			},
+		}},
+	})
+
+	RegisterDataSchema(&DataSchema{
+		File: JournalFileName,
+		Migrations: []Migration{{
+			Version:     1,
+			Description: "Add missing entry list",
+			Up: func(doc map[string]any) error {
+				return ensureArray(doc, "entries")
+			},
+		}},
+	})
+}
+
+// migrateTagsV1 normalizes tags the way AddTag does, keeping the ones it
+// would reject rather than losing them, and rebuilds all_tags from the
+// stations.
+func migrateTagsV1(doc map[string]any) error {
+	for _, key := range []string{"station_tags", "tag_playlists"} {
+		if err := ensureObject(doc, key); err != nil {
+			return err
+		}
+		dropNullEntries(doc[key].(map[string]any))
+	}
+
+	var all []string
+	for _, key := range []string{"station_tags", "tag_playlists"} {
+		for name, v := range doc[key].(map[string]any) {
+			entry, ok := v.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s entry %s is not an object", key, name)
+			}
+			tags, err := tidyTags(entry["tags"])
+			if err != nil {
+				return fmt.Errorf("%s entry %s: %w", key, name, err)
+			}
+			entry["tags"] = tags
+			if key == "station_tags" {
+				all = append(all, tags...)
+			}
+		}
+	}
+	slices.Sort(all)
+	doc["all_tags"] = append([]string{}, slices.Compact(all)...)
+	return nil
+}
+
+// tidyTags lowercases tags, collapses their spaces and drops empty and
+// repeated ones, keeping their order.
+func tidyTags(v any) ([]string, error) {
+	var list []any
+	switch v := v.(type) {
+	case nil:
+	case []any:
+		list = v
+	case []string: // tidied already
+		for _, tag := range v {
+			list = append(list, tag)
+		}
+	default:
+		return nil, fmt.Errorf("tags is not a list")
+	}
+	tags := make([]string, 0, len(list))
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("tag %v is not text", item)
+		}
+		tag := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// dropNullEntries removes the keys of m whose value is null.
+func dropNullEntries(m map[string]any) {
+	for k, v := range m {
+		if v == nil {
+			delete(m, k)
+		}
+	}
+}
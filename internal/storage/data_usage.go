@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+// usageMonthFormat keys StationMetadata.MonthlyBytes.
+const usageMonthFormat = "2006-01"
+
+// usageMonthsKept is how many months of per-station usage are kept: the
+// current month plus a full year for comparison.
+const usageMonthsKept = 13
+
+// MonthlyUsage is the data downloaded in one calendar month.
+type MonthlyUsage struct {
+	Month string // "YYYY-MM"
+	Bytes int64
+}
+
+// StationUsage is the data one station downloaded in a month.
+type StationUsage struct {
+	Station api.Station
+	Bytes   int64
+}
+
+// UsageMonth returns the MonthlyBytes key for t.
+func UsageMonth(t time.Time) string {
+	return t.Format(usageMonthFormat)
+}
+
+// copyStationMetadata returns a copy of metadata that shares no maps with it.
+func copyStationMetadata(metadata *StationMetadata) StationMetadata {
+	metaCopy := *metadata
+	metaCopy.MonthlyBytes = maps.Clone(metadata.MonthlyBytes)
+	return metaCopy
+}
+
+// AddBytes records n bytes of stream data downloaded for a station in the
+// current month.
+func (m *MetadataManager) AddBytes(stationUUID string, n int64) {
+	if n <= 0 || stationUUID == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	metadata, exists := m.store.Stations[stationUUID]
+	if !exists {
+		metadata = &StationMetadata{}
+		m.store.Stations[stationUUID] = metadata
+	}
+	if metadata.MonthlyBytes == nil {
+		metadata.MonthlyBytes = make(map[string]int64)
+	}
+	metadata.TotalBytes += n
+	metadata.MonthlyBytes[UsageMonth(time.Now())] += n
+	pruneUsageMonths(metadata.MonthlyBytes)
+	m.sessionBytes += n
+	m.markDirtyLocked(stationUUID)
+}
+
+// pruneUsageMonths drops all but the newest usageMonthsKept months.
+func pruneUsageMonths(monthly map[string]int64) {
+	if len(monthly) <= usageMonthsKept {
+		return
+	}
+	months := make([]string, 0, len(monthly))
+	for month := range monthly {
+		months = append(months, month)
+	}
+	// "YYYY-MM" sorts chronologically as a string
+	sort.Strings(months)
+	for _, month := range months[:len(months)-usageMonthsKept] {
+		delete(monthly, month)
+	}
+}
+
+// SessionBytes returns the stream data downloaded since the manager was
+// opened.
+func (m *MetadataManager) SessionBytes() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.sessionBytes
+}
+
+// MonthBytes returns the stream data downloaded by all stations in month
+// ("YYYY-MM").
+func (m *MetadataManager) MonthBytes(month string) int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var total int64
+	for _, metadata := range m.store.Stations {
+		total += metadata.MonthlyBytes[month]
+	}
+	return total
+}
+
+// GetMonthlyUsage returns the data downloaded in every recorded month,
+// newest first.
+func (m *MetadataManager) GetMonthlyUsage() []MonthlyUsage {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	totals := make(map[string]int64)
+	for _, metadata := range m.store.Stations {
+		for month, n := range metadata.MonthlyBytes {
+			totals[month] += n
+		}
+	}
+	result := make([]MonthlyUsage, 0, len(totals))
+	for month, n := range totals {
+		result = append(result, MonthlyUsage{Month: month, Bytes: n})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Month > result[j].Month })
+	return result
+}
+
+// GetStationUsage returns the stations that downloaded data in month, the
+// heaviest first, truncated to limit results (0 = no limit).
+func (m *MetadataManager) GetStationUsage(month string, limit int) []StationUsage {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []StationUsage
+	for uuid, metadata := range m.store.Stations {
+		n := metadata.MonthlyBytes[month]
+		if n <= 0 {
+			continue
+		}
+		station := api.Station{StationUUID: uuid}
+		if cached, ok := m.store.StationCache[uuid]; ok {
+			station.Name = cached.Name
+			station.URLResolved = cached.URL
+			station.Bitrate = cached.Bitrate
+		}
+		result = append(result, StationUsage{Station: station, Bytes: n})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Bytes != result[j].Bytes {
+			return result[i].Bytes > result[j].Bytes
+		}
+		return result[i].Station.StationUUID < result[j].Station.StationUUID
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// FormatBytes formats a byte count as a human-readable string using binary
+// units (1 MB = 1024 KB).
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit && exp < 3; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %s", float64(n)/float64(div), []string{"KB", "MB", "GB", "TB"}[exp])
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDataUsage(t *testing.T) {
+	tmpDir := t.TempDir()
+	month := UsageMonth(time.Now())
+
+	mgr, err := NewMetadataManager(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create metadata manager: %v", err)
+	}
+	_ = mgr.StartPlay(testStation("heavy"))
+	mgr.AddBytes("heavy", 3000)
+	mgr.AddBytes("heavy", 2000)
+	mgr.AddBytes("light", 1000)
+	mgr.AddBytes("light", -5) // ignored
+
+	if got := mgr.SessionBytes(); got != 6000 {
+		t.Errorf("SessionBytes = %d, want 6000", got)
+	}
+	if got := mgr.MonthBytes(month); got != 6000 {
+		t.Errorf("MonthBytes = %d, want 6000", got)
+	}
+
+	usage := mgr.GetStationUsage(month, 0)
+	if len(usage) != 2 || usage[0].Station.StationUUID != "heavy" || usage[0].Bytes != 5000 {
+		t.Fatalf("unexpected station usage: %+v", usage)
+	}
+	if usage[0].Station.Name != "Test Station heavy" {
+		t.Errorf("expected the cached station name, got %q", usage[0].Station.Name)
+	}
+
+	// Returned metadata must not share the month map with the store
+	meta := mgr.GetMetadata("heavy")
+	meta.MonthlyBytes[month] = 0
+	if mgr.MonthBytes(month) != 6000 {
+		t.Error("changing a returned copy altered the store")
+	}
+
+	if err := mgr.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Totals survive a reload; the session counter starts again
+	mgr, err = NewMetadataManager(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to reopen metadata manager: %v", err)
+	}
+	defer func() { _ = mgr.Close() }()
+
+	if got := mgr.GetMetadata("heavy").TotalBytes; got != 5000 {
+		t.Errorf("TotalBytes after reload = %d, want 5000", got)
+	}
+	if got := mgr.SessionBytes(); got != 0 {
+		t.Errorf("SessionBytes after reload = %d, want 0", got)
+	}
+	months := mgr.GetMonthlyUsage()
+	if len(months) != 1 || months[0].Month != month || months[0].Bytes != 6000 {
+		t.Errorf("unexpected monthly usage: %+v", months)
+	}
+}
+
+func TestPruneUsageMonths(t *testing.T) {
+	monthly := make(map[string]int64)
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < usageMonthsKept+3; i++ {
+		monthly[UsageMonth(start.AddDate(0, i, 0))] = 1
+	}
+	pruneUsageMonths(monthly)
+
+	if len(monthly) != usageMonthsKept {
+		t.Fatalf("expected %d months, got %d", usageMonthsKept, len(monthly))
+	}
+	if _, ok := monthly["2025-03"]; ok {
+		t.Error("the oldest months should be dropped")
+	}
+	if _, ok := monthly["2026-03"]; !ok {
+		t.Error("the newest month should be kept")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:                      "0 B",
+		1023:                   "1023 B",
+		1536:                   "1.5 KB",
+		50 * 1024 * 1024:       "50.0 MB",
+		3 * 1024 * 1024 * 1024: "3.0 GB",
+		5 << 40:                "5.0 TB",
+		2048 * (1 << 40):       "2048.0 TB",
+	}
+	for n, want := range tests {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,456 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/config"
+
+	_ "modernc.org/sqlite" // pure-Go driver registered as "sqlite"
+)
+
+// DatabaseFileName is the SQLite database kept in the data directory when the
+// sqlite storage backend is selected.
+const DatabaseFileName = "tera.db"
+
+// JSON files that the SQLite backend replaces. They are still written as an
+// export so backups, Gist sync and the JSON backend keep working.
+const (
+	metadataFileName = "station_metadata.json"
+	ratingsFileName  = "station_ratings.json"
+	tagsFileName     = "station_tags.json"
+)
+
+// databaseSchema creates the tables and the indexes behind the Most Played and
+// Top Rated queries. Times are Unix nanoseconds, with 0 for the zero time.
+// Favorite lists are named by their path, as in "Jazz/Smooth", and keep the
+// content of their files, as do the folders' list order files.
+const databaseSchema = `
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS station_metadata (
+	uuid                   TEXT PRIMARY KEY,
+	play_count             INTEGER NOT NULL DEFAULT 0,
+	last_played            INTEGER NOT NULL DEFAULT 0,
+	first_played           INTEGER NOT NULL DEFAULT 0,
+	total_duration_seconds INTEGER NOT NULL DEFAULT 0,
+	total_bytes            INTEGER NOT NULL DEFAULT 0,
+	monthly_bytes          TEXT
+);
+CREATE INDEX IF NOT EXISTS station_metadata_play_count ON station_metadata (play_count DESC, uuid);
+CREATE INDEX IF NOT EXISTS station_metadata_last_played ON station_metadata (last_played DESC, uuid);
+CREATE TABLE IF NOT EXISTS station_ratings (
+	uuid       TEXT PRIMARY KEY,
+	rating     INTEGER NOT NULL,
+	rated_at   INTEGER NOT NULL DEFAULT 0,
+	updated_at INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS station_ratings_rating ON station_ratings (rating DESC, uuid);
+CREATE INDEX IF NOT EXISTS station_ratings_updated_at ON station_ratings (updated_at DESC, uuid);
+CREATE TABLE IF NOT EXISTS station_cache (
+	source   TEXT NOT NULL,
+	uuid     TEXT NOT NULL,
+	name     TEXT NOT NULL DEFAULT '',
+	url      TEXT NOT NULL DEFAULT '',
+	country  TEXT NOT NULL DEFAULT '',
+	language TEXT NOT NULL DEFAULT '',
+	tags     TEXT NOT NULL DEFAULT '',
+	codec    TEXT NOT NULL DEFAULT '',
+	bitrate  INTEGER NOT NULL DEFAULT 0,
+	votes    INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (source, uuid)
+);
+CREATE TABLE IF NOT EXISTS station_tags (
+	uuid       TEXT PRIMARY KEY,
+	tags       TEXT NOT NULL,
+	created_at INTEGER NOT NULL DEFAULT 0,
+	updated_at INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE IF NOT EXISTS tag_playlists (
+	name       TEXT PRIMARY KEY,
+	tags       TEXT NOT NULL,
+	match_mode TEXT NOT NULL,
+	created_at INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE IF NOT EXISTS favorite_lists (
+	name     TEXT PRIMARY KEY,
+	stations TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS favorite_folders (
+	folder     TEXT PRIMARY KEY,
+	list_order TEXT NOT NULL
+);
+`
+
+// databaseFiles are the JSON files the database replaces, with the functions
+// that import them.
+var databaseFiles = []struct {
+	file string
+	load func(data []byte, tx *sql.Tx) error
+}{
+	{metadataFileName, importMetadataJSON},
+	{ratingsFileName, importRatingsJSON},
+	{tagsFileName, importTagsJSON},
+}
+
+// station_cache.source values: the metadata and ratings stores each keep
+// their own copy of station details, as their JSON files do.
+const (
+	cacheSourceMetadata = "metadata"
+	cacheSourceRatings  = "ratings"
+)
+
+// Database is the SQLite store used by MetadataManager, RatingsManager,
+// TagsManager and the favorites Storage when the sqlite backend is selected.
+// The managers keep their in-memory stores and write only the rows that
+// changed; Storage reads and writes one list at a time, as with files.
+type Database struct {
+	db       *sql.DB
+	dataPath string
+
+	mu      sync.Mutex
+	imports map[string]uint64 // times each JSON file was imported
+}
+
+var activeDatabase atomic.Pointer[Database]
+
+// UseDatabase makes managers created for db's data directory store their data
+// in db. Pass nil to go back to JSON files.
+func UseDatabase(db *Database) {
+	activeDatabase.Store(db)
+}
+
+// databaseFor returns the active database if it belongs to dataPath.
+func databaseFor(dataPath string) *Database {
+	db := activeDatabase.Load()
+	if db == nil || filepath.Clean(db.dataPath) != filepath.Clean(dataPath) {
+		return nil
+	}
+	return db
+}
+
+// OpenConfiguredDatabase opens the database in dataPath and makes it active
+// when the unified config selects the sqlite backend, or returns the one
+// already active. It returns nil when the JSON files are in use.
+func OpenConfiguredDatabase(dataPath string) (*Database, error) {
+	if db := databaseFor(dataPath); db != nil {
+		return db, nil
+	}
+	cfg, err := LoadStorageConfigFromUnified()
+	if err != nil || cfg.Backend != config.StorageBackendSQLite {
+		return nil, nil
+	}
+	db, err := OpenDatabase(dataPath)
+	if err != nil {
+		return nil, err
+	}
+	UseDatabase(db)
+	return db, nil
+}
+
+// OpenDatabase opens or creates the database in dataPath. JSON files that
+// changed since they were last imported or exported, including every file and
+// the favorites on first use, are imported so the database starts with the
+// user's data.
+func OpenDatabase(dataPath string) (*Database, error) {
+	if err := os.MkdirAll(dataPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	dsn := "file:" + filepath.ToSlash(filepath.Join(dataPath, DatabaseFileName)) +
+		"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)"
+	sqlDB, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	// One connection serializes writers within the process; busy_timeout
+	// covers other tera processes such as "tera play".
+	sqlDB.SetMaxOpenConns(1)
+
+	d := &Database{db: sqlDB, dataPath: dataPath, imports: make(map[string]uint64)}
+	if _, err := sqlDB.Exec(databaseSchema); err != nil {
+		_ = sqlDB.Close()
+		return nil, fmt.Errorf("failed to create database schema: %w", err)
+	}
+	if err := d.importChangedJSON(); err != nil {
+		_ = sqlDB.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// Close writes the JSON export and closes the database. Managers using it
+// must be closed first so their last changes are included.
+func (d *Database) Close() error {
+	activeDatabase.CompareAndSwap(d, nil)
+	exportErr := d.ExportJSON()
+	if err := d.db.Close(); err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+	return exportErr
+}
+
+// Path returns the database file path.
+func (d *Database) Path() string {
+	return filepath.Join(d.dataPath, DatabaseFileName)
+}
+
+// importChangedJSON imports each JSON file whose modification time differs
+// from the one recorded when it was last imported or exported, and the
+// favorites files that changed.
+func (d *Database) importChangedJSON() error {
+	for _, f := range databaseFiles {
+		if _, err := d.importJSON(f.file); err != nil {
+			return err
+		}
+	}
+	_, err := d.importFavorites()
+	return err
+}
+
+// importJSON imports file if it changed since it was last imported or
+// exported, such as after a restore, and reports whether it did. The file's
+// lock is held meanwhile, so another process does not save it half-way.
+func (d *Database) importJSON(file string) (bool, error) {
+	var load func(data []byte, tx *sql.Tx) error
+	for _, f := range databaseFiles {
+		if f.file == file {
+			load = f.load
+		}
+	}
+	path := filepath.Join(d.dataPath, file)
+	MigrateOnLoad(path)
+	lock, err := LockFile(path)
+	if err != nil {
+		return false, err
+	}
+	defer lock.Unlock()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if d.syncedModTime(file) == info.ModTime().UnixNano() {
+		return false, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	err = d.withTx(func(tx *sql.Tx) error {
+		if err := load(data, tx); err != nil {
+			return err
+		}
+		return setSyncedModTime(tx, file, info.ModTime())
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to import %s: %w", file, err)
+	}
+	d.mu.Lock()
+	d.imports[file]++
+	d.mu.Unlock()
+	return true, nil
+}
+
+// importCount returns how many times file was imported since the database
+// was opened. A manager compares it with the count its store was loaded at
+// to tell whether the database changed under it.
+func (d *Database) importCount(file string) uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.imports[file]
+}
+
+// ExportJSON writes the database contents to the JSON files and favorites
+// directory it replaces, in the format the JSON backend reads. A file
+// changed on disk since it was last imported or exported, such as one just
+// restored, is newer than the database and left alone, to be imported
+// instead.
+func (d *Database) ExportJSON() error {
+	metadata, err := d.loadMetadataStore()
+	if err != nil {
+		return err
+	}
+	ratings, err := d.loadRatingsStore()
+	if err != nil {
+		return err
+	}
+	tags, err := d.loadTagsStore()
+	if err != nil {
+		return err
+	}
+	exports := []struct {
+		file  string
+		store any
+	}{
+		{metadataFileName, metadata},
+		{ratingsFileName, ratings},
+		{tagsFileName, tags},
+	}
+	for _, exp := range exports {
+		if err := d.exportJSON(exp.file, exp.store); err != nil {
+			return err
+		}
+	}
+	return d.exportFavorites()
+}
+
+// exportJSON writes store to file unless the file changed since it was last
+// imported or exported.
+func (d *Database) exportJSON(file string, store any) error {
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", file, err)
+	}
+	path := filepath.Join(d.dataPath, file)
+	lock, err := LockFile(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	if info, err := os.Stat(path); err == nil && info.ModTime().UnixNano() != d.syncedModTime(file) {
+		return nil
+	}
+	if err := atomicWriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to export %s: %w", file, err)
+	}
+	noteOwnWrite(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to export %s: %w", file, err)
+	}
+	err = d.withTx(func(tx *sql.Tx) error {
+		return setSyncedModTime(tx, file, info.ModTime())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export %s: %w", file, err)
+	}
+	return nil
+}
+
+// exportDatabaseJSON refreshes the JSON files in dataPath when they are
+// exports of the active database.
+func exportDatabaseJSON(dataPath string) error {
+	if db := databaseFor(dataPath); db != nil {
+		return db.ExportJSON()
+	}
+	return nil
+}
+
+// importDatabaseJSON imports the JSON files in dataPath that changed, such as
+// ones a restore or Gist pull just wrote, into the active database, where the
+// managers reload them from.
+func importDatabaseJSON(dataPath string) error {
+	if db := databaseFor(dataPath); db != nil {
+		return db.importChangedJSON()
+	}
+	return nil
+}
+
+// syncedModTime returns the recorded modification time of file, or 0.
+func (d *Database) syncedModTime(file string) int64 {
+	n, _ := strconv.ParseInt(d.syncedValue(file), 10, 64)
+	return n
+}
+
+func setSyncedModTime(tx *sql.Tx, file string, modTime time.Time) error {
+	return setSyncedValue(tx, file, strconv.FormatInt(modTime.UnixNano(), 10))
+}
+
+// syncedValue returns what was recorded of the version of file when it was
+// last imported or exported, or "".
+func (d *Database) syncedValue(file string) string {
+	var value string
+	if err := d.db.QueryRow(`SELECT value FROM meta WHERE key = ?`, "synced:"+file).Scan(&value); err != nil {
+		return ""
+	}
+	return value
+}
+
+func setSyncedValue(tx *sql.Tx, file, value string) error {
+	_, err := tx.Exec(`INSERT INTO meta (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`,
+		"synced:"+file, value)
+	return err
+}
+
+func deleteSyncedValue(tx *sql.Tx, file string) error {
+	_, err := tx.Exec(`DELETE FROM meta WHERE key = ?`, "synced:"+file)
+	return err
+}
+
+// withTx runs fn in a transaction, committing when it succeeds.
+func (d *Database) withTx(fn func(tx *sql.Tx) error) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+// dbTime converts t to the stored form; the zero time is stored as 0.
+func dbTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// fromDBTime is the inverse of dbTime.
+func fromDBTime(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// dirtySet records which keys of a manager's store changed since the last
+// save. all means the whole store must be rewritten.
+type dirtySet struct {
+	keys map[string]struct{}
+	all  bool
+}
+
+func (s *dirtySet) mark(key string) {
+	if s.keys == nil {
+		s.keys = make(map[string]struct{})
+	}
+	s.keys[key] = struct{}{}
+}
+
+func (s *dirtySet) markAll() {
+	s.all = true
+}
+
+// take returns the recorded changes and clears s.
+func (s *dirtySet) take() dirtySet {
+	taken := *s
+	*s = dirtySet{}
+	return taken
+}
+
+// restore adds changes that failed to save back into s.
+func (s *dirtySet) restore(failed dirtySet) {
+	if failed.all {
+		s.all = true
+	}
+	for key := range failed.keys {
+		s.mark(key)
+	}
+}
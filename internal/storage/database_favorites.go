@@ -0,0 +1,466 @@
+package storage
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The favorites directory holds one file per list, in folders, and an order
+// file per folder. With the sqlite backend the database holds the same files'
+// contents and the directory is an export. Each file is imported and
+// exported on its own, by its modification time like the other JSON files,
+// so a file written to the directory replaces only its own list.
+
+// favoritesDirName is the favorites directory in the data directory.
+const favoritesDirName = "favorites"
+
+// databaseForFavorites returns the active database if favoritePath is the
+// favorites directory of its data directory.
+func databaseForFavorites(favoritePath string) *Database {
+	db := activeDatabase.Load()
+	if db == nil || filepath.Clean(db.favoritesPath()) != filepath.Clean(favoritePath) {
+		return nil
+	}
+	return db
+}
+
+// favoritesPath returns the favorites directory the database stands in for.
+func (d *Database) favoritesPath() string {
+	return filepath.Join(d.dataPath, favoritesDirName)
+}
+
+// listNotExist is the error for a missing list, which os.IsNotExist
+// recognizes as it does a missing list file.
+func listNotExist(op, name string) error {
+	return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+func (d *Database) loadFavoriteList(name string) ([]byte, error) {
+	var data string
+	err := d.db.QueryRow(`SELECT stations FROM favorite_lists WHERE name = ?`, name).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, listNotExist("open", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(data), nil
+}
+
+func (d *Database) saveFavoriteList(name string, data []byte) error {
+	_, err := d.db.Exec(`INSERT INTO favorite_lists (name, stations) VALUES (?, ?)
+		ON CONFLICT (name) DO UPDATE SET stations = excluded.stations`, name, string(data))
+	return err
+}
+
+func (d *Database) favoriteListExists(name string) bool {
+	var n int
+	err := d.db.QueryRow(`SELECT COUNT(*) FROM favorite_lists WHERE name = ?`, name).Scan(&n)
+	return err == nil && n > 0
+}
+
+func (d *Database) deleteFavoriteList(name string) error {
+	result, err := d.db.Exec(`DELETE FROM favorite_lists WHERE name = ?`, name)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return listNotExist("remove", name)
+	}
+	return nil
+}
+
+func (d *Database) renameFavoriteList(oldName, newName string) error {
+	result, err := d.db.Exec(`UPDATE favorite_lists SET name = ? WHERE name = ?`, newName, oldName)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return listNotExist("rename", oldName)
+	}
+	return nil
+}
+
+// favoriteChildren returns the lists and subfolders ("Name/") of folder,
+// sorted by the names of their files, as os.ReadDir returns them.
+func (d *Database) favoriteChildren(folder string) ([]string, error) {
+	rows, err := d.db.Query(`SELECT name FROM favorite_lists`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	prefix := ""
+	if folder != "" {
+		prefix = folder + "/"
+	}
+	seen := make(map[string]bool)
+	var keys []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		key := rest
+		if sub, _, nested := strings.Cut(rest, "/"); nested {
+			key = sub + "/"
+		}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	fileName := func(key string) string {
+		if sub, ok := strings.CutSuffix(key, "/"); ok {
+			return sub
+		}
+		return key + ".json"
+	}
+	sort.Slice(keys, func(i, j int) bool { return fileName(keys[i]) < fileName(keys[j]) })
+	return keys, nil
+}
+
+func (d *Database) loadListOrder(folder string) listOrder {
+	var o listOrder
+	var data string
+	if err := d.db.QueryRow(`SELECT list_order FROM favorite_folders WHERE folder = ?`, folder).Scan(&data); err == nil {
+		_ = json.Unmarshal([]byte(data), &o)
+	}
+	return o
+}
+
+func (d *Database) saveListOrder(folder string, data []byte) error {
+	_, err := d.db.Exec(`INSERT INTO favorite_folders (folder, list_order) VALUES (?, ?)
+		ON CONFLICT (folder) DO UPDATE SET list_order = excluded.list_order`, folder, string(data))
+	return err
+}
+
+func (d *Database) deleteListOrder(folder string) error {
+	_, err := d.db.Exec(`DELETE FROM favorite_folders WHERE folder = ?`, folder)
+	return err
+}
+
+// favoritesFiles returns the contents of the favorites in the database by
+// their paths in the favorites directory, such as "Jazz/Smooth.json" and
+// "Jazz/.order.json".
+func (d *Database) favoritesFiles() (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	rows, err := d.db.Query(`SELECT name, stations FROM favorite_lists`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var name, data string
+		if err := rows.Scan(&name, &data); err != nil {
+			return nil, err
+		}
+		files[name+".json"] = []byte(data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	orders, err := d.db.Query(`SELECT folder, list_order FROM favorite_folders`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = orders.Close() }()
+	for orders.Next() {
+		var folder, data string
+		if err := orders.Scan(&folder, &data); err != nil {
+			return nil, err
+		}
+		files[path.Join(folder, SystemFileListOrder)] = []byte(data)
+	}
+	return files, orders.Err()
+}
+
+// isFavoritesFile reports whether rel, a slash-separated path in the
+// favorites directory, is a list or order file.
+func isFavoritesFile(rel string) bool {
+	name := path.Base(rel)
+	if name == SystemFileListOrder {
+		return true
+	}
+	return !strings.HasPrefix(name, ".") && path.Ext(name) == ".json" && rel != SystemFileSearchHistory
+}
+
+// walkFavorites calls fn for each list and order file in dir, by its
+// slash-separated path, in lexical order. TERA's hidden directories, such
+// as .backup, are skipped.
+func walkFavorites(dir string, fn func(rel string, info fs.FileInfo) error) error {
+	return filepath.WalkDir(dir, func(p string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if e.IsDir() {
+			if p != dir && strings.HasPrefix(e.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !isFavoritesFile(rel) {
+			return nil
+		}
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+		return fn(rel, info)
+	})
+}
+
+// favoritesSyncFile is the name the modification time of the favorites
+// file rel is recorded under, such as "favorites/Jazz/Smooth.json".
+func favoritesSyncFile(rel string) string {
+	return path.Join(favoritesDirName, rel)
+}
+
+// syncedFavorites returns the recorded modification times of the favorites
+// files, by their paths in the favorites directory.
+func (d *Database) syncedFavorites() (map[string]int64, error) {
+	prefix := "synced:" + favoritesDirName + "/"
+	rows, err := d.db.Query(`SELECT key, value FROM meta WHERE substr(key, 1, ?) = ?`, len(prefix), prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	synced := make(map[string]int64)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		synced[strings.TrimPrefix(key, prefix)], _ = strconv.ParseInt(value, 10, 64)
+	}
+	return synced, rows.Err()
+}
+
+// favoritesOnDisk returns the list and order files in dir by their paths.
+func favoritesOnDisk(dir string) (map[string]fs.FileInfo, error) {
+	files := make(map[string]fs.FileInfo)
+	err := walkFavorites(dir, func(rel string, info fs.FileInfo) error {
+		files[rel] = info
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return files, nil
+}
+
+// storeFavoritesFile saves the contents of the favorites file rel.
+func storeFavoritesFile(tx *sql.Tx, rel string, data []byte) error {
+	var err error
+	if folder, name := path.Split(rel); name == SystemFileListOrder {
+		_, err = tx.Exec(`INSERT INTO favorite_folders (folder, list_order) VALUES (?, ?)
+			ON CONFLICT (folder) DO UPDATE SET list_order = excluded.list_order`,
+			strings.TrimSuffix(folder, "/"), string(data))
+	} else {
+		_, err = tx.Exec(`INSERT INTO favorite_lists (name, stations) VALUES (?, ?)
+			ON CONFLICT (name) DO UPDATE SET stations = excluded.stations`,
+			strings.TrimSuffix(rel, ".json"), string(data))
+	}
+	return err
+}
+
+// removeFavoritesFile deletes the list or order of the favorites file rel.
+func removeFavoritesFile(tx *sql.Tx, rel string) error {
+	var err error
+	if folder, name := path.Split(rel); name == SystemFileListOrder {
+		_, err = tx.Exec(`DELETE FROM favorite_folders WHERE folder = ?`, strings.TrimSuffix(folder, "/"))
+	} else {
+		_, err = tx.Exec(`DELETE FROM favorite_lists WHERE name = ?`, strings.TrimSuffix(rel, ".json"))
+	}
+	return err
+}
+
+// importFavorites takes in the files of the favorites directory that were
+// written or removed since they were last imported or exported, and reports
+// whether there were any. Lists changed only in the database are kept, so
+// a file copied in or restored replaces just its own list. Files are taken
+// as they are, so a list that can't be parsed fails to load as it would
+// from its file.
+func (d *Database) importFavorites() (bool, error) {
+	dir := d.favoritesPath()
+	lock, err := LockFile(dir)
+	if err != nil {
+		return false, err
+	}
+	defer lock.Unlock()
+
+	synced, err := d.syncedFavorites()
+	if err != nil {
+		return false, fmt.Errorf("failed to import favorites: %w", err)
+	}
+	onDisk, err := favoritesOnDisk(dir)
+	if err != nil {
+		return false, fmt.Errorf("failed to read favorites: %w", err)
+	}
+	changed := make(map[string][]byte)
+	for rel, info := range onDisk {
+		if modTime, ok := synced[rel]; ok && modTime == info.ModTime().UnixNano() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return false, fmt.Errorf("failed to read favorites: %w", err)
+		}
+		changed[rel] = data
+	}
+	var removed []string
+	for rel := range synced {
+		if _, ok := onDisk[rel]; !ok {
+			removed = append(removed, rel)
+		}
+	}
+	if len(changed) == 0 && len(removed) == 0 {
+		return false, nil
+	}
+
+	err = d.withTx(func(tx *sql.Tx) error {
+		for rel, data := range changed {
+			if err := storeFavoritesFile(tx, rel, data); err != nil {
+				return err
+			}
+			if err := setSyncedModTime(tx, favoritesSyncFile(rel), onDisk[rel].ModTime()); err != nil {
+				return err
+			}
+		}
+		for _, rel := range removed {
+			if err := removeFavoritesFile(tx, rel); err != nil {
+				return err
+			}
+			if err := deleteSyncedValue(tx, favoritesSyncFile(rel)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to import favorites: %w", err)
+	}
+	return true, nil
+}
+
+// exportFavorites makes the favorites directory match the database: files
+// that differ are written, and those of lists and folders that are gone are
+// removed along with emptied folders. A file written or removed since it
+// was last imported or exported is newer than the database and left alone,
+// to be imported instead.
+func (d *Database) exportFavorites() error {
+	dir := d.favoritesPath()
+	lock, err := LockFile(dir)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	synced, err := d.syncedFavorites()
+	if err != nil {
+		return fmt.Errorf("failed to export favorites: %w", err)
+	}
+	onDisk, err := favoritesOnDisk(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read favorites: %w", err)
+	}
+	files, err := d.favoritesFiles()
+	if err != nil {
+		return err
+	}
+	newer := func(rel string) bool {
+		modTime, ok := synced[rel]
+		if info, exists := onDisk[rel]; exists {
+			return !ok || info.ModTime().UnixNano() != modTime
+		}
+		return ok
+	}
+
+	written := make(map[string]time.Time)
+	for rel, data := range files {
+		if newer(rel) {
+			continue
+		}
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if old, err := os.ReadFile(p); err == nil && bytes.Equal(old, data) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return fmt.Errorf("failed to export favorites: %w", err)
+		}
+		if err := atomicWriteFile(p, data, 0644); err != nil {
+			return fmt.Errorf("failed to export favorites: %w", err)
+		}
+		noteOwnWrite(p)
+		info, err := os.Stat(p)
+		if err != nil {
+			return fmt.Errorf("failed to export favorites: %w", err)
+		}
+		written[rel] = info.ModTime()
+	}
+	var removed, folders []string
+	for rel := range onDisk {
+		if folder := path.Dir(rel); folder != "." {
+			folders = append(folders, folder)
+		}
+		if _, ok := files[rel]; ok || newer(rel) {
+			continue
+		}
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to export favorites: %w", err)
+		}
+		noteOwnWrite(p)
+		removed = append(removed, rel)
+	}
+	// Deepest first, so a folder emptied by removing its subfolders goes too.
+	// Removing a folder that still holds anything fails and keeps it.
+	sort.Sort(sort.Reverse(sort.StringSlice(folders)))
+	for _, folder := range folders {
+		for ; folder != "."; folder = path.Dir(folder) {
+			_ = os.Remove(filepath.Join(dir, filepath.FromSlash(folder)))
+		}
+	}
+
+	err = d.withTx(func(tx *sql.Tx) error {
+		for rel, modTime := range written {
+			if err := setSyncedModTime(tx, favoritesSyncFile(rel), modTime); err != nil {
+				return err
+			}
+		}
+		for _, rel := range removed {
+			if err := deleteSyncedValue(tx, favoritesSyncFile(rel)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export favorites: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,776 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+// Orderings for the indexed Most Played and Top Rated queries. The UUID
+// tiebreak matches the in-memory sorts.
+const (
+	orderTopPlayed      = "m.play_count DESC, m.uuid"
+	orderRecentlyPlayed = "m.last_played DESC, m.uuid"
+	orderTopRated       = "r.rating DESC, r.uuid"
+	orderRecentlyRated  = "r.updated_at DESC, r.uuid"
+)
+
+// cacheColumns selects a station_cache row joined as c, with empty values
+// when the station has no cached details.
+const cacheColumns = `COALESCE(c.name, ''), COALESCE(c.url, ''), COALESCE(c.country, ''),
+	COALESCE(c.language, ''), COALESCE(c.tags, ''), COALESCE(c.codec, ''),
+	COALESCE(c.bitrate, 0), COALESCE(c.votes, 0)`
+
+// sqlLimit converts a "0 = no limit" count to SQLite's LIMIT.
+func sqlLimit(limit int) int {
+	if limit <= 0 {
+		return -1
+	}
+	return limit
+}
+
+func writeCachedStation(tx *sql.Tx, source, uuid string, c *CachedStation) error {
+	_, err := tx.Exec(`INSERT OR REPLACE INTO station_cache
+		(source, uuid, name, url, country, language, tags, codec, bitrate, votes)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		source, uuid, c.Name, c.URL, c.Country, c.Language, c.Tags, c.Codec, c.Bitrate, c.Votes)
+	return err
+}
+
+// loadCachedStations returns source's station cache keyed by UUID.
+func (d *Database) loadCachedStations(source string) (map[string]*CachedStation, error) {
+	rows, err := d.db.Query(`SELECT uuid, name, url, country, language, tags, codec, bitrate, votes
+		FROM station_cache WHERE source = ?`, source)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	cache := make(map[string]*CachedStation)
+	for rows.Next() {
+		var uuid string
+		var c CachedStation
+		if err := rows.Scan(&uuid, &c.Name, &c.URL, &c.Country, &c.Language, &c.Tags, &c.Codec, &c.Bitrate, &c.Votes); err != nil {
+			return nil, err
+		}
+		cache[uuid] = &c
+	}
+	return cache, rows.Err()
+}
+
+// stationFromCache builds the api.Station shown for a cached entry, as
+// sortedStationsLocked does.
+func stationFromCache(uuid string, c CachedStation) api.Station {
+	return api.Station{
+		StationUUID: uuid,
+		Name:        c.Name,
+		URLResolved: c.URL,
+		Country:     c.Country,
+		Language:    c.Language,
+		Tags:        c.Tags,
+		Codec:       c.Codec,
+		Bitrate:     c.Bitrate,
+		Votes:       c.Votes,
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Play statistics
+// ---------------------------------------------------------------------------
+
+// metadataChange is one station's statistics to save; nil Metadata deletes
+// the station.
+type metadataChange struct {
+	uuid     string
+	metadata *StationMetadata
+	cache    *CachedStation
+}
+
+// saveMetadata writes changes, first emptying the tables when replace is set.
+func (d *Database) saveMetadata(replace bool, changes []metadataChange) error {
+	return d.withTx(func(tx *sql.Tx) error {
+		return writeMetadata(tx, replace, changes)
+	})
+}
+
+func writeMetadata(tx *sql.Tx, replace bool, changes []metadataChange) error {
+	if replace {
+		if _, err := tx.Exec(`DELETE FROM station_metadata`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM station_cache WHERE source = ?`, cacheSourceMetadata); err != nil {
+			return err
+		}
+	}
+	for _, ch := range changes {
+		if ch.metadata == nil {
+			if _, err := tx.Exec(`DELETE FROM station_metadata WHERE uuid = ?`, ch.uuid); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(`DELETE FROM station_cache WHERE source = ? AND uuid = ?`, cacheSourceMetadata, ch.uuid); err != nil {
+				return err
+			}
+			continue
+		}
+		var monthly []byte
+		if len(ch.metadata.MonthlyBytes) > 0 {
+			var err error
+			if monthly, err = json.Marshal(ch.metadata.MonthlyBytes); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec(`INSERT OR REPLACE INTO station_metadata
+			(uuid, play_count, last_played, first_played, total_duration_seconds, total_bytes, monthly_bytes)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			ch.uuid, ch.metadata.PlayCount, dbTime(ch.metadata.LastPlayed), dbTime(ch.metadata.FirstPlayed),
+			ch.metadata.TotalDurationSeconds, ch.metadata.TotalBytes, nullableText(monthly))
+		if err != nil {
+			return err
+		}
+		if ch.cache != nil {
+			if err := writeCachedStation(tx, cacheSourceMetadata, ch.uuid, ch.cache); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// nullableText stores an empty value as NULL.
+func nullableText(b []byte) any {
+	if len(b) == 0 {
+		return nil
+	}
+	return string(b)
+}
+
+// scanMetadata reads the station_metadata columns selected by metadataColumns.
+func scanMetadata(scan func(dest ...any) error, extra ...any) (string, *StationMetadata, error) {
+	var uuid string
+	var lastPlayed, firstPlayed int64
+	var monthly sql.NullString
+	meta := &StationMetadata{}
+	dest := append([]any{&uuid, &meta.PlayCount, &lastPlayed, &firstPlayed,
+		&meta.TotalDurationSeconds, &meta.TotalBytes, &monthly}, extra...)
+	if err := scan(dest...); err != nil {
+		return "", nil, err
+	}
+	meta.LastPlayed = fromDBTime(lastPlayed)
+	meta.FirstPlayed = fromDBTime(firstPlayed)
+	if monthly.Valid {
+		if err := json.Unmarshal([]byte(monthly.String), &meta.MonthlyBytes); err != nil {
+			return "", nil, err
+		}
+	}
+	return uuid, meta, nil
+}
+
+const metadataColumns = `m.uuid, m.play_count, m.last_played, m.first_played,
+	m.total_duration_seconds, m.total_bytes, m.monthly_bytes`
+
+// loadMetadataStore reads every station's statistics.
+func (d *Database) loadMetadataStore() (*MetadataStore, error) {
+	cache, err := d.loadCachedStations(cacheSourceMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load station cache: %w", err)
+	}
+	rows, err := d.db.Query(`SELECT ` + metadataColumns + ` FROM station_metadata m`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	store := &MetadataStore{
+		Stations:     make(map[string]*StationMetadata),
+		StationCache: cache,
+		Version:      1,
+	}
+	for rows.Next() {
+		uuid, meta, err := scanMetadata(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load metadata: %w", err)
+		}
+		store.Stations[uuid] = meta
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load metadata: %w", err)
+	}
+	return store, nil
+}
+
+// queryMetadata returns stations in the given order using the indexes.
+func (d *Database) queryMetadata(order string, limit int) ([]StationWithMetadata, error) {
+	rows, err := d.db.Query(`SELECT `+metadataColumns+`, `+cacheColumns+`
+		FROM station_metadata m
+		LEFT JOIN station_cache c ON c.source = ? AND c.uuid = m.uuid
+		ORDER BY `+order+` LIMIT ?`, cacheSourceMetadata, sqlLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []StationWithMetadata
+	for rows.Next() {
+		var c CachedStation
+		uuid, meta, err := scanMetadata(rows.Scan,
+			&c.Name, &c.URL, &c.Country, &c.Language, &c.Tags, &c.Codec, &c.Bitrate, &c.Votes)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, StationWithMetadata{Station: stationFromCache(uuid, c), Metadata: meta})
+	}
+	return result, rows.Err()
+}
+
+func importMetadataJSON(data []byte, tx *sql.Tx) error {
+	var store MetadataStore
+	if err := json.Unmarshal(data, &store); err != nil {
+		return err
+	}
+	changes := make([]metadataChange, 0, len(store.Stations))
+	for uuid, meta := range store.Stations {
+		if meta == nil {
+			continue
+		}
+		changes = append(changes, metadataChange{uuid: uuid, metadata: meta, cache: store.StationCache[uuid]})
+	}
+	return writeMetadata(tx, true, changes)
+}
+
+// saveToDatabase writes the stations changed since the last save.
+func (m *MetadataManager) saveToDatabase() error {
+	m.mu.Lock()
+	dirty := m.dirty.take()
+	var changes []metadataChange
+	if dirty.all {
+		for uuid := range m.store.Stations {
+			changes = append(changes, m.metadataChangeLocked(uuid))
+		}
+	} else {
+		for uuid := range dirty.keys {
+			changes = append(changes, m.metadataChangeLocked(uuid))
+		}
+	}
+	m.mu.Unlock()
+
+	if err := m.db.saveMetadata(dirty.all, changes); err != nil {
+		m.mu.Lock()
+		m.dirty.restore(dirty)
+		m.mu.Unlock()
+		return fmt.Errorf("failed to save metadata: %w", err)
+	}
+	return nil
+}
+
+// reloadFromDatabase imports station_metadata.json if it changed on disk, as
+// after a restore, and takes in the imported statistics.
+func (m *MetadataManager) reloadFromDatabase() (bool, error) {
+	m.saveMu.Lock()
+	defer m.saveMu.Unlock()
+	if _, err := m.db.importJSON(metadataFileName); err != nil {
+		return false, err
+	}
+	imports := m.db.importCount(metadataFileName)
+	if imports == m.dbImports.Load() {
+		return false, nil
+	}
+	store, err := m.db.loadMetadataStore()
+	if err != nil {
+		return false, err
+	}
+	m.dbImports.Store(imports)
+	return m.replaceStore(store), nil
+}
+
+// metadataChangeLocked copies a station's statistics for saving. Caller must
+// hold m.mu.
+func (m *MetadataManager) metadataChangeLocked(uuid string) metadataChange {
+	ch := metadataChange{uuid: uuid}
+	if metadata, ok := m.store.Stations[uuid]; ok {
+		metaCopy := copyStationMetadata(metadata)
+		ch.metadata = &metaCopy
+	}
+	if cached, ok := m.store.StationCache[uuid]; ok {
+		cachedCopy := *cached
+		ch.cache = &cachedCopy
+	}
+	return ch
+}
+
+// markDirtyLocked records that a station changed. Caller must hold m.mu.
+func (m *MetadataManager) markDirtyLocked(uuid string) {
+	m.dirty.mark(uuid)
+	m.savePending.Store(true)
+}
+
+// queryDatabase saves pending changes and runs an indexed query. ok is false
+// when the JSON backend is in use or the query failed.
+func (m *MetadataManager) queryDatabase(order string, limit int) (result []StationWithMetadata, ok bool) {
+	if m.db == nil {
+		return nil, false
+	}
+	if m.savePending.CompareAndSwap(true, false) {
+		if err := m.Save(); err != nil {
+			m.savePending.Store(true)
+			return nil, false
+		}
+	}
+	result, err := m.db.queryMetadata(order, limit)
+	if err != nil {
+		return nil, false
+	}
+	if result == nil {
+		result = []StationWithMetadata{}
+	}
+	return result, true
+}
+
+// ---------------------------------------------------------------------------
+// Ratings
+// ---------------------------------------------------------------------------
+
+// ratingChange is one station's rating to save; nil Rating deletes it.
+type ratingChange struct {
+	uuid   string
+	rating *StationRating
+	cache  *RatingsCachedStation
+}
+
+func (d *Database) saveRatings(replace bool, changes []ratingChange) error {
+	return d.withTx(func(tx *sql.Tx) error {
+		return writeRatings(tx, replace, changes)
+	})
+}
+
+func writeRatings(tx *sql.Tx, replace bool, changes []ratingChange) error {
+	if replace {
+		if _, err := tx.Exec(`DELETE FROM station_ratings`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM station_cache WHERE source = ?`, cacheSourceRatings); err != nil {
+			return err
+		}
+	}
+	for _, ch := range changes {
+		if ch.rating == nil {
+			if _, err := tx.Exec(`DELETE FROM station_ratings WHERE uuid = ?`, ch.uuid); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(`DELETE FROM station_cache WHERE source = ? AND uuid = ?`, cacheSourceRatings, ch.uuid); err != nil {
+				return err
+			}
+			continue
+		}
+		_, err := tx.Exec(`INSERT OR REPLACE INTO station_ratings (uuid, rating, rated_at, updated_at)
+			VALUES (?, ?, ?, ?)`,
+			ch.uuid, ch.rating.Rating, dbTime(ch.rating.RatedAt), dbTime(ch.rating.UpdatedAt))
+		if err != nil {
+			return err
+		}
+		if ch.cache != nil {
+			cached := CachedStation(*ch.cache)
+			if err := writeCachedStation(tx, cacheSourceRatings, ch.uuid, &cached); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+const ratingColumns = `r.uuid, r.rating, r.rated_at, r.updated_at`
+
+func scanRating(scan func(dest ...any) error, extra ...any) (string, *StationRating, error) {
+	var uuid string
+	var ratedAt, updatedAt int64
+	rating := &StationRating{}
+	if err := scan(append([]any{&uuid, &rating.Rating, &ratedAt, &updatedAt}, extra...)...); err != nil {
+		return "", nil, err
+	}
+	rating.RatedAt = fromDBTime(ratedAt)
+	rating.UpdatedAt = fromDBTime(updatedAt)
+	return uuid, rating, nil
+}
+
+// loadRatingsStore reads every rating.
+func (d *Database) loadRatingsStore() (*RatingsStore, error) {
+	cache, err := d.loadCachedStations(cacheSourceRatings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load station cache: %w", err)
+	}
+	rows, err := d.db.Query(`SELECT ` + ratingColumns + ` FROM station_ratings r`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ratings: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	store := &RatingsStore{
+		Ratings:      make(map[string]*StationRating),
+		StationCache: make(map[string]*RatingsCachedStation, len(cache)),
+		Version:      1,
+	}
+	for uuid, c := range cache {
+		rc := RatingsCachedStation(*c)
+		store.StationCache[uuid] = &rc
+	}
+	for rows.Next() {
+		uuid, rating, err := scanRating(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load ratings: %w", err)
+		}
+		store.Ratings[uuid] = rating
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load ratings: %w", err)
+	}
+	return store, nil
+}
+
+// queryRatings returns ratings of at least minRating in the given order
+// using the indexes.
+func (d *Database) queryRatings(order string, minRating, limit int) ([]StationWithRating, error) {
+	rows, err := d.db.Query(`SELECT `+ratingColumns+`, `+cacheColumns+`
+		FROM station_ratings r
+		LEFT JOIN station_cache c ON c.source = ? AND c.uuid = r.uuid
+		WHERE r.rating >= ?
+		ORDER BY `+order+` LIMIT ?`, cacheSourceRatings, minRating, sqlLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []StationWithRating
+	for rows.Next() {
+		var c CachedStation
+		uuid, rating, err := scanRating(rows.Scan,
+			&c.Name, &c.URL, &c.Country, &c.Language, &c.Tags, &c.Codec, &c.Bitrate, &c.Votes)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, StationWithRating{Station: stationFromCache(uuid, c), Rating: rating})
+	}
+	return result, rows.Err()
+}
+
+func importRatingsJSON(data []byte, tx *sql.Tx) error {
+	var store RatingsStore
+	if err := json.Unmarshal(data, &store); err != nil {
+		return err
+	}
+	changes := make([]ratingChange, 0, len(store.Ratings))
+	for uuid, rating := range store.Ratings {
+		if rating == nil {
+			continue
+		}
+		changes = append(changes, ratingChange{uuid: uuid, rating: rating, cache: store.StationCache[uuid]})
+	}
+	return writeRatings(tx, true, changes)
+}
+
+// saveToDatabase writes the ratings changed since the last save.
+func (r *RatingsManager) saveToDatabase() error {
+	r.mu.Lock()
+	dirty := r.dirty.take()
+	var changes []ratingChange
+	if dirty.all {
+		for uuid := range r.store.Ratings {
+			changes = append(changes, r.ratingChangeLocked(uuid))
+		}
+	} else {
+		for uuid := range dirty.keys {
+			changes = append(changes, r.ratingChangeLocked(uuid))
+		}
+	}
+	r.mu.Unlock()
+
+	if err := r.db.saveRatings(dirty.all, changes); err != nil {
+		r.mu.Lock()
+		r.dirty.restore(dirty)
+		r.mu.Unlock()
+		return fmt.Errorf("failed to save ratings: %w", err)
+	}
+	return nil
+}
+
+// reloadFromDatabase imports station_ratings.json if it changed on disk, as
+// after a restore, and takes in the imported ratings.
+func (r *RatingsManager) reloadFromDatabase() (bool, error) {
+	r.saveMu.Lock()
+	defer r.saveMu.Unlock()
+	if _, err := r.db.importJSON(ratingsFileName); err != nil {
+		return false, err
+	}
+	imports := r.db.importCount(ratingsFileName)
+	if imports == r.dbImports.Load() {
+		return false, nil
+	}
+	store, err := r.db.loadRatingsStore()
+	if err != nil {
+		return false, err
+	}
+	r.dbImports.Store(imports)
+	return r.replaceStore(store), nil
+}
+
+// ratingChangeLocked copies a station's rating for saving. Caller must hold
+// r.mu.
+func (r *RatingsManager) ratingChangeLocked(uuid string) ratingChange {
+	ch := ratingChange{uuid: uuid}
+	if rating, ok := r.store.Ratings[uuid]; ok {
+		ratingCopy := *rating
+		ch.rating = &ratingCopy
+	}
+	if cached, ok := r.store.StationCache[uuid]; ok {
+		cachedCopy := *cached
+		ch.cache = &cachedCopy
+	}
+	return ch
+}
+
+// markDirtyLocked records that a rating changed. Caller must hold r.mu.
+func (r *RatingsManager) markDirtyLocked(uuid string) {
+	r.dirty.mark(uuid)
+	r.savePending.Store(true)
+}
+
+// queryDatabase saves pending changes and runs an indexed query. ok is false
+// when the JSON backend is in use or the query failed.
+func (r *RatingsManager) queryDatabase(order string, minRating, limit int) (result []StationWithRating, ok bool) {
+	if r.db == nil {
+		return nil, false
+	}
+	if r.savePending.CompareAndSwap(true, false) {
+		if err := r.Save(); err != nil {
+			r.savePending.Store(true)
+			return nil, false
+		}
+	}
+	result, err := r.db.queryRatings(order, minRating, limit)
+	if err != nil {
+		return nil, false
+	}
+	if result == nil {
+		result = []StationWithRating{}
+	}
+	return result, true
+}
+
+// ---------------------------------------------------------------------------
+// Tags
+// ---------------------------------------------------------------------------
+
+// tagsChange holds the station tags and playlists to save; nil values delete
+// the station's tags or the playlist.
+type tagsChange struct {
+	stations  map[string]*StationTags
+	playlists map[string]*TagPlaylist
+}
+
+func (d *Database) saveTags(replace bool, ch tagsChange) error {
+	return d.withTx(func(tx *sql.Tx) error {
+		return writeTags(tx, replace, ch)
+	})
+}
+
+func writeTags(tx *sql.Tx, replace bool, ch tagsChange) error {
+	if replace {
+		if _, err := tx.Exec(`DELETE FROM station_tags`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM tag_playlists`); err != nil {
+			return err
+		}
+	}
+	for uuid, st := range ch.stations {
+		if st == nil {
+			if _, err := tx.Exec(`DELETE FROM station_tags WHERE uuid = ?`, uuid); err != nil {
+				return err
+			}
+			continue
+		}
+		tags, err := json.Marshal(st.Tags)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT OR REPLACE INTO station_tags (uuid, tags, created_at, updated_at)
+			VALUES (?, ?, ?, ?)`, uuid, string(tags), dbTime(st.CreatedAt), dbTime(st.UpdatedAt))
+		if err != nil {
+			return err
+		}
+	}
+	for name, p := range ch.playlists {
+		if p == nil {
+			if _, err := tx.Exec(`DELETE FROM tag_playlists WHERE name = ?`, name); err != nil {
+				return err
+			}
+			continue
+		}
+		tags, err := json.Marshal(p.Tags)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT OR REPLACE INTO tag_playlists (name, tags, match_mode, created_at)
+			VALUES (?, ?, ?, ?)`, name, string(tags), p.MatchMode, dbTime(p.CreatedAt))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTagsStore reads every station's tags and all playlists.
+func (d *Database) loadTagsStore() (*TagsStore, error) {
+	store := &TagsStore{
+		StationTags:  make(map[string]*StationTags),
+		AllTags:      []string{},
+		TagPlaylists: make(map[string]*TagPlaylist),
+		Version:      1,
+	}
+
+	rows, err := d.db.Query(`SELECT uuid, tags, created_at, updated_at FROM station_tags`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var uuid, tags string
+		var createdAt, updatedAt int64
+		if err := rows.Scan(&uuid, &tags, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to load tags: %w", err)
+		}
+		st := &StationTags{CreatedAt: fromDBTime(createdAt), UpdatedAt: fromDBTime(updatedAt)}
+		if err := json.Unmarshal([]byte(tags), &st.Tags); err != nil {
+			return nil, fmt.Errorf("failed to load tags for %s: %w", uuid, err)
+		}
+		store.StationTags[uuid] = st
+		for _, tag := range st.Tags {
+			if !slices.Contains(store.AllTags, tag) {
+				store.AllTags = append(store.AllTags, tag)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load tags: %w", err)
+	}
+	slices.Sort(store.AllTags)
+
+	prows, err := d.db.Query(`SELECT name, tags, match_mode, created_at FROM tag_playlists`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tag playlists: %w", err)
+	}
+	defer func() { _ = prows.Close() }()
+	for prows.Next() {
+		var name, tags string
+		var createdAt int64
+		p := &TagPlaylist{}
+		if err := prows.Scan(&name, &tags, &p.MatchMode, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to load tag playlists: %w", err)
+		}
+		if err := json.Unmarshal([]byte(tags), &p.Tags); err != nil {
+			return nil, fmt.Errorf("failed to load tag playlist %s: %w", name, err)
+		}
+		p.CreatedAt = fromDBTime(createdAt)
+		store.TagPlaylists[name] = p
+	}
+	if err := prows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load tag playlists: %w", err)
+	}
+	return store, nil
+}
+
+func importTagsJSON(data []byte, tx *sql.Tx) error {
+	var store TagsStore
+	if err := json.Unmarshal(data, &store); err != nil {
+		return err
+	}
+	ch := tagsChange{stations: make(map[string]*StationTags), playlists: make(map[string]*TagPlaylist)}
+	for uuid, st := range store.StationTags {
+		if st != nil && len(st.Tags) > 0 {
+			ch.stations[uuid] = st
+		}
+	}
+	for name, p := range store.TagPlaylists {
+		if p != nil {
+			ch.playlists[name] = p
+		}
+	}
+	return writeTags(tx, true, ch)
+}
+
+// saveToDatabase writes the station tags and playlists changed since the
+// last save.
+func (t *TagsManager) saveToDatabase() error {
+	t.mu.Lock()
+	dirty := t.dirty.take()
+	dirtyPlaylists := t.dirtyPlaylists.take()
+	replace := dirty.all || dirtyPlaylists.all
+	ch := tagsChange{stations: make(map[string]*StationTags), playlists: make(map[string]*TagPlaylist)}
+	if replace {
+		for uuid := range t.store.StationTags {
+			dirty.mark(uuid)
+		}
+		for name := range t.store.TagPlaylists {
+			dirtyPlaylists.mark(name)
+		}
+	}
+	for uuid := range dirty.keys {
+		var stCopy *StationTags
+		if st, ok := t.store.StationTags[uuid]; ok {
+			stCopy = &StationTags{Tags: slices.Clone(st.Tags), CreatedAt: st.CreatedAt, UpdatedAt: st.UpdatedAt}
+		}
+		ch.stations[uuid] = stCopy
+	}
+	for name := range dirtyPlaylists.keys {
+		var pCopy *TagPlaylist
+		if p, ok := t.store.TagPlaylists[name]; ok {
+			pCopy = &TagPlaylist{Tags: slices.Clone(p.Tags), MatchMode: p.MatchMode, CreatedAt: p.CreatedAt}
+		}
+		ch.playlists[name] = pCopy
+	}
+	t.mu.Unlock()
+
+	if err := t.db.saveTags(replace, ch); err != nil {
+		t.mu.Lock()
+		t.dirty.restore(dirty)
+		t.dirtyPlaylists.restore(dirtyPlaylists)
+		t.mu.Unlock()
+		return fmt.Errorf("failed to save tags: %w", err)
+	}
+	return nil
+}
+
+// reloadFromDatabase imports station_tags.json if it changed on disk, as
+// after a restore, and takes in the imported tags and playlists.
+func (t *TagsManager) reloadFromDatabase() (bool, error) {
+	t.saveMu.Lock()
+	defer t.saveMu.Unlock()
+	if _, err := t.db.importJSON(tagsFileName); err != nil {
+		return false, err
+	}
+	imports := t.db.importCount(tagsFileName)
+	if imports == t.dbImports.Load() {
+		return false, nil
+	}
+	store, err := t.db.loadTagsStore()
+	if err != nil {
+		return false, err
+	}
+	t.dbImports.Store(imports)
+	return t.replaceStore(store), nil
+}
+
+// markDirtyLocked records that a station's tags changed. Caller must hold
+// t.mu.
+func (t *TagsManager) markDirtyLocked(uuid string) {
+	t.dirty.mark(uuid)
+	t.savePending.Store(true)
+}
+
+// markPlaylistDirtyLocked records that a playlist changed. Caller must hold
+// t.mu.
+func (t *TagsManager) markPlaylistDirtyLocked(name string) {
+	t.dirtyPlaylists.mark(name)
+	t.savePending.Store(true)
+}
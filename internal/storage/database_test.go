@@ -0,0 +1,498 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// openTestDatabase opens the database in dataPath and makes it active for
+// the rest of the test.
+func openTestDatabase(t *testing.T, dataPath string) *Database {
+	t.Helper()
+	db, err := OpenDatabase(dataPath)
+	if err != nil {
+		t.Fatalf("OpenDatabase: %v", err)
+	}
+	UseDatabase(db)
+	t.Cleanup(func() { UseDatabase(nil) })
+	return db
+}
+
+// writeJSONStores fills dataPath's JSON files through the JSON backend.
+func writeJSONStores(t *testing.T, dataPath string) {
+	t.Helper()
+	meta, _ := NewMetadataManager(dataPath)
+	for _, uuid := range []string{"a", "b", "b", "c", "c", "c"} {
+		_ = meta.StartPlay(testStation(uuid))
+		_ = meta.StopPlay(uuid)
+	}
+	meta.AddBytes("c", 4096)
+	if err := meta.Close(); err != nil {
+		t.Fatalf("metadata Close: %v", err)
+	}
+
+	ratings, _ := NewRatingsManager(dataPath)
+	_ = ratings.SetRating(testStation("a"), 5)
+	_ = ratings.SetRating(testStation("b"), 3)
+	if err := ratings.Close(); err != nil {
+		t.Fatalf("ratings Close: %v", err)
+	}
+
+	tags, _ := NewTagsManager(dataPath)
+	_ = tags.SetTags("a", []string{"jazz", "chill"})
+	_ = tags.CreatePlaylist("Evening", []string{"chill"}, "any")
+	_ = tags.Close()
+}
+
+func TestDatabase_ImportsJSONOnFirstOpen(t *testing.T) {
+	dataPath := t.TempDir()
+	writeJSONStores(t, dataPath)
+
+	db := openTestDatabase(t, dataPath)
+	defer func() { _ = db.Close() }()
+
+	meta, _ := NewMetadataManager(dataPath)
+	defer func() { _ = meta.Close() }()
+	top := meta.GetTopPlayed(2)
+	if len(top) != 2 || top[0].Station.StationUUID != "c" || top[0].Metadata.PlayCount != 3 || top[1].Station.StationUUID != "b" {
+		t.Fatalf("unexpected top played: %+v", top)
+	}
+	if top[0].Station.Name != "Test Station c" {
+		t.Errorf("expected the cached name, got %q", top[0].Station.Name)
+	}
+	if got := meta.GetMetadata("c").TotalBytes; got != 4096 {
+		t.Errorf("TotalBytes = %d, want 4096", got)
+	}
+
+	ratings, _ := NewRatingsManager(dataPath)
+	defer func() { _ = ratings.Close() }()
+	if got := ratings.GetByMinRating(4); len(got) != 1 || got[0].Station.StationUUID != "a" {
+		t.Errorf("unexpected ratings >= 4: %+v", got)
+	}
+
+	tags, _ := NewTagsManager(dataPath)
+	defer func() { _ = tags.Close() }()
+	if got := tags.GetAllTags(); len(got) != 2 || got[0] != "chill" || got[1] != "jazz" {
+		t.Errorf("unexpected tags: %v", got)
+	}
+	if p := tags.GetPlaylist("Evening"); p == nil || p.MatchMode != "any" {
+		t.Errorf("expected the playlist to be imported, got %+v", p)
+	}
+}
+
+func TestDatabase_SavesChangedRows(t *testing.T) {
+	dataPath := t.TempDir()
+	db := openTestDatabase(t, dataPath)
+
+	meta, _ := NewMetadataManager(dataPath)
+	_ = meta.StartPlay(testStation("x"))
+	_ = meta.StartPlay(testStation("y"))
+	_ = meta.StartPlay(testStation("x"))
+
+	// A query sees changes that the save loop has not written yet
+	recent := meta.GetRecentlyPlayed(0)
+	if len(recent) != 2 || recent[0].Station.StationUUID != "x" || recent[0].Metadata.PlayCount != 2 {
+		t.Fatalf("unexpected recently played: %+v", recent)
+	}
+	if err := meta.Close(); err != nil {
+		t.Fatalf("metadata Close: %v", err)
+	}
+
+	ratings, _ := NewRatingsManager(dataPath)
+	_ = ratings.SetRating(testStation("x"), 4)
+	_ = ratings.SetRating(testStation("y"), 2)
+	_ = ratings.RemoveRating("y")
+	if err := ratings.Close(); err != nil {
+		t.Fatalf("ratings Close: %v", err)
+	}
+
+	tags, _ := NewTagsManager(dataPath)
+	_ = tags.AddTag("x", "news")
+	_ = tags.CreatePlaylist("Old", []string{"news"}, "all")
+	_ = tags.UpdatePlaylist("Old", "New", []string{"news"}, "any")
+	_ = tags.Close()
+
+	if err := db.Close(); err != nil {
+		t.Fatalf("database Close: %v", err)
+	}
+
+	// Reopen everything from the database alone
+	if err := os.Remove(filepath.Join(dataPath, metadataFileName)); err != nil {
+		t.Fatal(err)
+	}
+	db = openTestDatabase(t, dataPath)
+	defer func() { _ = db.Close() }()
+
+	meta, _ = NewMetadataManager(dataPath)
+	defer func() { _ = meta.Close() }()
+	if m := meta.GetMetadata("x"); m == nil || m.PlayCount != 2 || m.FirstPlayed.IsZero() {
+		t.Errorf("unexpected metadata for x: %+v", m)
+	}
+
+	ratings, _ = NewRatingsManager(dataPath)
+	defer func() { _ = ratings.Close() }()
+	if top := ratings.GetTopRated(0); len(top) != 1 || top[0].Rating.Rating != 4 {
+		t.Errorf("unexpected top rated: %+v", top)
+	}
+
+	tags, _ = NewTagsManager(dataPath)
+	defer func() { _ = tags.Close() }()
+	if got := tags.GetTags("x"); len(got) != 1 || got[0] != "news" {
+		t.Errorf("unexpected tags for x: %v", got)
+	}
+	if tags.GetPlaylist("Old") != nil || tags.GetPlaylist("New") == nil {
+		t.Errorf("expected the renamed playlist, got %v", tags.GetAllPlaylists())
+	}
+
+	if err := meta.ClearAll(); err != nil {
+		t.Fatalf("ClearAll: %v", err)
+	}
+	if got := meta.GetTopPlayed(0); len(got) != 0 {
+		t.Errorf("expected no stations after ClearAll, got %+v", got)
+	}
+}
+
+func TestDatabase_ExportJSON(t *testing.T) {
+	dataPath := t.TempDir()
+	db := openTestDatabase(t, dataPath)
+	defer func() { _ = db.Close() }()
+
+	ratings, _ := NewRatingsManager(dataPath)
+	_ = ratings.SetRating(testStation("z"), 5)
+	_ = ratings.Close()
+
+	if err := db.ExportJSON(); err != nil {
+		t.Fatalf("ExportJSON: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dataPath, ratingsFileName))
+	if err != nil {
+		t.Fatalf("expected an exported ratings file: %v", err)
+	}
+	var store RatingsStore
+	if err := json.Unmarshal(data, &store); err != nil {
+		t.Fatalf("export is not a ratings store: %v", err)
+	}
+	if store.Ratings["z"] == nil || store.Ratings["z"].Rating != 5 || store.StationCache["z"].Name != "Test Station z" {
+		t.Errorf("unexpected export: %s", data)
+	}
+
+	// The export does not count as an outside change
+	info, _ := os.Stat(filepath.Join(dataPath, ratingsFileName))
+	if db.syncedModTime(ratingsFileName) != info.ModTime().UnixNano() {
+		t.Error("expected the export's modification time to be recorded")
+	}
+}
+
+func TestDatabase_ReimportsChangedJSON(t *testing.T) {
+	dataPath := t.TempDir()
+	db := openTestDatabase(t, dataPath)
+	ratings, _ := NewRatingsManager(dataPath)
+	_ = ratings.SetRating(testStation("old"), 2)
+	_ = ratings.Close()
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restored backup replaces the JSON file
+	restored := RatingsStore{
+		Ratings: map[string]*StationRating{"new": {Rating: 4, RatedAt: time.Now(), UpdatedAt: time.Now()}},
+		Version: 1,
+	}
+	data, _ := json.Marshal(restored)
+	path := filepath.Join(dataPath, ratingsFileName)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	_ = os.Chtimes(path, future, future)
+
+	db = openTestDatabase(t, dataPath)
+	defer func() { _ = db.Close() }()
+	ratings, _ = NewRatingsManager(dataPath)
+	defer func() { _ = ratings.Close() }()
+	if ratings.GetRating("old") != nil || ratings.GetRating("new") == nil {
+		t.Errorf("expected the restored ratings, got %+v", ratings.GetAllRated())
+	}
+}
+
+func TestDatabase_RestoreWhileOpen(t *testing.T) {
+	configDir := t.TempDir()
+	dataPath := filepath.Join(configDir, "data")
+	db := openTestDatabase(t, dataPath)
+	ratings, _ := NewRatingsManager(dataPath)
+	_ = ratings.SetRating(testStation("old"), 2)
+	if err := ratings.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A backup taken elsewhere, with the JSON backend
+	srcDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(srcDir, "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	backup := RatingsStore{
+		Ratings: map[string]*StationRating{"new": {Rating: 4, RatedAt: time.Now(), UpdatedAt: time.Now()}},
+		Version: 1,
+	}
+	data, _ := json.Marshal(backup)
+	if err := os.WriteFile(filepath.Join(srcDir, "data", ratingsFileName), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	zipPath := filepath.Join(t.TempDir(), "backup.zip")
+	if err := (&BackupManager{configDir: srcDir}).Export(zipPath, SyncPrefs{RatingsVotes: true}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	if err := (&BackupManager{configDir: configDir}).Restore(zipPath, SyncPrefs{RatingsVotes: true}, true); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if changed, err := ratings.Reload(); err != nil || !changed {
+		t.Fatalf("Reload = %v, %v; expected the restored ratings", changed, err)
+	}
+	if ratings.GetRating("old") != nil || ratings.GetRating("new") == nil {
+		t.Errorf("expected the restored ratings, got %+v", ratings.GetAllRated())
+	}
+	if changed, _ := ratings.Reload(); changed {
+		t.Error("a second Reload should find nothing new")
+	}
+
+	_ = ratings.Close()
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	var store RatingsStore
+	data, _ = os.ReadFile(filepath.Join(dataPath, ratingsFileName))
+	if err := json.Unmarshal(data, &store); err != nil {
+		t.Fatal(err)
+	}
+	if store.Ratings["old"] != nil || store.Ratings["new"] == nil {
+		t.Errorf("closing the database undid the restore: %s", data)
+	}
+}
+
+func TestDatabase_ExportKeepsNewerFiles(t *testing.T) {
+	dataPath := t.TempDir()
+	db := openTestDatabase(t, dataPath)
+	ratings, _ := NewRatingsManager(dataPath)
+	_ = ratings.SetRating(testStation("old"), 2)
+	_ = ratings.Close()
+	if err := db.ExportJSON(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Written behind the database's back and not imported yet
+	path := filepath.Join(dataPath, ratingsFileName)
+	newer := `{"ratings":{"new":{"rating":4}},"version":1}`
+	if err := os.WriteFile(path, []byte(newer), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	_ = os.Chtimes(path, future, future)
+
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != newer {
+		t.Errorf("export overwrote a newer file: %s", data)
+	}
+}
+
+func TestDatabase_Favorites(t *testing.T) {
+	dataPath := t.TempDir()
+	favPath := filepath.Join(dataPath, favoritesDirName)
+	ctx := context.Background()
+	db := openTestDatabase(t, dataPath)
+
+	store := NewStorage(favPath)
+	for _, name := range []string{"Rock", "Jazz/Smooth", "Old"} {
+		if err := store.CreateList(ctx, name); err != nil {
+			t.Fatalf("CreateList(%q): %v", name, err)
+		}
+	}
+	if err := store.AddStation(ctx, "Jazz/Smooth", *testStation("s1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RenameList(ctx, "Rock", "Pop"); err != nil {
+		t.Fatal(err)
+	}
+	if moved, err := store.MoveList(ctx, "Pop", -1); err != nil || !moved {
+		t.Fatalf("MoveList = %v, %v", moved, err)
+	}
+	if _, err := os.Stat(filepath.Join(favPath, "Jazz", "Smooth.json")); !os.IsNotExist(err) {
+		t.Errorf("expected the list in the database only, stat: %v", err)
+	}
+
+	list, err := NewStorage(favPath).LoadList(ctx, "Jazz/Smooth")
+	if err != nil || len(list.Stations) != 1 || list.Stations[0].StationUUID != "s1" {
+		t.Fatalf("LoadList = %+v, %v", list, err)
+	}
+	if lists, _ := store.GetAllLists(ctx); strings.Join(lists, ",") != "Jazz/Smooth,Pop,Old" {
+		t.Errorf("unexpected lists: %v", lists)
+	}
+
+	if err := db.ExportJSON(); err != nil {
+		t.Fatalf("ExportJSON: %v", err)
+	}
+	for _, rel := range []string{"Pop.json", "Old.json", "Jazz/Smooth.json", SystemFileListOrder} {
+		if _, err := os.Stat(filepath.Join(favPath, filepath.FromSlash(rel))); err != nil {
+			t.Errorf("expected %s in the export: %v", rel, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(favPath, "Rock.json")); !os.IsNotExist(err) {
+		t.Errorf("renamed list left in the export, stat: %v", err)
+	}
+
+	if err := store.DeleteList(ctx, "Jazz/Smooth"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(favPath, "Jazz")); !os.IsNotExist(err) {
+		t.Errorf("deleted list's folder left in the export, stat: %v", err)
+	}
+
+	// Read back from the files with the JSON backend
+	UseDatabase(nil)
+	if lists, _ := NewStorage(favPath).GetAllLists(ctx); len(lists) != 2 || lists[0] != "Pop" || lists[1] != "Old" {
+		t.Errorf("unexpected exported lists: %v", lists)
+	}
+}
+
+func TestDatabase_ImportsFavoritesOnFirstOpen(t *testing.T) {
+	dataPath := t.TempDir()
+	favPath := filepath.Join(dataPath, favoritesDirName)
+	ctx := context.Background()
+
+	files := NewStorage(favPath)
+	for _, name := range []string{"B", "A", "Jazz/Smooth"} {
+		if err := files.CreateList(ctx, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = files.AddStation(ctx, "Jazz/Smooth", *testStation("s1"))
+	if _, err := files.MoveList(ctx, "B", -1); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := files.GetAllLists(ctx)
+
+	db := openTestDatabase(t, dataPath)
+	defer func() { _ = db.Close() }()
+	if _, err := db.loadFavoriteList("Jazz/Smooth"); err != nil {
+		t.Fatalf("expected the list in the database: %v", err)
+	}
+
+	store := NewStorage(favPath)
+	if got, _ := store.GetAllLists(ctx); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("GetAllLists = %v, want %v", got, want)
+	}
+	if ok, _ := store.StationExists(ctx, "Jazz/Smooth", "s1"); !ok {
+		t.Error("expected the imported station")
+	}
+	if changed, err := store.Reload(); err != nil || changed {
+		t.Errorf("Reload = %v, %v; expected nothing new", changed, err)
+	}
+}
+
+func TestDatabase_ReloadsChangedFavorites(t *testing.T) {
+	dataPath := t.TempDir()
+	favPath := filepath.Join(dataPath, favoritesDirName)
+	ctx := context.Background()
+	db := openTestDatabase(t, dataPath)
+	defer func() { _ = db.Close() }()
+
+	store := NewStorage(favPath)
+	if err := store.CreateList(ctx, "Mine"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.ExportJSON(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process, or a restore, replaces the lists
+	if err := os.Remove(filepath.Join(favPath, "Mine.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(favPath, "Theirs.json"), []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Not imported yet, so exporting leaves the newer directory alone
+	if err := db.ExportJSON(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(favPath, "Mine.json")); !os.IsNotExist(err) {
+		t.Errorf("export overwrote a newer favorites directory, stat: %v", err)
+	}
+
+	if changed, err := store.Reload(); err != nil || !changed {
+		t.Fatalf("Reload = %v, %v; expected the new lists", changed, err)
+	}
+	if lists, _ := store.GetAllLists(ctx); len(lists) != 1 || lists[0] != "Theirs" {
+		t.Errorf("unexpected lists after Reload: %v", lists)
+	}
+}
+
+func TestDatabase_ReloadKeepsUnexportedFavorites(t *testing.T) {
+	dataPath := t.TempDir()
+	favPath := filepath.Join(dataPath, favoritesDirName)
+	ctx := context.Background()
+	db := openTestDatabase(t, dataPath)
+
+	store := NewStorage(favPath)
+	if err := store.CreateList(ctx, "A"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.ExportJSON(); err != nil {
+		t.Fatal(err)
+	}
+	// Saved to the database only; A.json still holds no stations
+	if err := store.AddStation(ctx, "A", *testStation("x")); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unrelated list copied in, or restored from a Gist
+	if err := os.WriteFile(filepath.Join(favPath, "B.json"), []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := store.Reload(); err != nil || !changed {
+		t.Fatalf("Reload = %v, %v; expected the new list", changed, err)
+	}
+	if ok, _ := store.StationExists(ctx, "A", "x"); !ok {
+		t.Error("Reload dropped a station saved only to the database")
+	}
+	if lists, _ := store.GetAllLists(ctx); strings.Join(lists, ",") != "A,B" {
+		t.Errorf("unexpected lists after Reload: %v", lists)
+	}
+
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	UseDatabase(nil)
+	if ok, _ := NewStorage(favPath).StationExists(ctx, "A", "x"); !ok {
+		t.Error("expected the station in the exported A.json")
+	}
+}
+
+func TestDatabase_StorageExport(t *testing.T) {
+	dataPath := t.TempDir()
+	favPath := filepath.Join(dataPath, favoritesDirName)
+	db := openTestDatabase(t, dataPath)
+	defer func() { _ = db.Close() }()
+
+	store := NewStorage(favPath)
+	if err := store.CreateList(context.Background(), "Jazz/Smooth"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Export(); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(favPath, "Jazz", "Smooth.json")); err != nil {
+		t.Errorf("expected the list file after Export: %v", err)
+	}
+}
@@ -3,8 +3,8 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
-	"path/filepath"
 	"sync"
 
 	"github.com/shinokada/tera/v3/internal/api"
@@ -13,21 +13,25 @@ import (
 // System files that should not be treated as favorite lists
 const (
 	SystemFileSearchHistory = "search-history.json"
+	// SystemFileListOrder keeps the order of the lists in each folder
+	SystemFileListOrder = ".order.json"
 )
 
 type Storage struct {
 	favoritePath string
+	db           *Database  // nil when the lists are files in favoritePath
 	mu           sync.Mutex // Protects concurrent access to favorites operations
 }
 
 func NewStorage(favoritePath string) *Storage {
-	return &Storage{favoritePath: favoritePath}
+	return &Storage{favoritePath: favoritePath, db: databaseForFavorites(favoritePath)}
 }
 
 func (s *Storage) LoadList(ctx context.Context, name string) (*FavoritesList, error) {
-	path := filepath.Join(s.favoritePath, name+".json")
-
-	data, err := os.ReadFile(path)
+	if err := ValidateListName(name); err != nil {
+		return nil, err
+	}
+	data, err := s.readList(name)
 	if err != nil {
 		return nil, err
 	}
@@ -40,27 +44,32 @@ func (s *Storage) LoadList(ctx context.Context, name string) (*FavoritesList, er
 	return &FavoritesList{
 		Name:     name,
 		Stations: stations,
+		Ordered:  s.isOrdered(name),
 	}, nil
 }
 
-// SaveList saves a favorites list to disk
+// SaveList saves a favorites list to disk, creating its folder if needed
 func (s *Storage) SaveList(ctx context.Context, list *FavoritesList) error {
-	path := filepath.Join(s.favoritePath, list.Name+".json")
-
+	if err := ValidateListName(list.Name); err != nil {
+		return err
+	}
 	data, err := json.MarshalIndent(list.Stations, "", "  ")
 	if err != nil {
 		return err
 	}
-
-	return os.WriteFile(path, data, 0644)
+	return s.writeList(list.Name, data)
 }
 
 // AddStation adds a station to a list, checking for duplicates by UUID
 func (s *Storage) AddStation(ctx context.Context, listName string, station api.Station) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// Load existing list
+	var before []api.Station
 	list, err := s.LoadList(ctx, listName)
 	if err != nil {
 		// If list doesn't exist, create new one
@@ -72,6 +81,8 @@ func (s *Storage) AddStation(ctx context.Context, listName string, station api.S
 		} else {
 			return err
 		}
+	} else {
+		before = list.Stations
 	}
 
 	// Check for duplicates
@@ -85,34 +96,69 @@ func (s *Storage) AddStation(ctx context.Context, listName string, station api.S
 	list.Stations = append(list.Stations, station)
 
 	// Save
-	return s.SaveList(ctx, list)
+	if err := s.SaveList(ctx, list); err != nil {
+		return err
+	}
+	s.recordList(fmt.Sprintf("Added %s to %s", station.TrimName(), listName), listName, before, list.Stations)
+	return nil
 }
 
-// GetAllLists returns a list of all favorite list names
-func (s *Storage) GetAllLists(ctx context.Context) ([]string, error) {
-	entries, err := os.ReadDir(s.favoritePath)
+// AddStations adds stations to a list, creating it if needed. Stations
+// already in the list, by UUID or by stream URL, are skipped. It returns how
+// many stations were added.
+func (s *Storage) AddStations(ctx context.Context, listName string, stations []api.Station) (int, error) {
+	unlock, err := s.lock()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
+	defer unlock()
 
-	var lists []string
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
+	var before []api.Station
+	list, err := s.LoadList(ctx, listName)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return 0, err
 		}
-		name := entry.Name()
-		if filepath.Ext(name) == ".json" {
-			// Skip system files that are not favorite lists
-			if name == SystemFileSearchHistory {
-				continue
-			}
-			// Remove .json extension
-			listName := name[:len(name)-5]
-			lists = append(lists, listName)
+		list = &FavoritesList{
+			Name:     listName,
+			Stations: []api.Station{},
+		}
+	} else {
+		before = list.Stations
+	}
+
+	uuids := make(map[string]bool, len(list.Stations))
+	urls := make(map[string]bool, len(list.Stations))
+	for _, existing := range list.Stations {
+		uuids[existing.StationUUID] = true
+		if existing.URLResolved != "" {
+			urls[existing.URLResolved] = true
 		}
 	}
 
-	return lists, nil
+	added := 0
+	for _, station := range stations {
+		if uuids[station.StationUUID] || (station.URLResolved != "" && urls[station.URLResolved]) {
+			continue
+		}
+		uuids[station.StationUUID] = true
+		urls[station.URLResolved] = true
+		list.Stations = append(list.Stations, station)
+		added++
+	}
+
+	if err := s.SaveList(ctx, list); err != nil {
+		return added, err
+	}
+	s.recordList(fmt.Sprintf("Added %d stations to %s", added, listName), listName, before, list.Stations)
+	return added, nil
+}
+
+// GetAllLists returns the names of all favorite lists. Lists in folders
+// are named by their path, as in "Jazz/Smooth", and follow their folder's
+// position; each folder keeps its manual order, then sorts by name.
+func (s *Storage) GetAllLists(ctx context.Context) ([]string, error) {
+	return s.allLists("", nil)
 }
 
 // StationExists checks if a station exists in a list
@@ -136,8 +182,11 @@ func (s *Storage) StationExists(ctx context.Context, listName string, stationUUI
 
 // RemoveStation removes a station from a list by UUID
 func (s *Storage) RemoveStation(ctx context.Context, listName string, stationUUID string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// Load existing list
 	list, err := s.LoadList(ctx, listName)
@@ -146,23 +195,89 @@ func (s *Storage) RemoveStation(ctx context.Context, listName string, stationUUI
 	}
 
 	// Find and remove the station
-	found := false
+	var removed *api.Station
 	newStations := make([]api.Station, 0, len(list.Stations))
-	for _, station := range list.Stations {
+	for i, station := range list.Stations {
 		if station.StationUUID != stationUUID {
 			newStations = append(newStations, station)
 		} else {
-			found = true
+			removed = &list.Stations[i]
 		}
 	}
 
-	if !found {
+	if removed == nil {
 		return ErrStationNotFound
 	}
 
 	// Update list
+	before := list.Stations
 	list.Stations = newStations
 
 	// Save
-	return s.SaveList(ctx, list)
+	if err := s.SaveList(ctx, list); err != nil {
+		return err
+	}
+	s.recordList(fmt.Sprintf("Removed %s from %s", removed.TrimName(), listName), listName, before, newStations)
+	return nil
+}
+
+// recordList journals a change to the stations of a list. A nil slice
+// stands for a list that does not exist.
+func (s *Storage) recordList(description, name string, before, after []api.Station) {
+	RecordChange(description, NewJournalChange(JournalFavorites, name, before, after))
+}
+
+// ApplyJournal writes a list back for undo and redo: value holds its
+// stations, or null to delete it.
+func (s *Storage) ApplyJournal(key string, value json.RawMessage) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if isNullValue(value) {
+		return s.removeList(key)
+	}
+	stations := []api.Station{}
+	if err := json.Unmarshal(value, &stations); err != nil {
+		return err
+	}
+	return s.SaveList(context.Background(), &FavoritesList{Name: key, Stations: stations})
+}
+
+// Reload takes in lists that another process or a restore wrote to the
+// favorites directory while the database holds the favorites, and reports
+// whether there were any. Lists kept as files are always read from disk,
+// so there is nothing to take in.
+func (s *Storage) Reload() (bool, error) {
+	if s.db == nil {
+		return false, nil
+	}
+	return s.db.importFavorites()
+}
+
+// Export writes the lists the database holds to the favorites directory,
+// as a backup does before copying them, so the files can be read as they
+// are. Lists kept as files are already there.
+func (s *Storage) Export() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.exportFavorites()
+}
+
+// lock takes s.mu and the cross-process lock on the favorites, which another
+// TERA may be changing too. The returned function releases both.
+func (s *Storage) lock() (func(), error) {
+	s.mu.Lock()
+	lock, err := LockFile(s.favoritePath)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	return func() {
+		lock.Unlock()
+		s.mu.Unlock()
+	}, nil
 }
@@ -157,3 +157,35 @@ func TestStorage_StationExists(t *testing.T) {
 		t.Error("Expected station to not exist")
 	}
 }
+
+func TestStorage_AddStations(t *testing.T) {
+	store := NewStorage(filepath.Join(t.TempDir(), "favorites"))
+	ctx := context.Background()
+
+	first := []api.Station{
+		{StationUUID: "a", Name: "A", URLResolved: "http://a.example/stream"},
+		{StationUUID: "b", Name: "B", URLResolved: "http://b.example/stream"},
+	}
+	added, err := store.AddStations(ctx, "Imported", first)
+	if err != nil || added != 2 {
+		t.Fatalf("AddStations = %d, %v", added, err)
+	}
+
+	// Same UUID, and a different UUID with a stream already in the list
+	more := []api.Station{
+		{StationUUID: "a", Name: "A again"},
+		{StationUUID: "custom-1", Name: "B copy", URLResolved: "http://b.example/stream"},
+		{StationUUID: "c", Name: "C", URLResolved: "http://c.example/stream"},
+	}
+	added, err = store.AddStations(ctx, "Imported", more)
+	if err != nil || added != 1 {
+		t.Fatalf("expected only the new station to be added, got %d, %v", added, err)
+	}
+	list, err := store.LoadList(ctx, "Imported")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Stations) != 3 || list.Stations[2].StationUUID != "c" {
+		t.Errorf("unexpected list: %+v", list.Stations)
+	}
+}
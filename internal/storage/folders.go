@@ -0,0 +1,529 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+// ErrInvalidListName is returned for list names that are empty or contain
+// empty or hidden path segments, which could leave the favorites directory,
+// and for names whose file would be one of the system files.
+var ErrInvalidListName = errors.New("invalid list name")
+
+// reservedListNames are the names of the lists whose files would be the
+// system files in the favorites directory.
+var reservedListNames = []string{
+	strings.TrimSuffix(SystemFileSearchHistory, ".json"),
+	strings.TrimSuffix(SystemFileListOrder, ".json"),
+}
+
+// listOrder is the content of a folder's SystemFileListOrder file.
+type listOrder struct {
+	// Lists and subfolders in display order. Folders end in "/". Entries
+	// not listed follow in name order.
+	Lists []string `json:"lists,omitempty"`
+	// Ordered names the lists whose stations were put in order by hand;
+	// the others are shown sorted by name.
+	Ordered []string `json:"ordered,omitempty"`
+}
+
+// ValidateListName checks a list name. Names may contain "/" to put the
+// list in a folder, as in "Jazz/Smooth". Reserved names are rejected in any
+// case, as the file system may not tell them apart.
+func ValidateListName(name string) error {
+	if name == "" || strings.ContainsAny(name, "\\\x00") {
+		return fmt.Errorf("%w %q", ErrInvalidListName, name)
+	}
+	for _, reserved := range reservedListNames {
+		if strings.EqualFold(name, reserved) {
+			return fmt.Errorf("%w %q: reserved name", ErrInvalidListName, name)
+		}
+	}
+	for _, seg := range strings.Split(name, "/") {
+		if seg == "" || strings.HasPrefix(seg, ".") {
+			return fmt.Errorf("%w %q", ErrInvalidListName, name)
+		}
+	}
+	return nil
+}
+
+// listPath returns the file of the list name.
+func (s *Storage) listPath(name string) string {
+	return filepath.Join(s.favoritePath, filepath.FromSlash(name)+".json")
+}
+
+// readList returns the content of the list name's file. A missing list is an
+// error os.IsNotExist recognizes.
+func (s *Storage) readList(name string) ([]byte, error) {
+	if s.db != nil {
+		return s.db.loadFavoriteList(name)
+	}
+	return os.ReadFile(s.listPath(name))
+}
+
+// writeList replaces the content of the list name's file, creating its
+// folders if needed.
+func (s *Storage) writeList(name string, data []byte) error {
+	if s.db != nil {
+		return s.db.saveFavoriteList(name, data)
+	}
+	p := s.listPath(name)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	if err := atomicWriteFile(p, data, 0644); err != nil {
+		return err
+	}
+	noteOwnWrite(p)
+	return nil
+}
+
+// listExists reports whether the list name exists.
+func (s *Storage) listExists(name string) bool {
+	if s.db != nil {
+		return s.db.favoriteListExists(name)
+	}
+	_, err := os.Stat(s.listPath(name))
+	return err == nil
+}
+
+// deleteListFile deletes the list name, leaving its folder's order alone.
+func (s *Storage) deleteListFile(name string) error {
+	if s.db != nil {
+		return s.db.deleteFavoriteList(name)
+	}
+	if err := os.Remove(s.listPath(name)); err != nil {
+		return err
+	}
+	noteOwnWrite(s.listPath(name))
+	return nil
+}
+
+// renameListFile moves the list oldName to newName, creating its folders if
+// needed.
+func (s *Storage) renameListFile(oldName, newName string) error {
+	if s.db != nil {
+		return s.db.renameFavoriteList(oldName, newName)
+	}
+	newPath := s.listPath(newName)
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(s.listPath(oldName), newPath); err != nil {
+		return err
+	}
+	noteOwnWrite(s.listPath(oldName))
+	noteOwnWrite(newPath)
+	return nil
+}
+
+// splitListName splits "Jazz/Smooth" into the folder "Jazz" and "Smooth".
+func splitListName(name string) (folder, base string) {
+	folder, base = path.Split(name)
+	return strings.TrimSuffix(folder, "/"), base
+}
+
+func (s *Storage) orderPath(folder string) string {
+	return filepath.Join(s.favoritePath, filepath.FromSlash(folder), SystemFileListOrder)
+}
+
+func (s *Storage) loadOrder(folder string) listOrder {
+	if s.db != nil {
+		return s.db.loadListOrder(folder)
+	}
+	var o listOrder
+	if data, err := os.ReadFile(s.orderPath(folder)); err == nil {
+		_ = json.Unmarshal(data, &o)
+	}
+	return o
+}
+
+func (s *Storage) saveOrder(folder string, o listOrder) error {
+	data, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return err
+	}
+	if s.db != nil {
+		return s.db.saveListOrder(folder, data)
+	}
+	if err := atomicWriteFile(s.orderPath(folder), data, 0644); err != nil {
+		return err
+	}
+	noteOwnWrite(s.orderPath(folder))
+	return nil
+}
+
+// isOrdered reports whether the stations of the list name are in manual
+// order.
+func (s *Storage) isOrdered(name string) bool {
+	folder, base := splitListName(name)
+	return contains(s.loadOrder(folder).Ordered, base)
+}
+
+// children returns the lists and subfolders ("Name/") of folder in display
+// order.
+func (s *Storage) children(folder string) ([]string, error) {
+	var keys []string
+	var err error
+	if s.db != nil {
+		keys, err = s.db.favoriteChildren(folder)
+	} else {
+		keys, err = s.childFiles(folder)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rank := make(map[string]int)
+	for i, key := range s.loadOrder(folder).Lists {
+		rank[key] = i + 1
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		ri, rj := rank[keys[i]], rank[keys[j]]
+		switch {
+		case ri > 0 && rj > 0:
+			return ri < rj
+		case ri > 0 || rj > 0:
+			return ri > 0
+		}
+		return false // already sorted by name
+	})
+	return keys, nil
+}
+
+// childFiles returns the lists and subfolders of folder in the favorites
+// directory, sorted by name.
+func (s *Storage) childFiles(folder string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.favoritePath, filepath.FromSlash(folder)))
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, entry := range entries {
+		name := entry.Name()
+		// Hidden entries are TERA's own, such as .backup and .order.json
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		if entry.IsDir() {
+			keys = append(keys, name+"/")
+			continue
+		}
+		if filepath.Ext(name) != ".json" || (folder == "" && name == SystemFileSearchHistory) {
+			continue
+		}
+		keys = append(keys, strings.TrimSuffix(name, ".json"))
+	}
+	return keys, nil
+}
+
+// allLists appends the lists under folder, depth first, to lists.
+func (s *Storage) allLists(folder string, lists []string) ([]string, error) {
+	keys, err := s.children(folder)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		name := path.Join(folder, strings.TrimSuffix(key, "/"))
+		if strings.HasSuffix(key, "/") {
+			if lists, err = s.allLists(name, lists); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		lists = append(lists, name)
+	}
+	return lists, nil
+}
+
+// CreateList creates an empty list, and its folders if needed.
+func (s *Storage) CreateList(ctx context.Context, name string) error {
+	if err := ValidateListName(name); err != nil {
+		return err
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if s.listExists(name) {
+		return fmt.Errorf("list %q already exists", name)
+	}
+	if err := s.writeList(name, []byte("[]")); err != nil {
+		return err
+	}
+	s.recordList("Created list "+name, name, nil, []api.Station{})
+	return nil
+}
+
+// DeleteList deletes a list. Folders left without lists are removed.
+func (s *Storage) DeleteList(ctx context.Context, name string) error {
+	if err := ValidateListName(name); err != nil {
+		return err
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var before []api.Station
+	if list, err := s.LoadList(ctx, name); err == nil {
+		before = list.Stations
+	}
+	if err := s.deleteListFile(name); err != nil {
+		return err
+	}
+	folder, base := splitListName(name)
+	s.forget(folder, base)
+	s.pruneFolder(folder)
+	if before != nil {
+		s.recordList("Deleted list "+name, name, before, nil)
+	}
+	return nil
+}
+
+// RenameList renames a list, which may move it to another folder. Its
+// position and manual station order are kept.
+func (s *Storage) RenameList(ctx context.Context, oldName, newName string) error {
+	if err := ValidateListName(oldName); err != nil {
+		return err
+	}
+	if err := ValidateListName(newName); err != nil {
+		return err
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if s.listExists(newName) {
+		return fmt.Errorf("list %q already exists", newName)
+	}
+	var stations []api.Station
+	if list, err := s.LoadList(ctx, oldName); err == nil {
+		stations = list.Stations
+	}
+	if err := s.renameListFile(oldName, newName); err != nil {
+		return err
+	}
+	if stations != nil {
+		RecordChange(fmt.Sprintf("Renamed list %s to %s", oldName, newName),
+			NewJournalChange(JournalFavorites, oldName, stations, nil),
+			NewJournalChange(JournalFavorites, newName, nil, stations))
+	}
+
+	oldFolder, oldBase := splitListName(oldName)
+	newFolder, newBase := splitListName(newName)
+	o := s.loadOrder(oldFolder)
+	ordered := contains(o.Ordered, oldBase)
+	if oldFolder == newFolder {
+		i := indexOf(o.Lists, oldBase)
+		if i < 0 && !ordered {
+			return nil
+		}
+		if i >= 0 {
+			o.Lists[i] = newBase
+		}
+		if ordered {
+			o.Ordered = append(remove(o.Ordered, oldBase), newBase)
+		}
+		return s.saveOrder(oldFolder, o)
+	}
+
+	s.forget(oldFolder, oldBase)
+	s.pruneFolder(oldFolder)
+	if ordered {
+		n := s.loadOrder(newFolder)
+		n.Ordered = append(n.Ordered, newBase)
+		return s.saveOrder(newFolder, n)
+	}
+	return nil
+}
+
+// forget drops a deleted list from its folder's order file.
+func (s *Storage) forget(folder, base string) {
+	o := s.loadOrder(folder)
+	if !contains(o.Lists, base) && !contains(o.Ordered, base) {
+		return
+	}
+	o.Lists = remove(o.Lists, base)
+	o.Ordered = remove(o.Ordered, base)
+	_ = s.saveOrder(folder, o)
+}
+
+// pruneFolder removes folder, and then its parents, while they hold no
+// lists.
+func (s *Storage) pruneFolder(folder string) {
+	for folder != "" {
+		keys, err := s.children(folder)
+		if err != nil || len(keys) > 0 {
+			return
+		}
+		if s.db != nil {
+			if s.db.deleteListOrder(folder) != nil {
+				return
+			}
+		} else {
+			_ = os.Remove(s.orderPath(folder))
+			noteOwnWrite(s.orderPath(folder))
+			if os.Remove(filepath.Join(s.favoritePath, filepath.FromSlash(folder))) != nil {
+				return
+			}
+		}
+		parent, base := splitListName(folder)
+		s.forget(parent, base+"/")
+		folder = parent
+	}
+}
+
+// MoveList moves a list up (delta < 0) or down (delta > 0) among the lists
+// and subfolders of its folder. It reports whether the list moved, which it
+// does not at either end.
+func (s *Storage) MoveList(ctx context.Context, name string, delta int) (bool, error) {
+	if err := ValidateListName(name); err != nil {
+		return false, err
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	folder, base := splitListName(name)
+	keys, err := s.children(folder)
+	if err != nil {
+		return false, err
+	}
+	i := indexOf(keys, base)
+	if i < 0 {
+		return false, fmt.Errorf("list %q not found", name)
+	}
+	j := i + delta
+	if j < 0 || j >= len(keys) {
+		return false, nil
+	}
+	keys[i], keys[j] = keys[j], keys[i]
+
+	o := s.loadOrder(folder)
+	o.Lists = keys
+	return true, s.saveOrder(folder, o)
+}
+
+// MoveStation moves a station up (delta < 0) or down (delta > 0) in a list
+// and returns its new position. A list still sorted by name is first saved
+// in that order, which from then on is kept as the manual order.
+func (s *Storage) MoveStation(ctx context.Context, listName, stationUUID string, delta int) (int, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	list, err := s.LoadList(ctx, listName)
+	if err != nil {
+		return 0, err
+	}
+	if !list.Ordered {
+		SortStationsByName(list.Stations)
+	}
+	i := -1
+	for k := range list.Stations {
+		if list.Stations[k].StationUUID == stationUUID {
+			i = k
+			break
+		}
+	}
+	if i < 0 {
+		return 0, ErrStationNotFound
+	}
+	j := i + delta
+	if j < 0 || j >= len(list.Stations) {
+		return i, nil
+	}
+	list.Stations[i], list.Stations[j] = list.Stations[j], list.Stations[i]
+	if err := s.SaveList(ctx, list); err != nil {
+		return i, err
+	}
+
+	if !list.Ordered {
+		folder, base := splitListName(listName)
+		o := s.loadOrder(folder)
+		o.Ordered = append(o.Ordered, base)
+		if err := s.saveOrder(folder, o); err != nil {
+			return j, err
+		}
+	}
+	return j, nil
+}
+
+// SortStationsByName sorts stations by name, ignoring case, as lists
+// without a manual order are shown.
+func SortStationsByName(stations []api.Station) {
+	sort.SliceStable(stations, func(i, j int) bool {
+		return strings.ToLower(stations[i].TrimName()) < strings.ToLower(stations[j].TrimName())
+	})
+}
+
+// ResolveListName finds the list meant by name: the list of that name, or
+// else the only list whose name or last path segment matches it ignoring
+// case, so "smooth" finds "Jazz/Smooth".
+func (s *Storage) ResolveListName(ctx context.Context, name string) (string, error) {
+	if ValidateListName(name) == nil && s.listExists(name) {
+		return name, nil
+	}
+	lists, err := s.GetAllLists(ctx)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	var matches []string
+	for _, list := range lists {
+		if strings.EqualFold(list, name) {
+			return list, nil
+		}
+		if _, base := splitListName(list); strings.EqualFold(base, name) {
+			matches = append(matches, list)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("list %q not found", name)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("list %q is ambiguous: %s", name, strings.Join(matches, ", "))
+}
+
+func indexOf(values []string, v string) int {
+	for i := range values {
+		if values[i] == v {
+			return i
+		}
+	}
+	return -1
+}
+
+func contains(values []string, v string) bool {
+	return indexOf(values, v) >= 0
+}
+
+func remove(values []string, v string) []string {
+	out := values[:0]
+	for _, x := range values {
+		if x != v {
+			out = append(out, x)
+		}
+	}
+	return out
+}
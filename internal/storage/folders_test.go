@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+func TestValidateListName(t *testing.T) {
+	for _, name := range []string{"Jazz", "My-favorites", "Jazz/Smooth", "a/b/c", "100%", "Jazz/search-history"} {
+		if err := ValidateListName(name); err != nil {
+			t.Errorf("ValidateListName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "/Jazz", "Jazz/", "a//b", "../x", "Jazz/..", ".hidden", `a\b`, "search-history", "Search-History"} {
+		if err := ValidateListName(name); !errors.Is(err, ErrInvalidListName) {
+			t.Errorf("ValidateListName(%q) = %v, want ErrInvalidListName", name, err)
+		}
+	}
+}
+
+func TestStorage_Folders(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStorage(dir)
+	ctx := context.Background()
+
+	for _, name := range []string{"My-favorites", "Rock", "Jazz/Smooth", "Jazz/Bebop", "Jazz/Latin/Bossa"} {
+		if err := store.CreateList(ctx, name); err != nil {
+			t.Fatalf("CreateList(%q): %v", name, err)
+		}
+	}
+	if err := store.CreateList(ctx, "Rock"); err == nil {
+		t.Error("expected an error for an existing list")
+	}
+	// Hidden folders and system files are not lists
+	if err := os.MkdirAll(filepath.Join(dir, ".backup"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, SystemFileSearchHistory), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lists, err := store.GetAllLists(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Jazz/Bebop", "Jazz/Latin/Bossa", "Jazz/Smooth", "My-favorites", "Rock"}
+	if !reflect.DeepEqual(lists, want) {
+		t.Errorf("GetAllLists = %v, want %v", lists, want)
+	}
+
+	// Move Rock to the top, then Smooth above Latin
+	for i := 0; i < 3; i++ {
+		if _, err := store.MoveList(ctx, "Rock", -1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if moved, err := store.MoveList(ctx, "Rock", -1); err != nil || moved {
+		t.Errorf("MoveList at the top = %v, %v", moved, err)
+	}
+	if _, err := store.MoveList(ctx, "Jazz/Smooth", -1); err != nil {
+		t.Fatal(err)
+	}
+	lists, _ = store.GetAllLists(ctx)
+	want = []string{"Rock", "Jazz/Bebop", "Jazz/Smooth", "Jazz/Latin/Bossa", "My-favorites"}
+	if !reflect.DeepEqual(lists, want) {
+		t.Errorf("after moves GetAllLists = %v, want %v", lists, want)
+	}
+
+	// Renaming in place keeps the position; deleting the last list of a
+	// folder removes it
+	if err := store.RenameList(ctx, "Jazz/Smooth", "Jazz/Cool"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RenameList(ctx, "Jazz/Latin/Bossa", "Bossa"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Jazz", "Latin")); !os.IsNotExist(err) {
+		t.Errorf("expected the empty Latin folder to be removed, got %v", err)
+	}
+	if err := store.DeleteList(ctx, "Jazz/Bebop"); err != nil {
+		t.Fatal(err)
+	}
+	// Bossa was never moved in the top folder, so it follows the others
+	lists, _ = store.GetAllLists(ctx)
+	want = []string{"Rock", "Jazz/Cool", "My-favorites", "Bossa"}
+	if !reflect.DeepEqual(lists, want) {
+		t.Errorf("after rename and delete GetAllLists = %v, want %v", lists, want)
+	}
+
+	if _, err := store.LoadList(ctx, "../outside"); !errors.Is(err, ErrInvalidListName) {
+		t.Errorf("LoadList outside the favorites directory = %v", err)
+	}
+}
+
+func TestStorage_MoveStation(t *testing.T) {
+	store := NewStorage(t.TempDir())
+	ctx := context.Background()
+	list := &FavoritesList{Name: "Jazz/Smooth", Stations: []api.Station{
+		{StationUUID: "c", Name: "Charlie"},
+		{StationUUID: "a", Name: "alpha"},
+		{StationUUID: "b", Name: "Bravo"},
+	}}
+	if err := store.SaveList(ctx, list); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first move starts from the name order the list was shown in
+	to, err := store.MoveStation(ctx, "Jazz/Smooth", "c", -1)
+	if err != nil || to != 1 {
+		t.Fatalf("MoveStation = %d, %v", to, err)
+	}
+	loaded, err := store.LoadList(ctx, "Jazz/Smooth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Ordered {
+		t.Error("expected the list to be in manual order")
+	}
+	var got []string
+	for _, s := range loaded.Stations {
+		got = append(got, s.StationUUID)
+	}
+	if want := []string{"a", "c", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stations = %v, want %v", got, want)
+	}
+
+	if to, err := store.MoveStation(ctx, "Jazz/Smooth", "a", -1); err != nil || to != 0 {
+		t.Errorf("MoveStation at the top = %d, %v", to, err)
+	}
+	if _, err := store.MoveStation(ctx, "Jazz/Smooth", "zzz", 1); !errors.Is(err, ErrStationNotFound) {
+		t.Errorf("MoveStation of a missing station = %v", err)
+	}
+
+	// The manual order follows the list when it is renamed
+	if err := store.RenameList(ctx, "Jazz/Smooth", "Smooth"); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, _ := store.LoadList(ctx, "Smooth"); loaded == nil || !loaded.Ordered {
+		t.Error("expected the renamed list to keep its manual order")
+	}
+}
+
+func TestStorage_ResolveListName(t *testing.T) {
+	store := NewStorage(t.TempDir())
+	ctx := context.Background()
+	for _, name := range []string{"My-favorites", "Jazz/Smooth", "Jazz/Latin", "Brazil/Latin"} {
+		if err := store.CreateList(ctx, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, want := range map[string]string{
+		"My-favorites": "My-favorites",
+		"my-favorites": "My-favorites",
+		"jazz/smooth":  "Jazz/Smooth",
+		"smooth":       "Jazz/Smooth",
+	} {
+		if got, err := store.ResolveListName(ctx, name); err != nil || got != want {
+			t.Errorf("ResolveListName(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := store.ResolveListName(ctx, "Latin"); err == nil {
+		t.Error("expected an error for an ambiguous name")
+	}
+	if _, err := store.ResolveListName(ctx, "Missing"); err == nil {
+		t.Error("expected an error for a missing list")
+	}
+}
@@ -1,10 +1,13 @@
 package storage
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -58,7 +61,11 @@ func NewGistSyncManager(client *gist.Client) (*GistSyncManager, error) {
 //	data/station_ratings.json         → ratings.json
 //	data/station_tags.json            → tags.json
 //	data/station_metadata.json        → metadata.json
+//	data/liked_songs.json             → liked_songs.json
+//	data/station_notes.json           → station_notes.json
+//	data/sessions/sessions-2026-10.jsonl  → sessions-2026-10.jsonl
 //	data/favorites/Jazz.json               → fav--Jazz.json
+//	data/favorites/Jazz/Smooth.json        → fav--Jazz%2FSmooth.json
 //	data/favorites/search-history.json    → search-history.json
 func gistFilename(relPath string) string {
 	slashPath := filepath.ToSlash(relPath)
@@ -75,18 +82,32 @@ func gistFilename(relPath string) string {
 		return "tags.json"
 	case "data/station_metadata.json":
 		return "metadata.json"
+	case "data/" + LikedSongsFileName:
+		return LikedSongsFileName
+	case "data/" + StationNotesFileName:
+		return StationNotesFileName
 	case "data/favorites/" + SystemFileSearchHistory:
 		return "search-history.json"
 	}
-	// data/favorites/Jazz.json → fav--Jazz.json
-	if strings.HasPrefix(slashPath, "data/favorites/") {
-		base := filepath.Base(relPath)
-		return "fav--" + base
+	if isSessionLogPath(slashPath) {
+		return filepath.Base(relPath)
+	}
+	// data/favorites/Jazz.json → fav--Jazz.json; lists in folders escape
+	// the "/" of their path
+	if rel, ok := strings.CutPrefix(slashPath, "data/favorites/"); ok {
+		return "fav--" + favPathEscaper.Replace(rel)
 	}
 	// Fallback: replace slashes with "--"
 	return strings.ReplaceAll(slashPath, "/", "--")
 }
 
+// favPathEscaper encodes the folders of a nested list in its Gist filename,
+// and favPathUnescaper decodes them.
+var (
+	favPathEscaper   = strings.NewReplacer("%", "%25", "/", "%2F")
+	favPathUnescaper = strings.NewReplacer("%2F", "/", "%25", "%")
+)
+
 // gistFilenameToRelPath is the inverse of gistFilename.
 // Returns "" for unrecognised filenames.
 func gistFilenameToRelPath(name string) string {
@@ -103,9 +124,18 @@ func gistFilenameToRelPath(name string) string {
 		return filepath.Join("data", "station_tags.json")
 	case "metadata.json":
 		return filepath.Join("data", "station_metadata.json")
+	case LikedSongsFileName:
+		return filepath.Join("data", LikedSongsFileName)
+	case StationNotesFileName:
+		return filepath.Join("data", StationNotesFileName)
 	case "search-history.json":
 		return filepath.Join("data", "favorites", SystemFileSearchHistory)
 	}
+	// IsSessionLogFile only accepts "sessions-YYYY-MM.jsonl", so the name
+	// cannot leave data/sessions/.
+	if IsSessionLogFile(name) {
+		return filepath.Join("data", SessionsDirName, name)
+	}
 	if strings.HasPrefix(name, "fav--") {
 		base := strings.TrimPrefix(name, "fav--")
 		// Reject empty, dot-segments, or any name that encodes a path separator.
@@ -114,6 +144,16 @@ func gistFilenameToRelPath(name string) string {
 		if base == "" || base == "." || base == ".." || base != filepath.Base(base) {
 			return ""
 		}
+		// fav--Jazz%2FSmooth.json is a list in a folder; only the escaped
+		// "/" may separate its segments, and none may be hidden other than
+		// a folder's list order file.
+		if strings.Contains(base, "%") {
+			rel := favPathUnescaper.Replace(base)
+			if !validNestedFavoritesPath(rel) {
+				return ""
+			}
+			return filepath.Join("data", "favorites", filepath.FromSlash(rel))
+		}
 		// Reject fav--search-history.json: search-history.json already has its
 		// own canonical mapping and this alias would resolve to the same
 		// destination, letting a crafted gist silently overwrite it with
@@ -126,6 +166,82 @@ func gistFilenameToRelPath(name string) string {
 	return ""
 }
 
+// validNestedFavoritesPath reports whether rel, a slash-separated path
+// decoded from an escaped Gist filename, is a list or a folder's list order
+// file in the favorites directory.
+func validNestedFavoritesPath(rel string) bool {
+	dir, file := path.Split(rel)
+	if file == SystemFileListOrder {
+		return ValidateListName(strings.TrimSuffix(dir, "/")) == nil
+	}
+	return ValidateListName(strings.TrimSuffix(rel, ".json")) == nil
+}
+
+// FavoritesGistFiles returns the contents of the *.json files in the
+// favorites directory favoritePath and its list folders by their names in a
+// favorites Gist (see FavoritesGistFilename). Hidden folders such as the
+// restore .backup are skipped.
+func FavoritesGistFiles(favoritePath string) (map[string]string, error) {
+	files := make(map[string]string)
+	err := filepath.WalkDir(favoritePath, func(p string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if e.IsDir() {
+			if p != favoritePath && strings.HasPrefix(e.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(e.Name(), ".json") {
+			return nil
+		}
+		rel, err := filepath.Rel(favoritePath, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[FavoritesGistFilename(filepath.ToSlash(rel))] = string(data)
+		return nil
+	})
+	return files, err
+}
+
+// FavoritesGistFilename maps rel, a slash-separated path in the favorites
+// directory, to its filename in a favorites Gist. Files at the top level
+// keep their names; lists in folders escape the "/" of their path as the
+// backup Gist does.
+//
+//	Jazz.json        → Jazz.json
+//	Jazz/Smooth.json → Jazz%2FSmooth.json
+//	Jazz/.order.json → Jazz%2F.order.json
+func FavoritesGistFilename(rel string) string {
+	return favPathEscaper.Replace(rel)
+}
+
+// FavoritesGistPath is the inverse of FavoritesGistFilename, returning the
+// slash-separated path in the favorites directory. It returns "" for a name
+// that would leave the favorites directory.
+func FavoritesGistPath(name string) string {
+	if name == "" || name == "." || name == ".." || name != filepath.Base(name) {
+		return ""
+	}
+	if !strings.Contains(name, "%") {
+		return name
+	}
+	rel := favPathUnescaper.Replace(name)
+	if !strings.Contains(rel, "/") {
+		return rel
+	}
+	if !validNestedFavoritesPath(rel) {
+		return ""
+	}
+	return rel
+}
+
 // FindBackupGist returns the single tera-data-backup Gist, or nil if none exists.
 // It returns an error if more than one qualifying Gist is found, since Push/Pull
 // cannot safely choose between them.
@@ -191,11 +307,48 @@ func syncPrefForRelPath(relPath string, prefs SyncPrefs) bool {
 	return zipEntryWanted(filepath.ToSlash(relPath), prefs)
 }
 
+// gistManifest is the content of backupGistMarkerFile. Encryption is set once
+// files of the Gist have been encrypted, and kept while any may still be.
+type gistManifest struct {
+	App        string            `json:"app"`
+	Encryption *encryptionHeader `json:"encryption,omitempty"`
+}
+
 // Push uploads selected files to the dedicated backup Gist.
 // If no backup Gist exists it is created (secret); otherwise its files are updated.
 // Files that exist locally are pushed; files that are in-scope in the Gist but
 // no longer exist locally are deleted (sent as null per the GitHub API).
 func (m *GistSyncManager) Push(prefs SyncPrefs) error {
+	return m.PushWithPassphrase(prefs, "")
+}
+
+// PushWithPassphrase is Push, encrypting each file with passphrase unless it
+// is empty. A Gist that already holds encrypted files keeps its salt, so the
+// files of categories not pushed now still open with the same passphrase; a
+// different passphrase is refused with ErrWrongPassphrase.
+func (m *GistSyncManager) PushWithPassphrase(prefs SyncPrefs, passphrase string) error {
+	existing, err := m.FindBackupGist()
+	if err != nil {
+		return err
+	}
+	httpClient := &http.Client{Timeout: backupGistHTTPTimeout}
+	manifest := gistManifest{App: "tera"}
+	if existing != nil {
+		if manifest.Encryption, err = gistEncryptionHeader(httpClient, existing.Files); err != nil {
+			return err
+		}
+	}
+	var key []byte
+	if passphrase != "" {
+		if manifest.Encryption != nil {
+			if key, err = manifest.Encryption.unlock(passphrase); err != nil {
+				return fmt.Errorf("the backup Gist is encrypted with a different passphrase: %w", err)
+			}
+		} else if manifest.Encryption, key, err = newEncryptionHeader(passphrase); err != nil {
+			return fmt.Errorf("failed to set up encryption: %w", err)
+		}
+	}
+
 	bm := &BackupManager{configDir: m.configDir}
 	relPaths, err := bm.categoryFiles(prefs)
 	if err != nil {
@@ -214,18 +367,23 @@ func (m *GistSyncManager) Push(prefs SyncPrefs) error {
 			}
 			return fmt.Errorf("failed to read %s: %w", relPath, err)
 		}
+		name := gistFilename(relPath)
 		content := string(data)
-		present[gistFilename(relPath)] = &content
+		if key != nil {
+			if content, err = sealGistContent(key, name, content); err != nil {
+				return fmt.Errorf("failed to encrypt %s: %w", relPath, err)
+			}
+		}
+		present[name] = &content
 	}
 
 	// Always include the marker file so FindBackupGist can identify this Gist.
-	marker := `{"app":"tera"}`
-	present[backupGistMarkerFile] = &marker
-
-	existing, err := m.FindBackupGist()
+	markerData, err := json.Marshal(manifest)
 	if err != nil {
 		return err
 	}
+	marker := string(markerData)
+	present[backupGistMarkerFile] = &marker
 
 	if existing == nil {
 		if len(present) == 1 { // only the marker
@@ -286,6 +444,12 @@ func (m *GistSyncManager) ConflictingGistFiles(g *gist.Gist, prefs SyncPrefs) ([
 // When force is false and files already exist, it returns RestoreConflictError.
 // Pass force=true to overwrite without checking.
 func (m *GistSyncManager) Pull(prefs SyncPrefs, force bool) error {
+	return m.PullWithPassphrase(prefs, force, "")
+}
+
+// PullWithPassphrase is Pull for a backup Gist that may be encrypted; see
+// PullFromGistWithPassphrase.
+func (m *GistSyncManager) PullWithPassphrase(prefs SyncPrefs, force bool, passphrase string) error {
 	// FindBackupGist already fetches the full Gist (including file contents and
 	// raw URLs) to verify the marker file, so reuse it directly.
 	g, err := m.FindBackupGist()
@@ -295,7 +459,7 @@ func (m *GistSyncManager) Pull(prefs SyncPrefs, force bool) error {
 	if g == nil {
 		return fmt.Errorf("no backup Gist found (description: %q); push first to create one", BackupGistDescription)
 	}
-	return m.PullFromGist(g, prefs, force)
+	return m.PullFromGistWithPassphrase(g, prefs, force, passphrase)
 }
 
 // PullFromGist downloads selected files from the given Gist directly,
@@ -303,6 +467,14 @@ func (m *GistSyncManager) Pull(prefs SyncPrefs, force bool) error {
 // When force is false and files already exist, it returns RestoreConflictError.
 // Pass force=true to overwrite without checking.
 func (m *GistSyncManager) PullFromGist(g *gist.Gist, prefs SyncPrefs, force bool) error {
+	return m.PullFromGistWithPassphrase(g, prefs, force, "")
+}
+
+// PullFromGistWithPassphrase is PullFromGist for a Gist whose files may be
+// encrypted. Encrypted files need the passphrase: without it
+// ErrPassphraseRequired is returned, and ErrWrongPassphrase for a wrong one,
+// before anything is written.
+func (m *GistSyncManager) PullFromGistWithPassphrase(g *gist.Gist, prefs SyncPrefs, force bool, passphrase string) error {
 	if g == nil {
 		return fmt.Errorf("gist is required")
 	}
@@ -316,6 +488,11 @@ func (m *GistSyncManager) PullFromGist(g *gist.Gist, prefs SyncPrefs, force bool
 	if _, ok := g.Files[backupGistMarkerFile]; !ok {
 		return fmt.Errorf("gist is not a tera backup (missing %s)", backupGistMarkerFile)
 	}
+	httpClient := &http.Client{Timeout: backupGistHTTPTimeout}
+	key, err := gistKey(httpClient, g.Files, passphrase)
+	if err != nil {
+		return err
+	}
 
 	if !force {
 		conflicts, err := m.ConflictingGistFiles(g, prefs)
@@ -329,8 +506,7 @@ func (m *GistSyncManager) PullFromGist(g *gist.Gist, prefs SyncPrefs, force bool
 
 	// Drive wanted from the Gist's own file list so restores work on a fresh
 	// machine where categoryFiles would return nothing for missing favorites.
-	httpClient := &http.Client{Timeout: backupGistHTTPTimeout}
-	return stageAndWriteGistFiles(httpClient, g.Files, prefs, m.configDir)
+	return stageAndWriteGistFiles(httpClient, g.Files, prefs, m.configDir, key)
 }
 
 // categorizePath updates prefs based on a single gist filename.
@@ -352,8 +528,12 @@ func categorizePath(name string, prefs *SyncPrefs) {
 	case relPath == filepath.Join("data", "blocklist.json"):
 		prefs.Blocklist = true
 	case relPath == filepath.Join("data", "station_metadata.json") ||
-		relPath == filepath.Join("data", "station_tags.json"):
+		relPath == filepath.Join("data", "station_tags.json") ||
+		relPath == filepath.Join("data", StationNotesFileName) ||
+		isSessionLogPath(filepath.ToSlash(relPath)):
 		prefs.MetadataTags = true
+	case relPath == filepath.Join("data", LikedSongsFileName):
+		prefs.LikedSongs = true
 	}
 }
 
@@ -441,6 +621,12 @@ func ConflictingFilesForGist(g *gist.Gist, prefs SyncPrefs) ([]string, error) {
 // first); this function does not re-fetch because it has no authenticated client
 // and a public re-fetch would fail for private gists.
 func RestoreFromGistDirect(g *gist.Gist, prefs SyncPrefs, force bool) error {
+	return RestoreFromGistDirectWithPassphrase(g, prefs, force, "")
+}
+
+// RestoreFromGistDirectWithPassphrase is RestoreFromGistDirect for a Gist
+// whose files may be encrypted, like PullFromGistWithPassphrase.
+func RestoreFromGistDirectWithPassphrase(g *gist.Gist, prefs SyncPrefs, force bool, passphrase string) error {
 	if g == nil {
 		return fmt.Errorf("gist is required")
 	}
@@ -450,6 +636,11 @@ func RestoreFromGistDirect(g *gist.Gist, prefs SyncPrefs, force bool) error {
 	if _, ok := g.Files[backupGistMarkerFile]; !ok {
 		return fmt.Errorf("gist is not a tera backup (missing %s)", backupGistMarkerFile)
 	}
+	httpClient := &http.Client{Timeout: backupGistHTTPTimeout}
+	key, err := gistKey(httpClient, g.Files, passphrase)
+	if err != nil {
+		return err
+	}
 	if !force {
 		conflicts, err := ConflictingFilesForGist(g, prefs)
 		if err != nil {
@@ -464,8 +655,65 @@ func RestoreFromGistDirect(g *gist.Gist, prefs SyncPrefs, force bool) error {
 		return err
 	}
 
-	httpClient := &http.Client{Timeout: backupGistHTTPTimeout}
-	return stageAndWriteGistFiles(httpClient, g.Files, prefs, baseDir)
+	return stageAndWriteGistFiles(httpClient, g.Files, prefs, baseDir, key)
+}
+
+// GistEncrypted reports whether any file of the backup Gist g is encrypted.
+// g must be fully fetched.
+func GistEncrypted(g *gist.Gist) bool {
+	if g == nil {
+		return false
+	}
+	for name, f := range g.Files {
+		if name != backupGistMarkerFile && isSealedGistContent(f.Content) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckGistPassphrase returns nil if the backup Gist g can be restored with
+// passphrase: none of its files is encrypted, or passphrase opens them.
+func CheckGistPassphrase(g *gist.Gist, passphrase string) error {
+	if g == nil {
+		return fmt.Errorf("gist is required")
+	}
+	_, err := gistKey(&http.Client{Timeout: backupGistHTTPTimeout}, g.Files, passphrase)
+	return err
+}
+
+// gistKey returns the key for the encrypted files of a backup Gist, or nil
+// if none is encrypted.
+func gistKey(httpClient *http.Client, files map[string]gist.GistFile, passphrase string) ([]byte, error) {
+	if !GistEncrypted(&gist.Gist{Files: files}) {
+		return nil, nil
+	}
+	header, err := gistEncryptionHeader(httpClient, files)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("gist has encrypted files but no encryption header in %s", backupGistMarkerFile)
+	}
+	return header.unlock(passphrase)
+}
+
+// gistEncryptionHeader reads the encryption header from the manifest of a
+// backup Gist; nil if it has none.
+func gistEncryptionHeader(httpClient *http.Client, files map[string]gist.GistFile) (*encryptionHeader, error) {
+	f, ok := files[backupGistMarkerFile]
+	if !ok {
+		return nil, nil
+	}
+	content, err := fetchRawContent(httpClient, f.RawURL, f.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", backupGistMarkerFile, err)
+	}
+	var manifest gistManifest
+	if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", backupGistMarkerFile, err)
+	}
+	return manifest.Encryption, nil
 }
 
 // stageAndWriteGistFiles fetches Gist file content into memory, then writes
@@ -479,11 +727,14 @@ func RestoreFromGistDirect(g *gist.Gist, prefs SyncPrefs, force bool) error {
 // already-written files on disk. This is intentional: the individually-atomic
 // files are never corrupt, and the user can complete a partial restore by
 // re-running with force=true (the overwrite-warning screen offers this).
+//
+// Encrypted files are decrypted with key, which must be set if there are any.
 func stageAndWriteGistFiles(
 	httpClient *http.Client,
 	files map[string]gist.GistFile,
 	prefs SyncPrefs,
 	baseDir string,
+	key []byte,
 ) error {
 	staged := make(map[string][]byte)
 	for name, gistFile := range files {
@@ -495,6 +746,14 @@ func stageAndWriteGistFiles(
 		if err != nil {
 			return fmt.Errorf("failed to fetch %s: %w", name, err)
 		}
+		if isSealedGistContent(content) {
+			if key == nil {
+				return ErrPassphraseRequired
+			}
+			if content, err = openGistContent(key, name, content); err != nil {
+				return err
+			}
+		}
 		staged[relPath] = []byte(content)
 	}
 	for relPath, content := range staged {
@@ -506,7 +765,9 @@ func stageAndWriteGistFiles(
 			return fmt.Errorf("failed to write %s: %w", relPath, err)
 		}
 	}
-	return nil
+	// With the SQLite backend the pulled stats, ratings, tags and favorites
+	// only count once they are in the database.
+	return importDatabaseJSON(filepath.Join(baseDir, "data"))
 }
 
 // fetchRawContent returns the file content. If rawURL is non-empty it fetches
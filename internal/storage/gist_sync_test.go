@@ -1,7 +1,9 @@
 package storage
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -16,10 +18,16 @@ func TestGistFilename(t *testing.T) {
 		{filepath.Join("data", "station_ratings.json"), "ratings.json"},
 		{filepath.Join("data", "station_tags.json"), "tags.json"},
 		{filepath.Join("data", "station_metadata.json"), "metadata.json"},
+		{filepath.Join("data", "liked_songs.json"), "liked_songs.json"},
+		{filepath.Join("data", "station_notes.json"), "station_notes.json"},
+		{filepath.Join("data", "sessions", "sessions-2026-10.jsonl"), "sessions-2026-10.jsonl"},
 		{filepath.Join("data", "favorites", SystemFileSearchHistory), "search-history.json"},
 		{filepath.Join("data", "favorites", "Jazz.json"), "fav--Jazz.json"},
 		{filepath.Join("data", "favorites", "My-80s-Rock-list.json"), "fav--My-80s-Rock-list.json"},
 		{filepath.Join("data", "favorites", "Bossa-nova.json"), "fav--Bossa-nova.json"},
+		{filepath.Join("data", "favorites", "Jazz", "Smooth.json"), "fav--Jazz%2FSmooth.json"},
+		{filepath.Join("data", "favorites", "Jazz", SystemFileListOrder), "fav--Jazz%2F.order.json"},
+		{filepath.Join("data", "favorites", "100%.json"), "fav--100%25.json"},
 	}
 
 	for _, tc := range cases {
@@ -41,9 +49,19 @@ func TestGistFilenameToRelPath(t *testing.T) {
 		{"ratings.json", filepath.Join("data", "station_ratings.json")},
 		{"tags.json", filepath.Join("data", "station_tags.json")},
 		{"metadata.json", filepath.Join("data", "station_metadata.json")},
+		{"liked_songs.json", filepath.Join("data", "liked_songs.json")},
+		{"station_notes.json", filepath.Join("data", "station_notes.json")},
+		{"sessions-2026-10.jsonl", filepath.Join("data", "sessions", "sessions-2026-10.jsonl")},
+		{"sessions-../x.jsonl", ""},
 		{"search-history.json", filepath.Join("data", "favorites", SystemFileSearchHistory)},
 		{"fav--Jazz.json", filepath.Join("data", "favorites", "Jazz.json")},
 		{"fav--Bossa-nova.json", filepath.Join("data", "favorites", "Bossa-nova.json")},
+		{"fav--Jazz%2FSmooth.json", filepath.Join("data", "favorites", "Jazz", "Smooth.json")},
+		{"fav--Jazz%2F.order.json", filepath.Join("data", "favorites", "Jazz", SystemFileListOrder)},
+		{"fav--100%25.json", filepath.Join("data", "favorites", "100%.json")},
+		{"fav--..%2F..%2Fconfig.yaml", ""},
+		{"fav--Jazz%2F..%2F..%2Fx.json", ""},
+		{"fav--%2FJazz.json", ""},
 		{"unknown.json", ""},
 		// fav--search-history.json must be rejected: search-history.json already
 		// has its own canonical mapping and both would resolve to the same path.
@@ -82,6 +100,9 @@ func TestGistFilenameRoundTrip(t *testing.T) {
 		filepath.Join("data", "station_ratings.json"),
 		filepath.Join("data", "station_tags.json"),
 		filepath.Join("data", "station_metadata.json"),
+		filepath.Join("data", "liked_songs.json"),
+		filepath.Join("data", "station_notes.json"),
+		filepath.Join("data", "sessions", "sessions-2026-10.jsonl"),
 		filepath.Join("data", "favorites", SystemFileSearchHistory),
 		filepath.Join("data", "favorites", "Jazz.json"),
 		filepath.Join("data", "favorites", "Smooth-Jazz.json"),
@@ -96,4 +117,52 @@ func TestGistFilenameRoundTrip(t *testing.T) {
 	}
 }
 
+func TestFavoritesGistFilename(t *testing.T) {
+	for _, rel := range []string{"Jazz.json", ".order.json", "Jazz/Smooth.json", "Jazz/Late/Night.json", "Jazz/.order.json", "100%.json"} {
+		name := FavoritesGistFilename(rel)
+		if strings.Contains(name, "/") {
+			t.Errorf("FavoritesGistFilename(%q) = %q contains a slash", rel, name)
+		}
+		if got := FavoritesGistPath(name); got != rel {
+			t.Errorf("round-trip failed for %q: name=%q, path=%q", rel, name, got)
+		}
+	}
+	for _, name := range []string{"", "..", "../x.json", "..%2Fx.json", "Jazz%2F..%2Fx.json", "Jazz%2F.hidden.json", "%2Fx.json"} {
+		if got := FavoritesGistPath(name); got != "" {
+			t.Errorf("FavoritesGistPath(%q) = %q, want rejected", name, got)
+		}
+	}
+}
 
+func TestFavoritesGistFiles(t *testing.T) {
+	dir := t.TempDir()
+	for rel, content := range map[string]string{
+		"Jazz.json":            "[1]",
+		"Jazz/Smooth.json":     "[2]",
+		"Jazz/.order.json":     "{}",
+		".backup/Old.json.bak": "x",
+		".backup/Old.json":     "x",
+		"notes.txt":            "x",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err := FavoritesGistFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"Jazz.json": "[1]", "Jazz%2FSmooth.json": "[2]", "Jazz%2F.order.json": "{}"}
+	if len(files) != len(want) {
+		t.Fatalf("FavoritesGistFiles = %v, want %v", files, want)
+	}
+	for name, content := range want {
+		if files[name] != content {
+			t.Errorf("files[%q] = %q, want %q", name, files[name], content)
+		}
+	}
+}
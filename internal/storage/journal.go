@@ -0,0 +1,359 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// JournalFileName is the undo/redo journal in the data directory.
+const JournalFileName = "journal.json"
+
+// The journal keeps changes for a limited window so undo stays meaningful
+// and the file stays small.
+const (
+	JournalMaxAge     = 7 * 24 * time.Hour
+	JournalMaxEntries = 200
+)
+
+// Stores whose changes are journaled. Each store registers how to put one of
+// its keys back with Journal.Handle.
+const (
+	JournalFavorites = "favorites" // key: list name, value: []api.Station
+	JournalTags      = "tags"      // key: station UUID, value: *StationTags
+	JournalRatings   = "ratings"   // key: station UUID, value: ratingSnapshot
+	JournalMetadata  = "metadata"  // key: station UUID, value: metadataSnapshot
+	JournalBlocklist = "blocklist" // keys and values are the blocklist's own
+)
+
+// ErrNothingToRedo is returned by Redo when no undone change is left.
+var ErrNothingToRedo = errors.New("nothing to redo")
+
+// JournalChange is the value of one key of a store before and after a
+// change. A JSON null means the key did not exist.
+type JournalChange struct {
+	Store  string          `json:"store"`
+	Key    string          `json:"key"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// NewJournalChange builds a change from the values of key before and after.
+// Pass nil for a value that does not exist.
+func NewJournalChange(store, key string, before, after any) JournalChange {
+	return JournalChange{Store: store, Key: key, Before: journalValue(before), After: journalValue(after)}
+}
+
+func journalValue(v any) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return data
+}
+
+// isNullValue reports whether raw is a missing value.
+func isNullValue(raw json.RawMessage) bool {
+	return len(raw) == 0 || bytes.Equal(raw, []byte("null"))
+}
+
+// JournalEntry is one user action, which may change several keys.
+type JournalEntry struct {
+	ID          int64           `json:"id"`
+	At          time.Time       `json:"at"`
+	Description string          `json:"description"`
+	Changes     []JournalChange `json:"changes"`
+	Undone      bool            `json:"undone,omitempty"`
+}
+
+type journalFile struct {
+	Version int            `json:"version"`
+	NextID  int64          `json:"next_id"`
+	Entries []JournalEntry `json:"entries"`
+}
+
+// Journal records data changes so they can be undone and redone, across
+// restarts. Undone entries stay at the end of the journal until a new change
+// is recorded, which drops them.
+type Journal struct {
+	path     string
+	file     *SharedFile
+	mu       sync.Mutex
+	data     journalFile
+	handlers map[string]func(key string, value json.RawMessage) error
+	// replaying is set while Undo or Redo writes values back, so the stores
+	// don't journal those writes as new changes.
+	replaying atomic.Bool
+}
+
+// OpenJournal loads the journal in dataPath. A missing file is an empty
+// journal; an unreadable one is replaced on the next change and reported.
+func OpenJournal(dataPath string) (*Journal, error) {
+	path := filepath.Join(dataPath, JournalFileName)
+	j := &Journal{
+		path:     path,
+		file:     NewSharedFile(path),
+		data:     journalFile{Version: 1, NextID: 1},
+		handlers: make(map[string]func(string, json.RawMessage) error),
+	}
+	MigrateOnLoad(path)
+	if err := j.loadLocked(); err != nil && !os.IsNotExist(err) {
+		return j, err
+	}
+	return j, nil
+}
+
+// loadLocked reads the journal from disk. Caller must hold j.mu, or be the
+// only one with j.
+func (j *Journal) loadLocked() error {
+	raw, err := j.file.Read()
+	if err != nil {
+		return err
+	}
+	var f journalFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", JournalFileName, err)
+	}
+	if f.NextID < 1 {
+		f.NextID = 1
+	}
+	j.data = f
+	j.pruneLocked(time.Now())
+	return nil
+}
+
+// lockLocked takes the cross-process lock on the journal and rereads it if
+// another TERA recorded changes since. Caller must hold j.mu.
+func (j *Journal) lockLocked() (*FileLock, error) {
+	lock, err := j.file.Lock()
+	if err != nil {
+		return nil, err
+	}
+	if j.file.Changed() {
+		_ = j.loadLocked()
+	}
+	return lock, nil
+}
+
+// Reload rereads the journal if another TERA recorded or replayed changes
+// since. It reports whether it did.
+func (j *Journal) Reload() (bool, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if !j.file.Changed() {
+		return false, nil
+	}
+	if err := j.loadLocked(); err != nil && !os.IsNotExist(err) {
+		return true, err
+	}
+	return true, nil
+}
+
+// Handle registers how changes of store are written back. apply gets a
+// key and the value to give it, null meaning the key should not exist.
+func (j *Journal) Handle(store string, apply func(key string, value json.RawMessage) error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.handlers[store] = apply
+}
+
+// Record adds a change to the journal. Changes that leave their value as it
+// was are skipped, and so is the entry when none is left.
+func (j *Journal) Record(description string, changes ...JournalChange) error {
+	if j == nil || j.replaying.Load() {
+		return nil
+	}
+	kept := changes[:0:0]
+	for _, c := range changes {
+		if !bytes.Equal(c.Before, c.After) {
+			kept = append(kept, c)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	lock, err := j.lockLocked()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	// A new change ends the redo history
+	n := len(j.data.Entries)
+	for n > 0 && j.data.Entries[n-1].Undone {
+		n--
+	}
+	j.data.Entries = append(j.data.Entries[:n], JournalEntry{
+		ID:          j.data.NextID,
+		At:          time.Now(),
+		Description: description,
+		Changes:     kept,
+	})
+	j.data.NextID++
+	j.pruneLocked(time.Now())
+	return j.saveLocked()
+}
+
+// Entries returns the journal, newest first.
+func (j *Journal) Entries() []JournalEntry {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	entries := make([]JournalEntry, 0, len(j.data.Entries))
+	for i := len(j.data.Entries) - 1; i >= 0; i-- {
+		entries = append(entries, j.data.Entries[i])
+	}
+	return entries
+}
+
+// Undo reverts the newest change that is not undone and returns its
+// description.
+func (j *Journal) Undo() (string, error) {
+	if j == nil {
+		return "", ErrNothingToUndo
+	}
+	return j.replay(func(entries []JournalEntry) int {
+		for i := len(entries) - 1; i >= 0; i-- {
+			if !entries[i].Undone {
+				return i
+			}
+		}
+		return -1
+	}, true)
+}
+
+// Redo applies again the oldest undone change and returns its description.
+func (j *Journal) Redo() (string, error) {
+	if j == nil {
+		return "", ErrNothingToRedo
+	}
+	return j.replay(func(entries []JournalEntry) int {
+		for i, e := range entries {
+			if e.Undone {
+				return i
+			}
+		}
+		return -1
+	}, false)
+}
+
+// replay writes back the before (undo) or after values of the entry pick
+// selects, then flags it.
+func (j *Journal) replay(pick func([]JournalEntry) int, undo bool) (string, error) {
+	j.mu.Lock()
+	if lock, err := j.lockLocked(); err == nil {
+		lock.Unlock()
+	}
+	i := pick(j.data.Entries)
+	if i < 0 {
+		j.mu.Unlock()
+		if undo {
+			return "", ErrNothingToUndo
+		}
+		return "", ErrNothingToRedo
+	}
+	entry := j.data.Entries[i]
+	handlers := make(map[string]func(string, json.RawMessage) error, len(j.handlers))
+	for store, h := range j.handlers {
+		handlers[store] = h
+	}
+	j.mu.Unlock()
+
+	// Writes happen without j.mu: the stores lock themselves and may call
+	// Record, which replaying turns into a no-op.
+	j.replaying.Store(true)
+	err := applyChanges(handlers, entry.Changes, undo)
+	j.replaying.Store(false)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", entry.Description, err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	lock, err := j.lockLocked()
+	if err != nil {
+		return "", err
+	}
+	defer lock.Unlock()
+	for k := range j.data.Entries {
+		if j.data.Entries[k].ID == entry.ID {
+			j.data.Entries[k].Undone = undo
+		}
+	}
+	return entry.Description, j.saveLocked()
+}
+
+func applyChanges(handlers map[string]func(string, json.RawMessage) error, changes []JournalChange, undo bool) error {
+	for n := range changes {
+		c := changes[n]
+		value := c.After
+		if undo {
+			// Put keys back in the reverse order they were changed in
+			c = changes[len(changes)-1-n]
+			value = c.Before
+		}
+		apply := handlers[c.Store]
+		if apply == nil {
+			return fmt.Errorf("%s changes can't be replayed here", c.Store)
+		}
+		if err := apply(c.Key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneLocked drops entries past the journal's window. Caller must hold
+// j.mu.
+func (j *Journal) pruneLocked(now time.Time) {
+	entries := j.data.Entries
+	for len(entries) > 0 && now.Sub(entries[0].At) > JournalMaxAge {
+		entries = entries[1:]
+	}
+	if len(entries) > JournalMaxEntries {
+		entries = entries[len(entries)-JournalMaxEntries:]
+	}
+	j.data.Entries = entries
+}
+
+// saveLocked writes the journal. Caller must hold j.mu and the file lock.
+func (j *Journal) saveLocked() error {
+	data, err := json.MarshalIndent(j.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := j.file.Write(data, 0644); err != nil {
+		return fmt.Errorf("failed to save journal: %w", err)
+	}
+	return nil
+}
+
+var activeJournal atomic.Pointer[Journal]
+
+// SetJournal makes the stores record their changes in j. Pass nil to stop
+// journaling.
+func SetJournal(j *Journal) {
+	activeJournal.Store(j)
+}
+
+// ActiveJournal returns the journal set with SetJournal, or nil.
+func ActiveJournal() *Journal {
+	return activeJournal.Load()
+}
+
+// RecordChange adds a change to the active journal, if there is one. A
+// journal that can't be saved doesn't fail the change itself.
+func RecordChange(description string, changes ...JournalChange) {
+	_ = activeJournal.Load().Record(description, changes...)
+}
@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+// newTestJournal opens a journal in a temp directory and makes it the active
+// one for the test.
+func newTestJournal(t *testing.T) *Journal {
+	t.Helper()
+	j, err := OpenJournal(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenJournal: %v", err)
+	}
+	SetJournal(j)
+	t.Cleanup(func() { SetJournal(nil) })
+	return j
+}
+
+func listUUIDs(t *testing.T, s *Storage, name string) []string {
+	t.Helper()
+	list, err := s.LoadList(context.Background(), name)
+	if err != nil {
+		t.Fatalf("LoadList(%q): %v", name, err)
+	}
+	var uuids []string
+	for _, st := range list.Stations {
+		uuids = append(uuids, st.StationUUID)
+	}
+	return uuids
+}
+
+func TestJournalFavoritesUndoRedo(t *testing.T) {
+	j := newTestJournal(t)
+	s := NewStorage(t.TempDir())
+	j.Handle(JournalFavorites, s.ApplyJournal)
+	ctx := context.Background()
+
+	jazz := api.Station{StationUUID: "a", Name: "Jazz FM"}
+	rock := api.Station{StationUUID: "b", Name: "Rock FM"}
+	if err := s.AddStation(ctx, "Mix", jazz); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddStation(ctx, "Mix", rock); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RemoveStation(ctx, "Mix", "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	desc, err := j.Undo()
+	if err != nil || desc != "Removed Jazz FM from Mix" {
+		t.Fatalf("Undo = %q, %v", desc, err)
+	}
+	if got := listUUIDs(t, s, "Mix"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("after undo Mix = %v", got)
+	}
+	if _, err := j.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := listUUIDs(t, s, "Mix"); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("after redo Mix = %v", got)
+	}
+
+	// Undoing the first add deletes the list it created
+	for i := 0; i < 3; i++ {
+		if _, err := j.Undo(); err != nil {
+			t.Fatalf("Undo %d: %v", i, err)
+		}
+	}
+	if lists, _ := s.GetAllLists(ctx); len(lists) != 0 {
+		t.Errorf("GetAllLists = %v, want none", lists)
+	}
+	if _, err := j.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Undo past the start = %v", err)
+	}
+
+	// A new change drops what could be redone
+	if err := s.CreateList(ctx, "Jazz"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := j.Redo(); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("Redo after a new change = %v", err)
+	}
+	if n := len(j.Entries()); n != 1 {
+		t.Errorf("journal has %d entries, want 1", n)
+	}
+}
+
+func TestJournalRenameAndDelete(t *testing.T) {
+	j := newTestJournal(t)
+	s := NewStorage(t.TempDir())
+	j.Handle(JournalFavorites, s.ApplyJournal)
+	ctx := context.Background()
+
+	if err := s.AddStation(ctx, "Jazz", api.Station{StationUUID: "a", Name: "Jazz FM"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RenameList(ctx, "Jazz", "Music/Jazz"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteList(ctx, "Music/Jazz"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := j.Undo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lists, _ := s.GetAllLists(ctx); !reflect.DeepEqual(lists, []string{"Jazz"}) {
+		t.Errorf("GetAllLists = %v, want [Jazz]", lists)
+	}
+	if got := listUUIDs(t, s, "Jazz"); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("Jazz = %v", got)
+	}
+}
+
+func TestJournalManagersAndReopen(t *testing.T) {
+	j := newTestJournal(t)
+	dir := t.TempDir()
+	tm, err := NewTagsManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tm.Close()
+	rm, err := NewRatingsManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rm.Close()
+	j.Handle(JournalTags, tm.ApplyJournal)
+	j.Handle(JournalRatings, rm.ApplyJournal)
+
+	station := &api.Station{StationUUID: "a", Name: "Jazz FM"}
+	if err := tm.AddTag("a", "chill"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rm.SetRating(station, 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := rm.ClearAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The journal survives a restart
+	reopened, err := OpenJournal(filepath.Dir(j.path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reopened.Handle(JournalTags, tm.ApplyJournal)
+	reopened.Handle(JournalRatings, rm.ApplyJournal)
+	SetJournal(reopened)
+
+	if desc, err := reopened.Undo(); err != nil || desc != "Cleared all ratings" {
+		t.Fatalf("Undo = %q, %v", desc, err)
+	}
+	if r := rm.GetRating("a"); r == nil || r.Rating != 4 {
+		t.Errorf("rating after undoing ClearAll = %+v", r)
+	}
+	if _, err := reopened.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if r := rm.GetRating("a"); r != nil {
+		t.Errorf("rating after undoing SetRating = %+v", r)
+	}
+	if _, err := reopened.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if tags := tm.GetTags("a"); len(tags) != 0 {
+		t.Errorf("tags after undo = %v", tags)
+	}
+	if _, err := reopened.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if tags := tm.GetTags("a"); !reflect.DeepEqual(tags, []string{"chill"}) {
+		t.Errorf("tags after redo = %v", tags)
+	}
+	if all := tm.GetAllTags(); !reflect.DeepEqual(all, []string{"chill"}) {
+		t.Errorf("GetAllTags after redo = %v", all)
+	}
+}
+
+func TestJournalPrune(t *testing.T) {
+	j := newTestJournal(t)
+	for i := 0; i < JournalMaxEntries+5; i++ {
+		if err := j.Record("change", NewJournalChange(JournalTags, "a", nil, []string{"x"})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(j.Entries()); n != JournalMaxEntries {
+		t.Errorf("journal has %d entries, want %d", n, JournalMaxEntries)
+	}
+
+	j.data.Entries[0].At = time.Now().Add(-JournalMaxAge - time.Hour)
+	j.pruneLocked(time.Now())
+	if n := len(j.Entries()); n != JournalMaxEntries-1 {
+		t.Errorf("after pruning old entries the journal has %d, want %d", n, JournalMaxEntries-1)
+	}
+
+	// Changes that change nothing are not journaled
+	if err := j.Record("noop", NewJournalChange(JournalTags, "b", nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if got := j.Entries()[0].Description; got == "noop" {
+		t.Error("a change without effect was journaled")
+	}
+}
@@ -0,0 +1,308 @@
+package storage
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+// LikedSongsFileName is the liked-songs store inside the data directory.
+const LikedSongsFileName = "liked_songs.json"
+
+// Liked-songs export formats.
+const (
+	LikedSongsFormatCSV     = "csv"
+	LikedSongsFormatJSON    = "json"
+	LikedSongsFormatQueries = "txt"
+)
+
+// ErrAlreadyLiked is returned by Add when the same song on the same station
+// is already the most recent bookmark.
+var ErrAlreadyLiked = errors.New("song already liked")
+
+// LikedSong is a bookmarked track together with where and when it was heard.
+type LikedSong struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`            // ICY title as sent by the station
+	Artist      string    `json:"artist,omitempty"` // Parts parsed from Title, when known
+	Track       string    `json:"track,omitempty"`
+	Album       string    `json:"album,omitempty"`
+	StationName string    `json:"station_name"`
+	StationUUID string    `json:"station_uuid,omitempty"`
+	LikedAt     time.Time `json:"liked_at"`
+}
+
+// Display renders the song as "Artist - Track (Album)", falling back to the
+// raw title for bookmarks whose parts are unknown.
+func (s LikedSong) Display() string {
+	if s.Track == "" {
+		return s.Title
+	}
+	d := s.Track
+	if s.Artist != "" {
+		d = s.Artist + " - " + d
+	}
+	if s.Album != "" {
+		d += " (" + s.Album + ")"
+	}
+	return d
+}
+
+// query renders the song as a plain "Artist Track" search query.
+func (s LikedSong) query() string {
+	if s.Track == "" {
+		return strings.Join(strings.Fields(s.Title), " ")
+	}
+	return strings.Join(strings.Fields(s.Artist+" "+s.Track), " ")
+}
+
+// LikedSongsStore is the on-disk format (no mutex — protected by manager).
+type LikedSongsStore struct {
+	Songs   []LikedSong `json:"songs"` // oldest first
+	Version int         `json:"version"`
+}
+
+// LikedSongsManager manages song bookmarks. Changes are rare and user
+// initiated, so every change is written straight to disk.
+type LikedSongsManager struct {
+	dataPath string
+	file     *SharedFile
+	store    *LikedSongsStore
+	mu       sync.RWMutex
+}
+
+// NewLikedSongsManager creates a manager and loads any existing bookmarks.
+// A missing or corrupted file starts an empty store.
+func NewLikedSongsManager(dataPath string) (*LikedSongsManager, error) {
+	m := &LikedSongsManager{
+		dataPath: dataPath,
+		file:     NewSharedFile(filepath.Join(dataPath, LikedSongsFileName)),
+		store:    &LikedSongsStore{Songs: []LikedSong{}, Version: 1},
+	}
+	MigrateOnLoad(m.file.Path())
+	if err := m.Load(); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "[WARN] liked_songs: failed to load %s: %v (starting with empty store)\n", m.file.Path(), err)
+	}
+	return m, nil
+}
+
+// Load reads bookmarks from disk, replacing the in-memory store only on success.
+func (m *LikedSongsManager) Load() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.loadLocked()
+}
+
+// loadLocked does the work of Load. Caller must hold m.mu.
+func (m *LikedSongsManager) loadLocked() error {
+	data, err := m.file.Read()
+	if err != nil {
+		return err
+	}
+	var tmp LikedSongsStore
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	if tmp.Songs == nil {
+		tmp.Songs = []LikedSong{}
+	}
+	*m.store = tmp
+	return nil
+}
+
+// lockFile takes the cross-process lock on the bookmarks and rereads them if
+// another process saved since. Caller must hold m.mu.
+func (m *LikedSongsManager) lockFile() (*FileLock, error) {
+	lock, err := m.file.Lock()
+	if err != nil {
+		return nil, err
+	}
+	if m.file.Changed() {
+		if err := m.loadLocked(); os.IsNotExist(err) {
+			*m.store = LikedSongsStore{Songs: []LikedSong{}, Version: 1}
+		}
+	}
+	return lock, nil
+}
+
+// Reload rereads the bookmarks if another process saved them. It reports
+// whether there was anything new.
+func (m *LikedSongsManager) Reload() (bool, error) {
+	if !m.file.Changed() {
+		return false, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lock, err := m.lockFile()
+	if err != nil {
+		return false, err
+	}
+	lock.Unlock()
+	return true, nil
+}
+
+// save writes the store to disk. Caller must hold m.mu and the file lock.
+func (m *LikedSongsManager) save() error {
+	data, err := json.MarshalIndent(m.store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal liked songs: %w", err)
+	}
+	return m.file.Write(data, 0644)
+}
+
+// Add bookmarks song as heard on station now. Only the title and its parsed
+// parts are taken from song; the rest is filled in here.
+func (m *LikedSongsManager) Add(song LikedSong, station api.Station) (LikedSong, error) {
+	title := strings.TrimSpace(song.Title)
+	if title == "" {
+		return LikedSong{}, fmt.Errorf("no song title to like")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lock, err := m.lockFile()
+	if err != nil {
+		return LikedSong{}, err
+	}
+	defer lock.Unlock()
+
+	if n := len(m.store.Songs); n > 0 {
+		last := m.store.Songs[n-1]
+		if last.Title == title && last.StationUUID == station.StationUUID {
+			return last, ErrAlreadyLiked
+		}
+	}
+
+	now := time.Now()
+	song = LikedSong{
+		ID:          strconv.FormatInt(now.UnixNano(), 36),
+		Title:       title,
+		Artist:      strings.TrimSpace(song.Artist),
+		Track:       strings.TrimSpace(song.Track),
+		Album:       strings.TrimSpace(song.Album),
+		StationName: station.TrimName(),
+		StationUUID: station.StationUUID,
+		LikedAt:     now,
+	}
+	m.store.Songs = append(m.store.Songs, song)
+	if err := m.save(); err != nil {
+		m.store.Songs = m.store.Songs[:len(m.store.Songs)-1]
+		return LikedSong{}, err
+	}
+	return song, nil
+}
+
+// Delete removes the bookmark with the given ID.
+func (m *LikedSongsManager) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lock, err := m.lockFile()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	for i, s := range m.store.Songs {
+		if s.ID != id {
+			continue
+		}
+		prev := m.store.Songs
+		m.store.Songs = append(append([]LikedSong{}, prev[:i]...), prev[i+1:]...)
+		if err := m.save(); err != nil {
+			m.store.Songs = prev
+			return err
+		}
+		return nil
+	}
+	return fmt.Errorf("liked song %q not found", id)
+}
+
+// All returns every bookmark, newest first.
+func (m *LikedSongsManager) All() []LikedSong {
+	return m.Search("")
+}
+
+// Search returns bookmarks whose title, artist, album or station contains query
+// (case-insensitive), newest first. An empty query matches everything.
+func (m *LikedSongsManager) Search(query string) []LikedSong {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	out := make([]LikedSong, 0, len(m.store.Songs))
+	for i := len(m.store.Songs) - 1; i >= 0; i-- {
+		s := m.store.Songs[i]
+		if query == "" ||
+			strings.Contains(strings.ToLower(s.Title), query) ||
+			strings.Contains(strings.ToLower(s.Artist), query) ||
+			strings.Contains(strings.ToLower(s.Album), query) ||
+			strings.Contains(strings.ToLower(s.StationName), query) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Count returns the number of bookmarks.
+func (m *LikedSongsManager) Count() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.store.Songs)
+}
+
+// ExportLikedSongs writes songs to w in the given format: CSV with a header
+// row, a JSON array, or one search query per line for pasting into a
+// streaming service.
+func ExportLikedSongs(w io.Writer, songs []LikedSong, format string) error {
+	switch format {
+	case LikedSongsFormatCSV:
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"title", "artist", "track", "album", "station", "station_uuid", "liked_at"})
+		for _, s := range songs {
+			_ = cw.Write([]string{s.Title, s.Artist, s.Track, s.Album, s.StationName, s.StationUUID, s.LikedAt.Format(time.RFC3339)})
+		}
+		cw.Flush()
+		return cw.Error()
+	case LikedSongsFormatJSON:
+		if songs == nil {
+			songs = []LikedSong{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(songs)
+	case LikedSongsFormatQueries:
+		seen := make(map[string]bool)
+		for _, s := range songs {
+			q := s.query()
+			if q == "" || seen[strings.ToLower(q)] {
+				continue
+			}
+			seen[strings.ToLower(q)] = true
+			if _, err := fmt.Fprintln(w, q); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+// DefaultLikedSongsExportPath returns ~/tera-liked-songs-<date>.<format>.
+func DefaultLikedSongsExportPath(format string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	filename := fmt.Sprintf("tera-liked-songs-%s.%s", time.Now().Format("2006-01-02"), format)
+	return filepath.Join(home, filename), nil
+}
@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+func TestLikedSongsManager(t *testing.T) {
+	jazz := api.Station{StationUUID: "uuid-jazz", Name: "  Jazz FM  "}
+	rock := api.Station{StationUUID: "uuid-rock", Name: "Rock Radio"}
+
+	t.Run("AddAndPersist", func(t *testing.T) {
+		dir := t.TempDir()
+		mgr, _ := NewLikedSongsManager(dir)
+
+		song, err := mgr.Add(LikedSong{Title: "Miles Davis - So What"}, jazz)
+		if err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		if song.StationName != "Jazz FM" || song.StationUUID != "uuid-jazz" || song.ID == "" {
+			t.Errorf("unexpected song: %+v", song)
+		}
+
+		reloaded, _ := NewLikedSongsManager(dir)
+		if reloaded.Count() != 1 || reloaded.All()[0].Title != "Miles Davis - So What" {
+			t.Errorf("bookmark not persisted: %+v", reloaded.All())
+		}
+	})
+
+	t.Run("RejectsEmptyAndRepeatedLikes", func(t *testing.T) {
+		mgr, _ := NewLikedSongsManager(t.TempDir())
+		if _, err := mgr.Add(LikedSong{Title: "   "}, jazz); err == nil {
+			t.Error("expected error for empty title")
+		}
+		if _, err := mgr.Add(LikedSong{Title: "Song A"}, jazz); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := mgr.Add(LikedSong{Title: "Song A"}, jazz); !errors.Is(err, ErrAlreadyLiked) {
+			t.Errorf("expected ErrAlreadyLiked, got %v", err)
+		}
+		// The same title on another station is a separate bookmark.
+		if _, err := mgr.Add(LikedSong{Title: "Song A"}, rock); err != nil {
+			t.Errorf("same title on another station: %v", err)
+		}
+		if mgr.Count() != 2 {
+			t.Errorf("expected 2 bookmarks, got %d", mgr.Count())
+		}
+	})
+
+	t.Run("SearchNewestFirst", func(t *testing.T) {
+		mgr, _ := NewLikedSongsManager(t.TempDir())
+		for _, title := range []string{"Blue in Green", "Back in Black", "Blue Train"} {
+			st := jazz
+			if strings.Contains(title, "Black") {
+				st = rock
+			}
+			if _, err := mgr.Add(LikedSong{Title: title}, st); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got := mgr.Search("blue")
+		if len(got) != 2 || got[0].Title != "Blue Train" || got[1].Title != "Blue in Green" {
+			t.Errorf("unexpected search result: %+v", got)
+		}
+		if got := mgr.Search("rock radio"); len(got) != 1 || got[0].Title != "Back in Black" {
+			t.Errorf("station search: %+v", got)
+		}
+		if got := mgr.Search(""); len(got) != 3 {
+			t.Errorf("empty query should match all, got %d", len(got))
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		dir := t.TempDir()
+		mgr, _ := NewLikedSongsManager(dir)
+		a, _ := mgr.Add(LikedSong{Title: "Song A"}, jazz)
+		if _, err := mgr.Add(LikedSong{Title: "Song B"}, jazz); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := mgr.Delete(a.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if err := mgr.Delete(a.ID); err == nil {
+			t.Error("expected error deleting a missing bookmark")
+		}
+		reloaded, _ := NewLikedSongsManager(dir)
+		if all := reloaded.All(); len(all) != 1 || all[0].Title != "Song B" {
+			t.Errorf("unexpected bookmarks after delete: %+v", all)
+		}
+	})
+}
+
+func TestExportLikedSongs(t *testing.T) {
+	mgr, _ := NewLikedSongsManager(t.TempDir())
+	st := api.Station{StationUUID: "uuid-1", Name: "Jazz, Blues & More"}
+	for _, title := range []string{"Nina Simone - Feeling Good", "nina simone - feeling  good", "Etta James - At Last"} {
+		if _, err := mgr.Add(LikedSong{Title: title}, st); err != nil {
+			t.Fatal(err)
+		}
+	}
+	songs := mgr.All()
+
+	var csvOut strings.Builder
+	if err := ExportLikedSongs(&csvOut, songs, LikedSongsFormatCSV); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(lines) != 4 || lines[0] != "title,artist,track,album,station,station_uuid,liked_at" {
+		t.Errorf("unexpected CSV:\n%s", csvOut.String())
+	}
+	if !strings.Contains(lines[1], `"Jazz, Blues & More"`) {
+		t.Errorf("station with a comma should be quoted: %s", lines[1])
+	}
+
+	var jsonOut strings.Builder
+	if err := ExportLikedSongs(&jsonOut, songs, LikedSongsFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []LikedSong
+	if err := json.Unmarshal([]byte(jsonOut.String()), &decoded); err != nil || len(decoded) != 3 {
+		t.Errorf("JSON export did not round-trip: %v %+v", err, decoded)
+	}
+
+	var queries strings.Builder
+	if err := ExportLikedSongs(&queries, songs, LikedSongsFormatQueries); err != nil {
+		t.Fatal(err)
+	}
+	want := "Etta James - At Last\nnina simone - feeling good\n"
+	if queries.String() != want {
+		t.Errorf("queries = %q, want %q (duplicates collapsed)", queries.String(), want)
+	}
+
+	if err := ExportLikedSongs(&queries, songs, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestLikedSongsParsedParts(t *testing.T) {
+	mgr, _ := NewLikedSongsManager(t.TempDir())
+	st := api.Station{StationUUID: "uuid-1", Name: "Trip Hop Radio"}
+	song, err := mgr.Add(LikedSong{
+		Title:  "NOW PLAYING: Massive Attack - Teardrop",
+		Artist: "Massive Attack",
+		Track:  "Teardrop",
+		Album:  "Mezzanine",
+	}, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if song.Display() != "Massive Attack - Teardrop (Mezzanine)" {
+		t.Errorf("Display() = %q", song.Display())
+	}
+	if got := mgr.Search("mezzanine"); len(got) != 1 {
+		t.Errorf("expected album search to match, got %d", len(got))
+	}
+
+	var queries strings.Builder
+	if err := ExportLikedSongs(&queries, mgr.All(), LikedSongsFormatQueries); err != nil {
+		t.Fatal(err)
+	}
+	if queries.String() != "Massive Attack Teardrop\n" {
+		t.Errorf("query should use the parsed parts, got %q", queries.String())
+	}
+
+	if (LikedSong{Title: "Station ID"}).Display() != "Station ID" {
+		t.Error("Display() should fall back to the raw title")
+	}
+}
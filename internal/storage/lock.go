@@ -1,3 +1,142 @@
 package storage
 
-// atomicWriteFile is defined in atomic_write.go.
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Several TERA processes can share the data directory: two TUIs, or the TUI
+// and `tera play`. Stores that read a data file, change it and write it back
+// hold an advisory lock on the file meanwhile, and reread it first if another
+// process saved it since, so one process's save does not drop another's.
+
+// FileLock is a held lock on a data file; see LockFile.
+type FileLock struct {
+	f *os.File
+}
+
+// LockFile waits for and takes the lock guarding path, a data file or the
+// favorites directory. The lock is the hidden file ".<name>.lock" next to
+// path; it is advisory, so only TERA honors it.
+func LockFile(path string) (*FileLock, error) {
+	dir, name := filepath.Split(filepath.Clean(path))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, "."+name+".lock"), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock for %s: %w", name, err)
+	}
+	if err := lockFD(f); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", name, err)
+	}
+	return &FileLock{f: f}, nil
+}
+
+// Unlock releases the lock.
+func (l *FileLock) Unlock() {
+	_ = unlockFD(l.f)
+	_ = l.f.Close()
+}
+
+// sameVersion reports whether a and b describe the same version of a file.
+// Saves replace files by renaming, so a new save is a new file even when its
+// size and modification time happen to match. Two nils, for a file that
+// did not exist either time, are the same version.
+func sameVersion(a, b os.FileInfo) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return os.SameFile(a, b) && a.ModTime().Equal(b.ModTime()) && a.Size() == b.Size()
+}
+
+// statFile returns the version of path on disk, or nil if it can't be read.
+func statFile(path string) os.FileInfo {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	return info
+}
+
+// SharedFile is a data file other TERA processes may write too. It remembers
+// which version this process last read or wrote, so a store can tell when it
+// must read the file again.
+type SharedFile struct {
+	path   string
+	mu     sync.Mutex
+	onDisk os.FileInfo
+}
+
+// NewSharedFile returns the shared file at path.
+func NewSharedFile(path string) *SharedFile {
+	return &SharedFile{path: path}
+}
+
+// Path returns the path of the file.
+func (f *SharedFile) Path() string {
+	return f.path
+}
+
+// Lock takes the file's cross-process lock; see LockFile.
+func (f *SharedFile) Lock() (*FileLock, error) {
+	return LockFile(f.path)
+}
+
+// Read reads the file and remembers the version read. A missing file is
+// returned as os.ErrNotExist and remembered as such.
+func (f *SharedFile) Read() ([]byte, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			f.remember(nil)
+		}
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	// Stat the open file, not the path, so the version matches the content
+	// even if the file is replaced while it is read.
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	f.remember(info)
+	return data, nil
+}
+
+// Changed reports whether the file on disk is not the version last read or
+// written through f.
+func (f *SharedFile) Changed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !sameVersion(statFile(f.path), f.onDisk)
+}
+
+// Write replaces the file with data and remembers the new version. Callers
+// hold the lock, so the file can't change between the write and the stat.
+func (f *SharedFile) Write(data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return err
+	}
+	if err := atomicWriteFile(f.path, data, perm); err != nil {
+		return err
+	}
+	f.remember(statFile(f.path))
+	noteOwnWrite(f.path)
+	return nil
+}
+
+func (f *SharedFile) remember(info os.FileInfo) {
+	f.mu.Lock()
+	f.onDisk = info
+	f.mu.Unlock()
+}
@@ -0,0 +1,12 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package storage
+
+import "os"
+
+// Platforms without flock run unlocked; saves still replace files
+// atomically.
+
+func lockFD(*os.File) error { return nil }
+
+func unlockFD(*os.File) error { return nil }
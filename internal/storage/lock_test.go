@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockFileSerializesHolders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "blocklist.json")
+
+	first, err := LockFile(path)
+	if err != nil {
+		t.Fatalf("LockFile: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(path), ".blocklist.json.lock")); err != nil {
+		t.Fatalf("lock file not created: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		second, err := LockFile(path)
+		if err != nil {
+			t.Errorf("second LockFile: %v", err)
+			return
+		}
+		mu.Lock()
+		order = append(order, "second")
+		mu.Unlock()
+		second.Unlock()
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	order = append(order, "first")
+	mu.Unlock()
+	first.Unlock()
+	<-done
+
+	if len(order) != 2 || order[0] != "first" {
+		t.Errorf("lock order = %v, want the first holder to finish first", order)
+	}
+}
+
+func TestSharedFileChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.json")
+	f := NewSharedFile(path)
+
+	if _, err := f.Read(); !os.IsNotExist(err) {
+		t.Fatalf("Read of missing file: %v", err)
+	}
+	if f.Changed() {
+		t.Error("missing file reported as changed")
+	}
+
+	if err := f.Write([]byte(`{"a":1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if f.Changed() {
+		t.Error("own write reported as changed")
+	}
+
+	// Another process replaces the file
+	if err := atomicWriteFile(path, []byte(`{"a":2}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !f.Changed() {
+		t.Fatal("replaced file not reported as changed")
+	}
+	data, err := f.Read()
+	if err != nil || string(data) != `{"a":2}` {
+		t.Fatalf("Read = %q, %v", data, err)
+	}
+	if f.Changed() {
+		t.Error("file reported as changed after reading it")
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if !f.Changed() {
+		t.Error("removed file not reported as changed")
+	}
+}
+
+func TestRatingsManagersShareFile(t *testing.T) {
+	dir := t.TempDir()
+	a, err := NewRatingsManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = a.Close() }()
+	b, err := NewRatingsManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = b.Close() }()
+
+	if err := a.SetRating(testRatingStation("one"), 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Save(); err != nil {
+		t.Fatal(err)
+	}
+	// b never read a's rating, but its save must keep it
+	if err := b.SetRating(testRatingStation("two"), 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := a.Reload()
+	if err != nil || !reloaded {
+		t.Fatalf("Reload = %v, %v; want true", reloaded, err)
+	}
+	if r := a.GetRating("two"); r == nil || r.Rating != 3 {
+		t.Errorf("a does not see b's rating: %+v", r)
+	}
+	if reloaded, _ := a.Reload(); reloaded {
+		t.Error("second Reload reported a change")
+	}
+
+	c, err := NewRatingsManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+	if c.GetTotalRated() != 2 {
+		t.Errorf("expected both ratings on disk, got %d", c.GetTotalRated())
+	}
+}
@@ -16,6 +16,7 @@ var (
 type FavoritesList struct {
 	Name     string        `json:"-"`
 	Stations []api.Station `json:"stations"`
+	Ordered  bool          `json:"-"` // Stations are in manual order, not sorted by name
 }
 
 // Config represents application configuration
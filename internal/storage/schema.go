@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The data files with a registered DataSchema record the version of their
+// layout. When the layout changes, a numbered migration is added to the
+// file's DataSchema; stores run the pending ones when they load the file, and
+// `tera data migrate` runs them on demand. Favorite lists, which are plain
+// arrays, the search history and the sync preferences have no version and
+// are read as they are.
+
+// Migration upgrades a data file to Version from the version before it. Up
+// edits the decoded file in place; numbers are json.Number. It must leave a
+// file that is already in the new layout as it is.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(doc map[string]any) error
+}
+
+// DataSchema lists the migrations of one data file, in version order from 1.
+type DataSchema struct {
+	File       string // name in the data directory
+	Migrations []Migration
+	// FormatVersion spells a version the way the file stores it. Nil writes
+	// a plain number.
+	FormatVersion func(version int) any
+}
+
+// Current returns the version the last migration leaves the file at.
+func (s *DataSchema) Current() int {
+	if len(s.Migrations) == 0 {
+		return 0
+	}
+	return s.Migrations[len(s.Migrations)-1].Version
+}
+
+var (
+	schemasMu   sync.RWMutex
+	dataSchemas = make(map[string]*DataSchema)
+)
+
+// RegisterDataSchema adds the schema of a data file kept outside this
+// package. It panics if the migrations are not numbered 1, 2, 3...
+func RegisterDataSchema(s *DataSchema) {
+	for i, m := range s.Migrations {
+		if m.Version != i+1 || m.Up == nil {
+			panic(fmt.Sprintf("storage: migration %d of %s is out of order", i+1, s.File))
+		}
+	}
+	schemasMu.Lock()
+	defer schemasMu.Unlock()
+	dataSchemas[s.File] = s
+}
+
+// DataSchemas returns the registered schemas, sorted by file name.
+func DataSchemas() []*DataSchema {
+	schemasMu.RLock()
+	defer schemasMu.RUnlock()
+	schemas := make([]*DataSchema, 0, len(dataSchemas))
+	for _, s := range dataSchemas {
+		schemas = append(schemas, s)
+	}
+	sort.Slice(schemas, func(i, j int) bool { return schemas[i].File < schemas[j].File })
+	return schemas
+}
+
+func schemaFor(file string) *DataSchema {
+	schemasMu.RLock()
+	defer schemasMu.RUnlock()
+	return dataSchemas[file]
+}
+
+// MigrationReport says what migrating one file did, or would do.
+type MigrationReport struct {
+	Path    string
+	From    int
+	To      int
+	Pending []Migration // migrations run, or to run on a dry run
+	Backup  string      // copy of the file before migrating; empty when nothing ran
+	Missing bool        // the file does not exist
+	Failed  bool        // a migration failed, so the file was left as it was
+}
+
+// Newer reports whether the file was written by a newer TERA, whose layout
+// this one does not know. Such files are left alone.
+func (r MigrationReport) Newer() bool {
+	return r.From > r.To
+}
+
+// MigrateFile brings the data file at path up to the current version of its
+// schema. The file is only replaced once every migration succeeded, so a
+// failure leaves it as it was. Before that the original is copied to
+// "<name>.v<version>.bak" next to it; an older copy by that name is kept, as
+// it is closer to the original. With dryRun the migrations still run, on
+// the copy read into memory, so failures are reported, but nothing is
+// written.
+func MigrateFile(path string, schema *DataSchema, dryRun bool) (MigrationReport, error) {
+	report := MigrationReport{Path: path, To: schema.Current()}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		report.Missing = true
+		return report, nil
+	}
+	if !dryRun {
+		lock, err := LockFile(path)
+		if err != nil {
+			return report, err
+		}
+		defer lock.Unlock()
+	}
+
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		report.Missing = true
+		return report, nil
+	}
+	if err != nil {
+		return report, err
+	}
+	doc, version, err := decodeVersioned(raw)
+	if err != nil {
+		return report, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	report.From = version
+	for _, m := range schema.Migrations {
+		if m.Version > version {
+			report.Pending = append(report.Pending, m)
+		}
+	}
+	if len(report.Pending) == 0 {
+		return report, nil
+	}
+
+	for _, m := range report.Pending {
+		if err := m.Up(doc); err != nil {
+			report.Failed = true
+			return report, fmt.Errorf("migration %d of %s (%s): %w", m.Version, filepath.Base(path), m.Description, err)
+		}
+		doc["version"] = schema.formatVersion(m.Version)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		report.Failed = true
+		return report, err
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", path, version)
+	if _, err := os.Stat(backup); os.IsNotExist(err) {
+		if err := atomicWriteFile(backup, raw, 0644); err != nil {
+			return report, fmt.Errorf("failed to back up %s: %w", filepath.Base(path), err)
+		}
+	}
+	report.Backup = backup
+	if err := atomicWriteFile(path, data, 0644); err != nil {
+		return report, err
+	}
+	noteOwnWrite(path)
+	return report, nil
+}
+
+// MigrateDataDir migrates every registered data file in dataPath.
+func MigrateDataDir(dataPath string, dryRun bool) ([]MigrationReport, error) {
+	var reports []MigrationReport
+	var errs []error
+	for _, s := range DataSchemas() {
+		report, err := MigrateFile(filepath.Join(dataPath, s.File), s, dryRun)
+		reports = append(reports, report)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return reports, errors.Join(errs...)
+}
+
+// MigrateOnLoad runs the pending migrations of the data file at path before a
+// store reads it. If one fails, a warning is printed and the file is left
+// unchanged, to be read as it is.
+func MigrateOnLoad(path string) {
+	schema := schemaFor(filepath.Base(path))
+	if schema == nil {
+		return
+	}
+	if _, err := MigrateFile(path, schema, false); err != nil {
+		fmt.Fprintf(os.Stderr, "[WARN] %v (file left unchanged; run 'tera data migrate' to retry)\n", err)
+	}
+}
+
+func (s *DataSchema) formatVersion(version int) any {
+	if s.FormatVersion != nil {
+		return s.FormatVersion(version)
+	}
+	return version
+}
+
+// decodeVersioned decodes a data file and reads its version: a number, or a
+// string such as "1.0" whose major part counts. No version is version 0.
+func decodeVersioned(raw []byte) (map[string]any, int, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, 0, err
+	}
+	if doc == nil {
+		doc = make(map[string]any)
+	}
+
+	var text string
+	switch v := doc["version"].(type) {
+	case nil:
+		return doc, 0, nil
+	case json.Number:
+		text = v.String()
+	case string:
+		text = v
+	default:
+		return nil, 0, fmt.Errorf("version is not a number")
+	}
+	major, _, _ := strings.Cut(text, ".")
+	version, err := strconv.Atoi(major)
+	if err != nil || version < 0 {
+		return nil, 0, fmt.Errorf("invalid version %q", text)
+	}
+	return doc, version, nil
+}
+
+// numberValue reads a decoded JSON number, or one a migration set, as an int.
+func numberValue(v any) (int, bool) {
+	if i, ok := v.(int); ok {
+		return i, true
+	}
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	if i, err := n.Int64(); err == nil {
+		return int(i), true
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// ensureObject makes doc[key] an object if it is missing or null.
+func ensureObject(doc map[string]any, key string) error {
+	switch doc[key].(type) {
+	case nil:
+		doc[key] = map[string]any{}
+	case map[string]any:
+	default:
+		return fmt.Errorf("%s is not an object", key)
+	}
+	return nil
+}
+
+// ensureArray makes doc[key] an array if it is missing or null.
+func ensureArray(doc map[string]any, key string) error {
+	switch doc[key].(type) {
+	case nil:
+		doc[key] = []any{}
+	case []any:
+	default:
+		return fmt.Errorf("%s is not a list", key)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestMigrateFileBacksUpAndIsIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ratingsFileName)
+	original := `{"ratings":{"a":{"rating":9},"b":null,"c":{"rated_at":"2024-01-01T00:00:00Z"},"d":{"rating":3}}}`
+	writeTestFile(t, path, original)
+	schema := schemaFor(ratingsFileName)
+
+	report, err := MigrateFile(path, schema, true)
+	if err != nil || report.From != 0 || report.To != 1 || len(report.Pending) != 1 {
+		t.Fatalf("dry run = %+v, %v", report, err)
+	}
+	if readTestFile(t, path) != original {
+		t.Fatal("dry run changed the file")
+	}
+
+	report, err = MigrateFile(path, schema, false)
+	if err != nil {
+		t.Fatalf("MigrateFile: %v", err)
+	}
+	if report.Backup != path+".v0.bak" || readTestFile(t, report.Backup) != original {
+		t.Fatalf("backup %q does not hold the original", report.Backup)
+	}
+	var store RatingsStore
+	if err := json.Unmarshal([]byte(readTestFile(t, path)), &store); err != nil {
+		t.Fatal(err)
+	}
+	if store.Version != 1 || len(store.Ratings) != 2 || store.Ratings["a"].Rating != 5 || store.Ratings["d"].Rating != 3 {
+		t.Errorf("migrated store = %+v", store)
+	}
+
+	migrated := readTestFile(t, path)
+	report, err = MigrateFile(path, schema, false)
+	if err != nil || len(report.Pending) != 0 || report.Backup != "" {
+		t.Errorf("second run = %+v, %v", report, err)
+	}
+	if readTestFile(t, path) != migrated {
+		t.Error("second run changed the file")
+	}
+}
+
+func TestMigrationsAreIdempotent(t *testing.T) {
+	samples := map[string]string{
+		metadataFileName:     `{"stations":{"a":{"play_count":2},"b":null}}`,
+		ratingsFileName:      `{"ratings":{"a":{"rating":0}}}`,
+		tagsFileName:         `{"station_tags":{"a":{"tags":["Jazz","jazz","  Late   Night ",""]}},"tag_playlists":{"p":{"tags":["JAZZ"]}}}`,
+		StationNotesFileName: `{"notes":{"a":{"name":"Mine"},"b":null}}`,
+		LikedSongsFileName:   `{}`,
+		JournalFileName:      `{"next_id":3}`,
+	}
+	for _, schema := range DataSchemas() {
+		sample, ok := samples[schema.File]
+		if !ok {
+			continue // registered by another package
+		}
+		for _, m := range schema.Migrations {
+			once, _, err := decodeVersioned([]byte(sample))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := m.Up(once); err != nil {
+				t.Fatalf("%s migration %d: %v", schema.File, m.Version, err)
+			}
+			first, _ := json.Marshal(once)
+			if err := m.Up(once); err != nil {
+				t.Fatalf("%s migration %d again: %v", schema.File, m.Version, err)
+			}
+			second, _ := json.Marshal(once)
+			if string(first) != string(second) {
+				t.Errorf("%s migration %d is not idempotent:\n%s\n%s", schema.File, m.Version, first, second)
+			}
+		}
+	}
+}
+
+func TestMigrateTagsNormalizes(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, tagsFileName),
+		`{"station_tags":{"a":{"tags":["Jazz","jazz","  Late   Night "]},"b":{"tags":["rock"]}},"all_tags":["stale"]}`)
+
+	tm, err := NewTagsManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = tm.Close() }()
+
+	if got := tm.GetTags("a"); !reflect.DeepEqual(got, []string{"jazz", "late night"}) {
+		t.Errorf("tags of a = %v", got)
+	}
+	if got := tm.GetAllTags(); !reflect.DeepEqual(got, []string{"jazz", "late night", "rock"}) {
+		t.Errorf("all tags = %v", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, tagsFileName+".v0.bak")); err != nil {
+		t.Errorf("no backup: %v", err)
+	}
+}
+
+func TestMigrateFileFailureLeavesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.json")
+	original := `{"items":[1,2],"version":1}`
+	writeTestFile(t, path, original)
+	schema := &DataSchema{
+		File: "test.json",
+		Migrations: []Migration{
+			{Version: 1, Description: "first", Up: func(map[string]any) error { return nil }},
+			{Version: 2, Description: "second", Up: func(doc map[string]any) error {
+				doc["items"] = nil
+				return nil
+			}},
+			{Version: 3, Description: "third", Up: func(map[string]any) error { return errors.New("boom") }},
+		},
+	}
+
+	// A dry run runs the migrations too, so it sees the failure
+	report, err := MigrateFile(path, schema, true)
+	if err == nil || !report.Failed {
+		t.Fatalf("dry run = %+v, %v; expected the failing migration to be reported", report, err)
+	}
+	if readTestFile(t, path) != original {
+		t.Error("dry run changed the file")
+	}
+	if _, err := os.Stat(path + ".v1.bak"); !os.IsNotExist(err) {
+		t.Error("dry run wrote a backup")
+	}
+
+	if report, err := MigrateFile(path, schema, false); err == nil || !report.Failed {
+		t.Fatal("expected the failing migration to be reported")
+	}
+	if readTestFile(t, path) != original {
+		t.Error("failed migration changed the file")
+	}
+
+	// Once the migration is fixed, running again picks up where it failed
+	schema.Migrations[2].Up = func(map[string]any) error { return nil }
+	report, err = MigrateFile(path, schema, false)
+	if err != nil || report.From != 1 || len(report.Pending) != 2 || report.Failed {
+		t.Fatalf("retry = %+v, %v", report, err)
+	}
+	if readTestFile(t, path+".v1.bak") != original {
+		t.Error("retry did not keep the original")
+	}
+}
+
+func TestMigrateFileVersions(t *testing.T) {
+	dir := t.TempDir()
+	schema := &DataSchema{
+		File: "test.json",
+		Migrations: []Migration{{Version: 1, Description: "add list", Up: func(doc map[string]any) error {
+			return ensureArray(doc, "items")
+		}}},
+		FormatVersion: func(v int) any { return "1.0" },
+	}
+
+	// A version spelled as text counts by its major part
+	current := filepath.Join(dir, "current.json")
+	writeTestFile(t, current, `{"version":"1.0"}`)
+	if report, err := MigrateFile(current, schema, false); err != nil || report.From != 1 || len(report.Pending) != 0 {
+		t.Errorf("current file = %+v, %v", report, err)
+	}
+
+	// A file from a newer TERA is left alone
+	newer := filepath.Join(dir, "newer.json")
+	writeTestFile(t, newer, `{"version":"4.2"}`)
+	report, err := MigrateFile(newer, schema, false)
+	if err != nil || !report.Newer() || len(report.Pending) != 0 {
+		t.Errorf("newer file = %+v, %v", report, err)
+	}
+
+	old := filepath.Join(dir, "old.json")
+	writeTestFile(t, old, `{}`)
+	if _, err := MigrateFile(old, schema, false); err != nil {
+		t.Fatal(err)
+	}
+	doc, version, err := decodeVersioned([]byte(readTestFile(t, old)))
+	if err != nil || version != 1 || doc["version"] != "1.0" {
+		t.Errorf("old file migrated to %v (%d), %v", doc, version, err)
+	}
+
+	if report, err := MigrateFile(filepath.Join(dir, "missing.json"), schema, false); err != nil || !report.Missing {
+		t.Errorf("missing file = %+v, %v", report, err)
+	}
+}
@@ -0,0 +1,286 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SessionsDirName is the listening session log inside the data directory.
+// Sessions are appended to one JSON Lines file per month.
+const SessionsDirName = "sessions"
+
+// sessionFilePrefix and sessionFileExt frame the month in a log file name,
+// as in "sessions-2026-10.jsonl".
+const (
+	sessionFilePrefix = "sessions-"
+	sessionFileExt    = ".jsonl"
+)
+
+// Stop reasons recorded with each listening session.
+const (
+	StopReasonStopped  = "stopped"  // The user stopped playback
+	StopReasonSwitched = "switched" // Another station started
+	StopReasonEnded    = "ended"    // The stream ended or mpv exited
+	StopReasonNetwork  = "network"  // The network went away
+	StopReasonDataCap  = "data_cap" // The monthly data cap was reached
+	StopReasonExit     = "exit"     // TERA quit while playing
+)
+
+// ListeningSession is one uninterrupted play of a station.
+type ListeningSession struct {
+	StationUUID string    `json:"uuid"`
+	StationName string    `json:"name,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Source      string    `json:"source,omitempty"` // Screen or command the play started from
+	StopReason  string    `json:"stop_reason"`
+}
+
+// Duration returns how long the session lasted.
+func (s ListeningSession) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// SessionLog appends listening sessions to monthly files and drops the
+// months that fall outside the retention period.
+type SessionLog struct {
+	dir           string
+	retentionDays int // 0 keeps sessions forever
+	mu            sync.Mutex
+	currentFile   string // file of the last append; a new one means the log rotated
+}
+
+// NewSessionLog returns the session log kept in dataPath. retentionDays of 0
+// keeps every session.
+func NewSessionLog(dataPath string, retentionDays int) *SessionLog {
+	return &SessionLog{
+		dir:           filepath.Join(dataPath, SessionsDirName),
+		retentionDays: retentionDays,
+	}
+}
+
+// OpenConfiguredSessionLog returns the session log for dataPath using the
+// unified config, or nil when session logging is disabled.
+func OpenConfiguredSessionLog(dataPath string) *SessionLog {
+	cfg, _ := LoadSessionLogConfigFromUnified()
+	if !cfg.Enabled {
+		return nil
+	}
+	return NewSessionLog(dataPath, cfg.RetentionDays)
+}
+
+// sessionFileName returns the log file name for the month of t.
+func sessionFileName(t time.Time) string {
+	return sessionFilePrefix + UsageMonth(t) + sessionFileExt
+}
+
+// IsSessionLogFile reports whether name is a monthly session log file name.
+func IsSessionLogFile(name string) bool {
+	if !strings.HasPrefix(name, sessionFilePrefix) || !strings.HasSuffix(name, sessionFileExt) {
+		return false
+	}
+	month := strings.TrimSuffix(strings.TrimPrefix(name, sessionFilePrefix), sessionFileExt)
+	_, err := time.Parse(usageMonthFormat, month)
+	return err == nil
+}
+
+// cutoff returns the oldest start time kept, or the zero time when sessions
+// are kept forever.
+func (l *SessionLog) cutoff(now time.Time) time.Time {
+	if l.retentionDays <= 0 {
+		return time.Time{}
+	}
+	y, m, d := now.AddDate(0, 0, -l.retentionDays).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+}
+
+// Append adds s to the log file for the month it started in. The first
+// append to a new file also prunes expired months.
+func (l *SessionLog) Append(s ListeningSession) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create sessions directory: %w", err)
+	}
+	name := sessionFileName(s.Start.Local())
+	if name != l.currentFile {
+		l.currentFile = name
+		if err := l.pruneLocked(time.Now()); err != nil {
+			return err
+		}
+	}
+
+	// A single write of a whole line keeps appends from other tera
+	// processes from interleaving.
+	f, err := os.OpenFile(filepath.Join(l.dir, name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open session log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write session log: %w", err)
+	}
+	return f.Close()
+}
+
+// Prune removes the monthly files whose sessions all started before the
+// retention period.
+func (l *SessionLog) Prune(now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.pruneLocked(now)
+}
+
+func (l *SessionLog) pruneLocked(now time.Time) error {
+	cutoff := l.cutoff(now)
+	if cutoff.IsZero() {
+		return nil
+	}
+	// Months before the cutoff's month end before the cutoff
+	oldest := sessionFileName(cutoff)
+	files, err := l.files()
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		if name >= oldest {
+			break
+		}
+		if err := os.Remove(filepath.Join(l.dir, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// files returns the monthly log file names, oldest first.
+func (l *SessionLog) files() ([]string, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read sessions directory: %w", err)
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && IsSessionLogFile(e.Name()) {
+			files = append(files, e.Name())
+		}
+	}
+	// "sessions-YYYY-MM.jsonl" sorts chronologically as a string
+	sort.Strings(files)
+	return files, nil
+}
+
+// readFile returns the sessions in one log file that are inside the
+// retention period. Lines that do not parse, such as one cut short by a
+// crash, are skipped.
+func (l *SessionLog) readFile(name string, cutoff time.Time) ([]ListeningSession, error) {
+	f, err := os.Open(filepath.Join(l.dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open session log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var sessions []ListeningSession
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var s ListeningSession
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil || s.Start.IsZero() {
+			continue
+		}
+		if s.Start.Before(cutoff) {
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read session log: %w", err)
+	}
+	return sessions, nil
+}
+
+// Day returns the sessions that started on day's calendar date in day's
+// location, oldest first.
+func (l *SessionLog) Day(day time.Time) ([]ListeningSession, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	y, m, d := day.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+
+	all, err := l.readFile(sessionFileName(start), l.cutoff(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	var sessions []ListeningSession
+	for _, s := range all {
+		if !s.Start.Before(start) && s.Start.Before(end) {
+			sessions = append(sessions, s)
+		}
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Start.Before(sessions[j].Start)
+	})
+	return sessions, nil
+}
+
+// All returns every session inside the retention period, oldest first.
+func (l *SessionLog) All() ([]ListeningSession, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	files, err := l.files()
+	if err != nil {
+		return nil, err
+	}
+	cutoff := l.cutoff(time.Now())
+	var sessions []ListeningSession
+	for _, name := range files {
+		month, err := l.readFile(name, cutoff)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, month...)
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Start.Before(sessions[j].Start)
+	})
+	return sessions, nil
+}
+
+// Days returns the local dates that have sessions, oldest first, each at
+// midnight.
+func (l *SessionLog) Days() ([]time.Time, error) {
+	sessions, err := l.All()
+	if err != nil {
+		return nil, err
+	}
+	var days []time.Time
+	for _, s := range sessions {
+		y, m, d := s.Start.Local().Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+		if n := len(days); n == 0 || !days[n-1].Equal(day) {
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
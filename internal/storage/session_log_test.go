@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSessionLog_AppendAndBrowse(t *testing.T) {
+	dataPath := t.TempDir()
+	log := NewSessionLog(dataPath, 0)
+
+	today := startOfLocalDay(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+	at := func(day time.Time, hour int) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, 30, 0, 0, time.Local)
+	}
+	sessions := []ListeningSession{
+		{StationUUID: "b", Start: at(today, 10), End: at(today, 11), StopReason: StopReasonStopped},
+		{StationUUID: "a", Start: at(today, 8), End: at(today, 9), StopReason: StopReasonSwitched},
+		{StationUUID: "c", Start: at(yesterday, 23), End: at(today, 1), StopReason: StopReasonExit},
+	}
+	for _, s := range sessions {
+		if err := log.Append(s); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	got, err := log.Day(today)
+	if err != nil {
+		t.Fatalf("Day: %v", err)
+	}
+	if len(got) != 2 || got[0].StationUUID != "a" || got[1].StationUUID != "b" {
+		t.Fatalf("expected today's sessions oldest first, got %+v", got)
+	}
+	if got[0].Duration() != time.Hour {
+		t.Errorf("Duration = %v, want 1h", got[0].Duration())
+	}
+
+	// A session belongs to the day it started on
+	got, _ = log.Day(yesterday)
+	if len(got) != 1 || got[0].StationUUID != "c" {
+		t.Errorf("expected the late-night session yesterday, got %+v", got)
+	}
+
+	days, err := log.Days()
+	if err != nil {
+		t.Fatalf("Days: %v", err)
+	}
+	if len(days) != 2 || !days[0].Equal(yesterday) || !days[1].Equal(today) {
+		t.Errorf("unexpected days: %v", days)
+	}
+}
+
+func TestSessionLog_RetentionAndPrune(t *testing.T) {
+	dataPath := t.TempDir()
+	log := NewSessionLog(dataPath, 30)
+
+	now := time.Now()
+	old := now.AddDate(0, -3, 0)
+	recent := now.Add(-time.Hour)
+	for _, start := range []time.Time{old, now.AddDate(0, 0, -40), recent} {
+		if err := log.Append(ListeningSession{StationUUID: "x", Start: start, End: start.Add(time.Minute)}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	// Sessions past the retention period are hidden even before pruning
+	days, _ := log.Days()
+	if len(days) != 1 || !days[0].Equal(startOfLocalDay(recent)) {
+		t.Errorf("expected only the recent day, got %v", days)
+	}
+
+	if err := log.Prune(now); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataPath, SessionsDirName, sessionFileName(old))); !os.IsNotExist(err) {
+		t.Errorf("expected the expired month to be removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataPath, SessionsDirName, sessionFileName(recent))); err != nil {
+		t.Errorf("expected the current month to be kept: %v", err)
+	}
+}
+
+func TestIsSessionLogFile(t *testing.T) {
+	for name, want := range map[string]bool{
+		"sessions-2026-10.jsonl": true,
+		"sessions-2026-13.jsonl": false,
+		"sessions-2026-10.json":  false,
+		"sessions-../a.jsonl":    false,
+		"other.jsonl":            false,
+	} {
+		if got := IsSessionLogFile(name); got != want {
+			t.Errorf("IsSessionLogFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestMetadataManager_LogsSessions(t *testing.T) {
+	dataPath := t.TempDir()
+	log := NewSessionLog(dataPath, 0)
+	mgr, _ := NewMetadataManager(dataPath)
+	mgr.SetSessionLog(log)
+
+	mgr.SetPlaySource("favorites")
+	_ = mgr.StartPlay(testStation("a"))
+	mgr.SetPlaySource("search")
+	_ = mgr.StartPlay(testStation("b")) // stops "a" as switched
+	_ = mgr.StopPlayWithReason("b", StopReasonNetwork)
+	_ = mgr.StartPlay(testStation("c"))
+	if err := mgr.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	got, err := log.Day(time.Now())
+	if err != nil {
+		t.Fatalf("Day: %v", err)
+	}
+	want := []struct{ uuid, source, reason string }{
+		{"a", "favorites", StopReasonSwitched},
+		{"b", "search", StopReasonNetwork},
+		{"c", "search", StopReasonExit},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d sessions, got %+v", len(want), got)
+	}
+	for i, w := range want {
+		s := got[i]
+		if s.StationUUID != w.uuid || s.Source != w.source || s.StopReason != w.reason {
+			t.Errorf("session %d = %+v, want %+v", i, s, w)
+		}
+		if s.StationName != "Test Station "+w.uuid || s.End.Before(s.Start) {
+			t.Errorf("session %d has a bad name or times: %+v", i, s)
+		}
+	}
+}
+
+// startOfLocalDay returns local midnight on t's date.
+func startOfLocalDay(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
@@ -16,10 +16,12 @@ import (
 
 // StationMetadata tracks listening statistics for a station
 type StationMetadata struct {
-	PlayCount            int       `json:"play_count"`
-	LastPlayed           time.Time `json:"last_played"`
-	FirstPlayed          time.Time `json:"first_played"`
-	TotalDurationSeconds int64     `json:"total_duration_seconds"`
+	PlayCount            int              `json:"play_count"`
+	LastPlayed           time.Time        `json:"last_played"`
+	FirstPlayed          time.Time        `json:"first_played"`
+	TotalDurationSeconds int64            `json:"total_duration_seconds"`
+	TotalBytes           int64            `json:"total_bytes,omitempty"`   // Stream data downloaded
+	MonthlyBytes         map[string]int64 `json:"monthly_bytes,omitempty"` // Data downloaded per "YYYY-MM"
 }
 
 // CachedStation stores essential station info for display in Most Played
@@ -54,8 +56,16 @@ type MetadataManager struct {
 	mu            sync.RWMutex
 	saveMu        sync.Mutex // serializes concurrent Save() calls
 	savePending   atomic.Bool
-	currentPlay   string    // Track current playing station to prevent duplicates
-	playStartTime time.Time // When current play started
+	currentPlay   string        // Track current playing station to prevent duplicates
+	playStartTime time.Time     // When current play started
+	sessionBytes  int64         // Stream data downloaded since the manager was opened
+	sessionLog    *SessionLog   // nil when listening sessions are not logged
+	playSource    string        // Where new plays start from, recorded with each session
+	currentSource string        // playSource when the current play started
+	db            *Database     // nil when stored in station_metadata.json
+	dbImports     atomic.Uint64 // imports of the JSON file the store was loaded after
+	file          *SharedFile   // station_metadata.json
+	dirty         dirtySet      // stations changed since the last save
 	ctx           context.Context
 	cancel        context.CancelFunc
 	wg            sync.WaitGroup
@@ -69,6 +79,8 @@ func NewMetadataManager(dataPath string) (*MetadataManager, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	m := &MetadataManager{
 		dataPath: dataPath,
+		db:       databaseFor(dataPath),
+		file:     NewSharedFile(filepath.Join(dataPath, "station_metadata.json")),
 		store: &MetadataStore{
 			Stations:     make(map[string]*StationMetadata),
 			StationCache: make(map[string]*CachedStation),
@@ -79,6 +91,7 @@ func NewMetadataManager(dataPath string) (*MetadataManager, error) {
 	}
 
 	// Load existing metadata
+	MigrateOnLoad(m.file.Path())
 	if err := m.Load(); err != nil {
 		// If file doesn't exist or is corrupted, start fresh
 		// Log warning but don't fail
@@ -92,29 +105,47 @@ func NewMetadataManager(dataPath string) (*MetadataManager, error) {
 	return m, nil
 }
 
-// getMetadataFilePath returns the full path to the metadata file
-func (m *MetadataManager) getMetadataFilePath() string {
-	return filepath.Join(m.dataPath, "station_metadata.json")
-}
-
 // Load loads metadata from disk
 func (m *MetadataManager) Load() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	filePath := m.getMetadataFilePath()
-	data, err := os.ReadFile(filePath)
+	if m.db != nil {
+		imports := m.db.importCount(metadataFileName)
+		store, err := m.db.loadMetadataStore()
+		if err != nil {
+			return err
+		}
+		m.store = store
+		m.dbImports.Store(imports)
+		return nil
+	}
+
+	store, err := m.readFile()
 	if err != nil {
 		if os.IsNotExist(err) {
 			// File doesn't exist yet, use empty store
 			return nil
 		}
-		return fmt.Errorf("failed to read metadata file: %w", err)
+		return err
+	}
+	m.store = store
+	return nil
+}
+
+// readFile reads station_metadata.json.
+func (m *MetadataManager) readFile() (*MetadataStore, error) {
+	data, err := m.file.Read()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read metadata file: %w", err)
 	}
 
 	var store MetadataStore
 	if err := json.Unmarshal(data, &store); err != nil {
-		return fmt.Errorf("failed to parse metadata file: %w", err)
+		return nil, fmt.Errorf("failed to parse metadata file: %w", err)
 	}
 
 	// Ensure maps are initialized
@@ -124,9 +155,70 @@ func (m *MetadataManager) Load() error {
 	if store.StationCache == nil {
 		store.StationCache = make(map[string]*CachedStation)
 	}
+	return &store, nil
+}
 
-	m.store = &store
-	return nil
+// syncFromDisk takes in what another process saved to station_metadata.json
+// since this manager last read or wrote it. Stations changed here and not
+// saved yet keep their values. Caller must hold saveMu and the file lock.
+func (m *MetadataManager) syncFromDisk() (bool, error) {
+	if !m.file.Changed() {
+		return false, nil
+	}
+	store, err := m.readFile()
+	if os.IsNotExist(err) {
+		store, err = &MetadataStore{
+			Stations:     make(map[string]*StationMetadata),
+			StationCache: make(map[string]*CachedStation),
+			Version:      1,
+		}, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return m.replaceStore(store), nil
+}
+
+// replaceStore makes store, read from the file or the database, the one in
+// use, keeping the stations changed here and not saved yet. It reports false,
+// leaving the store alone, when everything here is to be saved over it.
+func (m *MetadataManager) replaceStore(store *MetadataStore) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.dirty.all {
+		return false // everything here is saved over it
+	}
+	for uuid := range m.dirty.keys {
+		delete(store.Stations, uuid)
+		delete(store.StationCache, uuid)
+		if md, ok := m.store.Stations[uuid]; ok {
+			store.Stations[uuid] = md
+		}
+		if cached, ok := m.store.StationCache[uuid]; ok {
+			store.StationCache[uuid] = cached
+		}
+	}
+	m.store = store
+	return true
+}
+
+// Reload takes in play statistics another process saved. It reports whether
+// there was anything new.
+func (m *MetadataManager) Reload() (bool, error) {
+	if m.db != nil {
+		return m.reloadFromDatabase()
+	}
+	if !m.file.Changed() {
+		return false, nil
+	}
+	m.saveMu.Lock()
+	defer m.saveMu.Unlock()
+	lock, err := m.file.Lock()
+	if err != nil {
+		return false, err
+	}
+	defer lock.Unlock()
+	return m.syncFromDisk()
 }
 
 // Save saves metadata to disk
@@ -134,33 +226,36 @@ func (m *MetadataManager) Save() error {
 	m.saveMu.Lock()
 	defer m.saveMu.Unlock()
 
-	m.mu.RLock()
-	data, err := json.MarshalIndent(m.store, "", "  ")
-	m.mu.RUnlock()
+	if m.db != nil {
+		return m.saveToDatabase()
+	}
 
+	lock, err := m.file.Lock()
 	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
+		return err
 	}
+	defer lock.Unlock()
 
-	filePath := m.getMetadataFilePath()
+	// Another process may have saved since: start from its file and put
+	// the stations changed here on top. A file that can't be read is
+	// replaced.
+	_, _ = m.syncFromDisk()
 
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-		return fmt.Errorf("failed to create metadata directory: %w", err)
-	}
+	m.mu.Lock()
+	data, err := json.MarshalIndent(m.store, "", "  ")
+	dirty := m.dirty.take()
+	m.mu.Unlock()
 
-	// Write to a temp file then rename for crash-safety: a direct write would
-	// leave a truncated/corrupt file if the process dies mid-write.
-	tmpPath := filePath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
-		_ = os.Remove(tmpPath) // best-effort cleanup of partial write
-		return fmt.Errorf("failed to write metadata temp file: %w", err)
-	}
-	if err := os.Rename(tmpPath, filePath); err != nil {
-		_ = os.Remove(tmpPath) // best-effort cleanup
-		return fmt.Errorf("failed to rename metadata file: %w", err)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
+	if err := m.file.Write(data, 0644); err != nil {
+		m.mu.Lock()
+		m.dirty.restore(dirty)
+		m.mu.Unlock()
+		return fmt.Errorf("failed to write metadata file: %w", err)
+	}
 	return nil
 }
 
@@ -183,11 +278,12 @@ func (m *MetadataManager) StartPlay(station *api.Station) error {
 
 	// Stop previous if exists (record duration)
 	if m.currentPlay != "" {
-		m.stopPlayLocked(m.currentPlay)
+		m.stopPlayLocked(m.currentPlay, StopReasonSwitched)
 	}
 
 	m.currentPlay = stationUUID
 	m.playStartTime = now
+	m.currentSource = m.playSource
 
 	// Get or create metadata for this station
 	metadata, exists := m.store.Stations[stationUUID]
@@ -215,13 +311,19 @@ func (m *MetadataManager) StartPlay(station *api.Station) error {
 	metadata.LastPlayed = now
 
 	// Mark save as pending
-	m.savePending.Store(true)
+	m.markDirtyLocked(stationUUID)
 
 	return nil
 }
 
-// StopPlay records that a station stopped playing
+// StopPlay records that the user stopped a station
 func (m *MetadataManager) StopPlay(stationUUID string) error {
+	return m.StopPlayWithReason(stationUUID, StopReasonStopped)
+}
+
+// StopPlayWithReason records that a station stopped playing and why; the
+// reason is one of the StopReason constants.
+func (m *MetadataManager) StopPlayWithReason(stationUUID, reason string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -230,25 +332,73 @@ func (m *MetadataManager) StopPlay(stationUUID string) error {
 		return nil
 	}
 
-	m.stopPlayLocked(stationUUID)
+	m.stopPlayLocked(stationUUID, reason)
 	return nil
 }
 
+// StopCurrentPlay records that whatever station is playing stopped for
+// reason. It does nothing when no station is playing.
+func (m *MetadataManager) StopCurrentPlay(reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.currentPlay != "" {
+		m.stopPlayLocked(m.currentPlay, reason)
+	}
+}
+
 // stopPlayLocked stops play for a station (must be called with lock held)
-func (m *MetadataManager) stopPlayLocked(stationUUID string) {
+func (m *MetadataManager) stopPlayLocked(stationUUID, reason string) {
 	// Calculate duration
 	if !m.playStartTime.IsZero() {
-		duration := time.Since(m.playStartTime)
+		now := time.Now()
+		duration := now.Sub(m.playStartTime)
 		if duration > 0 {
 			if metadata, exists := m.store.Stations[stationUUID]; exists {
 				metadata.TotalDurationSeconds += int64(duration.Seconds())
 			}
 		}
+		m.logSessionLocked(stationUUID, now, reason)
 	}
 
 	m.currentPlay = ""
 	m.playStartTime = time.Time{}
-	m.savePending.Store(true)
+	m.currentSource = ""
+	m.markDirtyLocked(stationUUID)
+}
+
+// SetSessionLog makes the manager record a listening session in log for
+// every play. Pass nil to stop logging.
+func (m *MetadataManager) SetSessionLog(log *SessionLog) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessionLog = log
+}
+
+// SetPlaySource sets where plays started from now on come from, such as a
+// screen name or "cli". It is recorded with their sessions.
+func (m *MetadataManager) SetPlaySource(source string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.playSource = source
+}
+
+// logSessionLocked appends the current play to the session log (must be
+// called with lock held). Logging errors are non-fatal.
+func (m *MetadataManager) logSessionLocked(stationUUID string, end time.Time, reason string) {
+	if m.sessionLog == nil {
+		return
+	}
+	session := ListeningSession{
+		StationUUID: stationUUID,
+		Start:       m.playStartTime,
+		End:         end,
+		Source:      m.currentSource,
+		StopReason:  reason,
+	}
+	if cached, ok := m.store.StationCache[stationUUID]; ok {
+		session.StationName = cached.Name
+	}
+	_ = m.sessionLog.Append(session)
 }
 
 // GetMetadata returns metadata for a station, or nil if not found
@@ -258,7 +408,7 @@ func (m *MetadataManager) GetMetadata(stationUUID string) *StationMetadata {
 
 	if metadata, exists := m.store.Stations[stationUUID]; exists {
 		// Return a copy to prevent external modification
-		metaCopy := *metadata
+		metaCopy := copyStationMetadata(metadata)
 		return &metaCopy
 	}
 	return nil
@@ -283,7 +433,7 @@ func (m *MetadataManager) GetCachedStation(stationUUID string) *CachedStation {
 func (m *MetadataManager) sortedStationsLocked(less func(a, b StationWithMetadata) bool, limit int) []StationWithMetadata {
 	result := make([]StationWithMetadata, 0, len(m.store.Stations))
 	for uuid, metadata := range m.store.Stations {
-		metaCopy := *metadata
+		metaCopy := copyStationMetadata(metadata)
 		station := api.Station{StationUUID: uuid}
 
 		// Populate station info from cache if available
@@ -321,6 +471,9 @@ func (m *MetadataManager) sortedStationsLocked(less func(a, b StationWithMetadat
 
 // GetTopPlayed returns stations sorted by play count (most played first)
 func (m *MetadataManager) GetTopPlayed(limit int) []StationWithMetadata {
+	if result, ok := m.queryDatabase(orderTopPlayed, limit); ok {
+		return result
+	}
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	return m.sortedStationsLocked(func(a, b StationWithMetadata) bool {
@@ -330,6 +483,9 @@ func (m *MetadataManager) GetTopPlayed(limit int) []StationWithMetadata {
 
 // GetRecentlyPlayed returns stations sorted by last played time (most recent first)
 func (m *MetadataManager) GetRecentlyPlayed(limit int) []StationWithMetadata {
+	if result, ok := m.queryDatabase(orderRecentlyPlayed, limit); ok {
+		return result
+	}
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	return m.sortedStationsLocked(func(a, b StationWithMetadata) bool {
@@ -389,16 +545,61 @@ func (m *MetadataManager) GetRecentlyPlayedCount(limit int) int {
 // ClearAll removes all metadata (for testing or user request)
 func (m *MetadataManager) ClearAll() error {
 	m.mu.Lock()
+	changes := make([]JournalChange, 0, len(m.store.Stations))
+	for uuid, md := range m.store.Stations {
+		snap := metadataSnapshot{Metadata: *md}
+		if cached := m.store.StationCache[uuid]; cached != nil {
+			c := *cached
+			snap.Station = &c
+		}
+		changes = append(changes, NewJournalChange(JournalMetadata, uuid, snap, nil))
+	}
+	RecordChange("Cleared play statistics", changes...)
 	m.store.Stations = make(map[string]*StationMetadata)
 	m.store.StationCache = make(map[string]*CachedStation)
 	m.currentPlay = ""
 	m.playStartTime = time.Time{}
+	m.currentSource = ""
+	m.dirty.markAll()
 	m.mu.Unlock()
 
 	// Save is called unconditionally, so no need to set savePending here.
 	return m.Save()
 }
 
+// metadataSnapshot is a station's play statistics as the journal keeps them.
+type metadataSnapshot struct {
+	Metadata StationMetadata `json:"metadata"`
+	Station  *CachedStation  `json:"station,omitempty"`
+}
+
+// ApplyJournal puts a station's play statistics back for undo and redo:
+// value holds a metadataSnapshot, or null to remove them.
+func (m *MetadataManager) ApplyJournal(key string, value json.RawMessage) error {
+	var snap *metadataSnapshot
+	if !isNullValue(value) {
+		if err := json.Unmarshal(value, &snap); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if snap == nil {
+		delete(m.store.Stations, key)
+		delete(m.store.StationCache, key)
+	} else {
+		md := snap.Metadata
+		m.store.Stations[key] = &md
+		if snap.Station != nil {
+			m.store.StationCache[key] = snap.Station
+		}
+	}
+	m.markDirtyLocked(key)
+	return nil
+}
+
 // saveLoop runs in the background and saves periodically when changes are pending.
 // The final save on shutdown is handled by Close() after wg.Wait() returns.
 func (m *MetadataManager) saveLoop() {
@@ -426,11 +627,7 @@ func (m *MetadataManager) saveLoop() {
 // It returns any error from the final disk write so callers can log or handle it.
 func (m *MetadataManager) Close() error {
 	// Stop any current play to record duration
-	m.mu.Lock()
-	if m.currentPlay != "" {
-		m.stopPlayLocked(m.currentPlay)
-	}
-	m.mu.Unlock()
+	m.StopCurrentPlay(StopReasonExit)
 
 	// Signal background goroutine to stop and wait for it to exit
 	m.cancel()
@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// StationNotesFileName is the file in the data directory holding the user's
+// display names, notes and preferred stream URLs. It is kept as JSON with
+// either storage backend.
+const StationNotesFileName = "station_notes.json"
+
+// Limits for the fields of a StationNote, in characters.
+const (
+	maxDisplayNameLength = 100
+	maxNotesLength       = 2000
+)
+
+// StationNote is the user's overlay for one station.
+type StationNote struct {
+	DisplayName  string    `json:"display_name,omitempty"`  // Shown instead of the station name
+	Notes        string    `json:"notes,omitempty"`         // Free text, matched by local filters
+	PreferredURL string    `json:"preferred_url,omitempty"` // Played instead of the station's URL
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// IsZero reports whether n sets nothing.
+func (n StationNote) IsZero() bool {
+	return n.DisplayName == "" && n.Notes == "" && n.PreferredURL == ""
+}
+
+// NotesStore is the content of station_notes.json.
+type NotesStore struct {
+	Notes   map[string]*StationNote `json:"notes"`
+	Version int                     `json:"version"`
+}
+
+// NotesManager keeps the station notes. Changes are rare and small, so each
+// one is written to disk straight away. It implements api.StationOverlay.
+type NotesManager struct {
+	dataPath string
+	file     *SharedFile
+	store    *NotesStore
+	mu       sync.RWMutex
+}
+
+// NewNotesManager creates a NotesManager and loads the notes in dataPath. A
+// missing file is not an error; an unreadable one is reported and the
+// manager starts empty.
+func NewNotesManager(dataPath string) (*NotesManager, error) {
+	n := &NotesManager{
+		dataPath: dataPath,
+		file:     NewSharedFile(filepath.Join(dataPath, StationNotesFileName)),
+		store:    &NotesStore{Notes: make(map[string]*StationNote), Version: 1},
+	}
+	MigrateOnLoad(n.file.Path())
+	if err := n.Load(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// Load reads the notes from disk, replacing those in memory.
+func (n *NotesManager) Load() error {
+	data, err := n.file.Read()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read station notes: %w", err)
+	}
+	var store NotesStore
+	if err := json.Unmarshal(data, &store); err != nil {
+		return fmt.Errorf("failed to parse station notes: %w", err)
+	}
+	if store.Notes == nil {
+		store.Notes = make(map[string]*StationNote)
+	}
+
+	n.mu.Lock()
+	n.store = &store
+	n.mu.Unlock()
+	return nil
+}
+
+// Reload rereads the notes if another process saved them. It reports whether
+// there was anything new.
+func (n *NotesManager) Reload() (bool, error) {
+	if !n.file.Changed() {
+		return false, nil
+	}
+	if err := n.Load(); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+// saveLocked writes the notes to disk, on top of what another process saved
+// since they were read. Caller must hold n.mu.
+func (n *NotesManager) saveLocked(stationUUID string) error {
+	lock, err := n.file.Lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	store := n.store
+	if n.file.Changed() {
+		if data, err := n.file.Read(); err == nil {
+			var disk NotesStore
+			if json.Unmarshal(data, &disk) == nil && disk.Notes != nil {
+				if note, ok := store.Notes[stationUUID]; ok {
+					disk.Notes[stationUUID] = note
+				} else {
+					delete(disk.Notes, stationUUID)
+				}
+				store = &disk
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal station notes: %w", err)
+	}
+	if err := n.file.Write(data, 0644); err != nil {
+		return err
+	}
+	n.store = store
+	return nil
+}
+
+// normalizeNote trims the fields of note and checks them.
+func normalizeNote(note StationNote) (StationNote, error) {
+	note.DisplayName = strings.Join(strings.Fields(note.DisplayName), " ")
+	note.Notes = strings.TrimSpace(note.Notes)
+	note.PreferredURL = strings.TrimSpace(note.PreferredURL)
+
+	if utf8.RuneCountInString(note.DisplayName) > maxDisplayNameLength {
+		return note, fmt.Errorf("display name cannot exceed %d characters", maxDisplayNameLength)
+	}
+	if utf8.RuneCountInString(note.Notes) > maxNotesLength {
+		return note, fmt.Errorf("notes cannot exceed %d characters", maxNotesLength)
+	}
+	if note.PreferredURL != "" {
+		u, err := url.Parse(note.PreferredURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return note, fmt.Errorf("stream URL must be an http or https URL")
+		}
+	}
+	return note, nil
+}
+
+// ValidateStationNote checks the fields of note as Set does.
+func ValidateStationNote(note StationNote) error {
+	_, err := normalizeNote(note)
+	return err
+}
+
+// Set stores note for a station, replacing any previous one. A note that
+// sets nothing removes the station's entry.
+func (n *NotesManager) Set(stationUUID string, note StationNote) error {
+	if stationUUID == "" {
+		return fmt.Errorf("station UUID cannot be empty")
+	}
+	note, err := normalizeNote(note)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if note.IsZero() {
+		if _, ok := n.store.Notes[stationUUID]; !ok {
+			return nil
+		}
+		delete(n.store.Notes, stationUUID)
+		return n.saveLocked(stationUUID)
+	}
+	note.UpdatedAt = time.Now()
+	n.store.Notes[stationUUID] = &note
+	return n.saveLocked(stationUUID)
+}
+
+// Remove deletes the note of a station.
+func (n *NotesManager) Remove(stationUUID string) error {
+	return n.Set(stationUUID, StationNote{})
+}
+
+// Get returns a copy of the note of a station, or nil if it has none.
+func (n *NotesManager) Get(stationUUID string) *StationNote {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if note, ok := n.store.Notes[stationUUID]; ok {
+		noteCopy := *note
+		return &noteCopy
+	}
+	return nil
+}
+
+// field returns one field of a station's note, or "".
+func (n *NotesManager) field(stationUUID string, get func(*StationNote) string) string {
+	if n == nil {
+		return ""
+	}
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if note, ok := n.store.Notes[stationUUID]; ok {
+		return get(note)
+	}
+	return ""
+}
+
+// DisplayName returns the user's name for a station, or "".
+func (n *NotesManager) DisplayName(stationUUID string) string {
+	return n.field(stationUUID, func(note *StationNote) string { return note.DisplayName })
+}
+
+// PreferredURL returns the user's stream URL for a station, or "".
+func (n *NotesManager) PreferredURL(stationUUID string) string {
+	return n.field(stationUUID, func(note *StationNote) string { return note.PreferredURL })
+}
+
+// Notes returns the user's notes on a station, or "".
+func (n *NotesManager) Notes(stationUUID string) string {
+	return n.field(stationUUID, func(note *StationNote) string { return note.Notes })
+}
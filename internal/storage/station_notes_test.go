@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shinokada/tera/v3/internal/api"
+)
+
+func TestNotesManager_SetAndReload(t *testing.T) {
+	dir := t.TempDir()
+	n, err := NewNotesManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	note := StationNote{
+		DisplayName:  "  Radio   XYZ ",
+		Notes:        " Great late-night jazz \n",
+		PreferredURL: "https://xyz.example/aac",
+	}
+	if err := n.Set("uuid-1", note); err != nil {
+		t.Fatal(err)
+	}
+	if got := n.DisplayName("uuid-1"); got != "Radio XYZ" {
+		t.Errorf("DisplayName = %q", got)
+	}
+
+	reloaded, err := NewNotesManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reloaded.Get("uuid-1")
+	if got == nil || got.Notes != "Great late-night jazz" || got.PreferredURL != "https://xyz.example/aac" || got.UpdatedAt.IsZero() {
+		t.Fatalf("reloaded note = %+v", got)
+	}
+
+	// Clearing every field removes the entry
+	if err := reloaded.Set("uuid-1", StationNote{}); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Get("uuid-1") != nil {
+		t.Error("expected the note to be removed")
+	}
+}
+
+func TestNotesManager_Validation(t *testing.T) {
+	n, _ := NewNotesManager(t.TempDir())
+	for _, note := range []StationNote{
+		{PreferredURL: "file:///etc/passwd"},
+		{PreferredURL: "not a url"},
+		{DisplayName: strings.Repeat("x", maxDisplayNameLength+1)},
+		{Notes: strings.Repeat("x", maxNotesLength+1)},
+	} {
+		if err := n.Set("uuid-1", note); err == nil {
+			t.Errorf("Set(%+v) succeeded, want an error", note)
+		}
+	}
+	if err := n.Set("", StationNote{Notes: "x"}); err == nil {
+		t.Error("expected an error for an empty UUID")
+	}
+}
+
+func TestNotesManager_CorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, StationNotesFileName), []byte("{invalid"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	n, err := NewNotesManager(dir)
+	if err == nil {
+		t.Error("expected a parse error")
+	}
+	if n == nil || n.Get("any") != nil {
+		t.Error("expected an empty manager")
+	}
+}
+
+func TestNotesManager_StationOverlay(t *testing.T) {
+	n, _ := NewNotesManager(t.TempDir())
+	if err := n.Set("uuid-1", StationNote{DisplayName: "XYZ", Notes: "sunday brunch", PreferredURL: "http://xyz.example/hq"}); err != nil {
+		t.Fatal(err)
+	}
+	api.SetStationOverlay(n)
+	defer api.SetStationOverlay(nil)
+
+	s := api.Station{StationUUID: "uuid-1", Name: "  ★ RADIO XYZ 128k MP3 ★", URLResolved: "http://xyz.example/lq"}
+	if got := s.TrimName(); got != "XYZ" {
+		t.Errorf("TrimName = %q", got)
+	}
+	if got := s.OriginalName(); got != "★ RADIO XYZ 128k MP3 ★" {
+		t.Errorf("OriginalName = %q", got)
+	}
+	if got := s.StreamURL(); got != "http://xyz.example/hq" {
+		t.Errorf("StreamURL = %q", got)
+	}
+	if !strings.Contains(s.SearchText(), "sunday brunch") {
+		t.Errorf("SearchText = %q, want the notes", s.SearchText())
+	}
+
+	other := api.Station{StationUUID: "uuid-2", Name: " Plain ", URLResolved: "http://plain.example"}
+	if other.TrimName() != "Plain" || other.StreamURL() != "http://plain.example" || other.SearchText() != " Plain " {
+		t.Errorf("station without a note changed: %q %q %q", other.TrimName(), other.StreamURL(), other.SearchText())
+	}
+}
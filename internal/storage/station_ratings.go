@@ -54,6 +54,10 @@ type RatingsManager struct {
 	mu          sync.RWMutex
 	saveMu      sync.Mutex // serializes concurrent Save() calls
 	savePending atomic.Bool
+	db          *Database     // nil when stored in station_ratings.json
+	dbImports   atomic.Uint64 // imports of the JSON file the store was loaded after
+	file        *SharedFile   // station_ratings.json
+	dirty       dirtySet      // ratings changed since the last save
 	ctx         context.Context
 	cancel      context.CancelFunc
 	wg          sync.WaitGroup
@@ -67,6 +71,8 @@ func NewRatingsManager(dataPath string) (*RatingsManager, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	r := &RatingsManager{
 		dataPath: dataPath,
+		db:       databaseFor(dataPath),
+		file:     NewSharedFile(filepath.Join(dataPath, "station_ratings.json")),
 		store: &RatingsStore{
 			Ratings:      make(map[string]*StationRating),
 			StationCache: make(map[string]*RatingsCachedStation),
@@ -77,6 +83,7 @@ func NewRatingsManager(dataPath string) (*RatingsManager, error) {
 	}
 
 	// Load existing ratings
+	MigrateOnLoad(r.file.Path())
 	if err := r.Load(); err != nil {
 		// If file doesn't exist or is corrupted, start fresh
 		// Log warning but don't fail
@@ -90,29 +97,47 @@ func NewRatingsManager(dataPath string) (*RatingsManager, error) {
 	return r, nil
 }
 
-// getRatingsFilePath returns the full path to the ratings file
-func (r *RatingsManager) getRatingsFilePath() string {
-	return filepath.Join(r.dataPath, "station_ratings.json")
-}
-
 // Load loads ratings from disk
 func (r *RatingsManager) Load() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	filePath := r.getRatingsFilePath()
-	data, err := os.ReadFile(filePath)
+	if r.db != nil {
+		imports := r.db.importCount(ratingsFileName)
+		store, err := r.db.loadRatingsStore()
+		if err != nil {
+			return err
+		}
+		r.store = store
+		r.dbImports.Store(imports)
+		return nil
+	}
+
+	store, err := r.readFile()
 	if err != nil {
 		if os.IsNotExist(err) {
 			// File doesn't exist yet, use empty store
 			return nil
 		}
-		return fmt.Errorf("failed to read ratings file: %w", err)
+		return err
+	}
+	r.store = store
+	return nil
+}
+
+// readFile reads station_ratings.json.
+func (r *RatingsManager) readFile() (*RatingsStore, error) {
+	data, err := r.file.Read()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read ratings file: %w", err)
 	}
 
 	var store RatingsStore
 	if err := json.Unmarshal(data, &store); err != nil {
-		return fmt.Errorf("failed to parse ratings file: %w", err)
+		return nil, fmt.Errorf("failed to parse ratings file: %w", err)
 	}
 
 	// Ensure maps are initialized
@@ -122,9 +147,70 @@ func (r *RatingsManager) Load() error {
 	if store.StationCache == nil {
 		store.StationCache = make(map[string]*RatingsCachedStation)
 	}
+	return &store, nil
+}
 
-	r.store = &store
-	return nil
+// syncFromDisk takes in what another process saved to station_ratings.json
+// since this manager last read or wrote it, keeping the ratings changed here
+// and not saved yet. Caller must hold saveMu and the file lock.
+func (r *RatingsManager) syncFromDisk() (bool, error) {
+	if !r.file.Changed() {
+		return false, nil
+	}
+	store, err := r.readFile()
+	if os.IsNotExist(err) {
+		store, err = &RatingsStore{
+			Ratings:      make(map[string]*StationRating),
+			StationCache: make(map[string]*RatingsCachedStation),
+			Version:      1,
+		}, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return r.replaceStore(store), nil
+}
+
+// replaceStore makes store, read from the file or the database, the one in
+// use, keeping the ratings changed here and not saved yet. It reports false,
+// leaving the store alone, when everything here is to be saved over it.
+func (r *RatingsManager) replaceStore(store *RatingsStore) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.dirty.all {
+		return false // everything here is saved over it
+	}
+	for uuid := range r.dirty.keys {
+		delete(store.Ratings, uuid)
+		delete(store.StationCache, uuid)
+		if rating, ok := r.store.Ratings[uuid]; ok {
+			store.Ratings[uuid] = rating
+		}
+		if cached, ok := r.store.StationCache[uuid]; ok {
+			store.StationCache[uuid] = cached
+		}
+	}
+	r.store = store
+	return true
+}
+
+// Reload takes in ratings another process saved. It reports whether there
+// was anything new.
+func (r *RatingsManager) Reload() (bool, error) {
+	if r.db != nil {
+		return r.reloadFromDatabase()
+	}
+	if !r.file.Changed() {
+		return false, nil
+	}
+	r.saveMu.Lock()
+	defer r.saveMu.Unlock()
+	lock, err := r.file.Lock()
+	if err != nil {
+		return false, err
+	}
+	defer lock.Unlock()
+	return r.syncFromDisk()
 }
 
 // Save saves ratings to disk
@@ -132,33 +218,35 @@ func (r *RatingsManager) Save() error {
 	r.saveMu.Lock()
 	defer r.saveMu.Unlock()
 
-	r.mu.RLock()
-	data, err := json.MarshalIndent(r.store, "", "  ")
-	r.mu.RUnlock()
+	if r.db != nil {
+		return r.saveToDatabase()
+	}
 
+	lock, err := r.file.Lock()
 	if err != nil {
-		return fmt.Errorf("failed to marshal ratings: %w", err)
+		return err
 	}
+	defer lock.Unlock()
 
-	filePath := r.getRatingsFilePath()
+	// Merge what another process saved meanwhile; an unreadable file is
+	// replaced.
+	_, _ = r.syncFromDisk()
 
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-		return fmt.Errorf("failed to create ratings directory: %w", err)
-	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.store, "", "  ")
+	dirty := r.dirty.take()
+	r.mu.Unlock()
 
-	// Write to a temp file then rename for crash-safety: a direct write would
-	// leave a truncated/corrupt file if the process dies mid-write.
-	tmpPath := filePath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
-		_ = os.Remove(tmpPath) // best-effort cleanup of partial write
-		return fmt.Errorf("failed to write ratings temp file: %w", err)
-	}
-	if err := os.Rename(tmpPath, filePath); err != nil {
-		_ = os.Remove(tmpPath) // best-effort cleanup
-		return fmt.Errorf("failed to rename ratings file: %w", err)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ratings: %w", err)
 	}
 
+	if err := r.file.Write(data, 0644); err != nil {
+		r.mu.Lock()
+		r.dirty.restore(dirty)
+		r.mu.Unlock()
+		return fmt.Errorf("failed to write ratings file: %w", err)
+	}
 	return nil
 }
 
@@ -177,6 +265,7 @@ func (r *RatingsManager) SetRating(station *api.Station, rating int) error {
 	stationUUID := station.StationUUID
 	now := time.Now()
 	existing := r.store.Ratings[stationUUID]
+	before := r.snapshotLocked(stationUUID)
 
 	if existing == nil {
 		r.store.Ratings[stationUUID] = &StationRating{
@@ -201,7 +290,9 @@ func (r *RatingsManager) SetRating(station *api.Station, rating int) error {
 		Votes:    station.Votes,
 	}
 
-	r.savePending.Store(true)
+	r.markDirtyLocked(stationUUID)
+	RecordChange(fmt.Sprintf("Rated %s %d★", station.TrimName(), rating),
+		NewJournalChange(JournalRatings, stationUUID, before, r.snapshotLocked(stationUUID)))
 	return nil
 }
 
@@ -214,9 +305,12 @@ func (r *RatingsManager) RemoveRating(stationUUID string) error {
 		return nil // Nothing to remove
 	}
 
+	before := r.snapshotLocked(stationUUID)
 	delete(r.store.Ratings, stationUUID)
 	delete(r.store.StationCache, stationUUID)
-	r.savePending.Store(true)
+	r.markDirtyLocked(stationUUID)
+	RecordChange("Removed rating for "+before.name(stationUUID),
+		NewJournalChange(JournalRatings, stationUUID, before, nil))
 	return nil
 }
 
@@ -277,6 +371,9 @@ func (r *RatingsManager) sortedRatingsLocked(less func(a, b StationWithRating) b
 
 // GetTopRated returns stations sorted by rating (highest first)
 func (r *RatingsManager) GetTopRated(limit int) []StationWithRating {
+	if result, ok := r.queryDatabase(orderTopRated, 0, limit); ok {
+		return result
+	}
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	return r.sortedRatingsLocked(func(a, b StationWithRating) bool {
@@ -287,6 +384,9 @@ func (r *RatingsManager) GetTopRated(limit int) []StationWithRating {
 // GetByMinRating returns stations with at least the specified rating,
 // sorted by rating (highest first)
 func (r *RatingsManager) GetByMinRating(minRating int) []StationWithRating {
+	if result, ok := r.queryDatabase(orderTopRated, minRating, 0); ok {
+		return result
+	}
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -325,6 +425,9 @@ func (r *RatingsManager) GetByMinRating(minRating int) []StationWithRating {
 
 // GetRecentlyRated returns stations sorted by when they were last rated (most recent first)
 func (r *RatingsManager) GetRecentlyRated(limit int) []StationWithRating {
+	if result, ok := r.queryDatabase(orderRecentlyRated, 0, limit); ok {
+		return result
+	}
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	return r.sortedRatingsLocked(func(a, b StationWithRating) bool {
@@ -351,14 +454,76 @@ func (r *RatingsManager) GetTotalRated() int {
 // ClearAll removes all ratings (for testing or user request)
 func (r *RatingsManager) ClearAll() error {
 	r.mu.Lock()
+	changes := make([]JournalChange, 0, len(r.store.Ratings))
+	for uuid := range r.store.Ratings {
+		changes = append(changes, NewJournalChange(JournalRatings, uuid, r.snapshotLocked(uuid), nil))
+	}
+	RecordChange("Cleared all ratings", changes...)
 	r.store.Ratings = make(map[string]*StationRating)
 	r.store.StationCache = make(map[string]*RatingsCachedStation)
+	r.dirty.markAll()
 	r.mu.Unlock()
 
 	// Save is called unconditionally, so no need to set savePending here.
 	return r.Save()
 }
 
+// ratingSnapshot is a station's rating as the journal keeps it.
+type ratingSnapshot struct {
+	Rating  StationRating         `json:"rating"`
+	Station *RatingsCachedStation `json:"station,omitempty"`
+}
+
+// name returns the station name for journal descriptions.
+func (s *ratingSnapshot) name(uuid string) string {
+	if s != nil && s.Station != nil && s.Station.Name != "" {
+		return s.Station.Name
+	}
+	return uuid
+}
+
+// snapshotLocked copies the rating of a station, or returns nil when it has
+// none. Caller must hold r.mu.
+func (r *RatingsManager) snapshotLocked(uuid string) *ratingSnapshot {
+	rating := r.store.Ratings[uuid]
+	if rating == nil {
+		return nil
+	}
+	s := &ratingSnapshot{Rating: *rating}
+	if cached := r.store.StationCache[uuid]; cached != nil {
+		c := *cached
+		s.Station = &c
+	}
+	return s
+}
+
+// ApplyJournal puts a station's rating back for undo and redo: value holds
+// a ratingSnapshot, or null to remove the rating.
+func (r *RatingsManager) ApplyJournal(key string, value json.RawMessage) error {
+	var snap *ratingSnapshot
+	if !isNullValue(value) {
+		if err := json.Unmarshal(value, &snap); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if snap == nil {
+		delete(r.store.Ratings, key)
+		delete(r.store.StationCache, key)
+	} else {
+		rating := snap.Rating
+		r.store.Ratings[key] = &rating
+		if snap.Station != nil {
+			r.store.StationCache[key] = snap.Station
+		}
+	}
+	r.markDirtyLocked(key)
+	return nil
+}
+
 // saveLoop runs in the background and saves periodically when changes are pending.
 // The final save on shutdown is handled by Close() after wg.Wait() returns.
 func (r *RatingsManager) saveLoop() {
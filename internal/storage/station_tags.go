@@ -46,13 +46,18 @@ type StationWithTags struct {
 
 // TagsManager manages custom station tags with thread-safe access and debounced saves.
 type TagsManager struct {
-	dataPath    string
-	store       *TagsStore
-	mu          sync.RWMutex
-	saveMu      sync.Mutex // serializes concurrent Save() calls
-	savePending atomic.Bool
-	cancel      context.CancelFunc
-	wg          sync.WaitGroup
+	dataPath       string
+	store          *TagsStore
+	mu             sync.RWMutex
+	saveMu         sync.Mutex // serializes concurrent Save() calls
+	savePending    atomic.Bool
+	db             *Database     // nil when stored in station_tags.json
+	dbImports      atomic.Uint64 // imports of the JSON file the store was loaded after
+	file           *SharedFile   // station_tags.json
+	dirty          dirtySet      // stations whose tags changed since the last save
+	dirtyPlaylists dirtySet      // playlists changed since the last save
+	cancel         context.CancelFunc
+	wg             sync.WaitGroup
 }
 
 var tagRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9\-_ ]*[a-z0-9]$|^[a-z0-9]$`)
@@ -62,6 +67,8 @@ func NewTagsManager(dataPath string) (*TagsManager, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	tm := &TagsManager{
 		dataPath: dataPath,
+		db:       databaseFor(dataPath),
+		file:     NewSharedFile(filepath.Join(dataPath, "station_tags.json")),
 		cancel:   cancel,
 		store: &TagsStore{
 			StationTags:  make(map[string]*StationTags),
@@ -71,6 +78,7 @@ func NewTagsManager(dataPath string) (*TagsManager, error) {
 		},
 	}
 
+	MigrateOnLoad(tm.file.Path())
 	if err := tm.Load(); err != nil && !os.IsNotExist(err) {
 		// Corrupted file — log warning and continue with empty store.
 		fmt.Fprintf(os.Stderr, "[WARN] station_tags: failed to load %s: %v (starting with empty store)\n", filepath.Join(dataPath, "station_tags.json"), err)
@@ -96,14 +104,34 @@ func (t *TagsManager) Load() error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	filePath := filepath.Join(t.dataPath, "station_tags.json")
-	data, err := os.ReadFile(filePath)
+	if t.db != nil {
+		imports := t.db.importCount(tagsFileName)
+		store, err := t.db.loadTagsStore()
+		if err != nil {
+			return err
+		}
+		*t.store = *store
+		t.dbImports.Store(imports)
+		return nil
+	}
+
+	tmp, err := t.readFile()
 	if err != nil {
 		return err
 	}
+	*t.store = *tmp
+	return nil
+}
+
+// readFile reads station_tags.json.
+func (t *TagsManager) readFile() (*TagsStore, error) {
+	data, err := t.file.Read()
+	if err != nil {
+		return nil, err
+	}
 	var tmp TagsStore
 	if err := json.Unmarshal(data, &tmp); err != nil {
-		return err
+		return nil, err
 	}
 	if tmp.StationTags == nil {
 		tmp.StationTags = make(map[string]*StationTags)
@@ -114,31 +142,114 @@ func (t *TagsManager) Load() error {
 	if tmp.AllTags == nil {
 		tmp.AllTags = []string{}
 	}
-	*t.store = tmp
-	return nil
+	return &tmp, nil
+}
+
+// syncFromDisk takes in what another process saved to station_tags.json since
+// this manager last read or wrote it, keeping the stations and playlists
+// changed here and not saved yet. Caller must hold saveMu and the file lock.
+func (t *TagsManager) syncFromDisk() (bool, error) {
+	if !t.file.Changed() {
+		return false, nil
+	}
+	tmp, err := t.readFile()
+	if os.IsNotExist(err) {
+		tmp, err = &TagsStore{
+			StationTags:  make(map[string]*StationTags),
+			AllTags:      []string{},
+			TagPlaylists: make(map[string]*TagPlaylist),
+			Version:      1,
+		}, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return t.replaceStore(tmp), nil
+}
+
+// replaceStore makes tmp, read from the file or the database, the store in
+// use, keeping the stations and playlists changed here and not saved yet. It
+// reports false, leaving the store alone, when everything here is to be
+// saved over it.
+func (t *TagsManager) replaceStore(tmp *TagsStore) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.dirty.all || t.dirtyPlaylists.all {
+		return false // everything here is saved over it
+	}
+	for uuid := range t.dirty.keys {
+		delete(tmp.StationTags, uuid)
+		if st, ok := t.store.StationTags[uuid]; ok {
+			tmp.StationTags[uuid] = st
+		}
+	}
+	for name := range t.dirtyPlaylists.keys {
+		delete(tmp.TagPlaylists, name)
+		if p, ok := t.store.TagPlaylists[name]; ok {
+			tmp.TagPlaylists[name] = p
+		}
+	}
+	*t.store = *tmp
+	t.store.AllTags = []string{}
+	for _, st := range t.store.StationTags {
+		for _, tag := range st.Tags {
+			t.addToAllTags(tag)
+		}
+	}
+	return true
 }
 
-// Save writes tags to disk atomically via a temp file.
+// Reload takes in tags and playlists another process saved. It reports
+// whether there was anything new.
+func (t *TagsManager) Reload() (bool, error) {
+	if t.db != nil {
+		return t.reloadFromDatabase()
+	}
+	if !t.file.Changed() {
+		return false, nil
+	}
+	t.saveMu.Lock()
+	defer t.saveMu.Unlock()
+	lock, err := t.file.Lock()
+	if err != nil {
+		return false, err
+	}
+	defer lock.Unlock()
+	return t.syncFromDisk()
+}
+
+// Save writes tags to disk atomically, on top of what another process saved
+// since the last load or save.
 func (t *TagsManager) Save() error {
 	t.saveMu.Lock()
 	defer t.saveMu.Unlock()
-	t.mu.RLock()
-	data, err := json.MarshalIndent(t.store, "", "  ")
-	t.mu.RUnlock()
+	if t.db != nil {
+		return t.saveToDatabase()
+	}
+	lock, err := t.file.Lock()
 	if err != nil {
 		return err
 	}
+	defer lock.Unlock()
+	_, _ = t.syncFromDisk()
 
-	filePath := filepath.Join(t.dataPath, "station_tags.json")
-	if err := os.MkdirAll(t.dataPath, 0755); err != nil {
+	t.mu.Lock()
+	data, err := json.MarshalIndent(t.store, "", "  ")
+	dirty := t.dirty.take()
+	dirtyPlaylists := t.dirtyPlaylists.take()
+	t.mu.Unlock()
+	if err != nil {
 		return err
 	}
 
-	tmpPath := filePath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+	if err := t.file.Write(data, 0644); err != nil {
+		t.mu.Lock()
+		t.dirty.restore(dirty)
+		t.dirtyPlaylists.restore(dirtyPlaylists)
+		t.mu.Unlock()
 		return err
 	}
-	return os.Rename(tmpPath, filePath)
+	return nil
 }
 
 // saveLoop saves pending changes every 5 seconds and flushes on shutdown.
@@ -193,6 +304,7 @@ func (t *TagsManager) AddTag(stationUUID string, tag string) error {
 
 	now := time.Now()
 	existing := t.store.StationTags[stationUUID]
+	before := cloneStationTags(existing)
 
 	if existing == nil {
 		t.store.StationTags[stationUUID] = &StationTags{
@@ -214,7 +326,8 @@ func (t *TagsManager) AddTag(stationUUID string, tag string) error {
 	}
 
 	t.addToAllTags(normalized)
-	t.savePending.Store(true)
+	t.markDirtyLocked(stationUUID)
+	t.recordLocked(fmt.Sprintf("Added tag %q", normalized), stationUUID, before)
 	return nil
 }
 
@@ -244,13 +357,15 @@ func (t *TagsManager) RemoveTag(stationUUID string, tag string) error {
 	}
 
 	if found {
+		before := cloneStationTags(existing)
 		existing.Tags = newTags
 		existing.UpdatedAt = time.Now()
 		if len(newTags) == 0 {
 			delete(t.store.StationTags, stationUUID)
 		}
 		t.pruneAllTags()
-		t.savePending.Store(true)
+		t.markDirtyLocked(stationUUID)
+		t.recordLocked(fmt.Sprintf("Removed tag %q", normalized), stationUUID, before)
 	}
 	return nil
 }
@@ -283,16 +398,19 @@ func (t *TagsManager) SetTags(stationUUID string, tags []string) error {
 
 	// Empty tag list — remove the entry entirely (same semantics as ClearTags).
 	if len(normalized) == 0 {
-		if _, ok := t.store.StationTags[stationUUID]; ok {
+		if existing, ok := t.store.StationTags[stationUUID]; ok {
+			before := cloneStationTags(existing)
 			delete(t.store.StationTags, stationUUID)
 			t.pruneAllTags()
-			t.savePending.Store(true)
+			t.markDirtyLocked(stationUUID)
+			t.recordLocked("Cleared tags", stationUUID, before)
 		}
 		return nil
 	}
 
 	now := time.Now()
 	existing := t.store.StationTags[stationUUID]
+	before := cloneStationTags(existing)
 	if existing == nil {
 		t.store.StationTags[stationUUID] = &StationTags{
 			Tags:      normalized,
@@ -308,7 +426,8 @@ func (t *TagsManager) SetTags(stationUUID string, tags []string) error {
 	for _, tag := range normalized {
 		t.addToAllTags(tag)
 	}
-	t.savePending.Store(true)
+	t.markDirtyLocked(stationUUID)
+	t.recordLocked("Changed tags", stationUUID, before)
 	return nil
 }
 
@@ -317,11 +436,56 @@ func (t *TagsManager) ClearTags(stationUUID string) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	if _, ok := t.store.StationTags[stationUUID]; ok {
+	if existing, ok := t.store.StationTags[stationUUID]; ok {
+		before := cloneStationTags(existing)
 		delete(t.store.StationTags, stationUUID)
 		t.pruneAllTags()
-		t.savePending.Store(true)
+		t.markDirtyLocked(stationUUID)
+		t.recordLocked("Cleared tags", stationUUID, before)
+	}
+	return nil
+}
+
+// cloneStationTags copies st so a journal entry keeps its value, or returns
+// nil for a station without tags.
+func cloneStationTags(st *StationTags) *StationTags {
+	if st == nil {
+		return nil
+	}
+	c := *st
+	c.Tags = append([]string(nil), st.Tags...)
+	return &c
+}
+
+// recordLocked journals the change of a station's tags from before to their
+// current value. Caller must hold t.mu.
+func (t *TagsManager) recordLocked(description, stationUUID string, before *StationTags) {
+	RecordChange(description, NewJournalChange(JournalTags, stationUUID, before, t.store.StationTags[stationUUID]))
+}
+
+// ApplyJournal puts a station's tags back for undo and redo: value holds
+// its StationTags, or null to remove them.
+func (t *TagsManager) ApplyJournal(key string, value json.RawMessage) error {
+	var st *StationTags
+	if !isNullValue(value) {
+		if err := json.Unmarshal(value, &st); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if st == nil {
+		delete(t.store.StationTags, key)
+	} else {
+		t.store.StationTags[key] = st
+		for _, tag := range st.Tags {
+			t.addToAllTags(tag)
+		}
 	}
+	t.pruneAllTags()
+	t.markDirtyLocked(key)
 	return nil
 }
 
@@ -470,7 +634,7 @@ func (t *TagsManager) CreatePlaylist(name string, tags []string, matchMode strin
 		MatchMode: matchMode,
 		CreatedAt: time.Now(),
 	}
-	t.savePending.Store(true)
+	t.markPlaylistDirtyLocked(name)
 	return nil
 }
 
@@ -484,7 +648,7 @@ func (t *TagsManager) DeletePlaylist(name string) error {
 	}
 
 	delete(t.store.TagPlaylists, name)
-	t.savePending.Store(true)
+	t.markPlaylistDirtyLocked(name)
 	return nil
 }
 
@@ -543,7 +707,8 @@ func (t *TagsManager) UpdatePlaylist(existingName, newName string, tags []string
 		delete(t.store.TagPlaylists, existingName)
 	}
 	t.store.TagPlaylists[newName] = updated
-	t.savePending.Store(true)
+	t.markPlaylistDirtyLocked(existingName)
+	t.markPlaylistDirtyLocked(newName)
 	return nil
 }
 
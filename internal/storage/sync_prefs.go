@@ -18,6 +18,7 @@ type SyncPrefs struct {
 	Blocklist     bool `json:"blocklist"`
 	MetadataTags  bool `json:"metadata_tags"`
 	SearchHistory bool `json:"search_history"`
+	LikedSongs    bool `json:"liked_songs"`
 }
 
 // DefaultSyncPrefs returns sensible defaults.
@@ -30,6 +31,7 @@ func DefaultSyncPrefs() SyncPrefs {
 		Blocklist:     true,
 		MetadataTags:  true,
 		SearchHistory: false,
+		LikedSongs:    true,
 	}
 }
 
@@ -63,7 +65,9 @@ func LoadSyncPrefs() (SyncPrefs, error) {
 		return DefaultSyncPrefs(), nil
 	}
 
-	var prefs SyncPrefs
+	// Start from defaults so categories added after the file was written
+	// get their default value rather than false.
+	prefs := DefaultSyncPrefs()
 	if err := json.Unmarshal(data, &prefs); err != nil {
 		return DefaultSyncPrefs(), fmt.Errorf("failed to parse sync prefs: %w", err)
 	}
@@ -27,6 +27,9 @@ func TestDefaultSyncPrefs(t *testing.T) {
 	if prefs.SearchHistory {
 		t.Error("expected SearchHistory to be false by default")
 	}
+	if !prefs.LikedSongs {
+		t.Error("expected LikedSongs to be true by default")
+	}
 }
 
 func TestLoadSyncPrefs_ReturnsDefaultsWhenMissing(t *testing.T) {
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// watchDebounce is how long DataWatcher waits for a burst of changes, such
+// as a restore writing many files, to end before it reports them.
+const watchDebounce = 300 * time.Millisecond
+
+// ownWrites maps the data files this process saved or removed to the version
+// it left behind, nil for a removed file, so DataWatcher can skip them.
+var ownWrites sync.Map
+
+// noteOwnWrite records that this process just wrote or removed path.
+func noteOwnWrite(path string) {
+	ownWrites.Store(filepath.Clean(path), statFile(path))
+}
+
+// isOwnWrite reports whether path is still as this process left it.
+func isOwnWrite(path string) bool {
+	v, ok := ownWrites.Load(filepath.Clean(path))
+	if !ok {
+		return false
+	}
+	info, _ := v.(os.FileInfo)
+	return sameVersion(statFile(path), info)
+}
+
+// DataWatcher reports data files that change on disk behind the back of this
+// process: saved by another TERA, restored from a backup or Gist, or edited
+// by hand. It watches the files directly in the data directory and every
+// list in the favorites tree; saves made by this process are not reported.
+//
+// Linux uses inotify; other platforms poll every few seconds.
+type DataWatcher struct {
+	dataPath     string
+	favoritePath string
+	onChange     func(paths []string)
+
+	mu      sync.Mutex
+	pending map[string]bool
+	timer   *time.Timer
+	closed  bool
+
+	stop      chan struct{}
+	closeOnce sync.Once
+	closeFn   func() error // stops the platform watcher
+}
+
+// WatchData starts watching dataPath and favoritePath. onChange is called
+// from a background goroutine with the changed files, sorted.
+func WatchData(dataPath, favoritePath string, onChange func(paths []string)) (*DataWatcher, error) {
+	w := &DataWatcher{
+		dataPath:     filepath.Clean(dataPath),
+		favoritePath: filepath.Clean(favoritePath),
+		onChange:     onChange,
+		stop:         make(chan struct{}),
+	}
+	if err := w.start(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Close stops watching. Changes not reported yet are dropped.
+func (w *DataWatcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.stop)
+		w.mu.Lock()
+		w.closed = true
+		if w.timer != nil {
+			w.timer.Stop()
+		}
+		w.mu.Unlock()
+		if w.closeFn != nil {
+			err = w.closeFn()
+		}
+	})
+	return err
+}
+
+// IsFavoritesPath reports whether path is in the watched favorites tree.
+func (w *DataWatcher) IsFavoritesPath(path string) bool {
+	rel, err := filepath.Rel(w.favoritePath, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// watchable reports whether a change to path can matter: data files are
+// JSON, and hidden files are TERA's own, apart from the list order.
+func watchable(path string) bool {
+	name := filepath.Base(path)
+	if strings.HasPrefix(name, ".") {
+		return name == SystemFileListOrder
+	}
+	return filepath.Ext(name) == ".json"
+}
+
+// notify queues path to be reported once changes settle.
+func (w *DataWatcher) notify(path string) {
+	if !watchable(path) {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	if w.pending == nil {
+		w.pending = make(map[string]bool)
+	}
+	w.pending[path] = true
+	if w.timer == nil {
+		w.timer = time.AfterFunc(watchDebounce, w.flush)
+	}
+}
+
+func (w *DataWatcher) flush() {
+	w.mu.Lock()
+	pending := w.pending
+	w.pending = nil
+	w.timer = nil
+	closed := w.closed
+	w.mu.Unlock()
+	if closed {
+		return
+	}
+
+	var paths []string
+	for path := range pending {
+		if !isOwnWrite(path) {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return
+	}
+	sort.Strings(paths)
+	w.onChange(paths)
+}
+
+// favoriteDirs returns the favorites directory and its folders. Hidden
+// directories, such as the cleanup snapshots, are skipped.
+func (w *DataWatcher) favoriteDirs(root string) []string {
+	var dirs []string
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		dirs = append(dirs, path)
+		return nil
+	})
+	return dirs
+}
+
+// watchedFiles returns the version of every watchable file in dirs, which
+// are not searched recursively.
+func watchedFiles(dirs []string) map[string]os.FileInfo {
+	files := make(map[string]os.FileInfo)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if entry.IsDir() || !watchable(path) {
+				continue
+			}
+			if info, err := entry.Info(); err == nil {
+				files[path] = info
+			}
+		}
+	}
+	return files
+}
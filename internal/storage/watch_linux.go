@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM |
+	syscall.IN_CREATE | syscall.IN_DELETE
+
+// inotifyWatcher maps inotify watch descriptors to their directories.
+type inotifyWatcher struct {
+	file *os.File
+	mu   sync.Mutex
+	dirs map[int32]string
+}
+
+func (w *DataWatcher) start() error {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return err
+	}
+	// A non-blocking descriptor goes through the runtime poller, so Close
+	// wakes the reading goroutine.
+	in := &inotifyWatcher{file: os.NewFile(uintptr(fd), "inotify"), dirs: make(map[int32]string)}
+	if err := in.add(w.dataPath); err != nil {
+		_ = in.file.Close()
+		return err
+	}
+	for _, dir := range w.favoriteDirs(w.favoritePath) {
+		_ = in.add(dir)
+	}
+	w.closeFn = in.file.Close
+	go w.readInotify(in)
+	return nil
+}
+
+func (in *inotifyWatcher) add(dir string) error {
+	wd, err := syscall.InotifyAddWatch(int(in.file.Fd()), dir, inotifyMask)
+	if err != nil {
+		return err
+	}
+	in.mu.Lock()
+	in.dirs[int32(wd)] = dir
+	in.mu.Unlock()
+	return nil
+}
+
+func (w *DataWatcher) readInotify(in *inotifyWatcher) {
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := in.file.Read(buf)
+		if err != nil {
+			return // closed
+		}
+		for off := 0; off+syscall.SizeofInotifyEvent <= n; {
+			ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			nameStart := off + syscall.SizeofInotifyEvent
+			name := string(bytes.TrimRight(buf[nameStart:nameStart+int(ev.Len)], "\x00"))
+			off = nameStart + int(ev.Len)
+
+			in.mu.Lock()
+			dir, ok := in.dirs[ev.Wd]
+			if ev.Mask&syscall.IN_IGNORED != 0 {
+				delete(in.dirs, ev.Wd)
+			}
+			in.mu.Unlock()
+			if !ok || name == "" {
+				continue
+			}
+			path := filepath.Join(dir, name)
+
+			if ev.Mask&syscall.IN_ISDIR != 0 {
+				// A new folder of lists: watch it, and report the lists
+				// written to it before the watch was in place.
+				if ev.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 && w.IsFavoritesPath(path) {
+					for _, sub := range w.favoriteDirs(path) {
+						_ = in.add(sub)
+					}
+					for file := range watchedFiles(w.favoriteDirs(path)) {
+						w.notify(file)
+					}
+				}
+				continue
+			}
+			w.notify(path)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+//go:build !linux
+
+package storage
+
+import (
+	"os"
+	"time"
+)
+
+// watchPollInterval is how often the data files are checked on platforms
+// without inotify.
+const watchPollInterval = 2 * time.Second
+
+func (w *DataWatcher) start() error {
+	seen := w.scan()
+	go func() {
+		ticker := time.NewTicker(watchPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+			}
+			current := w.scan()
+			for path, info := range current {
+				if !sameVersion(seen[path], info) {
+					w.notify(path)
+				}
+			}
+			for path := range seen {
+				if _, ok := current[path]; !ok {
+					w.notify(path)
+				}
+			}
+			seen = current
+		}
+	}()
+	return nil
+}
+
+func (w *DataWatcher) scan() map[string]os.FileInfo {
+	return watchedFiles(append([]string{w.dataPath}, w.favoriteDirs(w.favoritePath)...))
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchDataReportsOtherWrites(t *testing.T) {
+	dataPath := t.TempDir()
+	favPath := filepath.Join(dataPath, "favorites")
+	if err := os.MkdirAll(favPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan []string, 10)
+	w, err := WatchData(dataPath, favPath, func(paths []string) { changes <- paths })
+	if err != nil {
+		t.Fatalf("WatchData: %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	// A save by this process is not reported
+	own := NewSharedFile(filepath.Join(dataPath, "station_notes.json"))
+	if err := own.Write([]byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// but a list written by someone else is, and so is a new folder's list
+	list := filepath.Join(favPath, "Jazz.json")
+	if err := atomicWriteFile(list, []byte(`{"name":"Jazz"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(favPath, "Work"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	folded := filepath.Join(favPath, "Work", "Focus.json")
+	if err := atomicWriteFile(folded, []byte(`{"name":"Focus"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]bool)
+	deadline := time.After(10 * time.Second)
+	for !got[list] || !got[folded] {
+		select {
+		case paths := <-changes:
+			for _, p := range paths {
+				got[p] = true
+			}
+		case <-deadline:
+			t.Fatalf("changes reported: %v", got)
+		}
+	}
+	if got[own.Path()] {
+		t.Error("own save reported as a change")
+	}
+	if !w.IsFavoritesPath(folded) || w.IsFavoritesPath(own.Path()) {
+		t.Error("IsFavoritesPath misclassifies paths")
+	}
+}
@@ -0,0 +1,478 @@
+// Package subscription follows favorites lists that other people publish —
+// a public gist, or a JSON or playlist file at an HTTPS URL — as read-only
+// lists that are refreshed on a schedule. Subscribed stations are kept in
+// the data directory, apart from the user's own lists, so neither a refresh
+// nor a gist restore can overwrite the other.
+package subscription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/gist"
+	"github.com/shinokada/tera/v3/internal/playlist"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// FileName is the file in the data directory holding the subscriptions and
+// the stations last fetched for each.
+const FileName = "subscriptions.json"
+
+func init() {
+	storage.RegisterDataSchema(&storage.DataSchema{
+		File: FileName,
+		Migrations: []storage.Migration{{
+			Version:     1,
+			Description: "Add missing subscription list",
+			Up: func(doc map[string]any) error {
+				if doc["subscriptions"] == nil {
+					doc["subscriptions"] = []any{}
+				}
+				return nil
+			},
+		}},
+	})
+}
+
+// Refresh intervals.
+const (
+	DefaultInterval = 24 * time.Hour
+	MinInterval     = 15 * time.Minute
+)
+
+// maxDownloadSize caps how much of a subscribed file is read.
+const maxDownloadSize = 10 << 20
+
+// ErrNotFound is returned for a subscription name that is not subscribed.
+var ErrNotFound = errors.New("subscription not found")
+
+// Subscription is one followed list.
+type Subscription struct {
+	Name      string        `json:"name"`
+	Source    string        `json:"source"`         // Gist URL or ID, or an HTTPS URL
+	File      string        `json:"file,omitempty"` // Gist file to read; empty reads every list in the gist
+	Every     string        `json:"every,omitempty"`
+	CheckedAt time.Time     `json:"checked_at"` // Last refresh attempt
+	UpdatedAt time.Time     `json:"updated_at"` // Last successful refresh
+	LastError string        `json:"last_error,omitempty"`
+	Stations  []api.Station `json:"stations"`
+}
+
+// Interval returns how often the subscription is refreshed.
+func (s Subscription) Interval() time.Duration {
+	d, err := time.ParseDuration(s.Every)
+	if err != nil || d < MinInterval {
+		return DefaultInterval
+	}
+	return d
+}
+
+// Due reports whether the subscription should be refreshed at now.
+func (s Subscription) Due(now time.Time) bool {
+	return now.Sub(s.CheckedAt) >= s.Interval()
+}
+
+type store struct {
+	Subscriptions []*Subscription `json:"subscriptions"`
+	Version       int             `json:"version"`
+}
+
+// Manager keeps the subscriptions in dataPath.
+type Manager struct {
+	dataPath string
+	store    *store
+	mu       sync.Mutex
+
+	// Replaced in tests
+	client    *http.Client
+	fetchGist func(id string) (*gist.Gist, error)
+}
+
+// NewManager creates a Manager and loads the subscriptions in dataPath. A
+// missing file is not an error; an unreadable one is reported and the
+// manager starts empty.
+func NewManager(dataPath string) (*Manager, error) {
+	m := &Manager{
+		dataPath:  dataPath,
+		store:     &store{Version: 1},
+		client:    &http.Client{Timeout: 30 * time.Second},
+		fetchGist: gist.GetGistPublic,
+	}
+	storage.MigrateOnLoad(m.filePath())
+	data, err := os.ReadFile(m.filePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return m, fmt.Errorf("failed to read subscriptions: %w", err)
+	}
+	var s store
+	if err := json.Unmarshal(data, &s); err != nil {
+		return m, fmt.Errorf("failed to parse subscriptions: %w", err)
+	}
+	m.store = &s
+	return m, nil
+}
+
+func (m *Manager) filePath() string {
+	return filepath.Join(m.dataPath, FileName)
+}
+
+// saveLocked writes the subscriptions to disk. Caller must hold m.mu.
+func (m *Manager) saveLocked() error {
+	data, err := json.MarshalIndent(m.store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscriptions: %w", err)
+	}
+	if err := os.MkdirAll(m.dataPath, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	tmp := m.filePath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.filePath())
+}
+
+// findLocked returns the subscription called name. Caller must hold m.mu.
+func (m *Manager) findLocked(name string) (int, *Subscription) {
+	for i, s := range m.store.Subscriptions {
+		if strings.EqualFold(s.Name, name) {
+			return i, s
+		}
+	}
+	return -1, nil
+}
+
+// List returns the subscriptions, sorted by name.
+func (m *Manager) List() []Subscription {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	subs := make([]Subscription, len(m.store.Subscriptions))
+	for i, s := range m.store.Subscriptions {
+		subs[i] = *s
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		return strings.ToLower(subs[i].Name) < strings.ToLower(subs[j].Name)
+	})
+	return subs
+}
+
+// Names returns the names of the subscriptions, sorted.
+func (m *Manager) Names() []string {
+	subs := m.List()
+	names := make([]string, len(subs))
+	for i, s := range subs {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// Get returns the subscription called name.
+func (m *Manager) Get(name string) (Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, s := m.findLocked(name)
+	if s == nil {
+		return Subscription{}, ErrNotFound
+	}
+	return *s, nil
+}
+
+// Add subscribes to source, fetching it once so a wrong URL is reported
+// straight away. An empty name is taken from the gist description or the
+// file name; an interval of zero uses DefaultInterval.
+func (m *Manager) Add(ctx context.Context, name, source, file string, every time.Duration) (Subscription, error) {
+	source = strings.TrimSpace(source)
+	if every != 0 && every < MinInterval {
+		return Subscription{}, fmt.Errorf("refresh interval must be at least %v", MinInterval)
+	}
+	stations, title, err := m.fetch(ctx, source, file)
+	if err != nil {
+		return Subscription{}, err
+	}
+	if name == "" {
+		name = playlist.ListName(title, "Subscription")
+	}
+	if err := storage.ValidateListName(name); err != nil {
+		return Subscription{}, err
+	}
+
+	now := time.Now()
+	sub := &Subscription{
+		Name:      name,
+		Source:    source,
+		File:      file,
+		CheckedAt: now,
+		UpdatedAt: now,
+		Stations:  stations,
+	}
+	if every != 0 {
+		sub.Every = every.String()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, existing := m.findLocked(name); existing != nil {
+		return Subscription{}, fmt.Errorf("already subscribed as %q", existing.Name)
+	}
+	m.store.Subscriptions = append(m.store.Subscriptions, sub)
+	return *sub, m.saveLocked()
+}
+
+// Remove unsubscribes from name. Stations already copied into local lists
+// are kept.
+func (m *Manager) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i, s := m.findLocked(name)
+	if s == nil {
+		return ErrNotFound
+	}
+	m.store.Subscriptions = append(m.store.Subscriptions[:i], m.store.Subscriptions[i+1:]...)
+	return m.saveLocked()
+}
+
+// Refresh fetches name again. On failure the stations fetched last time are
+// kept and the error is recorded with the subscription.
+func (m *Manager) Refresh(ctx context.Context, name string) error {
+	sub, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+	stations, _, fetchErr := m.fetch(ctx, sub.Source, sub.File)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, s := m.findLocked(name)
+	if s == nil {
+		return ErrNotFound // Removed while fetching
+	}
+	s.CheckedAt = time.Now()
+	if fetchErr != nil {
+		s.LastError = fetchErr.Error()
+	} else {
+		s.LastError = ""
+		s.UpdatedAt = s.CheckedAt
+		s.Stations = stations
+	}
+	if err := m.saveLocked(); err != nil {
+		return err
+	}
+	return fetchErr
+}
+
+// RefreshDue refreshes the subscriptions that are due at now and returns
+// the names of those that were refreshed successfully, with the first
+// failure.
+func (m *Manager) RefreshDue(ctx context.Context, now time.Time) ([]string, error) {
+	var refreshed []string
+	var firstErr error
+	for _, sub := range m.List() {
+		if !sub.Due(now) {
+			continue
+		}
+		if err := m.Refresh(ctx, sub.Name); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", sub.Name, err)
+			}
+			continue
+		}
+		refreshed = append(refreshed, sub.Name)
+	}
+	return refreshed, firstErr
+}
+
+// Stations returns the stations last fetched for name.
+func (m *Manager) Stations(name string) ([]api.Station, error) {
+	sub, err := m.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return sub.Stations, nil
+}
+
+// CopyTo adds the stations of subscription name to the local list, which
+// is created if needed; stations already in it are skipped. It returns how
+// many were added.
+func (m *Manager) CopyTo(ctx context.Context, st *storage.Storage, name, list string) (int, error) {
+	stations, err := m.Stations(name)
+	if err != nil {
+		return 0, err
+	}
+	if err := storage.ValidateListName(list); err != nil {
+		return 0, err
+	}
+	return st.AddStations(ctx, list, stations)
+}
+
+// isGistSource reports whether source names a gist rather than a file URL.
+func isGistSource(source string) bool {
+	u, err := url.Parse(source)
+	if err != nil || u.Scheme == "" {
+		return true // A bare gist ID
+	}
+	host := strings.ToLower(u.Host)
+	return host == "gist.github.com" || host == "gist.githubusercontent.com"
+}
+
+// fetch downloads source and returns its stations and a title for it.
+func (m *Manager) fetch(ctx context.Context, source, file string) ([]api.Station, string, error) {
+	if source == "" {
+		return nil, "", fmt.Errorf("missing gist or URL")
+	}
+	if isGistSource(source) {
+		return m.fetchFromGist(ctx, source, file)
+	}
+
+	u, err := url.Parse(source)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, "", fmt.Errorf("subscriptions need a gist or an https URL")
+	}
+	data, err := m.download(ctx, source)
+	if err != nil {
+		return nil, "", err
+	}
+	base := path.Base(u.Path)
+	stations, err := parseStations(base, data)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(stations) == 0 {
+		return nil, "", fmt.Errorf("no stations found at %s", source)
+	}
+	return stations, strings.TrimSuffix(base, path.Ext(base)), nil
+}
+
+// fetchFromGist reads the favorites lists and playlists in a public gist,
+// or only the file named file. Other files, such as a synced config, are
+// ignored.
+func (m *Manager) fetchFromGist(ctx context.Context, source, file string) ([]api.Station, string, error) {
+	id, err := gist.ParseGistURL(source)
+	if err != nil {
+		return nil, "", err
+	}
+	g, err := m.fetchGist(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	names := make([]string, 0, len(g.Files))
+	for name := range g.Files {
+		if file == "" || name == file || name == "fav--"+file+".json" {
+			names = append(names, name)
+		}
+	}
+	if file != "" && len(names) == 0 {
+		return nil, "", fmt.Errorf("gist has no file %q", file)
+	}
+	sort.Strings(names)
+
+	var stations []api.Station
+	seen := make(map[string]bool)
+	for _, name := range names {
+		f := g.Files[name]
+		if !isListFile(name) {
+			continue
+		}
+		data := []byte(f.Content)
+		// Large files come without their content
+		if f.Content == "" && f.RawURL != "" {
+			if data, err = m.download(ctx, f.RawURL); err != nil {
+				return nil, "", err
+			}
+		}
+		list, err := parseStations(name, data)
+		if err != nil {
+			continue // Not a list, e.g. ratings.json
+		}
+		for _, st := range list {
+			if !seen[st.StationUUID] {
+				seen[st.StationUUID] = true
+				stations = append(stations, st)
+			}
+		}
+	}
+	if len(stations) == 0 {
+		return nil, "", fmt.Errorf("no favorites lists found in gist %s", id)
+	}
+
+	title := g.Description
+	if file != "" {
+		title = strings.TrimSuffix(strings.TrimPrefix(file, "fav--"), ".json")
+	}
+	return stations, title, nil
+}
+
+// isListFile reports whether a gist file may hold stations.
+func isListFile(name string) bool {
+	return strings.EqualFold(path.Ext(name), ".json") || playlist.FormatFromPath(name) != ""
+}
+
+func (m *Manager) download(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "tera-radio-player")
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("download failed: %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize))
+}
+
+// parseStations reads a TERA favorites list (a JSON array of stations) or a
+// playlist. Playlist streams become custom stations.
+func parseStations(name string, data []byte) ([]api.Station, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	trimmed := bytes.TrimSpace(data)
+	if strings.EqualFold(path.Ext(name), ".json") || bytes.HasPrefix(trimmed, []byte("[")) {
+		var list []api.Station
+		if err := json.Unmarshal(trimmed, &list); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		stations := list[:0]
+		for _, st := range list {
+			if st.URLResolved == "" {
+				continue
+			}
+			if st.StationUUID == "" {
+				st.StationUUID = api.CustomStationUUID(st.URLResolved)
+			}
+			stations = append(stations, st)
+		}
+		return stations, nil
+	}
+
+	p, err := playlist.Read(bytes.NewReader(data), playlist.FormatFromPath(name))
+	if err != nil {
+		return nil, err
+	}
+	stations := make([]api.Station, len(p.Entries))
+	for i, e := range p.Entries {
+		stations[i] = playlist.CustomStation(e)
+	}
+	return stations, nil
+}
@@ -0,0 +1,181 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/gist"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+const testGistID = "aa5a315d61ae9438b18d"
+
+func newTestManager(t *testing.T, g *gist.Gist) *Manager {
+	t.Helper()
+	m, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.fetchGist = func(id string) (*gist.Gist, error) {
+		if g == nil || id != g.ID {
+			return nil, errors.New("gist not found")
+		}
+		return g, nil
+	}
+	return m
+}
+
+func stationNames(t *testing.T, m *Manager, name string) []string {
+	t.Helper()
+	stations, err := m.Stations(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, st := range stations {
+		names = append(names, st.Name)
+	}
+	return names
+}
+
+func TestManager_AddFromGist(t *testing.T) {
+	g := &gist.Gist{
+		ID:          testGistID,
+		Description: "Team Radio",
+		Files: map[string]gist.GistFile{
+			"fav--Jazz.json": {Content: `[{"stationuuid":"a","name":"Jazz FM","url_resolved":"http://jazz"}]`},
+			"fav--News.json": {Content: `[{"stationuuid":"b","name":"News","url_resolved":"http://news"},{"stationuuid":"a","name":"Jazz FM","url_resolved":"http://jazz"}]`},
+			"ratings.json":   {Content: `{"ratings":{}}`},
+			"config.yaml":    {Content: "theme: dark"},
+			"extra.m3u":      {Content: "#EXTM3U\n#EXTINF:-1,Rock\nhttp://rock\n"},
+		},
+	}
+	m := newTestManager(t, g)
+	ctx := context.Background()
+
+	sub, err := m.Add(ctx, "", "https://gist.github.com/someone/"+testGistID, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Name != "Team-Radio" {
+		t.Errorf("Name = %q, want Team-Radio", sub.Name)
+	}
+	if got := stationNames(t, m, "Team-Radio"); !reflect.DeepEqual(got, []string{"Rock", "Jazz FM", "News"}) {
+		t.Errorf("stations = %v", got)
+	}
+
+	// One file of the gist, by list name
+	if _, err := m.Add(ctx, "", testGistID, "News", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if got := stationNames(t, m, "News"); !reflect.DeepEqual(got, []string{"News", "Jazz FM"}) {
+		t.Errorf("News stations = %v", got)
+	}
+	if _, err := m.Add(ctx, "news", testGistID, "News", 0); err == nil {
+		t.Error("expected an error for a duplicate name")
+	}
+	if _, err := m.Add(ctx, "Short", testGistID, "", time.Minute); err == nil {
+		t.Error("expected an error for a too short interval")
+	}
+
+	// Subscriptions survive a reload
+	reloaded, err := NewManager(m.dataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Names(); !reflect.DeepEqual(got, []string{"News", "Team-Radio"}) {
+		t.Errorf("Names = %v", got)
+	}
+}
+
+func TestManager_AddFromURL(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/lists/chill.m3u":
+			_, _ = w.Write([]byte("#EXTM3U\n#EXTINF:-1,Chill\nhttp://chill\n"))
+		case "/lists/picks.json":
+			_, _ = w.Write([]byte(`[{"name":"Custom","url_resolved":"http://custom"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	m := newTestManager(t, nil)
+	m.client = ts.Client()
+	ctx := context.Background()
+
+	sub, err := m.Add(ctx, "", ts.URL+"/lists/chill.m3u", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Name != "chill" || len(sub.Stations) != 1 || !sub.Stations[0].IsCustom() {
+		t.Errorf("sub = %+v", sub)
+	}
+	if sub, err := m.Add(ctx, "Picks", ts.URL+"/lists/picks.json", "", 0); err != nil || sub.Stations[0].StationUUID == "" {
+		t.Errorf("Add json = %+v, %v", sub, err)
+	}
+	if _, err := m.Add(ctx, "", ts.URL+"/missing.m3u", "", 0); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	if _, err := m.Add(ctx, "", "http://example.com/list.m3u", "", 0); err == nil {
+		t.Error("expected an error for a plain http URL")
+	}
+}
+
+func TestManager_RefreshAndCopy(t *testing.T) {
+	g := &gist.Gist{
+		ID:    testGistID,
+		Files: map[string]gist.GistFile{"fav--Jazz.json": {Content: `[{"stationuuid":"a","name":"Jazz FM","url_resolved":"http://jazz"}]`}},
+	}
+	m := newTestManager(t, g)
+	ctx := context.Background()
+	if _, err := m.Add(ctx, "Jazz", testGistID, "", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Not due yet
+	if refreshed, err := m.RefreshDue(ctx, time.Now()); err != nil || len(refreshed) != 0 {
+		t.Errorf("RefreshDue = %v, %v", refreshed, err)
+	}
+
+	g.Files["fav--Jazz.json"] = gist.GistFile{Content: `[{"stationuuid":"a","name":"Jazz FM","url_resolved":"http://jazz"},{"stationuuid":"c","name":"Smooth","url_resolved":"http://smooth"}]`}
+	refreshed, err := m.RefreshDue(ctx, time.Now().Add(DefaultInterval))
+	if err != nil || !reflect.DeepEqual(refreshed, []string{"Jazz"}) {
+		t.Fatalf("RefreshDue = %v, %v", refreshed, err)
+	}
+	if got := stationNames(t, m, "Jazz"); len(got) != 2 {
+		t.Errorf("after refresh stations = %v", got)
+	}
+
+	// A failed refresh keeps the last stations and records the error
+	m.fetchGist = func(string) (*gist.Gist, error) { return nil, errors.New("offline") }
+	if err := m.Refresh(ctx, "Jazz"); err == nil {
+		t.Error("expected the fetch error")
+	}
+	sub, _ := m.Get("Jazz")
+	if sub.LastError == "" || len(sub.Stations) != 2 {
+		t.Errorf("after failed refresh sub = %+v", sub)
+	}
+
+	store := storage.NewStorage(t.TempDir())
+	n, err := m.CopyTo(ctx, store, "Jazz", "My-Jazz")
+	if err != nil || n != 2 {
+		t.Fatalf("CopyTo = %d, %v", n, err)
+	}
+	if n, _ := m.CopyTo(ctx, store, "Jazz", "My-Jazz"); n != 0 {
+		t.Errorf("second CopyTo added %d", n)
+	}
+
+	if err := m.Remove("jazz"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Get("Jazz"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Remove = %v", err)
+	}
+}
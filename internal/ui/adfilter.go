@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/player"
+)
+
+// adSkipMsg is sent when a skip rule matches on p. Screens that can move on
+// (a favorites list or a shuffle session) play the next station, and the app
+// stops the players it holds (a handed-off station or a quick play); any
+// other player stays muted until the title changes.
+type adSkipMsg struct {
+	player *player.MPVPlayer
+}
+
+// adFilterFromConfig converts the ad filter settings into player rules. It
+// returns nil when the filter is off or has nothing to do. Patterns have
+// already been checked by config validation; any that still fail to compile
+// are skipped.
+func adFilterFromConfig(cfg config.AdFilterConfig) *player.AdFilter {
+	if !cfg.Enabled {
+		return nil
+	}
+	f := &player.AdFilter{
+		LowerPercent:  cfg.LowerVolume,
+		MuteOnConnect: time.Duration(cfg.MuteOnConnectSeconds) * time.Second,
+	}
+	for _, r := range cfg.Rules {
+		re, err := r.Compile()
+		if err != nil {
+			continue
+		}
+		action := player.AdActionMute
+		switch r.Action {
+		case config.AdActionLower:
+			action = player.AdActionLower
+		case config.AdActionSkip:
+			action = player.AdActionSkip
+		}
+		f.Rules = append(f.Rules, player.AdRule{Pattern: re, Action: action, StationUUID: r.Station})
+	}
+	if len(f.Rules) == 0 && f.MuteOnConnect == 0 {
+		return nil
+	}
+	return f
+}
+
+// adStatusText describes what the ad filter is doing to p, or "" when it is
+// not overriding the volume.
+func adStatusText(p *player.MPVPlayer) string {
+	if p == nil {
+		return ""
+	}
+	reason, active := p.AdStatus()
+	if !active {
+		return ""
+	}
+	switch reason {
+	case player.AdReasonConnect:
+		return "🔇 Muted while connecting (ad filter)"
+	case player.AdReasonLower:
+		return "🔉 Volume lowered by ad filter until the track changes"
+	default:
+		return "🔇 Muted by ad filter until the track changes"
+	}
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/player"
+)
+
+func TestAdFilterFromConfig(t *testing.T) {
+	cfg := config.DefaultAdFilterConfig()
+	if adFilterFromConfig(cfg) != nil {
+		t.Error("a disabled filter must not be installed")
+	}
+
+	cfg.Enabled = true
+	cfg.MuteOnConnectSeconds = 15
+	cfg.Rules = []config.AdFilterRule{
+		{Pattern: "traffic", Action: config.AdActionLower},
+		{Pattern: "jingle", Action: config.AdActionSkip, Station: "uuid-1"},
+	}
+	f := adFilterFromConfig(cfg)
+	if f == nil || len(f.Rules) != 2 || f.MuteOnConnect.Seconds() != 15 || f.LowerPercent != cfg.LowerVolume {
+		t.Fatalf("unexpected filter: %+v", f)
+	}
+	if r, ok := f.Match("TRAFFIC update", ""); !ok || r.Action != player.AdActionLower {
+		t.Errorf("expected case-insensitive lower rule, got %+v %v", r, ok)
+	}
+	if r, ok := f.Match("jingle", "uuid-1"); !ok || r.Action != player.AdActionSkip {
+		t.Errorf("expected station skip rule, got %+v %v", r, ok)
+	}
+
+	cfg.Rules = nil
+	cfg.MuteOnConnectSeconds = 0
+	if adFilterFromConfig(cfg) != nil {
+		t.Error("a filter with nothing to do must not be installed")
+	}
+}
+
+func TestPlayModel_AdSkipPlaysNextStation(t *testing.T) {
+	m := NewPlayModel(t.TempDir(), nil)
+	m.stations = []api.Station{
+		{StationUUID: "a", Name: "A"},
+		{StationUUID: "b", Name: "B"},
+	}
+	m.selectedStation = &m.stations[1]
+	m.state = playStatePlaying
+
+	// Skip requests from another player are ignored.
+	updated, cmd := m.Update(adSkipMsg{player: player.NewMPVPlayer()})
+	if cmd != nil || updated.(PlayModel).selectedStation.StationUUID != "b" {
+		t.Fatal("skip from a different player must be ignored")
+	}
+
+	updated, cmd = m.Update(adSkipMsg{player: m.player})
+	got := updated.(PlayModel)
+	if cmd == nil || got.selectedStation.StationUUID != "a" {
+		t.Errorf("expected wrap-around to station a, got %q", got.selectedStation.StationUUID)
+	}
+}
+
+func TestApp_AdSkipStopsHandedOffPlayer(t *testing.T) {
+	a := newTestApp()
+	a.screen = screenList
+	handedOff := player.NewMPVPlayer()
+	a.activePlayer = handedOff
+	a.activeStation = &api.Station{StationUUID: "a", Name: "Jazz FM"}
+
+	// A screen's own player is left to that screen
+	if _, ok := a.skipAppPlayback(player.NewMPVPlayer()); ok {
+		t.Fatal("skip from a screen player must not be handled by the app")
+	}
+
+	a.Update(adSkipMsg{player: handedOff})
+	if a.activePlayer != nil || a.activeStation != nil {
+		t.Error("expected the handed-off player to be stopped")
+	}
+	if !strings.Contains(a.listManagementScreen.message, "Jazz FM") {
+		t.Errorf("expected a notice, got %q", a.listManagementScreen.message)
+	}
+}
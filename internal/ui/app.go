@@ -17,7 +17,10 @@ import (
 	"github.com/shinokada/tera/v3/internal/blocklist"
 	"github.com/shinokada/tera/v3/internal/config"
 	"github.com/shinokada/tera/v3/internal/player"
+	"github.com/shinokada/tera/v3/internal/relay"
+	"github.com/shinokada/tera/v3/internal/remote"
 	"github.com/shinokada/tera/v3/internal/storage"
+	"github.com/shinokada/tera/v3/internal/subscription"
 	internaltimer "github.com/shinokada/tera/v3/internal/timer"
 	"github.com/shinokada/tera/v3/internal/ui/components"
 )
@@ -41,8 +44,37 @@ const (
 	screenBrowseTags
 	screenTagPlaylists
 	screenSleepSummary
+	screenLikedSongs
+	screenTimeline
+	screenStats
+	screenCleanup
+	screenChanges
 )
 
+// playSource names the screen in the listening session log, or returns ""
+// for screens that do not play stations.
+func (s Screen) playSource() string {
+	switch s {
+	case screenMainMenu:
+		return "main_menu"
+	case screenPlay:
+		return "favorites"
+	case screenSearch:
+		return "search"
+	case screenLucky:
+		return "lucky"
+	case screenMostPlayed:
+		return "most_played"
+	case screenTopRated:
+		return "top_rated"
+	case screenBrowseTags:
+		return "browse_tags"
+	case screenTagPlaylists:
+		return "tag_playlists"
+	}
+	return ""
+}
+
 // Main menu configuration
 const mainMenuItemCount = 11
 
@@ -68,12 +100,24 @@ type App struct {
 	connectionSettingsScreen ConnectionSettingsModel
 	appearanceSettingsScreen AppearanceSettingsModel
 	blocklistScreen          BlocklistModel
+	likedSongsScreen         LikedSongsModel
+	cleanupScreen            CleanupModel
+	changesScreen            ChangesModel
+	timelineScreen           TimelineModel
+	statsScreen              StatsModel
 	apiClient                *api.Client
 	blocklistManager         *blocklist.Manager
-	metadataManager          *storage.MetadataManager // Track play statistics
-	ratingsManager           *storage.RatingsManager  // Track station ratings
-	tagsManager              *storage.TagsManager     // Custom station tags
-	starRenderer             *components.StarRenderer // Render star ratings
+	metadataManager          *storage.MetadataManager   // Track play statistics
+	ratingsManager           *storage.RatingsManager    // Track station ratings
+	tagsManager              *storage.TagsManager       // Custom station tags
+	notesManager             *storage.NotesManager      // Display names, stream URLs and notes
+	subscriptions            *subscription.Manager      // Read-only lists followed from gists and URLs
+	likedSongsManager        *storage.LikedSongsManager // Bookmarked songs
+	journal                  *storage.Journal           // Undo/redo history of library changes
+	dataWatcher              *storage.DataWatcher       // Reports data files changed by other processes
+	playSourceScreen         Screen                     // screen last passed to SetPlaySource
+	database                 *storage.Database          // SQLite store; nil when the JSON files are used
+	starRenderer             *components.StarRenderer   // Render star ratings
 	favoritePath             string
 	quickFavorites           []api.Station
 	quickFavPlayer           *player.MPVPlayer
@@ -107,6 +151,17 @@ type App struct {
 	// Recently Played viewport
 	rpViewOffset    int // first RP entry index visible on screen
 	rpVisibleWindow int // last-known number of RP rows that fit on screen
+	// HTTP remote (nil unless remote.enabled is set)
+	remoteServer *remote.Server
+	// Stream relay (nil unless relay.enabled is set)
+	relay *relay.Relay
+	// Visualizer
+	visualizerCfg     config.VisualizerConfig
+	visualizerRunning bool                // true while the sampling loop is scheduled
+	audioLevels       *player.AudioLevels // latest measurement; nil when idle
+	// Automatic backups
+	autoBackupCfg config.AutoBackupConfig
+	autoBackupErr error // last scheduled backup failure, shown in Sync & Backup
 	// Cleanup guard
 	cleanupOnce sync.Once // Ensures Cleanup is only called once
 	// Bubbletea program handle (set by main) for sending async messages.
@@ -164,8 +219,14 @@ func NewApp() *App {
 		fmt.Fprintf(os.Stderr, "Warning: failed to load blocklist: %v\n", err)
 	}
 
-	// Initialize metadata manager for play statistics
+	// Open the SQLite store first so the managers below use it
 	dataPath := filepath.Join(configDir, "tera", "data")
+	database, err := storage.OpenConfiguredDatabase(dataPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to open database, using JSON files: %v\n", err)
+	}
+
+	// Initialize metadata manager for play statistics
 	metadataMgr, err := storage.NewMetadataManager(dataPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to initialize metadata manager: %v\n", err)
@@ -186,23 +247,67 @@ func NewApp() *App {
 		fmt.Fprintf(os.Stderr, "Warning: failed to initialize tags manager: %v\n", err)
 	}
 
+	// Load station notes; their display names and stream URLs apply everywhere
+	notesMgr, err := storage.NewNotesManager(dataPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load station notes: %v\n", err)
+	}
+	api.SetStationOverlay(notesMgr)
+
+	// Load subscribed lists; they are refreshed in the background
+	subscriptions, err := subscription.NewManager(dataPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load subscriptions: %v\n", err)
+	}
+
+	// Initialize liked songs manager for song bookmarks
+	likedSongsMgr, err := storage.NewLikedSongsManager(dataPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to initialize liked songs manager: %v\n", err)
+	}
+
+	// Journal library changes so u and ctrl+r can undo and redo them
+	journal, err := storage.OpenJournal(dataPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load undo history: %v\n", err)
+	}
+	journal.Handle(storage.JournalFavorites, storage.NewStorage(favPath).ApplyJournal)
+	journal.Handle(storage.JournalBlocklist, blocklistMgr.ApplyJournal)
+	if tagsMgr != nil {
+		journal.Handle(storage.JournalTags, tagsMgr.ApplyJournal)
+	}
+	if ratingsMgr != nil {
+		journal.Handle(storage.JournalRatings, ratingsMgr.ApplyJournal)
+	}
+	if metadataMgr != nil {
+		journal.Handle(storage.JournalMetadata, metadataMgr.ApplyJournal)
+	}
+	storage.SetJournal(journal)
+
 	app := &App{
-		screen:           screenMainMenu,
-		favoritePath:     favPath,
-		apiClient:        api.NewClient(),
-		quickFavPlayer:   player.NewMPVPlayer(),
-		helpModel:        components.NewHelpModel(components.CreateMainMenuHelp()),
-		blocklistManager: blocklistMgr,
-		metadataManager:  metadataMgr,
-		ratingsManager:   ratingsMgr,
-		tagsManager:      tagsMgr,
-		starRenderer:     starRenderer,
-		dataPath:         dataPath,
+		screen:            screenMainMenu,
+		favoritePath:      favPath,
+		apiClient:         api.NewClient(),
+		quickFavPlayer:    player.NewMPVPlayer(),
+		helpModel:         components.NewHelpModel(components.CreateMainMenuHelp()),
+		blocklistManager:  blocklistMgr,
+		metadataManager:   metadataMgr,
+		ratingsManager:    ratingsMgr,
+		tagsManager:       tagsMgr,
+		notesManager:      notesMgr,
+		subscriptions:     subscriptions,
+		likedSongsManager: likedSongsMgr,
+		journal:           journal,
+		database:          database,
+		starRenderer:      starRenderer,
+		dataPath:          dataPath,
 	}
 
 	// Set metadata manager on players for play tracking
 	if metadataMgr != nil {
 		app.quickFavPlayer.SetMetadataManager(metadataMgr)
+		metadataMgr.SetSessionLog(storage.OpenConfiguredSessionLog(dataPath))
+		metadataMgr.SetPlaySource(screenMainMenu.playSource())
 	}
 
 	// Load play history config
@@ -219,6 +324,77 @@ func NewApp() *App {
 		app.playOptsCfg = config.DefaultPlayOptionsConfig()
 	}
 
+	// Load visualizer config; it is applied in Init so the sampling loop
+	// starts with the program.
+	if vc, err := storage.LoadVisualizerConfigFromUnified(); err == nil {
+		app.visualizerCfg = vc
+	} else {
+		app.visualizerCfg = config.DefaultVisualizerConfig()
+	}
+
+	if ab, err := storage.LoadAutoBackupConfigFromUnified(); err == nil {
+		app.autoBackupCfg = ab
+	} else {
+		app.autoBackupCfg = config.DefaultAutoBackupConfig()
+	}
+
+	// Time-shift depth is a process-wide player setting.
+	if tc, err := storage.LoadTimeShiftConfigFromUnified(); err == nil {
+		player.SetTimeShift(tc.Depth())
+	}
+
+	// Network resume is process-wide too; the player reports when it starts
+	// and stops waiting so the now-playing bar can say so.
+	if rc, err := storage.LoadResumeConfigFromUnified(); err == nil {
+		player.SetNetworkResume(rc.Timeout())
+	}
+	player.SetNetworkStatusHandler(func(p *player.MPVPlayer, waiting bool) {
+		if prog := app.program.Load(); prog != nil {
+			go prog.Send(networkStatusMsg{player: p, waiting: waiting})
+		}
+	})
+
+	// Data saver: the monthly cap and low-bitrate variants apply to every
+	// player; passing the warning level or stopping at the cap refreshes the
+	// now-playing bar.
+	if dc, err := storage.LoadDataSaverConfigFromUnified(); err == nil {
+		applyDataSaver(dc, app.apiClient)
+	}
+	player.SetDataCapHandler(func(p *player.MPVPlayer, stopped bool) {
+		if prog := app.program.Load(); prog != nil {
+			go prog.Send(dataCapMsg{player: p, stopped: stopped})
+		}
+	})
+
+	// Custom patterns for splitting track titles into artist and title.
+	if mc, err := storage.LoadMetadataConfigFromUnified(); err == nil {
+		player.SetMetadataPatterns(metadataPatternsFromConfig(mc))
+	}
+
+	// Ad filter rules are process-wide as well; skip requests come back as
+	// messages so the current screen can pick the next station.
+	if ac, err := storage.LoadAdFilterConfigFromUnified(); err == nil {
+		player.SetAdFilter(adFilterFromConfig(ac))
+	}
+	player.SetAdSkipHandler(func(p *player.MPVPlayer, _ *api.Station) {
+		if prog := app.program.Load(); prog != nil {
+			go prog.Send(adSkipMsg{player: p})
+		}
+	})
+
+	// Pick up lists, blocks, tags and the like that another TERA, a restore
+	// or a hand edit changed while this one is running.
+	watcher, err := storage.WatchData(dataPath, favPath, func(paths []string) {
+		if prog := app.program.Load(); prog != nil {
+			go prog.Send(dataFilesChangedMsg{paths: paths})
+		}
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to watch data files: %v\n", err)
+	} else {
+		app.dataWatcher = watcher
+	}
+
 	// Initialize header renderer
 	InitializeHeaderRenderer()
 
@@ -290,14 +466,49 @@ func (a *App) loadQuickFavorites() {
 }
 
 func (a *App) Init() tea.Cmd {
-	// Check for updates in the background on startup.
-	return tea.Batch(checkForUpdates())
+	// Check for updates, refresh due subscriptions and take a due backup in
+	// the background on startup.
+	return tea.Batch(checkForUpdates(), a.applyVisualizerConfig(a.visualizerCfg),
+		refreshSubscriptions(a.subscriptions, subscriptionStartupDelay),
+		scheduleAutoBackup(a.autoBackupCfg, autoBackupStartupDelay))
+}
+
+// Subscriptions are first checked shortly after startup, so the first frame
+// is not held up, then every subscriptionCheckInterval; each one is only
+// fetched when its own refresh interval has passed.
+const (
+	subscriptionStartupDelay  = 5 * time.Second
+	subscriptionCheckInterval = 15 * time.Minute
+)
+
+// subscriptionsRefreshedMsg reports a background subscription check.
+type subscriptionsRefreshedMsg struct {
+	refreshed []string
+}
+
+// refreshSubscriptions refreshes the due subscriptions after delay. Failures
+// are recorded with each subscription and retried at its next interval.
+func refreshSubscriptions(mgr *subscription.Manager, delay time.Duration) tea.Cmd {
+	if mgr == nil {
+		return nil
+	}
+	return tea.Tick(delay, func(now time.Time) tea.Msg {
+		refreshed, _ := mgr.RefreshDue(context.Background(), now)
+		return subscriptionsRefreshedMsg{refreshed: refreshed}
+	})
 }
 
 // Cleanup stops all players and releases resources for graceful shutdown.
 // This function is idempotent and safe to call multiple times.
 func (a *App) Cleanup() {
 	a.cleanupOnce.Do(func() {
+		if a.dataWatcher != nil {
+			_ = a.dataWatcher.Close()
+		}
+		// Stop accepting remote commands before tearing players down.
+		a.stopRemote()
+		a.stopRelay()
+
 		if a.sleepTimer != nil {
 			a.sleepTimer.Cancel()
 			a.sleepTimer = nil
@@ -321,6 +532,10 @@ func (a *App) Cleanup() {
 			}
 		}
 
+		// Log the playing station as ended by quitting, not by a stop
+		if a.metadataManager != nil {
+			a.metadataManager.StopCurrentPlay(storage.StopReasonExit)
+		}
 		if a.activePlayer != nil {
 			_ = a.activePlayer.Stop()
 			a.activePlayer = nil
@@ -361,11 +576,38 @@ func (a *App) Cleanup() {
 		if a.tagsManager != nil {
 			_ = a.tagsManager.Close()
 		}
+		// Close the database last so its JSON export has every change
+		if a.database != nil {
+			_ = a.database.Close()
+		}
+		// Back up once every change is on disk. The terminal may still be
+		// in the alternate screen here, so a failure is not reported; the
+		// next scheduled or exit backup tries again.
+		if a.autoBackupCfg.Enabled && a.autoBackupCfg.OnExit {
+			_, _ = runAutoBackup(a.autoBackupCfg, time.Now())
+		}
 	})
 }
 
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Plays started while handling msg are logged as coming from the
+	// screen that was showing.
+	if a.screen != a.playSourceScreen && a.metadataManager != nil {
+		a.playSourceScreen = a.screen
+		a.metadataManager.SetPlaySource(a.screen.playSource())
+	}
+
 	switch msg := msg.(type) {
+	case subscriptionsRefreshedMsg:
+		// Subscribed lists open from the refreshed copy next time
+		return a, refreshSubscriptions(a.subscriptions, subscriptionCheckInterval)
+
+	case autoBackupDoneMsg:
+		if msg.err != nil || msg.path != "" {
+			a.autoBackupErr = msg.err
+		}
+		return a, scheduleAutoBackup(a.autoBackupCfg, autoBackupCheckInterval)
+
 	case versionCheckMsg:
 		// Handle version check result (from startup or settings)
 		a.updateChecked = true
@@ -390,8 +632,24 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.broadcastNowPlayingBar()
 				return a, nil
 			}
+		case "u", "ctrl+r":
+			// Undo and redo library changes from any screen that isn't
+			// taking text or using the key itself.
+			if !a.helpModel.IsVisible() && !a.capturesKeys() {
+				return a, replayJournal(a.journal, msg.String() == "u", 1)
+			}
 		}
 
+	case journalReplayedMsg:
+		a.loadQuickFavorites()
+		return a, tea.Batch(a.showNotice(msg.notice), func() tea.Msg { return libraryChangedMsg{} })
+
+	case dataFilesChangedMsg:
+		if a.reloadData(msg.paths) {
+			return a, func() tea.Msg { return libraryChangedMsg{} }
+		}
+		return a, nil
+
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
 		a.height = msg.Height
@@ -457,6 +715,9 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.playScreen.tagsManager = a.tagsManager
 				a.playScreen.tagRenderer = components.NewTagRenderer()
 			}
+			a.playScreen.likedSongs = a.likedSongsManager
+			a.playScreen.notesManager = a.notesManager
+			a.playScreen.subscriptions = a.subscriptions
 			// Pass data path for sleep timer config
 			a.playScreen.dataPath = a.dataPath
 			// Sync running timer state so Z cancels rather than reopens the dialog.
@@ -504,6 +765,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.searchScreen.tagsManager = a.tagsManager
 				a.searchScreen.tagRenderer = components.NewTagRenderer()
 			}
+			a.searchScreen.likedSongs = a.likedSongsManager
 			// Sync running timer state so Z cancels rather than reopens the dialog.
 			a.searchScreen.sleepTimerActive = a.sleepTimer != nil
 			// Seed quickFavorites so duplicate detection works from first use.
@@ -547,6 +809,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.luckyScreen.tagsManager = a.tagsManager
 				a.luckyScreen.tagRenderer = components.NewTagRenderer()
 			}
+			a.luckyScreen.likedSongs = a.likedSongsManager
 			// Set dimensions immediately if we have them
 			if a.width > 0 && a.height > 0 {
 				a.luckyScreen.width = a.width
@@ -574,6 +837,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.mostPlayedScreen.tagsManager = a.tagsManager
 				a.mostPlayedScreen.tagRenderer = components.NewTagRenderer()
 			}
+			a.mostPlayedScreen.likedSongs = a.likedSongsManager
 			// Set dimensions immediately if we have them
 			if a.width > 0 && a.height > 0 {
 				a.mostPlayedScreen.width = a.width
@@ -601,6 +865,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.topRatedScreen.tagsManager = a.tagsManager
 				a.topRatedScreen.tagRenderer = components.NewTagRenderer()
 			}
+			a.topRatedScreen.likedSongs = a.likedSongsManager
 			// Set dimensions immediately if we have them
 			if a.width > 0 && a.height > 0 {
 				a.topRatedScreen.width = a.width
@@ -613,6 +878,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.browseTagsScreen.nowPlayingBar = a.buildNowPlayingBannerText()
 				// Pass play options so volume/behaviour is consistent
 				a.browseTagsScreen.playOptsCfg = a.playOptsCfg
+				a.browseTagsScreen.likedSongs = a.likedSongsManager
 				// Set metadata manager for play tracking
 				if a.metadataManager != nil && a.browseTagsScreen.player != nil {
 					a.browseTagsScreen.player.SetMetadataManager(a.metadataManager)
@@ -631,6 +897,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.tagPlaylistsScreen.nowPlayingBar = a.buildNowPlayingBannerText()
 				// Pass play options so volume/behaviour is consistent
 				a.tagPlaylistsScreen.playOptsCfg = a.playOptsCfg
+				a.tagPlaylistsScreen.likedSongs = a.likedSongsManager
 				// Set metadata manager for play tracking
 				if a.metadataManager != nil && a.tagPlaylistsScreen.player != nil {
 					a.tagPlaylistsScreen.player.SetMetadataManager(a.metadataManager)
@@ -645,6 +912,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, func() tea.Msg { return backToMainMsg{} }
 		case screenGist:
 			a.gistScreen = NewGistModel(a.favoritePath)
+			if a.autoBackupErr != nil && a.gistScreen.message == "" {
+				a.gistScreen.message = fmt.Sprintf("Warning: %v", a.autoBackupErr)
+				a.gistScreen.messageIsError = true
+			}
 			a.gistScreen.nowPlayingBar = a.buildNowPlayingBannerText()
 			// Set dimensions immediately if we have them
 			if a.width > 0 && a.height > 0 {
@@ -680,6 +951,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, a.shuffleSettingsScreen.Init()
 		case screenConnectionSettings:
 			a.connectionSettingsScreen = NewConnectionSettingsModel()
+			a.connectionSettingsScreen.metadataManager = a.metadataManager
 			a.connectionSettingsScreen.nowPlayingBar = a.buildNowPlayingBannerText()
 			// Set dimensions immediately if we have them
 			if a.width > 0 && a.height > 0 {
@@ -705,6 +977,48 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.blocklistScreen.height = a.height
 			}
 			return a, a.blocklistScreen.Init()
+		case screenLikedSongs:
+			a.likedSongsScreen = NewLikedSongsModel(a.likedSongsManager)
+			a.likedSongsScreen.nowPlayingBar = a.buildNowPlayingBannerText()
+			// Set dimensions immediately if we have them
+			if a.width > 0 && a.height > 0 {
+				m, _ := a.likedSongsScreen.Update(tea.WindowSizeMsg{Width: a.width, Height: a.height})
+				a.likedSongsScreen = m.(LikedSongsModel)
+			}
+			return a, a.likedSongsScreen.Init()
+		case screenCleanup:
+			a.cleanupScreen = NewCleanupModel(a.favoritePath)
+			a.cleanupScreen.nowPlayingBar = a.buildNowPlayingBannerText()
+			// Set dimensions immediately if we have them
+			if a.width > 0 && a.height > 0 {
+				m, _ := a.cleanupScreen.Update(tea.WindowSizeMsg{Width: a.width, Height: a.height})
+				a.cleanupScreen = m.(CleanupModel)
+			}
+			return a, a.cleanupScreen.Init()
+		case screenChanges:
+			a.changesScreen = NewChangesModel(a.journal)
+			a.changesScreen.nowPlayingBar = a.buildNowPlayingBannerText()
+			if a.width > 0 && a.height > 0 {
+				m, _ := a.changesScreen.Update(tea.WindowSizeMsg{Width: a.width, Height: a.height})
+				a.changesScreen = m.(ChangesModel)
+			}
+			return a, a.changesScreen.Init()
+		case screenTimeline:
+			a.timelineScreen = NewTimelineModel(a.dataPath)
+			a.timelineScreen.nowPlayingBar = a.buildNowPlayingBannerText()
+			if a.width > 0 && a.height > 0 {
+				m, _ := a.timelineScreen.Update(tea.WindowSizeMsg{Width: a.width, Height: a.height})
+				a.timelineScreen = m.(TimelineModel)
+			}
+			return a, a.timelineScreen.Init()
+		case screenStats:
+			a.statsScreen = NewStatsModel(a.dataPath, a.metadataManager)
+			a.statsScreen.nowPlayingBar = a.buildNowPlayingBannerText()
+			if a.width > 0 && a.height > 0 {
+				m, _ := a.statsScreen.Update(tea.WindowSizeMsg{Width: a.width, Height: a.height})
+				a.statsScreen = m.(StatsModel)
+			}
+			return a, a.statsScreen.Init()
 		case screenMainMenu:
 			// Return to main menu and reload favorites and play history
 			a.loadQuickFavorites()
@@ -800,6 +1114,55 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.searchScreen.sleepCountdown = ""
 		return a, nil
 
+	case remoteRequestMsg:
+		return a, a.handleRemoteRequest(msg)
+
+	case visualizerConfigMsg:
+		return a, a.applyVisualizerConfig(msg.cfg)
+
+	case timeShiftConfigMsg:
+		player.SetTimeShift(msg.cfg.Depth())
+		return a, nil
+
+	case resumeConfigMsg:
+		player.SetNetworkResume(msg.cfg.Timeout())
+		return a, nil
+
+	case dataSaverConfigMsg:
+		applyDataSaver(msg.cfg, a.apiClient)
+		return a, nil
+
+	case sessionLogConfigMsg:
+		if a.metadataManager != nil {
+			var log *storage.SessionLog
+			if msg.cfg.Enabled {
+				log = storage.NewSessionLog(a.dataPath, msg.cfg.RetentionDays)
+			}
+			a.metadataManager.SetSessionLog(log)
+		}
+		return a, nil
+
+	case dataCapMsg:
+		if msg.stopped && msg.player == a.activePlayer {
+			a.activePlayer = nil
+			a.activeStation = nil
+			a.activeContextLabel = ""
+		}
+		a.broadcastNowPlayingBar()
+		// fall through so player screens show the cap status
+
+	case networkStatusMsg:
+		if msg.player == a.activePlayer {
+			a.broadcastNowPlayingBar()
+		}
+		// fall through so player screens redraw their status line
+
+	case visualizerTickMsg:
+		return a, a.sampleAudioLevels()
+
+	case audioLevelsMsg:
+		return a, a.handleAudioLevels(msg)
+
 	case handoffPlaybackMsg:
 		// A play screen is navigating away with ContinueOnNavigate=true.
 		// Stop any previously handed-off player before accepting the new one.
@@ -812,6 +1175,13 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.broadcastNowPlayingBar()
 		return a, nil
 
+	case adSkipMsg:
+		// Players the app holds have no list to move on to, so a skip rule
+		// stops them; other players are left to the screen that owns them.
+		if cmd, ok := a.skipAppPlayback(msg.player); ok {
+			return a, cmd
+		}
+
 	case stopActivePlaybackMsg:
 		// Stop the app-level handed-off player and clear its state.
 		if a.activePlayer != nil {
@@ -959,6 +1329,31 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.screen = screenMainMenu
 		}
 		return a, cmd
+	case screenLikedSongs:
+		var m tea.Model
+		m, cmd = a.likedSongsScreen.Update(msg)
+		a.likedSongsScreen = m.(LikedSongsModel)
+		return a, cmd
+	case screenCleanup:
+		var m tea.Model
+		m, cmd = a.cleanupScreen.Update(msg)
+		a.cleanupScreen = m.(CleanupModel)
+		return a, cmd
+	case screenChanges:
+		var m tea.Model
+		m, cmd = a.changesScreen.Update(msg)
+		a.changesScreen = m.(ChangesModel)
+		return a, cmd
+	case screenTimeline:
+		var m tea.Model
+		m, cmd = a.timelineScreen.Update(msg)
+		a.timelineScreen = m.(TimelineModel)
+		return a, cmd
+	case screenStats:
+		var m tea.Model
+		m, cmd = a.statsScreen.Update(msg)
+		a.statsScreen = m.(StatsModel)
+		return a, cmd
 	case screenMostPlayed:
 		a.mostPlayedScreen, cmd = a.mostPlayedScreen.Update(msg)
 		return a, cmd
@@ -1491,8 +1886,18 @@ func (a *App) buildNowPlayingBannerText() string {
 	if a.activeContextLabel != "" {
 		bar += "  ·  [" + a.activeContextLabel + "]"
 	}
+	if status := networkStatusText(a.activePlayer); status != "" {
+		bar += "  ·  " + status
+	}
+	if status := dataUsageText(a.activePlayer); status != "" {
+		bar += "  ·  " + status
+	}
 	bar += "  ·  x: Stop"
-	return successStyle().Render(bar)
+	bar = successStyle().Render(bar)
+	if a.audioLevels != nil && a.activePlayer != nil {
+		bar += "  " + renderCompactMeter(*a.audioLevels)
+	}
+	return bar
 }
 
 // broadcastNowPlayingBar pushes the current now-playing banner to every
@@ -1507,6 +1912,11 @@ func (a *App) broadcastNowPlayingBar() {
 	a.connectionSettingsScreen.nowPlayingBar = bar
 	a.appearanceSettingsScreen.nowPlayingBar = bar
 	a.blocklistScreen.nowPlayingBar = bar
+	a.likedSongsScreen.nowPlayingBar = bar
+	a.cleanupScreen.nowPlayingBar = bar
+	a.changesScreen.nowPlayingBar = bar
+	a.timelineScreen.nowPlayingBar = bar
+	a.statsScreen.nowPlayingBar = bar
 	a.sleepSummary.nowPlayingBar = bar
 	// Player screens (shown in list/browse states when ContinueOnNavigate is on)
 	a.playScreen.nowPlayingBar = bar
@@ -1518,6 +1928,203 @@ func (a *App) broadcastNowPlayingBar() {
 	a.tagPlaylistsScreen.nowPlayingBar = bar
 }
 
+// capturesKeys reports whether the current screen takes u and ctrl+r
+// itself, which it does while text is typed or a dialog is open.
+func (a *App) capturesKeys() bool {
+	switch a.screen {
+	case screenMainMenu:
+		return a.mainMenuList.SettingFilter()
+	case screenPlay:
+		return a.playScreen.capturesKeys()
+	case screenSearch:
+		return a.searchScreen.capturesKeys()
+	case screenList:
+		return a.listManagementScreen.capturesKeys()
+	case screenLucky:
+		return a.luckyScreen.capturesKeys()
+	case screenMostPlayed:
+		return a.mostPlayedScreen.capturesKeys()
+	case screenTopRated:
+		return a.topRatedScreen.capturesKeys()
+	case screenBrowseTags:
+		return a.browseTagsScreen.capturesKeys()
+	case screenTagPlaylists:
+		return a.tagPlaylistsScreen.capturesKeys()
+	case screenGist:
+		return a.gistScreen.capturesKeys()
+	case screenAppearanceSettings:
+		return a.appearanceSettingsScreen.capturesKeys()
+	case screenBlocklist:
+		return a.blocklistScreen.capturesKeys()
+	case screenLikedSongs:
+		return a.likedSongsScreen.capturesKeys()
+	case screenCleanup:
+		return a.cleanupScreen.capturesKeys()
+	}
+	return false
+}
+
+// reloadData rereads the stores whose files another process changed and
+// reports whether any of them did. Each store checks its own file, so the
+// paths only matter for the favorites, which have no store of their own.
+func (a *App) reloadData(paths []string) bool {
+	changed := false
+	for _, path := range paths {
+		if a.dataWatcher != nil && a.dataWatcher.IsFavoritesPath(path) {
+			changed = true
+			break
+		}
+	}
+	if changed {
+		// With the SQLite backend the lists are read from the database,
+		// so take in what was written to the directory first.
+		_, _ = storage.NewStorage(a.favoritePath).Reload()
+		a.loadQuickFavorites()
+	}
+
+	var reloaders []func() (bool, error)
+	if a.blocklistManager != nil {
+		reloaders = append(reloaders, a.blocklistManager.Reload)
+	}
+	if a.metadataManager != nil {
+		reloaders = append(reloaders, a.metadataManager.Reload)
+	}
+	if a.ratingsManager != nil {
+		reloaders = append(reloaders, a.ratingsManager.Reload)
+	}
+	if a.tagsManager != nil {
+		reloaders = append(reloaders, a.tagsManager.Reload)
+	}
+	if a.notesManager != nil {
+		reloaders = append(reloaders, a.notesManager.Reload)
+	}
+	if a.likedSongsManager != nil {
+		reloaders = append(reloaders, a.likedSongsManager.Reload)
+	}
+	if a.journal != nil {
+		reloaders = append(reloaders, a.journal.Reload)
+	}
+	for _, reload := range reloaders {
+		// A file that can't be read now keeps the copy in memory; the
+		// next change to it tries again.
+		if ok, err := reload(); ok && err == nil {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// skipAppPlayback stops p if it is the handed-off player or the main menu's
+// quick play, and says so. It reports false when p belongs to a screen.
+func (a *App) skipAppPlayback(p *player.MPVPlayer) (tea.Cmd, bool) {
+	var station *api.Station
+	switch {
+	case p == nil:
+		return nil, false
+	case p == a.activePlayer:
+		station = a.activeStation
+		_ = p.Stop()
+		a.activePlayer = nil
+		a.activeStation = nil
+		a.activeContextLabel = ""
+		a.broadcastNowPlayingBar()
+	case p == a.quickFavPlayer && a.playingFromMain:
+		station = a.playingStation
+		_ = p.Stop()
+		a.playingFromMain = false
+		a.playingStation = nil
+	default:
+		return nil, false
+	}
+	notice := "Stopped by ad filter"
+	if station != nil {
+		notice = fmt.Sprintf("Stopped %s: ad filter skip rule matched", station.TrimName())
+	}
+	return a.showNotice(notice), true
+}
+
+// showNotice shows a short message, such as the outcome of an undo or redo,
+// in the current screen's message line.
+func (a *App) showNotice(notice string) tea.Cmd {
+	switch a.screen {
+	case screenMainMenu:
+		startTick := a.volumeDisplayFrames <= 0
+		a.volumeDisplay = notice
+		a.volumeDisplayFrames = messageDisplayShort
+		if startTick {
+			return tickEverySecond()
+		}
+	case screenPlay:
+		startTick := a.playScreen.saveMessageTime <= 0 && !a.playScreen.sleepTimerActive
+		a.playScreen.saveMessage = notice
+		a.playScreen.saveMessageTime = messageDisplayShort
+		if startTick {
+			return tickEverySecond()
+		}
+	case screenSearch:
+		startTick := a.searchScreen.saveMessageTime <= 0 && !a.searchScreen.sleepTimerActive
+		a.searchScreen.saveMessage = notice
+		a.searchScreen.saveMessageTime = messageDisplayShort
+		if startTick {
+			return tickEverySecond()
+		}
+	case screenLucky:
+		// The lucky screen ticks all the time
+		a.luckyScreen.saveMessage = notice
+		a.luckyScreen.saveMessageTime = messageDisplayShort
+	case screenMostPlayed:
+		startTick := a.mostPlayedScreen.saveMessageTime <= 0
+		a.mostPlayedScreen.saveMessage = notice
+		a.mostPlayedScreen.saveMessageTime = messageDisplayShort
+		if startTick {
+			return tickEverySecond()
+		}
+	case screenTopRated:
+		startTick := a.topRatedScreen.saveMessageTime <= 0
+		a.topRatedScreen.saveMessage = notice
+		a.topRatedScreen.saveMessageTime = messageDisplayShort
+		if startTick {
+			return tickEverySecond()
+		}
+	case screenBrowseTags:
+		startTick := a.browseTagsScreen.saveMessageTime <= 0
+		a.browseTagsScreen.saveMessage = notice
+		a.browseTagsScreen.saveMessageTime = messageDisplayShort
+		if startTick {
+			return tickEverySecond()
+		}
+	case screenTagPlaylists:
+		startTick := a.tagPlaylistsScreen.saveMessageTime <= 0
+		a.tagPlaylistsScreen.saveMessage = notice
+		a.tagPlaylistsScreen.saveMessageTime = messageDisplayShort
+		if startTick {
+			return tickEverySecond()
+		}
+	case screenList:
+		a.listManagementScreen.message = notice
+		a.listManagementScreen.messageTime = 180
+	case screenBlocklist:
+		a.blocklistScreen.message = notice
+		a.blocklistScreen.messageTime = 180
+	case screenLikedSongs:
+		a.likedSongsScreen.message = notice
+		a.likedSongsScreen.messageTime = 180
+	case screenCleanup:
+		a.cleanupScreen.message = notice
+		a.cleanupScreen.messageTime = 180
+	case screenChanges:
+		a.changesScreen.message = notice
+		a.changesScreen.messageTime = 180
+	case screenTimeline:
+		a.timelineScreen.message = notice
+		a.timelineScreen.messageTime = 180
+	case screenStats:
+		a.statsScreen.message = notice
+		a.statsScreen.messageTime = 180
+	}
+	return nil
+}
+
 func (a *App) View() string {
 	var view string
 	switch a.screen {
@@ -1543,6 +2150,16 @@ func (a *App) View() string {
 		view = a.appearanceSettingsScreen.View()
 	case screenBlocklist:
 		view = a.blocklistScreen.View()
+	case screenLikedSongs:
+		view = a.likedSongsScreen.View()
+	case screenCleanup:
+		view = a.cleanupScreen.View()
+	case screenChanges:
+		view = a.changesScreen.View()
+	case screenTimeline:
+		view = a.timelineScreen.View()
+	case screenStats:
+		view = a.statsScreen.View()
 	case screenMostPlayed:
 		view = a.mostPlayedScreen.View()
 	case screenTopRated:
@@ -657,3 +657,16 @@ func (m AppearanceSettingsModel) viewPreview() string {
 
 	return b.String()
 }
+
+// capturesKeys is true in the header text, width, color and padding inputs.
+func (m AppearanceSettingsModel) capturesKeys() bool {
+	if m.helpModel.IsVisible() {
+		return true
+	}
+	switch m.state {
+	case appearanceStateTextInput, appearanceStateAsciiInput, appearanceStateWidthInput,
+		appearanceStateColorInput, appearanceStatePaddingInput:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/gist"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// Scheduled backups are first considered a while after startup, then every
+// autoBackupCheckInterval; one is only taken once the newest is as old as
+// the configured interval, so restarting TERA does not add generations.
+const (
+	autoBackupStartupDelay  = time.Minute
+	autoBackupCheckInterval = time.Hour
+)
+
+// autoBackupDoneMsg reports a scheduled backup check. path is empty when no
+// backup was due.
+type autoBackupDoneMsg struct {
+	path string
+	err  error
+}
+
+// scheduleAutoBackup takes a backup after delay if one is due. It returns
+// nil when backups are off or only taken on exit.
+func scheduleAutoBackup(cfg config.AutoBackupConfig, delay time.Duration) tea.Cmd {
+	every := cfg.IntervalDuration()
+	if !cfg.Enabled || every == 0 {
+		return nil
+	}
+	return tea.Tick(delay, func(now time.Time) tea.Msg {
+		bm, err := storage.NewBackupManager()
+		if err != nil {
+			return autoBackupDoneMsg{err: err}
+		}
+		dir, err := bm.AutoBackupDir(cfg.Dir)
+		if err != nil {
+			return autoBackupDoneMsg{err: err}
+		}
+		if due, err := storage.AutoBackupDue(dir, every, now); err != nil || !due {
+			return autoBackupDoneMsg{err: err}
+		}
+		path, err := runAutoBackup(cfg, now)
+		return autoBackupDoneMsg{path: path, err: err}
+	})
+}
+
+// runAutoBackup exports the categories last chosen in Sync & Backup to a new
+// generation and rotates the old ones.
+func runAutoBackup(cfg config.AutoBackupConfig, now time.Time) (string, error) {
+	bm, err := storage.NewBackupManager()
+	if err != nil {
+		return "", err
+	}
+	dir, err := bm.AutoBackupDir(cfg.Dir)
+	if err != nil {
+		return "", err
+	}
+	var passphrase string
+	if cfg.Encrypt {
+		if passphrase = gist.LoadBackupPassphrase(); passphrase == "" {
+			return "", fmt.Errorf("auto_backup.encrypt is set but no backup passphrase is remembered in the keychain")
+		}
+	}
+	prefs, _ := storage.LoadSyncPrefs()
+	path, err := bm.AutoBackup(dir, prefs, passphrase, now)
+	if err != nil {
+		return "", fmt.Errorf("automatic backup failed: %w", err)
+	}
+	if _, err := storage.RotateBackups(dir, storage.BackupRetention{
+		KeepLast:   cfg.KeepLast,
+		KeepDaily:  cfg.KeepDaily,
+		KeepWeekly: cfg.KeepWeekly,
+	}); err != nil {
+		return path, fmt.Errorf("failed to remove old backups: %w", err)
+	}
+	return path, nil
+}
@@ -229,6 +229,9 @@ func (m BlocklistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.messageTime = 180 // 3 seconds (at ~60fps)
 		return m, nil
 
+	case libraryChangedMsg:
+		return m, tea.Batch(m.loadBlockedStations(), m.loadBlockRules())
+
 	case blockRulesLoadedMsg:
 		m.rules = msg.rules
 		m.rulesListModel = createRulesListModel(msg.rules)
@@ -1017,3 +1020,13 @@ func (m BlocklistModel) renderPageWithBottomHelp(layout PageLayout, height int)
 	layout.NowPlaying = m.nowPlayingBar
 	return RenderPageWithBottomHelp(layout, height)
 }
+
+// capturesKeys is true while a rule value is typed, and in the blocked
+// stations view, where u unblocks the selected station.
+func (m BlocklistModel) capturesKeys() bool {
+	switch m.state {
+	case blocklistViewStations, blocklistBlockByCountry, blocklistBlockByLanguage, blocklistBlockByTag:
+		return true
+	}
+	return false
+}
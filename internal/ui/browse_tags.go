@@ -81,6 +81,7 @@ func (m BrowseTagsModel) handleConfirmStopInput(msg tea.KeyMsg) (BrowseTagsModel
 type BrowseTagsModel struct {
 	state            browseTagsState
 	tagsManager      *storage.TagsManager
+	likedSongs       *storage.LikedSongsManager // bookmarks for the "l" key
 	ratingsManager   *storage.RatingsManager
 	metadataManager  *storage.MetadataManager
 	blocklistManager *blocklist.Manager
@@ -463,6 +464,11 @@ func (m BrowseTagsModel) updatePlaying(msg tea.KeyMsg) (BrowseTagsModel, tea.Cmd
 				m.saveMessageTime = messageDisplayShort
 			}
 		}
+	case "l":
+		if msg := likeCurrentSong(m.likedSongs, m.player, m.selectedStation); msg != "" {
+			m.saveMessage = msg
+			m.saveMessageTime = messageDisplayShort
+		}
 	case "r":
 		if m.selectedStation != nil && m.ratingsManager != nil {
 			m.ratingMode = true
@@ -752,8 +758,10 @@ func (m BrowseTagsModel) viewPlaying() string {
 
 	sb.WriteString("\n")
 	if m.player != nil && m.player.IsPlaying() {
-		if track := m.player.GetCachedTrack(); IsValidTrackMetadata(track, m.selectedStation.TrimName()) {
-			sb.WriteString(successStyle().Render("▶ Now Playing:") + " " + infoStyle().Render(track))
+		if status := networkStatusText(m.player); status != "" {
+			sb.WriteString(highlightStyle().Render(status))
+		} else if track := m.player.GetCachedTrack(); IsValidTrackMetadata(track, m.selectedStation.TrimName()) {
+			sb.WriteString(successStyle().Render("▶ Now Playing:") + " " + infoStyle().Render(formatTrack(m.player, track)))
 		} else {
 			sb.WriteString(successStyle().Render("▶ Playing..."))
 		}
@@ -793,3 +801,11 @@ func (m BrowseTagsModel) renderPageWithBottomHelp(layout PageLayout, height int)
 	}
 	return RenderPageWithBottomHelp(layout, height)
 }
+
+// capturesKeys is true in the tag dialogs.
+func (m BrowseTagsModel) capturesKeys() bool {
+	if m.helpModel.IsVisible() {
+		return true
+	}
+	return m.state == browseTagsStateTagInput || m.state == browseTagsStateManageTags
+}
@@ -0,0 +1,209 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// ChangesModel lists the recent changes kept in the undo journal. u and
+// ctrl+r work here as everywhere; Enter undoes or redoes every change up to
+// the selected one.
+type ChangesModel struct {
+	journal       *storage.Journal
+	entries       []storage.JournalEntry // newest first
+	listModel     list.Model
+	message       string
+	messageTime   int
+	width         int
+	height        int
+	nowPlayingBar string // set by App when ContinueOnNavigate is active
+}
+
+// changeItem is one journal entry in the recent changes list.
+type changeItem struct {
+	entry storage.JournalEntry
+}
+
+func (i changeItem) Title() string {
+	if i.entry.Undone {
+		return "↶ " + i.entry.Description + " (undone)"
+	}
+	return i.entry.Description
+}
+
+func (i changeItem) Description() string {
+	return storage.FormatLastPlayed(i.entry.At)
+}
+
+func (i changeItem) FilterValue() string { return i.entry.Description }
+
+// NewChangesModel creates the recent changes screen
+func NewChangesModel(journal *storage.Journal) ChangesModel {
+	l := list.New([]list.Item{}, createStyledDelegate(), 80, 20)
+	l.Title = "↶ Recent Changes"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	l.SetShowPagination(true)
+	l.Styles.Title = listTitleStyle()
+	l.Styles.PaginationStyle = paginationStyle()
+
+	m := ChangesModel{journal: journal, listModel: l}
+	m.reload()
+	return m
+}
+
+// Init initializes the recent changes screen
+func (m ChangesModel) Init() tea.Cmd {
+	return nil
+}
+
+// reload reads the journal again, keeping the selected row.
+func (m *ChangesModel) reload() {
+	idx := m.listModel.Index()
+	m.entries = m.journal.Entries()
+	items := make([]list.Item, len(m.entries))
+	for i, e := range m.entries {
+		items[i] = changeItem{entry: e}
+	}
+	m.listModel.SetItems(items)
+	if idx >= len(items) {
+		idx = len(items) - 1
+	}
+	if idx >= 0 {
+		m.listModel.Select(idx)
+	}
+}
+
+// Update handles messages for the recent changes screen
+func (m ChangesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Decrement message timer
+	if m.messageTime > 0 {
+		m.messageTime--
+		if m.messageTime == 0 {
+			m.message = ""
+		}
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg { return navigateMsg{screen: screenList} }
+		case "0":
+			return m, func() tea.Msg { return navigateMsg{screen: screenMainMenu} }
+		case "q":
+			return m, tea.Quit
+		case "enter":
+			return m, m.replayTo(m.listModel.Index())
+		}
+
+	case libraryChangedMsg:
+		m.reload()
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		listHeight := msg.Height - 12
+		if listHeight < 5 {
+			listHeight = 5
+		}
+		m.listModel.SetSize(msg.Width-4, listHeight)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.listModel, cmd = m.listModel.Update(msg)
+	return m, cmd
+}
+
+// replayTo undoes the changes from the newest down to entry idx, or, when
+// idx is undone, redoes the changes up to it.
+func (m ChangesModel) replayTo(idx int) tea.Cmd {
+	if idx < 0 || idx >= len(m.entries) {
+		return nil
+	}
+	undo := !m.entries[idx].Undone
+	count := 0
+	for i, e := range m.entries {
+		if undo && i <= idx && !e.Undone {
+			count++
+		}
+		if !undo && i >= idx && e.Undone {
+			count++
+		}
+	}
+	return replayJournal(m.journal, undo, count)
+}
+
+// replayJournal undoes or redoes count changes and reports what it did.
+func replayJournal(journal *storage.Journal, undo bool, count int) tea.Cmd {
+	return func() tea.Msg {
+		verb, replay := "Undone", journal.Undo
+		if !undo {
+			verb, replay = "Redone", journal.Redo
+		}
+		var last string
+		done := 0
+		for done < count {
+			desc, err := replay()
+			if err != nil {
+				if done > 0 {
+					break
+				}
+				if errors.Is(err, storage.ErrNothingToUndo) {
+					return journalReplayedMsg{notice: "Nothing to undo"}
+				}
+				if errors.Is(err, storage.ErrNothingToRedo) {
+					return journalReplayedMsg{notice: "Nothing to redo"}
+				}
+				return journalReplayedMsg{notice: fmt.Sprintf("✗ %v", err)}
+			}
+			last = desc
+			done++
+		}
+		if done == 1 {
+			return journalReplayedMsg{notice: fmt.Sprintf("✓ %s: %s", verb, last)}
+		}
+		return journalReplayedMsg{notice: fmt.Sprintf("✓ %s %d changes", verb, done)}
+	}
+}
+
+// View renders the recent changes screen
+func (m ChangesModel) View() string {
+	var content strings.Builder
+	if m.message != "" {
+		style := successStyle()
+		if strings.Contains(m.message, "✗") {
+			style = errorStyle()
+		} else if !strings.Contains(m.message, "✓") {
+			style = infoStyle()
+		}
+		content.WriteString(style.Render(m.message))
+		content.WriteString("\n\n")
+	}
+	if len(m.entries) == 0 {
+		content.WriteString(infoStyle().Render("No changes yet."))
+	} else {
+		content.WriteString(m.listModel.View())
+	}
+
+	return m.renderPageWithBottomHelp(PageLayout{
+		Title:    "Recent Changes",
+		Subtitle: fmt.Sprintf("%d change(s) from the last %d days", len(m.entries), int(storage.JournalMaxAge.Hours()/24)),
+		Content:  content.String(),
+		Help:     "↑↓/jk: Navigate • Enter: Undo/redo up to here • u: Undo • Ctrl+R: Redo • Esc: Back • 0: Main Menu",
+	}, m.height)
+}
+
+// renderPageWithBottomHelp wraps RenderPageWithBottomHelp injecting the active now-playing bar.
+func (m ChangesModel) renderPageWithBottomHelp(layout PageLayout, height int) string {
+	layout.NowPlaying = m.nowPlayingBar
+	return RenderPageWithBottomHelp(layout, height)
+}
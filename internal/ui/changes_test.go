@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+func TestChangesUndoUpToSelected(t *testing.T) {
+	journal, err := storage.OpenJournal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage.SetJournal(journal)
+	t.Cleanup(func() { storage.SetJournal(nil) })
+
+	dir := t.TempDir()
+	store := storage.NewStorage(dir)
+	journal.Handle(storage.JournalFavorites, store.ApplyJournal)
+	ctx := context.Background()
+	for _, st := range []api.Station{{StationUUID: "a", Name: "Jazz FM"}, {StationUUID: "b", Name: "Rock FM"}, {StationUUID: "c", Name: "Folk FM"}} {
+		if err := store.AddStation(ctx, "Mix", st); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := NewChangesModel(journal)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	m = updated.(ChangesModel)
+	if len(m.entries) != 3 {
+		t.Fatalf("expected 3 changes, got %d", len(m.entries))
+	}
+	if !strings.Contains(m.View(), "Added Folk FM to Mix") {
+		t.Error("view does not list the newest change")
+	}
+
+	// Undo the two newest changes at once
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(ChangesModel)
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected a replay command")
+	}
+	msg, ok := cmd().(journalReplayedMsg)
+	if !ok || msg.notice != "✓ Undone 2 changes" {
+		t.Fatalf("notice = %+v", msg)
+	}
+	list, err := store.LoadList(ctx, "Mix")
+	if err != nil || len(list.Stations) != 1 || list.Stations[0].StationUUID != "a" {
+		t.Fatalf("Mix after undo = %+v, %v", list, err)
+	}
+
+	updated, _ = m.Update(libraryChangedMsg{})
+	m = updated.(ChangesModel)
+	if !strings.Contains(m.View(), "(undone)") {
+		t.Error("undone changes are not marked")
+	}
+
+	// Redo brings back the oldest undone change first
+	msg = replayJournal(journal, false, 1)().(journalReplayedMsg)
+	if msg.notice != "✓ Redone: Added Rock FM to Mix" {
+		t.Errorf("redo notice = %q", msg.notice)
+	}
+	if msg := replayJournal(nil, true, 1)().(journalReplayedMsg); msg.notice != "Nothing to undo" {
+		t.Errorf("undo without a journal = %q", msg.notice)
+	}
+}
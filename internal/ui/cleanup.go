@@ -0,0 +1,598 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// cleanupState represents the current state in the library cleanup screen
+type cleanupState int
+
+const (
+	cleanupGroups cleanupState = iota
+	cleanupKeep
+	cleanupMergeSources
+	cleanupMergeTarget
+	cleanupMergeDelete
+	cleanupMoveSource
+	cleanupMoveStations
+	cleanupMoveTarget
+	cleanupPreview
+	cleanupConfirmUndo
+)
+
+// CleanupModel finds duplicate stations across favorites lists and merges
+// lists or moves stations between them. Every change is previewed before it
+// is written, and the last one can be undone.
+type CleanupModel struct {
+	state         cleanupState
+	store         *storage.Storage
+	groups        []storage.DuplicateGroup
+	lists         []string
+	group         storage.DuplicateGroup // group being resolved in cleanupKeep
+	stations      []api.Station          // stations of moveFrom
+	picked        map[int]bool           // rows ticked in the multi-select states
+	sources       []string               // lists being merged
+	moveFrom      string
+	plan          *storage.CleanupPlan
+	listModel     list.Model
+	targetInput   textinput.Model
+	message       string
+	messageTime   int
+	width         int
+	height        int
+	nowPlayingBar string // set by App when ContinueOnNavigate is active
+}
+
+// cleanupItem is one row of the cleanup screen's list.
+type cleanupItem struct {
+	title string
+}
+
+func (i cleanupItem) Title() string       { return i.title }
+func (i cleanupItem) Description() string { return "" }
+func (i cleanupItem) FilterValue() string { return i.title }
+
+// NewCleanupModel creates the library cleanup screen
+func NewCleanupModel(favoritePath string) CleanupModel {
+	l := list.New([]list.Item{}, createStyledDelegate(), 80, 20)
+	l.Title = "🧹 Library Cleanup"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	l.SetShowPagination(true)
+	l.Styles.Title = listTitleStyle()
+	l.Styles.PaginationStyle = paginationStyle()
+
+	ti := textinput.New()
+	ti.Placeholder = "List name (use / for folders)"
+	ti.CharLimit = 100
+
+	m := CleanupModel{
+		state:       cleanupGroups,
+		store:       storage.NewStorage(favoritePath),
+		listModel:   l,
+		targetInput: ti,
+	}
+	m.rescan()
+	return m
+}
+
+// Init initializes the library cleanup screen
+func (m CleanupModel) Init() tea.Cmd {
+	return nil
+}
+
+// rescan reloads the lists and duplicate groups and shows the groups.
+func (m *CleanupModel) rescan() {
+	ctx := context.Background()
+	groups, err := m.store.FindDuplicates(ctx)
+	if err != nil {
+		m.message = fmt.Sprintf("✗ Failed to scan lists: %v", err)
+		m.messageTime = 180
+	}
+	m.groups = groups
+	m.lists, _ = m.store.GetAllLists(ctx)
+	m.showGroups()
+}
+
+func (m *CleanupModel) setItems(titles []string) {
+	items := make([]list.Item, len(titles))
+	for i, t := range titles {
+		items[i] = cleanupItem{title: t}
+	}
+	m.listModel.SetItems(items)
+	m.listModel.Select(0)
+}
+
+func (m *CleanupModel) showGroups() {
+	m.state = cleanupGroups
+	titles := make([]string, len(m.groups))
+	for i, g := range m.groups {
+		titles[i] = groupTitle(g)
+	}
+	m.setItems(titles)
+}
+
+// groupTitle describes a duplicate group in one line.
+func groupTitle(g storage.DuplicateGroup) string {
+	what := g.Key
+	if g.Kind == storage.DuplicateUUID {
+		what = g.Refs[0].Station.TrimName()
+	}
+	return fmt.Sprintf("%s: %s  —  %d entries in %s", g.Kind, what, len(g.Refs), strings.Join(g.Lists(), ", "))
+}
+
+// showPicker lists titles with a tick box each, for the multi-select states.
+func (m *CleanupModel) showPicker(state cleanupState, titles []string) {
+	m.state = state
+	m.picked = make(map[int]bool)
+	m.setItems(titles)
+	m.refreshPicker()
+}
+
+func (m *CleanupModel) refreshPicker() {
+	idx := m.listModel.Index()
+	items := m.listModel.Items()
+	for i, item := range items {
+		title := strings.TrimPrefix(strings.TrimPrefix(item.(cleanupItem).title, "[x] "), "[ ] ")
+		box := "[ ] "
+		if m.picked[i] {
+			box = "[x] "
+		}
+		items[i] = cleanupItem{title: box + title}
+	}
+	m.listModel.SetItems(items)
+	m.listModel.Select(idx)
+}
+
+func (m *CleanupModel) setMessage(msg string) {
+	m.message = msg
+	m.messageTime = 180 // 3 seconds (at ~60fps)
+}
+
+// preview shows plan for confirmation, or why there is nothing to do.
+func (m *CleanupModel) preview(plan *storage.CleanupPlan, err error) {
+	switch {
+	case err != nil:
+		m.setMessage(fmt.Sprintf("✗ %v", err))
+		m.showGroups()
+	case plan.Empty():
+		m.setMessage("Nothing to change")
+		m.showGroups()
+	default:
+		m.plan = plan
+		m.state = cleanupPreview
+	}
+}
+
+func (m *CleanupModel) startTarget(state cleanupState, value string) tea.Cmd {
+	m.state = state
+	m.targetInput.SetValue(value)
+	m.targetInput.CursorEnd()
+	m.targetInput.Focus()
+	return textinput.Blink
+}
+
+// Update handles messages for the library cleanup screen
+func (m CleanupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Decrement message timer
+	if m.messageTime > 0 {
+		m.messageTime--
+		if m.messageTime == 0 {
+			m.message = ""
+		}
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch m.state {
+		case cleanupKeep:
+			return m.handleKeepInput(msg)
+		case cleanupMergeSources, cleanupMoveStations:
+			return m.handlePickerInput(msg)
+		case cleanupMoveSource:
+			return m.handleMoveSourceInput(msg)
+		case cleanupMergeTarget, cleanupMoveTarget:
+			return m.handleTargetInput(msg)
+		case cleanupMergeDelete:
+			return m.handleMergeDeleteInput(msg)
+		case cleanupPreview:
+			return m.handlePreviewInput(msg)
+		case cleanupConfirmUndo:
+			return m.handleConfirmUndoInput(msg)
+		}
+		return m.handleGroupsInput(msg)
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		// Same page overhead as the liked songs screen
+		listHeight := msg.Height - 14
+		if listHeight < 5 {
+			listHeight = 5
+		}
+		m.listModel.SetSize(msg.Width-4, listHeight)
+		return m, nil
+
+	case libraryChangedMsg:
+		// Plans in progress are checked against the lists when applied
+		if m.state == cleanupGroups {
+			m.rescan()
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.listModel, cmd = m.listModel.Update(msg)
+	return m, cmd
+}
+
+func (m CleanupModel) handleGroupsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	ctx := context.Background()
+	switch msg.String() {
+	case "esc":
+		return m, func() tea.Msg { return navigateMsg{screen: screenList} }
+	case "0":
+		return m, func() tea.Msg { return navigateMsg{screen: screenMainMenu} }
+	case "q":
+		return m, tea.Quit
+	case "enter":
+		idx := m.listModel.Index()
+		if idx < 0 || idx >= len(m.groups) {
+			return m, nil
+		}
+		m.group = m.groups[idx]
+		m.state = cleanupKeep
+		titles := make([]string, len(m.group.Refs))
+		for i, ref := range m.group.Refs {
+			titles[i] = fmt.Sprintf("%s  (%s #%d)  %s", ref.Station.TrimName(), ref.List, ref.Index+1, ref.Station.URLResolved)
+		}
+		m.setItems(titles)
+		return m, nil
+	case "a":
+		m.preview(m.store.PlanDedupeLists(ctx))
+		return m, nil
+	case "m":
+		if len(m.lists) == 0 {
+			m.setMessage("No lists to merge")
+			return m, nil
+		}
+		m.showPicker(cleanupMergeSources, m.lists)
+		return m, nil
+	case "v":
+		if len(m.lists) == 0 {
+			m.setMessage("No lists to move stations from")
+			return m, nil
+		}
+		m.state = cleanupMoveSource
+		m.setItems(m.lists)
+		return m, nil
+	case "u":
+		if m.store.LastCleanup() == "" {
+			m.setMessage("Nothing to undo")
+			return m, nil
+		}
+		m.state = cleanupConfirmUndo
+		return m, nil
+	case "r":
+		m.rescan()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.listModel, cmd = m.listModel.Update(msg)
+	return m, cmd
+}
+
+func (m CleanupModel) handleKeepInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.showGroups()
+		return m, nil
+	case "enter":
+		m.preview(m.store.PlanKeepOne(context.Background(), m.group, m.listModel.Index()))
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.listModel, cmd = m.listModel.Update(msg)
+	return m, cmd
+}
+
+// handlePickerInput ticks lists to merge or stations to move.
+func (m CleanupModel) handlePickerInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.showGroups()
+		return m, nil
+	case " ", "x":
+		idx := m.listModel.Index()
+		m.picked[idx] = !m.picked[idx]
+		m.refreshPicker()
+		return m, nil
+	case "enter":
+		if m.state == cleanupMergeSources {
+			m.sources = nil
+			for i, name := range m.lists {
+				if m.picked[i] {
+					m.sources = append(m.sources, name)
+				}
+			}
+			if len(m.sources) == 0 {
+				m.setMessage("Select the lists to merge with Space")
+				return m, nil
+			}
+			return m, m.startTarget(cleanupMergeTarget, m.sources[0])
+		}
+		if len(m.pickedUUIDs()) == 0 {
+			m.setMessage("Select the stations to move with Space")
+			return m, nil
+		}
+		return m, m.startTarget(cleanupMoveTarget, "")
+	}
+
+	var cmd tea.Cmd
+	m.listModel, cmd = m.listModel.Update(msg)
+	return m, cmd
+}
+
+func (m CleanupModel) pickedUUIDs() []string {
+	var uuids []string
+	for i, st := range m.stations {
+		if m.picked[i] {
+			uuids = append(uuids, st.StationUUID)
+		}
+	}
+	return uuids
+}
+
+func (m CleanupModel) handleMoveSourceInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.showGroups()
+		return m, nil
+	case "enter":
+		idx := m.listModel.Index()
+		if idx < 0 || idx >= len(m.lists) {
+			return m, nil
+		}
+		list, err := m.store.LoadList(context.Background(), m.lists[idx])
+		if err != nil {
+			m.setMessage(fmt.Sprintf("✗ %v", err))
+			return m, nil
+		}
+		if len(list.Stations) == 0 {
+			m.setMessage(fmt.Sprintf("%s has no stations", list.Name))
+			return m, nil
+		}
+		m.moveFrom = list.Name
+		m.stations = list.Stations
+		titles := make([]string, len(m.stations))
+		for i, st := range m.stations {
+			titles[i] = st.TrimName()
+		}
+		m.showPicker(cleanupMoveStations, titles)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.listModel, cmd = m.listModel.Update(msg)
+	return m, cmd
+}
+
+func (m CleanupModel) handleTargetInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.targetInput.Blur()
+		if m.state == cleanupMergeTarget {
+			m.state = cleanupMergeSources
+		} else {
+			m.state = cleanupMoveStations
+		}
+		return m, nil
+	case "enter":
+		target := strings.TrimSpace(m.targetInput.Value())
+		if err := storage.ValidateListName(target); err != nil {
+			m.setMessage(fmt.Sprintf("✗ %v", err))
+			return m, nil
+		}
+		m.targetInput.Blur()
+		if m.state == cleanupMergeTarget {
+			m.state = cleanupMergeDelete
+			return m, nil
+		}
+		m.preview(m.store.PlanMoveStations(context.Background(), m.moveFrom, m.pickedUUIDs(), target))
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.targetInput, cmd = m.targetInput.Update(msg)
+	return m, cmd
+}
+
+func (m CleanupModel) handleMergeDeleteInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	target := strings.TrimSpace(m.targetInput.Value())
+	switch msg.String() {
+	case "y", "Y":
+		m.preview(m.store.PlanMergeLists(context.Background(), m.sources, target, true))
+	case "n", "N":
+		m.preview(m.store.PlanMergeLists(context.Background(), m.sources, target, false))
+	case "esc":
+		return m, m.startTarget(cleanupMergeTarget, target)
+	}
+	return m, nil
+}
+
+func (m CleanupModel) handlePreviewInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		if err := m.store.ApplyCleanup(context.Background(), m.plan); err != nil {
+			m.rescan()
+			m.setMessage(fmt.Sprintf("✗ %v", err))
+			return m, nil
+		}
+		summary := m.plan.Summary
+		m.plan = nil
+		m.rescan()
+		m.setMessage("✓ " + summary + " • u: Undo")
+		return m, nil
+	case "n", "N", "esc":
+		m.plan = nil
+		m.showGroups()
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m CleanupModel) handleConfirmUndoInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		summary, err := m.store.UndoCleanup(context.Background())
+		m.rescan()
+		switch {
+		case errors.Is(err, storage.ErrNothingToUndo):
+			m.setMessage("Nothing to undo")
+		case err != nil:
+			m.setMessage(fmt.Sprintf("✗ Undo failed: %v", err))
+		default:
+			m.setMessage("✓ Undone: " + summary)
+		}
+		return m, nil
+	case "n", "N", "esc":
+		m.showGroups()
+		return m, nil
+	}
+	return m, nil
+}
+
+// View renders the library cleanup screen
+func (m CleanupModel) View() string {
+	switch m.state {
+	case cleanupPreview:
+		return m.viewPreview()
+	case cleanupConfirmUndo:
+		return m.renderPageWithBottomHelp(PageLayout{
+			Title:   "Undo Cleanup",
+			Content: fmt.Sprintf("Undo %q?\n\nChanges made to those lists since then are lost.", m.store.LastCleanup()),
+			Help:    "y: Yes, undo • n/Esc: No, cancel",
+		}, m.height)
+	}
+
+	var content strings.Builder
+	if m.message != "" {
+		style := successStyle()
+		if strings.Contains(m.message, "✗") {
+			style = errorStyle()
+		} else if !strings.Contains(m.message, "✓") {
+			style = infoStyle()
+		}
+		content.WriteString(style.Render(m.message))
+		content.WriteString("\n\n")
+	}
+
+	var title, subtitle, help string
+	switch m.state {
+	case cleanupKeep:
+		title = "Keep One"
+		subtitle = groupTitle(m.group)
+		help = "↑↓/jk: Navigate • Enter: Keep this one, remove the others • Esc: Back"
+	case cleanupMergeSources:
+		title = "Merge Lists"
+		subtitle = "Select the lists to merge"
+		help = "↑↓/jk: Navigate • Space: Select • Enter: Continue • Esc: Back"
+	case cleanupMoveSource:
+		title = "Move Stations"
+		subtitle = "Select the list to move stations from"
+		help = "↑↓/jk: Navigate • Enter: Select • Esc: Back"
+	case cleanupMoveStations:
+		title = "Move Stations"
+		subtitle = "Select the stations to move from " + m.moveFrom
+		help = "↑↓/jk: Navigate • Space: Select • Enter: Continue • Esc: Back"
+	case cleanupMergeTarget, cleanupMoveTarget, cleanupMergeDelete:
+		title = "Merge Lists"
+		if m.state == cleanupMoveTarget {
+			title = "Move Stations"
+		}
+		subtitle = "Target list; it is created if it does not exist"
+		content.WriteString("Target: ")
+		content.WriteString(m.targetInput.View())
+		help = "Enter: Continue • Esc: Back"
+		if m.state == cleanupMergeDelete {
+			fmt.Fprintf(&content, "\n\nDelete %s after merging?", strings.Join(m.sources, ", "))
+			help = "y: Yes, delete • n: No, keep them • Esc: Back"
+		}
+		return m.renderPageWithBottomHelp(PageLayout{Title: title, Subtitle: subtitle, Content: content.String(), Help: help}, m.height)
+	default:
+		title = "Library Cleanup"
+		subtitle = fmt.Sprintf("%d duplicate group(s) across %d list(s)", len(m.groups), len(m.lists))
+		help = "↑↓/jk: Navigate • Enter: Resolve • a: Dedupe each list • m: Merge lists • v: Move stations • u: Undo • r: Rescan • Esc: Back"
+		if len(m.groups) == 0 {
+			content.WriteString(infoStyle().Render("No duplicates found."))
+			return m.renderPageWithBottomHelp(PageLayout{Title: title, Subtitle: subtitle, Content: content.String(), Help: help}, m.height)
+		}
+	}
+
+	content.WriteString(m.listModel.View())
+	return m.renderPageWithBottomHelp(PageLayout{
+		Title:    title,
+		Subtitle: subtitle,
+		Content:  content.String(),
+		Help:     help,
+	}, m.height)
+}
+
+// viewPreview lists what each list of the pending plan gains and loses.
+func (m CleanupModel) viewPreview() string {
+	var lines []string
+	for _, d := range m.plan.Preview() {
+		header := d.List
+		switch {
+		case d.Created:
+			header += " (new list)"
+		case d.Deleted:
+			header += " (list deleted)"
+		}
+		lines = append(lines, boldStyle().Render(header))
+		for _, st := range d.Removed {
+			lines = append(lines, errorStyle().Render("  - "+st.TrimName()))
+		}
+		for _, st := range d.Added {
+			lines = append(lines, successStyle().Render("  + "+st.TrimName()))
+		}
+	}
+	if limit := m.height - 12; limit > 0 && len(lines) > limit {
+		more := len(lines) - limit + 1
+		lines = append(lines[:limit-1], dimStyle().Render(fmt.Sprintf("… and %d more line(s)", more)))
+	}
+	return m.renderPageWithBottomHelp(PageLayout{
+		Title:    "Preview Changes",
+		Subtitle: m.plan.Summary,
+		Content:  strings.Join(lines, "\n"),
+		Help:     "y: Apply • n/Esc: Cancel",
+	}, m.height)
+}
+
+// renderPageWithBottomHelp wraps RenderPageWithBottomHelp injecting the active now-playing bar.
+func (m CleanupModel) renderPageWithBottomHelp(layout PageLayout, height int) string {
+	layout.NowPlaying = m.nowPlayingBar
+	return RenderPageWithBottomHelp(layout, height)
+}
+
+// capturesKeys is true while a target list is typed, and in the duplicate
+// groups view, where u undoes the last cleanup.
+func (m CleanupModel) capturesKeys() bool {
+	switch m.state {
+	case cleanupGroups, cleanupMergeTarget, cleanupMoveTarget:
+		return true
+	}
+	return false
+}
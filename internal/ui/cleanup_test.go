@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+func sendCleanupKeys(m CleanupModel, keys ...string) CleanupModel {
+	for _, k := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(CleanupModel)
+	}
+	return m
+}
+
+func cleanupListUUIDs(t *testing.T, store *storage.Storage, name string) []string {
+	t.Helper()
+	list, err := store.LoadList(context.Background(), name)
+	if err != nil {
+		t.Fatalf("LoadList(%q): %v", name, err)
+	}
+	var uuids []string
+	for _, st := range list.Stations {
+		uuids = append(uuids, st.StationUUID)
+	}
+	return uuids
+}
+
+func TestCleanupKeepOnePreviewAndUndo(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewStorage(dir)
+	ctx := context.Background()
+	jazz := api.Station{StationUUID: "a", Name: "Jazz FM", URLResolved: "http://jazz.example"}
+	rock := api.Station{StationUUID: "b", Name: "Rock FM", URLResolved: "http://rock.example"}
+	for name, stations := range map[string][]api.Station{"Jazz": {jazz, rock}, "Mix": {jazz}} {
+		if err := store.SaveList(ctx, &storage.FavoritesList{Name: name, Stations: stations}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := NewCleanupModel(dir)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	m = updated.(CleanupModel)
+	if len(m.groups) != 1 {
+		t.Fatalf("expected one duplicate group, got %d", len(m.groups))
+	}
+
+	// Keep the copy in Mix
+	m = sendCleanupKeys(m, "enter", "down", "enter")
+	if m.state != cleanupPreview {
+		t.Fatalf("expected the preview, got state %v", m.state)
+	}
+	if view := m.View(); !strings.Contains(view, "- Jazz FM") {
+		t.Errorf("preview does not show the removal:\n%s", view)
+	}
+	if got := cleanupListUUIDs(t, store, "Jazz"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("the preview changed Jazz: %v", got)
+	}
+
+	m = sendCleanupKeys(m, "y")
+	if got := cleanupListUUIDs(t, store, "Jazz"); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("after apply Jazz = %v", got)
+	}
+	if len(m.groups) != 0 || !strings.Contains(m.message, "✓") {
+		t.Errorf("expected no groups and a success message, got %d, %q", len(m.groups), m.message)
+	}
+
+	m = sendCleanupKeys(m, "u", "y")
+	if got := cleanupListUUIDs(t, store, "Jazz"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("after undo Jazz = %v", got)
+	}
+	if len(m.groups) != 1 {
+		t.Errorf("expected the group back after undo, got %d", len(m.groups))
+	}
+}
+
+func TestCleanupMergeLists(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewStorage(dir)
+	ctx := context.Background()
+	for name, uuid := range map[string]string{"Jazz": "a", "Rock": "b"} {
+		st := api.Station{StationUUID: uuid, Name: name, URLResolved: "http://" + uuid}
+		if err := store.SaveList(ctx, &storage.FavoritesList{Name: name, Stations: []api.Station{st}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := NewCleanupModel(dir)
+	// Tick both lists, keep the suggested target (Jazz), delete Rock
+	m = sendCleanupKeys(m, "m", " ", "down", " ", "enter", "enter", "y")
+	if m.state != cleanupPreview {
+		t.Fatalf("expected the preview, got state %v (%q)", m.state, m.message)
+	}
+	m = sendCleanupKeys(m, "y")
+	if got := cleanupListUUIDs(t, store, "Jazz"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("after merge Jazz = %v", got)
+	}
+	if lists, _ := store.GetAllLists(ctx); !reflect.DeepEqual(lists, []string{"Jazz"}) {
+		t.Errorf("GetAllLists = %v", lists)
+	}
+}
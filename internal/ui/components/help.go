@@ -178,7 +178,8 @@ func CreateMainMenuHelp() []HelpSection {
 				{"/*", "Adjust volume"},
 				{"m", "Toggle mute"},
 				{"b", "Block station"},
-				{"u", "Undo block"},
+				{"u", "Undo last change"},
+				{"Ctrl+R", "Redo"},
 			},
 		},
 	}
@@ -201,6 +202,9 @@ func CreateFavoritesHelp() []HelpSection {
 				{"Space", "Pause/Resume"},
 				{"/*", "Adjust volume"},
 				{"m", "Toggle mute"},
+				{"←/→", "Rewind/forward 10s"},
+				{"[/]", "Rewind/forward 60s"},
+				{"L", "Jump to live"},
 			},
 		},
 		{
@@ -210,9 +214,13 @@ func CreateFavoritesHelp() []HelpSection {
 				{"r", "Rate station (1-5)"},
 				{"t", "Add tag"},
 				{"T", "Manage tags"},
+				{"l", "Like current song"},
+				{"o", "Playback options"},
+				{"N", "Station notes"},
 				{"v", "Vote"},
 				{"b", "Block station"},
-				{"u", "Undo block"},
+				{"u", "Undo last change"},
+				{"Ctrl+R", "Redo"},
 				{"Z", "Sleep timer"},
 				{"+", "Extend sleep timer"},
 			},
@@ -247,9 +255,11 @@ func CreatePlayingHelp() []HelpSection {
 				{"r", "Rate station (1-5)"},
 				{"t", "Add tag"},
 				{"T", "Manage tags"},
+				{"l", "Like current song"},
 				{"v", "Vote"},
 				{"b", "Block station"},
-				{"u", "Undo block"},
+				{"u", "Undo last change"},
+				{"Ctrl+R", "Redo"},
 				{"Z", "Sleep timer"},
 				{"+", "Extend sleep timer"},
 			},
@@ -282,6 +292,7 @@ func CreateTagsPlayingHelp() []HelpSection {
 				{"r", "Rate station (1-5)"},
 				{"t", "Add tag"},
 				{"T", "Manage tags"},
+				{"l", "Like current song"},
 			},
 		},
 	}
@@ -321,8 +332,10 @@ func CreateLuckyHelp() []HelpSection {
 				{"r", "Rate station (1-5)"},
 				{"t", "Add tag"},
 				{"T", "Manage tags"},
+				{"l", "Like current song"},
 				{"b", "Block station"},
-				{"u", "Undo block (5s)"},
+				{"u", "Undo last change"},
+				{"Ctrl+R", "Redo"},
 			},
 		},
 		{
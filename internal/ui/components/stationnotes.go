@@ -0,0 +1,155 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shinokada/tera/v3/internal/storage"
+	"github.com/shinokada/tera/v3/internal/theme"
+)
+
+// StationNotesSavedMsg is dispatched when the user saves the editor. A note
+// with every field empty removes the station's note.
+type StationNotesSavedMsg struct {
+	Note storage.StationNote
+}
+
+// StationNotesCancelledMsg is dispatched when the user leaves the editor
+// without saving.
+type StationNotesCancelledMsg struct{}
+
+// Editor fields, in display order.
+const (
+	noteFieldName = iota
+	noteFieldURL
+	noteFieldNotes
+	noteFieldCount
+)
+
+var noteFieldLabels = [noteFieldCount]string{
+	"Display name",
+	"Stream URL",
+	"Notes",
+}
+
+var noteFieldHints = [noteFieldCount]string{
+	"Shown instead of the station name; empty uses the original",
+	"Played instead of the station's stream; empty uses the original",
+	"Free text, matched when you filter with /",
+}
+
+var noteFieldLimits = [noteFieldCount]int{100, 512, 2000}
+
+// StationNotesEditor is a form for the user's display name, preferred
+// stream URL and notes for a station.
+type StationNotesEditor struct {
+	inputs   [noteFieldCount]textinput.Model
+	focus    int
+	err      string
+	original string
+}
+
+// NewStationNotesEditor creates an editor pre-filled from note. original is
+// the station name as Radio Browser has it.
+func NewStationNotesEditor(original string, note *storage.StationNote, width int) StationNotesEditor {
+	e := StationNotesEditor{original: original}
+	for i := range e.inputs {
+		ti := textinput.New()
+		ti.CharLimit = noteFieldLimits[i]
+		ti.Width = max(width-20, 20)
+		e.inputs[i] = ti
+	}
+	e.inputs[noteFieldName].Placeholder = original
+	if note != nil {
+		e.inputs[noteFieldName].SetValue(note.DisplayName)
+		e.inputs[noteFieldURL].SetValue(note.PreferredURL)
+		e.inputs[noteFieldNotes].SetValue(note.Notes)
+	}
+	e.inputs[0].Focus()
+	return e
+}
+
+// Init satisfies the BubbleTea model interface.
+func (e StationNotesEditor) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles key messages for the editor.
+func (e StationNotesEditor) Update(msg tea.Msg) (StationNotesEditor, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		e.inputs[e.focus], cmd = e.inputs[e.focus].Update(msg)
+		return e, cmd
+	}
+
+	switch key.String() {
+	case "esc":
+		return e, func() tea.Msg { return StationNotesCancelledMsg{} }
+	case "tab", "down":
+		return e.setFocus((e.focus + 1) % noteFieldCount), nil
+	case "shift+tab", "up":
+		return e.setFocus((e.focus + noteFieldCount - 1) % noteFieldCount), nil
+	case "ctrl+u":
+		e.inputs[e.focus].SetValue("")
+		return e, nil
+	case "enter":
+		note := storage.StationNote{
+			DisplayName:  e.inputs[noteFieldName].Value(),
+			PreferredURL: e.inputs[noteFieldURL].Value(),
+			Notes:        e.inputs[noteFieldNotes].Value(),
+		}
+		if err := storage.ValidateStationNote(note); err != nil {
+			e.err = "✗ " + err.Error()
+			return e, nil
+		}
+		return e, func() tea.Msg { return StationNotesSavedMsg{Note: note} }
+	}
+
+	var cmd tea.Cmd
+	e.inputs[e.focus], cmd = e.inputs[e.focus].Update(msg)
+	e.err = ""
+	return e, cmd
+}
+
+func (e StationNotesEditor) setFocus(i int) StationNotesEditor {
+	e.inputs[e.focus].Blur()
+	e.focus = i
+	e.inputs[e.focus].Focus()
+	return e
+}
+
+// View renders the editor.
+func (e StationNotesEditor) View() string {
+	th := theme.Current()
+	labelStyle := lipgloss.NewStyle().Width(14)
+	focusStyle := lipgloss.NewStyle().Width(14).Bold(true).Foreground(th.HighlightColor())
+	hintStyle := lipgloss.NewStyle().Foreground(th.MutedColor())
+	errStyle := lipgloss.NewStyle().Foreground(th.ErrorColor())
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Notes for %s\n\n", e.original)
+	for i := range e.inputs {
+		style := labelStyle
+		if i == e.focus {
+			style = focusStyle
+		}
+		b.WriteString(style.Render(noteFieldLabels[i]))
+		b.WriteString(e.inputs[i].View())
+		b.WriteString("\n")
+		if i == e.focus {
+			b.WriteString(labelStyle.Render(""))
+			b.WriteString(hintStyle.Render(noteFieldHints[i]))
+			b.WriteString("\n")
+		}
+	}
+	if e.err != "" {
+		b.WriteString("\n")
+		b.WriteString(errStyle.Render(e.err))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
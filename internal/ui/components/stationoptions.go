@@ -0,0 +1,232 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/theme"
+)
+
+// StationOptionsSavedMsg is dispatched when the user saves the editor.
+// Options is nil when every field was cleared.
+type StationOptionsSavedMsg struct {
+	Options *api.PlaybackOptions
+}
+
+// StationOptionsCancelledMsg is dispatched when the user leaves the editor
+// without saving.
+type StationOptionsCancelledMsg struct{}
+
+// Editor fields, in display order.
+const (
+	optFieldUserAgent = iota
+	optFieldReferer
+	optFieldHeaders
+	optFieldCache
+	optFieldStart
+	optFieldMPVArgs
+	optFieldCount
+)
+
+var optFieldLabels = [optFieldCount]string{
+	"User-Agent",
+	"Referer",
+	"Headers",
+	"Cache (MB)",
+	"Start at (s)",
+	"mpv flags",
+}
+
+var optFieldHints = [optFieldCount]string{
+	"Empty uses mpv's default",
+	"Sent as the HTTP Referer header",
+	"Name: value, separate several with |",
+	"10-200, empty uses Settings > Connection",
+	"Skip into on-demand streams; empty for live radio",
+	"Space-separated, e.g. --cache-secs=30 --network-timeout=30",
+}
+
+// StationOptionsEditor is a form for a station's playback overrides. The
+// validate function is called on save so the caller decides which mpv
+// flags are acceptable.
+type StationOptionsEditor struct {
+	inputs   [optFieldCount]textinput.Model
+	focus    int
+	err      string
+	validate func(*api.PlaybackOptions) error
+	title    string
+}
+
+// NewStationOptionsEditor creates an editor pre-filled from opts.
+func NewStationOptionsEditor(stationName string, opts *api.PlaybackOptions, validate func(*api.PlaybackOptions) error, width int) StationOptionsEditor {
+	e := StationOptionsEditor{validate: validate, title: stationName}
+	for i := range e.inputs {
+		ti := textinput.New()
+		ti.CharLimit = 512
+		ti.Width = max(width-20, 20)
+		e.inputs[i] = ti
+	}
+	if opts != nil {
+		e.inputs[optFieldUserAgent].SetValue(opts.UserAgent)
+		e.inputs[optFieldReferer].SetValue(opts.Referer)
+		e.inputs[optFieldHeaders].SetValue(formatHeaders(opts.Headers))
+		if opts.CacheMB > 0 {
+			e.inputs[optFieldCache].SetValue(strconv.Itoa(opts.CacheMB))
+		}
+		if opts.StartOffset > 0 {
+			e.inputs[optFieldStart].SetValue(strconv.Itoa(opts.StartOffset))
+		}
+		e.inputs[optFieldMPVArgs].SetValue(strings.Join(opts.MPVArgs, " "))
+	}
+	e.inputs[0].Focus()
+	return e
+}
+
+// Init satisfies the BubbleTea model interface.
+func (e StationOptionsEditor) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles key messages for the editor.
+func (e StationOptionsEditor) Update(msg tea.Msg) (StationOptionsEditor, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		e.inputs[e.focus], cmd = e.inputs[e.focus].Update(msg)
+		return e, cmd
+	}
+
+	switch key.String() {
+	case "esc":
+		return e, func() tea.Msg { return StationOptionsCancelledMsg{} }
+	case "tab", "down":
+		return e.setFocus((e.focus + 1) % optFieldCount), nil
+	case "shift+tab", "up":
+		return e.setFocus((e.focus + optFieldCount - 1) % optFieldCount), nil
+	case "ctrl+u":
+		e.inputs[e.focus].SetValue("")
+		return e, nil
+	case "enter":
+		opts, err := e.parse()
+		if err == nil && e.validate != nil {
+			err = e.validate(opts)
+		}
+		if err != nil {
+			e.err = "✗ " + err.Error()
+			return e, nil
+		}
+		if opts.IsZero() {
+			opts = nil
+		}
+		return e, func() tea.Msg { return StationOptionsSavedMsg{Options: opts} }
+	}
+
+	var cmd tea.Cmd
+	e.inputs[e.focus], cmd = e.inputs[e.focus].Update(msg)
+	e.err = ""
+	return e, cmd
+}
+
+func (e StationOptionsEditor) setFocus(i int) StationOptionsEditor {
+	e.inputs[e.focus].Blur()
+	e.focus = i
+	e.inputs[e.focus].Focus()
+	return e
+}
+
+// parse converts the form fields into PlaybackOptions.
+func (e StationOptionsEditor) parse() (*api.PlaybackOptions, error) {
+	value := func(i int) string { return strings.TrimSpace(e.inputs[i].Value()) }
+
+	opts := &api.PlaybackOptions{
+		UserAgent: value(optFieldUserAgent),
+		Referer:   value(optFieldReferer),
+		MPVArgs:   strings.Fields(value(optFieldMPVArgs)),
+	}
+
+	headers, err := parseHeaders(value(optFieldHeaders))
+	if err != nil {
+		return nil, err
+	}
+	opts.Headers = headers
+
+	if s := value(optFieldCache); s != "" {
+		if opts.CacheMB, err = strconv.Atoi(s); err != nil {
+			return nil, fmt.Errorf("cache must be a number of megabytes")
+		}
+	}
+	if s := value(optFieldStart); s != "" {
+		if opts.StartOffset, err = strconv.Atoi(s); err != nil {
+			return nil, fmt.Errorf("start must be a number of seconds")
+		}
+	}
+	return opts, nil
+}
+
+// parseHeaders reads "Name: value | Name2: value" into a map.
+func parseHeaders(s string) (map[string]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	headers := make(map[string]string)
+	for _, entry := range strings.Split(s, "|") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("header %q must look like Name: value", entry)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// formatHeaders is the inverse of parseHeaders, sorted for a stable display.
+func formatHeaders(h map[string]string) string {
+	parts := make([]string, 0, len(h))
+	for name, value := range h {
+		parts = append(parts, name+": "+value)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " | ")
+}
+
+// View renders the editor.
+func (e StationOptionsEditor) View() string {
+	th := theme.Current()
+	labelStyle := lipgloss.NewStyle().Width(14)
+	focusStyle := lipgloss.NewStyle().Width(14).Bold(true).Foreground(th.HighlightColor())
+	hintStyle := lipgloss.NewStyle().Foreground(th.MutedColor())
+	errStyle := lipgloss.NewStyle().Foreground(th.ErrorColor())
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Playback options for %s\n\n", e.title)
+	for i := range e.inputs {
+		style := labelStyle
+		if i == e.focus {
+			style = focusStyle
+		}
+		b.WriteString(style.Render(optFieldLabels[i]))
+		b.WriteString(e.inputs[i].View())
+		b.WriteString("\n")
+		if i == e.focus {
+			b.WriteString(labelStyle.Render(""))
+			b.WriteString(hintStyle.Render(optFieldHints[i]))
+			b.WriteString("\n")
+		}
+	}
+	if e.err != "" {
+		b.WriteString("\n")
+		b.WriteString(errStyle.Render(e.err))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
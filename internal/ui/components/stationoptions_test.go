@@ -0,0 +1,84 @@
+package components
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+func TestParseHeaders(t *testing.T) {
+	h, err := parseHeaders("Referer: https://a | X-Key: v:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h["Referer"] != "https://a" || h["X-Key"] != "v:1" {
+		t.Errorf("unexpected headers: %v", h)
+	}
+	if _, err := parseHeaders("no colon here"); err == nil {
+		t.Error("expected an error for a malformed header")
+	}
+	if got := formatHeaders(h); got != "Referer: https://a | X-Key: v:1" {
+		t.Errorf("formatHeaders round trip: %q", got)
+	}
+}
+
+func TestStationOptionsEditor_RoundTrip(t *testing.T) {
+	in := &api.PlaybackOptions{UserAgent: "UA", CacheMB: 64, MPVArgs: []string{"--a", "--b=1"}}
+	e := NewStationOptionsEditor("Test FM", in, nil, 80)
+
+	_, cmd := e.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg, ok := cmd().(StationOptionsSavedMsg)
+	if !ok {
+		t.Fatal("expected StationOptionsSavedMsg")
+	}
+	if msg.Options.UserAgent != "UA" || msg.Options.CacheMB != 64 || len(msg.Options.MPVArgs) != 2 {
+		t.Errorf("options not preserved: %+v", msg.Options)
+	}
+}
+
+func TestStationOptionsEditor_ClearedSavesNil(t *testing.T) {
+	e := NewStationOptionsEditor("Test FM", nil, nil, 80)
+	_, cmd := e.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg := cmd().(StationOptionsSavedMsg); msg.Options != nil {
+		t.Errorf("expected nil options, got %+v", msg.Options)
+	}
+}
+
+func TestStationOptionsEditor_ValidationBlocksSave(t *testing.T) {
+	e := NewStationOptionsEditor("Test FM", &api.PlaybackOptions{UserAgent: "x"}, func(*api.PlaybackOptions) error {
+		return errors.New("nope")
+	}, 80)
+	e, cmd := e.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Error("expected no save on validation failure")
+	}
+	if e.err == "" {
+		t.Error("expected the error to be shown")
+	}
+}
+
+func TestStationNotesEditor_RoundTrip(t *testing.T) {
+	e := NewStationNotesEditor("RADIO XYZ 128k", &storage.StationNote{DisplayName: "XYZ", Notes: "jazz"}, 80)
+	_, cmd := e.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg, ok := cmd().(StationNotesSavedMsg)
+	if !ok {
+		t.Fatal("expected StationNotesSavedMsg")
+	}
+	if msg.Note.DisplayName != "XYZ" || msg.Note.Notes != "jazz" {
+		t.Errorf("note not preserved: %+v", msg.Note)
+	}
+}
+
+func TestStationNotesEditor_InvalidURLBlocksSave(t *testing.T) {
+	e := NewStationNotesEditor("XYZ", &storage.StationNote{PreferredURL: "ftp://xyz.example"}, 80)
+	e, cmd := e.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Error("expected no save with an invalid stream URL")
+	}
+	if e.err == "" {
+		t.Error("expected an error message")
+	}
+}
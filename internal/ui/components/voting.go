@@ -28,6 +28,10 @@ func ExecuteVote(station *api.Station, votedStations *storage.VotedStations, api
 			return VoteFailedMsg{Err: fmt.Errorf("no station selected")}
 		}
 
+		if station.IsCustom() {
+			return VoteFailedMsg{Err: fmt.Errorf("custom stations are not on Radio Browser")}
+		}
+
 		// Guard against nil votedStations
 		if votedStations == nil {
 			return VoteFailedMsg{Err: fmt.Errorf("voting system not initialized")}
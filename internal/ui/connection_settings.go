@@ -3,10 +3,12 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	cfgpkg "github.com/shinokada/tera/v3/internal/config"
 	"github.com/shinokada/tera/v3/internal/storage"
 	"github.com/shinokada/tera/v3/internal/theme"
 	"github.com/shinokada/tera/v3/internal/ui/components"
@@ -39,6 +41,39 @@ var streamBufferOptions = []struct {
 	{200, "200 MB (Extreme)"},
 }
 
+// Shared configuration for time-shift depth options (0 = off)
+var timeShiftOptions = []struct {
+	minutes int
+	label   string
+}{
+	{0, "Off (Default)"},
+	{5, "5 minutes"},
+	{10, "10 minutes"},
+	{30, "30 minutes"},
+	{60, "1 hour"},
+	{120, "2 hours (Maximum)"},
+}
+
+// Shared configuration for network resume timeout options (0 = off)
+var resumeOptions = []struct {
+	minutes int
+	label   string
+}{
+	{0, "Off"},
+	{2, "2 minutes"},
+	{5, "5 minutes"},
+	{10, "10 minutes (Default)"},
+	{30, "30 minutes"},
+	{60, "1 hour"},
+	{120, "2 hours (Maximum)"},
+}
+
+// Values cycled through on the Data Saver screen
+var (
+	dataSaverBitrateOptions = []int{32, 48, 64, 96, 128}
+	dataSaverCapOptions     = []int{0, 1024, 2048, 5120, 10240, 20480, 51200} // MB; 0 = no cap
+)
+
 // connectionSettingsState represents the current state in connection settings
 type connectionSettingsState int
 
@@ -46,6 +81,9 @@ const (
 	connectionSettingsMenu connectionSettingsState = iota
 	connectionSettingsDelay
 	connectionSettingsBuffer
+	connectionSettingsTimeShift
+	connectionSettingsResume
+	connectionSettingsDataSaver
 )
 
 // ConnectionSettingsModel represents the connection settings page
@@ -55,6 +93,12 @@ type ConnectionSettingsModel struct {
 	menuList         list.Model
 	delayList        list.Model
 	bufferList       list.Model
+	timeShiftList    list.Model
+	timeShift        cfgpkg.TimeShiftConfig
+	resumeList       list.Model
+	resume           cfgpkg.ResumeConfig
+	dataSaver        cfgpkg.DataSaverConfig
+	metadataManager  *storage.MetadataManager // set by App; source of the usage figures
 	width            int
 	height           int
 	message          string
@@ -71,16 +115,36 @@ func NewConnectionSettingsModel() ConnectionSettingsModel {
 		config = storage.DefaultConnectionConfig()
 	}
 
+	timeShift, err := storage.LoadTimeShiftConfigFromUnified()
+	if err != nil {
+		timeShift = cfgpkg.DefaultTimeShiftConfig()
+	}
+
+	resume, err := storage.LoadResumeConfigFromUnified()
+	if err != nil {
+		resume = cfgpkg.DefaultResumeConfig()
+	}
+
+	dataSaver, err := storage.LoadDataSaverConfigFromUnified()
+	if err != nil {
+		dataSaver = cfgpkg.DefaultDataSaverConfig()
+	}
+
 	m := ConnectionSettingsModel{
-		state:  connectionSettingsMenu,
-		config: config,
-		width:  80,
-		height: 24,
+		state:     connectionSettingsMenu,
+		config:    config,
+		timeShift: timeShift,
+		resume:    resume,
+		dataSaver: dataSaver,
+		width:     80,
+		height:    24,
 	}
 
 	m.rebuildMenuList()
 	m.buildDelayList()
 	m.buildBufferList()
+	m.buildTimeShiftList()
+	m.buildResumeList()
 
 	return m
 }
@@ -101,6 +165,12 @@ func (m ConnectionSettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateDelay(msg)
 		case connectionSettingsBuffer:
 			return m.updateBuffer(msg)
+		case connectionSettingsTimeShift:
+			return m.updateTimeShift(msg)
+		case connectionSettingsResume:
+			return m.updateResume(msg)
+		case connectionSettingsDataSaver:
+			return m.updateDataSaver(msg)
 		}
 
 	case tea.WindowSizeMsg:
@@ -157,16 +227,31 @@ func (m ConnectionSettingsModel) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			m.state = connectionSettingsDelay
 		case 2: // Set Stream Buffer
 			m.state = connectionSettingsBuffer
-		case 3: // Reset to Defaults
+		case 3: // Set Time-shift Buffer
+			m.state = connectionSettingsTimeShift
+		case 4: // Set Network Resume
+			m.state = connectionSettingsResume
+		case 5: // Data Saver & Usage
+			m.state = connectionSettingsDataSaver
+		case 6: // Reset to Defaults
 			m.config = storage.DefaultConnectionConfig()
 			m.saveConfig()
+			m.timeShift = cfgpkg.DefaultTimeShiftConfig()
+			m.resume = cfgpkg.DefaultResumeConfig()
+			m.dataSaver = cfgpkg.DefaultDataSaverConfig()
+			cmd := tea.Batch(m.saveTimeShift(), m.saveResume(), m.saveDataSaver())
 			m.rebuildMenuList()
 			m.buildDelayList()
 			m.buildBufferList()
-			m.message = "✓ Reset to default settings"
-			m.messageIsSuccess = true
-			m.messageTime = 3 // 3 seconds (decremented once per second via tickMsg)
-		case 4: // Back to Settings
+			m.buildTimeShiftList()
+			m.buildResumeList()
+			if m.messageIsSuccess || m.message == "" {
+				m.message = "✓ Reset to default settings"
+				m.messageIsSuccess = true
+				m.messageTime = 3 // 3 seconds (decremented once per second via tickMsg)
+			}
+			return m, cmd
+		case 7: // Back to Settings
 			return m, func() tea.Msg {
 				return navigateMsg{screen: screenSettings}
 			}
@@ -174,7 +259,7 @@ func (m ConnectionSettingsModel) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	}
 
 	// Handle number shortcuts
-	if key >= "1" && key <= "5" {
+	if key >= "1" && key <= "8" {
 		num := int(key[0] - '0')
 		m.menuList.Select(num - 1)
 		newModel, cmd := m.updateMenu(tea.KeyMsg{Type: tea.KeyEnter})
@@ -292,6 +377,215 @@ func (m ConnectionSettingsModel) updateBuffer(msg tea.KeyMsg) (tea.Model, tea.Cm
 	return m, nil
 }
 
+// updateTimeShift handles time-shift depth selection
+func (m ConnectionSettingsModel) updateTimeShift(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	// Handle escape/back
+	if key == "esc" {
+		m.state = connectionSettingsMenu
+		return m, nil
+	}
+
+	if key == "0" {
+		return m, func() tea.Msg {
+			return navigateMsg{screen: screenMainMenu}
+		}
+	}
+
+	// Handle ctrl+c
+	if key == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	// Handle selection
+	newList, selected := components.HandleMenuKey(msg, m.timeShiftList)
+	m.timeShiftList = newList
+
+	var cmd tea.Cmd
+	if selected >= 0 {
+		if selected < len(timeShiftOptions) {
+			opt := timeShiftOptions[selected]
+			m.timeShift.Enabled = opt.minutes > 0
+			if opt.minutes > 0 {
+				m.timeShift.DepthMinutes = opt.minutes
+			}
+			m.state = connectionSettingsMenu
+			m.message = fmt.Sprintf("✓ Time-shift set to %s (applies to the next station you play)", strings.ToLower(opt.label))
+			m.messageIsSuccess = true
+			m.messageTime = 3 // 3 seconds (decremented once per second via tickMsg)
+			cmd = m.saveTimeShift()
+			m.rebuildMenuList()
+			m.buildTimeShiftList()
+		} else if selected == len(timeShiftOptions) {
+			// Back option
+			m.state = connectionSettingsMenu
+		}
+	}
+
+	// Handle number shortcuts
+	if key >= "1" && key <= "7" {
+		num := int(key[0] - '0')
+		m.timeShiftList.Select(num - 1)
+		newModel, cmd := m.updateTimeShift(tea.KeyMsg{Type: tea.KeyEnter})
+		return newModel, cmd
+	}
+
+	return m, cmd
+}
+
+// saveTimeShift persists the time-shift settings and tells the App to apply
+// them to players started from now on.
+func (m *ConnectionSettingsModel) saveTimeShift() tea.Cmd {
+	if err := storage.SaveTimeShiftConfigToUnified(m.timeShift); err != nil {
+		m.message = fmt.Sprintf("✗ Failed to save: %v", err)
+		m.messageIsSuccess = false
+		m.messageTime = 3 // 3 seconds (decremented once per second via tickMsg)
+		return nil
+	}
+	cfg := m.timeShift
+	return func() tea.Msg { return timeShiftConfigMsg{cfg: cfg} }
+}
+
+// updateResume handles network resume timeout selection
+func (m ConnectionSettingsModel) updateResume(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	// Handle escape/back
+	if key == "esc" {
+		m.state = connectionSettingsMenu
+		return m, nil
+	}
+
+	if key == "0" {
+		return m, func() tea.Msg {
+			return navigateMsg{screen: screenMainMenu}
+		}
+	}
+
+	// Handle ctrl+c
+	if key == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	// Handle selection
+	newList, selected := components.HandleMenuKey(msg, m.resumeList)
+	m.resumeList = newList
+
+	var cmd tea.Cmd
+	if selected >= 0 {
+		if selected < len(resumeOptions) {
+			opt := resumeOptions[selected]
+			m.resume.Enabled = opt.minutes > 0
+			if opt.minutes > 0 {
+				m.resume.TimeoutMinutes = opt.minutes
+				m.message = fmt.Sprintf("✓ Waiting up to %s for the network (applies to the next station you play)", strings.TrimSuffix(opt.label, " (Default)"))
+			} else {
+				m.message = "✓ Network resume turned off"
+			}
+			m.state = connectionSettingsMenu
+			m.messageIsSuccess = true
+			m.messageTime = 3 // 3 seconds (decremented once per second via tickMsg)
+			cmd = m.saveResume()
+			m.rebuildMenuList()
+			m.buildResumeList()
+		} else if selected == len(resumeOptions) {
+			// Back option
+			m.state = connectionSettingsMenu
+		}
+	}
+
+	// Handle number shortcuts
+	if key >= "1" && key <= "8" {
+		num := int(key[0] - '0')
+		m.resumeList.Select(num - 1)
+		newModel, cmd := m.updateResume(tea.KeyMsg{Type: tea.KeyEnter})
+		return newModel, cmd
+	}
+
+	return m, cmd
+}
+
+// saveResume persists the network resume settings and tells the App to
+// apply them to players started from now on.
+func (m *ConnectionSettingsModel) saveResume() tea.Cmd {
+	if err := storage.SaveResumeConfigToUnified(m.resume); err != nil {
+		m.message = fmt.Sprintf("✗ Failed to save: %v", err)
+		m.messageIsSuccess = false
+		m.messageTime = 3 // 3 seconds (decremented once per second via tickMsg)
+		return nil
+	}
+	cfg := m.resume
+	return func() tea.Msg { return resumeConfigMsg{cfg: cfg} }
+}
+
+// updateDataSaver handles the data saver and usage screen, where single
+// keys change one setting each.
+func (m ConnectionSettingsModel) updateDataSaver(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = connectionSettingsMenu
+		return m, nil
+	case "0":
+		return m, func() tea.Msg {
+			return navigateMsg{screen: screenMainMenu}
+		}
+	case "ctrl+c":
+		return m, tea.Quit
+	case "t":
+		m.dataSaver.Enabled = !m.dataSaver.Enabled
+		m.message = fmt.Sprintf("✓ Data saver %s", boolToOnOff(m.dataSaver.Enabled))
+	case "b":
+		m.dataSaver.MaxBitrate = nextOption(dataSaverBitrateOptions, m.dataSaver.MaxBitrate)
+		m.message = fmt.Sprintf("✓ Preferring streams up to %d kbps", m.dataSaver.MaxBitrate)
+	case "c":
+		m.dataSaver.MonthlyCapMB = nextOption(dataSaverCapOptions, m.dataSaver.MonthlyCapMB)
+		m.message = "✓ Monthly cap: " + formatCapMB(m.dataSaver.MonthlyCapMB)
+	case "s":
+		m.dataSaver.StopAtCap = !m.dataSaver.StopAtCap
+		m.message = fmt.Sprintf("✓ Stop at cap %s", boolToOnOff(m.dataSaver.StopAtCap))
+	default:
+		return m, nil
+	}
+	m.messageIsSuccess = true
+	m.messageTime = 3 // 3 seconds (decremented once per second via tickMsg)
+	cmd := m.saveDataSaver()
+	m.rebuildMenuList()
+	return m, cmd
+}
+
+// nextOption returns the option after current, wrapping around. A value
+// that is not an option moves to the first one.
+func nextOption(options []int, current int) int {
+	for i, v := range options {
+		if v == current {
+			return options[(i+1)%len(options)]
+		}
+	}
+	return options[0]
+}
+
+// formatCapMB renders a monthly cap in MB.
+func formatCapMB(mb int) string {
+	if mb <= 0 {
+		return "none"
+	}
+	return storage.FormatBytes(int64(mb) << 20)
+}
+
+// saveDataSaver persists the data saver settings and tells the App to apply
+// them.
+func (m *ConnectionSettingsModel) saveDataSaver() tea.Cmd {
+	if err := storage.SaveDataSaverConfigToUnified(m.dataSaver); err != nil {
+		m.message = fmt.Sprintf("✗ Failed to save: %v", err)
+		m.messageIsSuccess = false
+		m.messageTime = 3 // 3 seconds (decremented once per second via tickMsg)
+		return nil
+	}
+	cfg := m.dataSaver
+	return func() tea.Msg { return dataSaverConfigMsg{cfg: cfg} }
+}
+
 // saveConfig saves the current configuration
 func (m *ConnectionSettingsModel) saveConfig() {
 	if err := storage.SaveConnectionConfig(m.config); err != nil {
@@ -308,6 +602,18 @@ func (m *ConnectionSettingsModel) rebuildMenuList() {
 		bufferLabel = "Set Stream Buffer (Disabled)"
 	}
 
+	timeShiftLabel := "Set Time-shift Buffer (Off)"
+	if m.timeShift.Enabled {
+		timeShiftLabel = fmt.Sprintf("Set Time-shift Buffer (%d min)", m.timeShift.DepthMinutes)
+	}
+
+	resumeLabel := "Set Network Resume (Off)"
+	if m.resume.Enabled {
+		resumeLabel = fmt.Sprintf("Set Network Resume (%d min)", m.resume.TimeoutMinutes)
+	}
+
+	dataSaverLabel := fmt.Sprintf("Data Saver & Usage (%s)", boolToOnOff(m.dataSaver.Enabled))
+
 	menuItems := []components.MenuItem{
 		components.NewMenuItem(
 			fmt.Sprintf("Toggle Auto-reconnect (%s)", boolToOnOff(m.config.AutoReconnect)),
@@ -324,15 +630,30 @@ func (m *ConnectionSettingsModel) rebuildMenuList() {
 			"Buffer size to handle brief signal drops",
 			"3",
 		),
+		components.NewMenuItem(
+			timeShiftLabel,
+			"Pause and rewind live radio",
+			"4",
+		),
+		components.NewMenuItem(
+			resumeLabel,
+			"Wait for the network and resume after a drop or sleep",
+			"5",
+		),
+		components.NewMenuItem(
+			dataSaverLabel,
+			"Monthly data use, cap and low-bitrate streams",
+			"6",
+		),
 		components.NewMenuItem(
 			"Reset to Defaults",
 			"Restore default connection settings",
-			"4",
+			"7",
 		),
 		components.NewMenuItem(
 			"Back to Settings",
 			"",
-			"5",
+			"8",
 		),
 	}
 
@@ -371,6 +692,38 @@ func (m *ConnectionSettingsModel) buildBufferList() {
 	m.bufferList = components.CreateMenu(menuItems, "", 50, len(menuItems)+2)
 }
 
+// buildTimeShiftList builds the time-shift depth selection list
+func (m *ConnectionSettingsModel) buildTimeShiftList() {
+	menuItems := []components.MenuItem{}
+	for i, opt := range timeShiftOptions {
+		shortcut := fmt.Sprintf("%d", i+1)
+		desc := ""
+		if (opt.minutes == 0 && !m.timeShift.Enabled) || (m.timeShift.Enabled && opt.minutes == m.timeShift.DepthMinutes) {
+			desc = "← Current"
+		}
+		menuItems = append(menuItems, components.NewMenuItem(opt.label, desc, shortcut))
+	}
+	menuItems = append(menuItems, components.NewMenuItem("Back", "", fmt.Sprintf("%d", len(timeShiftOptions)+1)))
+
+	m.timeShiftList = components.CreateMenu(menuItems, "", 50, len(menuItems)+2)
+}
+
+// buildResumeList builds the network resume timeout selection list
+func (m *ConnectionSettingsModel) buildResumeList() {
+	menuItems := []components.MenuItem{}
+	for i, opt := range resumeOptions {
+		shortcut := fmt.Sprintf("%d", i+1)
+		desc := ""
+		if (opt.minutes == 0 && !m.resume.Enabled) || (m.resume.Enabled && opt.minutes == m.resume.TimeoutMinutes) {
+			desc = "← Current"
+		}
+		menuItems = append(menuItems, components.NewMenuItem(opt.label, desc, shortcut))
+	}
+	menuItems = append(menuItems, components.NewMenuItem("Back", "", fmt.Sprintf("%d", len(resumeOptions)+1)))
+
+	m.resumeList = components.CreateMenu(menuItems, "", 50, len(menuItems)+2)
+}
+
 // View renders the connection settings screen
 func (m ConnectionSettingsModel) View() string {
 	switch m.state {
@@ -380,6 +733,12 @@ func (m ConnectionSettingsModel) View() string {
 		return m.viewDelay()
 	case connectionSettingsBuffer:
 		return m.viewBuffer()
+	case connectionSettingsTimeShift:
+		return m.viewTimeShift()
+	case connectionSettingsResume:
+		return m.viewResume()
+	case connectionSettingsDataSaver:
+		return m.viewDataSaver()
 	}
 	return "Unknown state"
 }
@@ -408,6 +767,17 @@ func (m ConnectionSettingsModel) viewMenu() string {
 	} else {
 		fmt.Fprintf(&content, "  Stream buffer:          %d MB\n", m.config.StreamBufferMB)
 	}
+	if m.timeShift.Enabled {
+		fmt.Fprintf(&content, "  Time-shift:             %d minutes\n", m.timeShift.DepthMinutes)
+	} else {
+		content.WriteString("  Time-shift:             Disabled\n")
+	}
+	if m.resume.Enabled {
+		fmt.Fprintf(&content, "  Network resume:         up to %d minutes\n", m.resume.TimeoutMinutes)
+	} else {
+		content.WriteString("  Network resume:         Disabled\n")
+	}
+	fmt.Fprintf(&content, "  Data saver:             %s\n", boolToEnabledDisabled(m.dataSaver.Enabled))
 	content.WriteString("\n")
 
 	// Menu
@@ -428,7 +798,7 @@ func (m ConnectionSettingsModel) viewMenu() string {
 
 	return m.renderPageWithBottomHelp(PageLayout{
 		Content: content.String(),
-		Help:    "↑↓/jk: Navigate • Enter: Select • 1-5: Shortcut • Esc: Back • 0: Main Menu",
+		Help:    "↑↓/jk: Navigate • Enter: Select • 1-8: Shortcut • Esc: Back • 0: Main Menu",
 	}, m.height)
 }
 
@@ -498,6 +868,138 @@ func (m ConnectionSettingsModel) viewBuffer() string {
 	}, m.height)
 }
 
+// viewTimeShift renders the time-shift depth selection screen
+func (m ConnectionSettingsModel) viewTimeShift() string {
+	var content strings.Builder
+
+	t := theme.Current()
+	titleStyle := lipgloss.NewStyle().
+		Foreground(t.HighlightColor()).
+		Bold(true).
+		PaddingLeft(t.Padding.ListItemLeft)
+
+	// Title
+	content.WriteString(titleStyle.Render("⚙️  Settings > Connection Settings > Time-shift"))
+	content.WriteString("\n\n")
+
+	content.WriteString(subtitleStyle().Render("How far back can you rewind live radio?"))
+	content.WriteString("\n\n")
+
+	// Time-shift list
+	content.WriteString(m.timeShiftList.View())
+
+	content.WriteString("\n\n")
+	content.WriteString(infoStyle().Render("Uses about 1 MB per minute at 128 kbps. In Play from Favorites: ←/→ ±10s, [/] ±60s, L: back to live"))
+
+	return m.renderPageWithBottomHelp(PageLayout{
+		Content: content.String(),
+		Help:    "↑↓/jk: Navigate • Enter: Select • 1-7: Shortcut • Esc: Back • 0: Main Menu",
+	}, m.height)
+}
+
+// viewResume renders the network resume timeout selection screen
+func (m ConnectionSettingsModel) viewResume() string {
+	var content strings.Builder
+
+	t := theme.Current()
+	titleStyle := lipgloss.NewStyle().
+		Foreground(t.HighlightColor()).
+		Bold(true).
+		PaddingLeft(t.Padding.ListItemLeft)
+
+	// Title
+	content.WriteString(titleStyle.Render("⚙️  Settings > Connection Settings > Network Resume"))
+	content.WriteString("\n\n")
+
+	content.WriteString(subtitleStyle().Render("How long should TERA wait for the network to come back?"))
+	content.WriteString("\n\n")
+
+	// Resume list
+	content.WriteString(m.resumeList.View())
+
+	content.WriteString("\n\n")
+	content.WriteString(infoStyle().Render("When Wi-Fi drops or the computer wakes from sleep, the same station resumes once its server can be reached"))
+
+	return m.renderPageWithBottomHelp(PageLayout{
+		Content: content.String(),
+		Help:    "↑↓/jk: Navigate • Enter: Select • 1-8: Shortcut • Esc: Back • 0: Main Menu",
+	}, m.height)
+}
+
+// viewDataSaver renders the data saver settings and monthly usage
+func (m ConnectionSettingsModel) viewDataSaver() string {
+	var content strings.Builder
+
+	t := theme.Current()
+	titleStyle := lipgloss.NewStyle().
+		Foreground(t.HighlightColor()).
+		Bold(true).
+		PaddingLeft(t.Padding.ListItemLeft)
+
+	// Title
+	content.WriteString(titleStyle.Render("⚙️  Settings > Connection Settings > Data Saver & Usage"))
+	content.WriteString("\n\n")
+
+	content.WriteString(subtitleStyle().Render("Data Saver:"))
+	content.WriteString("\n\n")
+	fmt.Fprintf(&content, "  Data saver (t):         %s\n", boolToEnabledDisabled(m.dataSaver.Enabled))
+	fmt.Fprintf(&content, "  Preferred bitrate (b):  up to %d kbps\n", m.dataSaver.MaxBitrate)
+	fmt.Fprintf(&content, "  Monthly cap (c):        %s\n", formatCapMB(m.dataSaver.MonthlyCapMB))
+	fmt.Fprintf(&content, "  Stop at cap (s):        %s\n", boolToOnOff(m.dataSaver.StopAtCap))
+	content.WriteString("\n")
+
+	content.WriteString(subtitleStyle().Render("Data Usage:"))
+	content.WriteString("\n\n")
+	if m.metadataManager == nil {
+		content.WriteString(dimStyle().Render("  Usage statistics are not available"))
+		content.WriteString("\n")
+	} else {
+		month := storage.UsageMonth(time.Now())
+		fmt.Fprintf(&content, "  This session:           %s\n", storage.FormatBytes(m.metadataManager.SessionBytes()))
+		used := m.metadataManager.MonthBytes(month)
+		if limit := m.dataSaver.CapBytes(); limit > 0 {
+			fmt.Fprintf(&content, "  This month:             %s of %s (%d%%)\n",
+				storage.FormatBytes(used), storage.FormatBytes(limit), used*100/limit)
+		} else {
+			fmt.Fprintf(&content, "  This month:             %s\n", storage.FormatBytes(used))
+		}
+
+		if months := m.metadataManager.GetMonthlyUsage(); len(months) > 0 {
+			content.WriteString("\n  By month:\n")
+			for i, mu := range months {
+				if i == 6 {
+					break
+				}
+				fmt.Fprintf(&content, "    %s  %10s\n", mu.Month, storage.FormatBytes(mu.Bytes))
+			}
+		}
+		if stations := m.metadataManager.GetStationUsage(month, 5); len(stations) > 0 {
+			content.WriteString("\n  Top stations this month:\n")
+			for _, su := range stations {
+				fmt.Fprintf(&content, "    %10s  %s\n", storage.FormatBytes(su.Bytes), su.Station.TrimName())
+			}
+		}
+	}
+
+	if m.message != "" {
+		content.WriteString("\n")
+		if m.messageIsSuccess {
+			content.WriteString(successStyle().Render(m.message))
+		} else {
+			content.WriteString(errorStyle().Render(m.message))
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(infoStyle().Render("Data saver plays a lower-bitrate stream of the same station when Radio Browser lists one, and applies the monthly cap"))
+
+	return m.renderPageWithBottomHelp(PageLayout{
+		Content: content.String(),
+		Help:    "t: Data saver • b: Bitrate • c: Cap • s: Stop at cap • Esc: Back • 0: Main Menu",
+	}, m.height)
+}
+
 // renderPageWithBottomHelp wraps RenderPageWithBottomHelp injecting the active now-playing bar.
 func (m ConnectionSettingsModel) renderPageWithBottomHelp(layout PageLayout, height int) string {
 	layout.NowPlaying = m.nowPlayingBar
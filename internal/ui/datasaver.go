@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/player"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// variantLookupTimeout bounds the Radio Browser lookup for a low-bitrate
+// variant; when it runs out the station plays as chosen.
+const variantLookupTimeout = 3 * time.Second
+
+// dataSaverConfigMsg is sent by Connection Settings when the data saver
+// settings change.
+type dataSaverConfigMsg struct {
+	cfg config.DataSaverConfig
+}
+
+// dataCapMsg is sent when a player passes the data cap warning level or
+// stops at the cap.
+type dataCapMsg struct {
+	player  *player.MPVPlayer
+	stopped bool
+}
+
+// dataCapFromConfig converts the data saver settings into a player cap. It
+// returns nil when there is no cap.
+func dataCapFromConfig(cfg config.DataSaverConfig) *player.DataCap {
+	limit := cfg.CapBytes()
+	if limit <= 0 {
+		return nil
+	}
+	return &player.DataCap{
+		Limit:  limit,
+		WarnAt: limit * int64(cfg.WarnPercent) / 100,
+		Stop:   cfg.StopAtCap,
+	}
+}
+
+// applyDataSaver installs the data cap and, when data saver is on, a picker
+// that swaps stations for their low-bitrate variants.
+func applyDataSaver(cfg config.DataSaverConfig, client *api.Client) {
+	player.SetDataCap(dataCapFromConfig(cfg))
+	if !cfg.Enabled || client == nil {
+		player.SetVariantPicker(nil)
+		return
+	}
+	player.SetVariantPicker(newVariantPicker(client, cfg.MaxBitrate))
+}
+
+// newVariantPicker returns a picker that looks up stations with the same
+// name on Radio Browser and plays the best one at or under maxKbps. Results
+// are remembered for the rest of the session.
+func newVariantPicker(client *api.Client, maxKbps int) player.VariantPicker {
+	var cache sync.Map // station UUID -> *api.Station (nil when there is none)
+	return func(station *api.Station) *api.Station {
+		if station.Bitrate <= maxKbps || strings.TrimSpace(station.Name) == "" {
+			return nil
+		}
+		if v, ok := cache.Load(station.StationUUID); ok {
+			return withStationSettings(v.(*api.Station), station)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), variantLookupTimeout)
+		defer cancel()
+		candidates, err := client.SearchAdvanced(ctx, api.SearchParams{
+			Name:       station.Name,
+			NameExact:  true,
+			HideBroken: true,
+			Limit:      50,
+		})
+		if err != nil {
+			// Try again next time; the network may be slow right now
+			return nil
+		}
+		variant := lowBitrateVariant(station, candidates, maxKbps)
+		cache.Store(station.StationUUID, variant)
+		return withStationSettings(variant, station)
+	}
+}
+
+// lowBitrateVariant picks the highest-bitrate candidate at or under maxKbps
+// that is the same station as station: same name and, when both are known,
+// the same country. It returns nil when there is none.
+func lowBitrateVariant(station *api.Station, candidates []api.Station, maxKbps int) *api.Station {
+	name := strings.ToLower(station.TrimName())
+	var best *api.Station
+	for i := range candidates {
+		c := &candidates[i]
+		if c.StationUUID == station.StationUUID || c.URLResolved == "" {
+			continue
+		}
+		if c.Bitrate <= 0 || c.Bitrate > maxKbps {
+			continue
+		}
+		if strings.ToLower(c.TrimName()) != name {
+			continue
+		}
+		if station.CountryCode != "" && c.CountryCode != "" && !strings.EqualFold(station.CountryCode, c.CountryCode) {
+			continue
+		}
+		if best == nil || c.Bitrate > best.Bitrate {
+			best = c
+		}
+	}
+	return best
+}
+
+// withStationSettings copies the user's volume for station onto a copy of
+// variant, or returns nil when there is no variant.
+func withStationSettings(variant, station *api.Station) *api.Station {
+	if variant == nil {
+		return nil
+	}
+	v := *variant
+	v.Volume = station.Volume
+	return &v
+}
+
+// dataUsageText describes how close p's month is to the data cap, or
+// returns "" when there is no cap or the warning level is not reached.
+func dataUsageText(p *player.MPVPlayer) string {
+	if p == nil {
+		return ""
+	}
+	usage, ok := p.DataUsage()
+	if !ok || !usage.Warning {
+		return ""
+	}
+	amount := fmt.Sprintf("%s of %s", storage.FormatBytes(usage.Used), storage.FormatBytes(usage.Limit))
+	switch {
+	case usage.Reached && usage.Stops:
+		return "⛔ Monthly data cap reached (" + amount + "); playback is stopped"
+	case usage.Reached:
+		return "⚠ Monthly data cap reached (" + amount + ")"
+	default:
+		return "⚠ " + amount + " monthly data used"
+	}
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+func TestLowBitrateVariant(t *testing.T) {
+	station := &api.Station{StationUUID: "hq", Name: "Jazz FM ", CountryCode: "GB", Bitrate: 320}
+	candidates := []api.Station{
+		{StationUUID: "hq", Name: "Jazz FM", CountryCode: "GB", Bitrate: 48, URLResolved: "http://a"}, // itself
+		{StationUUID: "us", Name: "Jazz FM", CountryCode: "US", Bitrate: 64, URLResolved: "http://b"}, // other country
+		{StationUUID: "lq", Name: "jazz fm", CountryCode: "GB", Bitrate: 32, URLResolved: "http://c"},
+		{StationUUID: "mq", Name: "Jazz FM", CountryCode: "", Bitrate: 64, URLResolved: "http://d"},
+		{StationUUID: "big", Name: "Jazz FM", CountryCode: "GB", Bitrate: 128, URLResolved: "http://e"}, // over the limit
+		{StationUUID: "nourl", Name: "Jazz FM", CountryCode: "GB", Bitrate: 48},
+	}
+
+	got := lowBitrateVariant(station, candidates, 64)
+	if got == nil || got.StationUUID != "mq" {
+		t.Fatalf("expected the 64 kbps variant, got %+v", got)
+	}
+	if got := lowBitrateVariant(station, candidates, 16); got != nil {
+		t.Errorf("expected no variant under 16 kbps, got %+v", got)
+	}
+
+	volume := 40
+	station.Volume = &volume
+	if v := withStationSettings(got, station); v.Volume != &volume || got.Volume != nil {
+		t.Errorf("expected the station volume on a copy, got %v (original %v)", v.Volume, got.Volume)
+	}
+}
+
+func TestDataCapFromConfig(t *testing.T) {
+	cfg := config.DefaultDataSaverConfig()
+	if dataCapFromConfig(cfg) != nil {
+		t.Error("expected no cap by default")
+	}
+
+	cfg.MonthlyCapMB = 1000
+	if dataCapFromConfig(cfg) != nil {
+		t.Error("the cap should only apply with data saver on")
+	}
+
+	cfg.Enabled = true
+	cfg.WarnPercent = 90
+	cfg.StopAtCap = true
+	c := dataCapFromConfig(cfg)
+	if c == nil || c.Limit != 1000<<20 || c.WarnAt != 900<<20 || !c.Stop {
+		t.Errorf("unexpected cap: %+v", c)
+	}
+}
+
+func TestConnectionSettings_DataSaver(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+
+	m := NewConnectionSettingsModel()
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("6")})
+	m = updated.(ConnectionSettingsModel)
+	if m.state != connectionSettingsDataSaver {
+		t.Fatalf("expected the data saver screen, got state %v", m.state)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m = updated.(ConnectionSettingsModel)
+	if !m.dataSaver.Enabled {
+		t.Error("expected data saver to be on")
+	}
+	if cmd == nil {
+		t.Fatal("expected the App to be told about the change")
+	}
+	if msg, ok := cmd().(dataSaverConfigMsg); !ok || !msg.cfg.Enabled {
+		t.Errorf("expected dataSaverConfigMsg with data saver on, got %#v", msg)
+	}
+
+	// No cap -> 1 GB; 64 kbps -> 96 kbps
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = updated.(ConnectionSettingsModel)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m = updated.(ConnectionSettingsModel)
+
+	saved, err := storage.LoadDataSaverConfigFromUnified()
+	if err != nil || !saved.Enabled || saved.MonthlyCapMB != 1024 || saved.MaxBitrate != 96 {
+		t.Errorf("unexpected saved settings %+v (%v)", saved, err)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(ConnectionSettingsModel)
+	if m.state != connectionSettingsMenu {
+		t.Errorf("expected Esc to return to the menu, got state %v", m.state)
+	}
+}
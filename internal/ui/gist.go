@@ -47,6 +47,8 @@ const (
 	gistStateRestoreGistChecklist // category checklist for Gist pull
 	gistStateOverwriteWarn        // warn before clobbering existing files
 	gistStateSyncProgress         // transient: show result then return to menu
+	gistStatePassphrase           // passphrase prompt for encrypting or opening a backup
+	gistStateAutoBackups          // pick an automatic backup to restore
 )
 
 // overwriteSource distinguishes which restore flow triggered the overwrite warning.
@@ -57,12 +59,30 @@ const (
 	overwriteSourceGist
 )
 
+// passphraseAction is what the passphrase prompt was opened for.
+type passphraseAction int
+
+const (
+	passphraseExportZip passphraseAction = iota
+	passphraseSyncGist
+	passphraseRestoreZip
+	passphraseRestoreGist
+)
+
+// encrypting reports whether the passphrase encrypts a new backup, where it
+// may be left empty, rather than opening an existing one.
+func (a passphraseAction) encrypting() bool {
+	return a == passphraseExportZip || a == passphraseSyncGist
+}
+
 // ── Internal message types ─────────────────────────────────────────────────────
 
 // Returned by async Cmds to signal multi-step flow transitions.
 type zipInspectedMsg struct {
-	path      string
-	available storage.SyncPrefs
+	path       string
+	available  storage.SyncPrefs
+	encrypted  bool
+	passphrase string // remembered passphrase, if it opens the zip
 }
 type zipConflictCheckMsg struct {
 	zipPath   string
@@ -70,9 +90,16 @@ type zipConflictCheckMsg struct {
 	conflicts []string
 }
 type gistRestoreAvailableMsg struct {
-	requestID uint64
-	g         *gist.Gist
-	available storage.SyncPrefs
+	requestID  uint64
+	g          *gist.Gist
+	available  storage.SyncPrefs
+	encrypted  bool
+	passphrase string // remembered passphrase, if it opens the Gist
+}
+type passphraseAcceptedMsg struct{ warning string }
+type backupGenerationsMsg struct {
+	dir   string
+	items []list.Item
 }
 type gistConflictCheckMsg struct {
 	g         *gist.Gist
@@ -95,6 +122,27 @@ func (i gistItem) Title() string       { return i.meta.Description }
 func (i gistItem) Description() string { return i.meta.CreatedAt.Format("2006-01-02 15:04") }
 func (i gistItem) FilterValue() string { return i.meta.Description }
 
+// backupGenerationItem is an automatic backup in the restore picker.
+type backupGenerationItem struct {
+	gen        storage.BackupGeneration
+	categories storage.SyncPrefs
+	encrypted  bool
+	err        error // the zip could not be read
+}
+
+func (i backupGenerationItem) Title() string { return i.gen.Time.Format("2006-01-02 15:04:05") }
+func (i backupGenerationItem) Description() string {
+	if i.err != nil {
+		return "unreadable: " + i.err.Error()
+	}
+	desc := fmt.Sprintf("%s • %s", formatBackupSize(i.gen.Size), categorySummary(i.categories))
+	if i.encrypted {
+		desc += " • encrypted"
+	}
+	return desc
+}
+func (i backupGenerationItem) FilterValue() string { return i.Title() }
+
 // ── GistModel ─────────────────────────────────────────────────────────────────
 
 type GistModel struct {
@@ -107,6 +155,8 @@ type GistModel struct {
 	tokenMenuList  list.Model
 	visibilityMenu list.Model
 	gistList       list.Model
+	backupList     list.Model // automatic backups
+	backupDir      string     // directory of the automatic backups
 	// gist data
 	gists           []*gist.GistMetadata
 	selectedGist    *gist.GistMetadata
@@ -126,6 +176,11 @@ type GistModel struct {
 	pendingGist      *gist.Gist // gist fetched during restore-from-URL flow
 	gistFetchPending bool       // true while a URL fetch is in flight
 	gistFetchSeq     uint64     // incremented on each new fetch; matched against msg.requestID to discard stale results
+	// backup encryption
+	passphraseFor      passphraseAction
+	passphrase         string            // passphrase of the backup being exported or restored
+	rememberPassphrase bool              // save the passphrase in the OS keychain once it works
+	pendingAvailable   storage.SyncPrefs // categories of the encrypted backup awaiting its passphrase
 	// ui
 	message        string
 	messageIsError bool
@@ -152,6 +207,7 @@ func NewGistModel(favoritePath string) GistModel {
 		components.NewMenuItem("Restore from backup (zip)", "Restore data from a local zip file", "8"),
 		components.NewMenuItem("Sync all data to Gist", "Push selected data to a backup Gist", "9"),
 		components.NewMenuItem("Restore all data from Gist", "Pull selected data from a backup Gist", "a"),
+		components.NewMenuItem("Restore automatic backup", "Pick one of the scheduled backups to restore", "b"),
 		// — Account —
 		components.NewMenuItem("Token Management", "Manage your GitHub Personal Access Token", "t"),
 	}
@@ -179,6 +235,12 @@ func NewGistModel(favoritePath string) GistModel {
 	gistList.SetShowTitle(false)
 	gistList.SetShowPagination(false)
 
+	backupList := list.New([]list.Item{}, createStyledDelegate(), 50, 20)
+	backupList.SetShowStatusBar(false)
+	backupList.SetShowHelp(false)
+	backupList.SetShowTitle(false)
+	backupList.SetFilteringEnabled(false)
+
 	ti := textinput.New()
 	ti.Placeholder = "Type here..."
 	ti.Focus()
@@ -197,6 +259,7 @@ func NewGistModel(favoritePath string) GistModel {
 		tokenMenuList:  tokenMenuList,
 		visibilityMenu: visibilityMenu,
 		gistList:       gistList,
+		backupList:     backupList,
 		textInput:      ti,
 		token:          token,
 		syncPrefs:      syncPrefs,
@@ -249,7 +312,7 @@ func (m GistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case gistStateCreateVisibility,
 			gistStateList, gistStateUpdate, gistStateDelete,
-			gistStateRecover, gistStateTokenMenu:
+			gistStateRecover, gistStateTokenMenu, gistStateAutoBackups:
 			if msg.String() == "esc" {
 				m.state = gistStateMenu
 				m.message = ""
@@ -265,6 +328,8 @@ func (m GistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.visibilityMenu.SetWidth(msg.Width)
 		m.gistList.SetWidth(msg.Width)
 		m.gistList.SetHeight(availableListHeight(msg.Height))
+		m.backupList.SetWidth(msg.Width)
+		m.backupList.SetHeight(availableListHeight(msg.Height))
 		m.checklist.SetWidth(msg.Width)
 		return m, nil
 
@@ -275,13 +340,14 @@ func (m GistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Dismiss transient states on error, returning to the menu.
 		// gistStateRestoreGistURL is intentionally excluded: errors received
 		// while fetching stay on the URL form so the user can correct a typo
-		// or retry without losing their input.
+		// or retry without losing their input. So is gistStatePassphrase, to
+		// try again after a wrong passphrase.
 		switch m.state {
 		case gistStateCreate, gistStateList, gistStateUpdate, gistStateDelete,
 			gistStateRecover, gistStateImportURL, gistStateSyncProgress,
 			gistStateExportPath, gistStateRestoreZipPath,
 			gistStateRestoreZipChecklist, gistStateSyncGistChecklist,
-			gistStateRestoreGistChecklist:
+			gistStateRestoreGistChecklist, gistStateAutoBackups:
 			m.state = gistStateMenu
 		}
 		return m, nil
@@ -348,15 +414,50 @@ func (m GistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case zipInspectedMsg:
 		// Zip opened OK — show checklist of available categories.
 		m.pendingZipPath = msg.path
+		m.passphrase = msg.passphrase
+		if msg.encrypted && msg.passphrase == "" {
+			m.pendingAvailable = msg.available
+			return m.startPassphrase(passphraseRestoreZip), nil
+		}
 		m.checklist = availableChecklist("Select categories to restore from zip:", msg.available)
 		m.state = gistStateRestoreZipChecklist
 		return m, nil
 
+	case backupGenerationsMsg:
+		if m.state != gistStateAutoBackups {
+			return m, nil
+		}
+		m.backupDir = msg.dir
+		m.backupList.SetItems(msg.items)
+		if m.height > 0 {
+			m.backupList.SetHeight(availableListHeight(m.height))
+		} else {
+			m.backupList.SetHeight(10)
+		}
+		m.message = ""
+		return m, nil
+
+	case passphraseAcceptedMsg:
+		if m.state != gistStatePassphrase {
+			return m, nil
+		}
+		m.message = msg.warning
+		m.messageIsError = msg.warning != ""
+		switch m.passphraseFor {
+		case passphraseRestoreZip:
+			m.checklist = availableChecklist("Select categories to restore from zip:", m.pendingAvailable)
+			m.state = gistStateRestoreZipChecklist
+		case passphraseRestoreGist:
+			m.checklist = availableChecklist("Select categories to restore from Gist:", m.pendingAvailable)
+			m.state = gistStateRestoreGistChecklist
+		}
+		return m, nil
+
 	case zipConflictCheckMsg:
 		if len(msg.conflicts) == 0 {
 			// No conflicts — restore immediately (force=false is fine).
 			m.state = gistStateSyncProgress
-			return m, m.restoreZipCmd(msg.zipPath, msg.prefs, false)
+			return m, m.restoreZipCmd(msg.zipPath, msg.prefs, false, m.passphrase)
 		}
 		// Conflicts exist — warn user.
 		m.pendingZipPath = msg.zipPath
@@ -376,6 +477,11 @@ func (m GistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.message = ""
 		m.messageIsError = false
 		m.pendingGist = msg.g
+		m.passphrase = msg.passphrase
+		if msg.encrypted && msg.passphrase == "" {
+			m.pendingAvailable = msg.available
+			return m.startPassphrase(passphraseRestoreGist), nil
+		}
 		m.checklist = availableChecklist("Select categories to restore from Gist:", msg.available)
 		m.state = gistStateRestoreGistChecklist
 		return m, nil
@@ -383,7 +489,7 @@ func (m GistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case gistConflictCheckMsg:
 		if len(msg.conflicts) == 0 {
 			m.state = gistStateSyncProgress
-			return m, m.restoreGistCmd(msg.g, msg.prefs, false)
+			return m, m.restoreGistCmd(msg.g, msg.prefs, false, m.passphrase)
 		}
 		m.pendingGist = msg.g
 		m.pendingPrefs = msg.prefs
@@ -566,8 +672,8 @@ func (m GistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
 			case "enter":
-				m.state = gistStateSyncProgress
-				return m, m.doExportZipCmd(m.textInput.Value(), m.pendingPrefs)
+				m.pendingZipPath = m.textInput.Value()
+				return m.startPassphrase(passphraseExportZip), nil
 			case "esc":
 				m.state = gistStateExportChecklist
 				return m, nil
@@ -576,6 +682,26 @@ func (m GistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.textInput, cmd = m.textInput.Update(msg)
 		cmds = append(cmds, cmd)
 
+	case gistStatePassphrase:
+		return m.updatePassphrase(msg)
+
+	case gistStateAutoBackups:
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
+			item, ok := m.backupList.SelectedItem().(backupGenerationItem)
+			if !ok {
+				return m, nil
+			}
+			if item.err != nil {
+				m.message = fmt.Sprintf("Cannot restore this backup: %v", item.err)
+				m.messageIsError = true
+				return m, nil
+			}
+			m.message = ""
+			return m, m.doInspectZipCmd(item.gen.Path)
+		}
+		m.backupList, cmd = m.backupList.Update(msg)
+		cmds = append(cmds, cmd)
+
 	case gistStateRestoreZipPath:
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
@@ -597,9 +723,9 @@ func (m GistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.state = gistStateSyncProgress
 				switch m.overwriteSrc {
 				case overwriteSourceZip:
-					return m, m.restoreZipCmd(m.pendingZipPath, m.pendingPrefs, true)
+					return m, m.restoreZipCmd(m.pendingZipPath, m.pendingPrefs, true, m.passphrase)
 				case overwriteSourceGist:
-					return m, m.restoreGistCmd(m.pendingGist, m.pendingPrefs, true)
+					return m, m.restoreGistCmd(m.pendingGist, m.pendingPrefs, true, m.passphrase)
 				}
 			case "esc":
 				m.state = gistStateMenu
@@ -618,6 +744,72 @@ func (m GistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // ── Sub-update helpers ────────────────────────────────────────────────────────
 
+// startPassphrase opens the passphrase prompt, filled in with the passphrase
+// remembered in the keychain when encrypting.
+func (m GistModel) startPassphrase(action passphraseAction) GistModel {
+	remembered := gist.LoadBackupPassphrase()
+	m.passphraseFor = action
+	m.rememberPassphrase = remembered != ""
+	m.textInput.Placeholder = "Passphrase"
+	if action.encrypting() {
+		m.textInput.Placeholder = "Passphrase (leave empty for no encryption)"
+	} else {
+		remembered = "" // it was tried already
+	}
+	m.textInput.SetValue(remembered)
+	m.textInput.EchoMode = textinput.EchoPassword
+	m.textInput.Focus()
+	m.state = gistStatePassphrase
+	return m
+}
+
+func (m GistModel) updatePassphrase(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "enter":
+			passphrase := m.textInput.Value()
+			m.passphrase = passphrase
+			switch m.passphraseFor {
+			case passphraseExportZip:
+				m.state = gistStateSyncProgress
+				return m, m.doExportZipCmd(m.pendingZipPath, m.pendingPrefs, passphrase, m.rememberPassphrase)
+			case passphraseSyncGist:
+				m.state = gistStateSyncProgress
+				return m, m.doSyncToGistCmd(m.pendingPrefs, passphrase, m.rememberPassphrase)
+			default:
+				if passphrase == "" {
+					m.message = "Enter the passphrase of this backup."
+					m.messageIsError = true
+					return m, nil
+				}
+				m.message = "Checking passphrase…"
+				m.messageIsError = false
+				return m, m.checkPassphraseCmd(passphrase, m.rememberPassphrase)
+			}
+		case "tab":
+			m.rememberPassphrase = !m.rememberPassphrase
+			return m, nil
+		case "esc":
+			m.message = ""
+			m.textInput.EchoMode = textinput.EchoNormal
+			switch m.passphraseFor {
+			case passphraseExportZip:
+				m.textInput.Placeholder = "Save location"
+				m.textInput.SetValue(m.pendingZipPath)
+				m.state = gistStateExportPath
+			case passphraseSyncGist:
+				m.state = gistStateSyncGistChecklist
+			default:
+				m.state = gistStateMenu
+			}
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	return m, cmd
+}
+
 func (m GistModel) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 	keyMsg, ok := msg.(tea.KeyMsg)
 	if !ok {
@@ -702,7 +894,12 @@ func (m GistModel) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.textInput.Focus()
 		return m, nil
 
-	case 10: // Token Management
+	case 10: // Restore automatic backup
+		m.state = gistStateAutoBackups
+		m.message = "Loading backups…"
+		return m, m.loadBackupGenerationsCmd()
+
+	case 11: // Token Management
 		m.state = gistStateTokenMenu
 		return m.initTokenMenu()
 	}
@@ -831,6 +1028,7 @@ func (m GistModel) buildDefaultChecklist(title string) components.ChecklistModel
 		{Key: "blocklist", Label: "Blocklist", Checked: m.syncPrefs.Blocklist},
 		{Key: "metadata_tags", Label: "Station metadata & tags", Checked: m.syncPrefs.MetadataTags},
 		{Key: "search_history", Label: "Search history", Checked: m.syncPrefs.SearchHistory},
+		{Key: "liked_songs", Label: "Liked songs", Checked: m.syncPrefs.LikedSongs},
 	}
 	return components.NewChecklistModel(title, items)
 }
@@ -850,6 +1048,7 @@ func availableChecklist(title string, available storage.SyncPrefs) components.Ch
 		{"blocklist", "Blocklist", available.Blocklist},
 		{"metadata_tags", "Station metadata & tags", available.MetadataTags},
 		{"search_history", "Search history", available.SearchHistory},
+		{"liked_songs", "Liked songs", available.LikedSongs},
 	}
 	var items []components.ChecklistItem
 	for _, e := range all {
@@ -877,6 +1076,8 @@ func checklistToPrefs(items []components.ChecklistItem) storage.SyncPrefs {
 			p.MetadataTags = item.Checked
 		case "search_history":
 			p.SearchHistory = item.Checked
+		case "liked_songs":
+			p.LikedSongs = item.Checked
 		}
 	}
 	return p
@@ -920,8 +1121,7 @@ func (m GistModel) handleChecklistConfirmed(msg components.ChecklistConfirmedMsg
 			m.messageIsError = true
 		}
 		m.pendingPrefs = prefs
-		m.state = gistStateSyncProgress
-		return m, m.doSyncToGistCmd(prefs)
+		return m.startPassphrase(passphraseSyncGist), nil
 
 	case gistStateRestoreGistChecklist:
 		m.pendingPrefs = prefs
@@ -933,7 +1133,7 @@ func (m GistModel) handleChecklistConfirmed(msg components.ChecklistConfirmedMsg
 
 // ── Async Cmd factories (Phase 3) ─────────────────────────────────────────────
 
-func (m GistModel) doExportZipCmd(rawPath string, prefs storage.SyncPrefs) tea.Cmd {
+func (m GistModel) doExportZipCmd(rawPath string, prefs storage.SyncPrefs, passphrase string, remember bool) tea.Cmd {
 	bm := m.backupManager
 	return func() tea.Msg {
 		if bm == nil {
@@ -943,10 +1143,13 @@ func (m GistModel) doExportZipCmd(rawPath string, prefs storage.SyncPrefs) tea.C
 		if err != nil {
 			return errMsg{fmt.Errorf("invalid path: %w", err)}
 		}
-		if err := bm.Export(resolved, prefs); err != nil {
+		if err := bm.ExportWithPassphrase(resolved, prefs, passphrase); err != nil {
 			return errMsg{fmt.Errorf("export failed: %w", err)}
 		}
-		return successMsg{fmt.Sprintf("✓ Backup saved to %s", resolved)}
+		if passphrase == "" {
+			return successMsg{fmt.Sprintf("✓ Backup saved to %s", resolved)}
+		}
+		return successMsg{fmt.Sprintf("✓ Encrypted backup saved to %s", resolved) + storePassphrase(passphrase, remember)}
 	}
 }
 
@@ -967,7 +1170,16 @@ func (m GistModel) doInspectZipCmd(rawPath string) tea.Cmd {
 		if available == (storage.SyncPrefs{}) {
 			return errMsg{fmt.Errorf("zip contains no recognised tera data files")}
 		}
-		return zipInspectedMsg{path: resolved, available: available}
+		encrypted, err := bm.IsArchiveEncrypted(resolved)
+		if err != nil {
+			return errMsg{fmt.Errorf("cannot read zip: %w", err)}
+		}
+		msg := zipInspectedMsg{path: resolved, available: available, encrypted: encrypted}
+		if remembered := gist.LoadBackupPassphrase(); encrypted && remembered != "" &&
+			bm.CheckArchivePassphrase(resolved, remembered) == nil {
+			msg.passphrase = remembered
+		}
+		return msg
 	}
 }
 
@@ -985,13 +1197,13 @@ func (m GistModel) doCheckZipConflictsCmd(zipPath string, prefs storage.SyncPref
 	}
 }
 
-func (m GistModel) restoreZipCmd(zipPath string, prefs storage.SyncPrefs, force bool) tea.Cmd {
+func (m GistModel) restoreZipCmd(zipPath string, prefs storage.SyncPrefs, force bool, passphrase string) tea.Cmd {
 	bm := m.backupManager
 	return func() tea.Msg {
 		if bm == nil {
 			return errMsg{fmt.Errorf("backup manager unavailable")}
 		}
-		if err := bm.Restore(zipPath, prefs, force); err != nil {
+		if err := bm.RestoreWithPassphrase(zipPath, prefs, force, passphrase); err != nil {
 			return errMsg{fmt.Errorf("restore failed: %w", err)}
 		}
 		return successMsg{"✓ Data restored successfully from zip."}
@@ -1032,23 +1244,139 @@ func (m GistModel) doFetchAvailableGistCategoriesCmd(requestID uint64, gistID st
 		if available == (storage.SyncPrefs{}) {
 			return errMsg{fmt.Errorf("no recognisable tera data found in this Gist")}
 		}
-		return gistRestoreAvailableMsg{requestID: requestID, g: g, available: available}
+		msg := gistRestoreAvailableMsg{requestID: requestID, g: g, available: available, encrypted: storage.GistEncrypted(g)}
+		if remembered := gist.LoadBackupPassphrase(); msg.encrypted && remembered != "" &&
+			storage.CheckGistPassphrase(g, remembered) == nil {
+			msg.passphrase = remembered
+		}
+		return msg
 	}
 }
 
-func (m GistModel) doSyncToGistCmd(prefs storage.SyncPrefs) tea.Cmd {
+func (m GistModel) doSyncToGistCmd(prefs storage.SyncPrefs, passphrase string, remember bool) tea.Cmd {
 	mgr := m.gistSyncMgr
 	return func() tea.Msg {
 		if mgr == nil {
 			return errMsg{fmt.Errorf("no Gist sync manager — token required")}
 		}
-		if err := mgr.Push(prefs); err != nil {
+		if err := mgr.PushWithPassphrase(prefs, passphrase); err != nil {
 			return errMsg{fmt.Errorf("gist sync failed: %w", err)}
 		}
-		return successMsg{fmt.Sprintf("✓ Data synced to Gist (%s).", storage.BackupGistDescription)}
+		if passphrase == "" {
+			return successMsg{fmt.Sprintf("✓ Data synced to Gist (%s).", storage.BackupGistDescription)}
+		}
+		return successMsg{fmt.Sprintf("✓ Data encrypted and synced to Gist (%s).", storage.BackupGistDescription) +
+			storePassphrase(passphrase, remember)}
 	}
 }
 
+// loadBackupGenerationsCmd lists the automatic backups with the categories
+// each one holds, newest first.
+func (m GistModel) loadBackupGenerationsCmd() tea.Cmd {
+	bm := m.backupManager
+	return func() tea.Msg {
+		if bm == nil {
+			return errMsg{fmt.Errorf("backup manager unavailable")}
+		}
+		cfg, _ := storage.LoadAutoBackupConfigFromUnified()
+		dir, err := bm.AutoBackupDir(cfg.Dir)
+		if err != nil {
+			return errMsg{err}
+		}
+		gens, err := storage.ListBackupGenerations(dir)
+		if err != nil {
+			return errMsg{fmt.Errorf("cannot list backups: %w", err)}
+		}
+		if len(gens) == 0 {
+			if !cfg.Enabled {
+				return errMsg{fmt.Errorf("no automatic backups yet; set auto_backup.enabled in config.yaml to take them")}
+			}
+			return errMsg{fmt.Errorf("no automatic backups in %s yet", dir)}
+		}
+		items := make([]list.Item, len(gens))
+		for i, g := range gens {
+			item := backupGenerationItem{gen: g}
+			item.categories, item.err = bm.ListArchiveCategories(g.Path)
+			if item.err == nil {
+				item.encrypted, item.err = bm.IsArchiveEncrypted(g.Path)
+			}
+			items[i] = item
+		}
+		return backupGenerationsMsg{dir: dir, items: items}
+	}
+}
+
+// categorySummary names the categories in p, for the backup picker.
+func categorySummary(p storage.SyncPrefs) string {
+	var names []string
+	for _, c := range []struct {
+		on   bool
+		name string
+	}{
+		{p.Favorites, "favorites"},
+		{p.Settings, "settings"},
+		{p.RatingsVotes, "ratings"},
+		{p.Blocklist, "blocklist"},
+		{p.MetadataTags, "metadata & tags"},
+		{p.SearchHistory, "search history"},
+		{p.LikedSongs, "liked songs"},
+	} {
+		if c.on {
+			names = append(names, c.name)
+		}
+	}
+	if len(names) == 0 {
+		return "no tera data"
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatBackupSize formats a file size in KB or MB.
+func formatBackupSize(size int64) string {
+	if size < 1<<20 {
+		return fmt.Sprintf("%d KB", (size+1023)>>10)
+	}
+	return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+}
+
+// checkPassphraseCmd tries passphrase on the encrypted zip or Gist being
+// restored. A wrong one comes back as an error, leaving the prompt open.
+func (m GistModel) checkPassphraseCmd(passphrase string, remember bool) tea.Cmd {
+	bm := m.backupManager
+	action, zipPath, g := m.passphraseFor, m.pendingZipPath, m.pendingGist
+	return func() tea.Msg {
+		var err error
+		if action == passphraseRestoreZip {
+			if bm == nil {
+				return errMsg{fmt.Errorf("backup manager unavailable")}
+			}
+			err = bm.CheckArchivePassphrase(zipPath, passphrase)
+		} else {
+			err = storage.CheckGistPassphrase(g, passphrase)
+		}
+		if err != nil {
+			return errMsg{err}
+		}
+		return passphraseAcceptedMsg{warning: strings.TrimPrefix(storePassphrase(passphrase, remember), " ")}
+	}
+}
+
+// storePassphrase saves or forgets the backup passphrase in the keychain as
+// the user chose. It returns a warning to append to the result message if
+// that failed.
+func storePassphrase(passphrase string, remember bool) string {
+	var err error
+	if remember {
+		err = gist.SaveBackupPassphrase(passphrase)
+	} else {
+		err = gist.DeleteBackupPassphrase()
+	}
+	if err != nil {
+		return fmt.Sprintf(" (Warning: %v)", err)
+	}
+	return ""
+}
+
 func (m GistModel) doCheckGistConflictsCmd(g *gist.Gist, prefs storage.SyncPrefs) tea.Cmd {
 	mgr := m.gistSyncMgr
 	return func() tea.Msg {
@@ -1067,17 +1395,17 @@ func (m GistModel) doCheckGistConflictsCmd(g *gist.Gist, prefs storage.SyncPrefs
 	}
 }
 
-func (m GistModel) restoreGistCmd(g *gist.Gist, prefs storage.SyncPrefs, force bool) tea.Cmd {
+func (m GistModel) restoreGistCmd(g *gist.Gist, prefs storage.SyncPrefs, force bool, passphrase string) tea.Cmd {
 	mgr := m.gistSyncMgr
 	return func() tea.Msg {
 		if mgr != nil {
-			if err := mgr.PullFromGist(g, prefs, force); err != nil {
+			if err := mgr.PullFromGistWithPassphrase(g, prefs, force, passphrase); err != nil {
 				return errMsg{fmt.Errorf("gist restore failed: %w", err)}
 			}
 			return successMsg{"✓ Data restored from Gist."}
 		}
 		// No token/sync manager — use standalone restore.
-		if err := storage.RestoreFromGistDirect(g, prefs, force); err != nil {
+		if err := storage.RestoreFromGistDirectWithPassphrase(g, prefs, force, passphrase); err != nil {
 			return errMsg{fmt.Errorf("gist restore failed: %w", err)}
 		}
 		return successMsg{"✓ Data restored from Gist."}
@@ -1098,7 +1426,7 @@ func (m GistModel) View() string {
 			Title:    "Sync & Backup",
 			Subtitle: "Select an Option",
 			Content:  m.viewMenuWithSections() + "\n" + m.renderMessage(),
-			Help:     "↑↓/jk: Navigate • Enter/1-9/a/t: Select • Esc: Back",
+			Help:     "↑↓/jk: Navigate • Enter/1-9/a/b/t: Select • Esc: Back",
 		}, h)
 
 	case gistStateCreateVisibility:
@@ -1271,6 +1599,49 @@ func (m GistModel) View() string {
 			Help:     "Enter: Open • Esc: Cancel",
 		}, h)
 
+	case gistStatePassphrase:
+		title, prompt := "Restore from Backup", "This backup is encrypted. Enter its passphrase:"
+		switch m.passphraseFor {
+		case passphraseExportZip:
+			title, prompt = "Export Backup", "Passphrase to encrypt the backup with:"
+		case passphraseSyncGist:
+			title, prompt = "Sync All Data to Gist", "Passphrase to encrypt the synced files with:"
+		case passphraseRestoreGist:
+			title = "Restore All Data from Gist"
+		}
+		remember := "[ ]"
+		if m.rememberPassphrase {
+			remember = "[x]"
+		}
+		tip := "Leave empty for no encryption. Without the passphrase the backup cannot be restored."
+		if !m.passphraseFor.encrypting() {
+			tip = "The passphrase is the one used when the backup was made."
+		}
+		return m.renderPageWithBottomHelp(PageLayout{
+			Title:    title,
+			Subtitle: "Passphrase",
+			Content: fmt.Sprintf(
+				"%s\n\n%s\n\n%s Remember in the OS keychain\n\n%s",
+				prompt,
+				m.textInput.View(),
+				remember,
+				dimStyle().Render(tip),
+			) + "\n\n" + m.renderMessage(),
+			Help: "Enter: Continue • Tab: Remember • Esc: Back",
+		}, h)
+
+	case gistStateAutoBackups:
+		content := m.backupList.View()
+		if m.backupDir != "" {
+			content += "\n" + dimStyle().Render("Backups in "+m.backupDir)
+		}
+		return m.renderPageWithBottomHelp(PageLayout{
+			Title:    "Restore Automatic Backup",
+			Subtitle: "Select a Backup",
+			Content:  content + "\n" + m.renderMessage(),
+			Help:     "↑↓/jk: Navigate • Enter: Restore • Esc: Back",
+		}, h)
+
 	case gistStateOverwriteWarn:
 		paths := strings.Join(m.overwritePaths, "\n  ")
 		return m.renderPageWithBottomHelp(PageLayout{
@@ -1303,7 +1674,7 @@ func (m GistModel) viewMenuWithSections() string {
 	}{
 		{0, "— Favorites Gist —"},
 		{6, "— Full Backup —"},
-		{10, "— Account —"},
+		{11, "— Account —"},
 	}
 
 	sIdx := 0
@@ -1354,18 +1725,18 @@ func (m *GistModel) initCreateGist() (tea.Model, tea.Cmd) {
 }
 
 func (m *GistModel) createGistCmd() tea.Msg {
-	files := make(map[string]*string)
-	entries, err := os.ReadDir(m.favoritePath)
+	// With the SQLite backend the files are an export; bring them up to
+	// date before they are uploaded.
+	if err := storage.NewStorage(m.favoritePath).Export(); err != nil {
+		return errMsg{err}
+	}
+	contents, err := storage.FavoritesGistFiles(m.favoritePath)
 	if err != nil {
 		return errMsg{err}
 	}
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
-			if content, err := os.ReadFile(filepath.Join(m.favoritePath, entry.Name())); err == nil {
-				s := string(content)
-				files[entry.Name()] = &s
-			}
-		}
+	files := make(map[string]*string, len(contents))
+	for name, content := range contents {
+		files[name] = &content
 	}
 	if len(files) == 0 {
 		return errMsg{fmt.Errorf("no favorite lists found in %s", m.favoritePath)}
@@ -1440,28 +1811,24 @@ func (m *GistModel) recoverGistCmd(gistID string) tea.Cmd {
 		timestamp := time.Now().Format("20060102-150405")
 		var backupFailures []string
 		for filename := range g.Files {
-			cleanName := filepath.Base(filename)
-			if cleanName != filename || cleanName == "." || cleanName == ".." {
+			rel := storage.FavoritesGistPath(filename)
+			if rel == "" {
 				continue
 			}
-			existingPath := filepath.Join(m.favoritePath, cleanName)
+			existingPath := filepath.Join(m.favoritePath, filepath.FromSlash(rel))
 			if _, err := os.Stat(existingPath); err == nil {
-				backupPath := filepath.Join(backupDir, fmt.Sprintf("%s.%s.bak", cleanName, timestamp))
+				backupPath := filepath.Join(backupDir, fmt.Sprintf("%s.%s.bak", filename, timestamp))
 				if data, err := os.ReadFile(existingPath); err == nil {
 					if err := os.WriteFile(backupPath, data, 0644); err != nil {
-						backupFailures = append(backupFailures, cleanName)
+						backupFailures = append(backupFailures, filename)
 					}
 				} else {
-					backupFailures = append(backupFailures, cleanName)
+					backupFailures = append(backupFailures, filename)
 				}
 			}
 		}
 		for filename, file := range g.Files {
-			cleanName := filepath.Base(filename)
-			if cleanName != filename || cleanName == "." || cleanName == ".." {
-				continue
-			}
-			if err := os.WriteFile(filepath.Join(m.favoritePath, cleanName), []byte(file.Content), 0644); err != nil {
+			if err := m.writeGistFavorite(filename, file.Content); err != nil {
 				return errMsg{err}
 			}
 		}
@@ -1472,6 +1839,21 @@ func (m *GistModel) recoverGistCmd(gistID string) tea.Cmd {
 	}
 }
 
+// writeGistFavorite writes the favorites Gist file filename to its place in
+// the favorites directory, creating the folder of a nested list. Names that
+// would leave the directory are skipped.
+func (m *GistModel) writeGistFavorite(filename, content string) error {
+	rel := storage.FavoritesGistPath(filename)
+	if rel == "" {
+		return nil
+	}
+	dest := filepath.Join(m.favoritePath, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, []byte(content), 0644)
+}
+
 func (m *GistModel) importGistCmd(gistID string) tea.Cmd {
 	return func() tea.Msg {
 		var g *gist.Gist
@@ -1491,13 +1873,13 @@ func (m *GistModel) importGistCmd(gistID string) tea.Cmd {
 		}
 		timestamp := time.Now().Format("20060102-150405")
 		for filename := range g.Files {
-			cleanName := filepath.Base(filename)
-			if cleanName != filename || cleanName == "." || cleanName == ".." {
+			rel := storage.FavoritesGistPath(filename)
+			if rel == "" {
 				continue
 			}
-			existingPath := filepath.Join(m.favoritePath, cleanName)
+			existingPath := filepath.Join(m.favoritePath, filepath.FromSlash(rel))
 			if _, err := os.Stat(existingPath); err == nil {
-				backupPath := filepath.Join(backupDir, fmt.Sprintf("%s.%s.bak", cleanName, timestamp))
+				backupPath := filepath.Join(backupDir, fmt.Sprintf("%s.%s.bak", filename, timestamp))
 				if data, err := os.ReadFile(existingPath); err == nil {
 					_ = os.WriteFile(backupPath, data, 0644)
 				}
@@ -1505,11 +1887,10 @@ func (m *GistModel) importGistCmd(gistID string) tea.Cmd {
 		}
 		importedCount := 0
 		for filename, file := range g.Files {
-			cleanName := filepath.Base(filename)
-			if cleanName != filename || cleanName == "." || cleanName == ".." {
+			if storage.FavoritesGistPath(filename) == "" {
 				continue
 			}
-			if err := os.WriteFile(filepath.Join(m.favoritePath, cleanName), []byte(file.Content), 0644); err != nil {
+			if err := m.writeGistFavorite(filename, file.Content); err != nil {
 				return errMsg{err}
 			}
 			importedCount++
@@ -1586,3 +1967,14 @@ func (m GistModel) renderPageWithBottomHelp(layout PageLayout, height int) strin
 	layout.NowPlaying = m.nowPlayingBar
 	return RenderPageWithBottomHelp(layout, height)
 }
+
+// capturesKeys is true in the states that read a name, URL, token, path or
+// passphrase, and while filtering gists.
+func (m GistModel) capturesKeys() bool {
+	switch m.state {
+	case gistStateCreateName, gistStateUpdateInput, gistStateImportURL, gistStateTokenSetup,
+		gistStateRestoreGistURL, gistStateExportPath, gistStateRestoreZipPath, gistStatePassphrase:
+		return true
+	}
+	return m.gistList.SettingFilter()
+}
@@ -0,0 +1,424 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/player"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// likeCurrentSong bookmarks the track p is playing on station and returns the
+// status line to show on the play screen.
+func likeCurrentSong(mgr *storage.LikedSongsManager, p *player.MPVPlayer, station *api.Station) string {
+	if mgr == nil || p == nil || station == nil {
+		return ""
+	}
+	info := p.GetCachedTrackInfo()
+	if !IsValidTrackMetadata(info.Raw, station.TrimName()) {
+		return "✗ No song title to like yet"
+	}
+	song, err := mgr.Add(storage.LikedSong{
+		Title:  info.Raw,
+		Artist: info.Artist,
+		Track:  info.Title,
+		Album:  info.Album,
+	}, *station)
+	if err != nil {
+		if errors.Is(err, storage.ErrAlreadyLiked) {
+			return "Already liked: " + song.Display()
+		}
+		return fmt.Sprintf("✗ Failed to like song: %v", err)
+	}
+	return "✓ Liked: " + song.Display()
+}
+
+// likedSongsState represents the current state in the liked songs screen
+type likedSongsState int
+
+const (
+	likedSongsList likedSongsState = iota
+	likedSongsSearch
+	likedSongsConfirmDelete
+	likedSongsExport
+)
+
+// likedSongsExportFormats lists the export choices in menu order.
+var likedSongsExportFormats = []struct {
+	format string
+	label  string
+}{
+	{storage.LikedSongsFormatCSV, "CSV (spreadsheet)"},
+	{storage.LikedSongsFormatJSON, "JSON"},
+	{storage.LikedSongsFormatQueries, "Search queries (one per line)"},
+}
+
+// LikedSongsModel browses, searches, deletes and exports bookmarked songs.
+type LikedSongsModel struct {
+	state         likedSongsState
+	manager       *storage.LikedSongsManager
+	songs         []storage.LikedSong // current (filtered) view, newest first
+	listModel     list.Model
+	searchInput   textinput.Model
+	query         string
+	message       string
+	messageTime   int
+	width         int
+	height        int
+	nowPlayingBar string // set by App when ContinueOnNavigate is active
+}
+
+// likedSongItem wraps a LikedSong for the list
+type likedSongItem struct {
+	song storage.LikedSong
+}
+
+func (i likedSongItem) Title() string {
+	return fmt.Sprintf("%s  —  %s · %s", i.song.Display(), i.song.StationName, i.song.LikedAt.Format("Jan 2 2006 15:04"))
+}
+func (i likedSongItem) Description() string { return "" }
+func (i likedSongItem) FilterValue() string { return i.song.Display() }
+
+type likedSongsExportedMsg struct {
+	path  string
+	count int
+}
+
+// NewLikedSongsModel creates the liked songs screen
+func NewLikedSongsModel(manager *storage.LikedSongsManager) LikedSongsModel {
+	l := list.New([]list.Item{}, createStyledDelegate(), 80, 20)
+	l.Title = "♥ Liked Songs"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	l.SetShowPagination(true)
+	l.Styles.Title = listTitleStyle()
+	l.Styles.PaginationStyle = paginationStyle()
+
+	ti := textinput.New()
+	ti.Placeholder = "Search title or station..."
+	ti.CharLimit = 100
+
+	m := LikedSongsModel{
+		state:       likedSongsList,
+		manager:     manager,
+		listModel:   l,
+		searchInput: ti,
+	}
+	m.refresh()
+	return m
+}
+
+// Init initializes the liked songs screen
+func (m LikedSongsModel) Init() tea.Cmd {
+	return nil
+}
+
+// refresh reloads the list from the manager using the current query.
+func (m *LikedSongsModel) refresh() {
+	m.songs = nil
+	if m.manager != nil {
+		m.songs = m.manager.Search(m.query)
+	}
+	items := make([]list.Item, len(m.songs))
+	for i, s := range m.songs {
+		items[i] = likedSongItem{song: s}
+	}
+	idx := m.listModel.Index()
+	m.listModel.SetItems(items)
+	if idx >= len(items) {
+		idx = len(items) - 1
+	}
+	m.listModel.Select(max(idx, 0))
+}
+
+func (m LikedSongsModel) selectedSong() (storage.LikedSong, bool) {
+	idx := m.listModel.Index()
+	if idx < 0 || idx >= len(m.songs) {
+		return storage.LikedSong{}, false
+	}
+	return m.songs[idx], true
+}
+
+// Update handles messages for the liked songs screen
+func (m LikedSongsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Decrement message timer
+	if m.messageTime > 0 {
+		m.messageTime--
+		if m.messageTime == 0 {
+			m.message = ""
+		}
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch m.state {
+		case likedSongsSearch:
+			return m.handleSearchInput(msg)
+		case likedSongsConfirmDelete:
+			return m.handleConfirmDeleteInput(msg)
+		case likedSongsExport:
+			return m.handleExportInput(msg)
+		}
+		return m.handleListInput(msg)
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		// Same page overhead as the blocklist screen
+		listHeight := msg.Height - 14
+		if listHeight < 5 {
+			listHeight = 5
+		}
+		m.listModel.SetSize(msg.Width-4, listHeight)
+		return m, nil
+
+	case likedSongsExportedMsg:
+		m.message = fmt.Sprintf("✓ Exported %d song(s) to: %s", msg.count, msg.path)
+		m.messageTime = 200
+		m.state = likedSongsList
+		return m, nil
+
+	case errMsg:
+		m.message = fmt.Sprintf("✗ %v", msg.err)
+		m.messageTime = 180 // 3 seconds (at ~60fps)
+		m.state = likedSongsList
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.listModel, cmd = m.listModel.Update(msg)
+	return m, cmd
+}
+
+func (m LikedSongsModel) handleListInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		// Clear an active search first, then leave
+		if m.query != "" {
+			m.query = ""
+			m.searchInput.SetValue("")
+			m.refresh()
+			return m, nil
+		}
+		return m, func() tea.Msg { return navigateMsg{screen: screenList} }
+	case "0":
+		return m, func() tea.Msg { return navigateMsg{screen: screenMainMenu} }
+	case "q":
+		return m, tea.Quit
+	case "/":
+		m.state = likedSongsSearch
+		m.searchInput.SetValue(m.query)
+		m.searchInput.CursorEnd()
+		m.searchInput.Focus()
+		return m, textinput.Blink
+	case "d", "x":
+		if _, ok := m.selectedSong(); ok {
+			m.state = likedSongsConfirmDelete
+		}
+		return m, nil
+	case "e":
+		if len(m.songs) == 0 {
+			m.message = "No liked songs to export"
+			m.messageTime = 150
+			return m, nil
+		}
+		m.state = likedSongsExport
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.listModel, cmd = m.listModel.Update(msg)
+	return m, cmd
+}
+
+// handleSearchInput filters the list as the user types.
+func (m LikedSongsModel) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.query = ""
+		m.searchInput.SetValue("")
+		m.searchInput.Blur()
+		m.state = likedSongsList
+		m.refresh()
+		return m, nil
+	case "enter", "down", "up":
+		m.searchInput.Blur()
+		m.state = likedSongsList
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	if q := m.searchInput.Value(); q != m.query {
+		m.query = q
+		m.listModel.Select(0)
+		m.refresh()
+	}
+	return m, cmd
+}
+
+func (m LikedSongsModel) handleConfirmDeleteInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		m.state = likedSongsList
+		song, ok := m.selectedSong()
+		if !ok {
+			return m, nil
+		}
+		if err := m.manager.Delete(song.ID); err != nil {
+			m.message = fmt.Sprintf("✗ %v", err)
+		} else {
+			m.message = fmt.Sprintf("✓ Removed: %s", song.Display())
+		}
+		m.messageTime = 180 // 3 seconds (at ~60fps)
+		m.refresh()
+		return m, nil
+	case "n", "N", "esc":
+		m.state = likedSongsList
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m LikedSongsModel) handleExportInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "esc" {
+		m.state = likedSongsList
+		return m, nil
+	}
+	if len(key) == 1 && key[0] >= '1' && int(key[0]-'1') < len(likedSongsExportFormats) {
+		return m, exportLikedSongs(m.songs, likedSongsExportFormats[key[0]-'1'].format)
+	}
+	return m, nil
+}
+
+// exportLikedSongs writes songs to the default export path for format.
+func exportLikedSongs(songs []storage.LikedSong, format string) tea.Cmd {
+	return func() tea.Msg {
+		path, err := storage.DefaultLikedSongsExportPath(format)
+		if err != nil {
+			return errMsg{err}
+		}
+		var b strings.Builder
+		if err := storage.ExportLikedSongs(&b, songs, format); err != nil {
+			return errMsg{err}
+		}
+		if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+			return errMsg{fmt.Errorf("failed to write export: %w", err)}
+		}
+		return likedSongsExportedMsg{path: path, count: len(songs)}
+	}
+}
+
+// View renders the liked songs screen
+func (m LikedSongsModel) View() string {
+	switch m.state {
+	case likedSongsConfirmDelete:
+		return m.viewConfirmDelete()
+	case likedSongsExport:
+		return m.viewExport()
+	}
+	return m.viewList()
+}
+
+func (m LikedSongsModel) viewList() string {
+	var content strings.Builder
+
+	total := 0
+	if m.manager != nil {
+		total = m.manager.Count()
+	}
+	subtitle := fmt.Sprintf("%d song(s) liked", total)
+	if m.query != "" {
+		subtitle = fmt.Sprintf("%d of %d song(s) match %q", len(m.songs), total, m.query)
+	}
+
+	if m.state == likedSongsSearch {
+		content.WriteString("Search: ")
+		content.WriteString(m.searchInput.View())
+		content.WriteString("\n\n")
+	}
+
+	if m.message != "" {
+		style := successStyle()
+		if strings.Contains(m.message, "✗") {
+			style = errorStyle()
+		} else if !strings.Contains(m.message, "✓") {
+			style = infoStyle()
+		}
+		content.WriteString(style.Render(m.message))
+		content.WriteString("\n\n")
+	}
+
+	switch {
+	case total == 0:
+		content.WriteString(infoStyle().Render("No liked songs yet.\n\nPress 'l' while a station is playing to save the current song."))
+	case len(m.songs) == 0:
+		content.WriteString(infoStyle().Render("No liked songs match your search."))
+	default:
+		content.WriteString(m.listModel.View())
+	}
+
+	help := "↑↓/jk: Navigate • /: Search • d: Delete • e: Export • Esc: Back • 0: Main Menu"
+	if m.state == likedSongsSearch {
+		help = "Type to filter • Enter: Done • Esc: Clear search"
+	}
+	return m.renderPageWithBottomHelp(PageLayout{
+		Title:    "Liked Songs",
+		Subtitle: subtitle,
+		Content:  content.String(),
+		Help:     help,
+	}, m.height)
+}
+
+func (m LikedSongsModel) viewConfirmDelete() string {
+	var content strings.Builder
+	if song, ok := m.selectedSong(); ok {
+		fmt.Fprintf(&content, "Remove %q from liked songs?", song.Display())
+	}
+	return m.renderPageWithBottomHelp(PageLayout{
+		Title:   "Confirm Delete",
+		Content: content.String(),
+		Help:    "y: Yes, remove • n/Esc: No, cancel",
+	}, m.height)
+}
+
+func (m LikedSongsModel) viewExport() string {
+	var content strings.Builder
+	scope := "all liked songs"
+	if m.query != "" {
+		scope = fmt.Sprintf("the %d song(s) matching %q", len(m.songs), m.query)
+	}
+	fmt.Fprintf(&content, "Export %s as:\n\n", scope)
+	for i, f := range likedSongsExportFormats {
+		content.WriteString(normalItemStyle().Render(fmt.Sprintf("  %d. %s", i+1, f.label)))
+		content.WriteString("\n")
+	}
+	if path, err := storage.DefaultLikedSongsExportPath("<format>"); err == nil {
+		content.WriteString("\n")
+		content.WriteString(dimStyle().Render("Saved to " + path))
+	}
+	return m.renderPageWithBottomHelp(PageLayout{
+		Title:   "Export Liked Songs",
+		Content: content.String(),
+		Help:    fmt.Sprintf("1-%d: Choose format • Esc: Cancel", len(likedSongsExportFormats)),
+	}, m.height)
+}
+
+// renderPageWithBottomHelp wraps RenderPageWithBottomHelp injecting the active now-playing bar.
+func (m LikedSongsModel) renderPageWithBottomHelp(layout PageLayout, height int) string {
+	layout.NowPlaying = m.nowPlayingBar
+	return RenderPageWithBottomHelp(layout, height)
+}
+
+// capturesKeys is true while searching songs.
+func (m LikedSongsModel) capturesKeys() bool {
+	return m.state == likedSongsSearch
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+func newTestLikedSongsModel(t *testing.T, titles ...string) LikedSongsModel {
+	t.Helper()
+	mgr, _ := storage.NewLikedSongsManager(t.TempDir())
+	station := api.Station{StationUUID: "uuid-1", Name: "Jazz FM"}
+	for _, title := range titles {
+		if _, err := mgr.Add(storage.LikedSong{Title: title}, station); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := NewLikedSongsModel(mgr)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	return updated.(LikedSongsModel)
+}
+
+func sendLikedSongsKeys(m LikedSongsModel, keys ...tea.KeyMsg) LikedSongsModel {
+	for _, k := range keys {
+		updated, _ := m.Update(k)
+		m = updated.(LikedSongsModel)
+	}
+	return m
+}
+
+func TestLikedSongsSearchFilters(t *testing.T) {
+	m := newTestLikedSongsModel(t, "Blue in Green", "So What", "Blue Train")
+
+	m = sendLikedSongsKeys(m,
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("blue")},
+	)
+	if m.state != likedSongsSearch {
+		t.Fatalf("expected search state, got %v", m.state)
+	}
+	if len(m.songs) != 2 || m.songs[0].Title != "Blue Train" {
+		t.Errorf("expected 2 newest-first matches, got %+v", m.songs)
+	}
+
+	// Enter keeps the filter; esc in the list clears it.
+	m = sendLikedSongsKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.state != likedSongsList || len(m.songs) != 2 {
+		t.Errorf("enter should keep the filter, state=%v songs=%d", m.state, len(m.songs))
+	}
+	m = sendLikedSongsKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.query != "" || len(m.songs) != 3 {
+		t.Errorf("esc should clear the filter, query=%q songs=%d", m.query, len(m.songs))
+	}
+}
+
+func TestLikedSongsDelete(t *testing.T) {
+	m := newTestLikedSongsModel(t, "Song A", "Song B")
+
+	m = sendLikedSongsKeys(m,
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")},
+	)
+	if m.manager.Count() != 2 {
+		t.Fatal("declining the confirmation must not delete")
+	}
+
+	m = sendLikedSongsKeys(m,
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")},
+	)
+	if m.manager.Count() != 1 || len(m.songs) != 1 || m.songs[0].Title != "Song A" {
+		t.Errorf("expected newest bookmark removed, got %+v", m.songs)
+	}
+}
+
+func TestLikeCurrentSongWithoutPlayer(t *testing.T) {
+	mgr, _ := storage.NewLikedSongsManager(t.TempDir())
+	if msg := likeCurrentSong(mgr, nil, &api.Station{Name: "X"}); msg != "" {
+		t.Errorf("expected no message without a player, got %q", msg)
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/playlist"
 	"github.com/shinokada/tera/v3/internal/storage"
 	"github.com/shinokada/tera/v3/internal/ui/components"
 )
@@ -27,6 +29,9 @@ const (
 	listManagementShowAll
 	listManagementConfirmDelete
 	listManagementEnterNewName
+	listManagementImport
+	listManagementSelectListToExport
+	listManagementSelectExportFormat
 )
 
 // ListManagementModel represents the list management screen
@@ -37,6 +42,7 @@ type ListManagementModel struct {
 	listModel     list.Model
 	textInput     textinput.Model
 	selectedList  string
+	importing     bool // a playlist import is looking up stations
 	err           error
 	message       string
 	messageTime   int
@@ -51,15 +57,8 @@ func NewListManagementModel(favoritePath string) ListManagementModel {
 	ti.Placeholder = "Enter list name"
 	ti.CharLimit = 50
 
-	items := []list.Item{
-		components.NewMenuItem("Create New List", "Create a new favorites list", "1"),
-		components.NewMenuItem("Delete List", "Delete an existing list", "2"),
-		components.NewMenuItem("Edit List Name", "Rename an existing list", "3"),
-		components.NewMenuItem("Show All Lists", "Display all favorite lists", "4"),
-	}
-
 	delegate := components.NewMenuDelegate()
-	l := list.New(items, delegate, 80, 10)
+	l := list.New(listManagementMenuItems(), delegate, 80, 10)
 	l.Title = "📋 List Management"
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(false)
@@ -77,6 +76,21 @@ func NewListManagementModel(favoritePath string) ListManagementModel {
 	}
 }
 
+// listManagementMenuItems returns the items of the List Management menu.
+func listManagementMenuItems() []list.Item {
+	return []list.Item{
+		components.NewMenuItem("Create New List", "Create a new favorites list", "1"),
+		components.NewMenuItem("Delete List", "Delete an existing list", "2"),
+		components.NewMenuItem("Edit List Name", "Rename an existing list", "3"),
+		components.NewMenuItem("Show All Lists", "Display all favorite lists", "4"),
+		components.NewMenuItem("Liked Songs", "Browse, search and export bookmarked songs", "5"),
+		components.NewMenuItem("Import Playlist", "Add stations from an M3U, PLS, XSPF or OPML file", "6"),
+		components.NewMenuItem("Export List", "Save a list as a playlist for other players", "7"),
+		components.NewMenuItem("Library Cleanup", "Find duplicates, merge lists and move stations", "8"),
+		components.NewMenuItem("Recent Changes", "Undo or redo recent changes to your library", "9"),
+	}
+}
+
 // Init initializes the list management screen
 func (m ListManagementModel) Init() tea.Cmd {
 	return m.loadLists()
@@ -112,7 +126,7 @@ func (m ListManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		// Ensure enough height for menu items (4 items + title + help)
+		// Ensure enough height for menu items (9 items + title + help)
 		h := msg.Height - 4
 		if h < 8 {
 			h = 8
@@ -120,11 +134,14 @@ func (m ListManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.listModel.SetSize(msg.Width-4, h)
 		return m, nil
 
+	case libraryChangedMsg:
+		return m, m.loadLists()
+
 	case listManagementListsLoadedMsg:
 		m.lists = msg.lists
 		// Populate list model based on current state
 		switch m.state {
-		case listManagementSelectListToDelete, listManagementSelectListToEdit:
+		case listManagementSelectListToDelete, listManagementSelectListToEdit, listManagementSelectListToExport:
 			items := make([]list.Item, len(m.lists))
 			for i, listName := range m.lists {
 				items[i] = components.NewMenuItem(listName, "", fmt.Sprintf("%d", i+1))
@@ -133,13 +150,7 @@ func (m ListManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.listModel.Select(0)
 		case listManagementMenu:
 			// Reset to main menu items
-			items := []list.Item{
-				components.NewMenuItem("Create New List", "Create a new favorites list", "1"),
-				components.NewMenuItem("Delete List", "Delete an existing list", "2"),
-				components.NewMenuItem("Edit List Name", "Rename an existing list", "3"),
-				components.NewMenuItem("Show All Lists", "Display all favorite lists", "4"),
-			}
-			m.listModel.SetItems(items)
+			m.listModel.SetItems(listManagementMenuItems())
 			m.listModel.Select(0)
 		}
 		return m, nil
@@ -148,9 +159,12 @@ func (m ListManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.message = msg.message
 		m.messageTime = 180 // 3 seconds (at ~60fps)
 		m.state = listManagementMenu
+		m.importing = false
+		m.textInput.CharLimit = 50
 		return m, m.loadLists()
 
 	case listManagementOperationErrorMsg:
+		m.importing = false
 		m.err = msg.err
 		m.message = msg.err.Error()
 		m.messageTime = 180 // 3 seconds (at ~60fps)
@@ -167,9 +181,9 @@ func (m ListManagementModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.listModel, cmd = m.listModel.Update(msg)
 	case listManagementSelectListToDelete:
 		m.listModel, cmd = m.listModel.Update(msg)
-	case listManagementSelectListToEdit:
+	case listManagementSelectListToEdit, listManagementSelectListToExport, listManagementSelectExportFormat:
 		m.listModel, cmd = m.listModel.Update(msg)
-	case listManagementCreate, listManagementDelete, listManagementEdit, listManagementEnterNewName:
+	case listManagementCreate, listManagementDelete, listManagementEdit, listManagementEnterNewName, listManagementImport:
 		m.textInput, cmd = m.textInput.Update(msg)
 	}
 	return m, cmd
@@ -196,6 +210,12 @@ func (m ListManagementModel) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		return m.handleConfirmDeleteInput(msg)
 	case listManagementEnterNewName:
 		return m.handleEnterNewNameInput(msg)
+	case listManagementImport:
+		return m.handleImportInput(msg)
+	case listManagementSelectListToExport:
+		return m.handleSelectListToExportInput(msg)
+	case listManagementSelectExportFormat:
+		return m.handleSelectExportFormatInput(msg)
 	}
 	return m, nil
 }
@@ -225,6 +245,21 @@ func (m ListManagementModel) handleMenuInput(msg tea.KeyMsg) (tea.Model, tea.Cmd
 	case "4":
 		// Show all lists
 		return m.executeMenuAction(3)
+	case "5":
+		// Liked songs
+		return m.executeMenuAction(4)
+	case "6":
+		// Import playlist
+		return m.executeMenuAction(5)
+	case "7":
+		// Export list
+		return m.executeMenuAction(6)
+	case "8":
+		// Library cleanup
+		return m.executeMenuAction(7)
+	case "9":
+		// Recent changes
+		return m.executeMenuAction(8)
 	}
 
 	var cmd tea.Cmd
@@ -261,10 +296,135 @@ func (m ListManagementModel) executeMenuAction(index int) (tea.Model, tea.Cmd) {
 	case 3: // Show all lists
 		m.state = listManagementShowAll
 		return m, m.loadLists()
+	case 4: // Liked songs
+		return m, func() tea.Msg {
+			return navigateMsg{screen: screenLikedSongs}
+		}
+	case 5: // Import playlist
+		m.state = listManagementImport
+		m.message = ""
+		m.textInput.Reset()
+		m.textInput.Placeholder = "Path to an .m3u, .pls, .xspf or .opml file"
+		m.textInput.CharLimit = 4096
+		m.textInput.Focus()
+		return m, textinput.Blink
+	case 6: // Export list
+		if len(m.lists) == 0 {
+			m.message = "No lists available to export"
+			m.messageTime = 150
+			return m, nil
+		}
+		m.state = listManagementSelectListToExport
+		return m, m.loadLists()
+	case 7: // Library cleanup
+		return m, func() tea.Msg {
+			return navigateMsg{screen: screenCleanup}
+		}
+	case 8: // Recent changes
+		return m, func() tea.Msg {
+			return navigateMsg{screen: screenChanges}
+		}
 	}
 	return m, nil
 }
 
+// handleImportInput handles the playlist path prompt
+func (m ListManagementModel) handleImportInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.importing {
+		// Wait for the Radio Browser lookups to finish
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc":
+		m.state = listManagementMenu
+		m.textInput.Blur()
+		m.textInput.CharLimit = 50
+		m.message = ""
+		return m, nil
+	case "enter":
+		path := strings.TrimSpace(m.textInput.Value())
+		if path == "" {
+			m.message = "Path cannot be empty"
+			m.messageTime = 150
+			return m, nil
+		}
+		m.importing = true
+		m.message = "Looking up stations on Radio Browser..."
+		m.messageTime = 0
+		return m, m.importPlaylist(path)
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	return m, cmd
+}
+
+// handleSelectListToExportInput handles selection of the list to export
+func (m ListManagementModel) handleSelectListToExportInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "m":
+		m.state = listManagementMenu
+		return m, m.loadLists()
+	case "q":
+		return m, tea.Quit
+	case "enter", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if key := msg.String(); key != "enter" {
+			idx := int(key[0] - '1')
+			if idx >= len(m.listModel.Items()) {
+				return m, nil
+			}
+			m.listModel.Select(idx)
+		}
+		menuItem, ok := m.listModel.SelectedItem().(components.MenuItem)
+		if !ok {
+			return m, nil
+		}
+		m.selectedList = menuItem.Title()
+		m.state = listManagementSelectExportFormat
+		m.listModel.SetItems(exportFormatItems())
+		m.listModel.Select(0)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.listModel, cmd = m.listModel.Update(msg)
+	return m, cmd
+}
+
+// exportFormatItems returns the playlist formats offered for export.
+func exportFormatItems() []list.Item {
+	return []list.Item{
+		components.NewMenuItem("M3U", "VLC, mpv, foobar2000 and most players", "1"),
+		components.NewMenuItem("PLS", "Winamp, Audacious and internet radio players", "2"),
+		components.NewMenuItem("XSPF", "VLC, Rhythmbox and other XML playlist players", "3"),
+		components.NewMenuItem("OPML", "Radio directories and podcast apps", "4"),
+	}
+}
+
+// handleSelectExportFormatInput handles the choice of playlist format
+func (m ListManagementModel) handleSelectExportFormatInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.state = listManagementSelectListToExport
+		return m, m.loadLists()
+	case "q":
+		return m, tea.Quit
+	case "enter", "1", "2", "3", "4":
+		idx := m.listModel.Index()
+		if key := msg.String(); key != "enter" {
+			idx = int(key[0] - '1')
+		}
+		if idx < 0 || idx >= len(playlist.Formats) {
+			return m, nil
+		}
+		return m, m.exportList(m.selectedList, playlist.Formats[idx])
+	}
+
+	var cmd tea.Cmd
+	m.listModel, cmd = m.listModel.Update(msg)
+	return m, cmd
+}
+
 // handleCreateInput handles input during list creation
 func (m ListManagementModel) handleCreateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -282,6 +442,11 @@ func (m ListManagementModel) handleCreateInput(msg tea.KeyMsg) (tea.Model, tea.C
 
 		// Replace spaces with hyphens
 		name = strings.ReplaceAll(name, " ", "-")
+		if err := storage.ValidateListName(name); err != nil {
+			m.message = "Invalid list name (use Folder/Name to put a list in a folder)"
+			m.messageTime = 150
+			return m, nil
+		}
 
 		// Check if list exists
 		for _, existing := range m.lists {
@@ -562,6 +727,11 @@ func (m ListManagementModel) handleEnterNewNameInput(msg tea.KeyMsg) (tea.Model,
 
 		// Replace spaces with hyphens
 		newName = strings.ReplaceAll(newName, " ", "-")
+		if err := storage.ValidateListName(newName); err != nil {
+			m.message = "Invalid list name (use Folder/Name to put a list in a folder)"
+			m.messageTime = 150
+			return m, nil
+		}
 
 		// Check if new name already exists
 		for _, existing := range m.lists {
@@ -591,13 +761,58 @@ func (m ListManagementModel) handleShowAllInput(msg tea.KeyMsg) (tea.Model, tea.
 	return m, nil
 }
 
-// createList creates a new list file
-func (m ListManagementModel) createList(name string) tea.Cmd {
+// importPlaylist imports the playlist at path into a list named after it,
+// matching streams to Radio Browser stations
+func (m ListManagementModel) importPlaylist(path string) tea.Cmd {
+	favoritePath := m.favoritePath
 	return func() tea.Msg {
-		path := filepath.Join(m.favoritePath, name+".json")
+		if path == "~" || strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, strings.TrimPrefix(path[1:], "/"))
+			}
+		}
+		store := storage.NewStorage(favoritePath)
+		r, err := playlist.ImportFile(context.Background(), store, api.NewClient(), path, "", "")
+		if err != nil {
+			return listManagementOperationErrorMsg{err}
+		}
+		message := fmt.Sprintf("✓ Imported %d station(s) into '%s' (%d by URL, %d by name, %d custom",
+			r.Added, r.List, r.ByURL, r.ByName, r.Custom)
+		if r.Duplicates > 0 {
+			message += fmt.Sprintf(", %d already there", r.Duplicates)
+		}
+		message += ")"
+		if r.LookupErr != nil {
+			message += " - Radio Browser lookup failed, kept the rest as custom stations"
+		}
+		return listManagementOperationSuccessMsg{message: message}
+	}
+}
 
-		// Create empty JSON array
-		if err := os.WriteFile(path, []byte("[]"), 0644); err != nil {
+// exportList writes a list to ~/tera-<list>.<format>
+func (m ListManagementModel) exportList(name, format string) tea.Cmd {
+	favoritePath := m.favoritePath
+	return func() tea.Msg {
+		path, err := playlist.DefaultExportPath(name, format)
+		if err != nil {
+			return listManagementOperationErrorMsg{err}
+		}
+		n, err := playlist.ExportList(context.Background(), storage.NewStorage(favoritePath), name, path, format)
+		if err != nil {
+			return listManagementOperationErrorMsg{err}
+		}
+		return listManagementOperationSuccessMsg{
+			message: fmt.Sprintf("✓ Exported %d station(s) to %s", n, path),
+		}
+	}
+}
+
+// createList creates a new list file, in its folder for names like
+// "Jazz/Smooth"
+func (m ListManagementModel) createList(name string) tea.Cmd {
+	return func() tea.Msg {
+		store := storage.NewStorage(m.favoritePath)
+		if err := store.CreateList(context.Background(), name); err != nil {
 			return listManagementOperationErrorMsg{err}
 		}
 
@@ -610,9 +825,8 @@ func (m ListManagementModel) createList(name string) tea.Cmd {
 // deleteList deletes a list file
 func (m ListManagementModel) deleteList(name string) tea.Cmd {
 	return func() tea.Msg {
-		path := filepath.Join(m.favoritePath, name+".json")
-
-		if err := os.Remove(path); err != nil {
+		store := storage.NewStorage(m.favoritePath)
+		if err := store.DeleteList(context.Background(), name); err != nil {
 			return listManagementOperationErrorMsg{err}
 		}
 
@@ -622,13 +836,11 @@ func (m ListManagementModel) deleteList(name string) tea.Cmd {
 	}
 }
 
-// renameList renames a list file
+// renameList renames a list file; a name with a different folder moves it
 func (m ListManagementModel) renameList(oldName, newName string) tea.Cmd {
 	return func() tea.Msg {
-		oldPath := filepath.Join(m.favoritePath, oldName+".json")
-		newPath := filepath.Join(m.favoritePath, newName+".json")
-
-		if err := os.Rename(oldPath, newPath); err != nil {
+		store := storage.NewStorage(m.favoritePath)
+		if err := store.RenameList(context.Background(), oldName, newName); err != nil {
 			return listManagementOperationErrorMsg{err}
 		}
 
@@ -659,6 +871,12 @@ func (m ListManagementModel) View() string {
 		return m.viewConfirmDelete()
 	case listManagementEnterNewName:
 		return m.viewEnterNewName()
+	case listManagementImport:
+		return m.viewImport()
+	case listManagementSelectListToExport:
+		return m.viewSelectListToExport()
+	case listManagementSelectExportFormat:
+		return m.viewSelectExportFormat()
 	}
 	return ""
 }
@@ -681,7 +899,7 @@ func (m ListManagementModel) viewMenu() string {
 
 	return m.renderPageWithBottomHelp(PageLayout{
 		Content: content.String(),
-		Help:    "↑↓/jk: Navigate • Enter: Select • 1-4: Quick select • Esc: Back • Ctrl+C: Quit",
+		Help:    "↑↓/jk: Navigate • Enter: Select • 1-9: Quick select • Esc: Back • Ctrl+C: Quit",
 	}, m.height)
 }
 
@@ -698,6 +916,8 @@ func (m ListManagementModel) viewCreate() string {
 		content.WriteString("\n")
 	}
 
+	content.WriteString(infoStyle().Render("Use Folder/Name to put the list in a folder."))
+	content.WriteString("\n\n")
 	content.WriteString(m.textInput.View())
 
 	if m.message != "" {
@@ -885,6 +1105,65 @@ func (m ListManagementModel) viewShowAll() string {
 	}, m.height)
 }
 
+// viewImport renders the playlist import prompt
+func (m ListManagementModel) viewImport() string {
+	var content strings.Builder
+
+	content.WriteString("Streams are matched to Radio Browser stations by URL, then by name.\n")
+	content.WriteString("Anything else is kept as a custom station. The list is named after the playlist.\n\n")
+	content.WriteString(m.textInput.View())
+
+	if m.message != "" {
+		style := errorStyle()
+		if m.importing {
+			style = infoStyle()
+		}
+		content.WriteString("\n\n")
+		content.WriteString(style.Render(m.message))
+	}
+
+	return m.renderPage(PageLayout{
+		Title:   "Import Playlist",
+		Content: content.String(),
+		Help:    "Enter: Import • Esc: Back • Ctrl+C: Quit",
+	})
+}
+
+// viewSelectListToExport renders the list selection view for export
+func (m ListManagementModel) viewSelectListToExport() string {
+	var content strings.Builder
+
+	content.WriteString(subtitleStyle().Render("Select a list to export:"))
+	content.WriteString("\n\n")
+	content.WriteString(m.listModel.View())
+
+	maxNum := min(len(m.lists), 9)
+
+	return m.renderPageWithBottomHelp(PageLayout{
+		Content: content.String(),
+		Help:    fmt.Sprintf("↑↓/jk: Navigate • Enter: Select • 1-%d: Quick select • Esc: Back • Ctrl+C: Quit", maxNum),
+	}, m.height)
+}
+
+// viewSelectExportFormat renders the playlist format choice
+func (m ListManagementModel) viewSelectExportFormat() string {
+	var content strings.Builder
+
+	if m.message != "" {
+		content.WriteString(errorStyle().Render(m.message))
+		content.WriteString("\n\n")
+	}
+
+	content.WriteString(m.listModel.View())
+
+	return m.renderPageWithBottomHelp(PageLayout{
+		Title:    "Export List",
+		Subtitle: fmt.Sprintf("Exporting '%s' to your home folder", m.selectedList),
+		Content:  content.String(),
+		Help:     "↑↓/jk: Navigate • Enter: Export • 1-4: Quick select • Esc: Back • Ctrl+C: Quit",
+	}, m.height)
+}
+
 // renderPage wraps RenderPage injecting the active now-playing bar.
 func (m ListManagementModel) renderPage(layout PageLayout) string {
 	layout.NowPlaying = m.nowPlayingBar
@@ -910,3 +1189,12 @@ type listManagementOperationSuccessMsg struct {
 type listManagementOperationErrorMsg struct {
 	err error
 }
+
+// capturesKeys is true while a list name or import path is typed.
+func (m ListManagementModel) capturesKeys() bool {
+	switch m.state {
+	case listManagementCreate, listManagementEnterNewName, listManagementImport:
+		return true
+	}
+	return false
+}
@@ -1,9 +1,12 @@
 package ui
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/shinokada/tera/v3/internal/ui/components"
 )
 
@@ -18,9 +21,9 @@ func TestListManagementModel_Creation(t *testing.T) {
 		t.Errorf("Expected favoritePath /tmp/test, got %s", model.favoritePath)
 	}
 
-	// Verify the list has 4 menu items
-	if len(model.listModel.Items()) != 4 {
-		t.Errorf("Expected 4 menu items, got %d", len(model.listModel.Items()))
+	// Verify the list has 7 menu items
+	if len(model.listModel.Items()) != 9 {
+		t.Errorf("Expected 9 menu items, got %d", len(model.listModel.Items()))
 	}
 
 	// Verify menu items are MenuItem type with correct shortcuts
@@ -32,6 +35,11 @@ func TestListManagementModel_Creation(t *testing.T) {
 		{"Delete List", "2"},
 		{"Edit List Name", "3"},
 		{"Show All Lists", "4"},
+		{"Liked Songs", "5"},
+		{"Import Playlist", "6"},
+		{"Export List", "7"},
+		{"Library Cleanup", "8"},
+		{"Recent Changes", "9"},
 	}
 
 	for i, item := range model.listModel.Items() {
@@ -64,6 +72,9 @@ func TestListManagementModel_States(t *testing.T) {
 		{"ShowAll", listManagementShowAll, "All Favorite Lists"},
 		{"ConfirmDelete", listManagementConfirmDelete, "Confirm Deletion"},
 		{"EnterNewName", listManagementEnterNewName, "Edit List Name"},
+		{"Import", listManagementImport, "Import Playlist"},
+		{"SelectListToExport", listManagementSelectListToExport, "Select a list to export"},
+		{"SelectExportFormat", listManagementSelectExportFormat, "Export List"},
 	}
 
 	for _, tt := range tests {
@@ -88,7 +99,7 @@ func TestListManagementModel_MenuFooter(t *testing.T) {
 	model.state = listManagementMenu
 	view := model.View()
 
-	expectedFooter := "↑↓/jk: Navigate • Enter: Select • 1-4: Quick select • Esc: Back • Ctrl+C: Quit"
+	expectedFooter := "↑↓/jk: Navigate • Enter: Select • 1-9: Quick select • Esc: Back • Ctrl+C: Quit"
 	if !strings.Contains(view, expectedFooter) {
 		t.Errorf("Expected menu footer to contain %q in:\n%s", expectedFooter, view)
 	}
@@ -172,6 +183,9 @@ func TestListManagementModel_MenuListDisplay(t *testing.T) {
 		"2. Delete List",
 		"3. Edit List Name",
 		"4. Show All Lists",
+		"5. Liked Songs",
+		"6. Import Playlist",
+		"7. Export List",
 	}
 
 	for _, item := range expectedItems {
@@ -218,3 +232,52 @@ func TestListManagementModel_SelectListToDeleteFooter(t *testing.T) {
 		t.Errorf("Expected selectListToDelete footer to contain Esc: Back • Ctrl+C: Quit in:\n%s", view)
 	}
 }
+
+func TestListManagementModel_ExportList(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+
+	favPath := filepath.Join(tmp, "favorites")
+	if err := os.MkdirAll(favPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `[{"stationuuid":"a","name":"Jazz FM","url_resolved":"http://jazz.example"}]`
+	if err := os.WriteFile(filepath.Join(favPath, "Jazz.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	model := NewListManagementModel(favPath)
+	updated, _ := model.Update(model.loadLists()())
+	model = updated.(ListManagementModel)
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("7")})
+	model = updated.(ListManagementModel)
+	if model.state != listManagementSelectListToExport {
+		t.Fatalf("expected the export list selection, got state %v", model.state)
+	}
+	updated, _ = model.Update(cmd())
+	model = updated.(ListManagementModel)
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	model = updated.(ListManagementModel)
+	if model.state != listManagementSelectExportFormat || model.selectedList != "Jazz" {
+		t.Fatalf("expected the format choice for Jazz, got state %v, list %q", model.state, model.selectedList)
+	}
+
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	msg, ok := cmd().(listManagementOperationSuccessMsg)
+	if !ok {
+		t.Fatalf("expected a success message, got %#v", cmd())
+	}
+	if !strings.Contains(msg.message, "Exported 1 station(s)") {
+		t.Errorf("unexpected message %q", msg.message)
+	}
+	exported, err := os.ReadFile(filepath.Join(tmp, "tera-Jazz.pls"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(exported), "File1=http://jazz.example\nTitle1=Jazz FM\n") {
+		t.Errorf("unexpected export:\n%s", exported)
+	}
+}
@@ -75,13 +75,13 @@ type LuckyModel struct {
 	lastSearchKeyword string        // Keyword used for current shuffle session
 	blocklistManager  *blocklist.Manager
 	metadataManager   *storage.MetadataManager // Track play statistics
-	lastBlockTime     time.Time
 	// Star rating fields
 	ratingsManager *storage.RatingsManager
 	starRenderer   *components.StarRenderer
 	ratingMode     bool // true when waiting for 1-5 input after pressing R
 	// Tag fields
 	tagsManager *storage.TagsManager
+	likedSongs  *storage.LikedSongsManager // bookmarks for the "l" key
 	tagRenderer *components.TagRenderer
 	tagInput    components.TagInput
 	manageTags  components.ManageTags
@@ -387,6 +387,15 @@ func (m LuckyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case adSkipMsg:
+		// A skip rule matched: move on like the shuffle timer would
+		if msg.player == m.player && m.shuffleManager != nil && m.state == luckyStateShufflePlaying {
+			return m, func() tea.Msg {
+				return shuffleAdvanceMsg{}
+			}
+		}
+		return m, nil
+
 	case shuffleAdvanceMsg:
 		// Auto-advance to next shuffle station
 		if m.shuffleManager != nil && m.state == luckyStateShufflePlaying {
@@ -429,8 +438,6 @@ func (m LuckyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tickEverySecond()
 
 	case stationBlockedMsg:
-		m.lastBlockTime = time.Now()
-
 		if msg.success {
 			// Stop playback
 			if m.player != nil {
@@ -439,7 +446,7 @@ func (m LuckyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.ratingMode = false // Clear rating mode on async state transition
 
 			// Show message
-			m.saveMessage = msg.message + " (press 'u' within 5s to undo)"
+			m.saveMessage = msg.message + " (press 'u' to undo)"
 			m.saveMessageTime = messageDisplayMedium
 
 			// If shuffle is active, advance to next station
@@ -462,16 +469,6 @@ func (m LuckyModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case undoBlockSuccessMsg:
-		m.saveMessage = "✓ Block undone"
-		m.saveMessageTime = messageDisplayMedium
-		return m, nil
-
-	case undoBlockFailedMsg:
-		m.saveMessage = "No recent block to undo"
-		m.saveMessageTime = messageDisplayMedium
-		return m, nil
-
 	case components.TagSubmittedMsg:
 		if m.state == luckyStateManageTags {
 			var cmd tea.Cmd
@@ -845,12 +842,6 @@ func (m LuckyModel) updatePlaying(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, m.blockStation()
 		}
 		return m, nil
-	case "u":
-		// Undo last block (within 5 seconds)
-		if time.Since(m.lastBlockTime) < 5*time.Second {
-			return m, m.undoLastBlock()
-		}
-		return m, nil
 	case "esc":
 		// Phase 5: Confirm before stopping
 		if m.playOptsCfg.ConfirmStop {
@@ -932,6 +923,12 @@ func (m LuckyModel) updatePlaying(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.saveMessageTime = messageDisplayShort
 		}
 		return m, nil
+	case "l":
+		if msg := likeCurrentSong(m.likedSongs, m.player, m.selectedStation); msg != "" {
+			m.saveMessage = msg
+			m.saveMessageTime = messageDisplayShort
+		}
+		return m, nil
 	case "r":
 		// Enter rating mode
 		if m.selectedStation != nil && m.ratingsManager != nil {
@@ -1496,16 +1493,26 @@ func (m LuckyModel) viewPlaying() string {
 	// Playback status with proper spacing
 	content.WriteString("\n")
 	if m.player.IsPlaying() {
-		if track := m.player.GetCachedTrack(); IsValidTrackMetadata(track, m.selectedStation.Name) {
+		if status := networkStatusText(m.player); status != "" {
+			content.WriteString(highlightStyle().Render(status))
+		} else if track := m.player.GetCachedTrack(); IsValidTrackMetadata(track, m.selectedStation.Name) {
 			content.WriteString(successStyle().Render("▶ Now Playing:"))
 			content.WriteString(" ")
-			content.WriteString(infoStyle().Render(track))
+			content.WriteString(infoStyle().Render(formatTrack(m.player, track)))
 		} else {
 			content.WriteString(successStyle().Render("▶ Playing..."))
 		}
 	} else {
 		content.WriteString(infoStyle().Render("⏸ Stopped"))
 	}
+	if status := adStatusText(m.player); status != "" {
+		content.WriteString("\n")
+		content.WriteString(infoStyle().Render(status))
+	}
+	if status := dataUsageText(m.player); status != "" {
+		content.WriteString("\n")
+		content.WriteString(highlightStyle().Render(status))
+	}
 
 	// Tag display
 	if m.tagsManager != nil && m.tagRenderer != nil {
@@ -1790,12 +1797,6 @@ func (m LuckyModel) updateShufflePlaying(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, m.blockStation()
 		}
 		return m, nil
-	case "u":
-		// Undo last block (within 5 seconds)
-		if time.Since(m.lastBlockTime) < 5*time.Second {
-			return m, m.undoLastBlock()
-		}
-		return m, nil
 	case "[":
 		// Previous shuffle station (from history)
 		if m.shuffleManager == nil {
@@ -1851,6 +1852,12 @@ func (m LuckyModel) updateShufflePlaying(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.saveMessageTime = messageDisplayShort
 		}
 		return m, nil
+	case "l":
+		if msg := likeCurrentSong(m.likedSongs, m.player, m.selectedStation); msg != "" {
+			m.saveMessage = msg
+			m.saveMessageTime = messageDisplayShort
+		}
+		return m, nil
 	case "r":
 		// Enter rating mode
 		if m.selectedStation != nil && m.ratingsManager != nil {
@@ -1971,16 +1978,26 @@ func (m LuckyModel) viewShufflePlaying() string {
 	content.WriteString("\n")
 	if m.player.IsPlaying() {
 		// Use the cached track to avoid a blocking IPC socket call in the render path.
-		if track := m.player.GetCachedTrack(); IsValidTrackMetadata(track, m.selectedStation.Name) {
+		if status := networkStatusText(m.player); status != "" {
+			content.WriteString(highlightStyle().Render(status))
+		} else if track := m.player.GetCachedTrack(); IsValidTrackMetadata(track, m.selectedStation.Name) {
 			content.WriteString(successStyle().Render("▶ Now Playing:"))
 			content.WriteString(" ")
-			content.WriteString(infoStyle().Render(track))
+			content.WriteString(infoStyle().Render(formatTrack(m.player, track)))
 		} else {
 			content.WriteString(successStyle().Render("▶ Playing..."))
 		}
 	} else {
 		content.WriteString(infoStyle().Render("⏸ Stopped"))
 	}
+	if status := adStatusText(m.player); status != "" {
+		content.WriteString("\n")
+		content.WriteString(infoStyle().Render(status))
+	}
+	if status := dataUsageText(m.player); status != "" {
+		content.WriteString("\n")
+		content.WriteString(highlightStyle().Render(status))
+	}
 
 	// Tag display (mirrors viewPlaying)
 	if m.tagsManager != nil && m.tagRenderer != nil {
@@ -2127,25 +2144,6 @@ func (m LuckyModel) blockStation() tea.Cmd {
 	}
 }
 
-// undoLastBlock undoes the last block operation
-func (m LuckyModel) undoLastBlock() tea.Cmd {
-	return func() tea.Msg {
-		if m.blocklistManager == nil {
-			return undoBlockFailedMsg{}
-		}
-		ctx := context.Background()
-		undone, err := m.blocklistManager.UndoLastBlock(ctx)
-		if err != nil {
-			return luckySearchErrorMsg{err}
-		}
-
-		if undone {
-			return undoBlockSuccessMsg{}
-		}
-		return undoBlockFailedMsg{}
-	}
-}
-
 // reloadSearchHistory reloads history from disk
 func (m *LuckyModel) reloadSearchHistory() {
 	store := storage.NewStorage(m.favoritePath)
@@ -2238,3 +2236,18 @@ func (m LuckyModel) renderPageWithBottomHelp(layout PageLayout, height int) stri
 	}
 	return RenderPageWithBottomHelp(layout, height)
 }
+
+// capturesKeys is true while the keyword field has focus and in the list
+// name and tag dialogs.
+func (m LuckyModel) capturesKeys() bool {
+	if m.helpModel.IsVisible() {
+		return true
+	}
+	switch m.state {
+	case luckyStateInput:
+		return m.inputMode
+	case luckyStateNewListInput, luckyStateTagInput, luckyStateManageTags:
+		return true
+	}
+	return false
+}
@@ -45,11 +45,22 @@ type stationBlockedMsg struct {
 	success     bool
 }
 
-// undoBlockSuccessMsg is sent when a block is successfully undone
-type undoBlockSuccessMsg struct{}
+// journalReplayedMsg is sent when u or ctrl+r has undone or redone changes.
+// notice says what happened.
+type journalReplayedMsg struct {
+	notice string
+}
+
+// libraryChangedMsg is sent to the current screen after an undo or redo, or
+// after another process changed the data files, so it can reload the lists,
+// tags, ratings or blocks it shows.
+type libraryChangedMsg struct{}
 
-// undoBlockFailedMsg is sent when a block undo operation fails
-type undoBlockFailedMsg struct{}
+// dataFilesChangedMsg is sent when data files change on disk behind this
+// process's back. paths are the files that changed.
+type dataFilesChangedMsg struct {
+	paths []string
+}
 
 // handoffPlaybackMsg is sent by a play screen when ContinueOnNavigate is on
 // and the user navigates away. App takes ownership of the player and station.
@@ -84,6 +84,7 @@ type MostPlayedModel struct {
 	ratingMode     bool // true when waiting for 1-5 input after pressing R
 	// Tag fields
 	tagsManager *storage.TagsManager
+	likedSongs  *storage.LikedSongsManager // bookmarks for the "l" key
 	tagRenderer *components.TagRenderer
 	tagInput    components.TagInput
 	// For saving to list
@@ -106,7 +107,7 @@ type mostPlayedStationItem struct {
 
 func (i mostPlayedStationItem) FilterValue() string {
 	if i.station.Name != "" {
-		return i.station.Name
+		return i.station.SearchText()
 	}
 	return i.station.StationUUID
 }
@@ -191,6 +192,8 @@ func createMostPlayedHelp() []components.HelpSection {
 				{Key: "Enter", Description: "Play"},
 				{Key: "s", Description: "Sort"},
 				{Key: "f", Description: "Add to favorites"},
+				{Key: "h", Description: "Listening timeline"},
+				{Key: "i", Description: "Listening stats"},
 				{Key: "?", Description: "Help"},
 				{Key: "Esc/m", Description: "Back"},
 			},
@@ -433,6 +436,11 @@ func (m MostPlayedModel) handleListInput(msg tea.KeyMsg) (MostPlayedModel, tea.C
 			}
 		}
 		return m, nil
+
+	case "h":
+		return m, func() tea.Msg { return navigateMsg{screen: screenTimeline} }
+	case "i":
+		return m, func() tea.Msg { return navigateMsg{screen: screenStats} }
 	}
 
 	// Pass to list model for navigation
@@ -564,6 +572,14 @@ func (m MostPlayedModel) handlePlayingInput(msg tea.KeyMsg) (MostPlayedModel, te
 			return m, tickEverySecond()
 		}
 
+	case "l":
+		if msg := likeCurrentSong(m.likedSongs, m.player, m.selectedStation); msg != "" {
+			m.saveMessage = msg
+			m.saveMessageSuccess = strings.HasPrefix(msg, "✓")
+			m.saveMessageTime = 3
+			return m, tickEverySecond()
+		}
+
 	case "r":
 		// Enter rating mode
 		if m.selectedStation != nil && m.ratingsManager != nil {
@@ -836,7 +852,7 @@ func (m MostPlayedModel) viewList() string {
 	return m.renderPageWithBottomHelp(PageLayout{
 		Title:   "📊 Most Played Stations",
 		Content: content.String(),
-		Help:    "↑↓/jk: Navigate • g/G: Top/End • Enter: Play • s: Sort • f: Fav • ?: Help • Esc: Back",
+		Help:    "↑↓/jk: Navigate • g/G: Top/End • Enter: Play • s: Sort • f: Fav • h: Timeline • i: Stats • ?: Help • Esc: Back",
 	}, m.height)
 }
 
@@ -879,10 +895,12 @@ func (m MostPlayedModel) viewPlaying() string {
 		} else {
 			// Use cached track to avoid IPC call in the render path
 			track := m.player.GetCachedTrack()
-			if IsValidTrackMetadata(track, m.selectedStation.Name) {
+			if status := networkStatusText(m.player); status != "" {
+				content.WriteString(highlightStyle().Render(status))
+			} else if IsValidTrackMetadata(track, m.selectedStation.Name) {
 				content.WriteString(successStyle().Render("▶ Now Playing:"))
 				content.WriteString(" ")
-				content.WriteString(infoStyle().Render(track))
+				content.WriteString(infoStyle().Render(formatTrack(m.player, track)))
 			} else {
 				content.WriteString(successStyle().Render("▶ Playing..."))
 			}
@@ -1017,3 +1035,11 @@ func (m MostPlayedModel) renderPageWithBottomHelp(layout PageLayout, height int)
 	}
 	return RenderPageWithBottomHelp(layout, height)
 }
+
+// capturesKeys is true in the tag dialog and while filtering stations.
+func (m MostPlayedModel) capturesKeys() bool {
+	if m.helpModel.IsVisible() {
+		return true
+	}
+	return m.state == mostPlayedStateTagInput || m.stationListModel.SettingFilter()
+}
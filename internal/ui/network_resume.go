@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/player"
+)
+
+// resumeConfigMsg is sent by Connection Settings when the network resume
+// timeout changes.
+type resumeConfigMsg struct {
+	cfg config.ResumeConfig
+}
+
+// networkStatusMsg is sent when a player starts or stops waiting for the
+// network, so the now-playing bar can be refreshed.
+type networkStatusMsg struct {
+	player  *player.MPVPlayer
+	waiting bool
+}
+
+// networkStatusText describes a player that lost its stream to a network
+// drop, or returns "" when p is playing normally.
+func networkStatusText(p *player.MPVPlayer) string {
+	if p == nil {
+		return ""
+	}
+	remaining, waiting := p.WaitingForNetwork()
+	if !waiting {
+		return ""
+	}
+	// A clock time stays correct between redraws, unlike a countdown.
+	return fmt.Sprintf("📡 Waiting for network… (gives up at %s)", time.Now().Add(remaining).Format("15:04"))
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/player"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+func TestNetworkStatusTextWhenPlayingNormally(t *testing.T) {
+	if got := networkStatusText(nil); got != "" {
+		t.Errorf("nil player: got %q", got)
+	}
+	if got := networkStatusText(player.NewMPVPlayer()); got != "" {
+		t.Errorf("idle player: got %q", got)
+	}
+}
+
+func TestConnectionSettings_SetNetworkResume(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+
+	m := NewConnectionSettingsModel()
+	if !m.resume.Enabled || m.resume.TimeoutMinutes != 10 {
+		t.Fatalf("expected the default 10 minute resume, got %+v", m.resume)
+	}
+
+	// 5: Set Network Resume, then 5: 30 minutes
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")})
+	m = updated.(ConnectionSettingsModel)
+	if m.state != connectionSettingsResume {
+		t.Fatalf("expected the resume screen, got state %v", m.state)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")})
+	m = updated.(ConnectionSettingsModel)
+
+	if m.state != connectionSettingsMenu || m.resume.TimeoutMinutes != 30 {
+		t.Errorf("expected 30 minutes back on the menu, got %+v in state %v", m.resume, m.state)
+	}
+	if cmd == nil {
+		t.Fatal("expected the App to be told about the new timeout")
+	}
+	if msg, ok := cmd().(resumeConfigMsg); !ok || msg.cfg.Timeout().Minutes() != 30 {
+		t.Errorf("expected resumeConfigMsg for 30 minutes, got %#v", msg)
+	}
+
+	saved, err := storage.LoadResumeConfigFromUnified()
+	if err != nil || saved.TimeoutMinutes != 30 {
+		t.Errorf("expected 30 minutes saved, got %+v (%v)", saved, err)
+	}
+
+	// 1: Off
+	m.state = connectionSettingsResume
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	m = updated.(ConnectionSettingsModel)
+	if m.resume.Enabled {
+		t.Error("expected network resume to be off")
+	}
+}
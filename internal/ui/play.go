@@ -4,8 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
-	"sort"
 	"strings"
 
 	"time"
@@ -18,6 +16,7 @@ import (
 	"github.com/shinokada/tera/v3/internal/config"
 	"github.com/shinokada/tera/v3/internal/player"
 	"github.com/shinokada/tera/v3/internal/storage"
+	"github.com/shinokada/tera/v3/internal/subscription"
 	"github.com/shinokada/tera/v3/internal/ui/components"
 )
 
@@ -33,7 +32,9 @@ const (
 	playStateTagInput
 	playStateManageTags
 	playStateSleepTimer
-	playStateConfirmStop // Phase 5: confirm stop prompt
+	playStateConfirmStop    // Phase 5: confirm stop prompt
+	playStateStationOptions // per-station playback options editor
+	playStateStationNotes   // display name, stream URL and notes editor
 )
 
 // PlayModel represents the play screen
@@ -41,9 +42,11 @@ type PlayModel struct {
 	state            playState
 	favoritePath     string
 	lists            []string
+	subscribed       []string // read-only subscribed lists, shown after lists
 	listItems        []list.Item
 	listModel        list.Model
 	selectedList     string
+	readOnly         bool // selectedList is a subscription
 	stations         []api.Station
 	stationItems     []list.Item
 	stationListModel list.Model
@@ -62,17 +65,24 @@ type PlayModel struct {
 	votedStations    *storage.VotedStations // Track voted stations
 	blocklistManager *blocklist.Manager
 	metadataManager  *storage.MetadataManager // Track play statistics
-	lastBlockTime    time.Time
-	trackHistory     []string // Last 5 tracks played
+	trackHistory     []string                 // Last 5 tracks played
 	// Star rating fields
 	ratingsManager *storage.RatingsManager
 	starRenderer   *components.StarRenderer
 	ratingMode     bool // true when waiting for 1-5 input after pressing R
 	// Tag fields
 	tagsManager *storage.TagsManager
+	likedSongs  *storage.LikedSongsManager // bookmarks for the "l" key
 	tagRenderer *components.TagRenderer
 	tagInput    components.TagInput
 	manageTags  components.ManageTags
+	// Per-station playback options editor
+	optionsEditor components.StationOptionsEditor
+	// Station notes, shared by every list (injected by App)
+	notesManager *storage.NotesManager
+	notesEditor  components.StationNotesEditor
+	// Subscribed lists (injected by App)
+	subscriptions *subscription.Manager
 	// Sleep timer fields
 	sleepTimerDialog components.SleepTimerDialog
 	dataPath         string // for loading last-used duration preference
@@ -82,15 +92,27 @@ type PlayModel struct {
 	playOptsCfg       config.PlayOptionsConfig
 	confirmStopTarget string // "back" or "main" — set when entering confirmStop state
 	nowPlayingBar     string // set by App when ContinueOnNavigate is active
+	// Visualizer (injected by App)
+	visualizerStyle string
+	audioLevels     *player.AudioLevels // nil when the visualizer is off or idle
+	// Time-shift
+	timeShiftPolling bool
+	behindLive       time.Duration // distance from the live edge; 0 when live
 }
 
 // playListItem wraps a list name for the bubbles list
 type playListItem struct {
-	name string
+	name       string
+	subscribed bool
 }
 
 func (i playListItem) FilterValue() string { return i.name }
-func (i playListItem) Title() string       { return i.name }
+func (i playListItem) Title() string {
+	if i.subscribed {
+		return "📡 " + i.name + " (subscribed)"
+	}
+	return i.name
+}
 func (i playListItem) Description() string { return "" }
 
 // stationListItem wraps a station for the bubbles list
@@ -100,7 +122,7 @@ type stationListItem struct {
 	tagPills  string // pre-rendered tag pills (empty if no tags)
 }
 
-func (i stationListItem) FilterValue() string { return i.station.Name }
+func (i stationListItem) FilterValue() string { return i.station.SearchText() }
 func (i stationListItem) Title() string {
 	var parts []string
 	name := i.station.TrimName()
@@ -237,37 +259,23 @@ func (m PlayModel) pollTrackHistory() tea.Cmd {
 func (m PlayModel) loadLists() tea.Cmd {
 	return func() tea.Msg {
 		lists, err := m.getAvailableLists()
-		if err != nil {
+		subscribed := m.subscriptions.Names()
+		if err != nil && len(subscribed) == 0 {
 			return errMsg{err}
 		}
-		return listsLoadedMsg{lists}
+		return listsLoadedMsg{lists: lists, subscribed: subscribed}
 	}
 }
 
-// getAvailableLists reads all JSON files from the favorite directory
+// getAvailableLists returns the favorite lists, including those in
+// folders, in their saved order
 func (m PlayModel) getAvailableLists() ([]string, error) {
-	entries, err := os.ReadDir(m.favoritePath)
+	store := storage.NewStorage(m.favoritePath)
+	lists, err := store.GetAllLists(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("failed to read favorites directory: %w", err)
 	}
 
-	var lists []string
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		name := entry.Name()
-		if strings.HasSuffix(name, ".json") {
-			// Skip system files that are not favorite lists
-			if name == "search-history.json" {
-				continue
-			}
-			// Remove .json extension
-			listName := strings.TrimSuffix(name, ".json")
-			lists = append(lists, listName)
-		}
-	}
-
 	if len(lists) == 0 {
 		return nil, fmt.Errorf("no favorite lists found in %s", m.favoritePath)
 	}
@@ -286,8 +294,16 @@ func (m PlayModel) loadStations() tea.Cmd {
 	}
 }
 
-// getStationsFromList reads and parses stations from a list file
+// getStationsFromList reads and parses stations from a list file, or the
+// stations last fetched for a subscription
 func (m PlayModel) getStationsFromList(listName string) ([]api.Station, error) {
+	if m.readOnly {
+		stations, err := m.subscriptions.Stations(listName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load subscription %s: %w", listName, err)
+		}
+		return stations, nil
+	}
 	store := storage.NewStorage(m.favoritePath)
 	list, err := store.LoadList(context.Background(), listName)
 	if err != nil {
@@ -298,11 +314,12 @@ func (m PlayModel) getStationsFromList(listName string) ([]api.Station, error) {
 		return []api.Station{}, nil
 	}
 
-	// Sort stations alphabetically (case-insensitive)
+	// Sort stations alphabetically (case-insensitive) unless they were put
+	// in order by hand
 	stations := list.Stations
-	sort.Slice(stations, func(i, j int) bool {
-		return strings.ToLower(stations[i].TrimName()) < strings.ToLower(stations[j].TrimName())
-	})
+	if !list.Ordered {
+		storage.SortStationsByName(stations)
+	}
 
 	return stations, nil
 }
@@ -346,6 +363,14 @@ func (m PlayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateSleepTimerDialog(msg)
 		case playStateConfirmStop:
 			return m.updateConfirmStop(msg)
+		case playStateStationOptions:
+			var cmd tea.Cmd
+			m.optionsEditor, cmd = m.optionsEditor.Update(msg)
+			return m, cmd
+		case playStateStationNotes:
+			var cmd tea.Cmd
+			m.notesEditor, cmd = m.notesEditor.Update(msg)
+			return m, cmd
 		}
 
 	case tea.WindowSizeMsg:
@@ -375,10 +400,11 @@ func (m PlayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case playbackStartedMsg:
 		// Playback started successfully - trigger refresh to show voted status
 		// Only start tick if not already running
+		m, tsCmd := m.startTimeShiftPolling()
 		if m.saveMessageTime <= 0 && !m.sleepTimerActive {
-			return m, tea.Batch(tickEverySecond(), m.pollTrackHistory())
+			return m, tea.Batch(tickEverySecond(), m.pollTrackHistory(), tsCmd)
 		}
-		return m, m.pollTrackHistory()
+		return m, tea.Batch(m.pollTrackHistory(), tsCmd)
 
 	case playbackErrorMsg:
 		m.err = msg.err
@@ -459,8 +485,6 @@ func (m PlayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case stationBlockedMsg:
-		m.lastBlockTime = time.Now()
-
 		if msg.success {
 			// Stop playback
 			if m.player != nil {
@@ -468,7 +492,7 @@ func (m PlayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			// Show message
-			m.saveMessage = msg.message + " (press 'u' within 5s to undo)"
+			m.saveMessage = msg.message + " (press 'u' to undo)"
 			m.saveMessageTime = messageDisplayMedium
 
 			// Return to station selection
@@ -503,36 +527,13 @@ func (m PlayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case undoBlockSuccessMsg:
-		m.saveMessage = "✓ Block undone"
-		startTick := m.saveMessageTime <= 0 && !m.sleepTimerActive
-		m.saveMessageTime = messageDisplayShort
-		// Update blocked status in list
-		if m.stationListModel.Items() != nil {
-			items := m.stationListModel.Items()
-			for i, item := range items {
-				if si, ok := item.(stationListItem); ok {
-					if m.blocklistManager != nil && !m.blocklistManager.IsBlockedByAny(&si.station) {
-						if si.isBlocked {
-							si.isBlocked = false
-							items[i] = si
-						}
-					}
-				}
-			}
-			m.stationListModel.SetItems(items)
-		}
-		if startTick {
-			return m, tickEverySecond()
-		}
-		return m, nil
-
-	case undoBlockFailedMsg:
-		m.saveMessage = "No recent block to undo"
-		startTick := m.saveMessageTime <= 0 && !m.sleepTimerActive
-		m.saveMessageTime = messageDisplayShort
-		if startTick {
-			return m, tickEverySecond()
+	case libraryChangedMsg:
+		// An undo or redo may have changed the lists or their stations
+		switch m.state {
+		case playStateListSelection:
+			return m, m.loadLists()
+		case playStateStationSelection:
+			return m, m.loadStations()
 		}
 		return m, nil
 
@@ -609,12 +610,71 @@ func (m PlayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.state = playStatePlaying
 		return m, nil
 
-	case listsLoadedMsg:
-		m.lists = msg.lists
-		m.listItems = make([]list.Item, len(msg.lists))
-		for i, name := range msg.lists {
-			m.listItems[i] = playListItem{name: name}
+	case components.StationOptionsSavedMsg:
+		m.state = playStatePlaying
+		if m.selectedStation == nil {
+			return m, nil
+		}
+		m.selectedStation.Options = msg.Options
+		if err := m.saveStationOptions(m.selectedStation); err != nil {
+			m.saveMessage = fmt.Sprintf("✗ Failed to save options: %v", err)
+			m.saveMessageTime = messageDisplayShort
+			return m, tickEverySecond()
+		}
+		m.saveMessage = "✓ Playback options saved — reconnecting"
+		startTick := m.saveMessageTime <= 0 && !m.sleepTimerActive
+		m.saveMessageTime = messageDisplayShort
+		// Restart the stream so the new headers and flags take effect now.
+		station, vol := *m.selectedStation, m.player.GetVolume()
+		restart := func() tea.Msg {
+			if err := m.player.PlayWithVolume(&station, vol); err != nil {
+				return playbackErrorMsg{err}
+			}
+			return playbackStartedMsg{}
+		}
+		if startTick {
+			return m, tea.Batch(tickEverySecond(), restart)
 		}
+		return m, restart
+
+	case components.StationOptionsCancelledMsg:
+		m.state = playStatePlaying
+		return m, nil
+
+	case components.StationNotesSavedMsg:
+		m.state = playStatePlaying
+		if m.selectedStation == nil || m.notesManager == nil {
+			return m, nil
+		}
+		oldURL := m.selectedStation.StreamURL()
+		if err := m.notesManager.Set(m.selectedStation.StationUUID, msg.Note); err != nil {
+			return m.showSaveMessage(fmt.Sprintf("✗ Failed to save notes: %v", err))
+		}
+		if m.selectedStation.StreamURL() == oldURL {
+			return m.showSaveMessage("✓ Station notes saved")
+		}
+		m.saveMessage = "✓ Station notes saved — reconnecting"
+		startTick := m.saveMessageTime <= 0 && !m.sleepTimerActive
+		m.saveMessageTime = messageDisplayShort
+		// Switch to the new stream URL now
+		station, vol := *m.selectedStation, m.player.GetVolume()
+		restart := func() tea.Msg {
+			if err := m.player.PlayWithVolume(&station, vol); err != nil {
+				return playbackErrorMsg{err}
+			}
+			return playbackStartedMsg{}
+		}
+		if startTick {
+			return m, tea.Batch(tickEverySecond(), restart)
+		}
+		return m, restart
+
+	case components.StationNotesCancelledMsg:
+		m.state = playStatePlaying
+		return m, nil
+
+	case listsLoadedMsg:
+		m.setListItems(msg.lists, msg.subscribed)
 
 		// Initialize now if we have dimensions, otherwise flag for later
 		if m.width > 0 && m.height > 0 {
@@ -665,6 +725,12 @@ func (m PlayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case timeShiftStatusMsg:
+		return m.handleTimeShiftStatus(msg)
+
+	case adSkipMsg:
+		return m.skipToNextStation(msg)
+
 	case trackHistoryMsg:
 		m.trackHistory = msg.tracks
 		// Continue polling if still playing
@@ -715,6 +781,9 @@ func (m *PlayModel) initializeStationListModel() {
 
 	m.stationListModel = list.New(m.stationItems, delegate, m.width, listHeight)
 	m.stationListModel.Title = fmt.Sprintf("Stations in %s", m.selectedList)
+	if m.readOnly {
+		m.stationListModel.Title += " (subscribed)"
+	}
 	m.stationListModel.SetShowStatusBar(true)
 	m.stationListModel.SetFilteringEnabled(true) // Enable fzf-style filtering
 	m.stationListModel.SetShowHelp(false)        // Disable built-in help to use custom help text
@@ -747,9 +816,14 @@ func (m PlayModel) updateListSelection(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Select list and move to station selection
 		if i, ok := m.listModel.SelectedItem().(playListItem); ok {
 			m.selectedList = i.name
+			m.readOnly = i.subscribed
 			m.state = playStateStationSelection
 			return m, m.loadStations()
 		}
+	case "K", "shift+up":
+		return m.moveList(-1)
+	case "J", "shift+down":
+		return m.moveList(1)
 	}
 
 	var cmd tea.Cmd
@@ -791,12 +865,31 @@ func (m PlayModel) updateStationSelection(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Prevent 'q' from quitting - do nothing
 		return m, nil
 	case "d":
+		if m.readOnly {
+			return m.showSaveMessage("Subscribed lists are read-only • c: Copy to a local list")
+		}
 		// Show delete confirmation
 		if i, ok := m.stationListModel.SelectedItem().(stationListItem); ok {
 			m.stationToDelete = &i.station
 			m.state = playStateDeleteConfirm
 			return m, nil
 		}
+	case "K", "shift+up", "J", "shift+down":
+		if m.stationListModel.SettingFilter() {
+			break
+		}
+		if m.readOnly {
+			return m.showSaveMessage("Subscribed lists are read-only • c: Copy to a local list")
+		}
+		delta := 1
+		if k := msg.String(); k == "K" || k == "shift+up" {
+			delta = -1
+		}
+		return m.moveStation(delta)
+	case "c":
+		if m.readOnly && !m.stationListModel.SettingFilter() {
+			return m.copySubscription()
+		}
 	case "enter":
 		// Select station and start playback
 		if i, ok := m.stationListModel.SelectedItem().(stationListItem); ok {
@@ -812,6 +905,99 @@ func (m PlayModel) updateStationSelection(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// moveList moves the selected list up or down within its folder and keeps
+// it selected
+func (m PlayModel) moveList(delta int) (tea.Model, tea.Cmd) {
+	i, ok := m.listModel.SelectedItem().(playListItem)
+	if !ok || i.subscribed {
+		return m, nil
+	}
+	store := storage.NewStorage(m.favoritePath)
+	moved, err := store.MoveList(context.Background(), i.name, delta)
+	if err != nil {
+		return m.showSaveMessage(fmt.Sprintf("✗ Failed to move list: %v", err))
+	}
+	if !moved {
+		return m, nil
+	}
+	lists, err := m.getAvailableLists()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.setListItems(lists, m.subscribed)
+	selected := 0
+	for k, name := range lists {
+		if name == i.name {
+			selected = k
+		}
+	}
+	cmd := m.listModel.SetItems(m.listItems)
+	m.listModel.Select(selected)
+	return m, cmd
+}
+
+// setListItems shows the local lists followed by the subscribed ones
+func (m *PlayModel) setListItems(lists, subscribed []string) {
+	m.lists = lists
+	m.subscribed = subscribed
+	m.listItems = make([]list.Item, 0, len(lists)+len(subscribed))
+	for _, name := range lists {
+		m.listItems = append(m.listItems, playListItem{name: name})
+	}
+	for _, name := range subscribed {
+		m.listItems = append(m.listItems, playListItem{name: name, subscribed: true})
+	}
+}
+
+// copySubscription copies the open subscription into a local list of the
+// same name, skipping stations the list already has
+func (m PlayModel) copySubscription() (tea.Model, tea.Cmd) {
+	store := storage.NewStorage(m.favoritePath)
+	n, err := m.subscriptions.CopyTo(context.Background(), store, m.selectedList, m.selectedList)
+	if err != nil {
+		return m.showSaveMessage(fmt.Sprintf("✗ Failed to copy: %v", err))
+	}
+	return m.showSaveMessage(fmt.Sprintf("✓ Copied %d station(s) to local list %s", n, m.selectedList))
+}
+
+// moveStation moves the selected station up or down in the list, which from
+// then on keeps its manual order instead of being sorted by name
+func (m PlayModel) moveStation(delta int) (tea.Model, tea.Cmd) {
+	if m.stationListModel.IsFiltered() {
+		return m.showSaveMessage("Clear the filter to reorder stations")
+	}
+	i, ok := m.stationListModel.SelectedItem().(stationListItem)
+	if !ok {
+		return m, nil
+	}
+	from := m.stationListModel.Index()
+	store := storage.NewStorage(m.favoritePath)
+	to, err := store.MoveStation(context.Background(), m.selectedList, i.station.StationUUID, delta)
+	if err != nil {
+		return m.showSaveMessage(fmt.Sprintf("✗ Failed to move station: %v", err))
+	}
+	if to == from || to < 0 || to >= len(m.stationItems) {
+		return m, nil
+	}
+	m.stations[from], m.stations[to] = m.stations[to], m.stations[from]
+	m.stationItems[from], m.stationItems[to] = m.stationItems[to], m.stationItems[from]
+	cmd := m.stationListModel.SetItems(m.stationItems)
+	m.stationListModel.Select(to)
+	return m, cmd
+}
+
+// showSaveMessage shows a transient message below the list
+func (m PlayModel) showSaveMessage(message string) (tea.Model, tea.Cmd) {
+	startTick := m.saveMessageTime <= 0 && !m.sleepTimerActive
+	m.saveMessage = message
+	m.saveMessageTime = messageDisplayShort
+	if startTick {
+		return m, tickEverySecond()
+	}
+	return m, nil
+}
+
 // handOffPlayer hands m.player to App and installs a fresh player on the
 // model, so App owns the running stream while PlayModel has a clean player
 // ready for the next selection. Returns the handoff command and the updated
@@ -837,6 +1023,35 @@ func (m PlayModel) handOffPlayer() (PlayModel, tea.Cmd) {
 	return m, cmd
 }
 
+// skipToNextStation plays the station after the current one in the list
+// when an ad filter skip rule matches on this screen's player.
+func (m PlayModel) skipToNextStation(msg adSkipMsg) (tea.Model, tea.Cmd) {
+	if msg.player != m.player || m.selectedStation == nil || len(m.stations) < 2 {
+		return m, nil
+	}
+	next := 0
+	for i, s := range m.stations {
+		if s.StationUUID == m.selectedStation.StationUUID {
+			next = (i + 1) % len(m.stations)
+			break
+		}
+	}
+	station := m.stations[next]
+	m.selectedStation = &station
+	if next < len(m.stationListModel.Items()) {
+		m.stationListModel.Select(next)
+	}
+	m.ratingMode = false
+	m.behindLive = 0
+	m.saveMessage = fmt.Sprintf("✓ Skipped ad — now playing %s", station.TrimName())
+	startTick := m.saveMessageTime <= 0 && !m.sleepTimerActive
+	m.saveMessageTime = messageDisplayShort
+	if startTick {
+		return m, tea.Batch(m.playStation(station), tickEverySecond())
+	}
+	return m, m.playStation(station)
+}
+
 // updatePlaying handles input during playback
 func (m PlayModel) updatePlaying(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Handle rating mode input first
@@ -844,6 +1059,10 @@ func (m PlayModel) updatePlaying(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleRatingModeInput(msg)
 	}
 
+	if m, cmd, ok := m.timeShiftKey(msg.String()); ok {
+		return m, cmd
+	}
+
 	switch msg.String() {
 	case "?":
 		m.helpModel.SetSize(m.width, m.height)
@@ -973,9 +1192,14 @@ func (m PlayModel) updatePlaying(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, m.blockStation()
 		}
 		return m, nil
-	case "u":
-		if time.Since(m.lastBlockTime) < 5*time.Second {
-			return m, m.undoLastBlock()
+	case "l":
+		if msg := likeCurrentSong(m.likedSongs, m.player, m.selectedStation); msg != "" {
+			m.saveMessage = msg
+			startTick := m.saveMessageTime <= 0 && !m.sleepTimerActive
+			m.saveMessageTime = messageDisplayShort
+			if startTick {
+				return m, tickEverySecond()
+			}
 		}
 		return m, nil
 	case "r":
@@ -1011,6 +1235,24 @@ func (m PlayModel) updatePlaying(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		return m, nil
+	case "o":
+		// Options are stored with the favorite, so they need a list to live in.
+		if m.selectedStation != nil && m.selectedList != "" && !m.readOnly {
+			w := max(m.width, 40)
+			m.optionsEditor = components.NewStationOptionsEditor(m.selectedStation.TrimName(), m.selectedStation.Options, player.ValidatePlaybackOptions, w)
+			m.state = playStateStationOptions
+			return m, m.optionsEditor.Init()
+		}
+		return m, nil
+	case "N":
+		if m.selectedStation != nil && m.notesManager != nil {
+			w := max(m.width, 40)
+			note := m.notesManager.Get(m.selectedStation.StationUUID)
+			m.notesEditor = components.NewStationNotesEditor(m.selectedStation.OriginalName(), note, w)
+			m.state = playStateStationNotes
+			return m, m.notesEditor.Init()
+		}
+		return m, nil
 	case "Z":
 		if m.sleepTimerActive {
 			return m, func() tea.Msg { return sleepTimerCancelMsg{} }
@@ -1212,6 +1454,31 @@ func (m PlayModel) saveStationVolume(station *api.Station) {
 	_ = store.SaveList(context.Background(), list)
 }
 
+// saveStationOptions persists a station's playback overrides in the current list.
+func (m PlayModel) saveStationOptions(station *api.Station) error {
+	store := storage.NewStorage(m.favoritePath)
+	list, err := store.LoadList(context.Background(), m.selectedList)
+	if err != nil {
+		return err
+	}
+	for i := range list.Stations {
+		if list.Stations[i].StationUUID == station.StationUUID {
+			list.Stations[i].Options = station.Options
+			break
+		}
+	}
+	if err := store.SaveList(context.Background(), list); err != nil {
+		return err
+	}
+	// Keep the in-memory list in sync so replaying from the list uses them.
+	for i := range m.stations {
+		if m.stations[i].StationUUID == station.StationUUID {
+			m.stations[i].Options = station.Options
+		}
+	}
+	return nil
+}
+
 // voteForStation votes for the currently playing station
 func (m PlayModel) voteForStation() tea.Cmd {
 	return components.ExecuteVote(m.selectedStation, m.votedStations, m.apiClient)
@@ -1270,25 +1537,6 @@ func (m *PlayModel) refreshStationTagPills(stationUUID string) {
 	m.stationListModel.SetItems(items)
 }
 
-// undoLastBlock undoes the last block operation
-func (m PlayModel) undoLastBlock() tea.Cmd {
-	return func() tea.Msg {
-		if m.blocklistManager == nil {
-			return undoBlockFailedMsg{}
-		}
-		ctx := context.Background()
-		undone, err := m.blocklistManager.UndoLastBlock(ctx)
-		if err != nil {
-			return errMsg{err}
-		}
-
-		if undone {
-			return undoBlockSuccessMsg{}
-		}
-		return undoBlockFailedMsg{}
-	}
-}
-
 // View renders the play screen
 func (m PlayModel) View() string {
 	if m.helpModel.IsVisible() {
@@ -1322,6 +1570,18 @@ func (m PlayModel) View() string {
 		}, m.height)
 	case playStateConfirmStop:
 		return m.viewConfirmStop()
+	case playStateStationOptions:
+		return m.renderPageWithBottomHelp(PageLayout{
+			Title:   "⚙️  Playback Options",
+			Content: m.optionsEditor.View(),
+			Help:    "Tab/↑↓: Field • Ctrl+U: Clear field • Enter: Save • Esc: Cancel",
+		}, m.height)
+	case playStateStationNotes:
+		return m.renderPageWithBottomHelp(PageLayout{
+			Title:   "📝 Station Notes",
+			Content: m.notesEditor.View(),
+			Help:    "Tab/↑↓: Field • Ctrl+U: Clear field • Enter: Save • Esc: Cancel",
+		}, m.height)
 	}
 
 	return "Unknown state"
@@ -1349,11 +1609,11 @@ func (m PlayModel) viewConfirmStop() string {
 // viewListSelection renders the list selection view
 func (m PlayModel) viewListSelection() string {
 	// Check if we have lists but no model yet (waiting for dimensions)
-	if len(m.lists) > 0 && m.listModel.Items() == nil {
+	if len(m.listItems) > 0 && m.listModel.Items() == nil {
 		return "Loading..."
 	}
 
-	if len(m.lists) == 0 {
+	if len(m.listItems) == 0 {
 		return m.noListsView()
 	}
 
@@ -1378,7 +1638,7 @@ func (m PlayModel) viewListSelection() string {
 		Title:    "Play from Favorites",
 		Subtitle: "Select a Favorite List",
 		Content:  content.String(),
-		Help:     "↑↓/jk: Navigate • g/G: Top/End • Enter: Select • Esc: Back • Ctrl+C: Quit",
+		Help:     "↑↓/jk: Navigate • g/G: Top/End • Enter: Select • J/K: Move • Esc: Back • Ctrl+C: Quit",
 	}, m.height)
 }
 
@@ -1410,10 +1670,12 @@ func (m PlayModel) viewPlaying() string {
 		// Playback status with proper spacing
 		content.WriteString("\n")
 		if m.player.IsPlaying() {
-			if track := m.player.GetCachedTrack(); IsValidTrackMetadata(track, m.selectedStation.TrimName()) {
+			if status := networkStatusText(m.player); status != "" {
+				content.WriteString(highlightStyle().Render(status))
+			} else if track := m.player.GetCachedTrack(); IsValidTrackMetadata(track, m.selectedStation.TrimName()) {
 				content.WriteString(successStyle().Render("▶ Now Playing:"))
 				content.WriteString(" ")
-				content.WriteString(infoStyle().Render(track))
+				content.WriteString(infoStyle().Render(formatTrack(m.player, track)))
 			} else {
 				content.WriteString(successStyle().Render("▶ Playing..."))
 			}
@@ -1430,6 +1692,31 @@ func (m PlayModel) viewPlaying() string {
 		content.WriteString("\n")
 	}
 
+	if !m.selectedStation.Options.IsZero() {
+		content.WriteString("\n")
+		content.WriteString(dimStyle().Render("⚙ Custom playback options — press o to edit"))
+	}
+
+	if m.behindLive >= time.Second {
+		content.WriteString("\n")
+		content.WriteString(highlightStyle().Render(fmt.Sprintf("⏪ %s behind live", formatBehindLive(m.behindLive))))
+		content.WriteString(dimStyle().Render(" · L: Go live"))
+	}
+
+	if status := adStatusText(m.player); status != "" {
+		content.WriteString("\n")
+		content.WriteString(infoStyle().Render(status))
+	}
+	if status := dataUsageText(m.player); status != "" {
+		content.WriteString("\n")
+		content.WriteString(highlightStyle().Render(status))
+	}
+
+	if vis := m.renderVisualizer(); vis != "" {
+		content.WriteString("\n\n")
+		content.WriteString(vis)
+	}
+
 	// Tag display
 	if m.tagsManager != nil && m.tagRenderer != nil {
 		tags := m.tagsManager.GetTags(m.selectedStation.StationUUID)
@@ -1462,7 +1749,7 @@ func (m PlayModel) viewPlaying() string {
 				trackStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
 			}
 
-			fmt.Fprintf(&content, "%s %s\n", indicator, trackStyle.Render(track))
+			fmt.Fprintf(&content, "%s %s\n", indicator, trackStyle.Render(formatTrack(m.player, track)))
 		}
 	}
 
@@ -1488,7 +1775,7 @@ func (m PlayModel) viewPlaying() string {
 		content.WriteString(highlightStyle().Render(timerInfo))
 	}
 
-	helpText := "Space: Pause • f: Fav • v: Vote • b: Block • Z: Sleep • +: Extend • 0: Main Menu • ?: Help"
+	helpText := "Space: Pause • ←/→: Seek • f: Fav • v: Vote • b: Block • o: Options • N: Notes • Z: Sleep • +: Extend • 0: Main Menu • ?: Help"
 	return m.renderPageWithBottomHelp(PageLayout{
 		Title:   "🎵 Now Playing",
 		Content: content.String(),
@@ -1555,11 +1842,15 @@ func (m PlayModel) viewStationSelection() string {
 		content.WriteString(style.Render(m.saveMessage))
 	}
 
+	help := "↑↓/jk: Navigate • g/G: Top/End • /: Filter • Enter: Play • J/K: Move • d: Delete • Esc: Back • 0: Main Menu • Ctrl+C: Quit"
+	if m.readOnly {
+		help = "↑↓/jk: Navigate • g/G: Top/End • /: Filter • Enter: Play • c: Copy to local list • Esc: Back • 0: Main Menu • Ctrl+C: Quit"
+	}
 	// Use the consistent page template with bottom-aligned help
 	return m.renderPageWithBottomHelp(PageLayout{
 		Title:   "Play from Favorites",
 		Content: content.String(),
-		Help:    "↑↓/jk: Navigate • g/G: Top/End • /: Filter • Enter: Play • d: Delete • Esc: Back • 0: Main Menu • Ctrl+C: Quit",
+		Help:    help,
 	}, m.height)
 }
 
@@ -1665,7 +1956,8 @@ func (m PlayModel) viewDeleteConfirm() string {
 // Messages
 
 type listsLoadedMsg struct {
-	lists []string
+	lists      []string
+	subscribed []string // subscriptions, for the favorites list picker
 }
 
 type stationsLoadedMsg struct {
@@ -1772,3 +2064,16 @@ func (m PlayModel) renderPageWithBottomHelp(layout PageLayout, height int) strin
 	}
 	return RenderPageWithBottomHelp(layout, height)
 }
+
+// capturesKeys is true while a dialog or the station filter takes text.
+func (m PlayModel) capturesKeys() bool {
+	if m.helpModel.IsVisible() {
+		return true
+	}
+	switch m.state {
+	case playStateTagInput, playStateManageTags, playStateSleepTimer,
+		playStateStationOptions, playStateStationNotes:
+		return true
+	}
+	return m.stationListModel.SettingFilter()
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/blocklist"
+	"github.com/shinokada/tera/v3/internal/storage"
+	"github.com/shinokada/tera/v3/internal/ui/components"
+)
+
+func TestPlayModel_StationOptionsSavedToList(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewStorage(dir)
+	station := api.Station{StationUUID: "uuid-1", Name: "Referer FM", URLResolved: "http://example.com/stream"}
+	if err := store.SaveList(context.Background(), &storage.FavoritesList{Name: "jazz", Stations: []api.Station{station}}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewPlayModel(dir, blocklist.NewManager(dir+"/blocklist.json"))
+	m.width, m.height = 100, 40
+	m.state = playStatePlaying
+	m.selectedList = "jazz"
+	m.stations = []api.Station{station}
+	m.selectedStation = &m.stations[0]
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	m = updated.(PlayModel)
+	if m.state != playStateStationOptions {
+		t.Fatalf("expected options editor, got state %v", m.state)
+	}
+
+	opts := &api.PlaybackOptions{Referer: "https://example.com/player"}
+	updated, _ = m.Update(components.StationOptionsSavedMsg{Options: opts})
+	m = updated.(PlayModel)
+	if m.state != playStatePlaying {
+		t.Errorf("expected to return to playing, got %v", m.state)
+	}
+
+	list, err := store.LoadList(context.Background(), "jazz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := list.Stations[0].Options; got == nil || got.Referer != opts.Referer {
+		t.Errorf("options not persisted: %+v", got)
+	}
+}
+
+func TestPlayModel_StationOptionsNeedList(t *testing.T) {
+	m := NewPlayModel(t.TempDir(), blocklist.NewManager(t.TempDir()+"/blocklist.json"))
+	m.state = playStatePlaying
+	m.selectedStation = &api.Station{StationUUID: "uuid-1"}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if updated.(PlayModel).state != playStatePlaying {
+		t.Error("options editor should not open without a favorites list")
+	}
+}
@@ -1,14 +1,17 @@
 package ui
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/shinokada/tera/v3/internal/api"
 	"github.com/shinokada/tera/v3/internal/blocklist"
+	"github.com/shinokada/tera/v3/internal/storage"
 )
 
 func TestStationListItem(t *testing.T) {
@@ -313,3 +316,40 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestGetStationsFromList_ManualOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := storage.NewStorage(tmpDir)
+	ctx := context.Background()
+	list := &storage.FavoritesList{Name: "Jazz/Smooth", Stations: []api.Station{
+		{StationUUID: "3", Name: "Zebra Radio"},
+		{StationUUID: "1", Name: "Alpha FM"},
+		{StationUUID: "2", Name: "Beta Station"},
+	}}
+	if err := store.SaveList(ctx, list); err != nil {
+		t.Fatal(err)
+	}
+	// Move Zebra Radio from last to second
+	if _, err := store.MoveStation(ctx, "Jazz/Smooth", "3", -1); err != nil {
+		t.Fatal(err)
+	}
+
+	blocklistManager := blocklist.NewManager(filepath.Join(tmpDir, "blocklist.json"))
+	model := NewPlayModel(tmpDir, blocklistManager)
+	loaded, err := model.getStationsFromList("Jazz/Smooth")
+	if err != nil {
+		t.Fatalf("getStationsFromList failed: %v", err)
+	}
+	var names []string
+	for _, s := range loaded {
+		names = append(names, s.Name)
+	}
+	if want := "Alpha FM,Zebra Radio,Beta Station"; strings.Join(names, ",") != want {
+		t.Errorf("expected manual order %s, got %s", want, strings.Join(names, ","))
+	}
+
+	lists, err := model.getAvailableLists()
+	if err != nil || len(lists) != 1 || lists[0] != "Jazz/Smooth" {
+		t.Errorf("getAvailableLists = %v, %v", lists, err)
+	}
+}
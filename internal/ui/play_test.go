@@ -8,6 +8,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/shinokada/tera/v3/internal/blocklist"
+	"github.com/shinokada/tera/v3/internal/subscription"
 )
 
 func TestNewPlayModel(t *testing.T) {
@@ -312,3 +313,54 @@ func TestPlayModel_Update_EnterKey(t *testing.T) {
 	// internally. In real usage, the list will have a default selection.
 	// This is tested through integration tests.
 }
+
+func TestPlaySubscribedListIsReadOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	favPath := filepath.Join(tmpDir, "favorites")
+	data := `{"subscriptions":[{"name":"Team","source":"aa5a315d61ae9438b18d","stations":[{"stationuuid":"a","name":"Jazz FM","url_resolved":"http://jazz"}]}],"version":1}`
+	if err := os.WriteFile(filepath.Join(tmpDir, subscription.FileName), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	subs, err := subscription.NewManager(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	model := NewPlayModel(favPath, blocklist.NewManager(filepath.Join(tmpDir, "blocklist.json")))
+	model.subscriptions = subs
+	model.width = 100
+	model.height = 40
+
+	// No local lists yet: the subscription is still offered
+	updated, _ := model.Update(model.loadLists()())
+	model = updated.(PlayModel)
+	if len(model.listItems) != 1 || !strings.Contains(model.listItems[0].(playListItem).Title(), "Team (subscribed)") {
+		t.Fatalf("expected the subscription in the list picker, got %v", model.listItems)
+	}
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(PlayModel)
+	if !model.readOnly {
+		t.Fatal("expected the subscription to open read-only")
+	}
+	updated, _ = model.Update(cmd())
+	model = updated.(PlayModel)
+	if len(model.stations) != 1 {
+		t.Fatalf("expected 1 station, got %d", len(model.stations))
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	model = updated.(PlayModel)
+	if model.state != playStateStationSelection || !strings.Contains(model.saveMessage, "read-only") {
+		t.Errorf("expected delete to be refused, got state %v, message %q", model.state, model.saveMessage)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	model = updated.(PlayModel)
+	if !strings.Contains(model.saveMessage, "✓ Copied 1 station(s)") {
+		t.Errorf("unexpected copy message %q", model.saveMessage)
+	}
+	if _, err := os.Stat(filepath.Join(favPath, "Team.json")); err != nil {
+		t.Errorf("expected a local copy: %v", err)
+	}
+}
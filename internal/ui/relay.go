@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/player"
+	"github.com/shinokada/tera/v3/internal/relay"
+)
+
+// StartRelay starts re-streaming whatever TERA plays on cfg.Listen. The relay
+// follows station changes on every screen through the player's playback
+// observer, so no per-screen wiring is needed.
+func (a *App) StartRelay(cfg config.RelayConfig) error {
+	r := relay.New(cfg.Listen, "TERA/"+Version)
+	if err := r.Start(); err != nil {
+		return err
+	}
+	player.SetPlaybackObserver(r.SetStation)
+	a.relay = r
+	return nil
+}
+
+// stopRelay detaches the relay from the players and closes every listener.
+func (a *App) stopRelay() {
+	if a.relay == nil {
+		return
+	}
+	player.SetPlaybackObserver(nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = a.relay.Shutdown(ctx)
+	a.relay = nil
+}
@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/player"
+	"github.com/shinokada/tera/v3/internal/remote"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// remoteContextLabel is shown in the now-playing bar for stations started
+// from the HTTP remote.
+const remoteContextLabel = "Remote"
+
+// remoteRequestTimeout bounds how long an HTTP handler waits for the Update
+// loop to answer before giving up.
+const remoteRequestTimeout = 5 * time.Second
+
+type remoteAction int
+
+const (
+	remoteActionNowPlaying remoteAction = iota
+	remoteActionPlay
+	remoteActionStop
+	remoteActionVolume
+)
+
+// remoteRequestMsg carries a request from the HTTP server into the Update
+// loop. All App state is read and written there, so the server never races
+// with key handling.
+type remoteRequestMsg struct {
+	action  remoteAction
+	station api.Station
+	volume  int
+	reply   chan remoteReply // buffered (1); the Update loop never blocks on it
+}
+
+type remoteReply struct {
+	nowPlaying remote.NowPlaying
+	volume     int
+	err        error
+}
+
+// remoteController implements remote.Controller by forwarding every call to
+// the running Bubble Tea program.
+type remoteController struct {
+	app *App
+}
+
+func (c remoteController) request(msg remoteRequestMsg) (remoteReply, error) {
+	p := c.app.program.Load()
+	if p == nil {
+		return remoteReply{}, errors.New("player is not running")
+	}
+	msg.reply = make(chan remoteReply, 1)
+	go p.Send(msg)
+	select {
+	case r := <-msg.reply:
+		return r, r.err
+	case <-time.After(remoteRequestTimeout):
+		return remoteReply{}, errors.New("timed out waiting for player")
+	}
+}
+
+func (c remoteController) NowPlaying() (remote.NowPlaying, error) {
+	r, err := c.request(remoteRequestMsg{action: remoteActionNowPlaying})
+	return r.nowPlaying, err
+}
+
+func (c remoteController) Play(station api.Station) error {
+	_, err := c.request(remoteRequestMsg{action: remoteActionPlay, station: station})
+	return err
+}
+
+func (c remoteController) Stop() error {
+	_, err := c.request(remoteRequestMsg{action: remoteActionStop})
+	return err
+}
+
+func (c remoteController) SetVolume(volume int) (int, error) {
+	r, err := c.request(remoteRequestMsg{action: remoteActionVolume, volume: volume})
+	return r.volume, err
+}
+
+// StartRemote starts the HTTP API and web remote described by cfg. A token is
+// generated and persisted on first use so the API is never left open. It must
+// be called after SetProgram.
+func (a *App) StartRemote(cfg config.RemoteConfig) error {
+	if cfg.Token == "" {
+		token, err := remote.GenerateToken()
+		if err != nil {
+			return err
+		}
+		cfg.Token = token
+		if err := storage.SaveRemoteConfigToUnified(cfg); err != nil {
+			return err
+		}
+	}
+	srv := remote.NewServer(remote.Options{
+		Addr:       cfg.Listen,
+		Token:      cfg.Token,
+		Favorites:  storage.NewStorage(a.favoritePath),
+		Ratings:    a.ratingsManager,
+		Client:     a.apiClient,
+		Controller: remoteController{app: a},
+	})
+	if err := srv.Start(); err != nil {
+		return err
+	}
+	a.remoteServer = srv
+	return nil
+}
+
+// stopRemote shuts the HTTP server down, giving in-flight requests a moment
+// to finish.
+func (a *App) stopRemote() {
+	if a.remoteServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = a.remoteServer.Shutdown(ctx)
+	a.remoteServer = nil
+}
+
+// currentPlayback returns the player that is audible right now together with
+// its station and a context label. App-level players take precedence over
+// screen-owned ones. Returns a nil player when nothing is playing.
+func (a *App) currentPlayback() (*player.MPVPlayer, *api.Station, string) {
+	if a.activeStation != nil && a.activePlayer != nil {
+		return a.activePlayer, a.activeStation, a.activeContextLabel
+	}
+	if a.playingFromMain && a.playingStation != nil && a.quickFavPlayer != nil {
+		return a.quickFavPlayer, a.playingStation, "Quick Play"
+	}
+	for _, sp := range []struct {
+		p     *player.MPVPlayer
+		label string
+	}{
+		{a.playScreen.player, "Favorites"},
+		{a.searchScreen.player, "Search"},
+		{a.luckyScreen.player, "Lucky"},
+		{a.mostPlayedScreen.player, "Most Played"},
+		{a.topRatedScreen.player, "Top Rated"},
+		{a.tagPlaylistsScreen.player, "Tag Playlists"},
+		{a.browseTagsScreen.player, "Browse Tags"},
+	} {
+		if sp.p != nil && sp.p.IsPlaying() {
+			if st := sp.p.GetCurrentStation(); st != nil {
+				return sp.p, st, sp.label
+			}
+		}
+	}
+	return nil, nil, ""
+}
+
+// handleRemoteRequest answers a remoteRequestMsg on the Update loop.
+func (a *App) handleRemoteRequest(msg remoteRequestMsg) tea.Cmd {
+	switch msg.action {
+	case remoteActionNowPlaying:
+		var np remote.NowPlaying
+		if p, st, label := a.currentPlayback(); p != nil {
+			track := p.GetCachedTrackInfo()
+			np = remote.NowPlaying{
+				Playing: true,
+				Paused:  p.IsPaused(),
+				Station: st,
+				Track:   track.Raw,
+				Artist:  track.Artist,
+				Title:   track.Title,
+				Album:   track.Album,
+				Volume:  p.GetVolume(),
+				Muted:   p.IsMuted(),
+				Context: label,
+			}
+		}
+		msg.reply <- remoteReply{nowPlaying: np}
+		return nil
+
+	case remoteActionStop:
+		a.stopAllPlayback()
+		a.broadcastNowPlayingBar()
+		msg.reply <- remoteReply{}
+		return nil
+
+	case remoteActionVolume:
+		p, _, _ := a.currentPlayback()
+		if p == nil {
+			msg.reply <- remoteReply{err: remote.ErrNothingPlaying}
+			return nil
+		}
+		p.SetVolume(msg.volume)
+		msg.reply <- remoteReply{volume: p.GetVolume()}
+		return nil
+
+	case remoteActionPlay:
+		// Remote playback always lives at the app level, like a
+		// ContinueOnNavigate handoff, so it survives screen changes and shows
+		// up in the now-playing bar with an "x: Stop" hint.
+		a.stopAllPlayback()
+		fresh := player.NewMPVPlayer()
+		if a.metadataManager != nil {
+			fresh.SetMetadataManager(a.metadataManager)
+		}
+		station := msg.station
+		a.activePlayer = fresh
+		a.activeStation = &station
+		a.activeContextLabel = remoteContextLabel
+		a.broadcastNowPlayingBar()
+
+		vol := a.playOptsCfg.DefaultVolume
+		if a.playOptsCfg.StartVolumeMode == "last_used" && a.playOptsCfg.LastUsedVolume > 0 {
+			vol = a.playOptsCfg.LastUsedVolume
+		}
+		if station.Volume != nil {
+			vol = *station.Volume
+		}
+		reply := msg.reply
+		return func() tea.Msg {
+			err := fresh.PlayWithVolume(&station, vol)
+			reply <- remoteReply{err: err}
+			if err != nil {
+				return playbackErrorMsg{err}
+			}
+			return playbackStartedMsg{}
+		}
+	}
+	msg.reply <- remoteReply{err: errors.New("unknown remote action")}
+	return nil
+}
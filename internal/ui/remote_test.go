@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shinokada/tera/v3/internal/player"
+	"github.com/shinokada/tera/v3/internal/remote"
+)
+
+func sendRemote(app *App, msg remoteRequestMsg) remoteReply {
+	msg.reply = make(chan remoteReply, 1)
+	app.Update(msg)
+	select {
+	case r := <-msg.reply:
+		return r
+	default:
+		return remoteReply{err: errors.New("no synchronous reply")}
+	}
+}
+
+func TestRemoteNowPlaying_Idle(t *testing.T) {
+	app := newContinueOnNavigateApp()
+	r := sendRemote(app, remoteRequestMsg{action: remoteActionNowPlaying})
+	if r.err != nil {
+		t.Fatalf("unexpected error: %v", r.err)
+	}
+	if r.nowPlaying.Playing || r.nowPlaying.Station != nil {
+		t.Errorf("expected idle state, got %+v", r.nowPlaying)
+	}
+}
+
+func TestRemoteNowPlaying_ActiveStation(t *testing.T) {
+	app := newContinueOnNavigateApp()
+	app.activePlayer = player.NewMPVPlayer()
+	app.activeStation = newTestStation("uuid-1", "Handoff Radio")
+	app.activeContextLabel = "Top Rated"
+
+	r := sendRemote(app, remoteRequestMsg{action: remoteActionNowPlaying})
+	if !r.nowPlaying.Playing {
+		t.Fatal("expected playing")
+	}
+	if r.nowPlaying.Station.StationUUID != "uuid-1" {
+		t.Errorf("expected uuid-1, got %s", r.nowPlaying.Station.StationUUID)
+	}
+	if r.nowPlaying.Context != "Top Rated" {
+		t.Errorf("expected context 'Top Rated', got %q", r.nowPlaying.Context)
+	}
+}
+
+func TestRemoteVolume_NothingPlaying(t *testing.T) {
+	app := newContinueOnNavigateApp()
+	r := sendRemote(app, remoteRequestMsg{action: remoteActionVolume, volume: 50})
+	if !errors.Is(r.err, remote.ErrNothingPlaying) {
+		t.Errorf("expected ErrNothingPlaying, got %v", r.err)
+	}
+}
+
+func TestRemoteStop_ClearsActiveStation(t *testing.T) {
+	app := newContinueOnNavigateApp()
+	app.activePlayer = player.NewMPVPlayer()
+	app.activeStation = newTestStation("uuid-1", "Handoff Radio")
+
+	r := sendRemote(app, remoteRequestMsg{action: remoteActionStop})
+	if r.err != nil {
+		t.Fatalf("unexpected error: %v", r.err)
+	}
+	if app.activePlayer != nil || app.activeStation != nil {
+		t.Error("expected app-level playback to be cleared")
+	}
+}
+
+func TestRemotePlay_HandsOffToAppLevel(t *testing.T) {
+	app := newContinueOnNavigateApp()
+	old := player.NewMPVPlayer()
+	app.activePlayer = old
+	app.activeStation = newTestStation("uuid-old", "Old Radio")
+
+	msg := remoteRequestMsg{
+		action:  remoteActionPlay,
+		station: *newTestStation("uuid-new", "New Radio"),
+		reply:   make(chan remoteReply, 1),
+	}
+	_, cmd := app.Update(msg)
+	if cmd == nil {
+		t.Fatal("expected a playback command")
+	}
+	if app.activePlayer == nil || app.activePlayer == old {
+		t.Error("expected a fresh app-level player")
+	}
+	if app.activeStation == nil || app.activeStation.StationUUID != "uuid-new" {
+		t.Errorf("expected uuid-new as active station, got %+v", app.activeStation)
+	}
+	if app.activeContextLabel != remoteContextLabel {
+		t.Errorf("expected context %q, got %q", remoteContextLabel, app.activeContextLabel)
+	}
+}
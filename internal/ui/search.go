@@ -147,6 +147,7 @@ type SearchModel struct {
 	ratingsManager   *storage.RatingsManager
 	starRenderer     *components.StarRenderer
 	tagsManager      *storage.TagsManager
+	likedSongs       *storage.LikedSongsManager // bookmarks for the "l" key
 	tagRenderer      *components.TagRenderer
 	tagInput         components.TagInput
 	manageTags       components.ManageTags
@@ -158,7 +159,6 @@ type SearchModel struct {
 	saveMessageTime  int
 	numberBuffer     string
 	err              error
-	lastBlockTime    time.Time
 	ratingMode       bool
 	// ...existing code...
 	sleepTimerActive    bool
@@ -652,12 +652,6 @@ func (m SearchModel) handlePlayerUpdate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, m.blockStation()
 		}
 		return m, nil
-	case "u":
-		// Undo last block (within 5 seconds)
-		if time.Since(m.lastBlockTime) < 5*time.Second {
-			return m, m.undoLastBlock()
-		}
-		return m, nil
 	case "?":
 		m.helpModel.SetSize(m.width, m.height)
 		m.helpModel.Toggle()
@@ -682,6 +676,16 @@ func (m SearchModel) handlePlayerUpdate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 		return m, nil
+	case "l":
+		if msg := likeCurrentSong(m.likedSongs, m.player, m.selectedStation); msg != "" {
+			m.saveMessage = msg
+			startTick := m.saveMessageTime <= 0 && !m.sleepTimerActive
+			m.saveMessageTime = messageDisplayShort
+			if startTick {
+				return m, tickEverySecond()
+			}
+		}
+		return m, nil
 	case "r":
 		// Enter rating mode
 		if m.selectedStation != nil && m.ratingsManager != nil {
@@ -1011,10 +1015,12 @@ func (m SearchModel) View() string {
 			if m.player.IsPlaying() {
 				// Use the cached track (kept fresh by monitorMetadata every 5 s) to
 				// avoid a blocking IPC socket call inside the render path.
-				if track := m.player.GetCachedTrack(); IsValidTrackMetadata(track, m.selectedStation.TrimName()) {
+				if status := networkStatusText(m.player); status != "" {
+					content.WriteString(highlightStyle().Render(status))
+				} else if track := m.player.GetCachedTrack(); IsValidTrackMetadata(track, m.selectedStation.TrimName()) {
 					content.WriteString(successStyle().Render("▶ Now Playing:"))
 					content.WriteString(" ")
-					content.WriteString(infoStyle().Render(track))
+					content.WriteString(infoStyle().Render(formatTrack(m.player, track)))
 				} else {
 					content.WriteString(successStyle().Render("▶ Playing..."))
 				}
@@ -1761,25 +1767,6 @@ func (m SearchModel) sleepTimerCountdown() string {
 	return formatSleepCountdown(m.sleepCountdown)
 }
 
-// undoLastBlock undoes the last block operation
-func (m SearchModel) undoLastBlock() tea.Cmd {
-	return func() tea.Msg {
-		if m.blocklistManager == nil {
-			return undoBlockFailedMsg{}
-		}
-		ctx := context.Background()
-		undone, err := m.blocklistManager.UndoLastBlock(ctx)
-		if err != nil {
-			return searchErrorMsg{err}
-		}
-
-		if undone {
-			return undoBlockSuccessMsg{}
-		}
-		return undoBlockFailedMsg{}
-	}
-}
-
 // renderPage injects the now-playing bar when the model's own player is not
 // actively playing (so viewPlaying is unaffected).
 func (m SearchModel) renderPage(layout PageLayout) string {
@@ -1795,3 +1782,17 @@ func (m SearchModel) renderPageWithBottomHelp(layout PageLayout, height int) str
 	}
 	return RenderPageWithBottomHelp(layout, height)
 }
+
+// capturesKeys is true in the query, list name and tag inputs, the
+// advanced form, and while filtering results.
+func (m SearchModel) capturesKeys() bool {
+	if m.helpModel.IsVisible() {
+		return true
+	}
+	switch m.state {
+	case searchStateInput, searchStateNewListInput, searchStateAdvancedForm,
+		searchStateTagInput, searchStateManageTags, searchStateSleepTimer:
+		return true
+	}
+	return m.resultsList.SettingFilter()
+}
@@ -2,7 +2,6 @@ package ui
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -199,12 +198,11 @@ func (m SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case stationBlockedMsg:
-		m.lastBlockTime = time.Now()
 		if msg.success {
 			if m.player != nil {
 				_ = m.player.Stop()
 			}
-			m.saveMessage = msg.message + " (press 'u' within 5s to undo)"
+			m.saveMessage = msg.message + " (press 'u' to undo)"
 			m.saveMessageTime = messageDisplayMedium
 			m.state = searchStateResults
 			m.selectedStation = nil
@@ -233,21 +231,17 @@ func (m SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case undoBlockSuccessMsg:
-		m.saveMessage = "✓ Block undone"
-		startTick := m.saveMessageTime <= 0 && !m.sleepTimerActive
-		m.saveMessageTime = messageDisplayShort
-		if startTick {
-			return m, tickEverySecond()
-		}
-		return m, nil
-
-	case undoBlockFailedMsg:
-		m.saveMessage = "No recent block to undo"
-		startTick := m.saveMessageTime <= 0 && !m.sleepTimerActive
-		m.saveMessageTime = messageDisplayShort
-		if startTick {
-			return m, tickEverySecond()
+	case libraryChangedMsg:
+		// An undo or redo may have blocked or unblocked results
+		if m.blocklistManager != nil && m.resultsList.Items() != nil {
+			items := m.resultsList.Items()
+			for i, item := range items {
+				if si, ok := item.(stationListItem); ok {
+					si.isBlocked = m.blocklistManager.IsBlockedByAny(&si.station)
+					items[i] = si
+				}
+			}
+			m.resultsList.SetItems(items)
 		}
 		return m, nil
 
@@ -59,6 +59,7 @@ type SettingsModel struct {
 	installInfo     api.InstallInfo
 	// Play options
 	playOptsCfg   config.PlayOptionsConfig
+	visualizerCfg config.VisualizerConfig
 	nowPlayingBar string // set by App when ContinueOnNavigate is active
 }
 
@@ -277,6 +278,12 @@ func NewSettingsModel(favoritePath string) SettingsModel {
 		poCfg = config.DefaultPlayOptionsConfig()
 	}
 
+	// Load visualizer config (shown under Play Options)
+	visCfg, err := storage.LoadVisualizerConfigFromUnified()
+	if err != nil {
+		visCfg = config.DefaultVisualizerConfig()
+	}
+
 	// Detect installation method
 	installInfo := api.DetectInstallMethod()
 
@@ -290,6 +297,7 @@ func NewSettingsModel(favoritePath string) SettingsModel {
 		searchHistory:   history,
 		playHistoryCfg:  phCfg,
 		playOptsCfg:     poCfg,
+		visualizerCfg:   visCfg,
 		// Update fields initialized to defaults
 		updateChecked:  false,
 		updateChecking: false,
@@ -827,7 +835,7 @@ func (m SettingsModel) viewSearchHistory() string {
 func (m SettingsModel) updatePlayOptions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	cfg := m.playOptsCfg
 	switch msg.String() {
-	case "esc", "7":
+	case "esc", "9":
 		m.state = settingsStateMenu
 		return m, nil
 	case "0":
@@ -916,10 +924,37 @@ func (m SettingsModel) updatePlayOptions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.messageTime = 3
 		return m, tickEverySecond()
 
-	case "6": // Reset to Defaults
+	case "6": // Cycle visualizer Off → VU → Spectrum
+		vis := m.visualizerCfg
+		switch {
+		case !vis.Enabled:
+			vis.Enabled, vis.Style = true, config.VisualizerStyleVU
+		case vis.Style == config.VisualizerStyleVU:
+			vis.Style = config.VisualizerStyleSpectrum
+		default:
+			vis.Enabled = false
+		}
+		return m.saveVisualizerConfig(vis, fmt.Sprintf("✓ Visualizer: %s", visualizerLabel(vis)))
+
+	case "7": // Toggle LowCPU
+		vis := m.visualizerCfg
+		vis.LowCPU = !vis.LowCPU
+		v := "Off"
+		if vis.LowCPU {
+			v = "On"
+		}
+		return m.saveVisualizerConfig(vis, fmt.Sprintf("✓ Low-CPU mode: %s", v))
+
+	case "8": // Reset to Defaults
 		cfg = config.DefaultPlayOptionsConfig()
-		if err := storage.SavePlayOptionsConfigToUnified(cfg); err == nil {
+		vis := config.DefaultVisualizerConfig()
+		err := storage.SavePlayOptionsConfigToUnified(cfg)
+		if err == nil {
+			err = storage.SaveVisualizerConfigToUnified(vis)
+		}
+		if err == nil {
 			m.playOptsCfg = cfg
+			m.visualizerCfg = vis
 			m.message = "✓ Play Options reset to defaults"
 			m.messageIsSuccess = true
 		} else {
@@ -927,11 +962,27 @@ func (m SettingsModel) updatePlayOptions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.messageIsSuccess = false
 		}
 		m.messageTime = 3
-		return m, tickEverySecond()
+		return m, tea.Batch(tickEverySecond(), visualizerConfigChanged(m.visualizerCfg))
 	}
 	return m, nil
 }
 
+// saveVisualizerConfig persists vis and tells the App to apply it right away,
+// so the play view picks up the change without a restart.
+func (m SettingsModel) saveVisualizerConfig(vis config.VisualizerConfig, success string) (tea.Model, tea.Cmd) {
+	if err := storage.SaveVisualizerConfigToUnified(vis); err != nil {
+		m.message = fmt.Sprintf("✗ Failed: %v", err)
+		m.messageIsSuccess = false
+		m.messageTime = 3
+		return m, tickEverySecond()
+	}
+	m.visualizerCfg = vis
+	m.message = success
+	m.messageIsSuccess = true
+	m.messageTime = 3
+	return m, tea.Batch(tickEverySecond(), visualizerConfigChanged(vis))
+}
+
 // viewPlayOptions renders the Play Options settings screen.
 func (m SettingsModel) viewPlayOptions() string {
 	var content strings.Builder
@@ -969,9 +1020,19 @@ func (m SettingsModel) viewPlayOptions() string {
 	content.WriteString(helpStyle().Render("      Display bitrate, codec, country, and tags in the play screen."))
 	content.WriteString("\n\n")
 
-	content.WriteString(normalItemStyle().Render("  6. Reset to Defaults"))
+	content.WriteString(normalItemStyle().Render(fmt.Sprintf("  6. Visualizer                              [%s]", visualizerLabel(m.visualizerCfg))))
 	content.WriteString("\n")
-	content.WriteString(normalItemStyle().Render("  7. Back"))
+	content.WriteString(helpStyle().Render("      Off → VU meter → Spectrum. Hidden when the terminal is too small."))
+	content.WriteString("\n\n")
+
+	content.WriteString(normalItemStyle().Render(fmt.Sprintf("  7. Low-CPU mode                            %s", boolStr(m.visualizerCfg.LowCPU))))
+	content.WriteString("\n")
+	content.WriteString(helpStyle().Render("      Skip audio analysis entirely; turns the visualizer off."))
+	content.WriteString("\n\n")
+
+	content.WriteString(normalItemStyle().Render("  8. Reset to Defaults"))
+	content.WriteString("\n")
+	content.WriteString(normalItemStyle().Render("  9. Back"))
 	content.WriteString("\n")
 
 	if m.message != "" {
@@ -986,7 +1047,7 @@ func (m SettingsModel) viewPlayOptions() string {
 	return m.renderPageWithBottomHelp(PageLayout{
 		Title:   "⚙️  Settings > Play Options",
 		Content: content.String(),
-		Help:    "1-7: Select • Esc/7: Back • 0: Main Menu • Ctrl+C: Quit",
+		Help:    "1-9: Select • Esc/9: Back • 0: Main Menu • Ctrl+C: Quit",
 	}, m.height)
 }
 
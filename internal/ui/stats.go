@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/stats"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// StatsModel shows the listening report as scrollable terminal charts.
+type StatsModel struct {
+	dataPath        string
+	metadataManager *storage.MetadataManager
+	report          stats.Report
+	lines           []string // rendered report, one entry per line
+	offset          int      // first line shown
+	message         string
+	messageTime     int
+	width           int
+	height          int
+	nowPlayingBar   string // set by App when ContinueOnNavigate is active
+}
+
+// NewStatsModel creates the stats screen for the session log in dataPath
+// and the play statistics in metadataManager.
+func NewStatsModel(dataPath string, metadataManager *storage.MetadataManager) StatsModel {
+	m := StatsModel{
+		dataPath:        dataPath,
+		metadataManager: metadataManager,
+		width:           80,
+	}
+	m.load()
+	return m
+}
+
+// Init initializes the stats screen
+func (m StatsModel) Init() tea.Cmd {
+	return nil
+}
+
+// load rebuilds the report as of now.
+func (m *StatsModel) load() {
+	report, err := stats.Collect(m.dataPath, m.metadataManager, time.Now(), stats.DefaultOptions())
+	if err != nil {
+		m.message = fmt.Sprintf("✗ %v", err)
+		m.messageTime = 180
+	}
+	m.report = report
+	m.render()
+}
+
+// render lays the report out for the current width.
+func (m *StatsModel) render() {
+	m.lines = strings.Split(stats.Render(m.report, m.width-4), "\n")
+	m.offset = min(m.offset, m.maxOffset())
+}
+
+// visibleLines is how many report lines fit on screen.
+func (m StatsModel) visibleLines() int {
+	// Same page overhead as the timeline screen
+	return max(m.height-14, 5)
+}
+
+func (m StatsModel) maxOffset() int {
+	return max(len(m.lines)-m.visibleLines(), 0)
+}
+
+// Update handles messages for the stats screen
+func (m StatsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Decrement message timer
+	if m.messageTime > 0 {
+		m.messageTime--
+		if m.messageTime == 0 {
+			m.message = ""
+		}
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m.handleInput(msg)
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.render()
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m StatsModel) handleInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return m, func() tea.Msg { return navigateMsg{screen: screenMostPlayed} }
+	case "0":
+		return m, func() tea.Msg { return navigateMsg{screen: screenMainMenu} }
+	case "q":
+		return m, tea.Quit
+	case "up", "k":
+		m.offset = max(m.offset-1, 0)
+	case "down", "j":
+		m.offset = min(m.offset+1, m.maxOffset())
+	case "pgup":
+		m.offset = max(m.offset-m.visibleLines(), 0)
+	case "pgdown", " ":
+		m.offset = min(m.offset+m.visibleLines(), m.maxOffset())
+	case "g", "home":
+		m.offset = 0
+	case "G", "end":
+		m.offset = m.maxOffset()
+	case "r":
+		m.load()
+		m.message = "✓ Stats refreshed"
+		m.messageTime = 150
+	}
+	return m, nil
+}
+
+// View renders the stats screen
+func (m StatsModel) View() string {
+	var content strings.Builder
+
+	if m.message != "" {
+		style := successStyle()
+		if strings.Contains(m.message, "✗") {
+			style = errorStyle()
+		}
+		content.WriteString(style.Render(m.message))
+		content.WriteString("\n\n")
+	}
+
+	if m.report.TotalSeconds == 0 && m.report.Sessions == 0 {
+		content.WriteString(infoStyle().Render("No listening stats yet.\n\nPlay some stations and come back to see where your time goes."))
+	} else {
+		end := min(m.offset+m.visibleLines(), len(m.lines))
+		content.WriteString(strings.Join(m.lines[m.offset:end], "\n"))
+	}
+
+	subtitle := "Listening time by week, month, station and hour"
+	if n := m.maxOffset(); n > 0 {
+		subtitle += fmt.Sprintf(" · line %d/%d", m.offset+1, len(m.lines))
+	}
+
+	return m.renderPageWithBottomHelp(PageLayout{
+		Title:    "📊 Listening Stats",
+		Subtitle: subtitle,
+		Content:  content.String(),
+		Help:     "↑↓/jk: Scroll • g/G: Top/End • r: Refresh • Esc: Back • 0: Main Menu",
+	}, m.height)
+}
+
+// renderPageWithBottomHelp wraps RenderPageWithBottomHelp injecting the active now-playing bar.
+func (m StatsModel) renderPageWithBottomHelp(layout PageLayout, height int) string {
+	layout.NowPlaying = m.nowPlayingBar
+	return RenderPageWithBottomHelp(layout, height)
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+func TestStatsScreen(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+
+	dataPath := t.TempDir()
+	m := NewStatsModel(dataPath, nil)
+	if view := m.View(); !strings.Contains(view, "No listening stats yet") {
+		t.Errorf("expected the empty state, got:\n%s", view)
+	}
+
+	start := startOfDay(time.Now()).Add(9 * time.Hour)
+	if start.After(time.Now()) {
+		start = start.AddDate(0, 0, -1)
+	}
+	err := storage.NewSessionLog(dataPath, 0).Append(storage.ListeningSession{
+		StationUUID: "uuid-1",
+		StationName: "Jazz FM",
+		Start:       start,
+		End:         start.Add(45 * time.Minute),
+		StopReason:  storage.StopReasonStopped,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m = NewStatsModel(dataPath, nil)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+	m = updated.(StatsModel)
+	view := m.View()
+	for _, want := range []string{"Listening Stats", "1 session(s) logged (45m)", "Hours per week"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the view", want)
+		}
+	}
+
+	// The report is taller than the screen, so it scrolls
+	if m.maxOffset() == 0 {
+		t.Fatal("expected the report to need scrolling")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	m = updated.(StatsModel)
+	if m.offset != m.maxOffset() || !strings.Contains(m.View(), "most") {
+		t.Errorf("G should scroll to the heatmap legend, offset %d", m.offset)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	m = updated.(StatsModel)
+	if m.offset != 0 {
+		t.Errorf("g should scroll to the top, offset %d", m.offset)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("expected esc to navigate")
+	}
+	if nav, ok := cmd().(navigateMsg); !ok || nav.screen != screenMostPlayed {
+		t.Errorf("esc should go back to Most Played, got %#v", nav)
+	}
+}
@@ -367,6 +367,12 @@ func RenderStationDetailsWithVote(station api.Station, voted bool) string {
 	var s strings.Builder
 
 	fmt.Fprintf(&s, "Name:    %s\n", boldStyle().Render(station.TrimName()))
+	if original := station.OriginalName(); original != station.TrimName() {
+		fmt.Fprintf(&s, "         %s\n", dimStyle().Render(original))
+	}
+	if notes := station.UserNotes(); notes != "" {
+		fmt.Fprintf(&s, "Notes:   %s\n", notes)
+	}
 
 	if station.Tags != "" {
 		fmt.Fprintf(&s, "Tags:    %s\n", station.Tags)
@@ -48,6 +48,7 @@ type playlistEntry struct {
 type TagPlaylistsModel struct {
 	state            tagPlaylistsState
 	tagsManager      *storage.TagsManager
+	likedSongs       *storage.LikedSongsManager // bookmarks for the "l" key
 	ratingsManager   *storage.RatingsManager
 	metadataManager  *storage.MetadataManager
 	blocklistManager *blocklist.Manager
@@ -641,6 +642,11 @@ func (m TagPlaylistsModel) updatePlaying(msg tea.KeyMsg) (TagPlaylistsModel, tea
 				m.saveMessageTime = messageDisplayShort
 			}
 		}
+	case "l":
+		if msg := likeCurrentSong(m.likedSongs, m.player, m.selectedStation); msg != "" {
+			m.saveMessage = msg
+			m.saveMessageTime = messageDisplayShort
+		}
 	case "r":
 		if m.selectedStation != nil && m.ratingsManager != nil {
 			m.ratingMode = true
@@ -1129,8 +1135,10 @@ func (m TagPlaylistsModel) viewPlaying() string {
 
 	sb.WriteString("\n")
 	if m.player != nil && m.player.IsPlaying() {
-		if track := m.player.GetCachedTrack(); IsValidTrackMetadata(track, m.selectedStation.TrimName()) {
-			sb.WriteString(successStyle().Render("▶ Now Playing:") + " " + infoStyle().Render(track))
+		if status := networkStatusText(m.player); status != "" {
+			sb.WriteString(highlightStyle().Render(status))
+		} else if track := m.player.GetCachedTrack(); IsValidTrackMetadata(track, m.selectedStation.TrimName()) {
+			sb.WriteString(successStyle().Render("▶ Now Playing:") + " " + infoStyle().Render(formatTrack(m.player, track)))
 		} else {
 			sb.WriteString(successStyle().Render("▶ Playing..."))
 		}
@@ -1169,3 +1177,15 @@ func (m TagPlaylistsModel) renderPageWithBottomHelp(layout PageLayout, height in
 	}
 	return RenderPageWithBottomHelp(layout, height)
 }
+
+// capturesKeys is true in the create/edit dialog and the tag dialogs.
+func (m TagPlaylistsModel) capturesKeys() bool {
+	if m.helpModel.IsVisible() {
+		return true
+	}
+	switch m.state {
+	case tagPlaylistsStateCreate, tagPlaylistsStateTagInput, tagPlaylistsStateManageTags:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,340 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+// sessionRetentionOptions are the retention periods the timeline cycles
+// through, in days; 0 keeps sessions forever.
+var sessionRetentionOptions = []int{7, 30, 90, 180, 365, 0}
+
+// sessionLogConfigMsg is sent by the timeline when the session log settings
+// change.
+type sessionLogConfigMsg struct {
+	cfg config.SessionLogConfig
+}
+
+// playSourceLabels names the play sources recorded in the session log.
+var playSourceLabels = map[string]string{
+	"main_menu":     "Main Menu",
+	"favorites":     "Favorites",
+	"search":        "Search",
+	"lucky":         "I Feel Lucky",
+	"most_played":   "Most Played",
+	"top_rated":     "Top Rated",
+	"browse_tags":   "Browse Tags",
+	"tag_playlists": "Tag Playlists",
+	"cli":           "Command line",
+}
+
+// stopReasonLabels describes why a session ended.
+var stopReasonLabels = map[string]string{
+	storage.StopReasonStopped:  "stopped",
+	storage.StopReasonSwitched: "switched station",
+	storage.StopReasonEnded:    "stream ended",
+	storage.StopReasonNetwork:  "network lost",
+	storage.StopReasonDataCap:  "data cap reached",
+	storage.StopReasonExit:     "quit",
+}
+
+// labelOr returns labels[key], or key itself when it has no label.
+func labelOr(labels map[string]string, key string) string {
+	if label, ok := labels[key]; ok {
+		return label
+	}
+	return key
+}
+
+// formatRetention describes a retention period in days.
+func formatRetention(days int) string {
+	if days <= 0 {
+		return "forever"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+// TimelineModel browses the listening session log one day at a time.
+type TimelineModel struct {
+	dataPath      string
+	log           *storage.SessionLog
+	cfg           config.SessionLogConfig
+	days          []time.Time // days with sessions, oldest first
+	day           time.Time   // day shown, at local midnight
+	sessions      []storage.ListeningSession
+	listModel     list.Model
+	message       string
+	messageTime   int
+	width         int
+	height        int
+	nowPlayingBar string // set by App when ContinueOnNavigate is active
+}
+
+// timelineItem wraps a ListeningSession for the list
+type timelineItem struct {
+	session storage.ListeningSession
+}
+
+func (i timelineItem) Title() string {
+	s := i.session
+	name := s.StationName
+	if name == "" {
+		name = s.StationUUID
+	}
+	title := fmt.Sprintf("%s–%s  %s  (%s)", s.Start.Local().Format("15:04"), s.End.Local().Format("15:04"),
+		name, storage.FormatDuration(int64(s.Duration().Seconds())))
+	if s.Source != "" {
+		title += " · " + labelOr(playSourceLabels, s.Source)
+	}
+	return title + " · " + labelOr(stopReasonLabels, s.StopReason)
+}
+func (i timelineItem) Description() string { return "" }
+func (i timelineItem) FilterValue() string { return i.session.StationName }
+
+// NewTimelineModel creates the timeline screen for the session log in
+// dataPath, showing the most recent day with sessions.
+func NewTimelineModel(dataPath string) TimelineModel {
+	l := list.New([]list.Item{}, createStyledDelegate(), 80, 20)
+	l.Title = "Sessions"
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	l.SetShowPagination(true)
+	l.Styles.Title = listTitleStyle()
+	l.Styles.PaginationStyle = paginationStyle()
+
+	cfg, _ := storage.LoadSessionLogConfigFromUnified()
+	m := TimelineModel{
+		dataPath: dataPath,
+		// Sessions are readable even while logging is off
+		log:       storage.NewSessionLog(dataPath, cfg.RetentionDays),
+		cfg:       cfg,
+		listModel: l,
+	}
+	m.loadDays()
+	if n := len(m.days); n > 0 {
+		m.day = m.days[n-1]
+	} else {
+		m.day = startOfDay(time.Now())
+	}
+	m.loadDay()
+	return m
+}
+
+// startOfDay returns local midnight on t's date.
+func startOfDay(t time.Time) time.Time {
+	y, mo, d := t.Local().Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, time.Local)
+}
+
+// Init initializes the timeline screen
+func (m TimelineModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *TimelineModel) loadDays() {
+	days, err := m.log.Days()
+	if err != nil {
+		m.message = fmt.Sprintf("✗ %v", err)
+		m.messageTime = 180
+	}
+	m.days = days
+}
+
+// loadDay reads the sessions of m.day into the list.
+func (m *TimelineModel) loadDay() {
+	sessions, err := m.log.Day(m.day)
+	if err != nil {
+		m.message = fmt.Sprintf("✗ %v", err)
+		m.messageTime = 180
+	}
+	m.sessions = sessions
+	items := make([]list.Item, len(sessions))
+	for i, s := range sessions {
+		items[i] = timelineItem{session: s}
+	}
+	m.listModel.SetItems(items)
+	m.listModel.Select(0)
+}
+
+// prevDay returns the latest day with sessions before m.day.
+func (m TimelineModel) prevDay() (time.Time, bool) {
+	for i := len(m.days) - 1; i >= 0; i-- {
+		if m.days[i].Before(m.day) {
+			return m.days[i], true
+		}
+	}
+	return time.Time{}, false
+}
+
+// nextDay returns the earliest day with sessions after m.day.
+func (m TimelineModel) nextDay() (time.Time, bool) {
+	for _, d := range m.days {
+		if d.After(m.day) {
+			return d, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Update handles messages for the timeline screen
+func (m TimelineModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Decrement message timer
+	if m.messageTime > 0 {
+		m.messageTime--
+		if m.messageTime == 0 {
+			m.message = ""
+		}
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m.handleInput(msg)
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		// Same page overhead as the liked songs screen
+		listHeight := msg.Height - 14
+		if listHeight < 5 {
+			listHeight = 5
+		}
+		m.listModel.SetSize(msg.Width-4, listHeight)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.listModel, cmd = m.listModel.Update(msg)
+	return m, cmd
+}
+
+func (m TimelineModel) handleInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return m, func() tea.Msg { return navigateMsg{screen: screenMostPlayed} }
+	case "0":
+		return m, func() tea.Msg { return navigateMsg{screen: screenMainMenu} }
+	case "q":
+		return m, tea.Quit
+	case "left", "h", "[":
+		if day, ok := m.prevDay(); ok {
+			m.day = day
+			m.loadDay()
+		} else {
+			m.message = "No earlier sessions"
+			m.messageTime = 150
+		}
+		return m, nil
+	case "right", "l", "]":
+		if day, ok := m.nextDay(); ok {
+			m.day = day
+			m.loadDay()
+		} else {
+			m.message = "No later sessions"
+			m.messageTime = 150
+		}
+		return m, nil
+	case "t":
+		m.day = startOfDay(time.Now())
+		m.loadDay()
+		return m, nil
+	case "r":
+		m.cfg.RetentionDays = nextOption(sessionRetentionOptions, m.cfg.RetentionDays)
+		cmd := m.saveConfig()
+		if cmd != nil {
+			m.message = "✓ Keeping sessions " + formatRetention(m.cfg.RetentionDays)
+			m.messageTime = 150
+		}
+		return m, cmd
+	case "o":
+		m.cfg.Enabled = !m.cfg.Enabled
+		cmd := m.saveConfig()
+		if cmd != nil {
+			if m.cfg.Enabled {
+				m.message = "✓ Session logging on"
+			} else {
+				m.message = "✓ Session logging off"
+			}
+			m.messageTime = 150
+		}
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.listModel, cmd = m.listModel.Update(msg)
+	return m, cmd
+}
+
+// saveConfig saves the session log settings and applies the new retention
+// to the sessions shown. It returns nil when saving failed.
+func (m *TimelineModel) saveConfig() tea.Cmd {
+	if err := storage.SaveSessionLogConfigToUnified(m.cfg); err != nil {
+		m.message = fmt.Sprintf("✗ Failed to save: %v", err)
+		m.messageTime = 180
+		return nil
+	}
+	m.log = storage.NewSessionLog(m.dataPath, m.cfg.RetentionDays)
+	m.loadDays()
+	m.loadDay()
+	cfg := m.cfg
+	return func() tea.Msg { return sessionLogConfigMsg{cfg: cfg} }
+}
+
+// View renders the timeline screen
+func (m TimelineModel) View() string {
+	var content strings.Builder
+
+	var total time.Duration
+	for _, s := range m.sessions {
+		total += s.Duration()
+	}
+	subtitle := fmt.Sprintf("%s · %d session(s) · %s listened",
+		m.day.Format("Monday, January 2 2006"), len(m.sessions), storage.FormatDuration(int64(total.Seconds())))
+
+	logging := "on"
+	if !m.cfg.Enabled {
+		logging = "off"
+	}
+	content.WriteString(dimStyle().Render(fmt.Sprintf("Logging %s · keeping sessions %s", logging, formatRetention(m.cfg.RetentionDays))))
+	content.WriteString("\n\n")
+
+	if m.message != "" {
+		style := successStyle()
+		if strings.Contains(m.message, "✗") {
+			style = errorStyle()
+		} else if !strings.Contains(m.message, "✓") {
+			style = infoStyle()
+		}
+		content.WriteString(style.Render(m.message))
+		content.WriteString("\n\n")
+	}
+
+	switch {
+	case len(m.days) == 0:
+		content.WriteString(infoStyle().Render("No listening sessions yet.\n\nEvery station you play is logged here when it stops."))
+	case len(m.sessions) == 0:
+		content.WriteString(infoStyle().Render("No sessions on this day."))
+	default:
+		content.WriteString(m.listModel.View())
+	}
+
+	return m.renderPageWithBottomHelp(PageLayout{
+		Title:    "🕘 Listening Timeline",
+		Subtitle: subtitle,
+		Content:  content.String(),
+		Help:     "↑↓/jk: Navigate • ←/→: Previous/Next day • t: Today • r: Retention • o: Logging on/off • Esc: Back • 0: Main Menu",
+	}, m.height)
+}
+
+// renderPageWithBottomHelp wraps RenderPageWithBottomHelp injecting the active now-playing bar.
+func (m TimelineModel) renderPageWithBottomHelp(layout PageLayout, height int) string {
+	layout.NowPlaying = m.nowPlayingBar
+	return RenderPageWithBottomHelp(layout, height)
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/storage"
+)
+
+func sendTimelineKeys(m TimelineModel, keys ...tea.KeyMsg) TimelineModel {
+	for _, k := range keys {
+		updated, _ := m.Update(k)
+		m = updated.(TimelineModel)
+	}
+	return m
+}
+
+func TestTimelineBrowsesDays(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+
+	dataPath := t.TempDir()
+	log := storage.NewSessionLog(dataPath, 0)
+	today := startOfDay(time.Now())
+	earlier := today.AddDate(0, 0, -3)
+	for _, day := range []time.Time{earlier, today} {
+		start := time.Date(day.Year(), day.Month(), day.Day(), 9, 0, 0, 0, time.Local)
+		err := log.Append(storage.ListeningSession{
+			StationUUID: "uuid-" + day.Format("02"),
+			StationName: "Jazz FM",
+			Start:       start,
+			End:         start.Add(90 * time.Minute),
+			Source:      "favorites",
+			StopReason:  storage.StopReasonStopped,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := NewTimelineModel(dataPath)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(TimelineModel)
+	if !m.day.Equal(today) || len(m.sessions) != 1 {
+		t.Fatalf("expected today's session first, got day %v with %d session(s)", m.day, len(m.sessions))
+	}
+	view := m.View()
+	for _, want := range []string{"1h 30m", "Favorites", "stopped"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the view", want)
+		}
+	}
+
+	// Days without sessions are skipped
+	m = sendTimelineKeys(m, tea.KeyMsg{Type: tea.KeyLeft})
+	if !m.day.Equal(earlier) || len(m.sessions) != 1 {
+		t.Errorf("left should go to the earlier day, got %v", m.day)
+	}
+	m = sendTimelineKeys(m, tea.KeyMsg{Type: tea.KeyLeft})
+	if !m.day.Equal(earlier) || m.message == "" {
+		t.Errorf("left on the first day should stay and say so, got %v %q", m.day, m.message)
+	}
+	m = sendTimelineKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if !m.day.Equal(today) {
+		t.Errorf("t should jump to today, got %v", m.day)
+	}
+}
+
+func TestTimelineRetentionSetting(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+
+	m := NewTimelineModel(t.TempDir())
+	if m.cfg.RetentionDays != 90 {
+		t.Fatalf("expected the default retention, got %d", m.cfg.RetentionDays)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = updated.(TimelineModel)
+	if m.cfg.RetentionDays != 180 || cmd == nil {
+		t.Fatalf("expected retention to move to 180 days, got %d", m.cfg.RetentionDays)
+	}
+	if msg, ok := cmd().(sessionLogConfigMsg); !ok || msg.cfg.RetentionDays != 180 {
+		t.Errorf("expected a sessionLogConfigMsg, got %#v", msg)
+	}
+	saved, err := storage.LoadSessionLogConfigFromUnified()
+	if err != nil || saved.RetentionDays != 180 {
+		t.Errorf("expected the retention to be saved, got %d (%v)", saved.RetentionDays, err)
+	}
+}
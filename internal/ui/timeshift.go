@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/config"
+)
+
+// timeShiftPollInterval is how often the play screen refreshes the
+// "behind live" offset.
+const timeShiftPollInterval = time.Second
+
+// timeShiftConfigMsg is sent by Connection Settings when the time-shift
+// depth changes.
+type timeShiftConfigMsg struct {
+	cfg config.TimeShiftConfig
+}
+
+// timeShiftStatusMsg carries the latest time-shift position. active is false
+// once the player no longer has a time-shift buffer, which ends polling.
+type timeShiftStatusMsg struct {
+	active bool
+	behind time.Duration
+}
+
+// pollTimeShift samples the time-shift position after one interval.
+func (m PlayModel) pollTimeShift() tea.Cmd {
+	p := m.player
+	return tea.Tick(timeShiftPollInterval, func(time.Time) tea.Msg {
+		if p == nil || !p.TimeShiftEnabled() {
+			return timeShiftStatusMsg{}
+		}
+		status, err := p.TimeShiftStatus()
+		if err != nil {
+			// Nothing buffered yet; keep polling.
+			return timeShiftStatusMsg{active: true}
+		}
+		return timeShiftStatusMsg{active: true, behind: status.Behind}
+	})
+}
+
+// startTimeShiftPolling begins polling unless a poll loop is already running.
+func (m PlayModel) startTimeShiftPolling() (PlayModel, tea.Cmd) {
+	if m.timeShiftPolling || m.player == nil || !m.player.TimeShiftEnabled() {
+		return m, nil
+	}
+	m.timeShiftPolling = true
+	return m, m.pollTimeShift()
+}
+
+// handleTimeShiftStatus records the offset and schedules the next poll.
+func (m PlayModel) handleTimeShiftStatus(msg timeShiftStatusMsg) (PlayModel, tea.Cmd) {
+	m.behindLive = msg.behind
+	if !msg.active || m.state == playStateListSelection || m.state == playStateStationSelection {
+		m.timeShiftPolling = false
+		m.behindLive = 0
+		return m, nil
+	}
+	return m, m.pollTimeShift()
+}
+
+// timeShiftKey handles the seek and go-live keys. ok is false for any other
+// key.
+func (m PlayModel) timeShiftKey(key string) (_ PlayModel, _ tea.Cmd, ok bool) {
+	var delta time.Duration
+	switch key {
+	case "left":
+		delta = -10 * time.Second
+	case "right":
+		delta = 10 * time.Second
+	case "shift+left", "[":
+		delta = -time.Minute
+	case "shift+right", "]":
+		delta = time.Minute
+	case "L":
+	default:
+		return m, nil, false
+	}
+
+	var err error
+	switch {
+	case m.player == nil || !m.player.TimeShiftEnabled():
+		m.saveMessage = "Time-shift is off — enable it in Settings > Connection"
+	case key == "L":
+		if err = m.player.JumpToLive(); err == nil {
+			m.behindLive = 0
+			m.saveMessage = "✓ Back to live"
+		}
+	default:
+		if err = m.player.Seek(delta); err == nil {
+			m.saveMessage = fmt.Sprintf("✓ Seek %+ds", int(delta.Seconds()))
+		}
+	}
+	if err != nil {
+		m.saveMessage = fmt.Sprintf("✗ Seek failed: %v", err)
+	}
+
+	startTick := m.saveMessageTime <= 0 && !m.sleepTimerActive
+	m.saveMessageTime = messageDisplayShort
+	if startTick {
+		return m, tickEverySecond(), true
+	}
+	return m, nil, true
+}
+
+// formatBehindLive renders an offset as m:ss, or h:mm:ss past an hour.
+func formatBehindLive(d time.Duration) string {
+	s := int(d.Round(time.Second).Seconds())
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
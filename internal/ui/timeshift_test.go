@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shinokada/tera/v3/internal/api"
+	"github.com/shinokada/tera/v3/internal/blocklist"
+)
+
+func TestFormatBehindLive(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                                    "0:00",
+		9*time.Second + 600*time.Millisecond: "0:10",
+		75 * time.Second:                     "1:15",
+		time.Hour + 2*time.Minute + 3*time.Second: "1:02:03",
+	}
+	for d, want := range tests {
+		if got := formatBehindLive(d); got != want {
+			t.Errorf("formatBehindLive(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestPlayModel_SeekKeysWhenTimeShiftOff(t *testing.T) {
+	dir := t.TempDir()
+	m := NewPlayModel(dir, blocklist.NewManager(dir+"/blocklist.json"))
+	m.width, m.height = 100, 40
+	m.state = playStatePlaying
+	m.selectedStation = &api.Station{StationUUID: "uuid-1", Name: "Live FM"}
+
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyLeft},
+		{Type: tea.KeyRunes, Runes: []rune("]")},
+		{Type: tea.KeyRunes, Runes: []rune("L")},
+	} {
+		updated, _ := m.Update(key)
+		got := updated.(PlayModel)
+		if !strings.Contains(got.saveMessage, "Time-shift is off") {
+			t.Errorf("%s: expected hint about enabling time-shift, got %q", key, got.saveMessage)
+		}
+		if got.state != playStatePlaying {
+			t.Errorf("%s: state changed to %v", key, got.state)
+		}
+	}
+}
+
+func TestPlayModel_BehindLiveIndicator(t *testing.T) {
+	dir := t.TempDir()
+	m := NewPlayModel(dir, blocklist.NewManager(dir+"/blocklist.json"))
+	m.width, m.height = 100, 40
+	m.state = playStatePlaying
+	m.selectedStation = &api.Station{StationUUID: "uuid-1", Name: "Live FM"}
+
+	updated, cmd := m.handleTimeShiftStatus(timeShiftStatusMsg{active: true, behind: 42 * time.Second})
+	if cmd == nil {
+		t.Error("expected the next poll to be scheduled")
+	}
+	if !strings.Contains(updated.viewPlaying(), "0:42 behind live") {
+		t.Error("expected the behind-live offset in the view")
+	}
+
+	updated, cmd = updated.handleTimeShiftStatus(timeShiftStatusMsg{})
+	if cmd != nil || updated.timeShiftPolling || updated.behindLive != 0 {
+		t.Error("expected polling to stop once time-shift is inactive")
+	}
+	if strings.Contains(updated.viewPlaying(), "behind live") {
+		t.Error("indicator should disappear at the live edge")
+	}
+}
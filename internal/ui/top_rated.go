@@ -109,8 +109,9 @@ type TopRatedModel struct {
 	ratingsManager     *storage.RatingsManager
 	metadataManager    *storage.MetadataManager
 	starRenderer       *components.StarRenderer
-	tagsManager        *storage.TagsManager    // for tag pill display
-	tagRenderer        *components.TagRenderer // for rendering tag pills
+	tagsManager        *storage.TagsManager       // for tag pill display
+	likedSongs         *storage.LikedSongsManager // bookmarks for the "l" key
+	tagRenderer        *components.TagRenderer    // for rendering tag pills
 	favoritePath       string
 	saveMessage        string
 	saveMessageSuccess bool
@@ -141,7 +142,7 @@ type topRatedStationItem struct {
 
 func (i topRatedStationItem) FilterValue() string {
 	if i.station.Name != "" {
-		return i.station.Name
+		return i.station.SearchText()
 	}
 	return i.station.StationUUID
 }
@@ -645,6 +646,15 @@ func (m TopRatedModel) handlePlayingInput(msg tea.KeyMsg) (TopRatedModel, tea.Cm
 		m.selectedStation = nil
 		return m, cmd
 
+	case "l":
+		if msg := likeCurrentSong(m.likedSongs, m.player, m.selectedStation); msg != "" {
+			m.saveMessage = msg
+			m.saveMessageSuccess = strings.HasPrefix(msg, "✓")
+			m.saveMessageTime = 3
+			return m, tickEverySecond()
+		}
+		return m, nil
+
 	case "*":
 		// Enter rating mode while playing
 		m.state = topRatedStateRating
@@ -850,10 +860,12 @@ func (m TopRatedModel) View() string {
 					content.WriteString(infoStyle().Render("⏸ Paused"))
 				} else {
 					track := m.player.GetCachedTrack()
-					if IsValidTrackMetadata(track, m.selectedStation.Name) {
+					if status := networkStatusText(m.player); status != "" {
+						content.WriteString(highlightStyle().Render(status))
+					} else if IsValidTrackMetadata(track, m.selectedStation.Name) {
 						content.WriteString(successStyle().Render("▶ Now Playing:"))
 						content.WriteString(" ")
-						content.WriteString(infoStyle().Render(track))
+						content.WriteString(infoStyle().Render(formatTrack(m.player, track)))
 					} else {
 						content.WriteString(successStyle().Render("▶ Playing..."))
 					}
@@ -923,3 +935,11 @@ func (m TopRatedModel) renderPageWithBottomHelp(layout PageLayout, height int) s
 }
 
 // Phase 5: Confirm stop prompt view
+
+// capturesKeys is true while filtering stations.
+func (m TopRatedModel) capturesKeys() bool {
+	if m.helpModel.IsVisible() {
+		return true
+	}
+	return m.stationListModel.SettingFilter()
+}
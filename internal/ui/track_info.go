@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/player"
+)
+
+// metadataPatternsFromConfig converts the custom title patterns into player
+// patterns. Patterns have already been checked by config validation; any
+// that still fail to compile are skipped.
+func metadataPatternsFromConfig(cfg config.MetadataConfig) []player.MetadataPattern {
+	var patterns []player.MetadataPattern
+	for _, p := range cfg.Patterns {
+		re, err := p.Compile()
+		if err != nil {
+			continue
+		}
+		patterns = append(patterns, player.MetadataPattern{Regexp: re, StationUUID: p.Station})
+	}
+	return patterns
+}
+
+// formatTrack renders a raw stream title heard on p's station as
+// "Artist - Title (Album)" using that station's patterns.
+func formatTrack(p *player.MPVPlayer, raw string) string {
+	var uuid string
+	if p != nil {
+		if st := p.GetCurrentStation(); st != nil {
+			uuid = st.StationUUID
+		}
+	}
+	return player.ParseTrack(raw, uuid).String()
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/player"
+)
+
+func TestFormatTrack_UsesConfiguredPatterns(t *testing.T) {
+	if got := formatTrack(nil, "NOW PLAYING: Radiohead - Creep"); got != "Radiohead - Creep" {
+		t.Errorf("built-in parse: got %q", got)
+	}
+	if got := formatTrack(nil, "Stand by Me"); got != "Stand by Me" {
+		t.Errorf("bare title: got %q", got)
+	}
+
+	cfg := config.MetadataConfig{Patterns: []config.MetadataPattern{
+		{Pattern: `^(?P<title>.+?) ~ (?P<artist>.+)$`},
+		{Pattern: `([`},
+	}}
+	patterns := metadataPatternsFromConfig(cfg)
+	if len(patterns) != 1 {
+		t.Fatalf("expected the invalid pattern to be skipped, got %d", len(patterns))
+	}
+	player.SetMetadataPatterns(patterns)
+	defer player.SetMetadataPatterns(nil)
+
+	if got := formatTrack(nil, "Creep ~ Radiohead"); got != "Radiohead - Creep" {
+		t.Errorf("custom parse: got %q", got)
+	}
+}
@@ -0,0 +1,262 @@
+package ui
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/player"
+)
+
+// The visualizer hides itself below this terminal size so it never pushes
+// the station details or the help bar off screen.
+const (
+	visualizerMinWidth  = 60
+	visualizerMinHeight = 24
+)
+
+// visualizerFloorDB is the level drawn as an empty meter. Quieter passages
+// in broadcast audio rarely drop below it, so the meter uses its full range.
+const visualizerFloorDB = -60.0
+
+// visualizerIdleInterval is how often the sampling loop checks for a
+// playing station while nothing is being measured.
+const visualizerIdleInterval = time.Second
+
+// spectrumRows is the height of the spectrum display in the play view.
+const spectrumRows = 4
+
+var barGlyphs = []rune(" ▁▂▃▄▅▆▇█")
+
+// visualizerTickMsg asks the App to sample audio levels.
+type visualizerTickMsg struct{}
+
+// audioLevelsMsg carries the latest measurement; levels is nil when nothing
+// is being measured (stopped, paused, terminal too small).
+type audioLevelsMsg struct {
+	levels *player.AudioLevels
+}
+
+// visualizerConfigMsg is sent by Settings when the visualizer config changes.
+type visualizerConfigMsg struct {
+	cfg config.VisualizerConfig
+}
+
+func visualizerConfigChanged(cfg config.VisualizerConfig) tea.Cmd {
+	return func() tea.Msg { return visualizerConfigMsg{cfg: cfg} }
+}
+
+// visualizerLabel is the Settings label for the current visualizer mode.
+func visualizerLabel(cfg config.VisualizerConfig) string {
+	switch {
+	case !cfg.Enabled:
+		return "Off"
+	case cfg.Style == config.VisualizerStyleSpectrum:
+		return "Spectrum"
+	default:
+		return "VU"
+	}
+}
+
+// visualizerFits reports whether a width×height terminal has room for the
+// visualizer.
+func visualizerFits(width, height int) bool {
+	return width >= visualizerMinWidth && height >= visualizerMinHeight
+}
+
+// levelFraction maps a dBFS level onto [0, 1] for drawing.
+func levelFraction(db float64) float64 {
+	if math.IsNaN(db) || db <= visualizerFloorDB {
+		return 0
+	}
+	if db >= 0 {
+		return 1
+	}
+	return 1 - db/visualizerFloorDB
+}
+
+// renderVUMeter draws one meter row per channel, width cells wide, with the
+// peak level marked by a bar.
+func renderVUMeter(lv player.AudioLevels, width int) string {
+	row := func(label string, rms, peak float64) string {
+		cells := max(width-2, 1)
+		filled := int(math.Round(levelFraction(rms) * float64(cells)))
+		peakAt := int(math.Round(levelFraction(peak)*float64(cells))) - 1
+		var b strings.Builder
+		for i := 0; i < cells; i++ {
+			switch {
+			case i < filled:
+				b.WriteRune('█')
+			case i == peakAt:
+				b.WriteRune('▏')
+			default:
+				b.WriteRune('·')
+			}
+		}
+		return label + " " + meterStyle(levelFraction(peak)).Render(b.String())
+	}
+	return row("L", lv.Left, lv.PeakLeft) + "\n" + row("R", lv.Right, lv.PeakRight)
+}
+
+// renderSpectrum draws bands as vertical bars rows lines tall, each bar two
+// cells wide with a gap.
+func renderSpectrum(bands []float64, rows int) string {
+	if len(bands) == 0 || rows < 1 {
+		return ""
+	}
+	steps := len(barGlyphs) - 1
+	lines := make([]string, rows)
+	for r := 0; r < rows; r++ {
+		// r counts from the top; level is the number of eighth-cells
+		// below this row.
+		below := (rows - 1 - r) * steps
+		var b strings.Builder
+		for _, db := range bands {
+			h := int(math.Round(levelFraction(db) * float64(rows*steps)))
+			g := barGlyphs[min(max(h-below, 0), steps)]
+			b.WriteRune(g)
+			b.WriteRune(g)
+			b.WriteRune(' ')
+		}
+		lines[r] = strings.TrimRight(b.String(), " ")
+	}
+	return meterStyle(0).Render(strings.Join(lines, "\n"))
+}
+
+// renderCompactMeter returns a short single-line meter for the now-playing
+// bar: the spectrum as one row of glyphs, or a mono VU bar.
+func renderCompactMeter(lv player.AudioLevels) string {
+	if len(lv.Bands) > 0 {
+		var b strings.Builder
+		steps := len(barGlyphs) - 1
+		for _, db := range lv.Bands {
+			b.WriteRune(barGlyphs[int(math.Round(levelFraction(db)*float64(steps)))])
+		}
+		return b.String()
+	}
+	const cells = 8
+	level := levelFraction(math.Max(lv.Left, lv.Right))
+	filled := int(math.Round(level * cells))
+	return strings.Repeat("▮", filled) + strings.Repeat("▯", cells-filled)
+}
+
+// meterStyle colours the meter by how hot the signal is.
+func meterStyle(peak float64) lipgloss.Style {
+	switch {
+	case peak >= 0.95:
+		return errorStyle()
+	case peak >= 0.8:
+		return highlightStyle()
+	default:
+		return successStyle()
+	}
+}
+
+// applyVisualizerConfig makes cfg the active visualizer setting. Players
+// started afterwards pick up the analysis filter; the returned command starts
+// the sampling loop if it is not already running.
+func (a *App) applyVisualizerConfig(cfg config.VisualizerConfig) tea.Cmd {
+	a.visualizerCfg = cfg
+	a.playScreen.visualizerStyle = cfg.Style
+
+	switch {
+	case !cfg.Active():
+		player.SetLevelAnalysis(-1)
+	case cfg.Style == config.VisualizerStyleSpectrum:
+		player.SetLevelAnalysis(cfg.Bands)
+	default:
+		player.SetLevelAnalysis(0)
+	}
+
+	if !cfg.Active() {
+		a.setAudioLevels(nil)
+		return nil
+	}
+	if a.visualizerRunning {
+		return nil
+	}
+	a.visualizerRunning = true
+	return visualizerTick(visualizerIdleInterval)
+}
+
+func visualizerTick(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg { return visualizerTickMsg{} })
+}
+
+// visualizerSource returns the player whose levels should be shown, or nil.
+func (a *App) visualizerSource() *player.MPVPlayer {
+	if !visualizerFits(a.width, a.height) {
+		return nil
+	}
+	if p := a.activePlayer; p != nil && p.IsPlaying() && !p.IsPaused() {
+		return p
+	}
+	if a.screen == screenPlay && a.playScreen.state == playStatePlaying {
+		if p := a.playScreen.player; p != nil && p.IsPlaying() && !p.IsPaused() {
+			return p
+		}
+	}
+	return nil
+}
+
+// sampleAudioLevels reads levels from the current source off the UI
+// goroutine. Only one sample is ever in flight: the next tick is scheduled
+// when its result arrives.
+func (a *App) sampleAudioLevels() tea.Cmd {
+	if !a.visualizerCfg.Active() {
+		a.visualizerRunning = false
+		a.setAudioLevels(nil)
+		return nil
+	}
+	p := a.visualizerSource()
+	if p == nil {
+		a.setAudioLevels(nil)
+		return visualizerTick(visualizerIdleInterval)
+	}
+	return func() tea.Msg {
+		lv, err := p.GetAudioLevels()
+		if err != nil {
+			return audioLevelsMsg{}
+		}
+		return audioLevelsMsg{levels: &lv}
+	}
+}
+
+// handleAudioLevels stores a measurement and schedules the next sample at
+// the configured frame rate.
+func (a *App) handleAudioLevels(msg audioLevelsMsg) tea.Cmd {
+	if !a.visualizerCfg.Active() {
+		a.visualizerRunning = false
+		a.setAudioLevels(nil)
+		return nil
+	}
+	a.setAudioLevels(msg.levels)
+	return visualizerTick(time.Second / time.Duration(max(a.visualizerCfg.FPS, 1)))
+}
+
+func (a *App) setAudioLevels(lv *player.AudioLevels) {
+	if a.audioLevels == nil && lv == nil {
+		return
+	}
+	a.audioLevels = lv
+	a.playScreen.audioLevels = lv
+	if a.activeStation != nil {
+		a.broadcastNowPlayingBar()
+	}
+}
+
+// renderVisualizer returns the play-view visualizer, or "" when there is
+// nothing to show or no room for it.
+func (m PlayModel) renderVisualizer() string {
+	if m.audioLevels == nil || !visualizerFits(m.width, m.height) {
+		return ""
+	}
+	if m.visualizerStyle == config.VisualizerStyleSpectrum && len(m.audioLevels.Bands) > 0 {
+		return renderSpectrum(m.audioLevels.Bands, spectrumRows)
+	}
+	width := min(m.width-8, 50)
+	return renderVUMeter(*m.audioLevels, width)
+}
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/shinokada/tera/v3/internal/config"
+	"github.com/shinokada/tera/v3/internal/player"
+)
+
+func TestLevelFraction(t *testing.T) {
+	cases := []struct {
+		db   float64
+		want float64
+	}{
+		{math.Inf(-1), 0},
+		{-90, 0},
+		{-30, 0.5},
+		{0, 1},
+		{3, 1},
+	}
+	for _, tc := range cases {
+		if got := levelFraction(tc.db); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("levelFraction(%v) = %v, want %v", tc.db, got, tc.want)
+		}
+	}
+}
+
+func TestRenderSpectrum_Height(t *testing.T) {
+	out := renderSpectrum([]float64{0, -30, math.Inf(-1)}, 4)
+	if lines := strings.Split(out, "\n"); len(lines) != 4 {
+		t.Fatalf("expected 4 rows, got %d", len(lines))
+	}
+	if renderSpectrum(nil, 4) != "" {
+		t.Error("expected empty output without bands")
+	}
+}
+
+func TestRenderVUMeter_Width(t *testing.T) {
+	out := renderVUMeter(player.AudioLevels{Left: -6, Right: -12, PeakLeft: -1, PeakRight: -3}, 30)
+	lines := strings.Split(out, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(lines))
+	}
+	for _, l := range lines {
+		if w := lipgloss.Width(l); w != 30 {
+			t.Errorf("expected width 30, got %d: %q", w, l)
+		}
+	}
+}
+
+func TestRenderCompactMeter(t *testing.T) {
+	if got := renderCompactMeter(player.AudioLevels{Left: 0, Right: -60}); got != "▮▮▮▮▮▮▮▮" {
+		t.Errorf("expected a full VU bar, got %q", got)
+	}
+	if got := renderCompactMeter(player.AudioLevels{Bands: []float64{0, math.Inf(-1)}}); got != "█ " {
+		t.Errorf("expected one glyph per band, got %q", got)
+	}
+}
+
+func TestApplyVisualizerConfig_LowCPU(t *testing.T) {
+	t.Cleanup(func() { player.SetLevelAnalysis(-1) })
+	app := newTestApp()
+
+	cfg := config.DefaultVisualizerConfig()
+	cfg.Enabled = true
+	if cmd := app.applyVisualizerConfig(cfg); cmd == nil || !app.visualizerRunning {
+		t.Fatal("expected the sampling loop to start")
+	}
+	// A second enable must not start a duplicate loop.
+	if cmd := app.applyVisualizerConfig(cfg); cmd != nil {
+		t.Error("expected no second loop")
+	}
+
+	cfg.LowCPU = true
+	app.audioLevels = &player.AudioLevels{}
+	if cmd := app.applyVisualizerConfig(cfg); cmd != nil {
+		t.Error("low-CPU mode should not schedule sampling")
+	}
+	if app.audioLevels != nil || app.playScreen.audioLevels != nil {
+		t.Error("expected levels to be cleared")
+	}
+	if cmd := app.sampleAudioLevels(); cmd != nil || app.visualizerRunning {
+		t.Error("the running loop should stop on its next tick")
+	}
+}
+
+func TestSampleAudioLevels_IdleWithoutPlayback(t *testing.T) {
+	app := newTestApp()
+	app.width, app.height = 120, 40
+	app.visualizerCfg = config.DefaultVisualizerConfig()
+	app.visualizerCfg.Enabled = true
+	app.visualizerRunning = true
+
+	if cmd := app.sampleAudioLevels(); cmd == nil {
+		t.Error("expected the idle loop to keep polling")
+	}
+	if app.audioLevels != nil {
+		t.Error("expected no levels while nothing plays")
+	}
+}
+
+func TestPlayModelRenderVisualizer_HiddenWhenSmall(t *testing.T) {
+	m := PlayModel{audioLevels: &player.AudioLevels{Left: -10, Right: -10}, width: 120, height: 40}
+	if m.renderVisualizer() == "" {
+		t.Fatal("expected a meter on a large terminal")
+	}
+	m.width, m.height = 50, 40
+	if m.renderVisualizer() != "" {
+		t.Error("expected the visualizer to hide on a narrow terminal")
+	}
+	m.width, m.height = 120, 15
+	if m.renderVisualizer() != "" {
+		t.Error("expected the visualizer to hide on a short terminal")
+	}
+}